@@ -21,6 +21,7 @@ import (
 	_ "embed"
 	"fmt"
 	"math/rand"
+	"strconv"
 	"strings"
 
 	"github.com/awslabs/operatorpkg/status"
@@ -52,7 +53,7 @@ type CloudProvider struct {
 
 func (c CloudProvider) Create(ctx context.Context, nodeClaim *v1.NodeClaim) (*v1.NodeClaim, error) {
 	// Create the Node because KwoK nodes don't have a kubelet, which is what Karpenter normally relies on to create the node.
-	node, err := c.toNode(nodeClaim)
+	node, offering, err := c.toNode(nodeClaim)
 	if err != nil {
 		return nil, fmt.Errorf("translating nodeclaim to node, %w", err)
 	}
@@ -60,7 +61,18 @@ func (c CloudProvider) Create(ctx context.Context, nodeClaim *v1.NodeClaim) (*v1
 		return nil, fmt.Errorf("creating node, %w", err)
 	}
 	// convert the node back into a node claim to get the chosen resolved requirement values.
-	return c.toNodeClaim(node)
+	created, err := c.toNodeClaim(node)
+	if err != nil {
+		return nil, err
+	}
+	created.Status.LaunchedInstance = &v1.LaunchedInstance{
+		InstanceType: node.Labels[corev1.LabelInstanceTypeStable],
+		Zone:         node.Labels[corev1.LabelTopologyZone],
+		CapacityType: node.Labels[v1.CapacityTypeLabelKey],
+		Price:        strconv.FormatFloat(offering.Price, 'f', -1, 64),
+		Currency:     c.Pricing().Currency,
+	}
+	return created, nil
 }
 
 func (c CloudProvider) Delete(ctx context.Context, nodeClaim *v1.NodeClaim) error {
@@ -130,6 +142,16 @@ func (c *CloudProvider) RepairPolicies() []cloudprovider.RepairPolicy {
 	return []cloudprovider.RepairPolicy{}
 }
 
+// Return no prices since the offerings of kwok's instance types are priced when they're constructed.
+func (c CloudProvider) Pricing() cloudprovider.Pricing {
+	return cloudprovider.Pricing{Currency: "USD"}
+}
+
+// Return nothing since kwok instances aren't interrupted.
+func (c CloudProvider) Interruptions() <-chan cloudprovider.Interruption {
+	return nil
+}
+
 func (c CloudProvider) getInstanceType(instanceTypeName string) (*cloudprovider.InstanceType, error) {
 	it, found := lo.Find(c.instanceTypes, func(it *cloudprovider.InstanceType) bool {
 		return it.Name == instanceTypeName
@@ -140,7 +162,7 @@ func (c CloudProvider) getInstanceType(instanceTypeName string) (*cloudprovider.
 	return it, nil
 }
 
-func (c CloudProvider) toNode(nodeClaim *v1.NodeClaim) (*corev1.Node, error) {
+func (c CloudProvider) toNode(nodeClaim *v1.NodeClaim) (*corev1.Node, *cloudprovider.Offering, error) {
 	newName := strings.Replace(namesgenerator.GetRandomName(0), "_", "-", -1)
 	//nolint
 	newName = fmt.Sprintf("%s-%d", newName, rand.Uint32())
@@ -150,7 +172,7 @@ func (c CloudProvider) toNode(nodeClaim *v1.NodeClaim) (*corev1.Node, error) {
 		return req.Key == corev1.LabelInstanceTypeStable
 	})
 	if !found {
-		return nil, fmt.Errorf("instance type requirement not found")
+		return nil, nil, fmt.Errorf("instance type requirement not found")
 	}
 
 	var instanceType *cloudprovider.InstanceType
@@ -159,7 +181,7 @@ func (c CloudProvider) toNode(nodeClaim *v1.NodeClaim) (*corev1.Node, error) {
 	for _, val := range req.Values {
 		it, err := c.getInstanceType(val)
 		if err != nil {
-			return nil, fmt.Errorf("instance type %s not found", val)
+			return nil, nil, fmt.Errorf("instance type %s not found", val)
 		}
 
 		availableOfferings := it.Offerings.Available().Compatible(requirements)
@@ -187,7 +209,7 @@ func (c CloudProvider) toNode(nodeClaim *v1.NodeClaim) (*corev1.Node, error) {
 			Allocatable: instanceType.Allocatable(),
 			Phase:       corev1.NodePending,
 		},
-	}, nil
+	}, cheapestOffering, nil
 }
 
 func addInstanceLabels(labels map[string]string, instanceType *cloudprovider.InstanceType, nodeClaim *v1.NodeClaim, offering *cloudprovider.Offering) map[string]string {
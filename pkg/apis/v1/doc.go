@@ -35,5 +35,9 @@ func init() {
 		&NodePool{},
 		&NodePoolList{},
 		&NodeClaim{},
-		&NodeClaimList{})
+		&NodeClaimList{},
+		&SchedulingSnapshot{},
+		&SchedulingSnapshotList{},
+		&NodeOverlay{},
+		&NodeOverlayList{})
 }
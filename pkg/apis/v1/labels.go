@@ -41,6 +41,8 @@ const (
 	NodeInitializedLabelKey = apis.Group + "/initialized"
 	NodeRegisteredLabelKey  = apis.Group + "/registered"
 	CapacityTypeLabelKey    = apis.Group + "/capacity-type"
+	PlacementGroupLabelKey  = apis.Group + "/placement-group"
+	ManagedByLabelKey       = apis.Group + "/managed-by"
 )
 
 // Karpenter specific annotations
@@ -50,11 +52,26 @@ const (
 	NodePoolHashAnnotationKey                  = apis.Group + "/nodepool-hash"
 	NodePoolHashVersionAnnotationKey           = apis.Group + "/nodepool-hash-version"
 	NodeClaimTerminationTimestampAnnotationKey = apis.Group + "/nodeclaim-termination-timestamp"
+	NodePoolAnnotationKey                      = apis.Group + "/nodepool"
+	RelaxedPreferencesAnnotationKey            = apis.Group + "/relaxed-preferences"
+	TerminationFinalizersAnnotationKey         = apis.Group + "/termination-finalizers"
+	AllowDeletionAnnotationKey                 = apis.Group + "/allow-deletion"
+	// AcknowledgeFleetReplacementAnnotationKey acknowledges a change to a NodePool that drifts all of its nodes at once.
+	// It's removed once the change is recorded in the NodePool's hash, so that each such change is acknowledged.
+	AcknowledgeFleetReplacementAnnotationKey = apis.Group + "/acknowledge-fleet-replacement"
+	// ForceDeleteAnnotationKey makes the deletion of a NodeClaim skip the drain of its node when set to "true", for the
+	// emergency removal of a wedged node. The node's pods are deleted without being evicted, bypassing their PDBs and
+	// do-not-disrupt annotations, and the detachment of its volumes isn't waited on.
+	ForceDeleteAnnotationKey = apis.Group + "/force-delete"
+	// PropagatedAnnotationsAnnotationKey records the keys of the NodePool and NodeClaim annotations that were last
+	// propagated onto a Node, so that the annotations that stop being propagated are removed from the Node
+	PropagatedAnnotationsAnnotationKey = apis.Group + "/propagated-annotations"
 )
 
 // Karpenter specific finalizers
 const (
-	TerminationFinalizer = apis.Group + "/termination"
+	TerminationFinalizer        = apis.Group + "/termination"
+	DeletionProtectionFinalizer = apis.Group + "/deletion-protection"
 )
 
 var (
@@ -84,6 +101,8 @@ var (
 		v1.LabelArchStable,
 		v1.LabelOSStable,
 		CapacityTypeLabelKey,
+		PlacementGroupLabelKey,
+		ManagedByLabelKey,
 		v1.LabelWindowsBuild,
 	)
 
@@ -104,6 +123,28 @@ var (
 	}
 )
 
+// ZoneIDLabelKey is the label that the cloud provider sets to the ID of a node's zone, if it identifies zones by IDs
+// as well as by names. It's empty unless a cloud provider registers it with RegisterZoneIDLabel.
+var ZoneIDLabelKey string
+
+// RegisterZoneIDLabel registers the label that a cloud provider sets to the ID of a node's zone as a well known label.
+// Requirements on zone IDs and on zone names are then normalized into each other, so that pods selecting zone IDs are
+// compatible with NodePools selecting zone names, and the other way around. Like RegisterWellKnownLabels, it should be
+// called from an init function.
+func RegisterZoneIDLabel(label string) {
+	ZoneIDLabelKey = label
+	RegisterWellKnownLabels(label)
+}
+
+// RegisterWellKnownLabels registers labels that a cloud provider sets on its nodes as well known labels, in addition to
+// the labels that Karpenter knows about. Registered labels are allowed in the node selectors of pods and the requirements
+// of NodePools, are passed through to the requirements of NodeClaims, and pods that select them are compatible with
+// NodePools that don't constrain them. Cloud providers should register their labels from an init function, before any
+// controllers start and before the node metrics are registered.
+func RegisterWellKnownLabels(labels ...string) {
+	WellKnownLabels.Insert(labels...)
+}
+
 // IsRestrictedLabel returns an error if the label is restricted.
 func IsRestrictedLabel(key string) error {
 	if WellKnownLabels.Has(key) {
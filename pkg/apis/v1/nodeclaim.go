@@ -28,12 +28,35 @@ type NodeClaimSpec struct {
 	// Taints will be applied to the NodeClaim's node.
 	// +optional
 	Taints []v1.Taint `json:"taints,omitempty"`
+	// TaintTemplates are taints whose values are Go templates that are resolved against the properties of the NodeClaim's
+	// instance when its node registers, e.g. {{ .InstanceType.Name }}, {{ .Zone }}, {{ .CapacityType }}, {{ .Architecture }}
+	// or {{ index .Labels "example.com/key" }}. Their values aren't known until then, so pods only tolerate them for
+	// provisioning through a toleration with the Exists operator.
+	// +optional
+	TaintTemplates []v1.Taint `json:"taintTemplates,omitempty"`
+	// LabelTemplates are labels whose values are Go templates that are resolved like the TaintTemplates, and applied to
+	// the NodeClaim's node when it registers. Their values aren't known until then, so pods can't select them for provisioning.
+	// +kubebuilder:validation:MaxProperties:=100
+	// +optional
+	LabelTemplates map[string]string `json:"labelTemplates,omitempty"`
 	// StartupTaints are taints that are applied to nodes upon startup which are expected to be removed automatically
 	// within a short period of time, typically by a DaemonSet that tolerates the taint. These are commonly used by
 	// daemonsets to allow initialization and enforce startup ordering.  StartupTaints are ignored for provisioning
 	// purposes in that pods are not required to tolerate a StartupTaint in order to have nodes provisioned for them.
 	// +optional
 	StartupTaints []v1.Taint `json:"startupTaints,omitempty"`
+	// StartupTaintTimeouts are how long, by taint key, Karpenter waits for a startup taint to be removed from the node
+	// after it registers. Karpenter removes startup taints that are still on the node after their timeout itself, so
+	// that a broken agent that never removes its taint doesn't keep the node from initializing.
+	// +kubebuilder:validation:XValidation:message="startupTaintTimeouts values must be durations, e.g. 10m",rule="self.all(x, self[x].matches('^([0-9]+(s|m|h))+$'))"
+	// +kubebuilder:validation:MaxProperties:=50
+	// +optional
+	StartupTaintTimeouts map[string]metav1.Duration `json:"startupTaintTimeouts,omitempty" hash:"ignore"`
+	// ReadinessGates are node conditions that must have the given status, in addition to the node being Ready, before
+	// Karpenter considers the NodeClaim initialized. Until then, pods aren't consolidated onto the node.
+	// +kubebuilder:validation:MaxItems:=20
+	// +optional
+	ReadinessGates []NodeReadinessGate `json:"readinessGates,omitempty" hash:"ignore"`
 	// Requirements are layered with GetLabels and applied to every node.
 	// +kubebuilder:validation:XValidation:message="requirements with operator 'In' must have a value defined",rule="self.all(x, x.operator == 'In' ? x.values.size() != 0 : true)"
 	// +kubebuilder:validation:XValidation:message="requirements operator 'Gt' or 'Lt' must have a single positive integer value",rule="self.all(x, (x.operator == 'Gt' || x.operator == 'Lt') ? (x.values.size() == 1 && int(x.values[0]) >= 0) : true)"
@@ -47,6 +70,21 @@ type NodeClaimSpec struct {
 	// NodeClassRef is a reference to an object that defines provider specific configuration
 	// +required
 	NodeClassRef *NodeClassReference `json:"nodeClassRef"`
+	// RegistrationTTL is the duration the controller will wait for the node of the NodeClaim to register, measured from
+	// when the NodeClaim's Registered status condition last transitioned, which is shortly after the NodeClaim is created.
+	// NodeClaims whose node hasn't registered within the TTL are deleted and replaced.
+	// If left undefined, the controller will wait 15 minutes for the node to register.
+	// +kubebuilder:validation:Pattern=`^([0-9]+(s|m|h))+$`
+	// +kubebuilder:validation:Type="string"
+	// +optional
+	RegistrationTTL *metav1.Duration `json:"registrationTTL,omitempty" hash:"ignore"`
+	// InitializationTTL is the duration the controller will wait for the node of the NodeClaim to initialize, measured
+	// from when the node registers. NodeClaims whose node hasn't initialized within the TTL are deleted and replaced.
+	// If left undefined, the controller will wait indefinitely for the node to initialize.
+	// +kubebuilder:validation:Pattern=`^([0-9]+(s|m|h))+$`
+	// +kubebuilder:validation:Type="string"
+	// +optional
+	InitializationTTL *metav1.Duration `json:"initializationTTL,omitempty" hash:"ignore"`
 	// TerminationGracePeriod is the maximum duration the controller will wait before forcefully deleting the pods on a node, measured from when deletion is first initiated.
 	//
 	// Warning: this feature takes precedence over a Pod's terminationGracePeriodSeconds value, and bypasses any blocked PDBs or the karpenter.sh/do-not-disrupt annotation.
@@ -74,6 +112,95 @@ type NodeClaimSpec struct {
 	// +kubebuilder:validation:Schemaless
 	// +optional
 	ExpireAfter NillableDuration `json:"expireAfter,omitempty"`
+	// ExpireAfterJitter is the maximum duration that is added to the ExpireAfter of each NodeClaim, so that NodeClaims
+	// that are created together don't all expire together. Each NodeClaim expires up to this much later, by an amount
+	// that is derived from its UID.
+	// +kubebuilder:validation:Pattern=`^([0-9]+(s|m|h))+$`
+	// +kubebuilder:validation:Type="string"
+	// +optional
+	ExpireAfterJitter *metav1.Duration `json:"expireAfterJitter,omitempty" hash:"ignore"`
+	// MaxLifetime is the hard limit on how long the node lives, measured from when the node is created. Unlike
+	// ExpireAfter, when it's reached the node is drained without waiting for PDBs or the karpenter.sh/do-not-disrupt
+	// annotation, as if its TerminationGracePeriod had elapsed. This is for compliance policies that require nodes to be
+	// recycled within a fixed period, and is typically set somewhat longer than ExpireAfter so that nodes are recycled
+	// gracefully when possible. If unset, nodes have no hard lifetime limit.
+	// +kubebuilder:validation:Pattern=`^([0-9]+(s|m|h))+$`
+	// +kubebuilder:validation:Type="string"
+	// +optional
+	MaxLifetime *metav1.Duration `json:"maxLifetime,omitempty"`
+	// Kubelet defines args to be used when configuring kubelet on provisioned nodes.
+	// Karpenter accounts for these values when computing the allocatable resources of an instance type.
+	// +optional
+	Kubelet *KubeletConfiguration `json:"kubelet,omitempty"`
+	// MetadataSyncPolicy controls whether changes to the labels and annotations of the NodeClaim after its Node registers
+	// are synced onto the Node. With "OnRegistration", the default, they're only synced when the Node registers. With
+	// "Always", labels and annotations that are added to or changed on the NodeClaim are also synced onto the Node, without
+	// replacing it. Labels and annotations that are removed from the NodeClaim aren't removed from the Node.
+	// +kubebuilder:validation:Enum:={OnRegistration,Always}
+	// +optional
+	MetadataSyncPolicy MetadataSyncPolicy `json:"metadataSyncPolicy,omitempty" hash:"ignore"`
+	// InstanceRetentionPolicy controls what happens to the instance of the NodeClaim when the NodeClaim is deleted. With
+	// "Delete", the default, the instance is terminated. With "Retain", the node is drained and removed from the cluster,
+	// but the instance is left running, e.g. for debugging. Retained instances are no longer tracked by Karpenter, and
+	// are left out of the garbage collection of orphaned instances.
+	// +kubebuilder:validation:Enum:={Delete,Retain}
+	// +optional
+	InstanceRetentionPolicy InstanceRetentionPolicy `json:"instanceRetentionPolicy,omitempty" hash:"ignore"`
+}
+
+// MetadataSyncPolicy controls when the labels and annotations of a NodeClaim are synced onto its Node
+type MetadataSyncPolicy string
+
+const (
+	MetadataSyncPolicyOnRegistration MetadataSyncPolicy = "OnRegistration"
+	MetadataSyncPolicyAlways         MetadataSyncPolicy = "Always"
+)
+
+// InstanceRetentionPolicy controls whether the instance of a NodeClaim is terminated when the NodeClaim is deleted
+type InstanceRetentionPolicy string
+
+const (
+	InstanceRetentionPolicyDelete InstanceRetentionPolicy = "Delete"
+	InstanceRetentionPolicyRetain InstanceRetentionPolicy = "Retain"
+)
+
+// NodeReadinessGate is a node condition that must have the given status before a NodeClaim is initialized
+type NodeReadinessGate struct {
+	// ConditionType is the type of the node condition, e.g. NetworkReady
+	// +kubebuilder:validation:MinLength:=1
+	// +required
+	ConditionType v1.NodeConditionType `json:"conditionType"`
+	// Status is the status that the node condition must have
+	// +kubebuilder:validation:Enum:={"True","False"}
+	// +kubebuilder:default:="True"
+	// +optional
+	Status v1.ConditionStatus `json:"status,omitempty"`
+}
+
+// KubeletConfiguration defines args to be used when configuring kubelet on provisioned nodes.
+// They are a subset of the upstream types, recognizing not all options may be supported.
+// Wherever possible, the types and names should reflect the upstream kubelet types.
+// https://pkg.go.dev/k8s.io/kubelet/config/v1beta1#KubeletConfiguration
+type KubeletConfiguration struct {
+	// MaxPods is an override for the maximum number of pods that can run on
+	// a worker node instance.
+	// +kubebuilder:validation:Minimum:=0
+	// +optional
+	MaxPods *int32 `json:"maxPods,omitempty"`
+	// SystemReserved contains resources reserved for OS system daemons and kernel memory.
+	// +kubebuilder:validation:XValidation:message="valid keys for systemReserved are ['cpu','memory','ephemeral-storage','pid']",rule="self.all(x, x=='cpu' || x=='memory' || x=='ephemeral-storage' || x=='pid')"
+	// +kubebuilder:validation:XValidation:message="systemReserved value cannot be a negative resource quantity",rule="self.all(x, !self[x].startsWith('-'))"
+	// +optional
+	SystemReserved map[string]string `json:"systemReserved,omitempty"`
+	// KubeReserved contains resources reserved for Kubernetes system components.
+	// +kubebuilder:validation:XValidation:message="valid keys for kubeReserved are ['cpu','memory','ephemeral-storage','pid']",rule="self.all(x, x=='cpu' || x=='memory' || x=='ephemeral-storage' || x=='pid')"
+	// +kubebuilder:validation:XValidation:message="kubeReserved value cannot be a negative resource quantity",rule="self.all(x, !self[x].startsWith('-'))"
+	// +optional
+	KubeReserved map[string]string `json:"kubeReserved,omitempty"`
+	// EvictionHard is the map of signal names to quantities that define hard eviction thresholds
+	// +kubebuilder:validation:XValidation:message="valid keys for evictionHard are ['memory.available','nodefs.available','nodefs.inodesFree','imagefs.available','imagefs.inodesFree','pid.available']",rule="self.all(x, x in ['memory.available','nodefs.available','nodefs.inodesFree','imagefs.available','imagefs.inodesFree','pid.available'])"
+	// +optional
+	EvictionHard map[string]string `json:"evictionHard,omitempty"`
 }
 
 // A node selector requirement with min values is a selector that contains values, a key, an operator that relates the key and values
@@ -123,6 +250,11 @@ func (ncr *NodeClassReference) GroupKind() schema.GroupKind {
 type Provider = runtime.RawExtension
 
 // NodeClaim is the Schema for the NodeClaims API
+// NodeClaims are usually created by Karpenter for a NodePool, but they can also be created directly for a single node
+// without the karpenter.sh/nodepool label. These standalone NodeClaims are launched, registered and initialized like
+// any other NodeClaim, but they're never drifted or consolidated, and are only removed when they're deleted, expire,
+// fail to register within their registration TTL, or their instance is gone. Like NodePools, standalone NodeClaims that
+// don't constrain the architecture, operating system or capacity type are launched as amd64, linux and on-demand.
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:path=nodeclaims,scope=Cluster,categories=karpenter
 // +kubebuilder:subresource:status
@@ -26,13 +26,26 @@ const (
 	ConditionTypeLaunched             = "Launched"
 	ConditionTypeRegistered           = "Registered"
 	ConditionTypeInitialized          = "Initialized"
+	ConditionTypeDraining             = "Draining"
+	ConditionTypeDrained              = "Drained"
+	ConditionTypeTerminated           = "Terminated"
 	ConditionTypeConsolidatable       = "Consolidatable"
 	ConditionTypeDrifted              = "Drifted"
+	ConditionTypeInterrupted          = "Interrupted"
 	ConditionTypeInstanceTerminating  = "InstanceTerminating"
 	ConditionTypeConsistentStateFound = "ConsistentStateFound"
 	ConditionTypeDisruptionReason     = "DisruptionReason"
 )
 
+// Reasons that the CloudProvider failed to launch a NodeClaim. CloudProviders may return other reasons as well.
+const (
+	LaunchFailureReasonInsufficientCapacity = "InsufficientCapacity"
+	LaunchFailureReasonNodeClassNotReady    = "NodeClassNotReady"
+	LaunchFailureReasonQuotaExceeded        = "QuotaExceeded"
+	LaunchFailureReasonInvalidConfiguration = "InvalidConfiguration"
+	LaunchFailureReasonUnknown              = "LaunchFailed"
+)
+
 // NodeClaimStatus defines the observed state of NodeClaim
 type NodeClaimStatus struct {
 	// NodeName is the name of the corresponding node object
@@ -58,6 +71,52 @@ type NodeClaimStatus struct {
 	// is also considered as removed.
 	// +optional
 	LastPodEventTime metav1.Time `json:"lastPodEventTime,omitempty"`
+	// LaunchFailure is why the latest attempt to launch the NodeClaim failed. It's cleared once the NodeClaim launches.
+	// +optional
+	LaunchFailure *LaunchFailure `json:"launchFailure,omitempty"`
+	// LaunchedInstance is the instance that the CloudProvider launched for the NodeClaim, as it was resolved at launch
+	// +optional
+	LaunchedInstance *LaunchedInstance `json:"launchedInstance,omitempty"`
+}
+
+// LaunchFailure describes why the CloudProvider failed to launch a NodeClaim
+type LaunchFailure struct {
+	// Reason is the kind of failure, e.g. InsufficientCapacity, QuotaExceeded or InvalidConfiguration
+	// +required
+	Reason string `json:"reason"`
+	// Message is the error that the CloudProvider returned
+	// +optional
+	Message string `json:"message,omitempty"`
+	// Retryable is true if launching is expected to succeed once retried, e.g. with other instance types after capacity
+	// ran out. Failures that aren't retryable, e.g. exceeded quotas or invalid configuration, need to be fixed by the
+	// user and are worth alerting on.
+	// +required
+	Retryable bool `json:"retryable"`
+	// Time is when the launch failed
+	// +required
+	Time metav1.Time `json:"time"`
+}
+
+// LaunchedInstance describes the instance that the CloudProvider launched for a NodeClaim
+type LaunchedInstance struct {
+	// InstanceType is the instance type that was launched
+	// +required
+	InstanceType string `json:"instanceType"`
+	// Zone is the zone that the instance was launched in
+	// +optional
+	Zone string `json:"zone,omitempty"`
+	// CapacityType is the capacity type that the instance was launched with, e.g. spot or on-demand
+	// +optional
+	CapacityType string `json:"capacityType,omitempty"`
+	// Price is the price of the instance's offering when it launched, as a decimal. It's unset if the CloudProvider
+	// didn't report the price of the offering, or if the NodeClaim launched before its price was recorded.
+	// +kubebuilder:validation:Pattern=`^[0-9]+(\.[0-9]+)?$`
+	// +optional
+	Price string `json:"price,omitempty"`
+	// Currency is the currency of the price, e.g. USD. It's unset if the CloudProvider doesn't report the currency of its
+	// prices.
+	// +optional
+	Currency string `json:"currency,omitempty"`
 }
 
 func (in *NodeClaim) StatusConditions() status.ConditionSet {
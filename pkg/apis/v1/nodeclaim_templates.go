@@ -0,0 +1,102 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"text/template"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// TemplateData is what the label and taint templates of a NodeClaim are resolved against
+type TemplateData struct {
+	InstanceType TemplateInstanceType
+	Zone         string
+	CapacityType string
+	Architecture string
+	// Labels are all the labels of the NodeClaim's node
+	Labels map[string]string
+}
+
+type TemplateInstanceType struct {
+	Name string
+}
+
+// ParseTemplate parses the value of a label or taint template. Labels that the node doesn't have resolve to "".
+func ParseTemplate(value string) (*template.Template, error) {
+	return template.New("").Option("missingkey=zero").Parse(value)
+}
+
+// ResolveTemplates resolves the label and taint templates of the NodeClaim against the labels of its node, returning
+// an error if a template doesn't resolve to a valid label or taint value
+func (in *NodeClaimSpec) ResolveTemplates(labels map[string]string) (map[string]string, []v1.Taint, error) {
+	data := TemplateData{
+		InstanceType: TemplateInstanceType{Name: labels[v1.LabelInstanceTypeStable]},
+		Zone:         labels[v1.LabelTopologyZone],
+		CapacityType: labels[CapacityTypeLabelKey],
+		Architecture: labels[v1.LabelArchStable],
+		Labels:       labels,
+	}
+	resolvedLabels := map[string]string{}
+	for key, value := range in.LabelTemplates {
+		resolved, err := resolve(value, data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("resolving label template %q, %w", key, err)
+		}
+		for _, err := range validation.IsValidLabelValue(resolved) {
+			return nil, nil, fmt.Errorf("resolving label template %q, invalid value %q, %s", key, resolved, err)
+		}
+		resolvedLabels[key] = resolved
+	}
+	var resolvedTaints []v1.Taint
+	for _, taint := range in.TaintTemplates {
+		resolved, err := resolve(taint.Value, data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("resolving taint template %q, %w", taint.Key, err)
+		}
+		if resolved != "" {
+			for _, err := range validation.IsValidLabelValue(resolved) {
+				return nil, nil, fmt.Errorf("resolving taint template %q, invalid value %q, %s", taint.Key, resolved, err)
+			}
+		}
+		taint.Value = resolved
+		resolvedTaints = append(resolvedTaints, taint)
+	}
+	return resolvedLabels, resolvedTaints, nil
+}
+
+func resolve(value string, data TemplateData) (string, error) {
+	t, err := ParseTemplate(value)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if err := t.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// TaintsWithTemplates returns the taints of the NodeClaim along with its taint templates. The values of taint templates
+// aren't resolved until the NodeClaim's Node registers, so only tolerations with the Exists operator tolerate them.
+func (in *NodeClaimSpec) TaintsWithTemplates() []v1.Taint {
+	return append(slices.Clone(in.Taints), in.TaintTemplates...)
+}
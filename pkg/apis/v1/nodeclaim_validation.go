@@ -62,6 +62,34 @@ type taintKeyEffect struct {
 func (in *NodeClaimTemplateSpec) validateTaints() (errs error) {
 	existing := map[taintKeyEffect]struct{}{}
 	errs = multierr.Combine(validateTaintsField(in.Taints, existing, "taints"), validateTaintsField(in.StartupTaints, existing, "startupTaints"))
+	// the values of taint templates are validated once they're resolved
+	errs = multierr.Append(errs, validateTaintsField(lo.Map(in.TaintTemplates, func(t v1.Taint, _ int) v1.Taint {
+		t.Value = ""
+		return t
+	}), existing, "taintTemplates"))
+	for _, taint := range in.TaintTemplates {
+		if _, err := ParseTemplate(taint.Value); err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("invalid template %q for taintTemplates[%s], %w", taint.Value, taint.Key, err))
+		}
+	}
+	return errs
+}
+
+func (in *NodeClaimTemplateSpec) validateLabelTemplates() (errs error) {
+	for key, value := range in.LabelTemplates {
+		if key == NodePoolLabelKey {
+			errs = multierr.Append(errs, fmt.Errorf("invalid key name %q in labelTemplates, restricted", key))
+		}
+		for _, err := range validation.IsQualifiedName(key) {
+			errs = multierr.Append(errs, fmt.Errorf("invalid key name %q in labelTemplates, %q", key, err))
+		}
+		if err := IsRestrictedLabel(key); err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("invalid key name %q in labelTemplates, %s", key, err.Error()))
+		}
+		if _, err := ParseTemplate(value); err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("invalid template %q for labelTemplates[%s], %w", value, key, err))
+		}
+	}
 	return errs
 }
 
@@ -0,0 +1,79 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NodeOverlaySpec describes the adjustments that are made to the instance types that the overlay selects
+// +kubebuilder:validation:XValidation:message="cannot set both price and priceAdjustment",rule="!(has(self.price) && has(self.priceAdjustment))"
+type NodeOverlaySpec struct {
+	// Requirements select the instance types that the overlay applies to. Prices are only adjusted for the offerings
+	// that are compatible with the requirements, e.g. an overlay that requires the spot capacity type only adjusts the
+	// price of spot offerings.
+	// +kubebuilder:validation:XValidation:message="requirements with operator 'In' must have a value defined",rule="self.all(x, x.operator == 'In' ? x.values.size() != 0 : true)"
+	// +kubebuilder:validation:MaxItems:=100
+	// +optional
+	Requirements []corev1.NodeSelectorRequirement `json:"requirements,omitempty"`
+	// PriceAdjustment adjusts the price of the selected offerings, either by a percentage of the price (e.g. "-10%")
+	// or by an absolute amount (e.g. "+0.05"). Prices are never adjusted below zero.
+	// +kubebuilder:validation:Pattern=`^(\+|-)?[0-9]+(\.[0-9]+)?%?$`
+	// +optional
+	PriceAdjustment *string `json:"priceAdjustment,omitempty"`
+	// Price overrides the price of the selected offerings, e.g. with an internal chargeback rate
+	// +kubebuilder:validation:Pattern=`^[0-9]+(\.[0-9]+)?$`
+	// +optional
+	Price *string `json:"price,omitempty"`
+	// Capacity overrides the capacity of the selected instance types. Resources that the instance types don't have,
+	// e.g. extended resources that are registered by a device plugin, are added.
+	// +optional
+	Capacity corev1.ResourceList `json:"capacity,omitempty"`
+	// Overhead is reserved on nodes of the selected instance types in addition to their system reserved resources,
+	// e.g. for agents that run outside of Kubernetes
+	// +optional
+	Overhead corev1.ResourceList `json:"overhead,omitempty"`
+	// Weight is the priority given to the overlay when multiple overlays select the same instance type. Each price,
+	// capacity and overhead value is taken from the overlay with the highest weight that sets it.
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Maximum:=100
+	// +optional
+	Weight *int32 `json:"weight,omitempty"`
+}
+
+// NodeOverlay adjusts the prices, capacity and overhead of the instance types that the cloud provider reports, e.g. to
+// account for discounts or internal chargeback rates. The adjusted instance types are used for both provisioning and
+// consolidation.
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=nodeoverlays,scope=Cluster,categories=karpenter
+// +kubebuilder:printcolumn:name="Weight",type="integer",JSONPath=".spec.weight",priority=1,description=""
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description=""
+type NodeOverlay struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec NodeOverlaySpec `json:"spec,omitempty"`
+}
+
+// NodeOverlayList contains a list of NodeOverlays
+// +kubebuilder:object:root=true
+type NodeOverlayList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NodeOverlay `json:"items"`
+}
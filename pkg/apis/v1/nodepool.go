@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"math"
 	"strconv"
+	"strings"
 
 	"github.com/mitchellh/hashstructure/v2"
 	"github.com/robfig/cron/v3"
@@ -47,6 +48,20 @@ type NodePoolSpec struct {
 	// Limits define a set of bounds for provisioning capacity.
 	// +optional
 	Limits Limits `json:"limits,omitempty"`
+	// DomainLimits bound the capacity that the NodePool can provision in each domain of a topology key, e.g.
+	// {"topology.kubernetes.io/zone": {"cpu": "100"}} allows at most 100 CPU of nodes in each zone, so that a single
+	// domain can't absorb the NodePool's entire limits. The topology keys must be well known labels, and capacity types are
+	// limited with CapacityTypeLimits instead.
+	// +kubebuilder:validation:XValidation:message="valid keys for domainLimits are ['topology.kubernetes.io/zone','topology.kubernetes.io/region','kubernetes.io/arch','kubernetes.io/os','node.kubernetes.io/instance-type']",rule="self.all(x, x in ['topology.kubernetes.io/zone','topology.kubernetes.io/region','kubernetes.io/arch','kubernetes.io/os','node.kubernetes.io/instance-type'])"
+	// +kubebuilder:validation:MaxProperties:=5
+	// +optional
+	DomainLimits map[string]Limits `json:"domainLimits,omitempty"`
+	// CapacityTypeLimits bound the capacity that the NodePool can provision of each capacity type, e.g.
+	// {"on-demand": {"cpu": "500"}, "spot": {"cpu": "2000"}}. Capacity types that aren't listed are only bound by the
+	// NodePool's limits.
+	// +kubebuilder:validation:MaxProperties:=5
+	// +optional
+	CapacityTypeLimits map[string]Limits `json:"capacityTypeLimits,omitempty"`
 	// Weight is the priority given to the nodepool during scheduling. A higher
 	// numerical weight indicates that this nodepool will be ordered
 	// ahead of other nodepools with lower weights. A nodepool with no weight
@@ -55,6 +70,113 @@ type NodePoolSpec struct {
 	// +kubebuilder:validation:Maximum:=100
 	// +optional
 	Weight *int32 `json:"weight,omitempty"`
+	// Replicas is the number of nodes that Karpenter maintains for this NodePool, regardless of pod demand.
+	// A NodePool with replicas set is static: it is not used to provision capacity for pending pods, and its
+	// nodes are never consolidated. Drifted nodes are replaced by launching a new node before the drifted one is removed.
+	// Replicas can also be set through the NodePool's scale subresource, e.g. with kubectl scale.
+	// +kubebuilder:validation:Minimum:=0
+	// +optional
+	Replicas *int64 `json:"replicas,omitempty"`
+	// Headroom is an amount of spare capacity that Karpenter keeps available on nodes from this NodePool, in addition
+	// to the capacity required by pending pods. The headroom is split into chunks of at most one CPU which are scheduled
+	// alongside pending pods, so new nodes are launched when the existing nodes can't hold the headroom.
+	// Headroom isn't exposed through the NodePool's scale subresource, which only scales a NodePool's replicas.
+	// +optional
+	Headroom v1.ResourceList `json:"headroom,omitempty"`
+	// MinNodesPerZone is the number of nodes that Karpenter keeps from this NodePool in each zone that the NodePool
+	// can launch nodes into, regardless of pod demand. Nodes are launched in zones that are below the minimum, and nodes
+	// aren't disrupted if that would take their zone below the minimum.
+	// +kubebuilder:validation:Minimum:=1
+	// +optional
+	MinNodesPerZone *int32 `json:"minNodesPerZone,omitempty"`
+	// MinNodes is the number of nodes that Karpenter keeps from this NodePool, regardless of pod demand. Nodes are
+	// launched while the NodePool has fewer nodes, and nodes aren't disrupted if that would take the NodePool below the
+	// minimum. The maximum number of nodes can be set with the nodes resource of the NodePool's limits.
+	// +kubebuilder:validation:Minimum:=1
+	// +optional
+	MinNodes *int32 `json:"minNodes,omitempty"`
+	// MinResources is the minimum capacity of the nodes launched from this NodePool. Instance types with less capacity
+	// than any of these resources are never selected, even when a single small pod is enough to trigger a scale-up.
+	// +optional
+	MinResources v1.ResourceList `json:"minResources,omitempty"`
+	// SimulatedPodRequests are the CPU and memory requests that Karpenter simulates for pods that don't request them when
+	// fitting them on this NodePool's nodes, both new and existing. They aren't applied to the pods, so the kube-scheduler still
+	// binds pods by their actual requests, but they stop pods without requests, e.g. best-effort pods, from packing onto the
+	// smallest instance types that fit their daemonsets, or being consolidated onto nodes without room for them.
+	// +kubebuilder:validation:XValidation:message="simulatedPodRequests may only contain cpu and memory",rule="self.all(x, x in ['cpu', 'memory'])"
+	// +optional
+	SimulatedPodRequests v1.ResourceList `json:"simulatedPodRequests,omitempty"`
+	// LaunchRate limits how quickly NodeClaims are launched for this NodePool, so that a runaway workload can't cause
+	// Karpenter to launch a large number of nodes before anyone notices. If unset, launches aren't rate limited.
+	// +optional
+	LaunchRate *LaunchRate `json:"launchRate,omitempty"`
+	// InstanceTypeTruncation controls how the instance types that are compatible with a NodeClaim are truncated before
+	// the NodeClaim is launched. If unset, the 60 cheapest instance types are used.
+	// +optional
+	InstanceTypeTruncation *InstanceTypeTruncation `json:"instanceTypeTruncation,omitempty"`
+	// CapacityTypeSplit is the percentage of the NodeClaims launched from this NodePool that should use each capacity
+	// type, e.g. {"spot": 70, "on-demand": 30}. New NodeClaims are launched with the capacity type that is furthest
+	// below its percentage, rather than the cheapest capacity type. Capacity types that aren't listed aren't launched.
+	// +kubebuilder:validation:XValidation:message="capacityTypeSplit percentages can't be negative",rule="self.all(k, self[k] >= 0)"
+	// +kubebuilder:validation:XValidation:message="capacityTypeSplit percentages must add up to 100",rule="self.map(k, self[k]).sum() == 100"
+	// +kubebuilder:validation:MaxProperties:=3
+	// +optional
+	CapacityTypeSplit map[string]int32 `json:"capacityTypeSplit,omitempty"`
+	// MaxPricePercentile limits the instance types that are considered for a NodeClaim to the cheapest percentage of
+	// the NodePool's instance types that are compatible with the NodeClaim's pods, bounding the cost of NodeClaims even
+	// when the NodePool's requirements are broad. If unset, all compatible instance types are considered.
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Maximum:=100
+	// +optional
+	MaxPricePercentile *int32 `json:"maxPricePercentile,omitempty"`
+	// TopologyDomains are additional topology domains of the nodes launched from this NodePool, by label key, e.g.
+	// {"example.com/rack": ["rack-a", "rack-b"]}. Pods can spread across these domains with topology spread constraints
+	// and pod affinities in the same way as they spread across zones. NodeClaims are labeled with the domain that their
+	// pods were scheduled to.
+	// +kubebuilder:validation:XValidation:message="topologyDomains must have at least one value for each key",rule="self.all(k, self[k].size() > 0)"
+	// +kubebuilder:validation:MaxProperties:=10
+	// +optional
+	TopologyDomains map[string][]string `json:"topologyDomains,omitempty"`
+	// ExcludedDaemonSets selects daemonsets, by the labels of their pods, that aren't counted in the overhead of the
+	// nodes launched from this NodePool. This is for daemonsets that never run on the NodePool's nodes due to
+	// constraints that Karpenter can't see, e.g. an admission webhook, which would otherwise cause nodes to be oversized.
+	// +optional
+	ExcludedDaemonSets *metav1.LabelSelector `json:"excludedDaemonSets,omitempty"`
+	// AllowedNamespaces restricts the NodePool to the pods in the listed namespaces, so that tenants can't launch nodes
+	// from, or be scheduled onto the nodes of, another tenant's NodePool. If unset, pods in any namespace are allowed.
+	// Karpenter doesn't prevent the kube-scheduler from binding other pods to the NodePool's nodes, so this is typically
+	// paired with taints or an admission policy that enforces the same restriction.
+	// +kubebuilder:validation:MinItems:=1
+	// +kubebuilder:validation:MaxItems:=100
+	// +optional
+	AllowedNamespaces []string `json:"allowedNamespaces,omitempty"`
+	// ExistingCapacityWindow is how long after a NodeClaim is launched from this NodePool that pending pods which could
+	// run on its node wait for the node to initialize, rather than triggering new NodeClaims when they don't fit alongside
+	// the pods that the node was launched for. The pods on initializing nodes often complete or schedule elsewhere, and a
+	// node's allocatable resources are only known once it registers, so waiting trades scheduling latency for fewer,
+	// better-utilized nodes. If unset, pods never wait.
+	// +kubebuilder:validation:Pattern=`^([0-9]+(s|m|h))+$`
+	// +kubebuilder:validation:Type="string"
+	// +optional
+	ExistingCapacityWindow *metav1.Duration `json:"existingCapacityWindow,omitempty"`
+	// Paused stops Karpenter from launching nodes for this NodePool and from voluntarily disrupting its nodes, while
+	// leaving its existing nodes running. This is useful to freeze a NodePool during an incident or a migration. Nodes
+	// are still removed when they expire, when they're unhealthy, or when they're deleted.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+}
+
+// LaunchRate limits the number of NodeClaims that are launched for a NodePool over time
+type LaunchRate struct {
+	// NodeClaimsPerMinute is the number of NodeClaims that can be launched for the NodePool each minute.
+	// +kubebuilder:validation:Minimum:=1
+	// +required
+	NodeClaimsPerMinute int32 `json:"nodeClaimsPerMinute"`
+	// Burst is the number of NodeClaims that can be launched at once after the NodePool hasn't launched any NodeClaims
+	// for a while. If unset, this is the same as NodeClaimsPerMinute.
+	// +kubebuilder:validation:Minimum:=1
+	// +optional
+	Burst *int32 `json:"burst,omitempty"`
 }
 
 type Disruption struct {
@@ -72,6 +194,12 @@ type Disruption struct {
 	// +kubebuilder:validation:Enum:={WhenEmpty,WhenEmptyOrUnderutilized}
 	// +optional
 	ConsolidationPolicy ConsolidationPolicy `json:"consolidationPolicy,omitempty"`
+	// CapacityTypes override the consolidateAfter and consolidationPolicy of the NodePool's nodes with a capacity type,
+	// e.g. to consolidate on-demand nodes when they're underutilized while leaving spot nodes to be interrupted.
+	// +kubebuilder:validation:XValidation:message="'capacityType' must be unique",rule="self.all(x, self.exists_one(y, y.capacityType == x.capacityType))"
+	// +kubebuilder:validation:MaxItems=10
+	// +optional
+	CapacityTypes []CapacityTypeDisruption `json:"capacityTypes,omitempty"`
 	// Budgets is a list of Budgets.
 	// If there are multiple active budgets, Karpenter uses
 	// the most restrictive value. If left undefined,
@@ -83,12 +211,32 @@ type Disruption struct {
 	Budgets []Budget `json:"budgets,omitempty" hash:"ignore"`
 }
 
+// CapacityTypeDisruption overrides how the nodes with a capacity type are consolidated
+type CapacityTypeDisruption struct {
+	// CapacityType is the value of the karpenter.sh/capacity-type label of the nodes that this applies to.
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	CapacityType string `json:"capacityType"`
+	// ConsolidateAfter is the duration the controller will wait before attempting to terminate nodes with the capacity
+	// type that are underutilized. If unset, the NodePool's consolidateAfter applies.
+	// +kubebuilder:validation:Pattern=`^(([0-9]+(s|m|h))+)|(Never)$`
+	// +kubebuilder:validation:Type="string"
+	// +kubebuilder:validation:Schemaless
+	// +optional
+	ConsolidateAfter *NillableDuration `json:"consolidateAfter,omitempty"`
+	// ConsolidationPolicy describes which nodes with the capacity type Karpenter can disrupt through its consolidation
+	// algorithm. If unset, the NodePool's consolidationPolicy applies.
+	// +kubebuilder:validation:Enum:={WhenEmpty,WhenEmptyOrUnderutilized}
+	// +optional
+	ConsolidationPolicy ConsolidationPolicy `json:"consolidationPolicy,omitempty"`
+}
+
 // Budget defines when Karpenter will restrict the
 // number of Node Claims that can be terminating simultaneously.
 type Budget struct {
 	// Reasons is a list of disruption methods that this budget applies to. If Reasons is not set, this budget applies to all methods.
 	// Otherwise, this will apply to each reason defined.
-	// allowed reasons are Underutilized, Empty, and Drifted.
+	// allowed reasons are Underutilized, Empty, Drifted, and Interrupted.
 	// +optional
 	Reasons []DisruptionReason `json:"reasons,omitempty"`
 	// Nodes dictates the maximum number of NodeClaims owned by this NodePool
@@ -120,6 +268,38 @@ type Budget struct {
 	Duration *metav1.Duration `json:"duration,omitempty" hash:"ignore"`
 }
 
+// InstanceTypeTruncation controls which instance types are included in a NodeClaim when more instance types are
+// compatible with it than can be sent to the cloud provider
+// +kubebuilder:validation:XValidation:message="'pricePercentile' must be set if and only if the strategy is PricePercentile",rule="has(self.pricePercentile) == (has(self.strategy) && self.strategy == 'PricePercentile')"
+type InstanceTypeTruncation struct {
+	// MaxInstanceTypes is the maximum number of instance types that are included in a NodeClaim. If unset, this is 60.
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Maximum:=60
+	// +optional
+	MaxInstanceTypes *int32 `json:"maxInstanceTypes,omitempty"`
+	// Strategy determines which instance types are included in a NodeClaim. Cheapest includes the cheapest instance
+	// types. PricePercentile includes the cheapest instance types that are priced within the PricePercentile of the
+	// compatible instance types. Diverse includes instance types that cover as many values of the requirements with
+	// minValues as possible before including the cheapest of the remaining instance types. If unset, this is Cheapest.
+	// +kubebuilder:validation:Enum:={Cheapest,PricePercentile,Diverse}
+	// +optional
+	Strategy InstanceTypeTruncationStrategy `json:"strategy,omitempty"`
+	// PricePercentile is the percentile of the prices of the compatible instance types that instance types must be
+	// priced within to be included in a NodeClaim, when using the PricePercentile strategy.
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Maximum:=100
+	// +optional
+	PricePercentile *int32 `json:"pricePercentile,omitempty"`
+}
+
+type InstanceTypeTruncationStrategy string
+
+const (
+	InstanceTypeTruncationStrategyCheapest        InstanceTypeTruncationStrategy = "Cheapest"
+	InstanceTypeTruncationStrategyPricePercentile InstanceTypeTruncationStrategy = "PricePercentile"
+	InstanceTypeTruncationStrategyDiverse         InstanceTypeTruncationStrategy = "Diverse"
+)
+
 type ConsolidationPolicy string
 
 const (
@@ -128,15 +308,45 @@ const (
 )
 
 // DisruptionReason defines valid reasons for disruption budgets.
-// +kubebuilder:validation:Enum={Underutilized,Empty,Drifted}
+// +kubebuilder:validation:Enum={Underutilized,Empty,Drifted,Interrupted}
 type DisruptionReason string
 
 const (
 	DisruptionReasonUnderutilized DisruptionReason = "Underutilized"
 	DisruptionReasonEmpty         DisruptionReason = "Empty"
 	DisruptionReasonDrifted       DisruptionReason = "Drifted"
+	DisruptionReasonInterrupted   DisruptionReason = "Interrupted"
 )
 
+// DisruptionReasons are the reasons that disruption budgets can be scoped to
+var DisruptionReasons = []DisruptionReason{
+	DisruptionReasonEmpty,
+	DisruptionReasonDrifted,
+	DisruptionReasonUnderutilized,
+	DisruptionReasonInterrupted,
+}
+
+// ForCapacityType returns the disruption settings of the nodes with the capacity type, where the capacity type's
+// overrides replace the NodePool's consolidateAfter and consolidationPolicy
+func (in *Disruption) ForCapacityType(capacityType string) Disruption {
+	out := *in
+	override, ok := lo.Find(in.CapacityTypes, func(ct CapacityTypeDisruption) bool { return ct.CapacityType == capacityType })
+	if !ok {
+		return out
+	}
+	if override.ConsolidateAfter != nil {
+		out.ConsolidateAfter = *override.ConsolidateAfter
+	}
+	if override.ConsolidationPolicy != "" {
+		out.ConsolidationPolicy = override.ConsolidationPolicy
+	}
+	return out
+}
+
+// ResourceNodes is the resource that bounds the number of nodes of a NodePool in its limits, and that counts the nodes
+// of the NodePool in its status
+const ResourceNodes = v1.ResourceName("nodes")
+
 type Limits v1.ResourceList
 
 func (l Limits) ExceededBy(resources v1.ResourceList) error {
@@ -153,8 +363,20 @@ func (l Limits) ExceededBy(resources v1.ResourceList) error {
 	return nil
 }
 
+// RemainingNodes returns the number of nodes that can still be launched before the number of nodes reaches the limit,
+// or false if the number of nodes isn't limited
+func (l Limits) RemainingNodes(resources v1.ResourceList) (int64, bool) {
+	limit, ok := l[ResourceNodes]
+	if !ok {
+		return 0, false
+	}
+	usage := resources[ResourceNodes]
+	return max(limit.Value()-usage.Value(), 0), true
+}
+
 type NodeClaimTemplate struct {
 	ObjectMeta `json:"metadata,omitempty"`
+	// +kubebuilder:validation:XValidation:message="fallbackNodeClassRefs must have the same group and kind as nodeClassRef",rule="!has(self.fallbackNodeClassRefs) || self.fallbackNodeClassRefs.all(x, x.group == self.nodeClassRef.group && x.kind == self.nodeClassRef.kind)"
 	// +required
 	Spec NodeClaimTemplateSpec `json:"spec"`
 }
@@ -166,12 +388,35 @@ type NodeClaimTemplateSpec struct {
 	// Taints will be applied to the NodeClaim's node.
 	// +optional
 	Taints []v1.Taint `json:"taints,omitempty"`
+	// TaintTemplates are taints whose values are Go templates that are resolved against the properties of the NodeClaim's
+	// instance when its node registers, e.g. {{ .InstanceType.Name }}, {{ .Zone }}, {{ .CapacityType }}, {{ .Architecture }}
+	// or {{ index .Labels "example.com/key" }}. Their values aren't known until then, so pods only tolerate them for
+	// provisioning through a toleration with the Exists operator.
+	// +optional
+	TaintTemplates []v1.Taint `json:"taintTemplates,omitempty"`
+	// LabelTemplates are labels whose values are Go templates that are resolved like the TaintTemplates, and applied to
+	// the NodeClaim's node when it registers. Their values aren't known until then, so pods can't select them for provisioning.
+	// +kubebuilder:validation:MaxProperties:=100
+	// +optional
+	LabelTemplates map[string]string `json:"labelTemplates,omitempty"`
 	// StartupTaints are taints that are applied to nodes upon startup which are expected to be removed automatically
 	// within a short period of time, typically by a DaemonSet that tolerates the taint. These are commonly used by
 	// daemonsets to allow initialization and enforce startup ordering.  StartupTaints are ignored for provisioning
 	// purposes in that pods are not required to tolerate a StartupTaint in order to have nodes provisioned for them.
 	// +optional
 	StartupTaints []v1.Taint `json:"startupTaints,omitempty"`
+	// StartupTaintTimeouts are how long, by taint key, Karpenter waits for a startup taint to be removed from the node
+	// after it registers. Karpenter removes startup taints that are still on the node after their timeout itself, so
+	// that a broken agent that never removes its taint doesn't keep the node from initializing.
+	// +kubebuilder:validation:XValidation:message="startupTaintTimeouts values must be durations, e.g. 10m",rule="self.all(x, self[x].matches('^([0-9]+(s|m|h))+$'))"
+	// +kubebuilder:validation:MaxProperties:=50
+	// +optional
+	StartupTaintTimeouts map[string]metav1.Duration `json:"startupTaintTimeouts,omitempty" hash:"ignore"`
+	// ReadinessGates are node conditions that must have the given status, in addition to the node being Ready, before
+	// Karpenter considers the NodeClaim initialized. Until then, pods aren't consolidated onto the node.
+	// +kubebuilder:validation:MaxItems:=20
+	// +optional
+	ReadinessGates []NodeReadinessGate `json:"readinessGates,omitempty" hash:"ignore"`
 	// Requirements are layered with GetLabels and applied to every node.
 	// +kubebuilder:validation:XValidation:message="requirements with operator 'In' must have a value defined",rule="self.all(x, x.operator == 'In' ? x.values.size() != 0 : true)"
 	// +kubebuilder:validation:XValidation:message="requirements operator 'Gt' or 'Lt' must have a single positive integer value",rule="self.all(x, (x.operator == 'Gt' || x.operator == 'Lt') ? (x.values.size() == 1 && int(x.values[0]) >= 0) : true)"
@@ -184,6 +429,27 @@ type NodeClaimTemplateSpec struct {
 	// +kubebuilder:validation:XValidation:rule="self.kind == oldSelf.kind",message="nodeClassRef.kind is immutable"
 	// +required
 	NodeClassRef *NodeClassReference `json:"nodeClassRef"`
+	// FallbackNodeClassRefs are NodeClasses, in priority order, that new NodeClaims are launched with when the NodeClass
+	// of the NodeClassRef isn't ready or NodeClaims repeatedly fail to launch with it. They must have the same group and
+	// kind as the NodeClassRef.
+	// +kubebuilder:validation:MaxItems:=5
+	// +optional
+	FallbackNodeClassRefs []NodeClassReference `json:"fallbackNodeClassRefs,omitempty" hash:"ignore"`
+	// RegistrationTTL is the duration the controller will wait for the node of the NodeClaim to register, measured from
+	// when the NodeClaim's Registered status condition last transitioned, which is shortly after the NodeClaim is created.
+	// NodeClaims whose node hasn't registered within the TTL are deleted and replaced.
+	// If left undefined, the controller will wait 15 minutes for the node to register.
+	// +kubebuilder:validation:Pattern=`^([0-9]+(s|m|h))+$`
+	// +kubebuilder:validation:Type="string"
+	// +optional
+	RegistrationTTL *metav1.Duration `json:"registrationTTL,omitempty" hash:"ignore"`
+	// InitializationTTL is the duration the controller will wait for the node of the NodeClaim to initialize, measured
+	// from when the node registers. NodeClaims whose node hasn't initialized within the TTL are deleted and replaced.
+	// If left undefined, the controller will wait indefinitely for the node to initialize.
+	// +kubebuilder:validation:Pattern=`^([0-9]+(s|m|h))+$`
+	// +kubebuilder:validation:Type="string"
+	// +optional
+	InitializationTTL *metav1.Duration `json:"initializationTTL,omitempty" hash:"ignore"`
 	// TerminationGracePeriod is the maximum duration the controller will wait before forcefully deleting the pods on a node, measured from when deletion is first initiated.
 	//
 	// Warning: this feature takes precedence over a Pod's terminationGracePeriodSeconds value, and bypasses any blocked PDBs or the karpenter.sh/do-not-disrupt annotation.
@@ -211,22 +477,80 @@ type NodeClaimTemplateSpec struct {
 	// +kubebuilder:validation:Schemaless
 	// +optional
 	ExpireAfter NillableDuration `json:"expireAfter,omitempty"`
+	// ExpireAfterJitter is the maximum duration that is added to the ExpireAfter of each NodeClaim, so that NodeClaims
+	// that are created together don't all expire together. Each NodeClaim expires up to this much later, by an amount
+	// that is derived from its UID.
+	// +kubebuilder:validation:Pattern=`^([0-9]+(s|m|h))+$`
+	// +kubebuilder:validation:Type="string"
+	// +optional
+	ExpireAfterJitter *metav1.Duration `json:"expireAfterJitter,omitempty" hash:"ignore"`
+	// MaxLifetime is the hard limit on how long the node lives, measured from when the node is created. Unlike
+	// ExpireAfter, when it's reached the node is drained without waiting for PDBs or the karpenter.sh/do-not-disrupt
+	// annotation, as if its TerminationGracePeriod had elapsed. This is for compliance policies that require nodes to be
+	// recycled within a fixed period, and is typically set somewhat longer than ExpireAfter so that nodes are recycled
+	// gracefully when possible. If unset, nodes have no hard lifetime limit.
+	// +kubebuilder:validation:Pattern=`^([0-9]+(s|m|h))+$`
+	// +kubebuilder:validation:Type="string"
+	// +optional
+	MaxLifetime *metav1.Duration `json:"maxLifetime,omitempty"`
+	// Kubelet defines args to be used when configuring kubelet on provisioned nodes.
+	// Karpenter accounts for these values when computing the allocatable resources of an instance type.
+	// +optional
+	Kubelet *KubeletConfiguration `json:"kubelet,omitempty"`
+	// MetadataSyncPolicy controls whether changes to the labels and annotations of the NodeClaim after its Node registers
+	// are synced onto the Node. With "OnRegistration", the default, they're only synced when the Node registers. With
+	// "Always", labels and annotations that are added to or changed on the NodeClaim are also synced onto the Node, without
+	// replacing it. Labels and annotations that are removed from the NodeClaim aren't removed from the Node.
+	// +kubebuilder:validation:Enum:={OnRegistration,Always}
+	// +optional
+	MetadataSyncPolicy MetadataSyncPolicy `json:"metadataSyncPolicy,omitempty" hash:"ignore"`
+	// InstanceRetentionPolicy controls what happens to the instance of the NodeClaim when the NodeClaim is deleted. With
+	// "Delete", the default, the instance is terminated. With "Retain", the node is drained and removed from the cluster,
+	// but the instance is left running, e.g. for debugging. Retained instances are no longer tracked by Karpenter, and
+	// are left out of the garbage collection of orphaned instances.
+	// +kubebuilder:validation:Enum:={Delete,Retain}
+	// +optional
+	InstanceRetentionPolicy InstanceRetentionPolicy `json:"instanceRetentionPolicy,omitempty" hash:"ignore"`
+}
+
+// NodeClassRefs returns the NodeClassRef followed by the FallbackNodeClassRefs, in priority order
+func (in *NodeClaimTemplateSpec) NodeClassRefs() []*NodeClassReference {
+	refs := []*NodeClassReference{in.NodeClassRef}
+	for i := range in.FallbackNodeClassRefs {
+		refs = append(refs, &in.FallbackNodeClassRefs[i])
+	}
+	return refs
 }
 
 // This is used to convert between the NodeClaim's NodeClaimSpec to the Nodepool NodeClaimTemplate's NodeClaimSpec.
 func (in *NodeClaimTemplate) ToNodeClaim() *NodeClaim {
+	annotations := in.ObjectMeta.Annotations
+	if len(in.ObjectMeta.Finalizers) > 0 {
+		// the finalizers are recorded so that Karpenter can tell them apart from finalizers added by other means
+		annotations = lo.Assign(annotations, map[string]string{TerminationFinalizersAnnotationKey: strings.Join(in.ObjectMeta.Finalizers, ",")})
+	}
 	return &NodeClaim{
 		ObjectMeta: metav1.ObjectMeta{
 			Labels:      in.ObjectMeta.Labels,
-			Annotations: in.ObjectMeta.Annotations,
+			Annotations: annotations,
+			Finalizers:  in.ObjectMeta.Finalizers,
 		},
 		Spec: NodeClaimSpec{
-			Taints:                 in.Spec.Taints,
-			StartupTaints:          in.Spec.StartupTaints,
-			Requirements:           in.Spec.Requirements,
-			NodeClassRef:           in.Spec.NodeClassRef,
-			TerminationGracePeriod: in.Spec.TerminationGracePeriod,
-			ExpireAfter:            in.Spec.ExpireAfter,
+			Taints:                  in.Spec.Taints,
+			StartupTaints:           in.Spec.StartupTaints,
+			StartupTaintTimeouts:    in.Spec.StartupTaintTimeouts,
+			ReadinessGates:          in.Spec.ReadinessGates,
+			Requirements:            in.Spec.Requirements,
+			NodeClassRef:            in.Spec.NodeClassRef,
+			RegistrationTTL:         in.Spec.RegistrationTTL,
+			InitializationTTL:       in.Spec.InitializationTTL,
+			TerminationGracePeriod:  in.Spec.TerminationGracePeriod,
+			ExpireAfter:             in.Spec.ExpireAfter,
+			ExpireAfterJitter:       in.Spec.ExpireAfterJitter,
+			MaxLifetime:             in.Spec.MaxLifetime,
+			Kubelet:                 in.Spec.Kubelet,
+			MetadataSyncPolicy:      in.Spec.MetadataSyncPolicy,
+			InstanceRetentionPolicy: in.Spec.InstanceRetentionPolicy,
 		},
 	}
 }
@@ -245,6 +569,17 @@ type ObjectMeta struct {
 	// More info: https://kubernetes.io/docs/concepts/overview/working-with-objects/annotations
 	// +optional
 	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Finalizers are added to the NodeClaims that are created from this template, so that external controllers can
+	// gate the termination of their instances. When a NodeClaim is deleted, its node is drained first, as usual.
+	// Karpenter then waits for these finalizers to be removed before it terminates the instance and removes its own
+	// karpenter.sh/termination finalizers from the node and the NodeClaim. The finalizers are recorded in the
+	// karpenter.sh/termination-finalizers annotation of the NodeClaims, so that other finalizers on the NodeClaims don't
+	// block instance termination.
+	// +kubebuilder:validation:XValidation:message="finalizers may not contain the karpenter.sh/termination finalizer",rule="self.all(x, x != 'karpenter.sh/termination')"
+	// +kubebuilder:validation:MaxItems:=10
+	// +optional
+	Finalizers []string `json:"finalizers,omitempty" hash:"ignore"`
 }
 
 // NodePool is the Schema for the NodePools API
@@ -258,11 +593,18 @@ type ObjectMeta struct {
 // +kubebuilder:printcolumn:name="Weight",type="integer",JSONPath=".spec.weight",priority=1,description=""
 // +kubebuilder:printcolumn:name="CPU",type="string",JSONPath=".status.resources.cpu",priority=1,description=""
 // +kubebuilder:printcolumn:name="Memory",type="string",JSONPath=".status.resources.memory",priority=1,description=""
+// +kubebuilder:printcolumn:name="Last Provisioned",type="date",JSONPath=".status.lastProvisioningTime",priority=1,description=""
+// +kubebuilder:printcolumn:name="Last Disrupted",type="date",JSONPath=".status.lastDisruptionTime",priority=1,description=""
 // +kubebuilder:subresource:status
+// +kubebuilder:subresource:scale:specpath=.spec.replicas,statuspath=.status.replicas,selectorpath=.status.selector
 type NodePool struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
+	// +kubebuilder:validation:XValidation:message="replicas cannot be added to or removed from an existing NodePool",rule="has(self.replicas) == has(oldSelf.replicas)"
+	// +kubebuilder:validation:XValidation:message="headroom cannot be set on a NodePool with replicas",rule="!has(self.replicas) || !has(self.headroom)"
+	// +kubebuilder:validation:XValidation:message="minNodesPerZone cannot be set on a NodePool with replicas",rule="!has(self.replicas) || !has(self.minNodesPerZone)"
+	// +kubebuilder:validation:XValidation:message="minNodes cannot be set on a NodePool with replicas",rule="!has(self.replicas) || !has(self.minNodes)"
 	// +required
 	Spec   NodePoolSpec   `json:"spec"`
 	Status NodePoolStatus `json:"status,omitempty"`
@@ -282,6 +624,40 @@ func (in *NodePool) Hash() string {
 	})))
 }
 
+// IsStatic returns true if the NodePool maintains a fixed number of nodes rather than provisioning for pending pods
+func (in *NodePool) IsStatic() bool {
+	return in.Spec.Replicas != nil
+}
+
+// IsPaused returns true if the NodePool doesn't launch nodes or voluntarily disrupt its nodes
+func (in *NodePool) IsPaused() bool {
+	return in.Spec.Paused
+}
+
+// ActiveNodeClassRef returns the NodeClass that new NodeClaims for the NodePool are launched with. This is the NodeClass
+// in the status if it's still one of the template's NodeClasses, and otherwise the template's NodeClassRef.
+func (in *NodePool) ActiveNodeClassRef() *NodeClassReference {
+	if in.Status.NodeClassRef != nil && lo.ContainsBy(in.Spec.Template.Spec.NodeClassRefs(), func(ref *NodeClassReference) bool {
+		return *ref == *in.Status.NodeClassRef
+	}) {
+		return in.Status.NodeClassRef
+	}
+	return in.Spec.Template.Spec.NodeClassRef
+}
+
+// WithActiveNodeClassRef returns the NodePool with its template's NodeClassRef set to the active NodeClass, so that
+// the instance types that the cloudprovider resolves for it are those of the NodeClass that NodeClaims are launched
+// with. The NodePool is copied if the active NodeClass isn't the template's NodeClassRef.
+func (in *NodePool) WithActiveNodeClassRef() *NodePool {
+	active := in.ActiveNodeClassRef()
+	if active == nil || in.Spec.Template.Spec.NodeClassRef == nil || *active == *in.Spec.Template.Spec.NodeClassRef {
+		return in
+	}
+	nodePool := in.DeepCopy()
+	nodePool.Spec.Template.Spec.NodeClassRef = active.DeepCopy()
+	return nodePool
+}
+
 // NodePoolList contains a list of NodePool
 // +kubebuilder:object:root=true
 type NodePoolList struct {
@@ -19,6 +19,7 @@ package v1
 import (
 	"github.com/awslabs/operatorpkg/status"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const (
@@ -27,6 +28,9 @@ const (
 	ConditionTypeValidationSucceeded = "ValidationSucceeded"
 	// ConditionTypeNodeClassReady = "NodeClassReady" condition indicates that underlying nodeClass was resolved and is reporting as Ready
 	ConditionTypeNodeClassReady = "NodeClassReady"
+	// ConditionTypeDegraded = "Degraded" condition indicates that the NodePool's NodeClaims are repeatedly failing to
+	// launch. Its reason is the class of the launch failures. Degraded NodePools are deprioritized by the scheduler.
+	ConditionTypeDegraded = "Degraded"
 )
 
 // NodePoolStatus defines the observed state of NodePool
@@ -34,9 +38,58 @@ type NodePoolStatus struct {
 	// Resources is the list of resources that have been provisioned.
 	// +optional
 	Resources v1.ResourceList `json:"resources,omitempty"`
+	// CapacityTypeResources is the list of resources that have been provisioned, by capacity type
+	// +optional
+	CapacityTypeResources map[string]v1.ResourceList `json:"capacityTypeResources,omitempty"`
+	// RemainingNodes is the number of nodes that can still be launched before the NodePool reaches the nodes resource
+	// of its limits. The current number of nodes is the nodes resource of the NodePool's resources.
+	// +optional
+	RemainingNodes *int64 `json:"remainingNodes,omitempty"`
+	// Allocatable is the sum of the allocatable resources of the NodePool's nodes
+	// +optional
+	Allocatable v1.ResourceList `json:"allocatable,omitempty"`
+	// Requested is the sum of the resources requested by the pods that are running on the NodePool's nodes
+	// +optional
+	Requested v1.ResourceList `json:"requested,omitempty"`
+	// LastProvisioningTime is when the NodePool last launched a NodeClaim
+	// +optional
+	LastProvisioningTime *metav1.Time `json:"lastProvisioningTime,omitempty"`
+	// LastDisruptionTime is when one of the NodePool's NodeClaims was last disrupted
+	// +optional
+	LastDisruptionTime *metav1.Time `json:"lastDisruptionTime,omitempty"`
+	// AllowedDisruptions is the number of the NodePool's nodes that its disruption budgets currently allow to be
+	// disrupted, by disruption reason. Nodes that are NotReady or already being disrupted count against the budgets.
+	// +optional
+	AllowedDisruptions map[DisruptionReason]int64 `json:"allowedDisruptions,omitempty"`
 	// Conditions contains signals for health and readiness
 	// +optional
 	Conditions []status.Condition `json:"conditions,omitempty"`
+	// Replicas is the number of the NodePool's nodes. It's reported by the NodePool's scale subresource, which scales a
+	// static NodePool by setting its replicas.
+	// +optional
+	Replicas int64 `json:"replicas,omitempty"`
+	// Selector is the label selector of the NodePool's nodes, in the string form that the scale subresource reports
+	// +optional
+	Selector string `json:"selector,omitempty"`
+	// NodeClassRef is the NodeClass that new NodeClaims are launched with. It's the first of the template's nodeClassRef
+	// and fallbackNodeClassRefs that is ready and isn't repeatedly failing to launch NodeClaims.
+	// +optional
+	NodeClassRef *NodeClassReference `json:"nodeClassRef,omitempty"`
+	// LaunchBackoff is how long the NodePool stays Degraded, and so deprioritized, after its NodeClaims were last
+	// repeatedly failing to launch. It's cleared once the NodePool is no longer Degraded.
+	// +optional
+	LaunchBackoff *LaunchBackoff `json:"launchBackoff,omitempty"`
+}
+
+// LaunchBackoff describes how long a Degraded NodePool is deprioritized for
+type LaunchBackoff struct {
+	// Duration is the current backoff. It doubles each time it elapses while the NodePool's NodeClaims are still
+	// repeatedly failing to launch.
+	// +required
+	Duration metav1.Duration `json:"duration"`
+	// Until is when the current backoff elapses
+	// +required
+	Until metav1.Time `json:"until"`
 }
 
 func (in *NodePool) StatusConditions() status.ConditionSet {
@@ -20,12 +20,13 @@ import (
 	"fmt"
 
 	"go.uber.org/multierr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 // RuntimeValidate will be used to validate any part of the CRD that can not be validated at CRD creation
 func (in *NodePool) RuntimeValidate() (errs error) {
-	errs = multierr.Combine(in.Spec.Template.validateLabels(), in.Spec.Template.Spec.validateTaints(), in.Spec.Template.Spec.validateRequirements(), in.Spec.Template.validateRequirementsNodePoolKeyDoesNotExist())
+	errs = multierr.Combine(in.Spec.Template.validateLabels(), in.Spec.Template.Spec.validateTaints(), in.Spec.Template.Spec.validateLabelTemplates(), in.Spec.Template.Spec.validateRequirements(), in.Spec.Template.validateRequirementsNodePoolKeyDoesNotExist(), in.Spec.validateTopologyDomains(), in.Spec.validateExcludedDaemonSets(), in.Spec.validateAllowedNamespaces())
 	return errs
 }
 
@@ -55,3 +56,39 @@ func (in *NodeClaimTemplate) validateRequirementsNodePoolKeyDoesNotExist() (errs
 	}
 	return errs
 }
+
+func (in *NodePoolSpec) validateTopologyDomains() (errs error) {
+	for key, values := range in.TopologyDomains {
+		for _, err := range validation.IsQualifiedName(key) {
+			errs = multierr.Append(errs, fmt.Errorf("invalid key name %q in topologyDomains, %q", key, err))
+		}
+		if err := IsRestrictedLabel(key); err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("invalid key name %q in topologyDomains, %s", key, err.Error()))
+		}
+		for _, value := range values {
+			for _, err := range validation.IsValidLabelValue(value) {
+				errs = multierr.Append(errs, fmt.Errorf("invalid value: %s for topologyDomains[%s], %s", value, key, err))
+			}
+		}
+	}
+	return errs
+}
+
+func (in *NodePoolSpec) validateExcludedDaemonSets() error {
+	if in.ExcludedDaemonSets == nil {
+		return nil
+	}
+	if _, err := metav1.LabelSelectorAsSelector(in.ExcludedDaemonSets); err != nil {
+		return fmt.Errorf("invalid excludedDaemonSets, %w", err)
+	}
+	return nil
+}
+
+func (in *NodePoolSpec) validateAllowedNamespaces() (errs error) {
+	for _, namespace := range in.AllowedNamespaces {
+		for _, err := range validation.IsDNS1123Label(namespace) {
+			errs = multierr.Append(errs, fmt.Errorf("invalid namespace %q in allowedNamespaces, %s", namespace, err))
+		}
+	}
+	return errs
+}
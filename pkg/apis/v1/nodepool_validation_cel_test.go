@@ -27,6 +27,7 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/samber/lo"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 
@@ -109,6 +110,26 @@ var _ = Describe("CEL/Validation", func() {
 			nodePool.Spec.Disruption.ConsolidationPolicy = ConsolidationPolicyWhenEmpty
 			Expect(env.Client.Create(ctx, nodePool)).To(Succeed())
 		})
+		It("should succeed when overriding consolidation for a capacity type", func() {
+			nodePool.Spec.Disruption.CapacityTypes = []CapacityTypeDisruption{
+				{CapacityType: CapacityTypeOnDemand, ConsolidateAfter: lo.ToPtr(MustParseNillableDuration("30s"))},
+				{CapacityType: CapacityTypeSpot, ConsolidationPolicy: ConsolidationPolicyWhenEmpty},
+			}
+			Expect(env.Client.Create(ctx, nodePool)).To(Succeed())
+		})
+		It("should fail when overriding consolidation for the same capacity type twice", func() {
+			nodePool.Spec.Disruption.CapacityTypes = []CapacityTypeDisruption{
+				{CapacityType: CapacityTypeSpot, ConsolidateAfter: lo.ToPtr(MustParseNillableDuration("Never"))},
+				{CapacityType: CapacityTypeSpot, ConsolidationPolicy: ConsolidationPolicyWhenEmpty},
+			}
+			Expect(env.Client.Create(ctx, nodePool)).ToNot(Succeed())
+		})
+		It("should fail on a negative consolidateAfter for a capacity type", func() {
+			nodePool.Spec.Disruption.CapacityTypes = []CapacityTypeDisruption{
+				{CapacityType: CapacityTypeSpot, ConsolidateAfter: lo.ToPtr(MustParseNillableDuration("-1s"))},
+			}
+			Expect(env.Client.Create(ctx, nodePool)).ToNot(Succeed())
+		})
 		It("should fail when creating a budget with an invalid cron", func() {
 			nodePool.Spec.Disruption.Budgets = []Budget{{
 				Nodes:    "10",
@@ -610,6 +631,37 @@ var _ = Describe("CEL/Validation", func() {
 			}
 		})
 	})
+	Context("Templates", func() {
+		It("should succeed for valid label and taint templates", func() {
+			nodePool.Spec.Template.Spec.LabelTemplates = map[string]string{
+				"example.com/instance-type": "{{ .InstanceType.Name }}",
+				"example.com/team":          `{{ index .Labels "team" }}`,
+			}
+			nodePool.Spec.Template.Spec.TaintTemplates = []v1.Taint{
+				{Key: "example.com/zone", Value: "{{ .Zone }}", Effect: v1.TaintEffectNoSchedule},
+			}
+			Expect(env.Client.Create(ctx, nodePool)).To(Succeed())
+			Expect(nodePool.RuntimeValidate()).To(Succeed())
+		})
+		It("should fail for templates that don't parse", func() {
+			nodePool.Spec.Template.Spec.LabelTemplates = map[string]string{"example.com/zone": "{{ .Zone "}
+			Expect(nodePool.RuntimeValidate()).ToNot(Succeed())
+			nodePool.Spec.Template.Spec.LabelTemplates = nil
+			nodePool.Spec.Template.Spec.TaintTemplates = []v1.Taint{{Key: "example.com/zone", Value: "{{ .Zone ", Effect: v1.TaintEffectNoSchedule}}
+			Expect(nodePool.RuntimeValidate()).ToNot(Succeed())
+		})
+		It("should fail for restricted label template keys", func() {
+			nodePool.Spec.Template.Spec.LabelTemplates = map[string]string{NodePoolLabelKey: "{{ .Zone }}"}
+			Expect(nodePool.RuntimeValidate()).ToNot(Succeed())
+			nodePool.Spec.Template.Spec.LabelTemplates = map[string]string{v1.LabelTopologyZone: "{{ .Zone }}"}
+			Expect(nodePool.RuntimeValidate()).ToNot(Succeed())
+		})
+		It("should fail for taint templates that duplicate a taint", func() {
+			nodePool.Spec.Template.Spec.Taints = []v1.Taint{{Key: "example.com/zone", Value: "a", Effect: v1.TaintEffectNoSchedule}}
+			nodePool.Spec.Template.Spec.TaintTemplates = []v1.Taint{{Key: "example.com/zone", Value: "{{ .Zone }}", Effect: v1.TaintEffectNoSchedule}}
+			Expect(nodePool.RuntimeValidate()).ToNot(Succeed())
+		})
+	})
 	Context("TerminationGracePeriod", func() {
 		It("should succeed on a positive terminationGracePeriod duration", func() {
 			nodePool.Spec.Template.Spec.TerminationGracePeriod = &metav1.Duration{Duration: time.Second * 300}
@@ -620,6 +672,227 @@ var _ = Describe("CEL/Validation", func() {
 			Expect(env.Client.Create(ctx, nodePool)).ToNot(Succeed())
 		})
 	})
+	Context("Replicas", func() {
+		It("should succeed on a positive replicas value", func() {
+			nodePool.Spec.Replicas = lo.ToPtr[int64](3)
+			Expect(env.Client.Create(ctx, nodePool)).To(Succeed())
+		})
+		It("should fail on a negative replicas value", func() {
+			nodePool.Spec.Replicas = lo.ToPtr[int64](-1)
+			Expect(env.Client.Create(ctx, nodePool)).ToNot(Succeed())
+		})
+		It("should succeed when updating replicas", func() {
+			nodePool.Spec.Replicas = lo.ToPtr[int64](3)
+			Expect(env.Client.Create(ctx, nodePool)).To(Succeed())
+			nodePool.Spec.Replicas = lo.ToPtr[int64](5)
+			Expect(env.Client.Update(ctx, nodePool)).To(Succeed())
+		})
+		It("should fail when adding replicas to an existing nodepool", func() {
+			Expect(env.Client.Create(ctx, nodePool)).To(Succeed())
+			nodePool.Spec.Replicas = lo.ToPtr[int64](3)
+			Expect(env.Client.Update(ctx, nodePool)).ToNot(Succeed())
+		})
+		It("should fail when removing replicas from an existing nodepool", func() {
+			nodePool.Spec.Replicas = lo.ToPtr[int64](3)
+			Expect(env.Client.Create(ctx, nodePool)).To(Succeed())
+			nodePool.Spec.Replicas = nil
+			Expect(env.Client.Update(ctx, nodePool)).ToNot(Succeed())
+		})
+	})
+	Context("Kubelet", func() {
+		It("should succeed on a valid kubelet configuration", func() {
+			nodePool.Spec.Template.Spec.Kubelet = &KubeletConfiguration{
+				MaxPods:        lo.ToPtr[int32](110),
+				SystemReserved: map[string]string{"cpu": "100m", "memory": "100Mi", "pid": "100"},
+				KubeReserved:   map[string]string{"cpu": "100m", "ephemeral-storage": "1Gi"},
+				EvictionHard:   map[string]string{"memory.available": "5%", "nodefs.available": "10%"},
+			}
+			Expect(env.Client.Create(ctx, nodePool)).To(Succeed())
+		})
+		It("should fail on a negative maxPods", func() {
+			nodePool.Spec.Template.Spec.Kubelet = &KubeletConfiguration{MaxPods: lo.ToPtr[int32](-1)}
+			Expect(env.Client.Create(ctx, nodePool)).ToNot(Succeed())
+		})
+		It("should fail on an invalid systemReserved key", func() {
+			nodePool.Spec.Template.Spec.Kubelet = &KubeletConfiguration{SystemReserved: map[string]string{"gpu": "1"}}
+			Expect(env.Client.Create(ctx, nodePool)).ToNot(Succeed())
+		})
+		It("should fail on a negative kubeReserved value", func() {
+			nodePool.Spec.Template.Spec.Kubelet = &KubeletConfiguration{KubeReserved: map[string]string{"cpu": "-1"}}
+			Expect(env.Client.Create(ctx, nodePool)).ToNot(Succeed())
+		})
+		It("should fail on an invalid evictionHard signal", func() {
+			nodePool.Spec.Template.Spec.Kubelet = &KubeletConfiguration{EvictionHard: map[string]string{"memory": "5%"}}
+			Expect(env.Client.Create(ctx, nodePool)).ToNot(Succeed())
+		})
+	})
+	Context("Headroom", func() {
+		It("should succeed when setting headroom", func() {
+			nodePool.Spec.Headroom = v1.ResourceList{v1.ResourceCPU: resource.MustParse("20"), v1.ResourceMemory: resource.MustParse("64Gi")}
+			Expect(env.Client.Create(ctx, nodePool)).To(Succeed())
+		})
+		It("should fail when setting headroom on a nodepool with replicas", func() {
+			nodePool.Spec.Replicas = lo.ToPtr[int64](3)
+			nodePool.Spec.Headroom = v1.ResourceList{v1.ResourceCPU: resource.MustParse("20")}
+			Expect(env.Client.Create(ctx, nodePool)).ToNot(Succeed())
+		})
+	})
+	Context("MinNodes", func() {
+		It("should succeed when setting minNodes", func() {
+			nodePool.Spec.MinNodes = lo.ToPtr[int32](2)
+			Expect(env.Client.Create(ctx, nodePool)).To(Succeed())
+		})
+		It("should fail on a minNodes of zero", func() {
+			nodePool.Spec.MinNodes = lo.ToPtr[int32](0)
+			Expect(env.Client.Create(ctx, nodePool)).ToNot(Succeed())
+		})
+		It("should fail when setting minNodes on a nodepool with replicas", func() {
+			nodePool.Spec.Replicas = lo.ToPtr[int64](3)
+			nodePool.Spec.MinNodes = lo.ToPtr[int32](1)
+			Expect(env.Client.Create(ctx, nodePool)).ToNot(Succeed())
+		})
+	})
+	Context("MinNodesPerZone", func() {
+		It("should succeed when setting minNodesPerZone", func() {
+			nodePool.Spec.MinNodesPerZone = lo.ToPtr[int32](2)
+			Expect(env.Client.Create(ctx, nodePool)).To(Succeed())
+		})
+		It("should fail on a minNodesPerZone of zero", func() {
+			nodePool.Spec.MinNodesPerZone = lo.ToPtr[int32](0)
+			Expect(env.Client.Create(ctx, nodePool)).ToNot(Succeed())
+		})
+		It("should fail when setting minNodesPerZone on a nodepool with replicas", func() {
+			nodePool.Spec.Replicas = lo.ToPtr[int64](3)
+			nodePool.Spec.MinNodesPerZone = lo.ToPtr[int32](1)
+			Expect(env.Client.Create(ctx, nodePool)).ToNot(Succeed())
+		})
+	})
+	Context("CapacityTypeSplit", func() {
+		It("should succeed when the percentages add up to 100", func() {
+			nodePool.Spec.CapacityTypeSplit = map[string]int32{CapacityTypeSpot: 70, CapacityTypeOnDemand: 30}
+			Expect(env.Client.Create(ctx, nodePool)).To(Succeed())
+		})
+		It("should fail when the percentages don't add up to 100", func() {
+			nodePool.Spec.CapacityTypeSplit = map[string]int32{CapacityTypeSpot: 70, CapacityTypeOnDemand: 20}
+			Expect(env.Client.Create(ctx, nodePool)).ToNot(Succeed())
+		})
+		It("should fail on a negative percentage", func() {
+			nodePool.Spec.CapacityTypeSplit = map[string]int32{CapacityTypeSpot: 110, CapacityTypeOnDemand: -10}
+			Expect(env.Client.Create(ctx, nodePool)).ToNot(Succeed())
+		})
+	})
+	Context("MaxPricePercentile", func() {
+		It("should succeed when the percentile is between 1 and 100", func() {
+			nodePool.Spec.MaxPricePercentile = lo.ToPtr[int32](50)
+			Expect(env.Client.Create(ctx, nodePool)).To(Succeed())
+		})
+		It("should fail when the percentile is 0", func() {
+			nodePool.Spec.MaxPricePercentile = lo.ToPtr[int32](0)
+			Expect(env.Client.Create(ctx, nodePool)).ToNot(Succeed())
+		})
+		It("should fail when the percentile is above 100", func() {
+			nodePool.Spec.MaxPricePercentile = lo.ToPtr[int32](101)
+			Expect(env.Client.Create(ctx, nodePool)).ToNot(Succeed())
+		})
+	})
+	Context("TopologyDomains", func() {
+		It("should succeed when each key has values", func() {
+			nodePool.Spec.TopologyDomains = map[string][]string{"example.com/rack": {"rack-a", "rack-b"}}
+			Expect(env.Client.Create(ctx, nodePool)).To(Succeed())
+		})
+		It("should fail when a key has no values", func() {
+			nodePool.Spec.TopologyDomains = map[string][]string{"example.com/rack": {}}
+			Expect(env.Client.Create(ctx, nodePool)).ToNot(Succeed())
+		})
+		It("should fail at runtime for restricted or invalid keys and values", func() {
+			nodePool.Spec.TopologyDomains = map[string][]string{"example.com/rack": {"rack-a"}}
+			Expect(nodePool.RuntimeValidate()).To(Succeed())
+			nodePool.Spec.TopologyDomains = map[string][]string{"karpenter.sh/rack": {"rack-a"}}
+			Expect(nodePool.RuntimeValidate()).ToNot(Succeed())
+			nodePool.Spec.TopologyDomains = map[string][]string{"example.com/rack/a": {"rack-a"}}
+			Expect(nodePool.RuntimeValidate()).ToNot(Succeed())
+			nodePool.Spec.TopologyDomains = map[string][]string{"example.com/rack": {"rack a"}}
+			Expect(nodePool.RuntimeValidate()).ToNot(Succeed())
+		})
+	})
+	Context("ExcludedDaemonSets", func() {
+		It("should succeed when setting a selector", func() {
+			nodePool.Spec.ExcludedDaemonSets = &metav1.LabelSelector{MatchLabels: map[string]string{"app": "gpu-driver"}}
+			Expect(env.Client.Create(ctx, nodePool)).To(Succeed())
+			Expect(nodePool.RuntimeValidate()).To(Succeed())
+		})
+		It("should fail at runtime for invalid selectors", func() {
+			nodePool.Spec.ExcludedDaemonSets = &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "app", Operator: metav1.LabelSelectorOpIn},
+			}}
+			Expect(nodePool.RuntimeValidate()).ToNot(Succeed())
+		})
+	})
+	Context("AllowedNamespaces", func() {
+		It("should succeed when listing namespaces", func() {
+			nodePool.Spec.AllowedNamespaces = []string{"team-a", "team-b"}
+			Expect(env.Client.Create(ctx, nodePool)).To(Succeed())
+			Expect(nodePool.RuntimeValidate()).To(Succeed())
+		})
+		It("should fail when the list is empty", func() {
+			nodePool.Spec.AllowedNamespaces = []string{}
+			Expect(env.Client.Create(ctx, nodePool)).ToNot(Succeed())
+		})
+		It("should fail at runtime for invalid namespace names", func() {
+			nodePool.Spec.AllowedNamespaces = []string{"Team_A"}
+			Expect(nodePool.RuntimeValidate()).ToNot(Succeed())
+		})
+	})
+	Context("ExistingCapacityWindow", func() {
+		It("should succeed when setting a window", func() {
+			nodePool.Spec.ExistingCapacityWindow = &metav1.Duration{Duration: 90 * time.Second}
+			Expect(env.Client.Create(ctx, nodePool)).To(Succeed())
+		})
+		It("should fail on a negative window", func() {
+			nodePool.Spec.ExistingCapacityWindow = &metav1.Duration{Duration: -time.Minute}
+			Expect(env.Client.Create(ctx, nodePool)).ToNot(Succeed())
+		})
+	})
+	Context("DomainLimits", func() {
+		It("should succeed on a well known topology key", func() {
+			nodePool.Spec.DomainLimits = map[string]Limits{v1.LabelTopologyZone: Limits(v1.ResourceList{v1.ResourceCPU: resource.MustParse("100")})}
+			Expect(env.Client.Create(ctx, nodePool)).To(Succeed())
+		})
+		It("should fail on a topology key that isn't well known", func() {
+			nodePool.Spec.DomainLimits = map[string]Limits{"example.com/rack": Limits(v1.ResourceList{v1.ResourceCPU: resource.MustParse("100")})}
+			Expect(env.Client.Create(ctx, nodePool)).ToNot(Succeed())
+		})
+	})
+	Context("InstanceTypeTruncation", func() {
+		It("should succeed when setting maxInstanceTypes without a strategy", func() {
+			nodePool.Spec.InstanceTypeTruncation = &InstanceTypeTruncation{MaxInstanceTypes: lo.ToPtr[int32](20)}
+			Expect(env.Client.Create(ctx, nodePool)).To(Succeed())
+		})
+		It("should succeed when setting the price percentile strategy with a pricePercentile", func() {
+			nodePool.Spec.InstanceTypeTruncation = &InstanceTypeTruncation{Strategy: InstanceTypeTruncationStrategyPricePercentile, PricePercentile: lo.ToPtr[int32](25)}
+			Expect(env.Client.Create(ctx, nodePool)).To(Succeed())
+		})
+		It("should fail when setting the price percentile strategy without a pricePercentile", func() {
+			nodePool.Spec.InstanceTypeTruncation = &InstanceTypeTruncation{Strategy: InstanceTypeTruncationStrategyPricePercentile}
+			Expect(env.Client.Create(ctx, nodePool)).ToNot(Succeed())
+		})
+		It("should fail when setting a pricePercentile with a different strategy", func() {
+			nodePool.Spec.InstanceTypeTruncation = &InstanceTypeTruncation{Strategy: InstanceTypeTruncationStrategyDiverse, PricePercentile: lo.ToPtr[int32](25)}
+			Expect(env.Client.Create(ctx, nodePool)).ToNot(Succeed())
+		})
+		It("should fail on a pricePercentile above 100", func() {
+			nodePool.Spec.InstanceTypeTruncation = &InstanceTypeTruncation{Strategy: InstanceTypeTruncationStrategyPricePercentile, PricePercentile: lo.ToPtr[int32](101)}
+			Expect(env.Client.Create(ctx, nodePool)).ToNot(Succeed())
+		})
+		It("should fail on a maxInstanceTypes of zero", func() {
+			nodePool.Spec.InstanceTypeTruncation = &InstanceTypeTruncation{MaxInstanceTypes: lo.ToPtr[int32](0)}
+			Expect(env.Client.Create(ctx, nodePool)).ToNot(Succeed())
+		})
+		It("should fail on a maxInstanceTypes above 60", func() {
+			nodePool.Spec.InstanceTypeTruncation = &InstanceTypeTruncation{MaxInstanceTypes: lo.ToPtr[int32](61)}
+			Expect(env.Client.Create(ctx, nodePool)).ToNot(Succeed())
+		})
+	})
 	Context("NodeClassRef", func() {
 		It("should fail to mutate group", func() {
 			Expect(env.Client.Create(ctx, nodePool)).To(Succeed())
@@ -644,4 +917,22 @@ var _ = Describe("CEL/Validation", func() {
 			Expect(env.Client.Create(ctx, nodePool)).ToNot(Succeed())
 		})
 	})
+	Context("FallbackNodeClassRefs", func() {
+		It("should succeed if the fallbacks have the same group and kind as the NodeClassRef", func() {
+			nodePool.Spec.Template.Spec.FallbackNodeClassRefs = []NodeClassReference{{Group: "karpenter.test.sh", Kind: "TestNodeClass", Name: "fallback"}}
+			Expect(env.Client.Create(ctx, nodePool)).To(Succeed())
+		})
+		It("should fail if a fallback has a different group", func() {
+			nodePool.Spec.Template.Spec.FallbackNodeClassRefs = []NodeClassReference{{Group: "karpenter.test.other.sh", Kind: "TestNodeClass", Name: "fallback"}}
+			Expect(env.Client.Create(ctx, nodePool)).ToNot(Succeed())
+		})
+		It("should fail if a fallback has a different kind", func() {
+			nodePool.Spec.Template.Spec.FallbackNodeClassRefs = []NodeClassReference{{Group: "karpenter.test.sh", Kind: "TestNodeClass2", Name: "fallback"}}
+			Expect(env.Client.Create(ctx, nodePool)).ToNot(Succeed())
+		})
+		It("should fail if a fallback name is unset", func() {
+			nodePool.Spec.Template.Spec.FallbackNodeClassRefs = []NodeClassReference{{Group: "karpenter.test.sh", Kind: "TestNodeClass"}}
+			Expect(env.Client.Create(ctx, nodePool)).ToNot(Succeed())
+		})
+	})
 })
@@ -0,0 +1,137 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SchedulingSnapshotSpec describes the inputs and decisions of a provisioning scheduling loop
+type SchedulingSnapshotSpec struct {
+	// Time is when the scheduling loop completed
+	// +required
+	Time metav1.Time `json:"time"`
+	// ExistingNodes are the nodes and in-flight NodeClaims that pods were considered for, along with the pods that
+	// were scheduled to them
+	// +optional
+	ExistingNodes []SchedulingSnapshotNode `json:"existingNodes,omitempty"`
+	// NodeClaims are the NodeClaims that were launched for pods that didn't fit on existing nodes
+	// +optional
+	NodeClaims []SchedulingSnapshotNodeClaim `json:"nodeClaims,omitempty"`
+	// TopologyDomains are the domains of each topology key that pods could be spread across
+	// +optional
+	TopologyDomains map[string][]string `json:"topologyDomains,omitempty"`
+	// PodErrors are the pods that couldn't be scheduled, along with why each NodePool rejected them
+	// +optional
+	PodErrors []SchedulingSnapshotPodError `json:"podErrors,omitempty"`
+	// Truncated is true if some of the nodes, NodeClaims, pods or instance types of the scheduling loop were left out,
+	// to keep the snapshot within the size limits of the API server
+	// +optional
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+type SchedulingSnapshotNode struct {
+	// Name is the name of the node, or of the NodeClaim if the node hasn't registered yet
+	// +required
+	Name string `json:"name"`
+	// NodePool is the NodePool that the node was launched for, if any
+	// +optional
+	NodePool string `json:"nodePool,omitempty"`
+	// InstanceType is the instance type of the node, if known
+	// +optional
+	InstanceType string `json:"instanceType,omitempty"`
+	// InFlight is true if the node hasn't initialized yet
+	// +optional
+	InFlight bool `json:"inFlight,omitempty"`
+	// Pods are the namespace/names of the pods that were scheduled to the node
+	// +optional
+	Pods []string `json:"pods,omitempty"`
+}
+
+type SchedulingSnapshotNodeClaim struct {
+	// NodePool is the NodePool that the NodeClaim was launched for
+	// +required
+	NodePool string `json:"nodePool"`
+	// InstanceTypes are the instance types that the NodeClaim could be launched as
+	// +optional
+	InstanceTypes []string `json:"instanceTypes,omitempty"`
+	// Pods are the namespace/names of the pods that were scheduled to the NodeClaim
+	// +optional
+	Pods []string `json:"pods,omitempty"`
+	// RejectedInstanceTypes are the instance types of the NodePool that aren't options for the NodeClaim's pods, along
+	// with why each was rejected
+	// +optional
+	RejectedInstanceTypes []SchedulingSnapshotInstanceTypeRejection `json:"rejectedInstanceTypes,omitempty"`
+}
+
+type SchedulingSnapshotPodError struct {
+	// Pod is the namespace/name of the pod
+	// +required
+	Pod string `json:"pod"`
+	// Error is why the pod couldn't be scheduled
+	// +required
+	Error string `json:"error"`
+	// Failures are why each NodePool couldn't launch a NodeClaim for the pod, e.g. because none of its instance types
+	// had the resources that the pod requested
+	// +optional
+	Failures []SchedulingSnapshotNodePoolFailure `json:"failures,omitempty"`
+}
+
+type SchedulingSnapshotNodePoolFailure struct {
+	// NodePool is the name of the NodePool
+	// +required
+	NodePool string `json:"nodePool"`
+	// Reason is the kind of constraint that the pod didn't satisfy
+	// +required
+	Reason string `json:"reason"`
+	// Details identify the constraint, e.g. requirement keys, taints or resource names
+	// +optional
+	Details []string `json:"details,omitempty"`
+	// RejectedInstanceTypes are the instance types of the NodePool, along with why each couldn't launch a NodeClaim for
+	// the pod. They're only recorded when the pod failed to schedule because of the NodePool's instance types.
+	// +optional
+	RejectedInstanceTypes []SchedulingSnapshotInstanceTypeRejection `json:"rejectedInstanceTypes,omitempty"`
+}
+
+type SchedulingSnapshotInstanceTypeRejection struct {
+	// InstanceType is the name of the instance type
+	// +required
+	InstanceType string `json:"instanceType"`
+	// Reason is the kind of constraint that the instance type didn't satisfy
+	// +required
+	Reason string `json:"reason"`
+}
+
+// SchedulingSnapshot is a debugging record of the latest provisioning scheduling loop. It's only written when
+// scheduling snapshots are enabled.
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=schedulingsnapshots,scope=Cluster,categories=karpenter
+// +kubebuilder:printcolumn:name="Time",type="date",JSONPath=".spec.time",description=""
+type SchedulingSnapshot struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec SchedulingSnapshotSpec `json:"spec,omitempty"`
+}
+
+// SchedulingSnapshotList contains a list of SchedulingSnapshots
+// +kubebuilder:object:root=true
+type SchedulingSnapshotList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SchedulingSnapshot `json:"items"`
+}
@@ -23,6 +23,7 @@ package v1
 import (
 	"github.com/awslabs/operatorpkg/status"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	timex "time"
@@ -58,10 +59,37 @@ func (in *Budget) DeepCopy() *Budget {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CapacityTypeDisruption) DeepCopyInto(out *CapacityTypeDisruption) {
+	*out = *in
+	if in.ConsolidateAfter != nil {
+		in, out := &in.ConsolidateAfter, &out.ConsolidateAfter
+		*out = new(NillableDuration)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CapacityTypeDisruption.
+func (in *CapacityTypeDisruption) DeepCopy() *CapacityTypeDisruption {
+	if in == nil {
+		return nil
+	}
+	out := new(CapacityTypeDisruption)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Disruption) DeepCopyInto(out *Disruption) {
 	*out = *in
 	in.ConsolidateAfter.DeepCopyInto(&out.ConsolidateAfter)
+	if in.CapacityTypes != nil {
+		in, out := &in.CapacityTypes, &out.CapacityTypes
+		*out = make([]CapacityTypeDisruption, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Budgets != nil {
 		in, out := &in.Budgets, &out.Budgets
 		*out = make([]Budget, len(*in))
@@ -81,6 +109,140 @@ func (in *Disruption) DeepCopy() *Disruption {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceTypeTruncation) DeepCopyInto(out *InstanceTypeTruncation) {
+	*out = *in
+	if in.MaxInstanceTypes != nil {
+		in, out := &in.MaxInstanceTypes, &out.MaxInstanceTypes
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PricePercentile != nil {
+		in, out := &in.PricePercentile, &out.PricePercentile
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceTypeTruncation.
+func (in *InstanceTypeTruncation) DeepCopy() *InstanceTypeTruncation {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceTypeTruncation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeletConfiguration) DeepCopyInto(out *KubeletConfiguration) {
+	*out = *in
+	if in.MaxPods != nil {
+		in, out := &in.MaxPods, &out.MaxPods
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SystemReserved != nil {
+		in, out := &in.SystemReserved, &out.SystemReserved
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.KubeReserved != nil {
+		in, out := &in.KubeReserved, &out.KubeReserved
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.EvictionHard != nil {
+		in, out := &in.EvictionHard, &out.EvictionHard
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeletConfiguration.
+func (in *KubeletConfiguration) DeepCopy() *KubeletConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeletConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LaunchBackoff) DeepCopyInto(out *LaunchBackoff) {
+	*out = *in
+	out.Duration = in.Duration
+	in.Until.DeepCopyInto(&out.Until)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LaunchBackoff.
+func (in *LaunchBackoff) DeepCopy() *LaunchBackoff {
+	if in == nil {
+		return nil
+	}
+	out := new(LaunchBackoff)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LaunchFailure) DeepCopyInto(out *LaunchFailure) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LaunchFailure.
+func (in *LaunchFailure) DeepCopy() *LaunchFailure {
+	if in == nil {
+		return nil
+	}
+	out := new(LaunchFailure)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LaunchRate) DeepCopyInto(out *LaunchRate) {
+	*out = *in
+	if in.Burst != nil {
+		in, out := &in.Burst, &out.Burst
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LaunchRate.
+func (in *LaunchRate) DeepCopy() *LaunchRate {
+	if in == nil {
+		return nil
+	}
+	out := new(LaunchRate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LaunchedInstance) DeepCopyInto(out *LaunchedInstance) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LaunchedInstance.
+func (in *LaunchedInstance) DeepCopy() *LaunchedInstance {
+	if in == nil {
+		return nil
+	}
+	out := new(LaunchedInstance)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in Limits) DeepCopyInto(out *Limits) {
 	{
@@ -196,6 +358,20 @@ func (in *NodeClaimSpec) DeepCopyInto(out *NodeClaimSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.TaintTemplates != nil {
+		in, out := &in.TaintTemplates, &out.TaintTemplates
+		*out = make([]corev1.Taint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LabelTemplates != nil {
+		in, out := &in.LabelTemplates, &out.LabelTemplates
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.StartupTaints != nil {
 		in, out := &in.StartupTaints, &out.StartupTaints
 		*out = make([]corev1.Taint, len(*in))
@@ -203,6 +379,18 @@ func (in *NodeClaimSpec) DeepCopyInto(out *NodeClaimSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.StartupTaintTimeouts != nil {
+		in, out := &in.StartupTaintTimeouts, &out.StartupTaintTimeouts
+		*out = make(map[string]metav1.Duration, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ReadinessGates != nil {
+		in, out := &in.ReadinessGates, &out.ReadinessGates
+		*out = make([]NodeReadinessGate, len(*in))
+		copy(*out, *in)
+	}
 	if in.Requirements != nil {
 		in, out := &in.Requirements, &out.Requirements
 		*out = make([]NodeSelectorRequirementWithMinValues, len(*in))
@@ -216,12 +404,37 @@ func (in *NodeClaimSpec) DeepCopyInto(out *NodeClaimSpec) {
 		*out = new(NodeClassReference)
 		**out = **in
 	}
+	if in.RegistrationTTL != nil {
+		in, out := &in.RegistrationTTL, &out.RegistrationTTL
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.InitializationTTL != nil {
+		in, out := &in.InitializationTTL, &out.InitializationTTL
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 	if in.TerminationGracePeriod != nil {
 		in, out := &in.TerminationGracePeriod, &out.TerminationGracePeriod
 		*out = new(metav1.Duration)
 		**out = **in
 	}
 	in.ExpireAfter.DeepCopyInto(&out.ExpireAfter)
+	if in.ExpireAfterJitter != nil {
+		in, out := &in.ExpireAfterJitter, &out.ExpireAfterJitter
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MaxLifetime != nil {
+		in, out := &in.MaxLifetime, &out.MaxLifetime
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Kubelet != nil {
+		in, out := &in.Kubelet, &out.Kubelet
+		*out = new(KubeletConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeClaimSpec.
@@ -259,6 +472,16 @@ func (in *NodeClaimStatus) DeepCopyInto(out *NodeClaimStatus) {
 		}
 	}
 	in.LastPodEventTime.DeepCopyInto(&out.LastPodEventTime)
+	if in.LaunchFailure != nil {
+		in, out := &in.LaunchFailure, &out.LaunchFailure
+		*out = new(LaunchFailure)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LaunchedInstance != nil {
+		in, out := &in.LaunchedInstance, &out.LaunchedInstance
+		*out = new(LaunchedInstance)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeClaimStatus.
@@ -298,6 +521,20 @@ func (in *NodeClaimTemplateSpec) DeepCopyInto(out *NodeClaimTemplateSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.TaintTemplates != nil {
+		in, out := &in.TaintTemplates, &out.TaintTemplates
+		*out = make([]corev1.Taint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LabelTemplates != nil {
+		in, out := &in.LabelTemplates, &out.LabelTemplates
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.StartupTaints != nil {
 		in, out := &in.StartupTaints, &out.StartupTaints
 		*out = make([]corev1.Taint, len(*in))
@@ -305,6 +542,18 @@ func (in *NodeClaimTemplateSpec) DeepCopyInto(out *NodeClaimTemplateSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.StartupTaintTimeouts != nil {
+		in, out := &in.StartupTaintTimeouts, &out.StartupTaintTimeouts
+		*out = make(map[string]metav1.Duration, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ReadinessGates != nil {
+		in, out := &in.ReadinessGates, &out.ReadinessGates
+		*out = make([]NodeReadinessGate, len(*in))
+		copy(*out, *in)
+	}
 	if in.Requirements != nil {
 		in, out := &in.Requirements, &out.Requirements
 		*out = make([]NodeSelectorRequirementWithMinValues, len(*in))
@@ -317,12 +566,42 @@ func (in *NodeClaimTemplateSpec) DeepCopyInto(out *NodeClaimTemplateSpec) {
 		*out = new(NodeClassReference)
 		**out = **in
 	}
+	if in.FallbackNodeClassRefs != nil {
+		in, out := &in.FallbackNodeClassRefs, &out.FallbackNodeClassRefs
+		*out = make([]NodeClassReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.RegistrationTTL != nil {
+		in, out := &in.RegistrationTTL, &out.RegistrationTTL
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.InitializationTTL != nil {
+		in, out := &in.InitializationTTL, &out.InitializationTTL
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 	if in.TerminationGracePeriod != nil {
 		in, out := &in.TerminationGracePeriod, &out.TerminationGracePeriod
 		*out = new(metav1.Duration)
 		**out = **in
 	}
 	in.ExpireAfter.DeepCopyInto(&out.ExpireAfter)
+	if in.ExpireAfterJitter != nil {
+		in, out := &in.ExpireAfterJitter, &out.ExpireAfterJitter
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MaxLifetime != nil {
+		in, out := &in.MaxLifetime, &out.MaxLifetime
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Kubelet != nil {
+		in, out := &in.Kubelet, &out.Kubelet
+		*out = new(KubeletConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeClaimTemplateSpec.
@@ -350,6 +629,115 @@ func (in *NodeClassReference) DeepCopy() *NodeClassReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeOverlay) DeepCopyInto(out *NodeOverlay) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeOverlay.
+func (in *NodeOverlay) DeepCopy() *NodeOverlay {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeOverlay)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NodeOverlay) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeOverlayList) DeepCopyInto(out *NodeOverlayList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NodeOverlay, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeOverlayList.
+func (in *NodeOverlayList) DeepCopy() *NodeOverlayList {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeOverlayList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NodeOverlayList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeOverlaySpec) DeepCopyInto(out *NodeOverlaySpec) {
+	*out = *in
+	if in.Requirements != nil {
+		in, out := &in.Requirements, &out.Requirements
+		*out = make([]corev1.NodeSelectorRequirement, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PriceAdjustment != nil {
+		in, out := &in.PriceAdjustment, &out.PriceAdjustment
+		*out = new(string)
+		**out = **in
+	}
+	if in.Price != nil {
+		in, out := &in.Price, &out.Price
+		*out = new(string)
+		**out = **in
+	}
+	if in.Capacity != nil {
+		in, out := &in.Capacity, &out.Capacity
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.Overhead != nil {
+		in, out := &in.Overhead, &out.Overhead
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.Weight != nil {
+		in, out := &in.Weight, &out.Weight
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeOverlaySpec.
+func (in *NodeOverlaySpec) DeepCopy() *NodeOverlaySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeOverlaySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodePool) DeepCopyInto(out *NodePool) {
 	*out = *in
@@ -421,11 +809,136 @@ func (in *NodePoolSpec) DeepCopyInto(out *NodePoolSpec) {
 			(*out)[key] = val.DeepCopy()
 		}
 	}
+	if in.DomainLimits != nil {
+		in, out := &in.DomainLimits, &out.DomainLimits
+		*out = make(map[string]Limits, len(*in))
+		for key, val := range *in {
+			var outVal map[corev1.ResourceName]resource.Quantity
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = make(Limits, len(*in))
+				for key, val := range *in {
+					(*out)[key] = val.DeepCopy()
+				}
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.CapacityTypeLimits != nil {
+		in, out := &in.CapacityTypeLimits, &out.CapacityTypeLimits
+		*out = make(map[string]Limits, len(*in))
+		for key, val := range *in {
+			var outVal map[corev1.ResourceName]resource.Quantity
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = make(Limits, len(*in))
+				for key, val := range *in {
+					(*out)[key] = val.DeepCopy()
+				}
+			}
+			(*out)[key] = outVal
+		}
+	}
 	if in.Weight != nil {
 		in, out := &in.Weight, &out.Weight
 		*out = new(int32)
 		**out = **in
 	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Headroom != nil {
+		in, out := &in.Headroom, &out.Headroom
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.MinNodesPerZone != nil {
+		in, out := &in.MinNodesPerZone, &out.MinNodesPerZone
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MinNodes != nil {
+		in, out := &in.MinNodes, &out.MinNodes
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MinResources != nil {
+		in, out := &in.MinResources, &out.MinResources
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.SimulatedPodRequests != nil {
+		in, out := &in.SimulatedPodRequests, &out.SimulatedPodRequests
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.LaunchRate != nil {
+		in, out := &in.LaunchRate, &out.LaunchRate
+		*out = new(LaunchRate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InstanceTypeTruncation != nil {
+		in, out := &in.InstanceTypeTruncation, &out.InstanceTypeTruncation
+		*out = new(InstanceTypeTruncation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CapacityTypeSplit != nil {
+		in, out := &in.CapacityTypeSplit, &out.CapacityTypeSplit
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.MaxPricePercentile != nil {
+		in, out := &in.MaxPricePercentile, &out.MaxPricePercentile
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TopologyDomains != nil {
+		in, out := &in.TopologyDomains, &out.TopologyDomains
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.ExcludedDaemonSets != nil {
+		in, out := &in.ExcludedDaemonSets, &out.ExcludedDaemonSets
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AllowedNamespaces != nil {
+		in, out := &in.AllowedNamespaces, &out.AllowedNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExistingCapacityWindow != nil {
+		in, out := &in.ExistingCapacityWindow, &out.ExistingCapacityWindow
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePoolSpec.
@@ -448,6 +961,58 @@ func (in *NodePoolStatus) DeepCopyInto(out *NodePoolStatus) {
 			(*out)[key] = val.DeepCopy()
 		}
 	}
+	if in.CapacityTypeResources != nil {
+		in, out := &in.CapacityTypeResources, &out.CapacityTypeResources
+		*out = make(map[string]corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			var outVal map[corev1.ResourceName]resource.Quantity
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = make(corev1.ResourceList, len(*in))
+				for key, val := range *in {
+					(*out)[key] = val.DeepCopy()
+				}
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.RemainingNodes != nil {
+		in, out := &in.RemainingNodes, &out.RemainingNodes
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Allocatable != nil {
+		in, out := &in.Allocatable, &out.Allocatable
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.Requested != nil {
+		in, out := &in.Requested, &out.Requested
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.LastProvisioningTime != nil {
+		in, out := &in.LastProvisioningTime, &out.LastProvisioningTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastDisruptionTime != nil {
+		in, out := &in.LastDisruptionTime, &out.LastDisruptionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.AllowedDisruptions != nil {
+		in, out := &in.AllowedDisruptions, &out.AllowedDisruptions
+		*out = make(map[DisruptionReason]int64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]status.Condition, len(*in))
@@ -455,6 +1020,16 @@ func (in *NodePoolStatus) DeepCopyInto(out *NodePoolStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.NodeClassRef != nil {
+		in, out := &in.NodeClassRef, &out.NodeClassRef
+		*out = new(NodeClassReference)
+		**out = **in
+	}
+	if in.LaunchBackoff != nil {
+		in, out := &in.LaunchBackoff, &out.LaunchBackoff
+		*out = new(LaunchBackoff)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePoolStatus.
@@ -467,6 +1042,21 @@ func (in *NodePoolStatus) DeepCopy() *NodePoolStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeReadinessGate) DeepCopyInto(out *NodeReadinessGate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeReadinessGate.
+func (in *NodeReadinessGate) DeepCopy() *NodeReadinessGate {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeReadinessGate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeSelectorRequirementWithMinValues) DeepCopyInto(out *NodeSelectorRequirementWithMinValues) {
 	*out = *in
@@ -505,6 +1095,11 @@ func (in *ObjectMeta) DeepCopyInto(out *ObjectMeta) {
 			(*out)[key] = val
 		}
 	}
+	if in.Finalizers != nil {
+		in, out := &in.Finalizers, &out.Finalizers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectMeta.
@@ -538,3 +1133,226 @@ func (in *ResourceRequirements) DeepCopy() *ResourceRequirements {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchedulingSnapshot) DeepCopyInto(out *SchedulingSnapshot) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulingSnapshot.
+func (in *SchedulingSnapshot) DeepCopy() *SchedulingSnapshot {
+	if in == nil {
+		return nil
+	}
+	out := new(SchedulingSnapshot)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SchedulingSnapshot) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchedulingSnapshotInstanceTypeRejection) DeepCopyInto(out *SchedulingSnapshotInstanceTypeRejection) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulingSnapshotInstanceTypeRejection.
+func (in *SchedulingSnapshotInstanceTypeRejection) DeepCopy() *SchedulingSnapshotInstanceTypeRejection {
+	if in == nil {
+		return nil
+	}
+	out := new(SchedulingSnapshotInstanceTypeRejection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchedulingSnapshotList) DeepCopyInto(out *SchedulingSnapshotList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SchedulingSnapshot, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulingSnapshotList.
+func (in *SchedulingSnapshotList) DeepCopy() *SchedulingSnapshotList {
+	if in == nil {
+		return nil
+	}
+	out := new(SchedulingSnapshotList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SchedulingSnapshotList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchedulingSnapshotNode) DeepCopyInto(out *SchedulingSnapshotNode) {
+	*out = *in
+	if in.Pods != nil {
+		in, out := &in.Pods, &out.Pods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulingSnapshotNode.
+func (in *SchedulingSnapshotNode) DeepCopy() *SchedulingSnapshotNode {
+	if in == nil {
+		return nil
+	}
+	out := new(SchedulingSnapshotNode)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchedulingSnapshotNodeClaim) DeepCopyInto(out *SchedulingSnapshotNodeClaim) {
+	*out = *in
+	if in.InstanceTypes != nil {
+		in, out := &in.InstanceTypes, &out.InstanceTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Pods != nil {
+		in, out := &in.Pods, &out.Pods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RejectedInstanceTypes != nil {
+		in, out := &in.RejectedInstanceTypes, &out.RejectedInstanceTypes
+		*out = make([]SchedulingSnapshotInstanceTypeRejection, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulingSnapshotNodeClaim.
+func (in *SchedulingSnapshotNodeClaim) DeepCopy() *SchedulingSnapshotNodeClaim {
+	if in == nil {
+		return nil
+	}
+	out := new(SchedulingSnapshotNodeClaim)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchedulingSnapshotNodePoolFailure) DeepCopyInto(out *SchedulingSnapshotNodePoolFailure) {
+	*out = *in
+	if in.Details != nil {
+		in, out := &in.Details, &out.Details
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RejectedInstanceTypes != nil {
+		in, out := &in.RejectedInstanceTypes, &out.RejectedInstanceTypes
+		*out = make([]SchedulingSnapshotInstanceTypeRejection, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulingSnapshotNodePoolFailure.
+func (in *SchedulingSnapshotNodePoolFailure) DeepCopy() *SchedulingSnapshotNodePoolFailure {
+	if in == nil {
+		return nil
+	}
+	out := new(SchedulingSnapshotNodePoolFailure)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchedulingSnapshotPodError) DeepCopyInto(out *SchedulingSnapshotPodError) {
+	*out = *in
+	if in.Failures != nil {
+		in, out := &in.Failures, &out.Failures
+		*out = make([]SchedulingSnapshotNodePoolFailure, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulingSnapshotPodError.
+func (in *SchedulingSnapshotPodError) DeepCopy() *SchedulingSnapshotPodError {
+	if in == nil {
+		return nil
+	}
+	out := new(SchedulingSnapshotPodError)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchedulingSnapshotSpec) DeepCopyInto(out *SchedulingSnapshotSpec) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	if in.ExistingNodes != nil {
+		in, out := &in.ExistingNodes, &out.ExistingNodes
+		*out = make([]SchedulingSnapshotNode, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NodeClaims != nil {
+		in, out := &in.NodeClaims, &out.NodeClaims
+		*out = make([]SchedulingSnapshotNodeClaim, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TopologyDomains != nil {
+		in, out := &in.TopologyDomains, &out.TopologyDomains
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.PodErrors != nil {
+		in, out := &in.PodErrors, &out.PodErrors
+		*out = make([]SchedulingSnapshotPodError, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulingSnapshotSpec.
+func (in *SchedulingSnapshotSpec) DeepCopy() *SchedulingSnapshotSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SchedulingSnapshotSpec)
+	in.DeepCopyInto(out)
+	return out
+}
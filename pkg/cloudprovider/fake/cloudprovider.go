@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"math"
 	"sort"
+	"strconv"
 	"sync"
 	"time"
 
@@ -61,6 +62,8 @@ type CloudProvider struct {
 	Drifted                   cloudprovider.DriftReason
 	NodeClassGroupVersionKind []schema.GroupVersionKind
 	RepairPolicy              []cloudprovider.RepairPolicy
+	InterruptionNotices       chan cloudprovider.Interruption
+	Prices                    cloudprovider.Pricing
 }
 
 func NewCloudProvider() *CloudProvider {
@@ -69,6 +72,7 @@ func NewCloudProvider() *CloudProvider {
 		CreatedNodeClaims:        map[string]*v1.NodeClaim{},
 		InstanceTypesForNodePool: map[string][]*cloudprovider.InstanceType{},
 		ErrorsForNodePool:        map[string]error{},
+		InterruptionNotices:      make(chan cloudprovider.Interruption, 10),
 	}
 }
 
@@ -88,6 +92,7 @@ func (c *CloudProvider) Reset() {
 	c.DeleteCalls = []*v1.NodeClaim{}
 	c.GetCalls = nil
 	c.Drifted = "drifted"
+	c.Prices = cloudprovider.Pricing{}
 	c.NodeClassGroupVersionKind = []schema.GroupVersionKind{
 		{
 			Group:   "",
@@ -140,10 +145,15 @@ func (c *CloudProvider) Create(ctx context.Context, nodeClaim *v1.NodeClaim) (*v
 		}
 	}
 	// Find Offering
+	var offering *cloudprovider.Offering
 	for _, o := range instanceType.Offerings.Available() {
 		if reqs.IsCompatible(o.Requirements, scheduling.AllowUndefinedWellKnownLabels) {
 			labels[corev1.LabelTopologyZone] = o.Requirements.Get(corev1.LabelTopologyZone).Any()
 			labels[v1.CapacityTypeLabelKey] = o.Requirements.Get(v1.CapacityTypeLabelKey).Any()
+			if pg := o.PlacementGroup(); pg != "" {
+				labels[v1.PlacementGroupLabelKey] = pg
+			}
+			offering = lo.ToPtr(o)
 			break
 		}
 	}
@@ -160,6 +170,15 @@ func (c *CloudProvider) Create(ctx context.Context, nodeClaim *v1.NodeClaim) (*v
 			Allocatable: lo.PickBy(instanceType.Allocatable(), func(_ corev1.ResourceName, v resource.Quantity) bool { return !resources.IsZero(v) }),
 		},
 	}
+	if offering != nil {
+		created.Status.LaunchedInstance = &v1.LaunchedInstance{
+			InstanceType: instanceType.Name,
+			Zone:         labels[corev1.LabelTopologyZone],
+			CapacityType: labels[v1.CapacityTypeLabelKey],
+			Price:        strconv.FormatFloat(offering.Price, 'f', -1, 64),
+			Currency:     c.Prices.Currency,
+		}
+	}
 	c.CreatedNodeClaims[created.Status.ProviderID] = created
 	return created, nil
 }
@@ -279,3 +298,13 @@ func (c *CloudProvider) Name() string {
 func (c *CloudProvider) GetSupportedNodeClasses() []status.Object {
 	return []status.Object{&v1alpha1.TestNodeClass{}}
 }
+
+func (c *CloudProvider) Interruptions() <-chan cloudprovider.Interruption {
+	return c.InterruptionNotices
+}
+
+func (c *CloudProvider) Pricing() cloudprovider.Pricing {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.Prices
+}
@@ -39,7 +39,7 @@ const (
 )
 
 func init() {
-	v1.WellKnownLabels.Insert(
+	v1.RegisterWellKnownLabels(
 		LabelInstanceSize,
 		ExoticInstanceLabelKey,
 		IntegerInstanceLabelKey,
@@ -107,6 +107,9 @@ func NewInstanceTypeWithCustomRequirement(options InstanceTypeOptions, customReq
 		scheduling.NewRequirement(ExoticInstanceLabelKey, corev1.NodeSelectorOpDoesNotExist),
 		scheduling.NewRequirement(IntegerInstanceLabelKey, corev1.NodeSelectorOpIn, fmt.Sprint(options.Resources.Cpu().Value())),
 	)
+	if placementGroups := lo.Compact(lo.Map(options.Offerings.Available(), func(o cloudprovider.Offering, _ int) string { return o.PlacementGroup() })); len(placementGroups) > 0 {
+		requirements.Add(scheduling.NewRequirement(v1.PlacementGroupLabelKey, corev1.NodeSelectorOpIn, placementGroups...))
+	}
 	if customReq != nil {
 		requirements.Add(customReq)
 	}
@@ -128,7 +131,9 @@ func NewInstanceTypeWithCustomRequirement(options InstanceTypeOptions, customReq
 				corev1.ResourceCPU:    resource.MustParse("100m"),
 				corev1.ResourceMemory: resource.MustParse("10Mi"),
 			},
+			ImageFilesystem: options.ImageFilesystemOverhead,
 		},
+		VolumeLimits: options.VolumeLimits,
 	}
 }
 
@@ -193,11 +198,13 @@ func InstanceTypes(total int) []*cloudprovider.InstanceType {
 }
 
 type InstanceTypeOptions struct {
-	Name             string
-	Offerings        cloudprovider.Offerings
-	Architecture     string
-	OperatingSystems sets.Set[string]
-	Resources        corev1.ResourceList
+	Name                    string
+	Offerings               cloudprovider.Offerings
+	Architecture            string
+	OperatingSystems        sets.Set[string]
+	Resources               corev1.ResourceList
+	VolumeLimits            map[string]int
+	ImageFilesystemOverhead corev1.ResourceList
 }
 
 func PriceFromResources(resources corev1.ResourceList) float64 {
@@ -19,7 +19,6 @@ package metrics
 import (
 	"context"
 
-	opmetrics "github.com/awslabs/operatorpkg/metrics"
 	"github.com/prometheus/client_golang/prometheus"
 	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 
@@ -45,7 +44,7 @@ const (
 // decorator implements CloudProvider
 var _ cloudprovider.CloudProvider = (*decorator)(nil)
 
-var MethodDuration = opmetrics.NewPrometheusHistogram(
+var MethodDuration = metrics.NewPrometheusHistogram(
 	crmetrics.Registry,
 	prometheus.HistogramOpts{
 		Namespace: metrics.Namespace,
@@ -61,7 +60,7 @@ var MethodDuration = opmetrics.NewPrometheusHistogram(
 )
 
 var (
-	ErrorsTotal = opmetrics.NewPrometheusCounter(
+	ErrorsTotal = metrics.NewPrometheusCounter(
 		crmetrics.Registry,
 		prometheus.CounterOpts{
 			Namespace: metrics.Namespace,
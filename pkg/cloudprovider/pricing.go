@@ -0,0 +1,111 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cloudprovider
+
+import (
+	"context"
+	"time"
+
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/clock"
+
+	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+)
+
+// PriceKey identifies the offering that a price applies to. Prices that don't vary by zone, e.g. on-demand prices,
+// leave the zone empty.
+type PriceKey struct {
+	InstanceType string
+	Zone         string
+	CapacityType string
+}
+
+// Pricing is a snapshot of the prices of the CloudProvider's offerings. Offerings that the snapshot doesn't price keep
+// the price that the CloudProvider set on them.
+type Pricing struct {
+	// Prices of the offerings, keyed by the offering that they apply to
+	Prices map[PriceKey]float64
+	// Currency of the prices, e.g. USD
+	Currency string
+	// UpdatedAt is when the prices were last refreshed from the CloudProvider's pricing source
+	UpdatedAt time.Time
+}
+
+// Price returns the price of the offering, falling back to the price of the instance type and capacity type in every
+// zone, and false if neither is known
+func (p Pricing) Price(instanceType, zone, capacityType string) (float64, bool) {
+	if price, ok := p.Prices[PriceKey{InstanceType: instanceType, Zone: zone, CapacityType: capacityType}]; ok {
+		return price, true
+	}
+	price, ok := p.Prices[PriceKey{InstanceType: instanceType, CapacityType: capacityType}]
+	return price, ok
+}
+
+// Age returns how long ago the prices were refreshed, or zero if the CloudProvider doesn't report when they were
+func (p Pricing) Age(clk clock.PassiveClock) time.Duration {
+	if p.UpdatedAt.IsZero() {
+		return 0
+	}
+	return clk.Since(p.UpdatedAt)
+}
+
+// WithPricing returns copies of the instance types with the prices of their offerings set from the pricing. Instance
+// types without priced offerings are returned unmodified.
+func (its InstanceTypes) WithPricing(pricing Pricing) InstanceTypes {
+	if len(pricing.Prices) == 0 {
+		return its
+	}
+	return lo.Map(its, func(it *InstanceType, _ int) *InstanceType {
+		priced := false
+		offerings := lo.Map(it.Offerings, func(o Offering, _ int) Offering {
+			if price, ok := pricing.Price(it.Name, offeringValue(o, corev1.LabelTopologyZone), offeringValue(o, v1.CapacityTypeLabelKey)); ok {
+				o.Price = price
+				priced = true
+			}
+			return o
+		})
+		if !priced {
+			return it
+		}
+		return &InstanceType{
+			Name:         it.Name,
+			Requirements: it.Requirements,
+			Offerings:    offerings,
+			Capacity:     it.Capacity,
+			Overhead:     it.Overhead,
+			VolumeLimits: it.VolumeLimits,
+		}
+	})
+}
+
+// DecorateWithPricing returns a CloudProvider that returns instance types with the prices of their offerings set from
+// the CloudProvider's pricing, so that scheduling, consolidation and the launch prices of NodeClaims use the same prices
+func DecorateWithPricing(cloudProvider CloudProvider) CloudProvider {
+	return &pricingDecorator{CloudProvider: cloudProvider}
+}
+
+type pricingDecorator struct {
+	CloudProvider
+}
+
+func (d *pricingDecorator) GetInstanceTypes(ctx context.Context, nodePool *v1.NodePool) ([]*InstanceType, error) {
+	instanceTypes, err := d.CloudProvider.GetInstanceTypes(ctx, nodePool)
+	if err != nil {
+		return nil, err
+	}
+	return InstanceTypes(instanceTypes).WithPricing(d.Pricing()), nil
+}
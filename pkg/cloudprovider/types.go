@@ -21,13 +21,17 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"slices"
 	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/awslabs/operatorpkg/status"
 	"github.com/samber/lo"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/util/sets"
 
 	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
@@ -55,13 +59,15 @@ type RepairPolicy struct {
 // CloudProvider interface is implemented by cloud providers to support provisioning.
 type CloudProvider interface {
 	// Create launches a NodeClaim with the given resource requests and requirements and returns a hydrated
-	// NodeClaim back with resolved NodeClaim labels for the launched NodeClaim
+	// NodeClaim back with resolved NodeClaim labels for the launched NodeClaim. The returned NodeClaim should report the
+	// launched instance and the price of its offering in its LaunchedInstance status.
 	Create(context.Context, *v1.NodeClaim) (*v1.NodeClaim, error)
 	// Delete removes a NodeClaim from the cloudprovider by its provider id
 	Delete(context.Context, *v1.NodeClaim) error
 	// Get retrieves a NodeClaim from the cloudprovider by its provider id
 	Get(context.Context, string) (*v1.NodeClaim, error)
-	// List retrieves all NodeClaims from the cloudprovider
+	// List retrieves all NodeClaims from the cloudprovider. The NodeClaims report the instance retention policy that their
+	// instances were created with, so that retained instances aren't garbage collected once their NodeClaim is deleted.
 	List(context.Context) ([]*v1.NodeClaim, error)
 	// GetInstanceTypes returns instance types supported by the cloudprovider.
 	// Availability of types or zone may vary by nodepool or over time.  Regardless of
@@ -79,6 +85,35 @@ type CloudProvider interface {
 	// GetSupportedNodeClasses returns CloudProvider NodeClass that implements status.Object
 	// NOTE: It returns a list where the first element should be the default NodeClass
 	GetSupportedNodeClasses() []status.Object
+	// Interruptions returns a channel that the CloudProvider delivers notices of upcoming interruptions of its instances
+	// on, e.g. spot interruptions and rebalance recommendations. CloudProviders that don't deliver interruption notices
+	// return nil.
+	Interruptions() <-chan Interruption
+	// Pricing returns the current prices of the CloudProvider's offerings. The prices take precedence over the prices
+	// that GetInstanceTypes sets on the offerings, which are only used for the offerings that the pricing doesn't price.
+	Pricing() Pricing
+}
+
+type InterruptionKind string
+
+const (
+	// InterruptionKindSpotInterruption notices that a spot instance is going to be reclaimed
+	InterruptionKindSpotInterruption InterruptionKind = "SpotInterruption"
+	// InterruptionKindRebalanceRecommendation notices that a spot instance is at an elevated risk of being reclaimed
+	InterruptionKindRebalanceRecommendation InterruptionKind = "RebalanceRecommendation"
+	// InterruptionKindScheduledMaintenance notices that an instance is going to be stopped or retired for maintenance
+	InterruptionKindScheduledMaintenance InterruptionKind = "ScheduledMaintenance"
+)
+
+// Interruption is a notice that the instance of a NodeClaim is going to be interrupted. The NodeClaim is drained and
+// deleted through the disruption budgets of its NodePool for the Interrupted reason.
+type Interruption struct {
+	// ProviderID of the instance that's going to be interrupted
+	ProviderID string
+	// Kind of the interruption
+	Kind InterruptionKind
+	// Time at which the instance is going to be interrupted, or zero if it isn't known
+	Time time.Time
 }
 
 // InstanceType describes the properties of a potential node (either concrete attributes of an instance of this type
@@ -91,11 +126,16 @@ type InstanceType struct {
 	Requirements scheduling.Requirements
 	// Note that though this is an array it is expected that all the Offerings are unique from one another
 	Offerings Offerings
-	// Resources are the full resource capacities for this instance type
+	// Resources are the full resource capacities for this instance type. This may include arbitrary extended resources
+	// which are honored when scheduling pods that request them. Extended resources registered by a device plugin after
+	// node startup should also be added to scheduling.KnownDevicePluginResources.
 	Capacity corev1.ResourceList
 	// Overhead is the amount of resource overhead expected to be used by kubelet and any other system daemons outside
 	// of Kubernetes.
 	Overhead *InstanceTypeOverhead
+	// VolumeLimits is the maximum number of volumes that each CSI driver can attach to a node of this instance type,
+	// keyed by the name of the CSI driver. Volumes of drivers without a limit aren't restricted.
+	VolumeLimits map[string]int
 
 	once        sync.Once
 	allocatable corev1.ResourceList
@@ -211,10 +251,75 @@ func (its InstanceTypes) SatisfiesMinValues(requirements scheduling.Requirements
 	return len(its), nil
 }
 
-// Truncate truncates the InstanceTypes based on the passed-in requirements
+// SelectByStrategy selects up to maxItems instance types using the truncation strategy of the NodePool, returning them
+// ordered by price. The NodePool's MaxInstanceTypes overrides maxItems when it's set. The cheapest instance types are
+// selected when no truncation is configured.
+func (its InstanceTypes) SelectByStrategy(requirements scheduling.Requirements, maxItems int, truncation *v1.InstanceTypeTruncation) InstanceTypes {
+	ordered := its.OrderByPrice(requirements)
+	if truncation == nil {
+		return lo.Slice(ordered, 0, maxItems)
+	}
+	if truncation.MaxInstanceTypes != nil {
+		maxItems = int(*truncation.MaxInstanceTypes)
+	}
+	switch truncation.Strategy {
+	case v1.InstanceTypeTruncationStrategyPricePercentile:
+		// Keep the instance types in the cheapest percentile, always keeping at least the cheapest instance type
+		percentile := int(lo.FromPtrOr(truncation.PricePercentile, 100))
+		count := lo.Max([]int{1, int(math.Ceil(float64(len(ordered)*percentile) / 100))})
+		return lo.Slice(ordered, 0, lo.Min([]int{count, maxItems}))
+	case v1.InstanceTypeTruncationStrategyDiverse:
+		return ordered.selectDiverse(requirements, maxItems)
+	default:
+		return lo.Slice(ordered, 0, maxItems)
+	}
+}
+
+// selectDiverse selects instance types in price order, preferring those that add a new value for a requirement with
+// minValues so that the selection covers as many distinct values as possible. Any remaining slots are filled with the
+// cheapest of the instance types that weren't selected. The instance types are expected to already be ordered by price.
+func (its InstanceTypes) selectDiverse(requirements scheduling.Requirements, maxItems int) InstanceTypes {
+	keys := lo.Filter(requirements.Keys().UnsortedList(), func(key string, _ int) bool {
+		return requirements.Get(key).MinValues != nil
+	})
+	if len(keys) == 0 || len(its) <= maxItems {
+		return lo.Slice(its, 0, maxItems)
+	}
+	seen := map[string]sets.Set[string]{}
+	for _, key := range keys {
+		seen[key] = sets.New[string]()
+	}
+	selected := sets.New[int]()
+	for i, it := range its {
+		if len(selected) >= maxItems {
+			break
+		}
+		diverse := false
+		for _, key := range keys {
+			values := it.Requirements.Get(key).Values()
+			if !seen[key].HasAll(values...) {
+				seen[key].Insert(values...)
+				diverse = true
+			}
+		}
+		if diverse {
+			selected.Insert(i)
+		}
+	}
+	for i := range its {
+		if len(selected) >= maxItems {
+			break
+		}
+		selected.Insert(i)
+	}
+	// Preserve the price ordering of the selected instance types
+	return lo.Filter(its, func(_ *InstanceType, i int) bool { return selected.Has(i) })
+}
+
+// Truncate truncates the InstanceTypes based on the passed-in requirements and the truncation configuration of the NodePool
 // It returns an error if it isn't possible to truncate the instance types on maxItems without violating minValues
-func (its InstanceTypes) Truncate(requirements scheduling.Requirements, maxItems int) (InstanceTypes, error) {
-	truncatedInstanceTypes := lo.Slice(its.OrderByPrice(requirements), 0, maxItems)
+func (its InstanceTypes) Truncate(requirements scheduling.Requirements, maxItems int, truncation *v1.InstanceTypeTruncation) (InstanceTypes, error) {
+	truncatedInstanceTypes := its.SelectByStrategy(requirements, maxItems, truncation)
 	// Only check for a validity of NodeClaim if its requirement has minValues in it.
 	if requirements.HasMinValues() {
 		if _, err := truncatedInstanceTypes.SatisfiesMinValues(requirements); err != nil {
@@ -224,6 +329,193 @@ func (its InstanceTypes) Truncate(requirements scheduling.Requirements, maxItems
 	return truncatedInstanceTypes, nil
 }
 
+// WithKubeletConfiguration returns copies of the instance types with their pod capacity and overhead overridden by
+// the kubelet configuration. The instance types are returned unmodified if there is no kubelet configuration.
+func (its InstanceTypes) WithKubeletConfiguration(kubelet *v1.KubeletConfiguration) InstanceTypes {
+	if kubelet == nil {
+		return its
+	}
+	return lo.Map(its, func(it *InstanceType, _ int) *InstanceType {
+		capacity := it.Capacity.DeepCopy()
+		if kubelet.MaxPods != nil {
+			capacity[corev1.ResourcePods] = *resource.NewQuantity(int64(lo.FromPtr(kubelet.MaxPods)), resource.DecimalSI)
+		}
+		overhead := &InstanceTypeOverhead{}
+		if it.Overhead != nil {
+			overhead.KubeReserved = it.Overhead.KubeReserved.DeepCopy()
+			overhead.SystemReserved = it.Overhead.SystemReserved.DeepCopy()
+			overhead.EvictionThreshold = it.Overhead.EvictionThreshold.DeepCopy()
+			overhead.ImageFilesystem = it.Overhead.ImageFilesystem.DeepCopy()
+		}
+		overhead.KubeReserved = lo.Assign(overhead.KubeReserved, reservedResources(kubelet.KubeReserved))
+		overhead.SystemReserved = lo.Assign(overhead.SystemReserved, reservedResources(kubelet.SystemReserved))
+		overhead.EvictionThreshold = lo.Assign(overhead.EvictionThreshold, evictionThreshold(capacity, kubelet.EvictionHard))
+		return &InstanceType{
+			Name:         it.Name,
+			Requirements: it.Requirements,
+			Offerings:    it.Offerings,
+			Capacity:     capacity,
+			Overhead:     overhead,
+			VolumeLimits: it.VolumeLimits,
+		}
+	})
+}
+
+// ZoneMapping returns the mapping of zone IDs to zone names of the instance types' offerings, for cloud providers that
+// register a zone ID label
+func (its InstanceTypes) ZoneMapping() *scheduling.ZoneMapping {
+	zones := scheduling.NewZoneMapping()
+	if v1.ZoneIDLabelKey == "" {
+		return zones
+	}
+	for _, it := range its {
+		for _, offering := range it.Offerings {
+			zones.Insert(offering.Requirements)
+		}
+	}
+	return zones
+}
+
+// AtLeast filters the instance types to those whose capacity is at least the passed resources
+func (its InstanceTypes) AtLeast(minResources corev1.ResourceList) InstanceTypes {
+	if len(minResources) == 0 {
+		return its
+	}
+	return lo.Filter(its, func(it *InstanceType, _ int) bool {
+		return resources.Fits(minResources, it.Capacity)
+	})
+}
+
+// WithOverlays returns copies of the instance types with their prices, capacity and overhead adjusted by the overlays
+// that select them. Instance types that no overlay selects are returned unmodified.
+func (its InstanceTypes) WithOverlays(overlays []*v1.NodeOverlay) InstanceTypes {
+	if len(overlays) == 0 {
+		return its
+	}
+	// Overlays are ordered by descending weight so that the first overlay that sets a value takes precedence
+	overlays = slices.Clone(overlays)
+	sort.SliceStable(overlays, func(a, b int) bool {
+		if wa, wb := lo.FromPtr(overlays[a].Spec.Weight), lo.FromPtr(overlays[b].Spec.Weight); wa != wb {
+			return wa > wb
+		}
+		return overlays[a].Name < overlays[b].Name
+	})
+	requirements := lo.Map(overlays, func(o *v1.NodeOverlay, _ int) scheduling.Requirements {
+		return scheduling.NewNodeSelectorRequirements(o.Spec.Requirements...)
+	})
+	return lo.Map(its, func(it *InstanceType, _ int) *InstanceType {
+		selected := lo.Filter(lo.Range(len(overlays)), func(i int, _ int) bool {
+			return it.Requirements.IsCompatible(requirements[i], scheduling.AllowUndefinedWellKnownLabels)
+		})
+		if len(selected) == 0 {
+			return it
+		}
+		capacity := it.Capacity.DeepCopy()
+		overhead := &InstanceTypeOverhead{}
+		if it.Overhead != nil {
+			overhead.KubeReserved = it.Overhead.KubeReserved.DeepCopy()
+			overhead.SystemReserved = it.Overhead.SystemReserved.DeepCopy()
+			overhead.EvictionThreshold = it.Overhead.EvictionThreshold.DeepCopy()
+			overhead.ImageFilesystem = it.Overhead.ImageFilesystem.DeepCopy()
+		}
+		overlaidCapacity, overlaidOverhead := corev1.ResourceList{}, corev1.ResourceList{}
+		for _, i := range selected {
+			overlaidCapacity = lo.Assign(overlays[i].Spec.Capacity, overlaidCapacity)
+			overlaidOverhead = lo.Assign(overlays[i].Spec.Overhead, overlaidOverhead)
+		}
+		capacity = lo.Assign(capacity, overlaidCapacity)
+		overhead.SystemReserved = resources.Merge(overhead.SystemReserved, overlaidOverhead)
+		offerings := lo.Map(it.Offerings, func(o Offering, _ int) Offering {
+			for _, i := range selected {
+				if overlays[i].Spec.Price == nil && overlays[i].Spec.PriceAdjustment == nil {
+					continue
+				}
+				// the instance type is already known to be compatible, so only the offering's own keys are checked
+				if o.Requirements.Intersects(requirements[i]) != nil {
+					continue
+				}
+				o.Price = overlaidPrice(o.Price, overlays[i].Spec)
+				break
+			}
+			return o
+		})
+		return &InstanceType{
+			Name:         it.Name,
+			Requirements: it.Requirements,
+			Offerings:    offerings,
+			Capacity:     capacity,
+			Overhead:     overhead,
+			VolumeLimits: it.VolumeLimits,
+		}
+	})
+}
+
+// overlaidPrice returns the price set by the overlay, or the price adjusted by the overlay's price adjustment. Values
+// that don't parse are validated by the CRD and leave the price unmodified.
+func overlaidPrice(price float64, overlay v1.NodeOverlaySpec) float64 {
+	if overlay.Price != nil {
+		if p, err := strconv.ParseFloat(*overlay.Price, 64); err == nil {
+			return p
+		}
+		return price
+	}
+	adjustment := lo.FromPtr(overlay.PriceAdjustment)
+	if percentage, found := strings.CutSuffix(adjustment, "%"); found {
+		if p, err := strconv.ParseFloat(percentage, 64); err == nil {
+			price += price * p / 100
+		}
+	} else if a, err := strconv.ParseFloat(adjustment, 64); err == nil {
+		price += a
+	}
+	return math.Max(price, 0)
+}
+
+// reservedResources converts a kubelet reserved map into a resource list, ignoring the pid reservation since it
+// isn't a schedulable resource
+func reservedResources(reserved map[string]string) corev1.ResourceList {
+	result := corev1.ResourceList{}
+	for k, v := range reserved {
+		if k == "pid" {
+			continue
+		}
+		if quantity, err := resource.ParseQuantity(v); err == nil {
+			result[corev1.ResourceName(k)] = quantity
+		}
+	}
+	return result
+}
+
+// evictionSignals maps the kubelet eviction signals to the resources that they reserve
+var evictionSignals = map[string]corev1.ResourceName{
+	"memory.available": corev1.ResourceMemory,
+	"nodefs.available": corev1.ResourceEphemeralStorage,
+}
+
+// evictionThreshold converts a kubelet eviction map into a resource list. Thresholds may either be quantities or
+// percentages of the capacity.
+func evictionThreshold(capacity corev1.ResourceList, eviction map[string]string) corev1.ResourceList {
+	result := corev1.ResourceList{}
+	for signal, v := range eviction {
+		name, ok := evictionSignals[signal]
+		if !ok {
+			continue
+		}
+		if percentage, found := strings.CutSuffix(v, "%"); found {
+			p, err := strconv.ParseFloat(percentage, 64)
+			if err != nil {
+				continue
+			}
+			c := capacity[name]
+			result[name] = *resource.NewQuantity(int64(math.Ceil(float64(c.Value())*p/100)), resource.BinarySI)
+			continue
+		}
+		if quantity, err := resource.ParseQuantity(v); err == nil {
+			result[name] = quantity
+		}
+	}
+	return result
+}
+
 type InstanceTypeOverhead struct {
 	// KubeReserved returns the default resources allocated to kubernetes system daemons by default
 	KubeReserved corev1.ResourceList
@@ -231,10 +523,14 @@ type InstanceTypeOverhead struct {
 	SystemReserved corev1.ResourceList
 	// EvictionThreshold returns the resources used to maintain a hard eviction threshold
 	EvictionThreshold corev1.ResourceList
+	// ImageFilesystem returns the ephemeral storage that container images and their overlay layers are expected to
+	// consume when they share the node's root filesystem. The kubelet doesn't reserve this storage, but pods whose
+	// ephemeral storage requests don't fit alongside it are evicted once the disk fills up.
+	ImageFilesystem corev1.ResourceList
 }
 
 func (i InstanceTypeOverhead) Total() corev1.ResourceList {
-	return resources.Merge(i.KubeReserved, i.SystemReserved, i.EvictionThreshold)
+	return resources.Merge(i.KubeReserved, i.SystemReserved, i.EvictionThreshold, i.ImageFilesystem)
 }
 
 // An Offering describes where an InstanceType is available to be used, with the expectation that its properties
@@ -249,6 +545,15 @@ type Offering struct {
 	Available bool
 }
 
+// PlacementGroup returns the sub-zone placement group (e.g. a partition or a spread group) that the offering launches
+// into, or an empty string if the offering isn't constrained to a placement group
+func (o Offering) PlacementGroup() string {
+	if !o.Requirements.Has(v1.PlacementGroupLabelKey) {
+		return ""
+	}
+	return o.Requirements.Get(v1.PlacementGroupLabelKey).Any()
+}
+
 type Offerings []Offering
 
 // Available filters the available offerings from the returned offerings
@@ -342,11 +647,16 @@ func IgnoreNodeClaimNotFoundError(err error) error {
 // InsufficientCapacityError is an error type returned by CloudProviders when a launch fails due to a lack of capacity from NodeClaim requirements
 type InsufficientCapacityError struct {
 	error
+	// Offerings are the offerings that the CloudProvider ran out of capacity for, which are marked unavailable
+	Offerings []UnavailableOffering
 }
 
-func NewInsufficientCapacityError(err error) *InsufficientCapacityError {
+// NewInsufficientCapacityError returns an insufficient capacity error. CloudProviders pass the offerings that they ran
+// out of capacity for, so that they aren't launched again until their TTL expires.
+func NewInsufficientCapacityError(err error, offerings ...UnavailableOffering) *InsufficientCapacityError {
 	return &InsufficientCapacityError{
-		error: err,
+		error:     err,
+		Offerings: offerings,
 	}
 }
 
@@ -389,6 +699,9 @@ func IsNodeClassNotReadyError(err error) bool {
 type CreateError struct {
 	error
 	ConditionMessage string
+	// Reason classifies the failure, e.g. v1.LaunchFailureReasonQuotaExceeded. Failures without a reason are reported as
+	// v1.LaunchFailureReasonUnknown.
+	Reason string
 }
 
 func NewCreateError(err error, message string) *CreateError {
@@ -397,3 +710,13 @@ func NewCreateError(err error, message string) *CreateError {
 		ConditionMessage: message,
 	}
 }
+
+// NewCreateErrorWithReason returns a CreateError that is classified by the reason, e.g. so that users can alert on
+// launches that failed due to exceeded quotas
+func NewCreateErrorWithReason(err error, reason string, message string) *CreateError {
+	return &CreateError{
+		error:            err,
+		ConditionMessage: message,
+		Reason:           reason,
+	}
+}
@@ -0,0 +1,159 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cloudprovider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/patrickmn/go-cache"
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+)
+
+// UnavailableOfferingsTTL is how long an offering is unavailable for when the cloud provider doesn't report a TTL
+const UnavailableOfferingsTTL = 3 * time.Minute
+
+// UnavailableOffering identifies the offerings that the cloud provider ran out of capacity for. Empty fields match
+// every value, e.g. an offering without an instance type marks the capacity type of the zone unavailable for every
+// instance type.
+type UnavailableOffering struct {
+	InstanceType string
+	Zone         string
+	CapacityType string
+	// TTL is how long the offerings are unavailable for, defaulting to UnavailableOfferingsTTL
+	TTL time.Duration
+}
+
+func (o UnavailableOffering) key() string {
+	return fmt.Sprintf("%s:%s:%s", o.InstanceType, o.Zone, o.CapacityType)
+}
+
+// UnavailableOfferings caches the offerings that recently failed to launch due to insufficient capacity, so that the
+// scheduler and consolidation avoid them until their TTL expires
+type UnavailableOfferings struct {
+	cache *cache.Cache
+}
+
+func NewUnavailableOfferings() *UnavailableOfferings {
+	return &UnavailableOfferings{
+		cache: cache.New(UnavailableOfferingsTTL, time.Minute),
+	}
+}
+
+// MarkUnavailable marks the offerings unavailable for their TTL
+func (u *UnavailableOfferings) MarkUnavailable(ctx context.Context, reason string, offering UnavailableOffering) {
+	ttl := lo.Ternary(offering.TTL > 0, offering.TTL, UnavailableOfferingsTTL)
+	log.FromContext(ctx).WithValues(
+		"reason", reason,
+		"instance-type", offering.InstanceType,
+		"zone", offering.Zone,
+		"capacity-type", offering.CapacityType,
+		"ttl", ttl,
+	).V(1).Info("marking offering unavailable")
+	u.cache.Set(offering.key(), struct{}{}, ttl)
+}
+
+// IsUnavailable returns true if the offering, or any of the unavailable offerings that match it, was marked unavailable
+func (u *UnavailableOfferings) IsUnavailable(instanceType, zone, capacityType string) bool {
+	for _, it := range []string{instanceType, ""} {
+		for _, z := range []string{zone, ""} {
+			for _, ct := range []string{capacityType, ""} {
+				if _, ok := u.cache.Get(UnavailableOffering{InstanceType: it, Zone: z, CapacityType: ct}.key()); ok {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// Flush marks every offering available again
+func (u *UnavailableOfferings) Flush() {
+	u.cache.Flush()
+}
+
+// Decorate returns a CloudProvider that marks the offerings that the cloud provider reports in insufficient capacity
+// errors unavailable, and that returns instance types with the unavailable offerings marked as such
+func (u *UnavailableOfferings) Decorate(cloudProvider CloudProvider) CloudProvider {
+	return &unavailableOfferingsDecorator{CloudProvider: cloudProvider, unavailableOfferings: u}
+}
+
+type unavailableOfferingsDecorator struct {
+	CloudProvider
+	unavailableOfferings *UnavailableOfferings
+}
+
+func (d *unavailableOfferingsDecorator) Create(ctx context.Context, nodeClaim *v1.NodeClaim) (*v1.NodeClaim, error) {
+	created, err := d.CloudProvider.Create(ctx, nodeClaim)
+	var icErr *InsufficientCapacityError
+	if errors.As(err, &icErr) {
+		for _, offering := range icErr.Offerings {
+			d.unavailableOfferings.MarkUnavailable(ctx, "InsufficientCapacity", offering)
+		}
+	}
+	return created, err
+}
+
+func (d *unavailableOfferingsDecorator) GetInstanceTypes(ctx context.Context, nodePool *v1.NodePool) ([]*InstanceType, error) {
+	instanceTypes, err := d.CloudProvider.GetInstanceTypes(ctx, nodePool)
+	if err != nil {
+		return nil, err
+	}
+	return InstanceTypes(instanceTypes).WithUnavailableOfferings(d.unavailableOfferings), nil
+}
+
+// WithUnavailableOfferings returns copies of the instance types with the offerings that were marked unavailable no
+// longer available. Instance types without unavailable offerings are returned unmodified.
+func (its InstanceTypes) WithUnavailableOfferings(u *UnavailableOfferings) InstanceTypes {
+	if u.cache.ItemCount() == 0 {
+		return its
+	}
+	return lo.Map(its, func(it *InstanceType, _ int) *InstanceType {
+		unavailable := lo.Map(it.Offerings, func(o Offering, _ int) bool {
+			return o.Available && u.IsUnavailable(it.Name, offeringValue(o, corev1.LabelTopologyZone), offeringValue(o, v1.CapacityTypeLabelKey))
+		})
+		if !lo.Contains(unavailable, true) {
+			return it
+		}
+		offerings := lo.Map(it.Offerings, func(o Offering, i int) Offering {
+			o.Available = o.Available && !unavailable[i]
+			return o
+		})
+		return &InstanceType{
+			Name:         it.Name,
+			Requirements: it.Requirements,
+			Offerings:    offerings,
+			Capacity:     it.Capacity,
+			Overhead:     it.Overhead,
+			VolumeLimits: it.VolumeLimits,
+		}
+	})
+}
+
+// offeringValue returns the value of the offering's requirement for the key, or an empty string if the offering isn't
+// constrained by the key
+func offeringValue(o Offering, key string) string {
+	if !o.Requirements.Has(key) {
+		return ""
+	}
+	return o.Requirements.Get(key).Any()
+}
@@ -36,8 +36,10 @@ import (
 	metricsnode "sigs.k8s.io/karpenter/pkg/controllers/metrics/node"
 	metricsnodepool "sigs.k8s.io/karpenter/pkg/controllers/metrics/nodepool"
 	metricspod "sigs.k8s.io/karpenter/pkg/controllers/metrics/pod"
+	metricspricing "sigs.k8s.io/karpenter/pkg/controllers/metrics/pricing"
 	"sigs.k8s.io/karpenter/pkg/controllers/node/health"
 	nodehydration "sigs.k8s.io/karpenter/pkg/controllers/node/hydration"
+	nodepropagation "sigs.k8s.io/karpenter/pkg/controllers/node/propagation"
 	"sigs.k8s.io/karpenter/pkg/controllers/node/termination"
 	"sigs.k8s.io/karpenter/pkg/controllers/node/termination/terminator"
 	nodeclaimconsistency "sigs.k8s.io/karpenter/pkg/controllers/nodeclaim/consistency"
@@ -45,11 +47,17 @@ import (
 	"sigs.k8s.io/karpenter/pkg/controllers/nodeclaim/expiration"
 	nodeclaimgarbagecollection "sigs.k8s.io/karpenter/pkg/controllers/nodeclaim/garbagecollection"
 	nodeclaimhydration "sigs.k8s.io/karpenter/pkg/controllers/nodeclaim/hydration"
+	nodeclaiminterruption "sigs.k8s.io/karpenter/pkg/controllers/nodeclaim/interruption"
 	nodeclaimlifecycle "sigs.k8s.io/karpenter/pkg/controllers/nodeclaim/lifecycle"
 	"sigs.k8s.io/karpenter/pkg/controllers/nodeclaim/podevents"
 	nodepoolcounter "sigs.k8s.io/karpenter/pkg/controllers/nodepool/counter"
+	nodepooldefaulting "sigs.k8s.io/karpenter/pkg/controllers/nodepool/defaulting"
+	nodepooldegraded "sigs.k8s.io/karpenter/pkg/controllers/nodepool/degraded"
+	nodepooldeletionprotection "sigs.k8s.io/karpenter/pkg/controllers/nodepool/deletionprotection"
 	nodepoolhash "sigs.k8s.io/karpenter/pkg/controllers/nodepool/hash"
+	nodepooloverlap "sigs.k8s.io/karpenter/pkg/controllers/nodepool/overlap"
 	nodepoolreadiness "sigs.k8s.io/karpenter/pkg/controllers/nodepool/readiness"
+	nodepoolstatic "sigs.k8s.io/karpenter/pkg/controllers/nodepool/static"
 	nodepoolvalidation "sigs.k8s.io/karpenter/pkg/controllers/nodepool/validation"
 	"sigs.k8s.io/karpenter/pkg/controllers/provisioning"
 	"sigs.k8s.io/karpenter/pkg/controllers/state"
@@ -66,6 +74,7 @@ func NewControllers(
 	recorder events.Recorder,
 	cloudProvider cloudprovider.CloudProvider,
 ) []controller.Controller {
+	cloudProvider = cloudprovider.NewUnavailableOfferings().Decorate(cloudprovider.DecorateWithPricing(cloudProvider))
 	cluster := state.NewCluster(clock, kubeClient, cloudProvider)
 	p := provisioning.NewProvisioner(kubeClient, recorder, cloudProvider, cluster, clock)
 	evictionQueue := terminator.NewQueue(kubeClient, recorder)
@@ -76,10 +85,15 @@ func NewControllers(
 		disruption.NewController(clock, kubeClient, p, cloudProvider, recorder, cluster, disruptionQueue),
 		provisioning.NewPodController(kubeClient, p, cluster),
 		provisioning.NewNodeController(kubeClient, p),
+		provisioning.NewNodePoolController(kubeClient, cloudProvider, p),
 		nodepoolhash.NewController(kubeClient, cloudProvider),
+		nodepooldefaulting.NewController(kubeClient, cloudProvider),
 		expiration.NewController(clock, kubeClient, cloudProvider),
 		informer.NewDaemonSetController(kubeClient, cluster),
+		informer.NewNamespaceController(kubeClient, cluster),
 		informer.NewNodeController(kubeClient, cluster),
+		informer.NewLeaseController(kubeClient, cluster),
+		informer.NewStaleNodeController(clock, mgr.GetAPIReader(), cluster),
 		informer.NewPodController(kubeClient, cluster),
 		informer.NewNodePoolController(kubeClient, cloudProvider, cluster),
 		informer.NewNodeClaimController(kubeClient, cloudProvider, cluster),
@@ -87,21 +101,39 @@ func NewControllers(
 		metricspod.NewController(kubeClient, cluster),
 		metricsnodepool.NewController(kubeClient, cloudProvider),
 		metricsnode.NewController(cluster),
+		metricspricing.NewController(clock, cloudProvider),
 		nodepoolreadiness.NewController(kubeClient, cloudProvider),
-		nodepoolcounter.NewController(kubeClient, cloudProvider, cluster),
+		nodepooldegraded.NewController(clock, kubeClient, cloudProvider, cluster),
+		nodepoolcounter.NewController(clock, kubeClient, cloudProvider, cluster),
+		nodepooldeletionprotection.NewController(kubeClient, cloudProvider, recorder),
 		nodepoolvalidation.NewController(kubeClient, cloudProvider),
+		nodepooloverlap.NewController(kubeClient, cloudProvider, recorder),
+		nodepoolstatic.NewController(kubeClient, mgr.GetAPIReader(), cloudProvider, cluster),
 		podevents.NewController(clock, kubeClient, cloudProvider),
 		nodeclaimconsistency.NewController(clock, kubeClient, cloudProvider, recorder),
-		nodeclaimlifecycle.NewController(clock, kubeClient, cloudProvider, recorder),
-		nodeclaimgarbagecollection.NewController(clock, kubeClient, cloudProvider),
+		nodeclaimlifecycle.NewController(clock, kubeClient, cloudProvider, cluster, recorder),
+		nodeclaimgarbagecollection.NewController(clock, kubeClient, cloudProvider, recorder),
 		nodeclaimdisruption.NewController(clock, kubeClient, cloudProvider),
 		nodeclaimhydration.NewController(kubeClient, cloudProvider),
+		nodeclaiminterruption.NewController(kubeClient, cloudProvider, recorder),
 		nodehydration.NewController(kubeClient, cloudProvider),
 		status.NewController[*v1.NodeClaim](kubeClient, mgr.GetEventRecorderFor("karpenter"), status.EmitDeprecatedMetrics, status.WithLabels(append(lo.Map(cloudProvider.GetSupportedNodeClasses(), func(obj status.Object, _ int) string { return v1.NodeClassLabelKey(object.GVK(obj).GroupKind()) }), v1.NodePoolLabelKey)...)),
 		status.NewController[*v1.NodePool](kubeClient, mgr.GetEventRecorderFor("karpenter"), status.EmitDeprecatedMetrics),
 		status.NewGenericObjectController[*corev1.Node](kubeClient, mgr.GetEventRecorderFor("karpenter"), status.WithLabels(append(lo.Map(cloudProvider.GetSupportedNodeClasses(), func(obj status.Object, _ int) string { return v1.NodeClassLabelKey(object.GVK(obj).GroupKind()) }), v1.NodePoolLabelKey, v1.NodeInitializedLabelKey)...)),
 	}
 
+	if options.FromContext(ctx).EnableDryRunProvisioning {
+		lo.Must0(mgr.AddMetricsServerExtraHandler(provisioning.DryRunPath, provisioning.NewDryRunHandler(ctx, p)))
+	}
+
+	if options.FromContext(ctx).FeatureGates.PodPreBinding {
+		controllers = append(controllers, provisioning.NewBindingController(kubeClient, cloudProvider, cluster, recorder))
+	}
+
+	if options.FromContext(ctx).NodeAnnotationAllowlist != "" {
+		controllers = append(controllers, nodepropagation.NewController(kubeClient, cloudProvider))
+	}
+
 	// The cloud provider must define status conditions for the node repair controller to use to detect unhealthy nodes
 	if len(cloudProvider.RepairPolicies()) != 0 && options.FromContext(ctx).FeatureGates.NodeRepair {
 		controllers = append(controllers, health.NewController(kubeClient, cloudProvider, clock, recorder))
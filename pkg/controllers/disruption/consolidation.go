@@ -21,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 	"sort"
+	"strconv"
 	"time"
 
 	"github.com/samber/lo"
@@ -102,14 +103,16 @@ func (c *consolidation) ShouldDisrupt(_ context.Context, cn *Candidate) bool {
 		c.recorder.Publish(disruptionevents.Unconsolidatable(cn.Node, cn.NodeClaim, fmt.Sprintf("Node does not have label %q", corev1.LabelTopologyZone))...)
 		return false
 	}
-	if cn.nodePool.Spec.Disruption.ConsolidateAfter.Duration == nil {
+	// The NodePool's consolidation settings can be overridden for the candidate's capacity type
+	disruption := cn.nodePool.Spec.Disruption.ForCapacityType(cn.Labels()[v1.CapacityTypeLabelKey])
+	if disruption.ConsolidateAfter.Duration == nil {
 		c.recorder.Publish(disruptionevents.Unconsolidatable(cn.Node, cn.NodeClaim, fmt.Sprintf("NodePool %q has consolidation disabled", cn.nodePool.Name))...)
 		return false
 	}
 	// If we don't have the "WhenEmptyOrUnderutilized" policy set, we should not do any of the consolidation methods, but
 	// we should also not fire an event here to users since this can be confusing when the field on the NodePool
 	// is named "consolidationPolicy"
-	if cn.nodePool.Spec.Disruption.ConsolidationPolicy != v1.ConsolidationPolicyWhenEmptyOrUnderutilized {
+	if disruption.ConsolidationPolicy != v1.ConsolidationPolicyWhenEmptyOrUnderutilized {
 		c.recorder.Publish(disruptionevents.Unconsolidatable(cn.Node, cn.NodeClaim, fmt.Sprintf("NodePool %q has non-empty consolidation disabled", cn.nodePool.Name))...)
 		return false
 	}
@@ -301,10 +304,15 @@ func (c *consolidation) computeSpotToSpotConsolidation(ctx context.Context, cand
 	}, results, nil
 }
 
-// getCandidatePrices returns the sum of the prices of the given candidates
+// getCandidatePrices returns the sum of the prices of the given candidates. Candidates are priced at the price that
+// they launched at when it was recorded, and at the price of their offering otherwise.
 func getCandidatePrices(candidates []*Candidate) (float64, error) {
 	var price float64
 	for _, c := range candidates {
+		if launchPrice, ok := launchedPrice(c.NodeClaim); ok {
+			price += launchPrice
+			continue
+		}
 		compatibleOfferings := c.instanceType.Offerings.Compatible(scheduling.NewLabelRequirements(c.StateNode.Labels()))
 		if len(compatibleOfferings) == 0 {
 			return 0.0, fmt.Errorf("unable to determine offering for %s/%s/%s", c.instanceType.Name, c.capacityType, c.zone)
@@ -313,3 +321,12 @@ func getCandidatePrices(candidates []*Candidate) (float64, error) {
 	}
 	return price, nil
 }
+
+// launchedPrice returns the price that the NodeClaim's instance launched at, if it was recorded
+func launchedPrice(nodeClaim *v1.NodeClaim) (float64, bool) {
+	if nodeClaim == nil || nodeClaim.Status.LaunchedInstance == nil || nodeClaim.Status.LaunchedInstance.Price == "" {
+		return 0, false
+	}
+	price, err := strconv.ParseFloat(nodeClaim.Status.LaunchedInstance.Price, 64)
+	return price, err == nil
+}
@@ -17,8 +17,11 @@ limitations under the License.
 package disruption_test
 
 import (
+	"encoding/json"
 	"fmt"
 	"math/rand"
+	"net/http"
+	"net/http/httptest"
 	"sort"
 	"strings"
 	"sync"
@@ -929,6 +932,70 @@ var _ = Describe("Consolidation", func() {
 			Entry("if the candidate is on-demand node", false),
 			Entry("if the candidate is spot node", true),
 		)
+		It("cannot replace node when the scheduling extender rejects all of the replacement instance types", func() {
+			var calls atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				defer GinkgoRecover()
+				a := pscheduling.ExtenderArgs{}
+				Expect(json.NewDecoder(r.Body).Decode(&a)).To(Succeed())
+				calls.Add(1)
+				Expect(json.NewEncoder(w).Encode(pscheduling.ExtenderResult{NodeClaims: lo.Map(a.NodeClaims, func(_ pscheduling.ExtenderNodeClaim, _ int) pscheduling.ExtenderNodeClaimResult {
+					return pscheduling.ExtenderNodeClaimResult{InstanceTypes: []string{}}
+				})})).To(Succeed())
+			}))
+			DeferCleanup(server.Close)
+			ctx = options.ToContext(ctx, test.Options(test.OptionsFields{SchedulingExtenderURL: lo.ToPtr(server.URL), SchedulingExtenderDisruption: lo.ToPtr(true)}))
+
+			pod := test.Pod(test.PodOptions{ObjectMeta: metav1.ObjectMeta{Labels: labels}})
+			ExpectApplied(ctx, env.Client, pod, node, nodeClaim, nodePool)
+			ExpectManualBinding(ctx, env.Client, pod, node)
+			ExpectMakeNodesAndNodeClaimsInitializedAndStateUpdated(ctx, env.Client, nodeStateController, nodeClaimStateController, []*corev1.Node{node}, []*v1.NodeClaim{nodeClaim})
+
+			fakeClock.Step(10 * time.Minute)
+			ExpectSingletonReconciled(ctx, disruptionController)
+
+			// the simulation asked the extender, which left no instance type to replace the node with
+			Expect(calls.Load()).To(BeNumerically(">", 0))
+			Expect(ExpectNodeClaims(ctx, env.Client)).To(HaveLen(1))
+			ExpectExists(ctx, env.Client, nodeClaim)
+			ExpectExists(ctx, env.Client, node)
+		})
+		It("does not call the scheduling extender unless disruption opts into it", func() {
+			var calls atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				defer GinkgoRecover()
+				a := pscheduling.ExtenderArgs{}
+				Expect(json.NewDecoder(r.Body).Decode(&a)).To(Succeed())
+				calls.Add(1)
+				Expect(json.NewEncoder(w).Encode(pscheduling.ExtenderResult{NodeClaims: lo.Map(a.NodeClaims, func(_ pscheduling.ExtenderNodeClaim, _ int) pscheduling.ExtenderNodeClaimResult {
+					return pscheduling.ExtenderNodeClaimResult{InstanceTypes: []string{}}
+				})})).To(Succeed())
+			}))
+			DeferCleanup(server.Close)
+			ctx = options.ToContext(ctx, test.Options(test.OptionsFields{SchedulingExtenderURL: lo.ToPtr(server.URL)}))
+
+			pod := test.Pod(test.PodOptions{ObjectMeta: metav1.ObjectMeta{Labels: labels}})
+			ExpectApplied(ctx, env.Client, pod, node, nodeClaim, nodePool)
+			ExpectManualBinding(ctx, env.Client, pod, node)
+			ExpectMakeNodesAndNodeClaimsInitializedAndStateUpdated(ctx, env.Client, nodeStateController, nodeClaimStateController, []*corev1.Node{node}, []*v1.NodeClaim{nodeClaim})
+
+			fakeClock.Step(10 * time.Minute)
+
+			var wg sync.WaitGroup
+			ExpectToWait(fakeClock, &wg)
+			ExpectMakeNewNodeClaimsReady(ctx, env.Client, &wg, cluster, cloudProvider, 1)
+			ExpectSingletonReconciled(ctx, disruptionController)
+			wg.Wait()
+			ExpectSingletonReconciled(ctx, queue)
+			ExpectNodeClaimsCascadeDeletion(ctx, env.Client, nodeClaim)
+
+			// the simulation didn't ask the extender, so the node is replaced
+			Expect(calls.Load()).To(BeNumerically("==", 0))
+			nodeClaims := ExpectNodeClaims(ctx, env.Client)
+			Expect(nodeClaims).To(HaveLen(1))
+			Expect(nodeClaims[0].Name).ToNot(Equal(nodeClaim.Name))
+			ExpectNotFound(ctx, env.Client, nodeClaim, node)
+		})
 		It("cannot replace spot with spot if less than minimum InstanceTypes flexibility", func() {
 			// Forcefully shrink the possible instanceTypes to be lower than 15 to replace a nodeclaim
 			cloudProvider.InstanceTypes = lo.Slice(fake.InstanceTypesAssorted(), 0, 5)
@@ -2230,6 +2297,89 @@ var _ = Describe("Consolidation", func() {
 			ExpectExists(ctx, env.Client, nodeClaim)
 			ExpectExists(ctx, env.Client, node)
 		})
+		It("won't replace on-demand node if it launched at a lower price than the on-demand replacement", func() {
+			currentInstance := fake.NewInstanceType(fake.InstanceTypeOptions{
+				Name: "current-on-demand",
+				Offerings: []cloudprovider.Offering{
+					{
+						Requirements: scheduling.NewLabelRequirements(map[string]string{v1.CapacityTypeLabelKey: v1.CapacityTypeOnDemand, corev1.LabelTopologyZone: "test-zone-1a"}),
+						Price:        0.7,
+						Available:    false,
+					},
+				},
+			})
+			replacementInstance := fake.NewInstanceType(fake.InstanceTypeOptions{
+				Name: "on-demand-replacement",
+				Offerings: []cloudprovider.Offering{
+					{
+						Requirements: scheduling.NewLabelRequirements(map[string]string{v1.CapacityTypeLabelKey: v1.CapacityTypeOnDemand, corev1.LabelTopologyZone: "test-zone-1a"}),
+						Price:        0.6,
+						Available:    true,
+					},
+				},
+			})
+			cloudProvider.InstanceTypes = []*cloudprovider.InstanceType{
+				currentInstance,
+				replacementInstance,
+			}
+
+			rs := test.ReplicaSet()
+			ExpectApplied(ctx, env.Client, rs)
+			Expect(env.Client.Get(ctx, client.ObjectKeyFromObject(rs), rs)).To(Succeed())
+			pod := test.Pod(test.PodOptions{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels,
+					OwnerReferences: []metav1.OwnerReference{
+						{
+							APIVersion:         "apps/v1",
+							Kind:               "ReplicaSet",
+							Name:               rs.Name,
+							UID:                rs.UID,
+							Controller:         lo.ToPtr(true),
+							BlockOwnerDeletion: lo.ToPtr(true),
+						},
+					}}})
+			nodePool.Spec.Template.Spec.Requirements = []v1.NodeSelectorRequirementWithMinValues{
+				{
+					NodeSelectorRequirement: corev1.NodeSelectorRequirement{
+						Key:      v1.CapacityTypeLabelKey,
+						Operator: corev1.NodeSelectorOpIn,
+						Values:   []string{v1.CapacityTypeOnDemand},
+					},
+				},
+			}
+			nodeClaim, node = test.NodeClaimAndNode(v1.NodeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						v1.NodePoolLabelKey:            nodePool.Name,
+						corev1.LabelInstanceTypeStable: currentInstance.Name,
+						v1.CapacityTypeLabelKey:        v1.CapacityTypeOnDemand,
+						corev1.LabelTopologyZone:       "test-zone-1a",
+					},
+				},
+				Status: v1.NodeClaimStatus{
+					Allocatable: map[corev1.ResourceName]resource.Quantity{corev1.ResourceCPU: resource.MustParse("32")},
+					// the instance launched before the price of its offering went up
+					LaunchedInstance: &v1.LaunchedInstance{
+						InstanceType: currentInstance.Name,
+						Zone:         "test-zone-1a",
+						CapacityType: v1.CapacityTypeOnDemand,
+						Price:        "0.5",
+						Currency:     "USD",
+					},
+				},
+			})
+			ExpectApplied(ctx, env.Client, rs, pod, nodeClaim, node, nodePool)
+			ExpectManualBinding(ctx, env.Client, pod, node)
+			ExpectMakeNodesAndNodeClaimsInitializedAndStateUpdated(ctx, env.Client, nodeStateController, nodeClaimStateController, []*corev1.Node{node}, []*v1.NodeClaim{nodeClaim})
+
+			fakeClock.Step(10 * time.Minute)
+			ExpectSingletonReconciled(ctx, disruptionController)
+
+			Expect(ExpectNodeClaims(ctx, env.Client)).To(HaveLen(1))
+			Expect(ExpectNodes(ctx, env.Client)).To(HaveLen(1))
+			ExpectExists(ctx, env.Client, nodeClaim)
+			ExpectExists(ctx, env.Client, node)
+		})
 	})
 	Context("Delete", func() {
 		var nodeClaims []*v1.NodeClaim
@@ -2301,6 +2451,50 @@ var _ = Describe("Consolidation", func() {
 			// and delete the old one
 			ExpectNotFound(ctx, env.Client, nodeClaims[1], nodes[1])
 		})
+		It("won't delete nodes if the pods don't fit on the remaining nodes with the nodePool's simulated pod requests", func() {
+			nodePool.Spec.SimulatedPodRequests = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("16")}
+			rs := test.ReplicaSet()
+			ExpectApplied(ctx, env.Client, rs)
+			ownerRefs := []metav1.OwnerReference{
+				{
+					APIVersion:         "apps/v1",
+					Kind:               "ReplicaSet",
+					Name:               rs.Name,
+					UID:                rs.UID,
+					Controller:         lo.ToPtr(true),
+					BlockOwnerDeletion: lo.ToPtr(true),
+				},
+			}
+			// the pod on the first node can't be disrupted and leaves only 12 CPU free on the node
+			blockingPod := test.Pod(test.PodOptions{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:          labels,
+					OwnerReferences: ownerRefs,
+					Annotations:     map[string]string{v1.DoNotDisruptAnnotationKey: "true"},
+				},
+				ResourceRequirements: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("20")},
+				},
+			})
+			// the pod on the second node doesn't request any resources, so it's simulated as requesting 16 CPU
+			pod := test.Pod(test.PodOptions{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels, OwnerReferences: ownerRefs},
+			})
+			ExpectApplied(ctx, env.Client, rs, blockingPod, pod, nodeClaims[0], nodes[0], nodeClaims[1], nodes[1], nodePool)
+
+			ExpectManualBinding(ctx, env.Client, blockingPod, nodes[0])
+			ExpectManualBinding(ctx, env.Client, pod, nodes[1])
+
+			ExpectMakeNodesAndNodeClaimsInitializedAndStateUpdated(ctx, env.Client, nodeStateController, nodeClaimStateController, []*corev1.Node{nodes[0], nodes[1]}, []*v1.NodeClaim{nodeClaims[0], nodeClaims[1]})
+
+			fakeClock.Step(10 * time.Minute)
+			ExpectSingletonReconciled(ctx, disruptionController)
+
+			Expect(ExpectNodeClaims(ctx, env.Client)).To(HaveLen(2))
+			Expect(ExpectNodes(ctx, env.Client)).To(HaveLen(2))
+			ExpectExists(ctx, env.Client, nodeClaims[1])
+			ExpectExists(ctx, env.Client, nodes[1])
+		})
 		It("can delete nodes if another nodePool has no node template", func() {
 			// create our RS so we can link a pod to it
 			rs := test.ReplicaSet()
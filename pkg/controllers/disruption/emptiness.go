@@ -43,7 +43,7 @@ func NewEmptiness(c consolidation) *Emptiness {
 // ShouldDisrupt is a predicate used to filter candidates
 func (e *Emptiness) ShouldDisrupt(_ context.Context, c *Candidate) bool {
 	// If consolidation is disabled, don't do anything. This emptiness should run for both WhenEmpty and WhenEmptyOrUnderutilized
-	if c.nodePool.Spec.Disruption.ConsolidateAfter.Duration == nil {
+	if c.nodePool.Spec.Disruption.ForCapacityType(c.Labels()[v1.CapacityTypeLabelKey]).ConsolidateAfter.Duration == nil {
 		e.recorder.Publish(disruptionevents.Unconsolidatable(c.Node, c.NodeClaim, fmt.Sprintf("NodePool %q has consolidation disabled", c.nodePool.Name))...)
 		return false
 	}
@@ -62,10 +62,32 @@ func (e *Emptiness) ComputeCommand(ctx context.Context, disruptionBudgetMapping
 
 	empty := make([]*Candidate, 0, len(candidates))
 	constrainedByBudgets := false
+	// Empty nodes are deleted without simulating scheduling, so the minimum nodes and minimum nodes per zone of each
+	// NodePool are enforced by tracking how many nodes can still be removed from each NodePool and zone
+	removableNodes := map[string]int{}
+	removableNodesPerZone := map[string]map[string]int{}
 	for _, candidate := range candidates {
 		if len(candidate.reschedulablePods) > 0 {
 			continue
 		}
+		if minNodes := candidate.nodePool.Spec.MinNodes; minNodes != nil {
+			if _, ok := removableNodes[candidate.nodePool.Name]; !ok {
+				removableNodes[candidate.nodePool.Name] = scheduling.NodeCount(e.cluster.Nodes(), candidate.nodePool.Name) - int(*minNodes)
+			}
+			if removableNodes[candidate.nodePool.Name] <= 0 {
+				continue
+			}
+		}
+		if minNodes := candidate.nodePool.Spec.MinNodesPerZone; minNodes != nil {
+			if _, ok := removableNodesPerZone[candidate.nodePool.Name]; !ok {
+				removableNodesPerZone[candidate.nodePool.Name] = lo.MapValues(scheduling.NodesPerZone(e.cluster.Nodes(), candidate.nodePool.Name), func(count int, _ string) int {
+					return count - int(*minNodes)
+				})
+			}
+			if removableNodesPerZone[candidate.nodePool.Name][candidate.zone] <= 0 {
+				continue
+			}
+		}
 		if disruptionBudgetMapping[candidate.nodePool.Name] == 0 {
 			// set constrainedByBudgets to true if any node was a candidate but was constrained by a budget
 			constrainedByBudgets = true
@@ -75,6 +97,12 @@ func (e *Emptiness) ComputeCommand(ctx context.Context, disruptionBudgetMapping
 		// add it to the list of candidates, and decrement the budget.
 		empty = append(empty, candidate)
 		disruptionBudgetMapping[candidate.nodePool.Name]--
+		if candidate.nodePool.Spec.MinNodes != nil {
+			removableNodes[candidate.nodePool.Name]--
+		}
+		if candidate.nodePool.Spec.MinNodesPerZone != nil {
+			removableNodesPerZone[candidate.nodePool.Name][candidate.zone]--
+		}
 	}
 	// none empty, so do nothing
 	if len(empty) == 0 {
@@ -125,6 +125,17 @@ func NodePoolBlockedForDisruptionReason(nodePool *v1.NodePool, reason v1.Disrupt
 	}
 }
 
+// NodePoolPaused is published for a paused NodePool, rather than a Blocked event for each of its nodes
+func NodePoolPaused(nodePool *v1.NodePool) events.Event {
+	return events.Event{
+		InvolvedObject: nodePool,
+		Type:           corev1.EventTypeNormal,
+		Reason:         "DisruptionBlocked",
+		Message:        "No allowed disruptions as the NodePool is paused",
+		DedupeValues:   []string{string(nodePool.UID), "paused"},
+	}
+}
+
 func NodePoolBlocked(nodePool *v1.NodePool) events.Event {
 	return events.Event{
 		InvolvedObject: nodePool,
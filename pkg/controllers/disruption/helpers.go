@@ -38,7 +38,7 @@ import (
 	"sigs.k8s.io/karpenter/pkg/events"
 	"sigs.k8s.io/karpenter/pkg/metrics"
 	operatorlogging "sigs.k8s.io/karpenter/pkg/operator/logging"
-	nodeutils "sigs.k8s.io/karpenter/pkg/utils/node"
+	"sigs.k8s.io/karpenter/pkg/operator/options"
 	nodepoolutils "sigs.k8s.io/karpenter/pkg/utils/nodepool"
 	"sigs.k8s.io/karpenter/pkg/utils/pdb"
 )
@@ -79,6 +79,12 @@ func SimulateScheduling(ctx context.Context, kubeClient client.Client, cluster *
 		pods = append(pods, n.reschedulablePods...)
 	}
 	pods = append(pods, deletingNodePods...)
+	// include the headroom so that disruption doesn't remove the spare capacity that provisioning keeps available
+	headroomPods, err := provisioner.GetHeadroomPods(ctx)
+	if err != nil {
+		return pscheduling.Results{}, fmt.Errorf("determining headroom pods, %w", err)
+	}
+	pods = append(pods, headroomPods...)
 	scheduler, err := provisioner.NewScheduler(log.IntoContext(ctx, operatorlogging.NopLogger), pods, stateNodes)
 	if err != nil {
 		return pscheduling.Results{}, fmt.Errorf("creating scheduler, %w", err)
@@ -88,7 +94,14 @@ func SimulateScheduling(ctx context.Context, kubeClient client.Client, cluster *
 		return client.ObjectKeyFromObject(p), nil
 	})
 
-	results := scheduler.Solve(log.IntoContext(ctx, operatorlogging.NopLogger), pods).TruncateInstanceTypes(pscheduling.MaxInstanceTypes)
+	results := scheduler.Solve(log.IntoContext(ctx, operatorlogging.NopLogger), pods)
+	// the extender is called for every candidate that's simulated, so it's only called when disruption opts into it
+	if options.FromContext(ctx).SchedulingExtenderDisruption {
+		if results, err = provisioner.Extend(ctx, results); err != nil {
+			return pscheduling.Results{}, fmt.Errorf("extending scheduling results, %w", err)
+		}
+	}
+	results = results.TruncateInstanceTypes(pscheduling.MaxInstanceTypes)
 	for _, n := range results.ExistingNodes {
 		// We consider existing nodes for scheduling. When these nodes are unmanaged, their taint logic should
 		// tell us if we can schedule to them or not; however, if these nodes are managed, we will still schedule to them
@@ -167,12 +180,16 @@ func BuildNodePoolMap(ctx context.Context, kubeClient client.Client, cloudProvid
 	if err != nil {
 		return nil, nil, fmt.Errorf("listing node pools, %w", err)
 	}
+	overlays := &v1.NodeOverlayList{}
+	if err := kubeClient.List(ctx, overlays); err != nil {
+		return nil, nil, fmt.Errorf("listing node overlays, %w", err)
+	}
 
 	nodePoolToInstanceTypesMap := map[string]map[string]*cloudprovider.InstanceType{}
 	for _, np := range nodePools {
 		nodePoolMap[np.Name] = np
 
-		nodePoolInstanceTypes, err := cloudProvider.GetInstanceTypes(ctx, np)
+		nodePoolInstanceTypes, err := cloudProvider.GetInstanceTypes(ctx, np.WithActiveNodeClassRef())
 		if err != nil {
 			// don't error out on building the node pool, we just won't be able to handle any nodes that
 			// were created by it
@@ -183,7 +200,9 @@ func BuildNodePoolMap(ctx context.Context, kubeClient client.Client, cloudProvid
 			continue
 		}
 		nodePoolToInstanceTypesMap[np.Name] = map[string]*cloudprovider.InstanceType{}
-		for _, it := range nodePoolInstanceTypes {
+		// Overlays are applied after the kubelet configuration, as they are when provisioning
+		nodePoolInstanceTypes = cloudprovider.InstanceTypes(nodePoolInstanceTypes).WithKubeletConfiguration(np.Spec.Template.Spec.Kubelet)
+		for _, it := range cloudprovider.InstanceTypes(nodePoolInstanceTypes).WithOverlays(lo.ToSlicePtr(overlays.Items)) {
 			nodePoolToInstanceTypesMap[np.Name][it.Name] = it
 		}
 	}
@@ -196,37 +215,7 @@ func BuildNodePoolMap(ctx context.Context, kubeClient client.Client, cloudProvid
 //nolint:gocyclo
 func BuildDisruptionBudgetMapping(ctx context.Context, cluster *state.Cluster, clk clock.Clock, kubeClient client.Client, cloudProvider cloudprovider.CloudProvider, recorder events.Recorder, reason v1.DisruptionReason) (map[string]int, error) {
 	disruptionBudgetMapping := map[string]int{}
-	numNodes := map[string]int{}   // map[nodepool] -> node count in nodepool
-	disrupting := map[string]int{} // map[nodepool] -> nodes undergoing disruption
-	for _, node := range cluster.Nodes() {
-		// We only consider nodes that we own and are initialized towards the total.
-		// If a node is launched/registered, but not initialized, pods aren't scheduled
-		// to the node, and these are treated as unhealthy until they're cleaned up.
-		// This prevents odd roundup cases with percentages where replacement nodes that
-		// aren't initialized could be counted towards the total, resulting in more disruptions
-		// to active nodes than desired, where Karpenter should wait for these nodes to be
-		// healthy before continuing.
-		if !node.Managed() || !node.Initialized() {
-			continue
-		}
-
-		// Additionally, don't consider nodeclaims that have the terminating condition. A nodeclaim should have
-		// the Terminating condition only when the node is drained and cloudprovider.Delete() was successful
-		// on the underlying cloud provider machine.
-		if node.NodeClaim.StatusConditions().Get(v1.ConditionTypeInstanceTerminating).IsTrue() {
-			continue
-		}
-
-		nodePool := node.Labels()[v1.NodePoolLabelKey]
-		numNodes[nodePool]++
-
-		// If the node satisfies one of the following, we subtract it from the allowed disruptions.
-		// 1. Has a NotReady conditiion
-		// 2. Is marked as disrupting
-		if cond := nodeutils.GetCondition(node.Node, corev1.NodeReady); cond.Status != corev1.ConditionTrue || node.MarkedForDeletion() {
-			disrupting[nodePool]++
-		}
-	}
+	numNodes, disrupting := cluster.DisruptionCounts()
 	nodePools, err := nodepoolutils.ListManaged(ctx, kubeClient, cloudProvider)
 	if err != nil {
 		return disruptionBudgetMapping, fmt.Errorf("listing node pools, %w", err)
@@ -0,0 +1,68 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package disruption
+
+import (
+	"context"
+	"sort"
+
+	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+	"sigs.k8s.io/karpenter/pkg/controllers/provisioning/scheduling"
+)
+
+// Interruption is a subreconciler that deletes the candidates whose instances the cloud provider is going to interrupt.
+// Replacements aren't launched up front, since the instances may be reclaimed before they'd initialize, so the pods of
+// the candidates are provisioned for as they're drained.
+type Interruption struct{}
+
+func NewInterruption() *Interruption {
+	return &Interruption{}
+}
+
+// ShouldDisrupt is a predicate used to filter candidates
+func (i *Interruption) ShouldDisrupt(_ context.Context, c *Candidate) bool {
+	return c.NodeClaim.StatusConditions().Get(v1.ConditionTypeInterrupted).IsTrue()
+}
+
+// ComputeCommand generates a disruption command given candidates
+func (i *Interruption) ComputeCommand(_ context.Context, disruptionBudgetMapping map[string]int, candidates ...*Candidate) (Command, scheduling.Results, error) {
+	sort.Slice(candidates, func(a int, b int) bool {
+		return candidates[a].NodeClaim.StatusConditions().Get(v1.ConditionTypeInterrupted).LastTransitionTime.Time.Before(
+			candidates[b].NodeClaim.StatusConditions().Get(v1.ConditionTypeInterrupted).LastTransitionTime.Time)
+	})
+	interrupted := make([]*Candidate, 0, len(candidates))
+	for _, candidate := range candidates {
+		if disruptionBudgetMapping[candidate.nodePool.Name] > 0 {
+			interrupted = append(interrupted, candidate)
+			disruptionBudgetMapping[candidate.nodePool.Name]--
+		}
+	}
+	return Command{
+		candidates: interrupted,
+	}, scheduling.Results{}, nil
+}
+
+func (i *Interruption) Reason() v1.DisruptionReason {
+	return v1.DisruptionReasonInterrupted
+}
+
+func (i *Interruption) Class() string {
+	return EventualDisruptionClass
+}
+
+func (i *Interruption) ConsolidationType() string {
+	return ""
+}
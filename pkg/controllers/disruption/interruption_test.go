@@ -0,0 +1,178 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package disruption_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+	"sigs.k8s.io/karpenter/pkg/test"
+	. "sigs.k8s.io/karpenter/pkg/test/expectations"
+)
+
+var _ = Describe("Interruption", func() {
+	var nodePool *v1.NodePool
+	var nodeClaim *v1.NodeClaim
+	var node *corev1.Node
+	var labels map[string]string
+
+	BeforeEach(func() {
+		nodePool = test.NodePool(v1.NodePool{
+			Spec: v1.NodePoolSpec{
+				Disruption: v1.Disruption{
+					ConsolidateAfter: v1.MustParseNillableDuration("Never"),
+					Budgets: []v1.Budget{{
+						Nodes: "100%",
+					}},
+				},
+			},
+		})
+		labels = map[string]string{
+			v1.NodePoolLabelKey:            nodePool.Name,
+			corev1.LabelInstanceTypeStable: mostExpensiveInstance.Name,
+			v1.CapacityTypeLabelKey:        mostExpensiveOffering.Requirements.Get(v1.CapacityTypeLabelKey).Any(),
+			corev1.LabelTopologyZone:       mostExpensiveOffering.Requirements.Get(corev1.LabelTopologyZone).Any(),
+		}
+		nodeClaim, node = test.NodeClaimAndNode(v1.NodeClaim{
+			ObjectMeta: metav1.ObjectMeta{Labels: labels},
+			Status: v1.NodeClaimStatus{
+				ProviderID: test.RandomProviderID(),
+				Allocatable: map[corev1.ResourceName]resource.Quantity{
+					corev1.ResourceCPU:  resource.MustParse("32"),
+					corev1.ResourcePods: resource.MustParse("100"),
+				},
+			},
+		})
+		nodeClaim.StatusConditions().SetTrueWithReason(v1.ConditionTypeInterrupted, "SpotInterruption", "Instance is going to be interrupted")
+	})
+	It("should delete interrupted nodes without launching replacements", func() {
+		pod := test.Pod()
+		ExpectApplied(ctx, env.Client, nodePool, nodeClaim, node, pod)
+		ExpectManualBinding(ctx, env.Client, pod, node)
+
+		// inform cluster state about nodes and nodeclaims
+		ExpectMakeNodesAndNodeClaimsInitializedAndStateUpdated(ctx, env.Client, nodeStateController, nodeClaimStateController, []*corev1.Node{node}, []*v1.NodeClaim{nodeClaim})
+
+		ExpectSingletonReconciled(ctx, disruptionController)
+		ExpectSingletonReconciled(ctx, queue)
+		// Cascade any deletion of the nodeClaim to the node
+		ExpectNodeClaimsCascadeDeletion(ctx, env.Client, nodeClaim)
+
+		Expect(cloudProvider.CreateCalls).To(HaveLen(0))
+		Expect(ExpectNodeClaims(ctx, env.Client)).To(HaveLen(0))
+		ExpectNotFound(ctx, env.Client, nodeClaim, node)
+	})
+	DescribeTable("should delete interrupted nodes with pods that block eviction",
+		func(blocking func() []client.Object) {
+			objs := blocking()
+			pod := objs[0].(*corev1.Pod)
+			ExpectApplied(ctx, env.Client, append([]client.Object{nodePool, nodeClaim, node}, objs...)...)
+			ExpectManualBinding(ctx, env.Client, pod, node)
+
+			// inform cluster state about nodes and nodeclaims
+			ExpectMakeNodesAndNodeClaimsInitializedAndStateUpdated(ctx, env.Client, nodeStateController, nodeClaimStateController, []*corev1.Node{node}, []*v1.NodeClaim{nodeClaim})
+
+			ExpectSingletonReconciled(ctx, disruptionController)
+			ExpectSingletonReconciled(ctx, queue)
+			ExpectNodeClaimsCascadeDeletion(ctx, env.Client, nodeClaim)
+
+			ExpectNotFound(ctx, env.Client, nodeClaim, node)
+		},
+		Entry("do-not-disrupt pods", func() []client.Object {
+			return []client.Object{test.Pod(test.PodOptions{ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{v1.DoNotDisruptAnnotationKey: "true"},
+			}})}
+		}),
+		Entry("PDB-blocked pods", func() []client.Object {
+			podLabels := map[string]string{"test": "value"}
+			return []client.Object{
+				test.Pod(test.PodOptions{ObjectMeta: metav1.ObjectMeta{Labels: podLabels}}),
+				test.PodDisruptionBudget(test.PDBOptions{Labels: podLabels, MaxUnavailable: fromInt(0)}),
+			}
+		}),
+	)
+	It("should ignore nodes without the interrupted status condition", func() {
+		nodeClaim.StatusConditions().Clear(v1.ConditionTypeInterrupted)
+		ExpectApplied(ctx, env.Client, nodePool, nodeClaim, node)
+
+		// inform cluster state about nodes and nodeclaims
+		ExpectMakeNodesAndNodeClaimsInitializedAndStateUpdated(ctx, env.Client, nodeStateController, nodeClaimStateController, []*corev1.Node{node}, []*v1.NodeClaim{nodeClaim})
+
+		fakeClock.Step(10 * time.Minute)
+		ExpectSingletonReconciled(ctx, disruptionController)
+
+		Expect(ExpectNodeClaims(ctx, env.Client)).To(HaveLen(1))
+		ExpectExists(ctx, env.Client, nodeClaim)
+	})
+	It("should only disrupt as many nodes as the interrupted budget allows", func() {
+		nodeClaims, nodes := test.NodeClaimsAndNodes(10, v1.NodeClaim{
+			ObjectMeta: metav1.ObjectMeta{Labels: labels},
+			Status: v1.NodeClaimStatus{
+				Allocatable: map[corev1.ResourceName]resource.Quantity{
+					corev1.ResourceCPU:  resource.MustParse("32"),
+					corev1.ResourcePods: resource.MustParse("100"),
+				},
+			},
+		})
+		nodePool.Spec.Disruption.Budgets = []v1.Budget{
+			{Nodes: "0%", Reasons: []v1.DisruptionReason{v1.DisruptionReasonDrifted}},
+			{Nodes: "30%", Reasons: []v1.DisruptionReason{v1.DisruptionReasonInterrupted}},
+		}
+		ExpectApplied(ctx, env.Client, nodePool)
+		for i := range nodeClaims {
+			nodeClaims[i].StatusConditions().SetTrueWithReason(v1.ConditionTypeInterrupted, "SpotInterruption", "Instance is going to be interrupted")
+			ExpectApplied(ctx, env.Client, nodeClaims[i], nodes[i])
+		}
+		// inform cluster state about nodes and nodeclaims
+		ExpectMakeNodesAndNodeClaimsInitializedAndStateUpdated(ctx, env.Client, nodeStateController, nodeClaimStateController, nodes, nodeClaims)
+		ExpectSingletonReconciled(ctx, disruptionController)
+
+		// Execute command, thus deleting 3 nodes
+		ExpectSingletonReconciled(ctx, queue)
+		Expect(len(ExpectNodeClaims(ctx, env.Client))).To(Equal(7))
+	})
+	It("should disrupt interrupted nodes before drifted nodes", func() {
+		drifted, driftedNode := test.NodeClaimAndNode(v1.NodeClaim{
+			ObjectMeta: metav1.ObjectMeta{Labels: labels},
+			Status: v1.NodeClaimStatus{
+				ProviderID: test.RandomProviderID(),
+				Allocatable: map[corev1.ResourceName]resource.Quantity{
+					corev1.ResourceCPU:  resource.MustParse("32"),
+					corev1.ResourcePods: resource.MustParse("100"),
+				},
+			},
+		})
+		drifted.StatusConditions().SetTrue(v1.ConditionTypeDrifted)
+		ExpectApplied(ctx, env.Client, nodePool, nodeClaim, node, drifted, driftedNode)
+
+		// inform cluster state about nodes and nodeclaims
+		ExpectMakeNodesAndNodeClaimsInitializedAndStateUpdated(ctx, env.Client, nodeStateController, nodeClaimStateController, []*corev1.Node{node, driftedNode}, []*v1.NodeClaim{nodeClaim, drifted})
+
+		ExpectSingletonReconciled(ctx, disruptionController)
+		ExpectSingletonReconciled(ctx, queue)
+		ExpectNodeClaimsCascadeDeletion(ctx, env.Client, nodeClaim)
+
+		ExpectNotFound(ctx, env.Client, nodeClaim, node)
+		ExpectExists(ctx, env.Client, drifted)
+	})
+})
@@ -17,7 +17,6 @@ limitations under the License.
 package disruption
 
 import (
-	opmetrics "github.com/awslabs/operatorpkg/metrics"
 	"github.com/prometheus/client_golang/prometheus"
 	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 
@@ -36,7 +35,7 @@ func init() {
 }
 
 var (
-	EvaluationDurationSeconds = opmetrics.NewPrometheusHistogram(
+	EvaluationDurationSeconds = metrics.NewPrometheusHistogram(
 		crmetrics.Registry,
 		prometheus.HistogramOpts{
 			Namespace: metrics.Namespace,
@@ -47,7 +46,7 @@ var (
 		},
 		[]string{metrics.ReasonLabel, consolidationTypeLabel},
 	)
-	DecisionsPerformedTotal = opmetrics.NewPrometheusCounter(
+	DecisionsPerformedTotal = metrics.NewPrometheusCounter(
 		crmetrics.Registry,
 		prometheus.CounterOpts{
 			Namespace: metrics.Namespace,
@@ -57,7 +56,7 @@ var (
 		},
 		[]string{decisionLabel, metrics.ReasonLabel, consolidationTypeLabel},
 	)
-	EligibleNodes = opmetrics.NewPrometheusGauge(
+	EligibleNodes = metrics.NewPrometheusGauge(
 		crmetrics.Registry,
 		prometheus.GaugeOpts{
 			Namespace: metrics.Namespace,
@@ -67,7 +66,7 @@ var (
 		},
 		[]string{metrics.ReasonLabel},
 	)
-	ConsolidationTimeoutsTotal = opmetrics.NewPrometheusCounter(
+	ConsolidationTimeoutsTotal = metrics.NewPrometheusCounter(
 		crmetrics.Registry,
 		prometheus.CounterOpts{
 			Namespace: metrics.Namespace,
@@ -77,7 +76,7 @@ var (
 		},
 		[]string{consolidationTypeLabel},
 	)
-	NodePoolAllowedDisruptions = opmetrics.NewPrometheusGauge(
+	NodePoolAllowedDisruptions = metrics.NewPrometheusGauge(
 		crmetrics.Registry,
 		prometheus.GaugeOpts{
 			Namespace: metrics.Namespace,
@@ -17,7 +17,6 @@ limitations under the License.
 package orchestration
 
 import (
-	opmetrics "github.com/awslabs/operatorpkg/metrics"
 	"github.com/prometheus/client_golang/prometheus"
 	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 
@@ -31,7 +30,7 @@ const (
 )
 
 var (
-	DisruptionQueueFailuresTotal = opmetrics.NewPrometheusCounter(
+	DisruptionQueueFailuresTotal = metrics.NewPrometheusCounter(
 		crmetrics.Registry,
 		prometheus.CounterOpts{
 			Namespace: metrics.Namespace,
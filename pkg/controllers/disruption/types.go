@@ -90,13 +90,24 @@ func NewCandidate(ctx context.Context, kubeClient client.Client, recorder events
 		recorder.Publish(disruptionevents.Blocked(node.Node, node.NodeClaim, fmt.Sprintf("NodePool %q not found", nodePoolName))...)
 		return nil, fmt.Errorf("nodepool %q can't be resolved for state node", nodePoolName)
 	}
+	// Nodes for static NodePools are only replaced by the static NodePool controller, never disrupted. They're skipped
+	// without an event, as they're expected to never be candidates.
+	if nodePool.IsStatic() {
+		return nil, fmt.Errorf("nodepool %q is static", nodePoolName)
+	}
+	if nodePool.IsPaused() {
+		recorder.Publish(disruptionevents.NodePoolPaused(nodePool))
+		return nil, fmt.Errorf("nodepool %q is paused", nodePoolName)
+	}
 	// We only care if instanceType in non-empty consolidation to do price-comparison.
 	instanceType := instanceTypeMap[node.Labels()[corev1.LabelInstanceTypeStable]]
 	if pods, err = node.ValidatePodsDisruptable(ctx, kubeClient, pdbs); err != nil {
 		// If the NodeClaim has a TerminationGracePeriod set and the disruption class is eventual, the node should be
-		// considered a candidate even if there's a pod that will block eviction. Other error types should still cause
-		// failure creating the candidate.
-		eventualDisruptionCandidate := node.NodeClaim.Spec.TerminationGracePeriod != nil && disruptionClass == EventualDisruptionClass
+		// considered a candidate even if there's a pod that will block eviction. Interrupted nodes are always considered,
+		// like expired nodes, since the cloud provider reclaims their instances whether or not their pods are evicted.
+		// Other error types should still cause failure creating the candidate.
+		eventualDisruptionCandidate := (node.NodeClaim.Spec.TerminationGracePeriod != nil && disruptionClass == EventualDisruptionClass) ||
+			node.NodeClaim.StatusConditions().Get(v1.ConditionTypeInterrupted).IsTrue()
 		if lo.Ternary(eventualDisruptionCandidate, state.IgnorePodBlockEvictionError(err), err) != nil {
 			recorder.Publish(disruptionevents.Blocked(node.Node, node.NodeClaim, err.Error())...)
 			return nil, err
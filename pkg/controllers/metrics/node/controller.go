@@ -18,6 +18,7 @@ package node
 
 import (
 	"context"
+	"strconv"
 	"strings"
 	"time"
 
@@ -44,10 +45,11 @@ const (
 	resourceType = "resource_type"
 	nodeName     = "node_name"
 	nodePhase    = "phase"
+	currency     = "currency"
 )
 
 var (
-	Allocatable = opmetrics.NewPrometheusGauge(
+	Allocatable = metrics.NewPrometheusGauge(
 		crmetrics.Registry,
 		prometheus.GaugeOpts{
 			Namespace: metrics.Namespace,
@@ -57,7 +59,7 @@ var (
 		},
 		nodeLabelNamesWithResourceType(),
 	)
-	TotalPodRequests = opmetrics.NewPrometheusGauge(
+	TotalPodRequests = metrics.NewPrometheusGauge(
 		crmetrics.Registry,
 		prometheus.GaugeOpts{
 			Namespace: metrics.Namespace,
@@ -67,7 +69,7 @@ var (
 		},
 		nodeLabelNamesWithResourceType(),
 	)
-	TotalPodLimits = opmetrics.NewPrometheusGauge(
+	TotalPodLimits = metrics.NewPrometheusGauge(
 		crmetrics.Registry,
 		prometheus.GaugeOpts{
 			Namespace: metrics.Namespace,
@@ -77,7 +79,7 @@ var (
 		},
 		nodeLabelNamesWithResourceType(),
 	)
-	TotalDaemonRequests = opmetrics.NewPrometheusGauge(
+	TotalDaemonRequests = metrics.NewPrometheusGauge(
 		crmetrics.Registry,
 		prometheus.GaugeOpts{
 			Namespace: metrics.Namespace,
@@ -87,7 +89,7 @@ var (
 		},
 		nodeLabelNamesWithResourceType(),
 	)
-	TotalDaemonLimits = opmetrics.NewPrometheusGauge(
+	TotalDaemonLimits = metrics.NewPrometheusGauge(
 		crmetrics.Registry,
 		prometheus.GaugeOpts{
 			Namespace: metrics.Namespace,
@@ -97,7 +99,7 @@ var (
 		},
 		nodeLabelNamesWithResourceType(),
 	)
-	SystemOverhead = opmetrics.NewPrometheusGauge(
+	SystemOverhead = metrics.NewPrometheusGauge(
 		crmetrics.Registry,
 		prometheus.GaugeOpts{
 			Namespace: metrics.Namespace,
@@ -107,7 +109,7 @@ var (
 		},
 		nodeLabelNamesWithResourceType(),
 	)
-	Lifetime = opmetrics.NewPrometheusGauge(
+	Lifetime = metrics.NewPrometheusGauge(
 		crmetrics.Registry,
 		prometheus.GaugeOpts{
 			Namespace: metrics.Namespace,
@@ -117,7 +119,17 @@ var (
 		},
 		nodeLabelNames(),
 	)
-	ClusterUtilization = opmetrics.NewPrometheusGauge(
+	LaunchPrice = metrics.NewPrometheusGauge(
+		crmetrics.Registry,
+		prometheus.GaugeOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: metrics.NodeSubsystem,
+			Name:      "launch_price",
+			Help:      "Hourly price that the node's instance launched at, as recorded on its NodeClaim's status. Labeled by the currency of the price.",
+		},
+		append(nodeLabelNames(), currency),
+	)
+	ClusterUtilization = metrics.NewPrometheusGauge(
 		crmetrics.Registry,
 		prometheus.GaugeOpts{
 			Namespace: metrics.Namespace,
@@ -240,12 +252,22 @@ func buildMetrics(n *state.StateNode) (res []*metrics.StoreMetric) {
 			})
 		}
 	}
-	return append(res,
+	res = append(res,
 		&metrics.StoreMetric{
 			GaugeMetric: Lifetime,
 			Value:       time.Since(n.Node.GetCreationTimestamp().Time).Seconds(),
 			Labels:      getNodeLabels(n.Node),
 		})
+	if n.NodeClaim != nil && n.NodeClaim.Status.LaunchedInstance != nil {
+		if price, err := strconv.ParseFloat(n.NodeClaim.Status.LaunchedInstance.Price, 64); err == nil {
+			res = append(res, &metrics.StoreMetric{
+				GaugeMetric: LaunchPrice,
+				Value:       price,
+				Labels:      lo.Assign(getNodeLabels(n.Node), map[string]string{currency: n.NodeClaim.Status.LaunchedInstance.Currency}),
+			})
+		}
+	}
+	return res
 }
 
 func getNodeLabelsWithResourceType(node *corev1.Node, resourceTypeName string) prometheus.Labels {
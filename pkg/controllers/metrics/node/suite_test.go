@@ -29,6 +29,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"sigs.k8s.io/karpenter/pkg/apis"
+	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
 	"sigs.k8s.io/karpenter/pkg/cloudprovider/fake"
 	"sigs.k8s.io/karpenter/pkg/controllers/metrics/node"
 	"sigs.k8s.io/karpenter/pkg/controllers/state"
@@ -45,6 +46,7 @@ var fakeClock *clock.FakeClock
 var env *test.Environment
 var cluster *state.Cluster
 var nodeController *informer.NodeController
+var nodeClaimController *informer.NodeClaimController
 var metricsStateController *node.Controller
 var cloudProvider *fake.CloudProvider
 
@@ -63,6 +65,7 @@ var _ = BeforeSuite(func() {
 	fakeClock = clock.NewFakeClock(time.Now())
 	cluster = state.NewCluster(fakeClock, env.Client, cloudProvider)
 	nodeController = informer.NewNodeController(env.Client, cluster)
+	nodeClaimController = informer.NewNodeClaimController(env.Client, cloudProvider, cluster)
 	metricsStateController = node.NewController(cluster)
 })
 
@@ -136,4 +139,22 @@ var _ = Describe("Node Metrics", func() {
 		})
 		Expect(found).To(BeFalse())
 	})
+	It("should update the launch price metric from the NodeClaim's launched instance", func() {
+		nodeClaim, node := test.NodeClaimAndNode(v1.NodeClaim{
+			Status: v1.NodeClaimStatus{
+				LaunchedInstance: &v1.LaunchedInstance{InstanceType: "small", Price: "0.25", Currency: "USD"},
+			},
+		})
+		ExpectApplied(ctx, env.Client, nodeClaim, node)
+		ExpectReconcileSucceeded(ctx, nodeClaimController, client.ObjectKeyFromObject(nodeClaim))
+		ExpectReconcileSucceeded(ctx, nodeController, client.ObjectKeyFromObject(node))
+		ExpectSingletonReconciled(ctx, metricsStateController)
+
+		metric, found := FindMetricWithLabelValues("karpenter_nodes_launch_price", map[string]string{
+			"node_name": node.GetName(),
+			"currency":  "USD",
+		})
+		Expect(found).To(BeTrue())
+		Expect(metric.GetGauge().GetValue()).To(BeNumerically("~", 0.25))
+	})
 })
@@ -46,7 +46,7 @@ const (
 )
 
 var (
-	Limit = opmetrics.NewPrometheusGauge(
+	Limit = metrics.NewPrometheusGauge(
 		crmetrics.Registry,
 		prometheus.GaugeOpts{
 			Namespace: metrics.Namespace,
@@ -59,7 +59,7 @@ var (
 			nodePoolNameLabel,
 		},
 	)
-	Usage = opmetrics.NewPrometheusGauge(
+	Usage = metrics.NewPrometheusGauge(
 		crmetrics.Registry,
 		prometheus.GaugeOpts{
 			Namespace: metrics.Namespace,
@@ -100,7 +100,7 @@ func (c *Controller) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		}
 		return reconcile.Result{}, client.IgnoreNotFound(err)
 	}
-	if !nodepoolutils.IsManaged(nodePool, c.cloudProvider) {
+	if !nodepoolutils.IsManaged(ctx, nodePool, c.cloudProvider) {
 		return reconcile.Result{}, nil
 	}
 	c.metricStore.Update(req.NamespacedName.String(), buildMetrics(nodePool))
@@ -138,9 +138,9 @@ func makeLabels(nodePool *v1.NodePool, resourceTypeName string) prometheus.Label
 	}
 }
 
-func (c *Controller) Register(_ context.Context, m manager.Manager) error {
+func (c *Controller) Register(ctx context.Context, m manager.Manager) error {
 	return controllerruntime.NewControllerManagedBy(m).
 		Named("metrics.nodepool").
-		For(&v1.NodePool{}, builder.WithPredicates(nodepoolutils.IsManagedPredicateFuncs(c.cloudProvider))).
+		For(&v1.NodePool{}, builder.WithPredicates(nodepoolutils.IsManagedPredicateFuncs(ctx, c.cloudProvider))).
 		Complete(c)
 }
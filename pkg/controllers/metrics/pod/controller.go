@@ -22,7 +22,6 @@ import (
 	"strings"
 	"time"
 
-	opmetrics "github.com/awslabs/operatorpkg/metrics"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/samber/lo"
 	corev1 "k8s.io/api/core/v1"
@@ -56,7 +55,7 @@ const (
 )
 
 var (
-	PodState = opmetrics.NewPrometheusGauge(
+	PodState = metrics.NewPrometheusGauge(
 		crmetrics.Registry,
 		prometheus.GaugeOpts{
 			Namespace: metrics.Namespace,
@@ -66,7 +65,7 @@ var (
 		},
 		labelNames(),
 	)
-	PodStartupDurationSeconds = opmetrics.NewPrometheusSummary(
+	PodStartupDurationSeconds = metrics.NewPrometheusSummary(
 		crmetrics.Registry,
 		prometheus.SummaryOpts{
 			Namespace:  metrics.Namespace,
@@ -77,7 +76,7 @@ var (
 		},
 		[]string{},
 	)
-	PodUnstartedTimeSeconds = opmetrics.NewPrometheusGauge(
+	PodUnstartedTimeSeconds = metrics.NewPrometheusGauge(
 		crmetrics.Registry,
 		prometheus.GaugeOpts{
 			Namespace: metrics.Namespace,
@@ -87,7 +86,7 @@ var (
 		},
 		[]string{podName, podNamespace},
 	)
-	PodBoundDurationSeconds = opmetrics.NewPrometheusHistogram(
+	PodBoundDurationSeconds = metrics.NewPrometheusHistogram(
 		crmetrics.Registry,
 		prometheus.HistogramOpts{
 			Namespace: metrics.Namespace,
@@ -98,7 +97,7 @@ var (
 		},
 		[]string{},
 	)
-	PodUnboundTimeSeconds = opmetrics.NewPrometheusGauge(
+	PodUnboundTimeSeconds = metrics.NewPrometheusGauge(
 		crmetrics.Registry,
 		prometheus.GaugeOpts{
 			Namespace: metrics.Namespace,
@@ -109,7 +108,7 @@ var (
 		[]string{podName, podNamespace},
 	)
 	// Stage: alpha
-	PodProvisioningBoundDurationSeconds = opmetrics.NewPrometheusHistogram(
+	PodProvisioningBoundDurationSeconds = metrics.NewPrometheusHistogram(
 		crmetrics.Registry,
 		prometheus.HistogramOpts{
 			Namespace: metrics.Namespace,
@@ -121,7 +120,7 @@ var (
 		[]string{},
 	)
 	// Stage: alpha
-	PodProvisioningUnboundTimeSeconds = opmetrics.NewPrometheusGauge(
+	PodProvisioningUnboundTimeSeconds = metrics.NewPrometheusGauge(
 		crmetrics.Registry,
 		prometheus.GaugeOpts{
 			Namespace: metrics.Namespace,
@@ -132,7 +131,7 @@ var (
 		[]string{podName, podNamespace},
 	)
 	// Stage: alpha
-	PodProvisioningStartupDurationSeconds = opmetrics.NewPrometheusHistogram(
+	PodProvisioningStartupDurationSeconds = metrics.NewPrometheusHistogram(
 		crmetrics.Registry,
 		prometheus.HistogramOpts{
 			Namespace: metrics.Namespace,
@@ -144,7 +143,7 @@ var (
 		[]string{},
 	)
 	// Stage: alpha
-	PodProvisioningUnstartedTimeSeconds = opmetrics.NewPrometheusGauge(
+	PodProvisioningUnstartedTimeSeconds = metrics.NewPrometheusGauge(
 		crmetrics.Registry,
 		prometheus.GaugeOpts{
 			Namespace: metrics.Namespace,
@@ -155,7 +154,7 @@ var (
 		[]string{podName, podNamespace},
 	)
 	// Stage: alpha
-	PodSchedulingUndecidedTimeSeconds = opmetrics.NewPrometheusGauge(
+	PodSchedulingUndecidedTimeSeconds = metrics.NewPrometheusGauge(
 		crmetrics.Registry,
 		prometheus.GaugeOpts{
 			Namespace: metrics.Namespace,
@@ -0,0 +1,117 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package pricing
+
+import (
+	"context"
+	"time"
+
+	"github.com/awslabs/operatorpkg/singleton"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/samber/lo"
+	"k8s.io/utils/clock"
+	controllerruntime "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"sigs.k8s.io/karpenter/pkg/cloudprovider"
+	"sigs.k8s.io/karpenter/pkg/metrics"
+	"sigs.k8s.io/karpenter/pkg/operator/injection"
+)
+
+const (
+	instanceTypeLabel = "instance_type"
+	zoneLabel         = "zone"
+	capacityTypeLabel = "capacity_type"
+	currencyLabel     = "currency"
+)
+
+var (
+	OfferingPrice = metrics.NewPrometheusGaugeWithAggregation(
+		crmetrics.Registry,
+		prometheus.GaugeOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "cloudprovider",
+			Name:      "offering_price_estimate",
+			Help:      "Estimated hourly price of the CloudProvider's offerings. Labeled by instance type, zone, capacity type and currency. The zone is empty for prices that apply in every zone. Offerings that metric label policies aggregate are recorded with the cheapest of their prices.",
+		},
+		[]string{
+			instanceTypeLabel,
+			zoneLabel,
+			capacityTypeLabel,
+			currencyLabel,
+		},
+		metrics.AggregateMin,
+	)
+	PricingAge = metrics.NewPrometheusGauge(
+		crmetrics.Registry,
+		prometheus.GaugeOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "cloudprovider",
+			Name:      "pricing_age_seconds",
+			Help:      "Seconds since the CloudProvider's prices were last refreshed. Zero if the CloudProvider doesn't report when they were.",
+		},
+		[]string{},
+	)
+)
+
+type Controller struct {
+	clock         clock.Clock
+	cloudProvider cloudprovider.CloudProvider
+	metricStore   *metrics.Store
+}
+
+func NewController(clk clock.Clock, cloudProvider cloudprovider.CloudProvider) *Controller {
+	return &Controller{
+		clock:         clk,
+		cloudProvider: cloudProvider,
+		metricStore:   metrics.NewStore(),
+	}
+}
+
+func (c *Controller) Reconcile(ctx context.Context) (reconcile.Result, error) {
+	ctx = injection.WithControllerName(ctx, "metrics.pricing") //nolint:ineffassign,staticcheck
+
+	pricing := c.cloudProvider.Pricing()
+	metricsMap := lo.MapEntries(pricing.Prices, func(key cloudprovider.PriceKey, price float64) (string, []*metrics.StoreMetric) {
+		return key.InstanceType + "/" + key.Zone + "/" + key.CapacityType, []*metrics.StoreMetric{{
+			GaugeMetric: OfferingPrice,
+			Labels: map[string]string{
+				instanceTypeLabel: key.InstanceType,
+				zoneLabel:         key.Zone,
+				capacityTypeLabel: key.CapacityType,
+				currencyLabel:     pricing.Currency,
+			},
+			Value: price,
+		}}
+	})
+	metricsMap["pricingAge"] = []*metrics.StoreMetric{{
+		GaugeMetric: PricingAge,
+		Labels:      map[string]string{},
+		Value:       pricing.Age(c.clock).Seconds(),
+	}}
+	c.metricStore.ReplaceAll(metricsMap)
+
+	return reconcile.Result{RequeueAfter: time.Minute}, nil
+}
+
+func (c *Controller) Register(_ context.Context, m manager.Manager) error {
+	return controllerruntime.NewControllerManagedBy(m).
+		Named("metrics.pricing").
+		WatchesRawSource(singleton.Source()).
+		Complete(singleton.AsReconciler(c))
+}
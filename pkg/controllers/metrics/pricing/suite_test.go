@@ -0,0 +1,133 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package pricing_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/samber/lo"
+	clock "k8s.io/utils/clock/testing"
+
+	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+	"sigs.k8s.io/karpenter/pkg/cloudprovider"
+	"sigs.k8s.io/karpenter/pkg/cloudprovider/fake"
+	"sigs.k8s.io/karpenter/pkg/controllers/metrics/pricing"
+	"sigs.k8s.io/karpenter/pkg/metrics"
+	. "sigs.k8s.io/karpenter/pkg/test/expectations"
+	. "sigs.k8s.io/karpenter/pkg/utils/testing"
+)
+
+var ctx context.Context
+var fakeClock *clock.FakeClock
+var cloudProvider *fake.CloudProvider
+var pricingController *pricing.Controller
+
+func TestAPIs(t *testing.T) {
+	ctx = TestContextWithLogger(t)
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "PricingMetrics")
+}
+
+var _ = BeforeSuite(func() {
+	fakeClock = clock.NewFakeClock(time.Now())
+	cloudProvider = fake.NewCloudProvider()
+	pricingController = pricing.NewController(fakeClock, cloudProvider)
+})
+
+var _ = BeforeEach(func() {
+	cloudProvider.Reset()
+})
+
+var _ = Describe("Metrics", func() {
+	It("should update the offering price metrics", func() {
+		cloudProvider.Prices = cloudprovider.Pricing{
+			Prices: map[cloudprovider.PriceKey]float64{
+				{InstanceType: "small", Zone: "test-zone-1", CapacityType: v1.CapacityTypeSpot}: 0.25,
+				{InstanceType: "small", CapacityType: v1.CapacityTypeOnDemand}:                  1.5,
+			},
+			Currency: "USD",
+		}
+		ExpectSingletonReconciled(ctx, pricingController)
+
+		m, found := FindMetricWithLabelValues("karpenter_cloudprovider_offering_price_estimate", map[string]string{
+			"instance_type": "small",
+			"zone":          "test-zone-1",
+			"capacity_type": v1.CapacityTypeSpot,
+			"currency":      "USD",
+		})
+		Expect(found).To(BeTrue())
+		Expect(m.GetGauge().GetValue()).To(BeNumerically("~", 0.25))
+		m, found = FindMetricWithLabelValues("karpenter_cloudprovider_offering_price_estimate", map[string]string{
+			"instance_type": "small",
+			"zone":          "",
+			"capacity_type": v1.CapacityTypeOnDemand,
+			"currency":      "USD",
+		})
+		Expect(found).To(BeTrue())
+		Expect(m.GetGauge().GetValue()).To(BeNumerically("~", 1.5))
+	})
+	It("should delete the metrics of offerings that are no longer priced", func() {
+		cloudProvider.Prices = cloudprovider.Pricing{
+			Prices: map[cloudprovider.PriceKey]float64{
+				{InstanceType: "large", Zone: "test-zone-2", CapacityType: v1.CapacityTypeSpot}: 0.5,
+			},
+			Currency: "USD",
+		}
+		ExpectSingletonReconciled(ctx, pricingController)
+		_, found := FindMetricWithLabelValues("karpenter_cloudprovider_offering_price_estimate", map[string]string{"instance_type": "large"})
+		Expect(found).To(BeTrue())
+
+		cloudProvider.Prices = cloudprovider.Pricing{}
+		ExpectSingletonReconciled(ctx, pricingController)
+		_, found = FindMetricWithLabelValues("karpenter_cloudprovider_offering_price_estimate", map[string]string{"instance_type": "large"})
+		Expect(found).To(BeFalse())
+	})
+	It("should record the cheapest price of the offerings that label policies aggregate", func() {
+		metrics.SetLabelPolicies(lo.Must(metrics.ParseLabelPolicies("karpenter_cloudprovider_offering_price_estimate:zone=drop")))
+		DeferCleanup(metrics.SetLabelPolicies, metrics.LabelPolicies(nil))
+		cloudProvider.Prices = cloudprovider.Pricing{
+			Prices: map[cloudprovider.PriceKey]float64{
+				{InstanceType: "medium", Zone: "test-zone-1", CapacityType: v1.CapacityTypeSpot}: 0.75,
+				{InstanceType: "medium", Zone: "test-zone-2", CapacityType: v1.CapacityTypeSpot}: 0.5,
+			},
+			Currency: "USD",
+		}
+		ExpectSingletonReconciled(ctx, pricingController)
+		m, found := FindMetricWithLabelValues("karpenter_cloudprovider_offering_price_estimate", map[string]string{
+			"instance_type": "medium",
+			"zone":          "",
+			"capacity_type": v1.CapacityTypeSpot,
+		})
+		Expect(found).To(BeTrue())
+		Expect(m.GetGauge().GetValue()).To(BeNumerically("~", 0.5))
+
+		cloudProvider.Prices = cloudprovider.Pricing{}
+		ExpectSingletonReconciled(ctx, pricingController)
+		_, found = FindMetricWithLabelValues("karpenter_cloudprovider_offering_price_estimate", map[string]string{"instance_type": "medium"})
+		Expect(found).To(BeFalse())
+	})
+	It("should update the pricing age metric", func() {
+		cloudProvider.Prices = cloudprovider.Pricing{UpdatedAt: fakeClock.Now().Add(-time.Hour)}
+		ExpectSingletonReconciled(ctx, pricingController)
+		m, found := FindMetricWithLabelValues("karpenter_cloudprovider_pricing_age_seconds", map[string]string{})
+		Expect(found).To(BeTrue())
+		Expect(m.GetGauge().GetValue()).To(BeNumerically("~", time.Hour.Seconds()))
+	})
+})
@@ -65,14 +65,18 @@ func (c *Controller) Reconcile(ctx context.Context, n *corev1.Node) (reconcile.R
 		}
 		return reconcile.Result{}, fmt.Errorf("hydrating node, %w", err)
 	}
-	if !nodeclaimutils.IsManaged(nc, c.cloudProvider) {
+	if !nodeclaimutils.IsManaged(ctx, nc, c.cloudProvider) {
 		return reconcile.Result{}, nil
 	}
 
 	stored := n.DeepCopy()
-	n.Labels = lo.Assign(n.Labels, map[string]string{
-		v1.NodeClassLabelKey(nc.Spec.NodeClassRef.GroupKind()): nc.Spec.NodeClassRef.Name,
-	})
+	for _, hydrate := range []func(*corev1.Node, *v1.NodeClaim){
+		hydrateNodeClassLabel,
+		hydrateNodePoolLabel,
+		hydrateLifecycleLabels,
+	} {
+		hydrate(n, nc)
+	}
 	if !equality.Semantic.DeepEqual(stored, n) {
 		if err := c.kubeClient.Patch(ctx, n, client.MergeFrom(stored)); err != nil {
 			return reconcile.Result{}, client.IgnoreNotFound(err)
@@ -81,6 +85,32 @@ func (c *Controller) Reconcile(ctx context.Context, n *corev1.Node) (reconcile.R
 	return reconcile.Result{}, nil
 }
 
+// hydrateNodeClassLabel adds the label for the NodeClass of the NodeClaim, which is used to look up the nodes of a NodeClass
+func hydrateNodeClassLabel(n *corev1.Node, nc *v1.NodeClaim) {
+	n.Labels = lo.Assign(n.Labels, map[string]string{
+		v1.NodeClassLabelKey(nc.Spec.NodeClassRef.GroupKind()): nc.Spec.NodeClassRef.Name,
+	})
+}
+
+// hydrateNodePoolLabel adds the NodePool label of the NodeClaim to nodes that registered without it
+func hydrateNodePoolLabel(n *corev1.Node, nc *v1.NodeClaim) {
+	if nodePoolName, ok := nc.Labels[v1.NodePoolLabelKey]; ok {
+		n.Labels = lo.Assign(n.Labels, map[string]string{v1.NodePoolLabelKey: nodePoolName})
+	}
+}
+
+// hydrateLifecycleLabels adds the registered and initialized labels to nodes whose NodeClaims registered or initialized
+// before the lifecycle controller labeled nodes. The lifecycle controller only adds these labels when the NodeClaim
+// transitions, so they would otherwise never be added to these nodes.
+func hydrateLifecycleLabels(n *corev1.Node, nc *v1.NodeClaim) {
+	if nc.StatusConditions().Get(v1.ConditionTypeRegistered).IsTrue() {
+		n.Labels = lo.Assign(n.Labels, map[string]string{v1.NodeRegisteredLabelKey: "true"})
+	}
+	if nc.StatusConditions().Get(v1.ConditionTypeInitialized).IsTrue() {
+		n.Labels = lo.Assign(n.Labels, map[string]string{v1.NodeInitializedLabelKey: "true"})
+	}
+}
+
 func (c *Controller) Name() string {
 	return "node.hydration"
 }
@@ -23,6 +23,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/samber/lo"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"sigs.k8s.io/karpenter/pkg/apis"
 	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
@@ -96,4 +97,38 @@ var _ = Describe("Hydration", func() {
 		Entry("should hydrate missing metadata onto the Node", true),
 		Entry("should ignore Nodes which aren't managed by this Karpenter instance", false),
 	)
+	It("should hydrate the lifecycle labels of NodeClaims that registered and initialized before they were added", func() {
+		nodeClaim, node := test.NodeClaimAndNode(v1.NodeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					v1.NodePoolLabelKey: "default",
+				},
+			},
+		})
+		nodeClaim.StatusConditions().SetTrue(v1.ConditionTypeRegistered)
+		nodeClaim.StatusConditions().SetTrue(v1.ConditionTypeInitialized)
+		delete(node.Labels, v1.NodePoolLabelKey)
+		delete(node.Labels, v1.NodeRegisteredLabelKey)
+		delete(node.Labels, v1.NodeInitializedLabelKey)
+		ExpectApplied(ctx, env.Client, nodeClaim, node)
+		ExpectObjectReconciled(ctx, env.Client, hydrationController, node)
+
+		node = ExpectExists(ctx, env.Client, node)
+		Expect(node.Labels).To(HaveKeyWithValue(v1.NodePoolLabelKey, "default"))
+		Expect(node.Labels).To(HaveKeyWithValue(v1.NodeRegisteredLabelKey, "true"))
+		Expect(node.Labels).To(HaveKeyWithValue(v1.NodeInitializedLabelKey, "true"))
+	})
+	It("shouldn't hydrate the lifecycle labels of NodeClaims that haven't registered", func() {
+		nodeClaim, node := test.NodeClaimAndNode()
+		nodeClaim.StatusConditions().SetUnknown(v1.ConditionTypeRegistered)
+		nodeClaim.StatusConditions().SetUnknown(v1.ConditionTypeInitialized)
+		delete(node.Labels, v1.NodeRegisteredLabelKey)
+		delete(node.Labels, v1.NodeInitializedLabelKey)
+		ExpectApplied(ctx, env.Client, nodeClaim, node)
+		ExpectObjectReconciled(ctx, env.Client, hydrationController, node)
+
+		node = ExpectExists(ctx, env.Client, node)
+		Expect(node.Labels).ToNot(HaveKey(v1.NodeRegisteredLabelKey))
+		Expect(node.Labels).ToNot(HaveKey(v1.NodeInitializedLabelKey))
+	})
 })
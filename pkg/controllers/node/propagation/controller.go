@@ -0,0 +1,149 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package propagation
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/awslabs/operatorpkg/reasonable"
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/klog/v2"
+	controllerruntime "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+	"sigs.k8s.io/karpenter/pkg/cloudprovider"
+	"sigs.k8s.io/karpenter/pkg/operator/injection"
+	"sigs.k8s.io/karpenter/pkg/operator/options"
+	nodeutils "sigs.k8s.io/karpenter/pkg/utils/node"
+	nodeclaimutils "sigs.k8s.io/karpenter/pkg/utils/nodeclaim"
+)
+
+// Controller propagates the annotations of NodePools and NodeClaims that are in the node annotation allowlist onto
+// their Nodes, and keeps them in sync as they change. The propagated keys are recorded on the Node so that the annotations
+// that stop being propagated are removed
+type Controller struct {
+	kubeClient    client.Client
+	cloudProvider cloudprovider.CloudProvider
+}
+
+func NewController(kubeClient client.Client, cloudProvider cloudprovider.CloudProvider) *Controller {
+	return &Controller{
+		kubeClient:    kubeClient,
+		cloudProvider: cloudProvider,
+	}
+}
+
+func (c *Controller) Reconcile(ctx context.Context, n *corev1.Node) (reconcile.Result, error) {
+	ctx = injection.WithControllerName(ctx, c.Name())
+	ctx = log.IntoContext(ctx, log.FromContext(ctx).WithValues("Node", klog.KRef(n.Namespace, n.Name)))
+
+	nc, err := nodeutils.NodeClaimForNode(ctx, c.kubeClient, n)
+	if err != nil {
+		if nodeutils.IsDuplicateNodeClaimError(err) || nodeutils.IsNodeClaimNotFoundError(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, fmt.Errorf("propagating annotations, %w", err)
+	}
+	if !nodeclaimutils.IsManaged(ctx, nc, c.cloudProvider) {
+		return reconcile.Result{}, nil
+	}
+	annotations := map[string]string{}
+	if nodePoolName, ok := nc.Labels[v1.NodePoolLabelKey]; ok {
+		nodePool := &v1.NodePool{}
+		if err := c.kubeClient.Get(ctx, client.ObjectKey{Name: nodePoolName}, nodePool); client.IgnoreNotFound(err) != nil {
+			return reconcile.Result{}, fmt.Errorf("getting nodepool, %w", err)
+		}
+		annotations = lo.Assign(annotations, nodePool.Annotations)
+	}
+	annotations = lo.PickBy(lo.Assign(annotations, nc.Annotations), func(key string, _ string) bool {
+		return allowed(ctx, key)
+	})
+
+	stored := n.DeepCopy()
+	// Annotations that were propagated before, but no longer are, e.g. because they were removed from the NodePool or
+	// NodeClaim or from the allowlist, are removed from the Node
+	n.Annotations = lo.OmitByKeys(n.Annotations, lo.Filter(strings.Split(n.Annotations[v1.PropagatedAnnotationsAnnotationKey], ","), func(key string, _ int) bool {
+		_, ok := annotations[key]
+		return !ok
+	}))
+	n.Annotations = lo.Assign(n.Annotations, annotations)
+	if keys := lo.Keys(annotations); len(keys) > 0 {
+		sort.Strings(keys)
+		n.Annotations[v1.PropagatedAnnotationsAnnotationKey] = strings.Join(keys, ",")
+	} else {
+		delete(n.Annotations, v1.PropagatedAnnotationsAnnotationKey)
+	}
+	if !equality.Semantic.DeepEqual(stored, n) {
+		if err := c.kubeClient.Patch(ctx, n, client.MergeFrom(stored)); err != nil {
+			return reconcile.Result{}, client.IgnoreNotFound(err)
+		}
+	}
+	return reconcile.Result{}, nil
+}
+
+// allowed returns true if the annotation key is in the node annotation allowlist, either as is or by one of the
+// prefixes in the allowlist that end with *
+func allowed(ctx context.Context, key string) bool {
+	return lo.SomeBy(strings.Split(options.FromContext(ctx).NodeAnnotationAllowlist, ","), func(entry string) bool {
+		entry = strings.TrimSpace(entry)
+		if prefix, ok := strings.CutSuffix(entry, "*"); ok {
+			return prefix != "" && strings.HasPrefix(key, prefix)
+		}
+		return entry != "" && key == entry
+	})
+}
+
+func (c *Controller) Name() string {
+	return "node.propagation"
+}
+
+func (c *Controller) Register(_ context.Context, m manager.Manager) error {
+	return controllerruntime.NewControllerManagedBy(m).
+		Named(c.Name()).
+		For(&corev1.Node{}).
+		Watches(&v1.NodeClaim{}, nodeutils.NodeClaimEventHandler(c.kubeClient)).
+		Watches(&v1.NodePool{}, nodePoolEventHandler(c.kubeClient)).
+		WithOptions(controller.Options{
+			RateLimiter:             reasonable.RateLimiter(),
+			MaxConcurrentReconciles: 1000,
+		}).
+		Complete(reconcile.AsReconciler(m.GetClient(), c))
+}
+
+// nodePoolEventHandler enqueues the Nodes of a NodePool when the NodePool changes
+func nodePoolEventHandler(c client.Client) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, o client.Object) []reconcile.Request {
+		nodes := &corev1.NodeList{}
+		if err := c.List(ctx, nodes, client.MatchingLabels{v1.NodePoolLabelKey: o.GetName()}); err != nil {
+			return nil
+		}
+		return lo.Map(nodes.Items, func(n corev1.Node, _ int) reconcile.Request {
+			return reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&n)}
+		})
+	})
+}
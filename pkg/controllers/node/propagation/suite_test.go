@@ -0,0 +1,156 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package propagation_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/samber/lo"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/karpenter/pkg/apis"
+	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+	"sigs.k8s.io/karpenter/pkg/cloudprovider/fake"
+	"sigs.k8s.io/karpenter/pkg/controllers/node/propagation"
+	"sigs.k8s.io/karpenter/pkg/operator/options"
+	"sigs.k8s.io/karpenter/pkg/test"
+	. "sigs.k8s.io/karpenter/pkg/test/expectations"
+	"sigs.k8s.io/karpenter/pkg/test/v1alpha1"
+	. "sigs.k8s.io/karpenter/pkg/utils/testing"
+)
+
+var ctx context.Context
+var propagationController *propagation.Controller
+var env *test.Environment
+var cloudProvider *fake.CloudProvider
+
+func TestAPIs(t *testing.T) {
+	ctx = TestContextWithLogger(t)
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Propagation")
+}
+
+var _ = BeforeSuite(func() {
+	env = test.NewEnvironment(test.WithCRDs(apis.CRDs...), test.WithCRDs(v1alpha1.CRDs...), test.WithFieldIndexers(test.NodeProviderIDFieldIndexer(ctx), test.NodeClaimProviderIDFieldIndexer(ctx)))
+	ctx = options.ToContext(ctx, test.Options(test.OptionsFields{NodeAnnotationAllowlist: lo.ToPtr("example.com/team,example.com/cost-*")}))
+
+	cloudProvider = fake.NewCloudProvider()
+	propagationController = propagation.NewController(env.Client, cloudProvider)
+})
+
+var _ = AfterSuite(func() {
+	Expect(env.Stop()).To(Succeed(), "Failed to stop environment")
+})
+
+var _ = AfterEach(func() {
+	ExpectCleanedUp(ctx, env.Client)
+	cloudProvider.Reset()
+})
+
+var _ = Describe("Propagation", func() {
+	var nodePool *v1.NodePool
+	BeforeEach(func() {
+		nodePool = test.NodePool(v1.NodePool{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					"example.com/team":        "nodepool-team",
+					"example.com/cost-center": "1234",
+					"example.com/other":       "other",
+				},
+			},
+		})
+	})
+	It("should propagate the allowed annotations of the NodePool and NodeClaim to the Node", func() {
+		nodeClaim, node := test.NodeClaimAndNode(v1.NodeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{v1.NodePoolLabelKey: nodePool.Name},
+				Annotations: map[string]string{
+					"example.com/team": "nodeclaim-team",
+				},
+			},
+		})
+		ExpectApplied(ctx, env.Client, nodePool, nodeClaim, node)
+		ExpectObjectReconciled(ctx, env.Client, propagationController, node)
+
+		node = ExpectExists(ctx, env.Client, node)
+		Expect(node.Annotations).To(HaveKeyWithValue("example.com/team", "nodeclaim-team"))
+		Expect(node.Annotations).To(HaveKeyWithValue("example.com/cost-center", "1234"))
+		Expect(node.Annotations).ToNot(HaveKey("example.com/other"))
+	})
+	It("should update the propagated annotations when they change", func() {
+		nodeClaim, node := test.NodeClaimAndNode(v1.NodeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{v1.NodePoolLabelKey: nodePool.Name},
+			},
+		})
+		ExpectApplied(ctx, env.Client, nodePool, nodeClaim, node)
+		ExpectObjectReconciled(ctx, env.Client, propagationController, node)
+		node = ExpectExists(ctx, env.Client, node)
+		Expect(node.Annotations).To(HaveKeyWithValue("example.com/team", "nodepool-team"))
+
+		nodePool.Annotations["example.com/team"] = "new-team"
+		ExpectApplied(ctx, env.Client, nodePool)
+		ExpectObjectReconciled(ctx, env.Client, propagationController, node)
+		node = ExpectExists(ctx, env.Client, node)
+		Expect(node.Annotations).To(HaveKeyWithValue("example.com/team", "new-team"))
+	})
+	It("should remove the propagated annotations when they're removed from the NodePool", func() {
+		nodeClaim, node := test.NodeClaimAndNode(v1.NodeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{v1.NodePoolLabelKey: nodePool.Name},
+			},
+		})
+		node.Annotations = lo.Assign(node.Annotations, map[string]string{"example.com/cost-owner": "user"})
+		ExpectApplied(ctx, env.Client, nodePool, nodeClaim, node)
+		ExpectObjectReconciled(ctx, env.Client, propagationController, node)
+		node = ExpectExists(ctx, env.Client, node)
+		Expect(node.Annotations).To(HaveKeyWithValue("example.com/cost-center", "1234"))
+		Expect(node.Annotations).To(HaveKeyWithValue(v1.PropagatedAnnotationsAnnotationKey, "example.com/cost-center,example.com/team"))
+
+		delete(nodePool.Annotations, "example.com/cost-center")
+		ExpectApplied(ctx, env.Client, nodePool)
+		ExpectObjectReconciled(ctx, env.Client, propagationController, node)
+		node = ExpectExists(ctx, env.Client, node)
+		Expect(node.Annotations).ToNot(HaveKey("example.com/cost-center"))
+		Expect(node.Annotations).To(HaveKeyWithValue("example.com/team", "nodepool-team"))
+		Expect(node.Annotations).To(HaveKeyWithValue(v1.PropagatedAnnotationsAnnotationKey, "example.com/team"))
+		// annotations that weren't propagated are left as they are, even if they're allowed
+		Expect(node.Annotations).To(HaveKeyWithValue("example.com/cost-owner", "user"))
+	})
+	It("should ignore Nodes which aren't managed by this Karpenter instance", func() {
+		nodeClaim, node := test.NodeClaimAndNode(v1.NodeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{v1.NodePoolLabelKey: nodePool.Name},
+			},
+			Spec: v1.NodeClaimSpec{
+				NodeClassRef: &v1.NodeClassReference{
+					Group: "karpenter.test.sh",
+					Kind:  "UnmanagedNodeClass",
+					Name:  "default",
+				},
+			},
+		})
+		ExpectApplied(ctx, env.Client, nodePool, nodeClaim, node)
+		ExpectObjectReconciled(ctx, env.Client, propagationController, node)
+
+		node = ExpectExists(ctx, env.Client, node)
+		Expect(node.Annotations).ToNot(HaveKey("example.com/team"))
+	})
+})
@@ -88,7 +88,7 @@ func (c *Controller) finalize(ctx context.Context, node *corev1.Node) (reconcile
 	if !controllerutil.ContainsFinalizer(node, v1.TerminationFinalizer) {
 		return reconcile.Result{}, nil
 	}
-	if !nodeutils.IsManaged(node, c.cloudProvider) {
+	if !nodeutils.IsManaged(ctx, node, c.cloudProvider) {
 		return reconcile.Result{}, nil
 	}
 
@@ -112,11 +112,22 @@ func (c *Controller) finalize(ctx context.Context, node *corev1.Node) (reconcile
 		}
 		return reconcile.Result{}, client.IgnoreNotFound(fmt.Errorf("tainting node with %s, %w", pretty.Taint(v1.DisruptedNoScheduleTaint), err))
 	}
-	if err = c.terminator.Drain(ctx, node, nodeTerminationTime); err != nil {
+	// A NodeClaim can be annotated to skip the drain of its node, for the emergency removal of a wedged node. The pods of
+	// the node are force deleted once its instance is terminated.
+	forceDelete := lo.ContainsBy(nodeClaims, func(nc *v1.NodeClaim) bool { return nc.Annotations[v1.ForceDeleteAnnotationKey] == "true" })
+	if forceDelete {
+		c.recorder.Publish(terminatorevents.NodeForceDeleting(node))
+	} else if err = c.terminator.Drain(ctx, node, nodeTerminationTime); err != nil {
 		if !terminator.IsNodeDrainError(err) {
 			return reconcile.Result{}, fmt.Errorf("draining node, %w", err)
 		}
 		c.recorder.Publish(terminatorevents.NodeFailedToDrain(node, err))
+		if err = c.updateDrainedCondition(ctx, node, nodeClaims, false); err != nil {
+			if errors.IsConflict(err) {
+				return reconcile.Result{Requeue: true}, nil
+			}
+			return reconcile.Result{}, fmt.Errorf("updating drained status condition, %w", err)
+		}
 		// If the underlying NodeClaim no longer exists, we want to delete to avoid trying to gracefully draining
 		// on nodes that are no longer alive. We do a check on the Ready condition of the node since, even
 		// though the CloudProvider says the instance is not around, we know that the kubelet process is still running
@@ -133,13 +144,22 @@ func (c *Controller) finalize(ctx context.Context, node *corev1.Node) (reconcile
 
 		return reconcile.Result{RequeueAfter: 1 * time.Second}, nil
 	}
-	NodesDrainedTotal.Inc(map[string]string{
-		metrics.NodePoolLabel: node.Labels[v1.NodePoolLabelKey],
-	})
+	if !forceDelete {
+		NodesDrainedTotal.Inc(map[string]string{
+			metrics.NodePoolLabel: node.Labels[v1.NodePoolLabelKey],
+		})
+		if err = c.updateDrainedCondition(ctx, node, nodeClaims, true); err != nil {
+			if errors.IsConflict(err) {
+				return reconcile.Result{Requeue: true}, nil
+			}
+			return reconcile.Result{}, fmt.Errorf("updating drained status condition, %w", err)
+		}
+	}
 	// In order for Pods associated with PersistentVolumes to smoothly migrate from the terminating Node, we wait
 	// for VolumeAttachments of drain-able Pods to be cleaned up before terminating Node and removing its finalizer.
-	// However, if TerminationGracePeriod is configured for Node, and we are past that period, we will skip waiting.
-	if nodeTerminationTime == nil || c.clock.Now().Before(*nodeTerminationTime) {
+	// However, if TerminationGracePeriod is configured for Node, and we are past that period, or the node is force
+	// deleted, we will skip waiting.
+	if !forceDelete && (nodeTerminationTime == nil || c.clock.Now().Before(*nodeTerminationTime)) {
 		areVolumesDetached, err := c.ensureVolumesDetached(ctx, node)
 		if err != nil {
 			return reconcile.Result{}, fmt.Errorf("ensuring no volume attachments, %w", err)
@@ -153,7 +173,7 @@ func (c *Controller) finalize(ctx context.Context, node *corev1.Node) (reconcile
 		return reconcile.Result{}, fmt.Errorf("deleting nodeclaims, %w", err)
 	}
 	for _, nodeClaim := range nodeClaims {
-		isInstanceTerminated, err := termination.EnsureTerminated(ctx, c.kubeClient, nodeClaim, c.cloudProvider)
+		isInstanceTerminated, err := termination.EnsureTerminated(ctx, c.kubeClient, nodeClaim, c.cloudProvider, c.clock)
 		if err != nil {
 			// 404 = the nodeClaim no longer exists
 			if errors.IsNotFound(err) {
@@ -169,12 +189,74 @@ func (c *Controller) finalize(ctx context.Context, node *corev1.Node) (reconcile
 			return reconcile.Result{RequeueAfter: 5 * time.Second}, nil
 		}
 	}
+	// Pods are only force deleted once the instance is terminated, so that their containers can't keep running after
+	// they're removed from the API server. Pods on retained instances are left for the pod garbage collector.
+	if forceDelete && !lo.ContainsBy(nodeClaims, func(nc *v1.NodeClaim) bool {
+		return nc.Spec.InstanceRetentionPolicy == v1.InstanceRetentionPolicyRetain
+	}) {
+		if err = c.terminator.ForceDrain(ctx, node); err != nil {
+			return reconcile.Result{}, fmt.Errorf("force draining node, %w", err)
+		}
+	}
 	if err := c.removeFinalizer(ctx, node); err != nil {
 		return reconcile.Result{}, err
 	}
+	if forceDelete {
+		NodesForceDeletedTotal.Inc(map[string]string{
+			metrics.NodePoolLabel: node.Labels[v1.NodePoolLabelKey],
+		})
+	}
 	return reconcile.Result{}, nil
 }
 
+// updateDrainedCondition records the drain of the node on its NodeClaims. Draining goes true once the drain starts and
+// Drained goes true once it completes, when the duration of the drain is observed.
+func (c *Controller) updateDrainedCondition(ctx context.Context, node *corev1.Node, nodeClaims []*v1.NodeClaim, drained bool) error {
+	for _, nodeClaim := range nodeClaims {
+		// The NodeClaims were deleted when the node started terminating, so they need to be refreshed before patching
+		if err := c.kubeClient.Get(ctx, client.ObjectKeyFromObject(nodeClaim), nodeClaim); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		if nodeClaim.StatusConditions().Get(v1.ConditionTypeDrained).IsTrue() {
+			continue
+		}
+		stored := nodeClaim.DeepCopy()
+		drainingSince := node.DeletionTimestamp.Time
+		if cond := nodeClaim.StatusConditions().Get(v1.ConditionTypeDraining); cond.IsTrue() {
+			drainingSince = cond.LastTransitionTime.Time
+		} else {
+			nodeClaim.StatusConditions().SetTrue(v1.ConditionTypeDraining)
+		}
+		if drained {
+			nodeClaim.StatusConditions().SetTrue(v1.ConditionTypeDrained)
+		} else {
+			nodeClaim.StatusConditions().SetUnknownWithReason(v1.ConditionTypeDrained, "Draining", "Draining")
+		}
+		if equality.Semantic.DeepEqual(stored, nodeClaim) {
+			continue
+		}
+		// We use client.MergeFromWithOptimisticLock because patching a list with a JSON merge patch
+		// can cause races due to the fact that it fully replaces the list on a change
+		// Here, we are updating the status condition list
+		if err := c.kubeClient.Status().Patch(ctx, nodeClaim, client.MergeFromWithOptions(stored, client.MergeFromWithOptimisticLock{})); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		if drained {
+			metrics.NodeClaimsPhaseDurationSeconds.Observe(c.clock.Since(drainingSince).Seconds(), map[string]string{
+				metrics.PhaseLabel:    v1.ConditionTypeDrained,
+				metrics.NodePoolLabel: node.Labels[v1.NodePoolLabelKey],
+			})
+		}
+	}
+	return nil
+}
+
 func (c *Controller) deleteAllNodeClaims(ctx context.Context, nodeClaims ...*v1.NodeClaim) error {
 	for _, nodeClaim := range nodeClaims {
 		// If we still get the NodeClaim, but it's already marked as terminating, we don't need to call Delete again
@@ -285,10 +367,10 @@ func (c *Controller) nodeTerminationTime(node *corev1.Node, nodeClaims ...*v1.No
 	return &expirationTime, nil
 }
 
-func (c *Controller) Register(_ context.Context, m manager.Manager) error {
+func (c *Controller) Register(ctx context.Context, m manager.Manager) error {
 	return controllerruntime.NewControllerManagedBy(m).
 		Named("node.termination").
-		For(&corev1.Node{}, builder.WithPredicates(nodeutils.IsManagedPredicateFuncs(c.cloudProvider))).
+		For(&corev1.Node{}, builder.WithPredicates(nodeutils.IsManagedPredicateFuncs(ctx, c.cloudProvider))).
 		WithOptions(
 			controller.Options{
 				RateLimiter: workqueue.NewTypedMaxOfRateLimiter[reconcile.Request](
@@ -19,7 +19,6 @@ package termination
 import (
 	"time"
 
-	opmetrics "github.com/awslabs/operatorpkg/metrics"
 	"github.com/prometheus/client_golang/prometheus"
 	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 
@@ -29,7 +28,7 @@ import (
 const dayDuration = time.Hour * 24
 
 var (
-	DurationSeconds = opmetrics.NewPrometheusSummary(
+	DurationSeconds = metrics.NewPrometheusSummary(
 		crmetrics.Registry,
 		prometheus.SummaryOpts{
 			Namespace:  metrics.Namespace,
@@ -40,7 +39,7 @@ var (
 		},
 		[]string{metrics.NodePoolLabel},
 	)
-	NodesDrainedTotal = opmetrics.NewPrometheusCounter(
+	NodesDrainedTotal = metrics.NewPrometheusCounter(
 		crmetrics.Registry,
 		prometheus.CounterOpts{
 			Namespace: metrics.Namespace,
@@ -50,7 +49,17 @@ var (
 		},
 		[]string{metrics.NodePoolLabel},
 	)
-	NodeLifetimeDurationSeconds = opmetrics.NewPrometheusHistogram(
+	NodesForceDeletedTotal = metrics.NewPrometheusCounter(
+		crmetrics.Registry,
+		prometheus.CounterOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: metrics.NodeSubsystem,
+			Name:      "force_deleted_total",
+			Help:      "The total number of nodes that Karpenter deleted without draining them, as their nodeclaims were annotated to be force deleted",
+		},
+		[]string{metrics.NodePoolLabel},
+	)
+	NodeLifetimeDurationSeconds = metrics.NewPrometheusHistogram(
 		crmetrics.Registry,
 		prometheus.HistogramOpts{
 			Namespace: metrics.Namespace,
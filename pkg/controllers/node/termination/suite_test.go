@@ -37,6 +37,7 @@ import (
 	"sigs.k8s.io/karpenter/pkg/controllers/node/termination"
 	"sigs.k8s.io/karpenter/pkg/controllers/node/termination/terminator"
 	"sigs.k8s.io/karpenter/pkg/metrics"
+	"sigs.k8s.io/karpenter/pkg/operator/options"
 	"sigs.k8s.io/karpenter/pkg/test"
 	. "sigs.k8s.io/karpenter/pkg/test/expectations"
 	"sigs.k8s.io/karpenter/pkg/test/v1alpha1"
@@ -59,6 +60,7 @@ func TestAPIs(t *testing.T) {
 }
 
 var _ = BeforeSuite(func() {
+	ctx = options.ToContext(ctx, test.Options())
 	fakeClock = clock.NewFakeClock(time.Now())
 	env = test.NewEnvironment(
 		test.WithCRDs(apis.CRDs...),
@@ -100,6 +102,8 @@ var _ = Describe("Termination", func() {
 		termination.DurationSeconds.Reset()
 		termination.NodeLifetimeDurationSeconds.Reset()
 		termination.NodesDrainedTotal.Reset()
+		termination.NodesForceDeletedTotal.Reset()
+		metrics.NodeClaimsPhaseDurationSeconds.Reset()
 	})
 
 	Context("Reconciliation", func() {
@@ -564,6 +568,36 @@ var _ = Describe("Termination", func() {
 			ExpectObjectReconciled(ctx, env.Client, terminationController, node)
 			ExpectNotFound(ctx, env.Client, node)
 		})
+		It("should record the Draining and Drained conditions on the nodeclaim", func() {
+			pod := test.Pod(test.PodOptions{NodeName: node.Name, ObjectMeta: metav1.ObjectMeta{OwnerReferences: defaultOwnerRefs}})
+			ExpectApplied(ctx, env.Client, node, nodeClaim, pod)
+
+			// Trigger Termination Controller
+			Expect(env.Client.Delete(ctx, node)).To(Succeed())
+			node = ExpectNodeExists(ctx, env.Client, node.Name)
+			ExpectObjectReconciled(ctx, env.Client, terminationController, node)
+			ExpectSingletonReconciled(ctx, queue)
+			EventuallyExpectTerminating(ctx, env.Client, pod)
+
+			// The nodeclaim should be draining until the pod is deleted
+			node = ExpectNodeExists(ctx, env.Client, node.Name)
+			ExpectObjectReconciled(ctx, env.Client, terminationController, node)
+			nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+			Expect(nodeClaim.StatusConditions().Get(v1.ConditionTypeDrained).IsUnknown()).To(BeTrue())
+			Expect(nodeClaim.StatusConditions().Get(v1.ConditionTypeDrained).Reason).To(Equal("Draining"))
+			Expect(nodeClaim.StatusConditions().Get(v1.ConditionTypeDraining).IsTrue()).To(BeTrue())
+			drainingSince := nodeClaim.StatusConditions().Get(v1.ConditionTypeDraining).LastTransitionTime
+
+			ExpectDeleted(ctx, env.Client, pod)
+			node = ExpectNodeExists(ctx, env.Client, node.Name)
+			ExpectObjectReconciled(ctx, env.Client, terminationController, node)
+			nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+			Expect(nodeClaim.StatusConditions().Get(v1.ConditionTypeDrained).IsTrue()).To(BeTrue())
+			Expect(nodeClaim.StatusConditions().Get(v1.ConditionTypeDraining).LastTransitionTime).To(Equal(drainingSince))
+			m, ok := FindMetricWithLabelValues("karpenter_nodeclaims_phase_duration_seconds", map[string]string{"phase": v1.ConditionTypeDrained, "nodepool": node.Labels[v1.NodePoolLabelKey]})
+			Expect(ok).To(BeTrue())
+			Expect(m.GetHistogram().GetSampleCount()).To(BeNumerically("==", 1))
+		})
 		It("should delete nodes with no underlying instance even if not fully drained", func() {
 			pods := test.Pods(2, test.PodOptions{NodeName: node.Name, ObjectMeta: metav1.ObjectMeta{OwnerReferences: defaultOwnerRefs}})
 			ExpectApplied(ctx, env.Client, node, nodeClaim, pods[0], pods[1])
@@ -836,6 +870,77 @@ var _ = Describe("Termination", func() {
 			})
 		})
 	})
+	Context("Force Delete", func() {
+		BeforeEach(func() {
+			nodeClaim.Annotations = lo.Assign(nodeClaim.Annotations, map[string]string{v1.ForceDeleteAnnotationKey: "true"})
+			recorder.Reset()
+		})
+		It("should delete pods without evicting them, bypassing PDBs and the do-not-disrupt annotation", func() {
+			minAvailable := intstr.FromInt32(1)
+			labelSelector := map[string]string{test.RandomName(): test.RandomName()}
+			pdb := test.PodDisruptionBudget(test.PDBOptions{
+				Labels:       labelSelector,
+				MinAvailable: &minAvailable,
+			})
+			podPDB := test.Pod(test.PodOptions{
+				NodeName:   node.Name,
+				ObjectMeta: metav1.ObjectMeta{Labels: labelSelector, OwnerReferences: defaultOwnerRefs},
+				Phase:      corev1.PodRunning,
+			})
+			podDoNotDisrupt := test.Pod(test.PodOptions{
+				NodeName: node.Name,
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations:     map[string]string{v1.DoNotDisruptAnnotationKey: "true"},
+					OwnerReferences: defaultOwnerRefs,
+				},
+				Phase: corev1.PodRunning,
+			})
+			ExpectApplied(ctx, env.Client, node, nodeClaim, podPDB, podDoNotDisrupt, pdb)
+
+			Expect(env.Client.Delete(ctx, node)).To(Succeed())
+			node = ExpectNodeExists(ctx, env.Client, node.Name)
+			ExpectObjectReconciled(ctx, env.Client, terminationController, node)
+			// The pods aren't deleted until the instance is terminated
+			ExpectExists(ctx, env.Client, podPDB)
+			ExpectExists(ctx, env.Client, podDoNotDisrupt)
+			Expect(queue.Has(node, podPDB)).To(BeFalse())
+			Expect(recorder.Calls("ForceDeleting")).To(BeNumerically(">=", 1))
+			Expect(cloudProvider.DeleteCalls).To(HaveLen(1))
+
+			ExpectObjectReconciled(ctx, env.Client, terminationController, node)
+			ExpectNotFound(ctx, env.Client, podPDB, podDoNotDisrupt, node)
+			Expect(recorder.Calls("ForceDeleted")).To(Equal(2))
+			ExpectMetricCounterValue(termination.NodesForceDeletedTotal, 1, map[string]string{"nodepool": node.Labels[v1.NodePoolLabelKey]})
+		})
+		It("should not delete the pods of retained instances", func() {
+			nodeClaim.Spec.InstanceRetentionPolicy = v1.InstanceRetentionPolicyRetain
+			pod := test.Pod(test.PodOptions{
+				NodeName:   node.Name,
+				ObjectMeta: metav1.ObjectMeta{OwnerReferences: defaultOwnerRefs},
+				Phase:      corev1.PodRunning,
+			})
+			ExpectApplied(ctx, env.Client, node, nodeClaim, pod)
+
+			Expect(env.Client.Delete(ctx, node)).To(Succeed())
+			node = ExpectNodeExists(ctx, env.Client, node.Name)
+			ExpectObjectReconciled(ctx, env.Client, terminationController, node)
+			ExpectNotFound(ctx, env.Client, node)
+			ExpectExists(ctx, env.Client, pod)
+			Expect(cloudProvider.DeleteCalls).To(BeEmpty())
+		})
+		It("should not wait for volume attachments", func() {
+			va := test.VolumeAttachment(test.VolumeAttachmentOptions{
+				NodeName:   node.Name,
+				VolumeName: "foo",
+			})
+			ExpectApplied(ctx, env.Client, node, nodeClaim, va)
+			Expect(env.Client.Delete(ctx, node)).To(Succeed())
+
+			ExpectObjectReconciled(ctx, env.Client, terminationController, node)
+			ExpectObjectReconciled(ctx, env.Client, terminationController, node)
+			ExpectNotFound(ctx, env.Client, node)
+		})
+	})
 	Context("Metrics", func() {
 		It("should fire the terminationSummary metric when deleting nodes", func() {
 			ExpectApplied(ctx, env.Client, node, nodeClaim)
@@ -46,6 +46,26 @@ func DisruptPodDelete(pod *corev1.Pod, gracePeriodSeconds *int64, nodeGracePerio
 	}
 }
 
+func ForceDeletePod(pod *corev1.Pod) events.Event {
+	return events.Event{
+		InvolvedObject: pod,
+		Type:           corev1.EventTypeWarning,
+		Reason:         "ForceDeleted",
+		Message:        "Deleting the pod without evicting it to force delete its node. This bypasses the PDB of the pod and the do-not-disrupt annotation.",
+		DedupeValues:   []string{pod.Name},
+	}
+}
+
+func NodeForceDeleting(node *corev1.Node) events.Event {
+	return events.Event{
+		InvolvedObject: node,
+		Type:           corev1.EventTypeWarning,
+		Reason:         "ForceDeleting",
+		Message:        fmt.Sprintf("Force deleting node as its nodeclaim is annotated with %s=true, pods are deleted without being evicted", v1.ForceDeleteAnnotationKey),
+		DedupeValues:   []string{node.Name},
+	}
+}
+
 func NodeFailedToDrain(node *corev1.Node, err error) events.Event {
 	return events.Event{
 		InvolvedObject: node,
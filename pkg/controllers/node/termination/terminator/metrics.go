@@ -17,7 +17,6 @@ limitations under the License.
 package terminator
 
 import (
-	opmetrics "github.com/awslabs/operatorpkg/metrics"
 	"github.com/prometheus/client_golang/prometheus"
 	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 
@@ -29,7 +28,7 @@ const (
 	CodeLabel = "code"
 )
 
-var NodesEvictionRequestsTotal = opmetrics.NewPrometheusCounter(
+var NodesEvictionRequestsTotal = metrics.NewPrometheusCounter(
 	crmetrics.Registry,
 	prometheus.CounterOpts{
 		Namespace: metrics.Namespace,
@@ -116,6 +116,25 @@ func (t *Terminator) Drain(ctx context.Context, node *corev1.Node, nodeGracePeri
 	return nil
 }
 
+// ForceDrain deletes the pods on the node without evicting them, so that a wedged node can be removed. This bypasses the
+// PDBs and do-not-disrupt annotations of the pods, and doesn't wait for them to terminate, so it must only be called
+// once the node's instance is terminated. Only mirror pods, which can't be deleted through the API server, and terminal
+// pods are left on the node.
+func (t *Terminator) ForceDrain(ctx context.Context, node *corev1.Node) error {
+	pods, err := nodeutils.GetPods(ctx, t.kubeClient, node)
+	if err != nil {
+		return fmt.Errorf("listing pods on node, %w", err)
+	}
+	for _, pod := range lo.Reject(pods, func(p *corev1.Pod, _ int) bool { return podutil.IsTerminal(p) || podutil.IsOwnedByNode(p) }) {
+		t.recorder.Publish(terminatorevents.ForceDeletePod(pod))
+		if err := t.kubeClient.Delete(ctx, pod, &client.DeleteOptions{GracePeriodSeconds: lo.ToPtr[int64](0)}); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("deleting pod, %w", err)
+		}
+		log.FromContext(ctx).WithValues("namespace", pod.Namespace, "name", pod.Name).V(1).Info("force deleted pod")
+	}
+	return nil
+}
+
 func (t *Terminator) groupPodsByPriority(pods []*corev1.Pod) [][]*corev1.Pod {
 	// 1. Prioritize noncritical pods, non-daemon pods https://kubernetes.io/docs/concepts/architecture/nodes/#graceful-node-shutdown
 	var nonCriticalNonDaemon, nonCriticalDaemon, criticalNonDaemon, criticalDaemon []*corev1.Pod
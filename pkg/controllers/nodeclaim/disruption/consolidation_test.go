@@ -21,6 +21,7 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/samber/lo"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -131,6 +132,35 @@ var _ = Describe("Underutilized", func() {
 		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
 		Expect(nodeClaim.StatusConditions().Get(v1.ConditionTypeConsolidatable)).To(BeNil())
 	})
+	It("should remove the status condition from the nodeClaim when consolidateAfter is never for its capacity type", func() {
+		nodeClaim.Labels[v1.CapacityTypeLabelKey] = v1.CapacityTypeSpot
+		nodePool.Spec.Disruption.CapacityTypes = []v1.CapacityTypeDisruption{
+			{CapacityType: v1.CapacityTypeSpot, ConsolidateAfter: lo.ToPtr(v1.MustParseNillableDuration("Never"))},
+		}
+		nodeClaim.StatusConditions().SetTrue(v1.ConditionTypeConsolidatable)
+		ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
+
+		ExpectObjectReconciled(ctx, env.Client, nodeClaimDisruptionController, nodeClaim)
+		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+		Expect(nodeClaim.StatusConditions().Get(v1.ConditionTypeConsolidatable)).To(BeNil())
+	})
+	It("should mark NodeClaims as consolidatable using the consolidateAfter of their capacity type", func() {
+		nodeClaim.Labels[v1.CapacityTypeLabelKey] = v1.CapacityTypeSpot
+		nodePool.Spec.Disruption.CapacityTypes = []v1.CapacityTypeDisruption{
+			{CapacityType: v1.CapacityTypeSpot, ConsolidateAfter: lo.ToPtr(v1.MustParseNillableDuration("10m"))},
+			{CapacityType: v1.CapacityTypeOnDemand, ConsolidateAfter: lo.ToPtr(v1.MustParseNillableDuration("Never"))},
+		}
+		ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
+		ExpectObjectReconciled(ctx, env.Client, nodeClaimDisruptionController, nodeClaim)
+		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+		Expect(nodeClaim.StatusConditions().Get(v1.ConditionTypeConsolidatable).IsTrue()).To(BeFalse())
+
+		fakeClock.Step(5 * time.Minute)
+
+		ExpectObjectReconciled(ctx, env.Client, nodeClaimDisruptionController, nodeClaim)
+		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+		Expect(nodeClaim.StatusConditions().Get(v1.ConditionTypeConsolidatable).IsTrue()).To(BeTrue())
+	})
 	It("should remove the status condition from the nodeClaim when the nodeClaim initialization condition is unknown", func() {
 		nodeClaim.StatusConditions().SetTrue(v1.ConditionTypeConsolidatable)
 		nodeClaim.StatusConditions().SetUnknown(v1.ConditionTypeInitialized)
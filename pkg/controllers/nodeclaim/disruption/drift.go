@@ -85,7 +85,7 @@ func (d *Drift) isDrifted(ctx context.Context, nodePool *v1.NodePool, nodeClaim
 		return reason, nil
 	}
 	// Include instance type checking separate from the other two to reduce the amount of times we grab the instance types.
-	its, err := d.cloudProvider.GetInstanceTypes(ctx, nodePool)
+	its, err := d.cloudProvider.GetInstanceTypes(ctx, nodePool.WithActiveNodeClassRef())
 	if err != nil {
 		return "", err
 	}
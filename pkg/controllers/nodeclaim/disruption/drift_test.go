@@ -189,6 +189,15 @@ var _ = Describe("Drift", func() {
 		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
 		Expect(nodeClaim.StatusConditions().Get(v1.ConditionTypeDrifted)).To(BeNil())
 	})
+	It("should not detect drift on standalone NodeClaims", func() {
+		cp.Drifted = "drifted"
+		delete(nodeClaim.Labels, v1.NodePoolLabelKey)
+		ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
+		ExpectObjectReconciled(ctx, env.Client, nodeClaimDisruptionController, nodeClaim)
+
+		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+		Expect(nodeClaim.StatusConditions().Get(v1.ConditionTypeDrifted)).To(BeNil())
+	})
 	It("should remove the status condition from the nodeClaim if the nodeClaim is no longer drifted", func() {
 		cp.Drifted = ""
 		nodeClaim.StatusConditions().SetTrue(v1.ConditionTypeDrifted)
@@ -21,6 +21,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/samber/lo"
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/utils/clock"
 	controllerruntime "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
@@ -52,22 +54,38 @@ func NewController(clk clock.Clock, kubeClient client.Client, cloudProvider clou
 }
 
 func (c *Controller) Reconcile(ctx context.Context, nodeClaim *v1.NodeClaim) (reconcile.Result, error) {
-	if !nodeclaimutils.IsManaged(nodeClaim, c.cloudProvider) {
+	if !nodeclaimutils.IsManaged(ctx, nodeClaim, c.cloudProvider) {
 		return reconcile.Result{}, nil
 	}
+	// NodeClaims that have reached their MaxLifetime are forcefully drained, even when they're already being deleted
+	var requeueAfter time.Duration
+	if nodeClaim.Spec.MaxLifetime != nil {
+		maxLifetimeTime := nodeClaim.CreationTimestamp.Add(nodeClaim.Spec.MaxLifetime.Duration)
+		if !c.clock.Now().Before(maxLifetimeTime) {
+			if err := c.enforceMaxLifetime(ctx, nodeClaim); err != nil {
+				if errors.IsConflict(err) {
+					return reconcile.Result{Requeue: true}, nil
+				}
+				return reconcile.Result{}, client.IgnoreNotFound(err)
+			}
+			return reconcile.Result{}, nil
+		}
+		requeueAfter = maxLifetimeTime.Sub(c.clock.Now())
+	}
 	if !nodeClaim.DeletionTimestamp.IsZero() {
-		return reconcile.Result{}, nil
+		return reconcile.Result{RequeueAfter: requeueAfter}, nil
 	}
 	// From here there are three scenarios to handle:
 	// 1. If ExpireAfter is not configured, exit expiration loop
-	if nodeClaim.Spec.ExpireAfter.Duration == nil {
-		return reconcile.Result{}, nil
+	expireAfter := nodeclaimutils.ExpireAfter(nodeClaim)
+	if expireAfter == nil {
+		return reconcile.Result{RequeueAfter: requeueAfter}, nil
 	}
-	expirationTime := nodeClaim.CreationTimestamp.Add(*nodeClaim.Spec.ExpireAfter.Duration)
+	expirationTime := nodeClaim.CreationTimestamp.Add(*expireAfter)
 	// 2. If the NodeClaim isn't expired leave the reconcile loop.
 	if c.clock.Now().Before(expirationTime) {
 		// Use t.Sub(clock.Now()) instead of time.Until() to ensure we're using the injected clock.
-		return reconcile.Result{RequeueAfter: expirationTime.Sub(c.clock.Now())}, nil
+		return reconcile.Result{RequeueAfter: lo.Min(lo.Compact([]time.Duration{requeueAfter, expirationTime.Sub(c.clock.Now())}))}, nil
 	}
 	// 3. Otherwise, if the NodeClaim is expired we can forcefully expire the nodeclaim (by deleting it)
 	if err := c.kubeClient.Delete(ctx, nodeClaim); err != nil {
@@ -87,9 +105,40 @@ func (c *Controller) Reconcile(ctx context.Context, nodeClaim *v1.NodeClaim) (re
 	return reconcile.Result{}, nil
 }
 
-func (c *Controller) Register(_ context.Context, m manager.Manager) error {
+// enforceMaxLifetime sets the termination timestamp of the NodeClaim to now and deletes it, so that its node is drained
+// without respecting PDBs or the karpenter.sh/do-not-disrupt annotation, as if its TerminationGracePeriod had elapsed
+func (c *Controller) enforceMaxLifetime(ctx context.Context, nodeClaim *v1.NodeClaim) error {
+	terminationTime, err := time.Parse(time.RFC3339, nodeClaim.Annotations[v1.NodeClaimTerminationTimestampAnnotationKey])
+	if err != nil || terminationTime.After(c.clock.Now()) {
+		stored := nodeClaim.DeepCopy()
+		nodeClaim.Annotations = lo.Assign(nodeClaim.Annotations, map[string]string{v1.NodeClaimTerminationTimestampAnnotationKey: c.clock.Now().Format(time.RFC3339)})
+		// We use client.MergeFromWithOptimisticLock because the lifecycle controller also sets the termination timestamp,
+		// and we don't want it to overwrite ours with a later one
+		if err = c.kubeClient.Patch(ctx, nodeClaim, client.MergeFromWithOptions(stored, client.MergeFromWithOptimisticLock{})); err != nil {
+			return err
+		}
+		log.FromContext(ctx).WithValues(v1.NodeClaimTerminationTimestampAnnotationKey, nodeClaim.Annotations[v1.NodeClaimTerminationTimestampAnnotationKey]).Info("annotated nodeclaim")
+	}
+	if !nodeClaim.DeletionTimestamp.IsZero() {
+		return nil
+	}
+	if err = c.kubeClient.Delete(ctx, nodeClaim); err != nil {
+		return err
+	}
+	log.FromContext(ctx).V(1).Info("deleting nodeclaim that reached its max lifetime")
+	metrics.NodeClaimsDisruptedTotal.Inc(map[string]string{
+		metrics.ReasonLabel:       metrics.MaxLifetimeReason,
+		metrics.NodePoolLabel:     nodeClaim.Labels[v1.NodePoolLabelKey],
+		metrics.CapacityTypeLabel: nodeClaim.Labels[v1.CapacityTypeLabelKey],
+	})
+	// As with expiration, we sleep to read our own writes and avoid duplicating metrics and log lines
+	time.Sleep(time.Second)
+	return nil
+}
+
+func (c *Controller) Register(ctx context.Context, m manager.Manager) error {
 	return controllerruntime.NewControllerManagedBy(m).
 		Named("nodeclaim.expiration").
-		For(&v1.NodeClaim{}, builder.WithPredicates(nodeclaimutils.IsManagedPredicateFuncs(c.cloudProvider))).
+		For(&v1.NodeClaim{}, builder.WithPredicates(nodeclaimutils.IsManagedPredicateFuncs(ctx, c.cloudProvider))).
 		Complete(reconcile.AsReconciler(m.GetClient(), c))
 }
@@ -23,6 +23,7 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/samber/lo"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	clock "k8s.io/utils/clock/testing"
@@ -169,6 +170,15 @@ var _ = Describe("Expiration", func() {
 
 		ExpectNotFound(ctx, env.Client, nodeClaim)
 	})
+	It("should delete standalone NodeClaims if the nodeClaim is expired", func() {
+		delete(nodeClaim.Labels, v1.NodePoolLabelKey)
+		ExpectApplied(ctx, env.Client, nodeClaim, node)
+
+		fakeClock.Step(60 * time.Second)
+		ExpectObjectReconciled(ctx, env.Client, expirationController, nodeClaim)
+
+		ExpectNotFound(ctx, env.Client, nodeClaim)
+	})
 	It("should return the requeue interval for the time between now and when the nodeClaim expires", func() {
 		nodeClaim.Spec.ExpireAfter = v1.MustParseNillableDuration("200s")
 		ExpectApplied(ctx, env.Client, nodeClaim, node)
@@ -178,6 +188,71 @@ var _ = Describe("Expiration", func() {
 		result := ExpectObjectReconciled(ctx, env.Client, expirationController, nodeClaim)
 		Expect(result.RequeueAfter).To(BeNumerically("~", time.Second*100, time.Second))
 	})
+	It("should delay the expiration of the NodeClaim by up to its jitter", func() {
+		nodeClaim.Spec.ExpireAfter = v1.MustParseNillableDuration("200s")
+		nodeClaim.Spec.ExpireAfterJitter = &metav1.Duration{Duration: time.Second * 100}
+		ExpectApplied(ctx, env.Client, nodeClaim, node)
+
+		fakeClock.SetTime(nodeClaim.CreationTimestamp.Time.Add(time.Second * 100))
+
+		result := ExpectObjectReconciled(ctx, env.Client, expirationController, nodeClaim)
+		Expect(result.RequeueAfter).To(BeNumerically(">=", time.Second*99))
+		Expect(result.RequeueAfter).To(BeNumerically("<", time.Second*201))
+		Expect(result.RequeueAfter).To(Equal(ExpectObjectReconciled(ctx, env.Client, expirationController, nodeClaim).RequeueAfter))
+
+		// step past the maximum jitter to make the node expired
+		fakeClock.Step(time.Second * 200)
+		ExpectObjectReconciled(ctx, env.Client, expirationController, nodeClaim)
+		ExpectNotFound(ctx, env.Client, nodeClaim)
+	})
+	Context("MaxLifetime", func() {
+		BeforeEach(func() {
+			nodeClaim.ObjectMeta.Finalizers = append(nodeClaim.ObjectMeta.Finalizers, "test-finalizer")
+			nodeClaim.Spec.ExpireAfter = v1.MustParseNillableDuration("Never")
+			nodeClaim.Spec.MaxLifetime = &metav1.Duration{Duration: time.Second * 200}
+		})
+		It("should return the requeue interval for the time between now and when the nodeClaim reaches its max lifetime", func() {
+			ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
+
+			fakeClock.SetTime(nodeClaim.CreationTimestamp.Time.Add(time.Second * 100))
+
+			result := ExpectObjectReconciled(ctx, env.Client, expirationController, nodeClaim)
+			Expect(result.RequeueAfter).To(BeNumerically("~", time.Second*100, time.Second))
+			nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+			Expect(nodeClaim.DeletionTimestamp.IsZero()).To(BeTrue())
+		})
+		It("should set the termination timestamp and delete the NodeClaim when it reaches its max lifetime", func() {
+			ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
+
+			fakeClock.SetTime(nodeClaim.CreationTimestamp.Time.Add(time.Second * 300))
+			ExpectObjectReconciled(ctx, env.Client, expirationController, nodeClaim)
+
+			nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+			Expect(nodeClaim.DeletionTimestamp.IsZero()).To(BeFalse())
+			Expect(nodeClaim.Annotations).To(HaveKeyWithValue(v1.NodeClaimTerminationTimestampAnnotationKey, fakeClock.Now().Format(time.RFC3339)))
+			ExpectMetricCounterValue(metrics.NodeClaimsDisruptedTotal, 1, map[string]string{
+				metrics.ReasonLabel: metrics.MaxLifetimeReason,
+				"nodepool":          nodePool.Name,
+			})
+		})
+		It("should move the termination timestamp of a deleting NodeClaim forward when it reaches its max lifetime", func() {
+			nodeClaim.Annotations = lo.Assign(nodeClaim.Annotations, map[string]string{
+				v1.NodeClaimTerminationTimestampAnnotationKey: fakeClock.Now().Add(time.Hour).Format(time.RFC3339),
+			})
+			ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
+			ExpectDeletionTimestampSet(ctx, env.Client, nodeClaim)
+
+			fakeClock.SetTime(nodeClaim.CreationTimestamp.Time.Add(time.Second * 300))
+			ExpectObjectReconciled(ctx, env.Client, expirationController, nodeClaim)
+
+			nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+			Expect(nodeClaim.Annotations).To(HaveKeyWithValue(v1.NodeClaimTerminationTimestampAnnotationKey, fakeClock.Now().Format(time.RFC3339)))
+			_, found := FindMetricWithLabelValues("karpenter_nodeclaims_disrupted_total", map[string]string{
+				metrics.ReasonLabel: metrics.MaxLifetimeReason,
+			})
+			Expect(found).To(BeFalse())
+		})
+	})
 	It("shouldn't expire the same NodeClaim multiple times", func() {
 		nodeClaim.ObjectMeta.Finalizers = append(nodeClaim.ObjectMeta.Finalizers, "test-finalizer")
 		ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
@@ -18,6 +18,7 @@ package garbagecollection
 
 import (
 	"context"
+	"strconv"
 	"time"
 
 	"github.com/awslabs/operatorpkg/singleton"
@@ -36,23 +37,32 @@ import (
 
 	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
 	"sigs.k8s.io/karpenter/pkg/cloudprovider"
+	"sigs.k8s.io/karpenter/pkg/events"
 	"sigs.k8s.io/karpenter/pkg/metrics"
 	"sigs.k8s.io/karpenter/pkg/operator/injection"
+	"sigs.k8s.io/karpenter/pkg/operator/options"
 	nodeutils "sigs.k8s.io/karpenter/pkg/utils/node"
 	nodeclaimutils "sigs.k8s.io/karpenter/pkg/utils/nodeclaim"
 )
 
+// orphanedInstanceGracePeriod is how long after its creation an instance without a NodeClaim is considered orphaned.
+// Instances are launched before their NodeClaim is updated with their provider ID, so newer instances may still be
+// waiting for their NodeClaim.
+const orphanedInstanceGracePeriod = time.Minute
+
 type Controller struct {
 	clock         clock.Clock
 	kubeClient    client.Client
 	cloudProvider cloudprovider.CloudProvider
+	recorder      events.Recorder
 }
 
-func NewController(c clock.Clock, kubeClient client.Client, cloudProvider cloudprovider.CloudProvider) *Controller {
+func NewController(c clock.Clock, kubeClient client.Client, cloudProvider cloudprovider.CloudProvider, recorder events.Recorder) *Controller {
 	return &Controller{
 		clock:         c,
 		kubeClient:    kubeClient,
 		cloudProvider: cloudProvider,
+		recorder:      recorder,
 	}
 }
 
@@ -113,7 +123,53 @@ func (c *Controller) Reconcile(ctx context.Context) (reconcile.Result, error) {
 	if err = multierr.Combine(errs...); err != nil {
 		return reconcile.Result{}, err
 	}
-	return reconcile.Result{RequeueAfter: time.Minute * 2}, nil
+	if err = c.collectOrphanedInstances(ctx, cloudProviderNodeClaims); err != nil {
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{RequeueAfter: options.FromContext(ctx).GCInterval}, nil
+}
+
+// collectOrphanedInstances finds the cloudprovider instances that have no NodeClaim, and deletes them unless garbage
+// collection is in dry-run mode. NodeClaims of every instance of Karpenter are considered, so that instances launched
+// for the NodePools of another instance of Karpenter are never orphaned. Instances that were launched with the Retain
+// instance retention policy are left running on purpose when their NodeClaim is deleted, so they're never orphaned.
+func (c *Controller) collectOrphanedInstances(ctx context.Context, cloudProviderNodeClaims []*v1.NodeClaim) error {
+	nodeClaimList := &v1.NodeClaimList{}
+	if err := c.kubeClient.List(ctx, nodeClaimList); err != nil {
+		return err
+	}
+	providerIDs := sets.New(lo.FilterMap(nodeClaimList.Items, func(nc v1.NodeClaim, _ int) (string, bool) {
+		return nc.Status.ProviderID, nc.Status.ProviderID != ""
+	})...)
+	dryRun := options.FromContext(ctx).GCDryRun
+	orphaned := lo.Filter(cloudProviderNodeClaims, func(nc *v1.NodeClaim, _ int) bool {
+		// Instances without a creation time can't be told apart from instances that are still waiting for their NodeClaim
+		return !providerIDs.Has(nc.Status.ProviderID) &&
+			nc.Labels[v1.ManagedByLabelKey] == options.FromContext(ctx).ManagedBy &&
+			nc.Spec.InstanceRetentionPolicy != v1.InstanceRetentionPolicyRetain &&
+			!nc.CreationTimestamp.IsZero() && c.clock.Since(nc.CreationTimestamp.Time) > orphanedInstanceGracePeriod
+	})
+	OrphanedInstances.Set(float64(len(orphaned)), map[string]string{dryRunLabel: strconv.FormatBool(dryRun)})
+	OrphanedInstances.Delete(map[string]string{dryRunLabel: strconv.FormatBool(!dryRun)})
+
+	errs := make([]error, len(orphaned))
+	workqueue.ParallelizeUntil(ctx, 20, len(orphaned), func(i int) {
+		ctx := log.IntoContext(ctx, log.FromContext(ctx).WithValues("provider-id", orphaned[i].Status.ProviderID, "dry-run", dryRun))
+		// The event is published on the instance's node, if it registered one
+		if node, err := nodeclaimutils.NodeForNodeClaim(ctx, c.kubeClient, orphaned[i]); err == nil {
+			c.recorder.Publish(OrphanedInstanceEvent(node, orphaned[i].Status.ProviderID, dryRun))
+		}
+		if dryRun {
+			log.FromContext(ctx).Info("found instance with no nodeclaim, skipping deletion in dry-run mode")
+			return
+		}
+		if err := c.cloudProvider.Delete(ctx, orphaned[i]); cloudprovider.IgnoreNodeClaimNotFoundError(err) != nil {
+			errs[i] = err
+			return
+		}
+		log.FromContext(ctx).V(1).Info("garbage collecting instance with no nodeclaim")
+	})
+	return multierr.Combine(errs...)
 }
 
 func (c *Controller) Register(_ context.Context, m manager.Manager) error {
@@ -0,0 +1,39 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package garbagecollection
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"sigs.k8s.io/karpenter/pkg/events"
+)
+
+func OrphanedInstanceEvent(node *corev1.Node, providerID string, dryRun bool) events.Event {
+	message := fmt.Sprintf("Deleting instance %s, which has no NodeClaim", providerID)
+	if dryRun {
+		message = fmt.Sprintf("Instance %s has no NodeClaim and would be deleted if garbage collection wasn't in dry-run mode", providerID)
+	}
+	return events.Event{
+		InvolvedObject: node,
+		Type:           corev1.EventTypeWarning,
+		Reason:         "OrphanedInstance",
+		Message:        message,
+		DedupeValues:   []string{providerID},
+	}
+}
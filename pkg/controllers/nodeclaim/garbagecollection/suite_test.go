@@ -36,6 +36,7 @@ import (
 	"sigs.k8s.io/karpenter/pkg/cloudprovider/fake"
 	nodeclaimgarbagecollection "sigs.k8s.io/karpenter/pkg/controllers/nodeclaim/garbagecollection"
 	nodeclaimlifcycle "sigs.k8s.io/karpenter/pkg/controllers/nodeclaim/lifecycle"
+	"sigs.k8s.io/karpenter/pkg/controllers/state"
 	"sigs.k8s.io/karpenter/pkg/events"
 	"sigs.k8s.io/karpenter/pkg/operator/options"
 	"sigs.k8s.io/karpenter/pkg/test"
@@ -62,8 +63,8 @@ var _ = BeforeSuite(func() {
 	env = test.NewEnvironment(test.WithCRDs(apis.CRDs...), test.WithCRDs(v1alpha1.CRDs...), test.WithFieldIndexers(test.NodeProviderIDFieldIndexer(ctx)))
 	ctx = options.ToContext(ctx, test.Options())
 	cloudProvider = fake.NewCloudProvider()
-	garbageCollectionController = nodeclaimgarbagecollection.NewController(fakeClock, env.Client, cloudProvider)
-	nodeClaimController = nodeclaimlifcycle.NewController(fakeClock, env.Client, cloudProvider, events.NewRecorder(&record.FakeRecorder{}))
+	garbageCollectionController = nodeclaimgarbagecollection.NewController(fakeClock, env.Client, cloudProvider, events.NewRecorder(&record.FakeRecorder{}))
+	nodeClaimController = nodeclaimlifcycle.NewController(fakeClock, env.Client, cloudProvider, state.NewCluster(fakeClock, env.Client, cloudProvider), events.NewRecorder(&record.FakeRecorder{}))
 })
 
 var _ = AfterSuite(func() {
@@ -109,6 +110,20 @@ var _ = Describe("GarbageCollection", func() {
 		ExpectFinalizersRemoved(ctx, env.Client, nodeClaim)
 		ExpectNotFound(ctx, env.Client, nodeClaim)
 	})
+	It("should delete standalone NodeClaims when the Node is there in a NotReady state and the instance is gone", func() {
+		nodeClaim := test.NodeClaim()
+		ExpectApplied(ctx, env.Client, nodeClaim)
+
+		nodeClaim, node, err := ExpectNodeClaimDeployed(ctx, env.Client, cloudProvider, nodeClaim)
+		Expect(err).ToNot(HaveOccurred())
+		ExpectMakeNodesNotReady(ctx, env.Client, node)
+		fakeClock.SetTime(time.Now().Add(time.Second * 20))
+		Expect(cloudProvider.Delete(ctx, nodeClaim)).To(Succeed())
+
+		ExpectSingletonReconciled(ctx, garbageCollectionController)
+		ExpectFinalizersRemoved(ctx, env.Client, nodeClaim)
+		ExpectNotFound(ctx, env.Client, nodeClaim)
+	})
 	It("shouldn't delete the NodeClaim when the Node is there in a Ready state and the instance is gone", func() {
 		nodeClaim := test.NodeClaim(v1.NodeClaim{
 			ObjectMeta: metav1.ObjectMeta{
@@ -220,4 +235,70 @@ var _ = Describe("GarbageCollection", func() {
 		ExpectFinalizersRemoved(ctx, env.Client, nodeClaim)
 		ExpectExists(ctx, env.Client, nodeClaim)
 	})
+	Context("Orphaned Instances", func() {
+		var instance *v1.NodeClaim
+		BeforeEach(func() {
+			instance = test.NodeClaim(v1.NodeClaim{
+				ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(fakeClock.Now())},
+				Status:     v1.NodeClaimStatus{ProviderID: test.RandomProviderID()},
+			})
+			cloudProvider.CreatedNodeClaims[instance.Status.ProviderID] = instance
+		})
+		AfterEach(func() {
+			ctx = options.ToContext(ctx, test.Options())
+		})
+		It("shouldn't delete instances without a NodeClaim in dry-run mode", func() {
+			fakeClock.Step(2 * time.Minute)
+			ExpectSingletonReconciled(ctx, garbageCollectionController)
+			Expect(cloudProvider.CreatedNodeClaims).To(HaveKey(instance.Status.ProviderID))
+			ExpectMetricGaugeValue(nodeclaimgarbagecollection.OrphanedInstances, 1, map[string]string{"dry_run": "true"})
+		})
+		It("should delete instances without a NodeClaim when dry-run mode is disabled", func() {
+			ctx = options.ToContext(ctx, test.Options(test.OptionsFields{GCDryRun: lo.ToPtr(false)}))
+			fakeClock.Step(2 * time.Minute)
+			ExpectSingletonReconciled(ctx, garbageCollectionController)
+			Expect(cloudProvider.CreatedNodeClaims).ToNot(HaveKey(instance.Status.ProviderID))
+		})
+		It("shouldn't delete instances without a NodeClaim that were just launched", func() {
+			ctx = options.ToContext(ctx, test.Options(test.OptionsFields{GCDryRun: lo.ToPtr(false)}))
+			ExpectSingletonReconciled(ctx, garbageCollectionController)
+			Expect(cloudProvider.CreatedNodeClaims).To(HaveKey(instance.Status.ProviderID))
+		})
+		It("shouldn't delete instances that have a NodeClaim", func() {
+			ctx = options.ToContext(ctx, test.Options(test.OptionsFields{GCDryRun: lo.ToPtr(false)}))
+			nodeClaim := test.NodeClaim(v1.NodeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						v1.NodePoolLabelKey: nodePool.Name,
+					},
+				},
+			})
+			ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
+			nodeClaim, _, err := ExpectNodeClaimDeployed(ctx, env.Client, cloudProvider, nodeClaim)
+			Expect(err).ToNot(HaveOccurred())
+
+			fakeClock.Step(2 * time.Minute)
+			ExpectSingletonReconciled(ctx, garbageCollectionController)
+			Expect(cloudProvider.CreatedNodeClaims).To(HaveKey(nodeClaim.Status.ProviderID))
+		})
+		It("shouldn't delete retained instances without a NodeClaim", func() {
+			ctx = options.ToContext(ctx, test.Options(test.OptionsFields{GCDryRun: lo.ToPtr(false)}))
+			retained := test.NodeClaim(v1.NodeClaim{
+				ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(fakeClock.Now())},
+				Spec:       v1.NodeClaimSpec{InstanceRetentionPolicy: v1.InstanceRetentionPolicyRetain},
+				Status:     v1.NodeClaimStatus{ProviderID: test.RandomProviderID()},
+			})
+			cloudProvider.CreatedNodeClaims[retained.Status.ProviderID] = retained
+
+			fakeClock.Step(2 * time.Minute)
+			ExpectSingletonReconciled(ctx, garbageCollectionController)
+			Expect(cloudProvider.CreatedNodeClaims).To(HaveKey(retained.Status.ProviderID))
+			Expect(cloudProvider.CreatedNodeClaims).ToNot(HaveKey(instance.Status.ProviderID))
+			ExpectMetricGaugeValue(nodeclaimgarbagecollection.OrphanedInstances, 1, map[string]string{"dry_run": "false"})
+		})
+		It("should requeue at the configured interval", func() {
+			ctx = options.ToContext(ctx, test.Options(test.OptionsFields{GCInterval: lo.ToPtr(10 * time.Minute)}))
+			Expect(ExpectSingletonReconciled(ctx, garbageCollectionController).RequeueAfter).To(Equal(10 * time.Minute))
+		})
+	})
 })
@@ -21,6 +21,7 @@ import (
 
 	"github.com/awslabs/operatorpkg/reasonable"
 	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/klog/v2"
 	controllerruntime "sigs.k8s.io/controller-runtime"
@@ -39,7 +40,9 @@ import (
 )
 
 // Controller hydrates information to the NodeClaim which is expected in newer versions of Karpenter, but would not
-// exist on pre-existing NodeClaims.
+// exist on pre-existing NodeClaims. The NodeClaim's spec is immutable, so only its metadata and status are hydrated.
+// The NodePool hash annotations of NodeClaims are migrated by the NodePool hash controller, and the fields of NodePools
+// are defaulted by the CRD schema and the NodePool defaulting controller.
 type Controller struct {
 	kubeClient    client.Client
 	cloudProvider cloudprovider.CloudProvider
@@ -55,7 +58,7 @@ func NewController(kubeClient client.Client, cloudProvider cloudprovider.CloudPr
 func (c *Controller) Reconcile(ctx context.Context, nc *v1.NodeClaim) (reconcile.Result, error) {
 	ctx = injection.WithControllerName(ctx, c.Name())
 	ctx = log.IntoContext(ctx, log.FromContext(ctx).WithValues("NodeClaim", klog.KRef(nc.Namespace, nc.Name)))
-	if !nodeclaimutils.IsManaged(nc, c.cloudProvider) {
+	if !nodeclaimutils.IsManaged(ctx, nc, c.cloudProvider) {
 		return reconcile.Result{}, nil
 	}
 
@@ -68,17 +71,43 @@ func (c *Controller) Reconcile(ctx context.Context, nc *v1.NodeClaim) (reconcile
 			return reconcile.Result{}, client.IgnoreNotFound(err)
 		}
 	}
+
+	stored = nc.DeepCopy()
+	hydrateLaunchedInstance(nc)
+	if !equality.Semantic.DeepEqual(stored, nc) {
+		if err := c.kubeClient.Status().Patch(ctx, nc, client.MergeFrom(stored)); err != nil {
+			return reconcile.Result{}, client.IgnoreNotFound(err)
+		}
+	}
 	return reconcile.Result{}, nil
 }
 
+// hydrateLaunchedInstance records the instance that NodeClaims which launched before the launched instance was recorded
+// launched with, from the labels that the CloudProvider resolved at launch. The price that the instance launched at
+// isn't known anymore, so it's left unset.
+func hydrateLaunchedInstance(nc *v1.NodeClaim) {
+	if nc.Status.LaunchedInstance != nil || !nc.StatusConditions().Get(v1.ConditionTypeLaunched).IsTrue() {
+		return
+	}
+	instanceType, ok := nc.Labels[corev1.LabelInstanceTypeStable]
+	if !ok {
+		return
+	}
+	nc.Status.LaunchedInstance = &v1.LaunchedInstance{
+		InstanceType: instanceType,
+		Zone:         nc.Labels[corev1.LabelTopologyZone],
+		CapacityType: nc.Labels[v1.CapacityTypeLabelKey],
+	}
+}
+
 func (c *Controller) Name() string {
 	return "nodeclaim.hydration"
 }
 
-func (c *Controller) Register(_ context.Context, m manager.Manager) error {
+func (c *Controller) Register(ctx context.Context, m manager.Manager) error {
 	return controllerruntime.NewControllerManagedBy(m).
 		Named(c.Name()).
-		For(&v1.NodeClaim{}, builder.WithPredicates(nodeclaimutils.IsManagedPredicateFuncs(c.cloudProvider))).
+		For(&v1.NodeClaim{}, builder.WithPredicates(nodeclaimutils.IsManagedPredicateFuncs(ctx, c.cloudProvider))).
 		WithOptions(controller.Options{
 			RateLimiter:             reasonable.RateLimiter(),
 			MaxConcurrentReconciles: 1000,
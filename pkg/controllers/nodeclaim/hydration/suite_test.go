@@ -23,6 +23,8 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"sigs.k8s.io/karpenter/pkg/apis"
 	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
@@ -95,4 +97,37 @@ var _ = Describe("Hydration", func() {
 		Entry("should hydrate missing metadata onto the NodeClaim", true),
 		Entry("should ignore NodeClaims which aren't managed by this Karpenter instance", false),
 	)
+	It("should hydrate the launched instance of NodeClaims that launched before it was recorded", func() {
+		nodeClaim, _ := test.NodeClaimAndNode(v1.NodeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					corev1.LabelInstanceTypeStable: "default-instance-type",
+					corev1.LabelTopologyZone:       "test-zone-1",
+					v1.CapacityTypeLabelKey:        v1.CapacityTypeOnDemand,
+				},
+			},
+		})
+		nodeClaim.StatusConditions().SetTrue(v1.ConditionTypeLaunched)
+		ExpectApplied(ctx, env.Client, nodeClaim)
+		ExpectObjectReconciled(ctx, env.Client, hydrationController, nodeClaim)
+
+		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+		Expect(nodeClaim.Status.LaunchedInstance).To(Equal(&v1.LaunchedInstance{
+			InstanceType: "default-instance-type",
+			Zone:         "test-zone-1",
+			CapacityType: v1.CapacityTypeOnDemand,
+		}))
+	})
+	It("should not hydrate the launched instance of NodeClaims that haven't launched", func() {
+		nodeClaim := test.NodeClaim(v1.NodeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{corev1.LabelInstanceTypeStable: "default-instance-type"},
+			},
+		})
+		ExpectApplied(ctx, env.Client, nodeClaim)
+		ExpectObjectReconciled(ctx, env.Client, hydrationController, nodeClaim)
+
+		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+		Expect(nodeClaim.Status.LaunchedInstance).To(BeNil())
+	})
 })
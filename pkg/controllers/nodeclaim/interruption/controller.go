@@ -0,0 +1,136 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package interruption
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/util/workqueue"
+	controllerruntime "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+	"sigs.k8s.io/karpenter/pkg/cloudprovider"
+	"sigs.k8s.io/karpenter/pkg/events"
+	nodeclaimutils "sigs.k8s.io/karpenter/pkg/utils/nodeclaim"
+)
+
+// Controller marks the NodeClaims whose instances the cloud provider notices are going to be interrupted as Interrupted,
+// so that the disruption controller drains and deletes them through the NodePool's budgets for the Interrupted reason
+type Controller struct {
+	kubeClient    client.Client
+	cloudProvider cloudprovider.CloudProvider
+	recorder      events.Recorder
+	// interruptions are the notices that haven't been recorded on their NodeClaims yet, keyed by provider ID
+	interruptions sync.Map
+}
+
+func NewController(kubeClient client.Client, cloudProvider cloudprovider.CloudProvider, recorder events.Recorder) *Controller {
+	return &Controller{
+		kubeClient:    kubeClient,
+		cloudProvider: cloudProvider,
+		recorder:      recorder,
+	}
+}
+
+func (c *Controller) Reconcile(ctx context.Context, nodeClaim *v1.NodeClaim) (reconcile.Result, error) {
+	if !nodeclaimutils.IsManaged(ctx, nodeClaim, c.cloudProvider) {
+		return reconcile.Result{}, nil
+	}
+	value, ok := c.interruptions.Load(nodeClaim.Status.ProviderID)
+	if !ok {
+		return reconcile.Result{}, nil
+	}
+	interruption := value.(cloudprovider.Interruption)
+	if !nodeClaim.DeletionTimestamp.IsZero() || nodeClaim.StatusConditions().Get(v1.ConditionTypeInterrupted).IsTrue() {
+		c.interruptions.Delete(nodeClaim.Status.ProviderID)
+		return reconcile.Result{}, nil
+	}
+	stored := nodeClaim.DeepCopy()
+	message := "Instance is going to be interrupted"
+	if !interruption.Time.IsZero() {
+		message = fmt.Sprintf("Instance is going to be interrupted at %s", interruption.Time.UTC().Format(time.RFC3339))
+	}
+	nodeClaim.StatusConditions().SetTrueWithReason(v1.ConditionTypeInterrupted, string(interruption.Kind), message)
+	if err := c.kubeClient.Status().Patch(ctx, nodeClaim, client.MergeFromWithOptions(stored, client.MergeFromWithOptimisticLock{})); err != nil {
+		if errors.IsConflict(err) {
+			return reconcile.Result{Requeue: true}, nil
+		}
+		if errors.IsNotFound(err) {
+			c.interruptions.Delete(nodeClaim.Status.ProviderID)
+		}
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+	log.FromContext(ctx).WithValues("kind", interruption.Kind).Info("marked nodeclaim interrupted")
+	c.recorder.Publish(InterruptedEvent(nodeClaim, interruption))
+	c.interruptions.Delete(nodeClaim.Status.ProviderID)
+	return reconcile.Result{}, nil
+}
+
+// Notify records the interruption notice, so that its NodeClaim is marked Interrupted when it's next reconciled
+func (c *Controller) Notify(interruption cloudprovider.Interruption) {
+	c.interruptions.Store(interruption.ProviderID, interruption)
+}
+
+// watchInterruptions enqueues the NodeClaims of the interruption notices that the cloud provider delivers
+func (c *Controller) watchInterruptions(ctx context.Context, queue workqueue.TypedRateLimitingInterface[reconcile.Request]) error {
+	interruptions := c.cloudProvider.Interruptions()
+	if interruptions == nil {
+		return nil
+	}
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case interruption, ok := <-interruptions:
+				if !ok {
+					return
+				}
+				nodeClaims, err := nodeclaimutils.ListManaged(ctx, c.kubeClient, c.cloudProvider, nodeclaimutils.ForProviderID(interruption.ProviderID))
+				if err != nil {
+					log.FromContext(ctx).Error(err, "failed listing nodeclaims for interruption", "provider-id", interruption.ProviderID)
+					continue
+				}
+				if len(nodeClaims) == 0 {
+					continue
+				}
+				c.Notify(interruption)
+				for _, nodeClaim := range nodeClaims {
+					queue.Add(reconcile.Request{NamespacedName: client.ObjectKeyFromObject(nodeClaim)})
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+func (c *Controller) Register(ctx context.Context, m manager.Manager) error {
+	return controllerruntime.NewControllerManagedBy(m).
+		Named("nodeclaim.interruption").
+		For(&v1.NodeClaim{}, builder.WithPredicates(nodeclaimutils.IsManagedPredicateFuncs(ctx, c.cloudProvider))).
+		WatchesRawSource(source.Func(c.watchInterruptions)).
+		Complete(reconcile.AsReconciler(m.GetClient(), c))
+}
@@ -0,0 +1,142 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package interruption_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	"sigs.k8s.io/karpenter/pkg/apis"
+	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+	"sigs.k8s.io/karpenter/pkg/cloudprovider"
+	"sigs.k8s.io/karpenter/pkg/cloudprovider/fake"
+	"sigs.k8s.io/karpenter/pkg/controllers/nodeclaim/interruption"
+	"sigs.k8s.io/karpenter/pkg/events"
+	"sigs.k8s.io/karpenter/pkg/operator/options"
+	"sigs.k8s.io/karpenter/pkg/test"
+	. "sigs.k8s.io/karpenter/pkg/test/expectations"
+	"sigs.k8s.io/karpenter/pkg/test/v1alpha1"
+	. "sigs.k8s.io/karpenter/pkg/utils/testing"
+)
+
+var ctx context.Context
+var interruptionController *interruption.Controller
+var env *test.Environment
+var cp *fake.CloudProvider
+
+func TestAPIs(t *testing.T) {
+	ctx = TestContextWithLogger(t)
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Interruption")
+}
+
+var _ = BeforeSuite(func() {
+	env = test.NewEnvironment(test.WithCRDs(apis.CRDs...), test.WithCRDs(v1alpha1.CRDs...), test.WithFieldIndexers(test.NodeClaimProviderIDFieldIndexer(ctx)))
+	ctx = options.ToContext(ctx, test.Options())
+	cp = fake.NewCloudProvider()
+	interruptionController = interruption.NewController(env.Client, cp, events.NewRecorder(&record.FakeRecorder{}))
+})
+
+var _ = AfterSuite(func() {
+	Expect(env.Stop()).To(Succeed(), "Failed to stop environment")
+})
+
+var _ = BeforeEach(func() {
+	ctx = options.ToContext(ctx, test.Options())
+})
+
+var _ = AfterEach(func() {
+	ExpectCleanedUp(ctx, env.Client)
+})
+
+var _ = Describe("Interruption", func() {
+	var nodePool *v1.NodePool
+	var nodeClaim *v1.NodeClaim
+	var node *corev1.Node
+	BeforeEach(func() {
+		nodePool = test.NodePool()
+		nodeClaim, node = test.NodeClaimAndNode(v1.NodeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{v1.NodePoolLabelKey: nodePool.Name},
+			},
+		})
+	})
+	It("should mark the nodeclaim interrupted when the cloud provider notices an interruption", func() {
+		ExpectApplied(ctx, env.Client, nodePool, nodeClaim, node)
+		interruptionController.Notify(cloudprovider.Interruption{
+			ProviderID: nodeClaim.Status.ProviderID,
+			Kind:       cloudprovider.InterruptionKindSpotInterruption,
+			Time:       time.Now().Add(2 * time.Minute),
+		})
+		ExpectObjectReconciled(ctx, env.Client, interruptionController, nodeClaim)
+		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+		condition := nodeClaim.StatusConditions().Get(v1.ConditionTypeInterrupted)
+		Expect(condition.IsTrue()).To(BeTrue())
+		Expect(condition.Reason).To(Equal(string(cloudprovider.InterruptionKindSpotInterruption)))
+	})
+	It("should not mark the nodeclaim interrupted without a notice", func() {
+		ExpectApplied(ctx, env.Client, nodePool, nodeClaim, node)
+		ExpectObjectReconciled(ctx, env.Client, interruptionController, nodeClaim)
+		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+		Expect(nodeClaim.StatusConditions().Get(v1.ConditionTypeInterrupted)).To(BeNil())
+	})
+	It("should not mark nodeclaims interrupted for notices of other instances", func() {
+		ExpectApplied(ctx, env.Client, nodePool, nodeClaim, node)
+		interruptionController.Notify(cloudprovider.Interruption{
+			ProviderID: test.RandomProviderID(),
+			Kind:       cloudprovider.InterruptionKindRebalanceRecommendation,
+		})
+		ExpectObjectReconciled(ctx, env.Client, interruptionController, nodeClaim)
+		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+		Expect(nodeClaim.StatusConditions().Get(v1.ConditionTypeInterrupted)).To(BeNil())
+	})
+	It("should only mark the nodeclaim interrupted once per notice", func() {
+		ExpectApplied(ctx, env.Client, nodePool, nodeClaim, node)
+		interruptionController.Notify(cloudprovider.Interruption{
+			ProviderID: nodeClaim.Status.ProviderID,
+			Kind:       cloudprovider.InterruptionKindScheduledMaintenance,
+		})
+		ExpectObjectReconciled(ctx, env.Client, interruptionController, nodeClaim)
+		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+		Expect(nodeClaim.StatusConditions().Get(v1.ConditionTypeInterrupted).IsTrue()).To(BeTrue())
+
+		// the notice is consumed once it's recorded, so later reconciles leave the condition as is
+		nodeClaim.StatusConditions().SetFalse(v1.ConditionTypeInterrupted, "Test", "Test")
+		ExpectApplied(ctx, env.Client, nodeClaim)
+		ExpectObjectReconciled(ctx, env.Client, interruptionController, nodeClaim)
+		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+		Expect(nodeClaim.StatusConditions().Get(v1.ConditionTypeInterrupted).IsFalse()).To(BeTrue())
+	})
+	It("should not mark nodeclaims that are being deleted interrupted", func() {
+		nodeClaim.Finalizers = []string{v1.TerminationFinalizer}
+		ExpectApplied(ctx, env.Client, nodePool, nodeClaim, node)
+		ExpectDeletionTimestampSet(ctx, env.Client, nodeClaim)
+		interruptionController.Notify(cloudprovider.Interruption{
+			ProviderID: nodeClaim.Status.ProviderID,
+			Kind:       cloudprovider.InterruptionKindSpotInterruption,
+		})
+		ExpectObjectReconciled(ctx, env.Client, interruptionController, nodeClaim)
+		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+		Expect(nodeClaim.StatusConditions().Get(v1.ConditionTypeInterrupted)).To(BeNil())
+	})
+})
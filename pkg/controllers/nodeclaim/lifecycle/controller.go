@@ -44,6 +44,7 @@ import (
 
 	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
 	"sigs.k8s.io/karpenter/pkg/cloudprovider"
+	"sigs.k8s.io/karpenter/pkg/controllers/state"
 	"sigs.k8s.io/karpenter/pkg/events"
 	"sigs.k8s.io/karpenter/pkg/metrics"
 	"sigs.k8s.io/karpenter/pkg/operator/injection"
@@ -61,6 +62,7 @@ type nodeClaimReconciler interface {
 // the cluster as nodes and that they are properly initialized, ensuring that nodeclaims that do not have matching nodes
 // after some liveness TTL are removed
 type Controller struct {
+	clock         clock.Clock
 	kubeClient    client.Client
 	cloudProvider cloudprovider.CloudProvider
 	recorder      events.Recorder
@@ -71,23 +73,24 @@ type Controller struct {
 	liveness       *Liveness
 }
 
-func NewController(clk clock.Clock, kubeClient client.Client, cloudProvider cloudprovider.CloudProvider, recorder events.Recorder) *Controller {
+func NewController(clk clock.Clock, kubeClient client.Client, cloudProvider cloudprovider.CloudProvider, cluster *state.Cluster, recorder events.Recorder) *Controller {
 	return &Controller{
+		clock:         clk,
 		kubeClient:    kubeClient,
 		cloudProvider: cloudProvider,
 		recorder:      recorder,
 
-		launch:         &Launch{kubeClient: kubeClient, cloudProvider: cloudProvider, cache: cache.New(time.Minute, time.Second*10), recorder: recorder},
-		registration:   &Registration{kubeClient: kubeClient},
-		initialization: &Initialization{kubeClient: kubeClient},
+		launch:         &Launch{clock: clk, kubeClient: kubeClient, cloudProvider: cloudProvider, cluster: cluster, cache: cache.New(time.Minute, time.Second*10), recorder: recorder},
+		registration:   &Registration{clock: clk, kubeClient: kubeClient},
+		initialization: &Initialization{clock: clk, kubeClient: kubeClient, recorder: recorder},
 		liveness:       &Liveness{clock: clk, kubeClient: kubeClient},
 	}
 }
 
-func (c *Controller) Register(_ context.Context, m manager.Manager) error {
+func (c *Controller) Register(ctx context.Context, m manager.Manager) error {
 	return controllerruntime.NewControllerManagedBy(m).
 		Named(c.Name()).
-		For(&v1.NodeClaim{}, builder.WithPredicates(nodeclaimutils.IsManagedPredicateFuncs(c.cloudProvider))).
+		For(&v1.NodeClaim{}, builder.WithPredicates(nodeclaimutils.IsManagedPredicateFuncs(ctx, c.cloudProvider))).
 		Watches(
 			&corev1.Node{},
 			nodeclaimutils.NodeEventHandler(c.kubeClient, c.cloudProvider),
@@ -111,7 +114,7 @@ func (c *Controller) Name() string {
 func (c *Controller) Reconcile(ctx context.Context, nodeClaim *v1.NodeClaim) (reconcile.Result, error) {
 	ctx = injection.WithControllerName(ctx, c.Name())
 
-	if !nodeclaimutils.IsManaged(nodeClaim, c.cloudProvider) {
+	if !nodeclaimutils.IsManaged(ctx, nodeClaim, c.cloudProvider) {
 		return reconcile.Result{}, nil
 	}
 	if !nodeClaim.DeletionTimestamp.IsZero() {
@@ -206,7 +209,7 @@ func (c *Controller) finalize(ctx context.Context, nodeClaim *v1.NodeClaim) (rec
 	}
 	// We can expect ProviderID to be empty when there is a failure while launching the nodeClaim
 	if nodeClaim.Status.ProviderID != "" {
-		isInstanceTerminated, err := terminationutil.EnsureTerminated(ctx, c.kubeClient, nodeClaim, c.cloudProvider)
+		isInstanceTerminated, err := terminationutil.EnsureTerminated(ctx, c.kubeClient, nodeClaim, c.cloudProvider, c.clock)
 		if err != nil {
 			// 404 = the nodeClaim no longer exists
 			if errors.IsNotFound(err) {
@@ -221,9 +224,15 @@ func (c *Controller) finalize(ctx context.Context, nodeClaim *v1.NodeClaim) (rec
 		if !isInstanceTerminated {
 			return reconcile.Result{RequeueAfter: 5 * time.Second}, nil
 		}
-		InstanceTerminationDurationSeconds.Observe(time.Since(nodeClaim.StatusConditions().Get(v1.ConditionTypeInstanceTerminating).LastTransitionTime.Time).Seconds(), map[string]string{
+		InstanceTerminationDurationSeconds.Observe(c.clock.Since(nodeClaim.StatusConditions().Get(v1.ConditionTypeInstanceTerminating).LastTransitionTime.Time).Seconds(), map[string]string{
 			metrics.NodePoolLabel: nodeClaim.Labels[v1.NodePoolLabelKey],
 		})
+		if err := c.updateTerminatedCondition(ctx, nodeClaim); err != nil {
+			if errors.IsConflict(err) {
+				return reconcile.Result{Requeue: true}, nil
+			}
+			return reconcile.Result{}, client.IgnoreNotFound(fmt.Errorf("updating terminated status condition, %w", err))
+		}
 	}
 	stored := nodeClaim.DeepCopy() // The NodeClaim may have been modified in the EnsureTerminated function
 	controllerutil.RemoveFinalizer(nodeClaim, v1.TerminationFinalizer)
@@ -239,7 +248,7 @@ func (c *Controller) finalize(ctx context.Context, nodeClaim *v1.NodeClaim) (rec
 			return reconcile.Result{}, client.IgnoreNotFound(fmt.Errorf("removing termination finalizer, %w", err))
 		}
 		log.FromContext(ctx).Info("deleted nodeclaim")
-		NodeClaimTerminationDurationSeconds.Observe(time.Since(stored.DeletionTimestamp.Time).Seconds(), map[string]string{
+		NodeClaimTerminationDurationSeconds.Observe(c.clock.Since(stored.DeletionTimestamp.Time).Seconds(), map[string]string{
 			metrics.NodePoolLabel: nodeClaim.Labels[v1.NodePoolLabelKey],
 		})
 		metrics.NodeClaimsTerminatedTotal.Inc(map[string]string{
@@ -251,6 +260,32 @@ func (c *Controller) finalize(ctx context.Context, nodeClaim *v1.NodeClaim) (rec
 
 }
 
+// updateTerminatedCondition records that the NodeClaim's instance is terminated, observing the duration of its
+// termination from when its node drained, or from its deletion if the node wasn't drained. Retained instances are left
+// running, so they're never terminated.
+func (c *Controller) updateTerminatedCondition(ctx context.Context, nodeClaim *v1.NodeClaim) error {
+	if nodeClaim.Spec.InstanceRetentionPolicy == v1.InstanceRetentionPolicyRetain || nodeClaim.StatusConditions().Get(v1.ConditionTypeTerminated).IsTrue() {
+		return nil
+	}
+	terminatingSince := nodeClaim.DeletionTimestamp.Time
+	if cond := nodeClaim.StatusConditions().Get(v1.ConditionTypeDrained); cond.IsTrue() {
+		terminatingSince = cond.LastTransitionTime.Time
+	}
+	stored := nodeClaim.DeepCopy()
+	nodeClaim.StatusConditions().SetTrue(v1.ConditionTypeTerminated)
+	// We use client.MergeFromWithOptimisticLock because patching a list with a JSON merge patch
+	// can cause races due to the fact that it fully replaces the list on a change
+	// Here, we are updating the status condition list
+	if err := c.kubeClient.Status().Patch(ctx, nodeClaim, client.MergeFromWithOptions(stored, client.MergeFromWithOptimisticLock{})); err != nil {
+		return err
+	}
+	metrics.NodeClaimsPhaseDurationSeconds.Observe(c.clock.Since(terminatingSince).Seconds(), map[string]string{
+		metrics.PhaseLabel:    v1.ConditionTypeTerminated,
+		metrics.NodePoolLabel: nodeClaim.Labels[v1.NodePoolLabelKey],
+	})
+	return nil
+}
+
 func (c *Controller) ensureTerminationGracePeriodTerminationTimeAnnotation(ctx context.Context, nodeClaim *v1.NodeClaim) error {
 	// if the expiration annotation is already set, we don't need to do anything
 	if _, exists := nodeClaim.ObjectMeta.Annotations[v1.NodeClaimTerminationTimestampAnnotationKey]; exists {
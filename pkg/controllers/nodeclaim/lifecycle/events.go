@@ -44,3 +44,13 @@ func NodeClassNotReadyEvent(nodeClaim *v1.NodeClaim, err error) events.Event {
 		DedupeValues:   []string{string(nodeClaim.UID)},
 	}
 }
+
+func StartupTaintTimedOutEvent(nodeClaim *v1.NodeClaim, taint *corev1.Taint) events.Event {
+	return events.Event{
+		InvolvedObject: nodeClaim,
+		Type:           corev1.EventTypeWarning,
+		Reason:         "StartupTaintTimedOut",
+		Message:        fmt.Sprintf("Removed startup taint %q, which wasn't removed within its timeout", formatTaint(taint)),
+		DedupeValues:   []string{string(nodeClaim.UID), taint.Key},
+	}
+}
@@ -19,16 +19,20 @@ package lifecycle
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/samber/lo"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+	"sigs.k8s.io/karpenter/pkg/events"
+	"sigs.k8s.io/karpenter/pkg/metrics"
 	"sigs.k8s.io/karpenter/pkg/scheduling"
 	nodeutils "sigs.k8s.io/karpenter/pkg/utils/node"
 	nodeclaimutils "sigs.k8s.io/karpenter/pkg/utils/nodeclaim"
@@ -36,13 +40,16 @@ import (
 )
 
 type Initialization struct {
+	clock      clock.Clock
 	kubeClient client.Client
+	recorder   events.Recorder
 }
 
 // Reconcile checks for initialization based on if:
 // a) its current status is set to Ready
 // b) all the startup taints have been removed from the node
-// c) all extended resources have been registered
+// c) all the readiness gates of the nodeclaim are satisfied by the node's conditions
+// d) all requested extended resources and known device plugin resources have been registered
 // This method handles both nil nodepools and nodes without extended resources gracefully.
 func (i *Initialization) Reconcile(ctx context.Context, nodeClaim *v1.NodeClaim) (reconcile.Result, error) {
 	if cond := nodeClaim.StatusConditions().Get(v1.ConditionTypeInitialized); !cond.IsUnknown() {
@@ -64,10 +71,20 @@ func (i *Initialization) Reconcile(ctx context.Context, nodeClaim *v1.NodeClaim)
 		nodeClaim.StatusConditions().SetUnknownWithReason(v1.ConditionTypeInitialized, "NodeNotReady", "Node status is NotReady")
 		return reconcile.Result{}, nil
 	}
-	if taint, ok := StartupTaintsRemoved(node, nodeClaim); !ok {
-		nodeClaim.StatusConditions().SetUnknownWithReason(v1.ConditionTypeInitialized, "StartupTaintsExist", fmt.Sprintf("StartupTaint %q still exists", formatTaint(taint)))
+	if gate, ok := ReadinessGatesSatisfied(node, nodeClaim); !ok {
+		nodeClaim.StatusConditions().SetUnknownWithReason(v1.ConditionTypeInitialized, "ReadinessGatesNotSatisfied", fmt.Sprintf("Node condition %q is not %s", gate.ConditionType, lo.CoalesceOrEmpty(gate.Status, corev1.ConditionTrue)))
 		return reconcile.Result{}, nil
 	}
+	if _, ok := StartupTaintsRemoved(node, nodeClaim); !ok {
+		requeueAfter, err := i.removeTimedOutStartupTaints(ctx, nodeClaim, node)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if taint, ok := StartupTaintsRemoved(node, nodeClaim); !ok {
+			nodeClaim.StatusConditions().SetUnknownWithReason(v1.ConditionTypeInitialized, "StartupTaintsExist", fmt.Sprintf("StartupTaint %q still exists", formatTaint(taint)))
+			return reconcile.Result{RequeueAfter: requeueAfter}, nil
+		}
+	}
 	if taint, ok := KnownEphemeralTaintsRemoved(node); !ok {
 		nodeClaim.StatusConditions().SetUnknownWithReason(v1.ConditionTypeInitialized, "KnownEphemeralTaintsExist", fmt.Sprintf("KnownEphemeralTaint %q still exists", formatTaint(taint)))
 		return reconcile.Result{}, nil
@@ -76,6 +93,10 @@ func (i *Initialization) Reconcile(ctx context.Context, nodeClaim *v1.NodeClaim)
 		nodeClaim.StatusConditions().SetUnknownWithReason(v1.ConditionTypeInitialized, "ResourceNotRegistered", fmt.Sprintf("Resource %q was requested but not registered", name))
 		return reconcile.Result{}, nil
 	}
+	if name, ok := DevicePluginResourcesRegistered(node, nodeClaim); !ok {
+		nodeClaim.StatusConditions().SetUnknownWithReason(v1.ConditionTypeInitialized, "ResourceNotRegistered", fmt.Sprintf("Resource %q was advertised but not registered", name))
+		return reconcile.Result{}, nil
+	}
 	stored := node.DeepCopy()
 	node.Labels = lo.Assign(node.Labels, map[string]string{v1.NodeInitializedLabelKey: "true"})
 	if !equality.Semantic.DeepEqual(stored, node) {
@@ -84,10 +105,65 @@ func (i *Initialization) Reconcile(ctx context.Context, nodeClaim *v1.NodeClaim)
 		}
 	}
 	log.FromContext(ctx).WithValues("allocatable", node.Status.Allocatable).Info("initialized nodeclaim")
+	metrics.NodeClaimsPhaseDurationSeconds.Observe(i.clock.Since(nodeClaim.StatusConditions().Get(v1.ConditionTypeRegistered).LastTransitionTime.Time).Seconds(), map[string]string{
+		metrics.PhaseLabel:    v1.ConditionTypeInitialized,
+		metrics.NodePoolLabel: nodeClaim.Labels[v1.NodePoolLabelKey],
+	})
 	nodeClaim.StatusConditions().SetTrue(v1.ConditionTypeInitialized)
 	return reconcile.Result{}, nil
 }
 
+// removeTimedOutStartupTaints removes the startup taints that are still on the node after their timeout, measured from
+// when the node registered. It returns how long until the next startup taint on the node times out, or zero if none of
+// the startup taints on the node have a timeout.
+func (i *Initialization) removeTimedOutStartupTaints(ctx context.Context, nodeClaim *v1.NodeClaim, node *corev1.Node) (time.Duration, error) {
+	if len(nodeClaim.Spec.StartupTaintTimeouts) == 0 {
+		return 0, nil
+	}
+	registered := nodeClaim.StatusConditions().Get(v1.ConditionTypeRegistered).LastTransitionTime.Time
+	var requeueAfter time.Duration
+	var timedOut []corev1.Taint
+	stored := node.DeepCopy()
+	node.Spec.Taints = lo.Reject(node.Spec.Taints, func(t corev1.Taint, _ int) bool {
+		timeout, ok := nodeClaim.Spec.StartupTaintTimeouts[t.Key]
+		if !ok || !lo.ContainsBy(nodeClaim.Spec.StartupTaints, func(startupTaint corev1.Taint) bool { return startupTaint.MatchTaint(&t) }) {
+			return false
+		}
+		if remaining := timeout.Duration - i.clock.Since(registered); remaining > 0 {
+			requeueAfter = lo.Ternary(requeueAfter == 0, remaining, min(requeueAfter, remaining))
+			return false
+		}
+		timedOut = append(timedOut, t)
+		return true
+	})
+	if len(timedOut) == 0 {
+		return requeueAfter, nil
+	}
+	// We use client.MergeFromWithOptimisticLock because patching a list with a JSON merge patch
+	// can cause races due to the fact that it fully replaces the list on a change
+	// Here, we are updating the taint list
+	if err := i.kubeClient.Patch(ctx, node, client.MergeFromWithOptions(stored, client.MergeFromWithOptimisticLock{})); err != nil {
+		return 0, err
+	}
+	for _, t := range timedOut {
+		log.FromContext(ctx).WithValues("taint", formatTaint(&t)).Info("removed startup taint that wasn't removed within its timeout")
+		i.recorder.Publish(StartupTaintTimedOutEvent(nodeClaim, &t))
+	}
+	return requeueAfter, nil
+}
+
+// ReadinessGatesSatisfied returns true if all the node conditions in the nodeClaim's readiness gates have the status
+// that the readiness gates expect. It returns the first readiness gate that isn't satisfied otherwise.
+func ReadinessGatesSatisfied(node *corev1.Node, nodeClaim *v1.NodeClaim) (*v1.NodeReadinessGate, bool) {
+	for i := range nodeClaim.Spec.ReadinessGates {
+		gate := &nodeClaim.Spec.ReadinessGates[i]
+		if nodeutils.GetCondition(node, gate.ConditionType).Status != lo.CoalesceOrEmpty(gate.Status, corev1.ConditionTrue) {
+			return gate, false
+		}
+	}
+	return nil, true
+}
+
 // KnownEphemeralTaintsRemoved validates whether all the ephemeral taints are removed
 func KnownEphemeralTaintsRemoved(node *corev1.Node) (*corev1.Taint, bool) {
 	for _, knownTaint := range scheduling.KnownEphemeralTaints {
@@ -135,6 +211,21 @@ func RequestedResourcesRegistered(node *corev1.Node, nodeClaim *v1.NodeClaim) (c
 	return "", true
 }
 
+// DevicePluginResourcesRegistered returns true if all the known device plugin resources that the nodeClaim advertises
+// have been registered on the node. Pods may have been scheduled against these resources while the node was in-flight,
+// even if they weren't part of the nodeClaim's original requests.
+func DevicePluginResourcesRegistered(node *corev1.Node, nodeClaim *v1.NodeClaim) (corev1.ResourceName, bool) {
+	for resourceName, quantity := range nodeClaim.Status.Allocatable {
+		if !scheduling.KnownDevicePluginResources.Has(resourceName) || quantity.IsZero() {
+			continue
+		}
+		if resources.IsZero(node.Status.Allocatable[resourceName]) {
+			return resourceName, false
+		}
+	}
+	return "", true
+}
+
 func formatTaint(taint *corev1.Taint) string {
 	if taint == nil {
 		return "<nil>"
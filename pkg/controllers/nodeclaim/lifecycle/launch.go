@@ -20,24 +20,31 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 
 	"github.com/patrickmn/go-cache"
 	"github.com/samber/lo"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/clock"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
 	"sigs.k8s.io/karpenter/pkg/cloudprovider"
+	"sigs.k8s.io/karpenter/pkg/controllers/state"
 	"sigs.k8s.io/karpenter/pkg/events"
 	"sigs.k8s.io/karpenter/pkg/metrics"
 	"sigs.k8s.io/karpenter/pkg/scheduling"
+	nodeclaimutils "sigs.k8s.io/karpenter/pkg/utils/nodeclaim"
 )
 
 type Launch struct {
+	clock         clock.Clock
 	kubeClient    client.Client
 	cloudProvider cloudprovider.CloudProvider
+	cluster       *state.Cluster
 	cache         *cache.Cache // exists due to eventual consistency on the cache
 	recorder      events.Recorder
 }
@@ -51,6 +58,7 @@ func (l *Launch) Reconcile(ctx context.Context, nodeClaim *v1.NodeClaim) (reconc
 
 	var err error
 	var created *v1.NodeClaim
+	var cached bool
 
 	// One of the following scenarios can happen with a NodeClaim that isn't marked as launched:
 	//  1. It was already launched by the CloudProvider but the client-go cache wasn't updated quickly enough or
@@ -58,7 +66,7 @@ func (l *Launch) Reconcile(ctx context.Context, nodeClaim *v1.NodeClaim) (reconc
 	//  2. It is a standard NodeClaim launch where we should call CloudProvider Create() and fill in details of the launched
 	//     NodeClaim into the NodeClaim CR.
 	if ret, ok := l.cache.Get(string(nodeClaim.UID)); ok {
-		created = ret.(*v1.NodeClaim)
+		created, cached = ret.(*v1.NodeClaim), true
 	} else {
 		created, err = l.launchNodeClaim(ctx, nodeClaim)
 	}
@@ -68,13 +76,26 @@ func (l *Launch) Reconcile(ctx context.Context, nodeClaim *v1.NodeClaim) (reconc
 	}
 	l.cache.SetDefault(string(nodeClaim.UID), created)
 	nodeClaim = PopulateNodeClaimDetails(nodeClaim, created)
+	nodeClaim.Status.LaunchedInstance = l.launchedInstance(nodeClaim, created)
+	nodeClaim.Status.LaunchFailure = nil
 	nodeClaim.StatusConditions().SetTrue(v1.ConditionTypeLaunched)
+	// the launch was already observed when the NodeClaim that we cached was created
+	if !cached {
+		metrics.NodeClaimsPhaseDurationSeconds.Observe(l.clock.Since(nodeClaim.CreationTimestamp.Time).Seconds(), map[string]string{
+			metrics.PhaseLabel:    v1.ConditionTypeLaunched,
+			metrics.NodePoolLabel: nodeClaim.Labels[v1.NodePoolLabelKey],
+		})
+	}
 	return reconcile.Result{}, nil
 }
 
 func (l *Launch) launchNodeClaim(ctx context.Context, nodeClaim *v1.NodeClaim) (*v1.NodeClaim, error) {
-	created, err := l.cloudProvider.Create(ctx, nodeClaim)
+	created, err := l.cloudProvider.Create(ctx, withDefaultRequirements(nodeClaim))
 	if err != nil {
+		recordLaunchFailure(nodeClaim, err)
+		if nodePoolName, ok := nodeClaim.Labels[v1.NodePoolLabelKey]; ok {
+			l.cluster.RecordLaunchFailure(nodePoolName, nodeClaim.Status.LaunchFailure.Reason)
+		}
 		switch {
 		case cloudprovider.IsInsufficientCapacityError(err):
 			l.recorder.Publish(InsufficientCapacityErrorEvent(nodeClaim, err))
@@ -103,9 +124,9 @@ func (l *Launch) launchNodeClaim(ctx context.Context, nodeClaim *v1.NodeClaim) (
 		default:
 			var createError *cloudprovider.CreateError
 			if errors.As(err, &createError) {
-				nodeClaim.StatusConditions().SetUnknownWithReason(v1.ConditionTypeLaunched, "LaunchFailed", createError.ConditionMessage)
+				nodeClaim.StatusConditions().SetUnknownWithReason(v1.ConditionTypeLaunched, v1.LaunchFailureReasonUnknown, createError.ConditionMessage)
 			} else {
-				nodeClaim.StatusConditions().SetUnknownWithReason(v1.ConditionTypeLaunched, "LaunchFailed", truncateMessage(err.Error()))
+				nodeClaim.StatusConditions().SetUnknownWithReason(v1.ConditionTypeLaunched, v1.LaunchFailureReasonUnknown, truncateMessage(err.Error()))
 			}
 			return nil, fmt.Errorf("launching nodeclaim, %w", err)
 		}
@@ -119,6 +140,69 @@ func (l *Launch) launchNodeClaim(ctx context.Context, nodeClaim *v1.NodeClaim) (
 	return created, nil
 }
 
+// withDefaultRequirements returns the NodeClaim with the default requirements of NodePools for the keys that it doesn't
+// constrain if it's a standalone NodeClaim. The spec of a NodeClaim is immutable, so the defaults are applied to the
+// NodeClaim that the CloudProvider launches, and reach the NodeClaim through the labels of the launched instance.
+func withDefaultRequirements(nodeClaim *v1.NodeClaim) *v1.NodeClaim {
+	if !nodeclaimutils.IsStandalone(nodeClaim) {
+		return nodeClaim
+	}
+	defaulted := nodeClaim.DeepCopy()
+	defaulted.Spec.Requirements = nodeclaimutils.WithDefaultRequirements(defaulted.Spec.Requirements, defaulted.Labels)
+	return defaulted
+}
+
+// launchedInstance returns the instance that the CloudProvider reported launching for the NodeClaim. CloudProviders
+// that don't report it have it resolved from the NodeClaim's labels and priced from their pricing, leaving the price
+// unset if the pricing doesn't know the offering.
+func (l *Launch) launchedInstance(nodeClaim, created *v1.NodeClaim) *v1.LaunchedInstance {
+	if created.Status.LaunchedInstance != nil {
+		return created.Status.LaunchedInstance.DeepCopy()
+	}
+	instance := &v1.LaunchedInstance{
+		InstanceType: nodeClaim.Labels[corev1.LabelInstanceTypeStable],
+		Zone:         nodeClaim.Labels[corev1.LabelTopologyZone],
+		CapacityType: nodeClaim.Labels[v1.CapacityTypeLabelKey],
+	}
+	if instance.InstanceType == "" {
+		return nil
+	}
+	pricing := l.cloudProvider.Pricing()
+	if price, ok := pricing.Price(instance.InstanceType, instance.Zone, instance.CapacityType); ok {
+		instance.Price = strconv.FormatFloat(price, 'f', -1, 64)
+		instance.Currency = pricing.Currency
+	}
+	return instance
+}
+
+// recordLaunchFailure records why the CloudProvider failed to launch the NodeClaim on its status, classifying whether
+// retrying the launch is expected to succeed
+func recordLaunchFailure(nodeClaim *v1.NodeClaim, err error) {
+	failure := &v1.LaunchFailure{
+		Reason:    v1.LaunchFailureReasonUnknown,
+		Message:   truncateMessage(err.Error()),
+		Retryable: true,
+		Time:      metav1.Now(),
+	}
+	var createError *cloudprovider.CreateError
+	switch {
+	case cloudprovider.IsInsufficientCapacityError(err):
+		failure.Reason = v1.LaunchFailureReasonInsufficientCapacity
+	case cloudprovider.IsNodeClassNotReadyError(err):
+		failure.Reason = v1.LaunchFailureReasonNodeClassNotReady
+	case errors.As(err, &createError) && createError.Reason != "":
+		failure.Reason = createError.Reason
+		// exceeded quotas and invalid configuration keep failing until the user fixes them
+		failure.Retryable = createError.Reason != v1.LaunchFailureReasonQuotaExceeded && createError.Reason != v1.LaunchFailureReasonInvalidConfiguration
+	}
+	nodeClaim.Status.LaunchFailure = failure
+	LaunchFailuresTotal.Inc(map[string]string{
+		metrics.ReasonLabel:   failure.Reason,
+		retryableLabel:        strconv.FormatBool(failure.Retryable),
+		metrics.NodePoolLabel: nodeClaim.Labels[v1.NodePoolLabelKey],
+	})
+}
+
 func PopulateNodeClaimDetails(nodeClaim, retrieved *v1.NodeClaim) *v1.NodeClaim {
 	// These are ordered in priority order so that user-defined nodeClaim labels and requirements trump retrieved labels
 	// or the static nodeClaim labels
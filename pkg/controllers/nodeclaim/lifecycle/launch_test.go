@@ -18,14 +18,19 @@ package lifecycle_test
 
 import (
 	"fmt"
+	"strconv"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
 	"sigs.k8s.io/karpenter/pkg/cloudprovider"
+	nodeclaimlifecycle "sigs.k8s.io/karpenter/pkg/controllers/nodeclaim/lifecycle"
+	"sigs.k8s.io/karpenter/pkg/controllers/state"
+	"sigs.k8s.io/karpenter/pkg/scheduling"
 	"sigs.k8s.io/karpenter/pkg/test"
 	. "sigs.k8s.io/karpenter/pkg/test/expectations"
 )
@@ -72,6 +77,52 @@ var _ = Describe("Launch", func() {
 		Entry("should launch an instance when a new NodeClaim is created", true),
 		Entry("should ignore NodeClaims which aren't managed by this Karpenter instance", false),
 	)
+	It("should launch standalone NodeClaims with the default requirements of NodePools", func() {
+		nodeClaim := test.NodeClaim()
+		ExpectApplied(ctx, env.Client, nodeClaim)
+		ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+
+		Expect(cloudProvider.CreateCalls).To(HaveLen(1))
+		requirements := scheduling.NewNodeSelectorRequirementsWithMinValues(cloudProvider.CreateCalls[0].Spec.Requirements...)
+		Expect(requirements.Get(corev1.LabelArchStable).Values()).To(ConsistOf(v1.ArchitectureAmd64))
+		Expect(requirements.Get(corev1.LabelOSStable).Values()).To(ConsistOf(string(corev1.Linux)))
+		Expect(requirements.Get(v1.CapacityTypeLabelKey).Values()).To(ConsistOf(v1.CapacityTypeOnDemand))
+		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+		Expect(nodeClaim.Labels).To(HaveKeyWithValue(v1.CapacityTypeLabelKey, v1.CapacityTypeOnDemand))
+		// the spec is immutable, so the defaults are only applied to the launched instance
+		Expect(nodeClaim.Spec.Requirements).To(BeEmpty())
+	})
+	It("should not default the requirements that standalone NodeClaims constrain", func() {
+		nodeClaim := test.NodeClaim(v1.NodeClaim{
+			Spec: v1.NodeClaimSpec{
+				Requirements: []v1.NodeSelectorRequirementWithMinValues{
+					{NodeSelectorRequirement: corev1.NodeSelectorRequirement{Key: v1.CapacityTypeLabelKey, Operator: corev1.NodeSelectorOpIn, Values: []string{v1.CapacityTypeSpot}}},
+				},
+			},
+		})
+		ExpectApplied(ctx, env.Client, nodeClaim)
+		ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+
+		Expect(cloudProvider.CreateCalls).To(HaveLen(1))
+		requirements := scheduling.NewNodeSelectorRequirementsWithMinValues(cloudProvider.CreateCalls[0].Spec.Requirements...)
+		Expect(requirements.Get(v1.CapacityTypeLabelKey).Values()).To(ConsistOf(v1.CapacityTypeSpot))
+		Expect(requirements.Get(corev1.LabelArchStable).Values()).To(ConsistOf(v1.ArchitectureAmd64))
+		Expect(ExpectExists(ctx, env.Client, nodeClaim).Labels).To(HaveKeyWithValue(v1.CapacityTypeLabelKey, v1.CapacityTypeSpot))
+	})
+	It("should not default the requirements of NodeClaims of a NodePool", func() {
+		nodeClaim := test.NodeClaim(v1.NodeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					v1.NodePoolLabelKey: nodePool.Name,
+				},
+			},
+		})
+		ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
+		ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+
+		Expect(cloudProvider.CreateCalls).To(HaveLen(1))
+		Expect(cloudProvider.CreateCalls[0].Spec.Requirements).To(BeEmpty())
+	})
 	It("should add the Launched status condition after creating the NodeClaim", func() {
 		nodeClaim := test.NodeClaim(v1.NodeClaim{
 			ObjectMeta: metav1.ObjectMeta{
@@ -86,6 +137,65 @@ var _ = Describe("Launch", func() {
 		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
 		Expect(ExpectStatusConditionExists(nodeClaim, v1.ConditionTypeLaunched).Status).To(Equal(metav1.ConditionTrue))
 	})
+	It("should record the launched instance and its price on the NodeClaim status", func() {
+		nodeClaim := test.NodeClaim(v1.NodeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					v1.NodePoolLabelKey: nodePool.Name,
+				},
+			},
+			Spec: v1.NodeClaimSpec{
+				Requirements: []v1.NodeSelectorRequirementWithMinValues{
+					{NodeSelectorRequirement: corev1.NodeSelectorRequirement{Key: corev1.LabelInstanceTypeStable, Operator: corev1.NodeSelectorOpIn, Values: []string{"default-instance-type"}}},
+					{NodeSelectorRequirement: corev1.NodeSelectorRequirement{Key: corev1.LabelTopologyZone, Operator: corev1.NodeSelectorOpIn, Values: []string{"test-zone-2"}}},
+					{NodeSelectorRequirement: corev1.NodeSelectorRequirement{Key: v1.CapacityTypeLabelKey, Operator: corev1.NodeSelectorOpIn, Values: []string{v1.CapacityTypeSpot}}},
+				},
+			},
+		})
+		ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
+		ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+
+		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+		instanceTypes, err := cloudProvider.GetInstanceTypes(ctx, nodePool)
+		Expect(err).ToNot(HaveOccurred())
+		instanceType, ok := lo.Find(instanceTypes, func(it *cloudprovider.InstanceType) bool { return it.Name == "default-instance-type" })
+		Expect(ok).To(BeTrue())
+		offering := instanceType.Offerings.Compatible(scheduling.NewLabelRequirements(nodeClaim.Labels)).Cheapest()
+		Expect(nodeClaim.Status.LaunchedInstance).To(Equal(&v1.LaunchedInstance{
+			InstanceType: "default-instance-type",
+			Zone:         "test-zone-2",
+			CapacityType: v1.CapacityTypeSpot,
+			Price:        strconv.FormatFloat(offering.Price, 'f', -1, 64),
+		}))
+	})
+	It("should record the currency of the launch price from the cloudprovider's pricing", func() {
+		cloudProvider.Prices = cloudprovider.Pricing{Currency: "USD"}
+		nodeClaim := test.NodeClaim(v1.NodeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					v1.NodePoolLabelKey: nodePool.Name,
+				},
+			},
+		})
+		ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
+		ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+
+		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+		Expect(nodeClaim.Status.LaunchedInstance).ToNot(BeNil())
+		Expect(nodeClaim.Status.LaunchedInstance.Price).ToNot(BeEmpty())
+		Expect(nodeClaim.Status.LaunchedInstance.Currency).To(Equal("USD"))
+	})
+	It("should record the launched instance that the cloudprovider returned from create", func() {
+		nodeClaim := test.NodeClaim()
+		ExpectApplied(ctx, env.Client, nodeClaim)
+		ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+
+		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+		created, ok := cloudProvider.CreatedNodeClaims[nodeClaim.Status.ProviderID]
+		Expect(ok).To(BeTrue())
+		Expect(created.Status.LaunchedInstance).ToNot(BeNil())
+		Expect(nodeClaim.Status.LaunchedInstance).To(Equal(created.Status.LaunchedInstance))
+	})
 	It("should delete the nodeclaim if InsufficientCapacity is returned from the cloudprovider", func() {
 		cloudProvider.NextCreateErr = cloudprovider.NewInsufficientCapacityError(fmt.Errorf("all instance types were unavailable"))
 		nodeClaim := test.NodeClaim()
@@ -94,6 +204,15 @@ var _ = Describe("Launch", func() {
 		ExpectFinalizersRemoved(ctx, env.Client, nodeClaim)
 		ExpectNotFound(ctx, env.Client, nodeClaim)
 	})
+	It("should record the launch failures of deleted nodeclaims against their nodepool", func() {
+		cloudProvider.NextCreateErr = cloudprovider.NewInsufficientCapacityError(fmt.Errorf("all instance types were unavailable"))
+		nodeClaim := test.NodeClaim(v1.NodeClaim{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{v1.NodePoolLabelKey: "default"}}})
+		ExpectApplied(ctx, env.Client, nodeClaim)
+		ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+		ExpectFinalizersRemoved(ctx, env.Client, nodeClaim)
+		ExpectNotFound(ctx, env.Client, nodeClaim)
+		Expect(cluster.LaunchFailures("default")).To(ConsistOf(state.LaunchFailure{Reason: v1.LaunchFailureReasonInsufficientCapacity, Time: fakeClock.Now()}))
+	})
 	It("should delete the nodeclaim if NodeClassNotReady is returned from the cloudprovider", func() {
 		cloudProvider.NextCreateErr = cloudprovider.NewNodeClassNotReadyError(fmt.Errorf("nodeClass isn't ready"))
 		nodeClaim := test.NodeClaim()
@@ -113,4 +232,36 @@ var _ = Describe("Launch", func() {
 		Expect(condition.Status).To(Equal(metav1.ConditionUnknown))
 		Expect(condition.Message).To(Equal(conditionMessage))
 	})
+	It("should record non-retryable launch failures on the nodeclaim", func() {
+		nodeclaimlifecycle.LaunchFailuresTotal.Reset()
+		cloudProvider.NextCreateErr = cloudprovider.NewCreateErrorWithReason(fmt.Errorf("quota exceeded"), v1.LaunchFailureReasonQuotaExceeded, "quota exceeded")
+		nodeClaim := test.NodeClaim()
+		ExpectApplied(ctx, env.Client, nodeClaim)
+		_ = ExpectObjectReconcileFailed(ctx, env.Client, nodeClaimController, nodeClaim)
+		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+		Expect(nodeClaim.Status.LaunchFailure).ToNot(BeNil())
+		Expect(nodeClaim.Status.LaunchFailure.Reason).To(Equal(v1.LaunchFailureReasonQuotaExceeded))
+		Expect(nodeClaim.Status.LaunchFailure.Retryable).To(BeFalse())
+		ExpectMetricCounterValue(nodeclaimlifecycle.LaunchFailuresTotal, 1, map[string]string{
+			"reason":    v1.LaunchFailureReasonQuotaExceeded,
+			"retryable": "false",
+		})
+
+		// the launch failure is cleared once the nodeclaim launches
+		ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+		Expect(nodeClaim.Status.LaunchFailure).To(BeNil())
+		Expect(ExpectStatusConditionExists(nodeClaim, v1.ConditionTypeLaunched).Status).To(Equal(metav1.ConditionTrue))
+	})
+	It("should record unclassified launch failures as retryable", func() {
+		cloudProvider.NextCreateErr = fmt.Errorf("error launching instance")
+		nodeClaim := test.NodeClaim()
+		ExpectApplied(ctx, env.Client, nodeClaim)
+		_ = ExpectObjectReconcileFailed(ctx, env.Client, nodeClaimController, nodeClaim)
+		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+		Expect(nodeClaim.Status.LaunchFailure).ToNot(BeNil())
+		Expect(nodeClaim.Status.LaunchFailure.Reason).To(Equal(v1.LaunchFailureReasonUnknown))
+		Expect(nodeClaim.Status.LaunchFailure.Message).To(ContainSubstring("error launching instance"))
+		Expect(nodeClaim.Status.LaunchFailure.Retryable).To(BeTrue())
+	})
 })
@@ -18,8 +18,11 @@ package lifecycle
 
 import (
 	"context"
+	"fmt"
 	"time"
 
+	"github.com/samber/lo"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/clock"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -40,22 +43,32 @@ const registrationTTL = time.Minute * 15
 
 func (l *Liveness) Reconcile(ctx context.Context, nodeClaim *v1.NodeClaim) (reconcile.Result, error) {
 	registered := nodeClaim.StatusConditions().Get(v1.ConditionTypeRegistered)
-	if registered.IsTrue() {
-		return reconcile.Result{}, nil
-	}
 	if registered == nil {
 		return reconcile.Result{Requeue: true}, nil
 	}
-	// If the Registered statusCondition hasn't gone True during the TTL since we first updated it, we should terminate the NodeClaim
+	if !registered.IsTrue() {
+		// If the Registered statusCondition hasn't gone True during the TTL since we first updated it, we should terminate the NodeClaim
+		return l.enforceTTL(ctx, nodeClaim, "registration", lo.FromPtrOr(nodeClaim.Spec.RegistrationTTL, metav1.Duration{Duration: registrationTTL}).Duration, registered.LastTransitionTime.Time)
+	}
+	initialized := nodeClaim.StatusConditions().Get(v1.ConditionTypeInitialized)
+	if nodeClaim.Spec.InitializationTTL == nil || initialized == nil || initialized.IsTrue() {
+		return reconcile.Result{}, nil
+	}
+	// If the Initialized statusCondition hasn't gone True during the TTL since the node registered, we should terminate the NodeClaim
+	return l.enforceTTL(ctx, nodeClaim, "initialization", nodeClaim.Spec.InitializationTTL.Duration, registered.LastTransitionTime.Time)
+}
+
+// enforceTTL deletes the NodeClaim if the TTL has passed since the start time, requeueing until it has otherwise
+func (l *Liveness) enforceTTL(ctx context.Context, nodeClaim *v1.NodeClaim, phase string, ttl time.Duration, start time.Time) (reconcile.Result, error) {
 	// NOTE: ttl has to be stored and checked in the same place since l.clock can advance after the check causing a race
-	if ttl := registrationTTL - l.clock.Since(registered.LastTransitionTime.Time); ttl > 0 {
-		return reconcile.Result{RequeueAfter: ttl}, nil
+	if remaining := ttl - l.clock.Since(start); remaining > 0 {
+		return reconcile.Result{RequeueAfter: remaining}, nil
 	}
-	// Delete the NodeClaim if we believe the NodeClaim won't register since we haven't seen the node
+	// Delete the NodeClaim if we believe the NodeClaim won't register or initialize since we haven't seen it happen
 	if err := l.kubeClient.Delete(ctx, nodeClaim); err != nil {
 		return reconcile.Result{}, client.IgnoreNotFound(err)
 	}
-	log.FromContext(ctx).V(1).WithValues("ttl", registrationTTL).Info("terminating due to registration ttl")
+	log.FromContext(ctx).V(1).WithValues("ttl", ttl).Info(fmt.Sprintf("terminating due to %s ttl", phase))
 	metrics.NodeClaimsDisruptedTotal.Inc(map[string]string{
 		metrics.ReasonLabel:       "liveness",
 		metrics.NodePoolLabel:     nodeClaim.Labels[v1.NodePoolLabelKey],
@@ -17,14 +17,13 @@ limitations under the License.
 package lifecycle
 
 import (
-	opmetrics "github.com/awslabs/operatorpkg/metrics"
 	"github.com/prometheus/client_golang/prometheus"
 	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 
 	"sigs.k8s.io/karpenter/pkg/metrics"
 )
 
-var InstanceTerminationDurationSeconds = opmetrics.NewPrometheusHistogram(
+var InstanceTerminationDurationSeconds = metrics.NewPrometheusHistogram(
 	crmetrics.Registry,
 	prometheus.HistogramOpts{
 		Namespace: metrics.Namespace,
@@ -36,7 +35,7 @@ var InstanceTerminationDurationSeconds = opmetrics.NewPrometheusHistogram(
 	[]string{metrics.NodePoolLabel},
 )
 
-var NodeClaimTerminationDurationSeconds = opmetrics.NewPrometheusHistogram(
+var NodeClaimTerminationDurationSeconds = metrics.NewPrometheusHistogram(
 	crmetrics.Registry,
 	prometheus.HistogramOpts{
 		Namespace: metrics.Namespace,
@@ -46,3 +45,16 @@ var NodeClaimTerminationDurationSeconds = opmetrics.NewPrometheusHistogram(
 		Buckets:   prometheus.ExponentialBuckets(1, 2, 12)}, //The threshold values generated here are 1, 2, 4, 8, 16, 32, 64, 128, 256, 512, 1024. 2048
 	[]string{metrics.NodePoolLabel},
 )
+
+const retryableLabel = "retryable"
+
+var LaunchFailuresTotal = metrics.NewPrometheusCounter(
+	crmetrics.Registry,
+	prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: metrics.NodeClaimSubsystem,
+		Name:      "launch_failures_total",
+		Help:      "Number of times that the CloudProvider failed to launch a NodeClaim. Labeled by the reason for the failure, whether retrying is expected to succeed and the owning nodepool.",
+	},
+	[]string{metrics.ReasonLabel, retryableLabel, metrics.NodePoolLabel},
+)
@@ -25,6 +25,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -37,6 +38,7 @@ import (
 )
 
 type Registration struct {
+	clock      clock.Clock
 	kubeClient client.Client
 }
 
@@ -44,6 +46,9 @@ func (r *Registration) Reconcile(ctx context.Context, nodeClaim *v1.NodeClaim) (
 	if cond := nodeClaim.StatusConditions().Get(v1.ConditionTypeRegistered); !cond.IsUnknown() {
 		// Ensure that we always set the status condition to the latest generation
 		nodeClaim.StatusConditions().Set(*cond)
+		if cond.IsTrue() && nodeClaim.Spec.MetadataSyncPolicy == v1.MetadataSyncPolicyAlways {
+			return reconcile.Result{}, r.syncMetadata(ctx, nodeClaim)
+		}
 		return reconcile.Result{}, nil
 	}
 	ctx = log.IntoContext(ctx, log.FromContext(ctx).WithValues("provider-id", nodeClaim.Status.ProviderID))
@@ -76,6 +81,10 @@ func (r *Registration) Reconcile(ctx context.Context, nodeClaim *v1.NodeClaim) (
 		return reconcile.Result{}, err
 	}
 	log.FromContext(ctx).Info("registered nodeclaim")
+	metrics.NodeClaimsPhaseDurationSeconds.Observe(r.clock.Since(nodeClaim.StatusConditions().Get(v1.ConditionTypeLaunched).LastTransitionTime.Time).Seconds(), map[string]string{
+		metrics.PhaseLabel:    v1.ConditionTypeRegistered,
+		metrics.NodePoolLabel: nodeClaim.Labels[v1.NodePoolLabelKey],
+	})
 	nodeClaim.StatusConditions().SetTrue(v1.ConditionTypeRegistered)
 	nodeClaim.Status.NodeName = node.Name
 
@@ -95,6 +104,13 @@ func (r *Registration) syncNode(ctx context.Context, nodeClaim *v1.NodeClaim, no
 	// Sync all taints inside NodeClaim into the Node taints
 	node.Spec.Taints = scheduling.Taints(node.Spec.Taints).Merge(nodeClaim.Spec.Taints)
 	node.Spec.Taints = scheduling.Taints(node.Spec.Taints).Merge(nodeClaim.Spec.StartupTaints)
+	// Resolve the label and taint templates against the instance the Node was launched on
+	labels, taints, err := nodeClaim.Spec.ResolveTemplates(node.Labels)
+	if err != nil {
+		return fmt.Errorf("resolving templates, %w", err)
+	}
+	node.Labels = lo.Assign(node.Labels, labels)
+	node.Spec.Taints = scheduling.Taints(node.Spec.Taints).Merge(taints)
 	// Remove karpenter.sh/unregistered taint
 	node.Spec.Taints = lo.Reject(node.Spec.Taints, func(t corev1.Taint, _ int) bool {
 		return t.MatchTaint(&v1.UnregisteredNoExecuteTaint)
@@ -112,3 +128,25 @@ func (r *Registration) syncNode(ctx context.Context, nodeClaim *v1.NodeClaim, no
 	}
 	return nil
 }
+
+// syncMetadata syncs the labels and annotations that were added to or changed on the NodeClaim after its Node registered
+// onto the Node
+func (r *Registration) syncMetadata(ctx context.Context, nodeClaim *v1.NodeClaim) error {
+	node, err := nodeclaimutils.NodeForNodeClaim(ctx, r.kubeClient, nodeClaim)
+	if err != nil {
+		if nodeclaimutils.IsNodeNotFoundError(err) || nodeclaimutils.IsDuplicateNodeError(err) {
+			return nil
+		}
+		return fmt.Errorf("getting node for nodeclaim, %w", err)
+	}
+	stored := node.DeepCopy()
+	node.Labels = lo.Assign(node.Labels, nodeClaim.Labels)
+	node.Annotations = lo.Assign(node.Annotations, nodeClaim.Annotations)
+	if !equality.Semantic.DeepEqual(stored, node) {
+		if err := r.kubeClient.Patch(ctx, node, client.MergeFrom(stored)); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		log.FromContext(ctx).WithValues("Node", klog.KRef("", node.Name)).V(1).Info("synced nodeclaim metadata onto node")
+	}
+	return nil
+}
@@ -156,6 +156,46 @@ var _ = Describe("Registration", func() {
 			Expect(node.Labels).To(HaveKeyWithValue(k, v))
 		}
 	})
+	DescribeTable(
+		"should sync label changes after the Node comes online based on the metadata sync policy",
+		func(policy v1.MetadataSyncPolicy, synced bool) {
+			nodeClaim := test.NodeClaim(v1.NodeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						v1.NodePoolLabelKey: nodePool.Name,
+						"custom-label":      "custom-value",
+					},
+				},
+				Spec: v1.NodeClaimSpec{
+					MetadataSyncPolicy: policy,
+				},
+			})
+			ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
+			ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+			nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+
+			node := test.Node(test.NodeOptions{ProviderID: nodeClaim.Status.ProviderID, Taints: []corev1.Taint{v1.UnregisteredNoExecuteTaint}})
+			ExpectApplied(ctx, env.Client, node)
+			ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+			nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+			Expect(nodeClaim.StatusConditions().Get(v1.ConditionTypeRegistered).IsTrue()).To(BeTrue())
+
+			nodeClaim.Labels["custom-label"] = "updated-value"
+			nodeClaim.Labels["new-custom-label"] = "new-custom-value"
+			ExpectApplied(ctx, env.Client, nodeClaim)
+			ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+			node = ExpectExists(ctx, env.Client, node)
+			if synced {
+				Expect(node.Labels).To(HaveKeyWithValue("custom-label", "updated-value"))
+				Expect(node.Labels).To(HaveKeyWithValue("new-custom-label", "new-custom-value"))
+			} else {
+				Expect(node.Labels).To(HaveKeyWithValue("custom-label", "custom-value"))
+				Expect(node.Labels).ToNot(HaveKey("new-custom-label"))
+			}
+		},
+		Entry("with the OnRegistration policy", v1.MetadataSyncPolicyOnRegistration, false),
+		Entry("with the Always policy", v1.MetadataSyncPolicyAlways, true),
+	)
 	It("should sync the annotations to the Node when the Node comes online", func() {
 		nodeClaim := test.NodeClaim(v1.NodeClaim{
 			ObjectMeta: metav1.ObjectMeta{
@@ -240,6 +280,82 @@ var _ = Describe("Registration", func() {
 			},
 		))
 	})
+	It("should resolve the label and taint templates against the Node when the Node comes online", func() {
+		nodeClaim := test.NodeClaim(v1.NodeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					v1.NodePoolLabelKey: nodePool.Name,
+				},
+			},
+			Spec: v1.NodeClaimSpec{
+				LabelTemplates: map[string]string{
+					"example.com/instance-type": "{{ .InstanceType.Name }}",
+					"example.com/placement":     "{{ .Zone }}-{{ .CapacityType }}",
+					"example.com/team":          `{{ index .Labels "team" }}`,
+				},
+				TaintTemplates: []corev1.Taint{
+					{
+						Key:    "example.com/arch",
+						Effect: corev1.TaintEffectNoSchedule,
+						Value:  "{{ .Architecture }}",
+					},
+				},
+			},
+		})
+		ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
+		ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+
+		node := test.Node(test.NodeOptions{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					corev1.LabelInstanceTypeStable: "default-instance-type",
+					corev1.LabelTopologyZone:       "test-zone-1",
+					v1.CapacityTypeLabelKey:        v1.CapacityTypeSpot,
+					corev1.LabelArchStable:         v1.ArchitectureArm64,
+					"team":                         "team-a",
+				},
+			},
+			ProviderID: nodeClaim.Status.ProviderID,
+			Taints:     []corev1.Taint{v1.UnregisteredNoExecuteTaint},
+		})
+		ExpectApplied(ctx, env.Client, node)
+		ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+		node = ExpectExists(ctx, env.Client, node)
+
+		Expect(node.Labels).To(HaveKeyWithValue("example.com/instance-type", "default-instance-type"))
+		Expect(node.Labels).To(HaveKeyWithValue("example.com/placement", "test-zone-1-spot"))
+		Expect(node.Labels).To(HaveKeyWithValue("example.com/team", "team-a"))
+		Expect(node.Spec.Taints).To(ContainElement(corev1.Taint{
+			Key:    "example.com/arch",
+			Effect: corev1.TaintEffectNoSchedule,
+			Value:  v1.ArchitectureArm64,
+		}))
+	})
+	It("should not register the Node when a template resolves to an invalid value", func() {
+		nodeClaim := test.NodeClaim(v1.NodeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					v1.NodePoolLabelKey: nodePool.Name,
+				},
+			},
+			Spec: v1.NodeClaimSpec{
+				LabelTemplates: map[string]string{
+					"example.com/placement": "{{ .Zone }}/{{ .CapacityType }}",
+				},
+			},
+		})
+		ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
+		ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+
+		node := test.Node(test.NodeOptions{ProviderID: nodeClaim.Status.ProviderID, Taints: []corev1.Taint{v1.UnregisteredNoExecuteTaint}})
+		ExpectApplied(ctx, env.Client, node)
+		_ = ExpectObjectReconcileFailed(ctx, env.Client, nodeClaimController, nodeClaim)
+		node = ExpectExists(ctx, env.Client, node)
+		Expect(node.Labels).ToNot(HaveKey("example.com/placement"))
+		Expect(node.Labels).ToNot(HaveKey(v1.NodeRegisteredLabelKey))
+	})
 	It("should sync the startupTaints to the Node when the Node comes online", func() {
 		nodeClaim := test.NodeClaim(v1.NodeClaim{
 			ObjectMeta: metav1.ObjectMeta{
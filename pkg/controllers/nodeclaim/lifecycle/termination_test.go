@@ -35,6 +35,7 @@ import (
 	"sigs.k8s.io/karpenter/pkg/cloudprovider"
 	"sigs.k8s.io/karpenter/pkg/cloudprovider/fake"
 	"sigs.k8s.io/karpenter/pkg/controllers/nodeclaim/lifecycle"
+	"sigs.k8s.io/karpenter/pkg/metrics"
 	"sigs.k8s.io/karpenter/pkg/test"
 	. "sigs.k8s.io/karpenter/pkg/test/expectations"
 )
@@ -126,6 +127,21 @@ var _ = Describe("Termination", func() {
 		Entry("should delete the node and the CloudProvider NodeClaim when NodeClaim deletion is triggered", true),
 		Entry("should ignore NodeClaims which aren't managed by this Karpenter instance", false),
 	)
+	It("should observe the duration of the termination once the instance is terminated", func() {
+		metrics.NodeClaimsPhaseDurationSeconds.Reset()
+		ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
+		ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+
+		Expect(env.Client.Delete(ctx, nodeClaim)).To(Succeed())
+		ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim) // triggers the instance termination
+		_, ok := FindMetricWithLabelValues("karpenter_nodeclaims_phase_duration_seconds", map[string]string{"phase": v1.ConditionTypeTerminated, "nodepool": nodePool.Name})
+		Expect(ok).To(BeFalse())
+
+		ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim) // the instance is gone, so the nodeClaim is terminated
+		ExpectMetricHistogramSampleCountValue("karpenter_nodeclaims_phase_duration_seconds", 1, map[string]string{"phase": v1.ConditionTypeTerminated, "nodepool": nodePool.Name})
+		ExpectNotFound(ctx, env.Client, nodeClaim)
+	})
 	It("should delete the NodeClaim when the spec resource.Quantity values will change during deserialization", func() {
 		nodeClaim.SetGroupVersionKind(object.GVK(nodeClaim)) // This is needed so that the GVK is set on the unstructured object
 		u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(nodeClaim)
@@ -249,6 +265,27 @@ var _ = Describe("Termination", func() {
 
 		ExpectNotFound(ctx, env.Client, nodeClaim)
 	})
+	It("should not terminate the instance until the termination finalizers from the NodePool template are removed", func() {
+		nodeClaim.Annotations = lo.Assign(nodeClaim.Annotations, map[string]string{v1.TerminationFinalizersAnnotationKey: "test.sh/hook"})
+		nodeClaim.Finalizers = append(nodeClaim.Finalizers, "test.sh/hook")
+		ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
+		ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+
+		Expect(env.Client.Delete(ctx, nodeClaim)).To(Succeed())
+		result := ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+		Expect(result.RequeueAfter).To(BeEquivalentTo(5 * time.Second))
+		Expect(cloudProvider.DeleteCalls).To(HaveLen(0))
+		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+		Expect(nodeClaim.StatusConditions().Get(v1.ConditionTypeInstanceTerminating).IsTrue()).To(BeFalse())
+
+		stored := nodeClaim.DeepCopy()
+		nodeClaim.Finalizers = lo.Without(nodeClaim.Finalizers, "test.sh/hook")
+		Expect(env.Client.Patch(ctx, nodeClaim, client.MergeFrom(stored))).To(Succeed())
+		ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+		Expect(cloudProvider.DeleteCalls).To(HaveLen(1))
+		ExpectObjectReconciled(ctx, env.Client, nodeClaimController, nodeClaim)
+		ExpectNotFound(ctx, env.Client, nodeClaim)
+	})
 	It("should not call Delete() on the CloudProvider if the NodeClaim hasn't been launched yet", func() {
 		nodeClaim.Status.ProviderID = ""
 		ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
@@ -77,7 +77,7 @@ func (c *Controller) Reconcile(ctx context.Context, pod *corev1.Pod) (reconcile.
 		// if the nodeclaim doesn't exist, or has duplicates, ignore.
 		return reconcile.Result{}, nodeutils.IgnoreDuplicateNodeClaimError(nodeutils.IgnoreNodeClaimNotFoundError(fmt.Errorf("getting nodeclaims for node, %w", err)))
 	}
-	if !nodeclaimutils.IsManaged(nc, c.cloudProvider) {
+	if !nodeclaimutils.IsManaged(ctx, nc, c.cloudProvider) {
 		return reconcile.Result{}, nil
 	}
 
@@ -21,9 +21,13 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/samber/lo"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/utils/clock"
 	controllerruntime "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -41,12 +45,13 @@ import (
 
 // Controller for the resource
 type Controller struct {
+	clock         clock.Clock
 	kubeClient    client.Client
 	cloudProvider cloudprovider.CloudProvider
 	cluster       *state.Cluster
 }
 
-var ResourceNode = corev1.ResourceName("nodes")
+var ResourceNode = v1.ResourceNodes
 
 var BaseResources = corev1.ResourceList{
 	corev1.ResourceCPU:              resource.MustParse("0"),
@@ -57,8 +62,9 @@ var BaseResources = corev1.ResourceList{
 }
 
 // NewController is a constructor
-func NewController(kubeClient client.Client, cloudProvider cloudprovider.CloudProvider, cluster *state.Cluster) *Controller {
+func NewController(clk clock.Clock, kubeClient client.Client, cloudProvider cloudprovider.CloudProvider, cluster *state.Cluster) *Controller {
 	return &Controller{
+		clock:         clk,
 		kubeClient:    kubeClient,
 		cloudProvider: cloudProvider,
 		cluster:       cluster,
@@ -68,7 +74,7 @@ func NewController(kubeClient client.Client, cloudProvider cloudprovider.CloudPr
 // Reconcile a control loop for the resource
 func (c *Controller) Reconcile(ctx context.Context, nodePool *v1.NodePool) (reconcile.Result, error) {
 	ctx = injection.WithControllerName(ctx, "nodepool.counter")
-	if !nodepoolutils.IsManaged(nodePool, c.cloudProvider) {
+	if !nodepoolutils.IsManaged(ctx, nodePool, c.cloudProvider) {
 		return reconcile.Result{}, nil
 	}
 
@@ -81,12 +87,70 @@ func (c *Controller) Reconcile(ctx context.Context, nodePool *v1.NodePool) (reco
 	stored := nodePool.DeepCopy()
 	// Determine resource usage and update nodepool.status.resources
 	nodePool.Status.Resources = c.resourceCountsFor(v1.NodePoolLabelKey, nodePool.Name)
+	nodePool.Status.CapacityTypeResources = c.capacityTypeResourceCountsFor(nodePool.Name)
+	nodePool.Status.RemainingNodes = nil
+	if remaining, ok := nodePool.Spec.Limits.RemainingNodes(nodePool.Status.Resources); ok {
+		nodePool.Status.RemainingNodes = lo.ToPtr(remaining)
+	}
+	// The nodes are reported through the scale subresource, so that static NodePools can be scaled by generic tooling
+	nodePool.Status.Replicas = nodePool.Status.Resources.Name(ResourceNode, resource.DecimalSI).Value()
+	nodePool.Status.Selector = labels.SelectorFromSet(labels.Set{v1.NodePoolLabelKey: nodePool.Name}).String()
+	nodePool.Status.Allocatable, nodePool.Status.Requested = c.allocationFor(nodePool.Name)
+	c.updateLastActivity(nodePool)
+	nodePool.Status.AllowedDisruptions = c.allowedDisruptionsFor(nodePool)
 	if !equality.Semantic.DeepEqual(stored, nodePool) {
 		if err := c.kubeClient.Status().Patch(ctx, nodePool, client.MergeFrom(stored)); err != nil {
 			return reconcile.Result{}, client.IgnoreNotFound(err)
 		}
 	}
-	return reconcile.Result{}, nil
+	// Pods aren't watched and budgets are scheduled, so the requested resources and the allowed disruptions are
+	// refreshed periodically
+	return reconcile.Result{RequeueAfter: time.Minute}, nil
+}
+
+// allocationFor returns the allocatable resources of the NodePool's nodes and the resources that are requested by the
+// pods running on them
+func (c *Controller) allocationFor(nodePoolName string) (allocatable, requested corev1.ResourceList) {
+	c.cluster.ForEachNode(func(n *state.StateNode) bool {
+		if n.MarkedForDeletion() || n.Labels()[v1.NodePoolLabelKey] != nodePoolName {
+			return true
+		}
+		allocatable = resources.MergeInto(allocatable, n.Allocatable())
+		requested = resources.MergeInto(requested, n.PodRequests())
+		return true
+	})
+	return allocatable, requested
+}
+
+// updateLastActivity records when the NodePool last launched a NodeClaim and when one of its NodeClaims was last
+// disrupted. The NodeClaims are gone once they're deleted, so the recorded times only ever move forward.
+func (c *Controller) updateLastActivity(nodePool *v1.NodePool) {
+	c.cluster.ForEachNode(func(n *state.StateNode) bool {
+		if n.NodeClaim == nil || n.Labels()[v1.NodePoolLabelKey] != nodePool.Name {
+			return true
+		}
+		nodePool.Status.LastProvisioningTime = latest(nodePool.Status.LastProvisioningTime, n.NodeClaim.CreationTimestamp)
+		if cond := n.NodeClaim.StatusConditions().Get(v1.ConditionTypeDisruptionReason); cond.IsTrue() {
+			nodePool.Status.LastDisruptionTime = latest(nodePool.Status.LastDisruptionTime, cond.LastTransitionTime)
+		}
+		return true
+	})
+}
+
+// allowedDisruptionsFor returns how many of the NodePool's nodes its budgets currently allow to be disrupted, by
+// disruption reason. The nodes are counted the same way that the disruption controller counts them.
+func (c *Controller) allowedDisruptionsFor(nodePool *v1.NodePool) map[v1.DisruptionReason]int64 {
+	numNodes, disrupting := c.cluster.DisruptionCounts()
+	return lo.SliceToMap(v1.DisruptionReasons, func(reason v1.DisruptionReason) (v1.DisruptionReason, int64) {
+		return reason, int64(lo.Max([]int{nodePool.MustGetAllowedDisruptions(c.clock, numNodes[nodePool.Name], reason) - disrupting[nodePool.Name], 0}))
+	})
+}
+
+func latest(current *metav1.Time, t metav1.Time) *metav1.Time {
+	if t.IsZero() || (current != nil && !current.Before(&t)) {
+		return current
+	}
+	return &t
 }
 
 func (c *Controller) resourceCountsFor(ownerLabel string, ownerName string) corev1.ResourceList {
@@ -111,10 +175,31 @@ func (c *Controller) resourceCountsFor(ownerLabel string, ownerName string) core
 	return res
 }
 
-func (c *Controller) Register(_ context.Context, m manager.Manager) error {
+// capacityTypeResourceCountsFor returns the resources provisioned by the NodePool, by the capacity type of its nodes
+func (c *Controller) capacityTypeResourceCountsFor(nodePoolName string) map[string]corev1.ResourceList {
+	var res map[string]corev1.ResourceList
+	c.cluster.ForEachNode(func(n *state.StateNode) bool {
+		if n.MarkedForDeletion() || n.Labels()[v1.NodePoolLabelKey] != nodePoolName {
+			return true
+		}
+		capacityType, ok := n.Labels()[v1.CapacityTypeLabelKey]
+		if !ok {
+			return true
+		}
+		if res == nil {
+			res = map[string]corev1.ResourceList{}
+		}
+		res[capacityType] = resources.MergeInto(res[capacityType], n.Capacity())
+		res[capacityType] = resources.MergeInto(res[capacityType], corev1.ResourceList{ResourceNode: resource.MustParse("1")})
+		return true
+	})
+	return res
+}
+
+func (c *Controller) Register(ctx context.Context, m manager.Manager) error {
 	return controllerruntime.NewControllerManagedBy(m).
 		Named("nodepool.counter").
-		For(&v1.NodePool{}, builder.WithPredicates(nodepoolutils.IsManagedPredicateFuncs(c.cloudProvider))).
+		For(&v1.NodePool{}, builder.WithPredicates(nodepoolutils.IsManagedPredicateFuncs(ctx, c.cloudProvider))).
 		Watches(&v1.NodeClaim{}, nodepoolutils.NodeClaimEventHandler()).
 		Watches(&corev1.Node{}, nodepoolutils.NodeEventHandler()).
 		WithOptions(controller.Options{MaxConcurrentReconciles: 10}).
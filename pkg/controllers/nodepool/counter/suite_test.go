@@ -23,6 +23,7 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/samber/lo"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -35,6 +36,7 @@ import (
 	"sigs.k8s.io/karpenter/pkg/controllers/nodepool/counter"
 	"sigs.k8s.io/karpenter/pkg/controllers/state"
 	"sigs.k8s.io/karpenter/pkg/controllers/state/informer"
+	"sigs.k8s.io/karpenter/pkg/operator/options"
 	"sigs.k8s.io/karpenter/pkg/test"
 	. "sigs.k8s.io/karpenter/pkg/test/expectations"
 	"sigs.k8s.io/karpenter/pkg/test/v1alpha1"
@@ -60,6 +62,7 @@ func TestAPIs(t *testing.T) {
 }
 
 var _ = BeforeSuite(func() {
+	ctx = options.ToContext(ctx, test.Options())
 	cloudProvider = fake.NewCloudProvider()
 	env = test.NewEnvironment(test.WithCRDs(apis.CRDs...), test.WithCRDs(v1alpha1.CRDs...))
 	fakeClock = clock.NewFakeClock(time.Now())
@@ -67,7 +70,7 @@ var _ = BeforeSuite(func() {
 	nodeClaimController = informer.NewNodeClaimController(env.Client, cloudProvider, cluster)
 	nodeController = informer.NewNodeController(env.Client, cluster)
 	nodePoolInformerController = informer.NewNodePoolController(env.Client, cloudProvider, cluster)
-	nodePoolController = counter.NewController(env.Client, cloudProvider, cluster)
+	nodePoolController = counter.NewController(fakeClock, env.Client, cloudProvider, cluster)
 })
 
 var _ = AfterSuite(func() {
@@ -189,6 +192,110 @@ var _ = Describe("Counter", func() {
 		expected[corev1.ResourceName("nodes")] = resource.MustParse("1")
 		Expect(nodePool.Status.Resources).To(BeComparableTo(expected))
 	})
+	It("should report the remaining nodes when the number of nodes is limited", func() {
+		nodePool.Spec.Limits = v1.Limits(corev1.ResourceList{v1.ResourceNodes: resource.MustParse("3")})
+		ExpectApplied(ctx, env.Client, nodePool, node, nodeClaim)
+		ExpectMakeNodesAndNodeClaimsInitializedAndStateUpdated(ctx, env.Client, nodeController, nodeClaimController, []*corev1.Node{node}, []*v1.NodeClaim{nodeClaim})
+
+		ExpectObjectReconciled(ctx, env.Client, nodePoolController, nodePool)
+		nodePool = ExpectExists(ctx, env.Client, nodePool)
+		Expect(nodePool.Status.Resources).To(HaveKeyWithValue(v1.ResourceNodes, resource.MustParse("1")))
+		Expect(nodePool.Status.RemainingNodes).To(Equal(lo.ToPtr[int64](2)))
+	})
+	It("should not report the remaining nodes when the number of nodes isn't limited", func() {
+		ExpectObjectReconciled(ctx, env.Client, nodePoolController, nodePool)
+		nodePool = ExpectExists(ctx, env.Client, nodePool)
+		Expect(nodePool.Status.RemainingNodes).To(BeNil())
+	})
+	It("should report the nodes and their selector for the scale subresource", func() {
+		ExpectApplied(ctx, env.Client, node, nodeClaim, node2, nodeClaim2)
+		ExpectMakeNodesAndNodeClaimsInitializedAndStateUpdated(ctx, env.Client, nodeController, nodeClaimController, []*corev1.Node{node, node2}, []*v1.NodeClaim{nodeClaim, nodeClaim2})
+
+		ExpectObjectReconciled(ctx, env.Client, nodePoolController, nodePool)
+		nodePool = ExpectExists(ctx, env.Client, nodePool)
+		Expect(nodePool.Status.Replicas).To(Equal(int64(2)))
+		Expect(nodePool.Status.Selector).To(Equal(v1.NodePoolLabelKey + "=" + nodePool.Name))
+	})
+	It("should report the resources of the nodes by capacity type", func() {
+		node.Labels[v1.CapacityTypeLabelKey] = v1.CapacityTypeSpot
+		node2.Labels[v1.CapacityTypeLabelKey] = v1.CapacityTypeOnDemand
+		ExpectApplied(ctx, env.Client, node, nodeClaim, node2, nodeClaim2)
+		ExpectMakeNodesAndNodeClaimsInitializedAndStateUpdated(ctx, env.Client, nodeController, nodeClaimController, []*corev1.Node{node, node2}, []*v1.NodeClaim{nodeClaim, nodeClaim2})
+
+		ExpectObjectReconciled(ctx, env.Client, nodePoolController, nodePool)
+		nodePool = ExpectExists(ctx, env.Client, nodePool)
+		Expect(nodePool.Status.CapacityTypeResources).To(HaveLen(2))
+		Expect(nodePool.Status.CapacityTypeResources[v1.CapacityTypeSpot]).To(HaveKeyWithValue(corev1.ResourceCPU, resource.MustParse("100m")))
+		Expect(nodePool.Status.CapacityTypeResources[v1.CapacityTypeSpot]).To(HaveKeyWithValue(v1.ResourceNodes, resource.MustParse("1")))
+		Expect(nodePool.Status.CapacityTypeResources[v1.CapacityTypeOnDemand]).To(HaveKeyWithValue(corev1.ResourceCPU, resource.MustParse("500m")))
+		Expect(nodePool.Status.CapacityTypeResources[v1.CapacityTypeOnDemand]).To(HaveKeyWithValue(v1.ResourceNodes, resource.MustParse("1")))
+	})
+	It("should report the allocatable and requested resources of the nodes", func() {
+		node.Status.Allocatable = corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("90m"),
+			corev1.ResourcePods:   resource.MustParse("256"),
+			corev1.ResourceMemory: resource.MustParse("900Mi"),
+		}
+		pod := test.Pod(test.PodOptions{NodeName: node.Name, ResourceRequirements: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("50m"), corev1.ResourceMemory: resource.MustParse("100Mi")},
+		}})
+		ExpectApplied(ctx, env.Client, node, nodeClaim, pod)
+		ExpectMakeNodesAndNodeClaimsInitializedAndStateUpdated(ctx, env.Client, nodeController, nodeClaimController, []*corev1.Node{node}, []*v1.NodeClaim{nodeClaim})
+		Expect(cluster.UpdatePod(ctx, pod)).To(Succeed())
+
+		ExpectObjectReconciled(ctx, env.Client, nodePoolController, nodePool)
+		nodePool = ExpectExists(ctx, env.Client, nodePool)
+		Expect(nodePool.Status.Allocatable).To(BeComparableTo(node.Status.Allocatable))
+		Expect(nodePool.Status.Requested).To(BeComparableTo(pod.Spec.Containers[0].Resources.Requests))
+	})
+	It("should report the disruptions that the budgets currently allow by reason", func() {
+		nodePool.Spec.Disruption.Budgets = []v1.Budget{
+			{Nodes: "2"},
+			{Nodes: "0", Reasons: []v1.DisruptionReason{v1.DisruptionReasonDrifted}},
+		}
+		ExpectApplied(ctx, env.Client, nodePool, node, nodeClaim, node2, nodeClaim2)
+		ExpectMakeNodesAndNodeClaimsInitializedAndStateUpdated(ctx, env.Client, nodeController, nodeClaimController, []*corev1.Node{node, node2}, []*v1.NodeClaim{nodeClaim, nodeClaim2})
+		ExpectObjectReconciled(ctx, env.Client, nodePoolController, nodePool)
+		nodePool = ExpectExists(ctx, env.Client, nodePool)
+		Expect(nodePool.Status.AllowedDisruptions).To(Equal(map[v1.DisruptionReason]int64{
+			v1.DisruptionReasonEmpty:         2,
+			v1.DisruptionReasonUnderutilized: 2,
+			v1.DisruptionReasonDrifted:       0,
+			v1.DisruptionReasonInterrupted:   2,
+		}))
+
+		// nodes that are already disrupting count against the budgets
+		cluster.MarkForDeletion(nodeClaim.Status.ProviderID)
+		ExpectObjectReconciled(ctx, env.Client, nodePoolController, nodePool)
+		nodePool = ExpectExists(ctx, env.Client, nodePool)
+		Expect(nodePool.Status.AllowedDisruptions).To(HaveKeyWithValue(v1.DisruptionReasonEmpty, int64(1)))
+		Expect(nodePool.Status.AllowedDisruptions).To(HaveKeyWithValue(v1.DisruptionReasonDrifted, int64(0)))
+	})
+	It("should report when the nodepool last provisioned and disrupted a nodeclaim", func() {
+		ExpectApplied(ctx, env.Client, node, nodeClaim)
+		ExpectMakeNodesAndNodeClaimsInitializedAndStateUpdated(ctx, env.Client, nodeController, nodeClaimController, []*corev1.Node{node}, []*v1.NodeClaim{nodeClaim})
+		ExpectObjectReconciled(ctx, env.Client, nodePoolController, nodePool)
+		nodePool = ExpectExists(ctx, env.Client, nodePool)
+		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+		Expect(nodePool.Status.LastProvisioningTime).To(Equal(&nodeClaim.CreationTimestamp))
+		Expect(nodePool.Status.LastDisruptionTime).To(BeNil())
+
+		nodeClaim.StatusConditions().SetTrueWithReason(v1.ConditionTypeDisruptionReason, v1.ConditionTypeDisruptionReason, string(v1.DisruptionReasonEmpty))
+		ExpectApplied(ctx, env.Client, nodeClaim)
+		ExpectReconcileSucceeded(ctx, nodeClaimController, client.ObjectKeyFromObject(nodeClaim))
+		ExpectObjectReconciled(ctx, env.Client, nodePoolController, nodePool)
+		nodePool = ExpectExists(ctx, env.Client, nodePool)
+		Expect(nodePool.Status.LastDisruptionTime).ToNot(BeNil())
+
+		// the times are kept once the nodeclaim is gone
+		ExpectDeleted(ctx, env.Client, node, nodeClaim)
+		ExpectReconcileSucceeded(ctx, nodeController, client.ObjectKeyFromObject(node))
+		ExpectReconcileSucceeded(ctx, nodeClaimController, client.ObjectKeyFromObject(nodeClaim))
+		ExpectObjectReconciled(ctx, env.Client, nodePoolController, nodePool)
+		nodePool = ExpectExists(ctx, env.Client, nodePool)
+		Expect(nodePool.Status.LastProvisioningTime).ToNot(BeNil())
+		Expect(nodePool.Status.LastDisruptionTime).ToNot(BeNil())
+	})
 	It("should increase the counter when new nodes are created", func() {
 		ExpectApplied(ctx, env.Client, node, nodeClaim)
 		ExpectMakeNodesAndNodeClaimsInitializedAndStateUpdated(ctx, env.Client, nodeController, nodeClaimController, []*corev1.Node{node}, []*v1.NodeClaim{nodeClaim})
@@ -0,0 +1,122 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package defaulting
+
+import (
+	"context"
+	"sort"
+
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	controllerruntime "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+	"sigs.k8s.io/karpenter/pkg/cloudprovider"
+	"sigs.k8s.io/karpenter/pkg/operator/injection"
+	"sigs.k8s.io/karpenter/pkg/scheduling"
+	nodeclaimutils "sigs.k8s.io/karpenter/pkg/utils/nodeclaim"
+	nodepoolutils "sigs.k8s.io/karpenter/pkg/utils/nodepool"
+)
+
+// Controller defaults the requirements of NodePools and normalizes them into a canonical form, with a single
+// requirement per key that is sorted by key, so that equivalent requirements are always represented the same way.
+// Normalizing doesn't change which nodes satisfy the requirements, but defaulting does, so the defaults are only applied
+// to NodePools that haven't launched any NodeClaims yet. Otherwise, upgrading would drift every node of an existing
+// NodePool that falls outside of the defaults.
+type Controller struct {
+	kubeClient    client.Client
+	cloudProvider cloudprovider.CloudProvider
+}
+
+// NewController is a constructor
+func NewController(kubeClient client.Client, cloudProvider cloudprovider.CloudProvider) *Controller {
+	return &Controller{
+		kubeClient:    kubeClient,
+		cloudProvider: cloudProvider,
+	}
+}
+
+func (c *Controller) Reconcile(ctx context.Context, nodePool *v1.NodePool) (reconcile.Result, error) {
+	ctx = injection.WithControllerName(ctx, "nodepool.defaulting")
+	if !nodepoolutils.IsManaged(ctx, nodePool, c.cloudProvider) || !nodePool.DeletionTimestamp.IsZero() {
+		return reconcile.Result{}, nil
+	}
+	stored := nodePool.DeepCopy()
+	nodeClaims := &v1.NodeClaimList{}
+	if err := c.kubeClient.List(ctx, nodeClaims, nodeclaimutils.ForNodePool(nodePool.Name)); err != nil {
+		return reconcile.Result{}, err
+	}
+	if len(nodeClaims.Items) == 0 {
+		nodePool.Spec.Template.Spec.Requirements = nodeclaimutils.WithDefaultRequirements(nodePool.Spec.Template.Spec.Requirements, nodePool.Spec.Template.Labels)
+	}
+	nodePool.Spec.Template.Spec.Requirements = normalize(nodePool.Spec.Template.Spec.Requirements)
+	if !equality.Semantic.DeepEqual(stored, nodePool) {
+		// We use client.MergeFromWithOptimisticLock because patching a list with a JSON merge patch
+		// can cause races due to the fact that it fully replaces the list on a change
+		// Here, we are updating the requirement list
+		if err := c.kubeClient.Patch(ctx, nodePool, client.MergeFromWithOptions(stored, client.MergeFromWithOptimisticLock{})); err != nil {
+			if errors.IsConflict(err) {
+				return reconcile.Result{Requeue: true}, nil
+			}
+			return reconcile.Result{}, client.IgnoreNotFound(err)
+		}
+	}
+	return reconcile.Result{}, nil
+}
+
+func (c *Controller) Register(ctx context.Context, m manager.Manager) error {
+	return controllerruntime.NewControllerManagedBy(m).
+		Named("nodepool.defaulting").
+		For(&v1.NodePool{}, builder.WithPredicates(nodepoolutils.IsManagedPredicateFuncs(ctx, c.cloudProvider))).
+		WithOptions(controller.Options{MaxConcurrentReconciles: 10}).
+		Complete(reconcile.AsReconciler(m.GetClient(), c))
+}
+
+// normalize merges the requirements of each key into the fewest requirements that represent them, e.g. an Exists and a
+// NotIn requirement for the same key are merged into the NotIn requirement, and sorts the requirements by key. The
+// requirements of a key that can't be satisfied together are kept as they are, as merging them would replace them with
+// a DoesNotExist requirement that has a different meaning.
+func normalize(requirements []v1.NodeSelectorRequirementWithMinValues) []v1.NodeSelectorRequirementWithMinValues {
+	if len(requirements) == 0 {
+		return requirements
+	}
+	// requirements on deprecated labels are merged with the requirements on the labels that replaced them
+	byKey := lo.GroupBy(requirements, func(r v1.NodeSelectorRequirementWithMinValues) string {
+		return lo.CoalesceOrEmpty(v1.NormalizedLabels[r.Key], r.Key)
+	})
+	keys := lo.Keys(byKey)
+	sort.Strings(keys)
+	var normalized []v1.NodeSelectorRequirementWithMinValues
+	for _, key := range keys {
+		merged := scheduling.NewNodeSelectorRequirementsWithMinValues(byKey[key]...).Get(key)
+		if merged.Operator() == corev1.NodeSelectorOpDoesNotExist && lo.ContainsBy(byKey[key], func(r v1.NodeSelectorRequirementWithMinValues) bool {
+			return r.Operator != corev1.NodeSelectorOpDoesNotExist
+		}) {
+			normalized = append(normalized, byKey[key]...)
+			continue
+		}
+		normalized = append(normalized, merged.NodeSelectorRequirements()...)
+	}
+	return normalized
+}
@@ -0,0 +1,152 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package defaulting_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/karpenter/pkg/apis"
+	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+	"sigs.k8s.io/karpenter/pkg/cloudprovider/fake"
+	"sigs.k8s.io/karpenter/pkg/controllers/nodepool/defaulting"
+	"sigs.k8s.io/karpenter/pkg/operator/options"
+	"sigs.k8s.io/karpenter/pkg/test"
+	. "sigs.k8s.io/karpenter/pkg/test/expectations"
+	"sigs.k8s.io/karpenter/pkg/test/v1alpha1"
+	. "sigs.k8s.io/karpenter/pkg/utils/testing"
+)
+
+var (
+	nodePoolDefaultingController *defaulting.Controller
+	ctx                          context.Context
+	env                          *test.Environment
+	cp                           *fake.CloudProvider
+)
+
+func TestAPIs(t *testing.T) {
+	ctx = TestContextWithLogger(t)
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Defaulting")
+}
+
+var _ = BeforeSuite(func() {
+	ctx = options.ToContext(ctx, test.Options())
+	env = test.NewEnvironment(test.WithCRDs(apis.CRDs...), test.WithCRDs(v1alpha1.CRDs...))
+	cp = fake.NewCloudProvider()
+	nodePoolDefaultingController = defaulting.NewController(env.Client, cp)
+})
+var _ = AfterEach(func() {
+	ExpectCleanedUp(ctx, env.Client)
+})
+
+var _ = AfterSuite(func() {
+	Expect(env.Stop()).To(Succeed(), "Failed to stop environment")
+})
+
+func requirement(key string, operator corev1.NodeSelectorOperator, values ...string) v1.NodeSelectorRequirementWithMinValues {
+	return v1.NodeSelectorRequirementWithMinValues{NodeSelectorRequirement: corev1.NodeSelectorRequirement{Key: key, Operator: operator, Values: values}}
+}
+
+var _ = Describe("Defaulting", func() {
+	var nodePool *v1.NodePool
+	BeforeEach(func() {
+		nodePool = test.NodePool()
+	})
+	It("should default the architecture, operating system and capacity type requirements", func() {
+		ExpectApplied(ctx, env.Client, nodePool)
+		ExpectObjectReconciled(ctx, env.Client, nodePoolDefaultingController, nodePool)
+		nodePool = ExpectExists(ctx, env.Client, nodePool)
+		Expect(nodePool.Spec.Template.Spec.Requirements).To(Equal([]v1.NodeSelectorRequirementWithMinValues{
+			requirement(v1.CapacityTypeLabelKey, corev1.NodeSelectorOpIn, v1.CapacityTypeOnDemand),
+			requirement(corev1.LabelArchStable, corev1.NodeSelectorOpIn, v1.ArchitectureAmd64),
+			requirement(corev1.LabelOSStable, corev1.NodeSelectorOpIn, string(corev1.Linux)),
+		}))
+	})
+	It("should not default requirements that are constrained by the requirements or the template labels", func() {
+		nodePool.Spec.Template.Labels = map[string]string{corev1.LabelOSStable: string(corev1.Windows)}
+		nodePool.Spec.Template.Spec.Requirements = []v1.NodeSelectorRequirementWithMinValues{
+			requirement(v1.CapacityTypeLabelKey, corev1.NodeSelectorOpExists),
+			requirement(corev1.LabelArchStable, corev1.NodeSelectorOpIn, v1.ArchitectureArm64),
+		}
+		ExpectApplied(ctx, env.Client, nodePool)
+		ExpectObjectReconciled(ctx, env.Client, nodePoolDefaultingController, nodePool)
+		nodePool = ExpectExists(ctx, env.Client, nodePool)
+		Expect(nodePool.Spec.Template.Spec.Requirements).To(Equal([]v1.NodeSelectorRequirementWithMinValues{
+			requirement(v1.CapacityTypeLabelKey, corev1.NodeSelectorOpExists),
+			requirement(corev1.LabelArchStable, corev1.NodeSelectorOpIn, v1.ArchitectureArm64),
+		}))
+	})
+	It("should merge the requirements of each key and sort them by key", func() {
+		nodePool.Spec.Template.Spec.Requirements = []v1.NodeSelectorRequirementWithMinValues{
+			requirement(corev1.LabelTopologyZone, corev1.NodeSelectorOpIn, "test-zone-1", "test-zone-2", "test-zone-3"),
+			requirement(v1.CapacityTypeLabelKey, corev1.NodeSelectorOpIn, v1.CapacityTypeSpot),
+			requirement(corev1.LabelTopologyZone, corev1.NodeSelectorOpNotIn, "test-zone-2"),
+			requirement(corev1.LabelArchStable, corev1.NodeSelectorOpExists),
+			requirement(corev1.LabelOSStable, corev1.NodeSelectorOpExists),
+			requirement(corev1.LabelOSStable, corev1.NodeSelectorOpNotIn, string(corev1.Windows)),
+		}
+		ExpectApplied(ctx, env.Client, nodePool)
+		ExpectObjectReconciled(ctx, env.Client, nodePoolDefaultingController, nodePool)
+		nodePool = ExpectExists(ctx, env.Client, nodePool)
+		Expect(nodePool.Spec.Template.Spec.Requirements).To(Equal([]v1.NodeSelectorRequirementWithMinValues{
+			requirement(v1.CapacityTypeLabelKey, corev1.NodeSelectorOpIn, v1.CapacityTypeSpot),
+			requirement(corev1.LabelArchStable, corev1.NodeSelectorOpExists),
+			requirement(corev1.LabelOSStable, corev1.NodeSelectorOpNotIn, string(corev1.Windows)),
+			requirement(corev1.LabelTopologyZone, corev1.NodeSelectorOpIn, "test-zone-1", "test-zone-3"),
+		}))
+	})
+	It("should keep the requirements of a key that can't be satisfied together", func() {
+		nodePool.Spec.Template.Spec.Requirements = []v1.NodeSelectorRequirementWithMinValues{
+			requirement(v1.CapacityTypeLabelKey, corev1.NodeSelectorOpIn, v1.CapacityTypeSpot),
+			requirement(v1.CapacityTypeLabelKey, corev1.NodeSelectorOpIn, v1.CapacityTypeOnDemand),
+		}
+		ExpectApplied(ctx, env.Client, nodePool)
+		ExpectObjectReconciled(ctx, env.Client, nodePoolDefaultingController, nodePool)
+		nodePool = ExpectExists(ctx, env.Client, nodePool)
+		Expect(nodePool.Spec.Template.Spec.Requirements).To(ContainElements(
+			requirement(v1.CapacityTypeLabelKey, corev1.NodeSelectorOpIn, v1.CapacityTypeSpot),
+			requirement(v1.CapacityTypeLabelKey, corev1.NodeSelectorOpIn, v1.CapacityTypeOnDemand),
+		))
+	})
+	It("should only normalize the requirements of a NodePool that has launched NodeClaims", func() {
+		nodePool.Spec.Template.Spec.Requirements = []v1.NodeSelectorRequirementWithMinValues{
+			requirement(corev1.LabelTopologyZone, corev1.NodeSelectorOpIn, "test-zone-1", "test-zone-2"),
+			requirement(corev1.LabelTopologyZone, corev1.NodeSelectorOpNotIn, "test-zone-2"),
+		}
+		nodeClaim := test.NodeClaim(v1.NodeClaim{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{v1.NodePoolLabelKey: nodePool.Name}}})
+		ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
+		ExpectObjectReconciled(ctx, env.Client, nodePoolDefaultingController, nodePool)
+		nodePool = ExpectExists(ctx, env.Client, nodePool)
+		Expect(nodePool.Spec.Template.Spec.Requirements).To(Equal([]v1.NodeSelectorRequirementWithMinValues{
+			requirement(corev1.LabelTopologyZone, corev1.NodeSelectorOpIn, "test-zone-1"),
+		}))
+	})
+	It("should not patch a NodePool whose requirements are already defaulted and normalized", func() {
+		ExpectApplied(ctx, env.Client, nodePool)
+		ExpectObjectReconciled(ctx, env.Client, nodePoolDefaultingController, nodePool)
+		nodePool = ExpectExists(ctx, env.Client, nodePool)
+		resourceVersion := nodePool.ResourceVersion
+		ExpectObjectReconciled(ctx, env.Client, nodePoolDefaultingController, nodePool)
+		Expect(ExpectExists(ctx, env.Client, nodePool).ResourceVersion).To(Equal(resourceVersion))
+	})
+})
@@ -0,0 +1,141 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package degraded
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/samber/lo"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/clock"
+	controllerruntime "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+	"sigs.k8s.io/karpenter/pkg/cloudprovider"
+	"sigs.k8s.io/karpenter/pkg/controllers/state"
+	"sigs.k8s.io/karpenter/pkg/operator/injection"
+	nodepoolutils "sigs.k8s.io/karpenter/pkg/utils/nodepool"
+)
+
+const (
+	// launchFailureThreshold is the number of launch failures of the NodePool's NodeClaims within the
+	// state.LaunchFailureWindow that make the NodePool Degraded
+	launchFailureThreshold = 3
+	minBackoff             = time.Minute
+	maxBackoff             = 30 * time.Minute
+)
+
+// Controller marks the NodePools whose NodeClaims are repeatedly failing to launch as Degraded, backing off
+// exponentially while they keep failing. The failures are tracked by the cluster state, since NodeClaims that fail to
+// launch are often deleted before they could be counted.
+type Controller struct {
+	clock         clock.Clock
+	kubeClient    client.Client
+	cloudProvider cloudprovider.CloudProvider
+	cluster       *state.Cluster
+}
+
+// NewController is a constructor
+func NewController(clk clock.Clock, kubeClient client.Client, cloudProvider cloudprovider.CloudProvider, cluster *state.Cluster) *Controller {
+	return &Controller{
+		clock:         clk,
+		kubeClient:    kubeClient,
+		cloudProvider: cloudProvider,
+		cluster:       cluster,
+	}
+}
+
+func (c *Controller) Reconcile(ctx context.Context, nodePool *v1.NodePool) (reconcile.Result, error) {
+	ctx = injection.WithControllerName(ctx, "nodepool.degraded")
+	stored := nodePool.DeepCopy()
+
+	failures := c.cluster.LaunchFailures(nodePool.Name)
+	now := c.clock.Now()
+	backoff := nodePool.Status.LaunchBackoff
+	switch {
+	case backoff != nil && now.Before(backoff.Until.Time):
+		// the NodePool stays Degraded until its backoff elapses, so that it doesn't flap while launches are retried
+	case len(failures) >= launchFailureThreshold && (backoff == nil || failedSince(failures, backoff.Until.Add(-backoff.Duration.Duration))):
+		// the backoff doubles each time it elapses after launches failed again during it
+		duration := minBackoff
+		if backoff != nil {
+			duration = min(2*backoff.Duration.Duration, maxBackoff)
+		}
+		nodePool.Status.LaunchBackoff = &v1.LaunchBackoff{Duration: metav1.Duration{Duration: duration}, Until: metav1.NewTime(now.Add(duration))}
+		nodePool.StatusConditions().SetTrueWithReason(v1.ConditionTypeDegraded, failureClass(failures),
+			fmt.Sprintf("%d NodeClaims failed to launch in the last %s, backing off for %s", len(failures), state.LaunchFailureWindow, duration))
+	default:
+		nodePool.Status.LaunchBackoff = nil
+		if err := nodePool.StatusConditions().Clear(v1.ConditionTypeDegraded); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	if !equality.Semantic.DeepEqual(stored, nodePool) {
+		// We use client.MergeFromWithOptimisticLock because patching a list with a JSON merge patch
+		// can cause races due to the fact that it fully replaces the list on a change
+		// Here, we are updating the status condition list
+		if err := c.kubeClient.Status().Patch(ctx, nodePool, client.MergeFromWithOptions(stored, client.MergeFromWithOptimisticLock{})); client.IgnoreNotFound(err) != nil {
+			if errors.IsConflict(err) {
+				return reconcile.Result{Requeue: true}, nil
+			}
+			return reconcile.Result{}, err
+		}
+	}
+	if nodePool.Status.LaunchBackoff != nil {
+		return reconcile.Result{RequeueAfter: nodePool.Status.LaunchBackoff.Until.Sub(now)}, nil
+	}
+	return reconcile.Result{}, nil
+}
+
+// failedSince returns whether any of the launch failures happened after the time
+func failedSince(failures []state.LaunchFailure, since time.Time) bool {
+	return lo.ContainsBy(failures, func(f state.LaunchFailure) bool { return f.Time.After(since) })
+}
+
+// failureClass returns the most common reason that the NodeClaims failed to launch for
+func failureClass(failures []state.LaunchFailure) string {
+	counts := lo.CountValuesBy(failures, func(f state.LaunchFailure) string { return f.Reason })
+	reasons := lo.Keys(counts)
+	// Order reasons with equal counts by name for a consistent class
+	sort.Slice(reasons, func(a, b int) bool {
+		if counts[reasons[a]] == counts[reasons[b]] {
+			return reasons[a] < reasons[b]
+		}
+		return counts[reasons[a]] > counts[reasons[b]]
+	})
+	return reasons[0]
+}
+
+func (c *Controller) Register(ctx context.Context, m manager.Manager) error {
+	return controllerruntime.NewControllerManagedBy(m).
+		Named("nodepool.degraded").
+		For(&v1.NodePool{}, builder.WithPredicates(nodepoolutils.IsManagedPredicateFuncs(ctx, c.cloudProvider))).
+		Watches(&v1.NodeClaim{}, nodepoolutils.NodeClaimEventHandler()).
+		WithOptions(controller.Options{MaxConcurrentReconciles: 10}).
+		Complete(reconcile.AsReconciler(m.GetClient(), c))
+}
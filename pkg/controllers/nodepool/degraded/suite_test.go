@@ -0,0 +1,141 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package degraded_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clock "k8s.io/utils/clock/testing"
+
+	"sigs.k8s.io/karpenter/pkg/apis"
+	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+	"sigs.k8s.io/karpenter/pkg/cloudprovider/fake"
+	"sigs.k8s.io/karpenter/pkg/controllers/nodepool/degraded"
+	"sigs.k8s.io/karpenter/pkg/controllers/state"
+	"sigs.k8s.io/karpenter/pkg/operator/options"
+	"sigs.k8s.io/karpenter/pkg/test"
+	. "sigs.k8s.io/karpenter/pkg/test/expectations"
+	"sigs.k8s.io/karpenter/pkg/test/v1alpha1"
+	. "sigs.k8s.io/karpenter/pkg/utils/testing"
+)
+
+var (
+	degradedController *degraded.Controller
+	ctx                context.Context
+	env                *test.Environment
+	fakeClock          *clock.FakeClock
+	cp                 *fake.CloudProvider
+	cluster            *state.Cluster
+)
+
+func TestAPIs(t *testing.T) {
+	ctx = TestContextWithLogger(t)
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Degraded")
+}
+
+var _ = BeforeSuite(func() {
+	env = test.NewEnvironment(test.WithCRDs(apis.CRDs...), test.WithCRDs(v1alpha1.CRDs...))
+	fakeClock = clock.NewFakeClock(time.Now())
+	cp = fake.NewCloudProvider()
+	cluster = state.NewCluster(fakeClock, env.Client, cp)
+	degradedController = degraded.NewController(fakeClock, env.Client, cp, cluster)
+})
+var _ = BeforeEach(func() {
+	ctx = options.ToContext(ctx, test.Options())
+	fakeClock.SetTime(time.Now())
+	cluster.Reset()
+})
+var _ = AfterEach(func() {
+	ExpectCleanedUp(ctx, env.Client)
+})
+
+var _ = AfterSuite(func() {
+	Expect(env.Stop()).To(Succeed(), "Failed to stop environment")
+})
+
+var _ = Describe("Degraded", func() {
+	var nodePool *v1.NodePool
+	BeforeEach(func() {
+		nodePool = test.NodePool()
+		ExpectApplied(ctx, env.Client, nodePool)
+	})
+	failLaunches := func(count int, reason string) {
+		for range count {
+			cluster.RecordLaunchFailure(nodePool.Name, reason)
+		}
+	}
+	It("should not mark the NodePool as Degraded when few NodeClaims failed to launch", func() {
+		failLaunches(2, v1.LaunchFailureReasonQuotaExceeded)
+		ExpectObjectReconciled(ctx, env.Client, degradedController, nodePool)
+		nodePool = ExpectExists(ctx, env.Client, nodePool)
+		Expect(nodePool.StatusConditions().Get(v1.ConditionTypeDegraded)).To(BeNil())
+		Expect(nodePool.Status.LaunchBackoff).To(BeNil())
+	})
+	It("should mark the NodePool as Degraded with the most common failure class", func() {
+		failLaunches(2, v1.LaunchFailureReasonQuotaExceeded)
+		failLaunches(1, v1.LaunchFailureReasonInvalidConfiguration)
+		result := ExpectObjectReconciled(ctx, env.Client, degradedController, nodePool)
+		nodePool = ExpectExists(ctx, env.Client, nodePool)
+		condition := ExpectStatusConditionExists(nodePool, v1.ConditionTypeDegraded)
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(v1.LaunchFailureReasonQuotaExceeded))
+		Expect(nodePool.Status.LaunchBackoff).ToNot(BeNil())
+		Expect(nodePool.Status.LaunchBackoff.Duration.Duration).To(Equal(time.Minute))
+		Expect(result.RequeueAfter).To(BeNumerically("~", time.Minute, time.Second))
+	})
+	It("should not count launch failures outside of the window", func() {
+		failLaunches(2, v1.LaunchFailureReasonQuotaExceeded)
+		fakeClock.Step(state.LaunchFailureWindow)
+		failLaunches(1, v1.LaunchFailureReasonQuotaExceeded)
+		ExpectObjectReconciled(ctx, env.Client, degradedController, nodePool)
+		nodePool = ExpectExists(ctx, env.Client, nodePool)
+		Expect(nodePool.StatusConditions().Get(v1.ConditionTypeDegraded)).To(BeNil())
+	})
+	It("should double the backoff each time it elapses while NodeClaims keep failing to launch", func() {
+		failLaunches(3, v1.LaunchFailureReasonQuotaExceeded)
+		ExpectObjectReconciled(ctx, env.Client, degradedController, nodePool)
+		for _, expected := range []time.Duration{2 * time.Minute, 4 * time.Minute, 8 * time.Minute, 16 * time.Minute, 30 * time.Minute, 30 * time.Minute} {
+			nodePool = ExpectExists(ctx, env.Client, nodePool)
+			fakeClock.SetTime(nodePool.Status.LaunchBackoff.Until.Time)
+			failLaunches(3, v1.LaunchFailureReasonQuotaExceeded)
+			ExpectObjectReconciled(ctx, env.Client, degradedController, nodePool)
+			nodePool = ExpectExists(ctx, env.Client, nodePool)
+			Expect(nodePool.Status.LaunchBackoff.Duration.Duration).To(Equal(expected))
+		}
+	})
+	It("should stay Degraded until the backoff elapses once NodeClaims stop failing to launch", func() {
+		failLaunches(3, v1.LaunchFailureReasonQuotaExceeded)
+		ExpectObjectReconciled(ctx, env.Client, degradedController, nodePool)
+
+		fakeClock.Step(30 * time.Second)
+		ExpectObjectReconciled(ctx, env.Client, degradedController, nodePool)
+		nodePool = ExpectExists(ctx, env.Client, nodePool)
+		Expect(nodePool.StatusConditions().IsTrue(v1.ConditionTypeDegraded)).To(BeTrue())
+
+		fakeClock.Step(30 * time.Second)
+		ExpectObjectReconciled(ctx, env.Client, degradedController, nodePool)
+		nodePool = ExpectExists(ctx, env.Client, nodePool)
+		Expect(nodePool.StatusConditions().Get(v1.ConditionTypeDegraded)).To(BeNil())
+		Expect(nodePool.Status.LaunchBackoff).To(BeNil())
+	})
+})
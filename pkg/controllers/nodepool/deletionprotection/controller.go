@@ -0,0 +1,118 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletionprotection
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	controllerruntime "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+	"sigs.k8s.io/karpenter/pkg/cloudprovider"
+	"sigs.k8s.io/karpenter/pkg/events"
+	"sigs.k8s.io/karpenter/pkg/operator/injection"
+	"sigs.k8s.io/karpenter/pkg/operator/options"
+	nodeclaimutils "sigs.k8s.io/karpenter/pkg/utils/nodeclaim"
+	nodepoolutils "sigs.k8s.io/karpenter/pkg/utils/nodepool"
+)
+
+// Controller protects NodePools from being deleted while they still own NodeClaims. Deleting a NodePool deletes all of
+// its NodeClaims, so the deletion of a NodePool is held by a finalizer until its NodeClaims are gone or the deletion is
+// allowed through the allow-deletion annotation. The finalizer is removed from all NodePools when the feature gate is
+// disabled, so that their deletion isn't held forever.
+type Controller struct {
+	kubeClient    client.Client
+	cloudProvider cloudprovider.CloudProvider
+	recorder      events.Recorder
+}
+
+// NewController is a constructor
+func NewController(kubeClient client.Client, cloudProvider cloudprovider.CloudProvider, recorder events.Recorder) *Controller {
+	return &Controller{
+		kubeClient:    kubeClient,
+		cloudProvider: cloudProvider,
+		recorder:      recorder,
+	}
+}
+
+func (c *Controller) Reconcile(ctx context.Context, nodePool *v1.NodePool) (reconcile.Result, error) {
+	ctx = injection.WithControllerName(ctx, "nodepool.deletionprotection")
+	if !nodepoolutils.IsManaged(ctx, nodePool, c.cloudProvider) {
+		return reconcile.Result{}, nil
+	}
+	stored := nodePool.DeepCopy()
+	switch {
+	case !options.FromContext(ctx).FeatureGates.NodePoolDeletionProtection:
+		controllerutil.RemoveFinalizer(nodePool, v1.DeletionProtectionFinalizer)
+	case nodePool.DeletionTimestamp.IsZero():
+		controllerutil.AddFinalizer(nodePool, v1.DeletionProtectionFinalizer)
+	case controllerutil.ContainsFinalizer(nodePool, v1.DeletionProtectionFinalizer):
+		allowed, err := c.deletionAllowed(ctx, nodePool)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if allowed {
+			controllerutil.RemoveFinalizer(nodePool, v1.DeletionProtectionFinalizer)
+		}
+	}
+	if !equality.Semantic.DeepEqual(stored, nodePool) {
+		// We use client.MergeFromWithOptimisticLock because patching a list with a JSON merge patch
+		// can cause races due to the fact that it fully replaces the list on a change
+		// Here, we are updating the finalizer list
+		if err := c.kubeClient.Patch(ctx, nodePool, client.MergeFromWithOptions(stored, client.MergeFromWithOptimisticLock{})); err != nil {
+			if errors.IsConflict(err) {
+				return reconcile.Result{Requeue: true}, nil
+			}
+			return reconcile.Result{}, client.IgnoreNotFound(err)
+		}
+	}
+	return reconcile.Result{}, nil
+}
+
+// deletionAllowed returns true if the NodePool no longer owns NodeClaims or its deletion is explicitly allowed
+func (c *Controller) deletionAllowed(ctx context.Context, nodePool *v1.NodePool) (bool, error) {
+	if nodePool.Annotations[v1.AllowDeletionAnnotationKey] == "true" {
+		return true, nil
+	}
+	nodeClaims, err := nodeclaimutils.ListManaged(ctx, c.kubeClient, c.cloudProvider, nodeclaimutils.ForNodePool(nodePool.Name))
+	if err != nil {
+		return false, fmt.Errorf("listing nodeclaims, %w", err)
+	}
+	if len(nodeClaims) == 0 {
+		return true, nil
+	}
+	c.recorder.Publish(DeletionBlockedEvent(nodePool, len(nodeClaims)))
+	return false, nil
+}
+
+func (c *Controller) Register(ctx context.Context, m manager.Manager) error {
+	return controllerruntime.NewControllerManagedBy(m).
+		Named("nodepool.deletionprotection").
+		For(&v1.NodePool{}, builder.WithPredicates(nodepoolutils.IsManagedPredicateFuncs(ctx, c.cloudProvider))).
+		Watches(&v1.NodeClaim{}, nodepoolutils.NodeClaimEventHandler()).
+		WithOptions(controller.Options{MaxConcurrentReconciles: 10}).
+		Complete(reconcile.AsReconciler(m.GetClient(), c))
+}
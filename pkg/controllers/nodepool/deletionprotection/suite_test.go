@@ -0,0 +1,112 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletionprotection_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/samber/lo"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/karpenter/pkg/apis"
+	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+	"sigs.k8s.io/karpenter/pkg/cloudprovider/fake"
+	"sigs.k8s.io/karpenter/pkg/controllers/nodepool/deletionprotection"
+	"sigs.k8s.io/karpenter/pkg/operator/options"
+	"sigs.k8s.io/karpenter/pkg/test"
+	. "sigs.k8s.io/karpenter/pkg/test/expectations"
+	"sigs.k8s.io/karpenter/pkg/test/v1alpha1"
+	. "sigs.k8s.io/karpenter/pkg/utils/testing"
+)
+
+var (
+	deletionProtectionController *deletionprotection.Controller
+	ctx                          context.Context
+	env                          *test.Environment
+	cp                           *fake.CloudProvider
+	recorder                     *test.EventRecorder
+)
+
+func TestAPIs(t *testing.T) {
+	ctx = TestContextWithLogger(t)
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "DeletionProtection")
+}
+
+var _ = BeforeSuite(func() {
+	env = test.NewEnvironment(test.WithCRDs(apis.CRDs...), test.WithCRDs(v1alpha1.CRDs...))
+	cp = fake.NewCloudProvider()
+	recorder = test.NewEventRecorder()
+	deletionProtectionController = deletionprotection.NewController(env.Client, cp, recorder)
+})
+var _ = BeforeEach(func() {
+	ctx = options.ToContext(ctx, test.Options(test.OptionsFields{FeatureGates: test.FeatureGates{NodePoolDeletionProtection: lo.ToPtr(true)}}))
+	recorder.Reset()
+})
+var _ = AfterEach(func() {
+	ExpectCleanedUp(ctx, env.Client)
+})
+
+var _ = AfterSuite(func() {
+	Expect(env.Stop()).To(Succeed(), "Failed to stop environment")
+})
+
+var _ = Describe("DeletionProtection", func() {
+	var nodePool *v1.NodePool
+	var nodeClaim *v1.NodeClaim
+	BeforeEach(func() {
+		nodePool = test.NodePool()
+		nodeClaim = test.NodeClaim(v1.NodeClaim{
+			ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{v1.NodePoolLabelKey: nodePool.Name}},
+		})
+		ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
+		ExpectObjectReconciled(ctx, env.Client, deletionProtectionController, nodePool)
+		nodePool = ExpectExists(ctx, env.Client, nodePool)
+	})
+	It("should add the deletion protection finalizer", func() {
+		Expect(nodePool.Finalizers).To(ContainElement(v1.DeletionProtectionFinalizer))
+	})
+	It("should block the deletion of a nodepool that owns nodeclaims", func() {
+		Expect(env.Client.Delete(ctx, nodePool)).To(Succeed())
+		ExpectObjectReconciled(ctx, env.Client, deletionProtectionController, nodePool)
+		nodePool = ExpectExists(ctx, env.Client, nodePool)
+		Expect(nodePool.Finalizers).To(ContainElement(v1.DeletionProtectionFinalizer))
+		Expect(recorder.Calls("DeletionBlocked")).To(Equal(1))
+	})
+	It("should allow the deletion of a nodepool once its nodeclaims are gone", func() {
+		Expect(env.Client.Delete(ctx, nodePool)).To(Succeed())
+		ExpectDeleted(ctx, env.Client, nodeClaim)
+		ExpectObjectReconciled(ctx, env.Client, deletionProtectionController, nodePool)
+		ExpectNotFound(ctx, env.Client, nodePool)
+	})
+	It("should allow the deletion of a nodepool with the allow-deletion annotation", func() {
+		nodePool.Annotations = lo.Assign(nodePool.Annotations, map[string]string{v1.AllowDeletionAnnotationKey: "true"})
+		ExpectApplied(ctx, env.Client, nodePool)
+		Expect(env.Client.Delete(ctx, nodePool)).To(Succeed())
+		ExpectObjectReconciled(ctx, env.Client, deletionProtectionController, nodePool)
+		ExpectNotFound(ctx, env.Client, nodePool)
+	})
+	It("should remove the deletion protection finalizer when the feature gate is disabled", func() {
+		ctx = options.ToContext(ctx, test.Options(test.OptionsFields{FeatureGates: test.FeatureGates{NodePoolDeletionProtection: lo.ToPtr(false)}}))
+		ExpectObjectReconciled(ctx, env.Client, deletionProtectionController, nodePool)
+		nodePool = ExpectExists(ctx, env.Client, nodePool)
+		Expect(nodePool.Finalizers).ToNot(ContainElement(v1.DeletionProtectionFinalizer))
+	})
+})
@@ -27,6 +27,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
@@ -53,16 +54,20 @@ func NewController(kubeClient client.Client, cloudProvider cloudprovider.CloudPr
 // Reconcile the resource
 func (c *Controller) Reconcile(ctx context.Context, np *v1.NodePool) (reconcile.Result, error) {
 	ctx = injection.WithControllerName(ctx, "nodepool.hash")
-	if !nodepoolutils.IsManaged(np, c.cloudProvider) {
+	if !nodepoolutils.IsManaged(ctx, np, c.cloudProvider) {
 		return reconcile.Result{}, nil
 	}
 
 	stored := np.DeepCopy()
 
-	if np.Annotations[v1.NodePoolHashVersionAnnotationKey] != v1.NodePoolHashVersion {
-		if err := c.updateNodeClaimHash(ctx, np); err != nil {
-			return reconcile.Result{}, err
-		}
+	// NodeClaims are migrated even when the NodePool's hash version is up to date, as NodeClaims can be launched with an
+	// older hash version by a controller that hasn't been upgraded yet
+	if err := c.updateNodeClaimHash(ctx, np); err != nil {
+		return reconcile.Result{}, err
+	}
+	// A fleet replacement is only acknowledged for the change that it was acknowledged with
+	if hash, ok := np.Annotations[v1.NodePoolHashAnnotationKey]; ok && hash != np.Hash() {
+		delete(np.Annotations, v1.AcknowledgeFleetReplacementAnnotationKey)
 	}
 	np.Annotations = lo.Assign(np.Annotations, map[string]string{
 		v1.NodePoolHashAnnotationKey:        np.Hash(),
@@ -77,10 +82,13 @@ func (c *Controller) Reconcile(ctx context.Context, np *v1.NodePool) (reconcile.
 	return reconcile.Result{}, nil
 }
 
-func (c *Controller) Register(_ context.Context, m manager.Manager) error {
+func (c *Controller) Register(ctx context.Context, m manager.Manager) error {
 	return controllerruntime.NewControllerManagedBy(m).
 		Named("nodepool.hash").
-		For(&v1.NodePool{}, builder.WithPredicates(nodepoolutils.IsManagedPredicateFuncs(c.cloudProvider))).
+		For(&v1.NodePool{}, builder.WithPredicates(nodepoolutils.IsManagedPredicateFuncs(ctx, c.cloudProvider))).
+		Watches(&v1.NodeClaim{}, nodepoolutils.NodeClaimEventHandler(), builder.WithPredicates(predicate.NewPredicateFuncs(func(o client.Object) bool {
+			return o.GetAnnotations()[v1.NodePoolHashVersionAnnotationKey] != v1.NodePoolHashVersion
+		}))).
 		WithOptions(controller.Options{MaxConcurrentReconciles: 10}).
 		Complete(reconcile.AsReconciler(m.GetClient(), c))
 }
@@ -88,6 +96,8 @@ func (c *Controller) Register(_ context.Context, m manager.Manager) error {
 // Updating `nodepool-hash-version` annotation inside the karpenter controller means a breaking change has been made to the hash calculation.
 // The `nodepool-hash` annotation on the NodePool will be updated, due to the breaking change, making the `nodepool-hash` on the NodeClaim different from
 // NodePool. Since, we cannot rely on the `nodepool-hash` on the NodeClaims, due to the breaking change, we will need to re-calculate the hash and update the annotation.
+// NodeClaims that are already on the controller's hash version are left as they are, so that adding a field to the hash
+// never marks NodeClaims that were launched before the upgrade as drifted.
 // For more information on the Drift Hash Versioning: https://github.com/kubernetes-sigs/karpenter/blob/main/designs/drift-hash-versioning.md
 func (c *Controller) updateNodeClaimHash(ctx context.Context, np *v1.NodePool) error {
 	nodeClaims, err := nodeclaimutils.ListManaged(ctx, c.kubeClient, c.cloudProvider, nodeclaimutils.ForNodePool(np.Name))
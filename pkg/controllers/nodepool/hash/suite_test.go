@@ -32,6 +32,7 @@ import (
 	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
 	"sigs.k8s.io/karpenter/pkg/cloudprovider/fake"
 	"sigs.k8s.io/karpenter/pkg/controllers/nodepool/hash"
+	"sigs.k8s.io/karpenter/pkg/operator/options"
 	"sigs.k8s.io/karpenter/pkg/test"
 	. "sigs.k8s.io/karpenter/pkg/test/expectations"
 	"sigs.k8s.io/karpenter/pkg/test/v1alpha1"
@@ -50,6 +51,7 @@ func TestAPIs(t *testing.T) {
 }
 
 var _ = BeforeSuite(func() {
+	ctx = options.ToContext(ctx, test.Options())
 	env = test.NewEnvironment(test.WithCRDs(apis.CRDs...), test.WithCRDs(v1alpha1.CRDs...))
 	cp = fake.NewCloudProvider()
 	nodePoolController = hash.NewController(env.Client, cp)
@@ -147,6 +149,32 @@ var _ = Describe("Static Drift Hash", func() {
 
 		Expect(nodePool.Annotations).To(HaveKeyWithValue(v1.NodePoolHashAnnotationKey, expectedHash))
 	})
+	It("should remove the fleet replacement acknowledgement once the acknowledged change is hashed", func() {
+		ExpectApplied(ctx, env.Client, nodePool)
+		ExpectObjectReconciled(ctx, env.Client, nodePoolController, nodePool)
+		nodePool = ExpectExists(ctx, env.Client, nodePool)
+
+		nodePool.Annotations[v1.AcknowledgeFleetReplacementAnnotationKey] = "true"
+		nodePool.Spec.Template.Spec.Taints = nil
+		ExpectApplied(ctx, env.Client, nodePool)
+		ExpectObjectReconciled(ctx, env.Client, nodePoolController, nodePool)
+		nodePool = ExpectExists(ctx, env.Client, nodePool)
+
+		Expect(nodePool.Annotations).To(HaveKeyWithValue(v1.NodePoolHashAnnotationKey, nodePool.Hash()))
+		Expect(nodePool.Annotations).ToNot(HaveKey(v1.AcknowledgeFleetReplacementAnnotationKey))
+	})
+	It("should keep the fleet replacement acknowledgement until the acknowledged change is made", func() {
+		ExpectApplied(ctx, env.Client, nodePool)
+		ExpectObjectReconciled(ctx, env.Client, nodePoolController, nodePool)
+		nodePool = ExpectExists(ctx, env.Client, nodePool)
+
+		nodePool.Annotations[v1.AcknowledgeFleetReplacementAnnotationKey] = "true"
+		ExpectApplied(ctx, env.Client, nodePool)
+		ExpectObjectReconciled(ctx, env.Client, nodePoolController, nodePool)
+		nodePool = ExpectExists(ctx, env.Client, nodePool)
+
+		Expect(nodePool.Annotations).To(HaveKeyWithValue(v1.AcknowledgeFleetReplacementAnnotationKey, "true"))
+	})
 	It("should update nodepool hash version when the nodepool hash version is out of sync with the controller hash version", func() {
 		nodePool.Annotations = map[string]string{
 			v1.NodePoolHashAnnotationKey:        "abceduefed",
@@ -226,6 +254,29 @@ var _ = Describe("Static Drift Hash", func() {
 		Expect(nodeClaim.Annotations).To(HaveKeyWithValue(v1.NodePoolHashAnnotationKey, "1234564654"))
 		Expect(nodeClaim.Annotations).To(HaveKeyWithValue(v1.NodePoolHashVersionAnnotationKey, v1.NodePoolHashVersion))
 	})
+	It("should update the nodepool hash on nodeclaims with an older hash version when the nodepool hash version matches", func() {
+		ExpectApplied(ctx, env.Client, nodePool)
+		ExpectObjectReconciled(ctx, env.Client, nodePoolController, nodePool)
+		nodePool = ExpectExists(ctx, env.Client, nodePool)
+		Expect(nodePool.Annotations).To(HaveKeyWithValue(v1.NodePoolHashVersionAnnotationKey, v1.NodePoolHashVersion))
+
+		// the NodeClaim was launched by a controller that hasn't been upgraded yet
+		nodeClaim := test.NodeClaim(v1.NodeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{v1.NodePoolLabelKey: nodePool.Name},
+				Annotations: map[string]string{
+					v1.NodePoolHashAnnotationKey:        "1234564654",
+					v1.NodePoolHashVersionAnnotationKey: "test-version",
+				},
+			},
+		})
+		ExpectApplied(ctx, env.Client, nodeClaim)
+
+		ExpectObjectReconciled(ctx, env.Client, nodePoolController, nodePool)
+		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
+		Expect(nodeClaim.Annotations).To(HaveKeyWithValue(v1.NodePoolHashAnnotationKey, nodePool.Hash()))
+		Expect(nodeClaim.Annotations).To(HaveKeyWithValue(v1.NodePoolHashVersionAnnotationKey, v1.NodePoolHashVersion))
+	})
 	It("should not update nodepool hash on the nodeclaim if it's drifted", func() {
 		nodePool.Annotations = map[string]string{
 			v1.NodePoolHashAnnotationKey:        "abceduefed",
@@ -0,0 +1,111 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package overlap
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	controllerruntime "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+	"sigs.k8s.io/karpenter/pkg/cloudprovider"
+	"sigs.k8s.io/karpenter/pkg/events"
+	"sigs.k8s.io/karpenter/pkg/operator/injection"
+	"sigs.k8s.io/karpenter/pkg/scheduling"
+	nodepoolutils "sigs.k8s.io/karpenter/pkg/utils/nodepool"
+)
+
+// Controller warns about NodePools that overlap, i.e. that have the same weight and could both provision nodes for the
+// same pods. The provisioner picks between such NodePools by their names, which is rarely what was intended.
+type Controller struct {
+	kubeClient    client.Client
+	cloudProvider cloudprovider.CloudProvider
+	recorder      events.Recorder
+}
+
+// NewController is a constructor
+func NewController(kubeClient client.Client, cloudProvider cloudprovider.CloudProvider, recorder events.Recorder) *Controller {
+	return &Controller{
+		kubeClient:    kubeClient,
+		cloudProvider: cloudProvider,
+		recorder:      recorder,
+	}
+}
+
+func (c *Controller) Reconcile(ctx context.Context, nodePool *v1.NodePool) (reconcile.Result, error) {
+	ctx = injection.WithControllerName(ctx, "nodepool.overlap")
+	if !nodePool.DeletionTimestamp.IsZero() || nodePool.IsStatic() {
+		return reconcile.Result{}, nil
+	}
+	nodePools, err := nodepoolutils.ListManaged(ctx, c.kubeClient, c.cloudProvider)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("listing nodepools, %w", err)
+	}
+	for _, other := range nodePools {
+		if other.Name == nodePool.Name || !other.DeletionTimestamp.IsZero() || other.IsStatic() || !Overlaps(nodePool, other) {
+			continue
+		}
+		// The NodePool that the provisioner orders first wins the pods that both NodePools can schedule
+		ordered := []*v1.NodePool{nodePool, other}
+		nodepoolutils.OrderByWeight(ordered)
+		c.recorder.Publish(OverlappingEvent(nodePool, other, ordered[0]))
+	}
+	return reconcile.Result{}, nil
+}
+
+// Overlaps returns true if the NodePools have the same weight and a pod could schedule to either of them. NodePools
+// with different taints are considered to be meant for different pods, even though a pod could tolerate both.
+func Overlaps(a, b *v1.NodePool) bool {
+	if lo.FromPtr(a.Spec.Weight) != lo.FromPtr(b.Spec.Weight) {
+		return false
+	}
+	if a.Spec.AllowedNamespaces != nil && b.Spec.AllowedNamespaces != nil && !lo.Some(a.Spec.AllowedNamespaces, b.Spec.AllowedNamespaces) {
+		return false
+	}
+	if !sameTaints(a.Spec.Template.Spec.Taints, b.Spec.Template.Spec.Taints) {
+		return false
+	}
+	return requirements(a).Intersects(requirements(b)) == nil
+}
+
+func requirements(nodePool *v1.NodePool) scheduling.Requirements {
+	requirements := scheduling.NewNodeSelectorRequirementsWithMinValues(nodePool.Spec.Template.Spec.Requirements...)
+	requirements.Add(scheduling.NewLabelRequirements(nodePool.Spec.Template.Labels).Values()...)
+	return requirements
+}
+
+func sameTaints(a, b []corev1.Taint) bool {
+	return len(a) == len(b) && lo.EveryBy(a, func(taint corev1.Taint) bool {
+		return lo.ContainsBy(b, func(other corev1.Taint) bool { return taint.MatchTaint(&other) && taint.Value == other.Value })
+	})
+}
+
+func (c *Controller) Register(ctx context.Context, m manager.Manager) error {
+	return controllerruntime.NewControllerManagedBy(m).
+		Named("nodepool.overlap").
+		For(&v1.NodePool{}, builder.WithPredicates(nodepoolutils.IsManagedPredicateFuncs(ctx, c.cloudProvider))).
+		WithOptions(controller.Options{MaxConcurrentReconciles: 10}).
+		Complete(reconcile.AsReconciler(m.GetClient(), c))
+}
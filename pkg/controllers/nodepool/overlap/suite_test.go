@@ -0,0 +1,115 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package overlap_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/karpenter/pkg/apis"
+	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+	"sigs.k8s.io/karpenter/pkg/cloudprovider/fake"
+	"sigs.k8s.io/karpenter/pkg/controllers/nodepool/overlap"
+	"sigs.k8s.io/karpenter/pkg/operator/options"
+	"sigs.k8s.io/karpenter/pkg/test"
+	. "sigs.k8s.io/karpenter/pkg/test/expectations"
+	"sigs.k8s.io/karpenter/pkg/test/v1alpha1"
+	. "sigs.k8s.io/karpenter/pkg/utils/testing"
+)
+
+var (
+	overlapController *overlap.Controller
+	ctx               context.Context
+	env               *test.Environment
+	cp                *fake.CloudProvider
+	recorder          *test.EventRecorder
+)
+
+func TestAPIs(t *testing.T) {
+	ctx = TestContextWithLogger(t)
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Overlap")
+}
+
+var _ = BeforeSuite(func() {
+	env = test.NewEnvironment(test.WithCRDs(apis.CRDs...), test.WithCRDs(v1alpha1.CRDs...))
+	cp = fake.NewCloudProvider()
+	recorder = test.NewEventRecorder()
+	overlapController = overlap.NewController(env.Client, cp, recorder)
+})
+var _ = BeforeEach(func() {
+	ctx = options.ToContext(ctx, test.Options())
+	recorder.Reset()
+})
+var _ = AfterEach(func() {
+	ExpectCleanedUp(ctx, env.Client)
+})
+
+var _ = AfterSuite(func() {
+	Expect(env.Stop()).To(Succeed(), "Failed to stop environment")
+})
+
+var _ = Describe("Overlap", func() {
+	var nodePool, other *v1.NodePool
+	BeforeEach(func() {
+		nodePool = test.NodePool(v1.NodePool{ObjectMeta: metav1.ObjectMeta{Name: "a"}})
+		other = test.NodePool(v1.NodePool{ObjectMeta: metav1.ObjectMeta{Name: "b"}})
+	})
+	It("should warn about NodePools with the same weight and compatible requirements", func() {
+		ExpectApplied(ctx, env.Client, nodePool, other)
+		ExpectObjectReconciled(ctx, env.Client, overlapController, nodePool)
+		Expect(recorder.Calls("Overlapping")).To(Equal(1))
+		// NodePools with equal weights are ordered by name, so the pods go to the NodePool with the greater name
+		Expect(recorder.DetectedEvent(`Nodepool overlaps with nodepool "b", both have weight 0 and compatible requirements, pods that can schedule to both are provisioned by nodepool "b"`)).To(BeTrue())
+	})
+	It("should not warn about NodePools with different weights", func() {
+		other.Spec.Weight = lo.ToPtr(int32(10))
+		ExpectApplied(ctx, env.Client, nodePool, other)
+		ExpectObjectReconciled(ctx, env.Client, overlapController, nodePool)
+		Expect(recorder.Calls("Overlapping")).To(Equal(0))
+	})
+	It("should not warn about NodePools with disjoint requirements", func() {
+		nodePool.Spec.Template.Spec.Requirements = []v1.NodeSelectorRequirementWithMinValues{
+			{NodeSelectorRequirement: corev1.NodeSelectorRequirement{Key: v1.CapacityTypeLabelKey, Operator: corev1.NodeSelectorOpIn, Values: []string{v1.CapacityTypeSpot}}},
+		}
+		other.Spec.Template.Spec.Requirements = []v1.NodeSelectorRequirementWithMinValues{
+			{NodeSelectorRequirement: corev1.NodeSelectorRequirement{Key: v1.CapacityTypeLabelKey, Operator: corev1.NodeSelectorOpIn, Values: []string{v1.CapacityTypeOnDemand}}},
+		}
+		ExpectApplied(ctx, env.Client, nodePool, other)
+		ExpectObjectReconciled(ctx, env.Client, overlapController, nodePool)
+		Expect(recorder.Calls("Overlapping")).To(Equal(0))
+	})
+	It("should not warn about NodePools with different taints", func() {
+		other.Spec.Template.Spec.Taints = []corev1.Taint{{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule}}
+		ExpectApplied(ctx, env.Client, nodePool, other)
+		ExpectObjectReconciled(ctx, env.Client, overlapController, nodePool)
+		Expect(recorder.Calls("Overlapping")).To(Equal(0))
+	})
+	It("should not warn about NodePools restricted to different namespaces", func() {
+		nodePool.Spec.AllowedNamespaces = []string{"team-a"}
+		other.Spec.AllowedNamespaces = []string{"team-b"}
+		ExpectApplied(ctx, env.Client, nodePool, other)
+		ExpectObjectReconciled(ctx, env.Client, overlapController, nodePool)
+		Expect(recorder.Calls("Overlapping")).To(Equal(0))
+	})
+})
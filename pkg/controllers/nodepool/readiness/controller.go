@@ -18,14 +18,15 @@ package readiness
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/awslabs/operatorpkg/object"
 	"github.com/awslabs/operatorpkg/status"
 	"github.com/samber/lo"
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	controllerruntime "sigs.k8s.io/controller-runtime"
-	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
@@ -51,36 +52,83 @@ func NewController(kubeClient client.Client, cloudProvider cloudprovider.CloudPr
 	}
 }
 
+// launchFailureThreshold is the number of NodeClaims that can be failing to launch with a NodeClass before the NodePool
+// falls back to the next of its NodeClasses
+const launchFailureThreshold = 3
+
 func (c *Controller) Reconcile(ctx context.Context, nodePool *v1.NodePool) (reconcile.Result, error) {
 	ctx = injection.WithControllerName(ctx, "nodepool.readiness")
 	stored := nodePool.DeepCopy()
 
-	nodeClass, ok := lo.Find(c.cloudProvider.GetSupportedNodeClasses(), func(nc status.Object) bool {
+	supported, ok := lo.Find(c.cloudProvider.GetSupportedNodeClasses(), func(nc status.Object) bool {
 		return object.GVK(nc).GroupKind() == nodePool.Spec.Template.Spec.NodeClassRef.GroupKind()
 	})
 	if !ok {
-		// Ignore NodePools which aren't using a supported NodeClass.
-		return reconcile.Result{}, nil
+		// NodePools which aren't using a supported NodeClass may be managed by another instance of Karpenter, unless no
+		// NodeClass of their group and kind is served by the cluster, in which case no instance of Karpenter can manage them
+		return c.validateNodeClassKind(ctx, nodePool)
 	}
 
-	err := c.kubeClient.Get(ctx, client.ObjectKey{Name: nodePool.Spec.Template.Spec.NodeClassRef.Name}, nodeClass)
-	if client.IgnoreNotFound(err) != nil {
-		return reconcile.Result{}, err
+	// New NodeClaims are launched with the first NodeClass that is ready and isn't repeatedly failing to launch. If every
+	// ready NodeClass is failing to launch, we stick with the first of them rather than blocking provisioning entirely.
+	var active, failing *v1.NodeClassReference
+	var notReady *readiness
+	for _, ref := range nodePool.Spec.Template.Spec.NodeClassRefs() {
+		r, err := c.readiness(ctx, nodePool, ref, supported.DeepCopyObject().(status.Object))
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if r.ready && !r.failing {
+			active = ref
+			break
+		}
+		if r.ready && failing == nil {
+			failing = ref
+		}
+		if !r.ready && notReady == nil {
+			notReady = &r
+		}
 	}
-	switch {
-	case errors.IsNotFound(err):
-		nodePool.StatusConditions().SetFalse(v1.ConditionTypeNodeClassReady, "NodeClassNotFound", "NodeClass not found on cluster")
-	case !nodeClass.GetDeletionTimestamp().IsZero():
-		nodePool.StatusConditions().SetFalse(v1.ConditionTypeNodeClassReady, "NodeClassTerminating", "NodeClass is Terminating")
-	default:
-		c.setReadyCondition(nodePool, nodeClass)
+	if active == nil {
+		active = failing
+	}
+	if active != nil {
+		nodePool.StatusConditions().SetTrue(v1.ConditionTypeNodeClassReady)
+		nodePool.Status.NodeClassRef = active
+	} else {
+		nodePool.StatusConditions().SetFalse(v1.ConditionTypeNodeClassReady, notReady.reason, notReady.message)
+		nodePool.Status.NodeClassRef = nil
+	}
+
+	if !equality.Semantic.DeepEqual(stored, nodePool) {
+		// We use client.MergeFromWithOptimisticLock because patching a list with a JSON merge patch
+		// can cause races due to the fact that it fully replaces the list on a change
+		// Here, we are updating the status condition list
+		if err := c.kubeClient.Status().Patch(ctx, nodePool, client.MergeFromWithOptions(stored, client.MergeFromWithOptimisticLock{})); client.IgnoreNotFound(err) != nil {
+			if errors.IsConflict(err) {
+				return reconcile.Result{Requeue: true}, nil
+			}
+			return reconcile.Result{}, err
+		}
 	}
+	return reconcile.Result{}, nil
+}
 
+// validateNodeClassKind marks the NodePool's NodeClass as not ready if the cluster doesn't serve a NodeClass of the group
+// and kind of its NodeClassRef, e.g. as the group or kind is misspelled
+func (c *Controller) validateNodeClassKind(ctx context.Context, nodePool *v1.NodePool) (reconcile.Result, error) {
+	groupKind := nodePool.Spec.Template.Spec.NodeClassRef.GroupKind()
+	if _, err := c.kubeClient.RESTMapper().RESTMapping(groupKind); !meta.IsNoMatchError(err) {
+		return reconcile.Result{}, err
+	}
+	stored := nodePool.DeepCopy()
+	nodePool.StatusConditions().SetFalse(v1.ConditionTypeNodeClassReady, "NodeClassKindNotFound", fmt.Sprintf("NodeClass kind %q is not served by the cluster", groupKind.String()))
+	nodePool.Status.NodeClassRef = nil
 	if !equality.Semantic.DeepEqual(stored, nodePool) {
 		// We use client.MergeFromWithOptimisticLock because patching a list with a JSON merge patch
 		// can cause races due to the fact that it fully replaces the list on a change
 		// Here, we are updating the status condition list
-		if err = c.kubeClient.Status().Patch(ctx, nodePool, client.MergeFromWithOptions(stored, client.MergeFromWithOptimisticLock{})); client.IgnoreNotFound(err) != nil {
+		if err := c.kubeClient.Status().Patch(ctx, nodePool, client.MergeFromWithOptions(stored, client.MergeFromWithOptimisticLock{})); client.IgnoreNotFound(err) != nil {
 			if errors.IsConflict(err) {
 				return reconcile.Result{Requeue: true}, nil
 			}
@@ -90,24 +138,55 @@ func (c *Controller) Reconcile(ctx context.Context, nodePool *v1.NodePool) (reco
 	return reconcile.Result{}, nil
 }
 
-func (c *Controller) setReadyCondition(nodePool *v1.NodePool, nodeClass status.Object) {
+type readiness struct {
+	ready   bool
+	reason  string
+	message string
+	// failing is true if the NodePool has too many NodeClaims that are failing to launch with the NodeClass
+	failing bool
+}
+
+func (c *Controller) readiness(ctx context.Context, nodePool *v1.NodePool, ref *v1.NodeClassReference, nodeClass status.Object) (readiness, error) {
+	err := c.kubeClient.Get(ctx, client.ObjectKey{Name: ref.Name}, nodeClass)
+	if client.IgnoreNotFound(err) != nil {
+		return readiness{}, err
+	}
+	switch {
+	case errors.IsNotFound(err):
+		return readiness{reason: "NodeClassNotFound", message: "NodeClass not found on cluster"}, nil
+	case !nodeClass.GetDeletionTimestamp().IsZero():
+		return readiness{reason: "NodeClassTerminating", message: "NodeClass is Terminating"}, nil
+	}
 	ready := nodeClass.StatusConditions().Get(status.ConditionReady)
 	if ready.IsUnknown() {
-		nodePool.StatusConditions().SetFalse(v1.ConditionTypeNodeClassReady, "NodeClassReadinessUnknown", "Node Class Readiness Unknown")
+		return readiness{reason: "NodeClassReadinessUnknown", message: "Node Class Readiness Unknown"}, nil
 	} else if ready.IsFalse() {
-		nodePool.StatusConditions().SetFalse(v1.ConditionTypeNodeClassReady, ready.Reason, ready.Message)
-	} else {
-		nodePool.StatusConditions().SetTrue(v1.ConditionTypeNodeClassReady)
+		return readiness{reason: ready.Reason, message: ready.Message}, nil
 	}
+	nodeClaims := &v1.NodeClaimList{}
+	if err = c.kubeClient.List(ctx, nodeClaims, client.MatchingLabels{
+		v1.NodePoolLabelKey:                   nodePool.Name,
+		v1.NodeClassLabelKey(ref.GroupKind()): ref.Name,
+	}); err != nil {
+		return readiness{}, err
+	}
+	failed := lo.CountBy(nodeClaims.Items, func(nc v1.NodeClaim) bool {
+		launched := nc.StatusConditions().Get(v1.ConditionTypeLaunched)
+		return launched.IsUnknown() && launched.Reason == v1.LaunchFailureReasonUnknown
+	})
+	return readiness{ready: true, failing: failed >= launchFailureThreshold}, nil
 }
 
 func (c *Controller) Register(_ context.Context, m manager.Manager) error {
 	b := controllerruntime.NewControllerManagedBy(m).
 		Named("nodepool.readiness").
-		For(&v1.NodePool{}, builder.WithPredicates(nodepoolutils.IsManagedPredicateFuncs(c.cloudProvider))).
+		// NodePools that aren't managed by this instance of Karpenter are reconciled to validate the kind of their NodeClass
+		For(&v1.NodePool{}).
 		WithOptions(controller.Options{MaxConcurrentReconciles: 10})
 	for _, nodeClass := range c.cloudProvider.GetSupportedNodeClasses() {
 		b.Watches(nodeClass, nodepoolutils.NodeClassEventHandler(c.kubeClient))
 	}
+	// NodeClaims are watched to fall back to another NodeClass when NodeClaims are failing to launch
+	b.Watches(&v1.NodeClaim{}, nodepoolutils.NodeClaimEventHandler())
 	return b.Complete(reconcile.AsReconciler(m.GetClient(), c))
 }
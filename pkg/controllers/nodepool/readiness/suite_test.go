@@ -91,6 +91,14 @@ var _ = Describe("Readiness", func() {
 		nodePool = ExpectExists(ctx, env.Client, nodePool)
 		Expect(nodePool.StatusConditions().Get(status.ConditionReady).IsFalse()).To(BeTrue())
 	})
+	It("should have status condition on nodePool as not ready when the nodeClass kind isn't served by the cluster", func() {
+		nodePool.Spec.Template.Spec.NodeClassRef.Kind = "MisspelledNodeClass"
+		ExpectApplied(ctx, env.Client, nodePool)
+		ExpectObjectReconciled(ctx, env.Client, controller, nodePool)
+		nodePool = ExpectExists(ctx, env.Client, nodePool)
+		Expect(nodePool.StatusConditions().Get(status.ConditionReady).IsFalse()).To(BeTrue())
+		Expect(nodePool.StatusConditions().Get(v1.ConditionTypeNodeClassReady).Reason).To(Equal("NodeClassKindNotFound"))
+	})
 	It("should have status condition on nodePool as ready if nodeClass is ready", func() {
 		ExpectApplied(ctx, env.Client, nodePool, nodeClass)
 		_ = ExpectObjectReconciled(ctx, env.Client, controller, nodePool)
@@ -131,4 +139,62 @@ var _ = Describe("Readiness", func() {
 		nodePool = ExpectExists(ctx, env.Client, nodePool)
 		Expect(nodePool.StatusConditions().Get(status.ConditionReady).IsFalse()).To(BeTrue())
 	})
+	Context("Fallback NodeClasses", func() {
+		var fallback *v1alpha1.TestNodeClass
+		BeforeEach(func() {
+			fallback = test.NodeClass()
+			nodePool.Spec.Template.Spec.FallbackNodeClassRefs = []v1.NodeClassReference{{
+				Group: object.GVK(fallback).Group,
+				Kind:  object.GVK(fallback).Kind,
+				Name:  fallback.Name,
+			}}
+		})
+		It("should launch NodeClaims with the NodeClassRef if it's ready", func() {
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass, fallback)
+			ExpectObjectReconciled(ctx, env.Client, controller, nodePool)
+			nodePool = ExpectExists(ctx, env.Client, nodePool)
+			Expect(nodePool.StatusConditions().Get(v1.ConditionTypeNodeClassReady).IsTrue()).To(BeTrue())
+			Expect(nodePool.Status.NodeClassRef.Name).To(Equal(nodeClass.Name))
+			Expect(nodePool.ActiveNodeClassRef().Name).To(Equal(nodeClass.Name))
+		})
+		It("should fall back to the next NodeClass if the NodeClassRef isn't ready", func() {
+			ExpectApplied(ctx, env.Client, nodePool, fallback)
+			ExpectObjectReconciled(ctx, env.Client, controller, nodePool)
+			nodePool = ExpectExists(ctx, env.Client, nodePool)
+			Expect(nodePool.StatusConditions().Get(v1.ConditionTypeNodeClassReady).IsTrue()).To(BeTrue())
+			Expect(nodePool.ActiveNodeClassRef().Name).To(Equal(fallback.Name))
+		})
+		It("should fall back to the next NodeClass if NodeClaims are repeatedly failing to launch with the NodeClassRef", func() {
+			ExpectApplied(ctx, env.Client, nodePool, nodeClass, fallback)
+			for range 3 {
+				nodeClaim := test.NodeClaim(v1.NodeClaim{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+					v1.NodePoolLabelKey: nodePool.Name,
+					v1.NodeClassLabelKey(nodePool.Spec.Template.Spec.NodeClassRef.GroupKind()): nodeClass.Name,
+				}}})
+				nodeClaim.StatusConditions().SetUnknownWithReason(v1.ConditionTypeLaunched, v1.LaunchFailureReasonUnknown, "launch failed")
+				ExpectApplied(ctx, env.Client, nodeClaim)
+			}
+			ExpectObjectReconciled(ctx, env.Client, controller, nodePool)
+			nodePool = ExpectExists(ctx, env.Client, nodePool)
+			Expect(nodePool.StatusConditions().Get(v1.ConditionTypeNodeClassReady).IsTrue()).To(BeTrue())
+			Expect(nodePool.ActiveNodeClassRef().Name).To(Equal(fallback.Name))
+		})
+		It("should resolve instance types against the fallback NodeClass once it's active", func() {
+			ExpectApplied(ctx, env.Client, nodePool, fallback)
+			ExpectObjectReconciled(ctx, env.Client, controller, nodePool)
+			nodePool = ExpectExists(ctx, env.Client, nodePool)
+			active := nodePool.WithActiveNodeClassRef()
+			Expect(active.Spec.Template.Spec.NodeClassRef.Name).To(Equal(fallback.Name))
+			// the NodePool itself is left as it is
+			Expect(nodePool.Spec.Template.Spec.NodeClassRef.Name).To(Equal(nodeClass.Name))
+		})
+		It("should report why the NodeClassRef isn't ready if none of the NodeClasses are ready", func() {
+			ExpectApplied(ctx, env.Client, nodePool)
+			ExpectObjectReconciled(ctx, env.Client, controller, nodePool)
+			nodePool = ExpectExists(ctx, env.Client, nodePool)
+			Expect(nodePool.StatusConditions().Get(v1.ConditionTypeNodeClassReady).IsFalse()).To(BeTrue())
+			Expect(nodePool.StatusConditions().Get(v1.ConditionTypeNodeClassReady).Reason).To(Equal("NodeClassNotFound"))
+			Expect(nodePool.Status.NodeClassRef).To(BeNil())
+		})
+	})
 })
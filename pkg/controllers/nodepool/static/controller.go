@@ -0,0 +1,173 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package static
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/awslabs/operatorpkg/status"
+	"github.com/samber/lo"
+	"k8s.io/klog/v2"
+	controllerruntime "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+	"sigs.k8s.io/karpenter/pkg/cloudprovider"
+	scheduler "sigs.k8s.io/karpenter/pkg/controllers/provisioning/scheduling"
+	"sigs.k8s.io/karpenter/pkg/controllers/state"
+	"sigs.k8s.io/karpenter/pkg/metrics"
+	"sigs.k8s.io/karpenter/pkg/operator/injection"
+	"sigs.k8s.io/karpenter/pkg/scheduling"
+	nodeclaimutils "sigs.k8s.io/karpenter/pkg/utils/nodeclaim"
+	nodepoolutils "sigs.k8s.io/karpenter/pkg/utils/nodepool"
+)
+
+// Controller maintains the desired number of NodeClaims for static NodePools
+type Controller struct {
+	kubeClient client.Client
+	// apiReader lists NodeClaims without the cache, which may not include the NodeClaims that were just launched for the
+	// NodePool and would cause them to be launched again
+	apiReader     client.Reader
+	cloudProvider cloudprovider.CloudProvider
+	cluster       *state.Cluster
+}
+
+// NewController is a constructor
+func NewController(kubeClient client.Client, apiReader client.Reader, cloudProvider cloudprovider.CloudProvider, cluster *state.Cluster) *Controller {
+	return &Controller{
+		kubeClient:    kubeClient,
+		apiReader:     apiReader,
+		cloudProvider: cloudProvider,
+		cluster:       cluster,
+	}
+}
+
+func (c *Controller) Reconcile(ctx context.Context, nodePool *v1.NodePool) (reconcile.Result, error) {
+	ctx = injection.WithControllerName(ctx, "nodepool.static")
+	if !nodepoolutils.IsManaged(ctx, nodePool, c.cloudProvider) || !nodePool.IsStatic() || nodePool.IsPaused() || !nodePool.DeletionTimestamp.IsZero() {
+		return reconcile.Result{}, nil
+	}
+	if !nodePool.StatusConditions().IsTrue(status.ConditionReady) {
+		return reconcile.Result{}, nil
+	}
+	nodeClaims, err := nodeclaimutils.ListManaged(ctx, c.apiReader, c.cloudProvider, nodeclaimutils.ForNodePool(nodePool.Name))
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("listing nodeclaims, %w", err)
+	}
+	nodeClaims = lo.Filter(nodeClaims, func(nc *v1.NodeClaim, _ int) bool { return nc.DeletionTimestamp.IsZero() })
+	// Drifted NodeClaims don't count towards the desired replicas so that their replacements are launched before
+	// the drifted NodeClaims are removed
+	drifted, current := lo.FilterReject(nodeClaims, func(nc *v1.NodeClaim, _ int) bool {
+		return nc.StatusConditions().Get(v1.ConditionTypeDrifted).IsTrue()
+	})
+	desired := int(lo.FromPtr(nodePool.Spec.Replicas))
+
+	if len(current) < desired {
+		if err = c.launch(ctx, nodePool, desired-len(current)); err != nil {
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{}, nil
+	}
+
+	var toDelete []*v1.NodeClaim
+	if len(current) > desired {
+		// Prefer removing NodeClaims that haven't initialized yet, and then the most recently created NodeClaims
+		sort.SliceStable(current, func(i, j int) bool {
+			iInitialized := current[i].StatusConditions().Get(v1.ConditionTypeInitialized).IsTrue()
+			jInitialized := current[j].StatusConditions().Get(v1.ConditionTypeInitialized).IsTrue()
+			if iInitialized != jInitialized {
+				return !iInitialized
+			}
+			return current[j].CreationTimestamp.Before(&current[i].CreationTimestamp)
+		})
+		toDelete = append(toDelete, current[:len(current)-desired]...)
+		current = current[len(current)-desired:]
+	}
+	if lo.CountBy(current, func(nc *v1.NodeClaim) bool { return nc.StatusConditions().Get(v1.ConditionTypeInitialized).IsTrue() }) >= desired {
+		toDelete = append(toDelete, drifted...)
+	}
+	for _, nodeClaim := range toDelete {
+		if err = c.kubeClient.Delete(ctx, nodeClaim); err != nil {
+			if client.IgnoreNotFound(err) != nil {
+				return reconcile.Result{}, fmt.Errorf("deleting nodeclaim, %w", err)
+			}
+			continue
+		}
+		reason := lo.Ternary(nodeClaim.StatusConditions().Get(v1.ConditionTypeDrifted).IsTrue(), strings.ToLower(string(v1.DisruptionReasonDrifted)), metrics.StaticReason)
+		log.FromContext(ctx).WithValues("NodeClaim", klog.KRef("", nodeClaim.Name), "reason", reason).Info("deleting nodeclaim for static nodepool")
+		metrics.NodeClaimsDisruptedTotal.Inc(map[string]string{
+			metrics.ReasonLabel:       reason,
+			metrics.NodePoolLabel:     nodePool.Name,
+			metrics.CapacityTypeLabel: nodeClaim.Labels[v1.CapacityTypeLabelKey],
+		})
+	}
+	return reconcile.Result{}, nil
+}
+
+// launch creates count NodeClaims from the NodePool's template, constrained to the instance types which are compatible
+// with the template's requirements
+func (c *Controller) launch(ctx context.Context, nodePool *v1.NodePool, count int) error {
+	if err := nodePool.Spec.Limits.ExceededBy(nodePool.Status.Resources); err != nil {
+		log.FromContext(ctx).WithValues("NodePool", klog.KRef("", nodePool.Name)).Error(err, "unable to launch nodeclaims for static nodepool")
+		return nil
+	}
+	instanceTypes, err := c.cloudProvider.GetInstanceTypes(ctx, nodePool.WithActiveNodeClassRef())
+	if err != nil {
+		return fmt.Errorf("getting instance types, %w", err)
+	}
+	// Account for the kubelet configuration of the NodePool in the resources of the instance types, the same as the
+	// provisioner does
+	instanceTypes = cloudprovider.InstanceTypes(instanceTypes).WithKubeletConfiguration(nodePool.Spec.Template.Spec.Kubelet)
+	nct := scheduler.NewNodeClaimTemplate(nodePool)
+	nct.InstanceTypeOptions = cloudprovider.InstanceTypes(lo.Filter(instanceTypes, func(it *cloudprovider.InstanceType, _ int) bool {
+		return nct.Requirements.IsCompatible(it.Requirements, scheduling.AllowUndefinedWellKnownLabels)
+	})).Compatible(nct.Requirements).AtLeast(nodePool.Spec.MinResources)
+	if len(nct.InstanceTypeOptions) == 0 {
+		return fmt.Errorf("no instance types satisfy the requirements of nodepool %q", nodePool.Name)
+	}
+	for range count {
+		nodeClaim := nct.ToNodeClaim()
+		if err = c.kubeClient.Create(ctx, nodeClaim); err != nil {
+			return fmt.Errorf("creating nodeclaim, %w", err)
+		}
+		log.FromContext(ctx).WithValues("NodeClaim", klog.KRef("", nodeClaim.Name)).Info("created nodeclaim for static nodepool")
+		metrics.NodeClaimsCreatedTotal.Inc(map[string]string{
+			metrics.ReasonLabel:       metrics.StaticReason,
+			metrics.NodePoolLabel:     nodePool.Name,
+			metrics.CapacityTypeLabel: nodeClaim.Labels[v1.CapacityTypeLabelKey],
+		})
+		c.cluster.UpdateNodeClaim(nodeClaim)
+	}
+	return nil
+}
+
+func (c *Controller) Register(ctx context.Context, m manager.Manager) error {
+	return controllerruntime.NewControllerManagedBy(m).
+		Named("nodepool.static").
+		For(&v1.NodePool{}, builder.WithPredicates(nodepoolutils.IsManagedPredicateFuncs(ctx, c.cloudProvider))).
+		Watches(&v1.NodeClaim{}, nodepoolutils.NodeClaimEventHandler()).
+		WithOptions(controller.Options{MaxConcurrentReconciles: 10}).
+		Complete(reconcile.AsReconciler(m.GetClient(), c))
+}
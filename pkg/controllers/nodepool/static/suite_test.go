@@ -0,0 +1,195 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package static_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clock "k8s.io/utils/clock/testing"
+
+	"sigs.k8s.io/karpenter/pkg/apis"
+	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+	"sigs.k8s.io/karpenter/pkg/cloudprovider/fake"
+	"sigs.k8s.io/karpenter/pkg/controllers/nodepool/static"
+	"sigs.k8s.io/karpenter/pkg/controllers/state"
+	"sigs.k8s.io/karpenter/pkg/operator/options"
+	"sigs.k8s.io/karpenter/pkg/test"
+	. "sigs.k8s.io/karpenter/pkg/test/expectations"
+	"sigs.k8s.io/karpenter/pkg/test/v1alpha1"
+	. "sigs.k8s.io/karpenter/pkg/utils/testing"
+)
+
+var (
+	controller    *static.Controller
+	ctx           context.Context
+	env           *test.Environment
+	cluster       *state.Cluster
+	fakeClock     *clock.FakeClock
+	cloudProvider *fake.CloudProvider
+	nodePool      *v1.NodePool
+)
+
+func TestAPIs(t *testing.T) {
+	ctx = TestContextWithLogger(t)
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Static")
+}
+
+var _ = BeforeSuite(func() {
+	ctx = options.ToContext(ctx, test.Options())
+	cloudProvider = fake.NewCloudProvider()
+	env = test.NewEnvironment(test.WithCRDs(apis.CRDs...), test.WithCRDs(v1alpha1.CRDs...))
+	fakeClock = clock.NewFakeClock(time.Now())
+	cluster = state.NewCluster(fakeClock, env.Client, cloudProvider)
+	controller = static.NewController(env.Client, env.Client, cloudProvider, cluster)
+})
+
+var _ = AfterEach(func() {
+	ExpectCleanedUp(ctx, env.Client)
+	cluster.Reset()
+})
+
+var _ = AfterSuite(func() {
+	Expect(env.Stop()).To(Succeed(), "Failed to stop environment")
+})
+
+var _ = Describe("Static", func() {
+	BeforeEach(func() {
+		nodePool = test.NodePool(v1.NodePool{Spec: v1.NodePoolSpec{Replicas: lo.ToPtr[int64](3)}})
+	})
+	It("should launch NodeClaims up to the desired replicas", func() {
+		ExpectApplied(ctx, env.Client, nodePool)
+		ExpectObjectReconciled(ctx, env.Client, controller, nodePool)
+
+		nodeClaims := ExpectNodeClaims(ctx, env.Client)
+		Expect(nodeClaims).To(HaveLen(3))
+		for _, nc := range nodeClaims {
+			Expect(nc.Labels).To(HaveKeyWithValue(v1.NodePoolLabelKey, nodePool.Name))
+			Expect(nc.OwnerReferences).To(ContainElement(HaveField("UID", nodePool.UID)))
+		}
+	})
+	It("should not launch NodeClaims once the desired replicas exist", func() {
+		ExpectApplied(ctx, env.Client, nodePool)
+		ExpectObjectReconciled(ctx, env.Client, controller, nodePool)
+		ExpectObjectReconciled(ctx, env.Client, controller, nodePool)
+		Expect(ExpectNodeClaims(ctx, env.Client)).To(HaveLen(3))
+	})
+	It("should only launch instance types that satisfy minResources", func() {
+		nodePool.Spec.MinResources = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("8")}
+		ExpectApplied(ctx, env.Client, nodePool)
+		ExpectObjectReconciled(ctx, env.Client, controller, nodePool)
+
+		nodeClaims := ExpectNodeClaims(ctx, env.Client)
+		Expect(nodeClaims).To(HaveLen(3))
+		for _, nc := range nodeClaims {
+			requirement, ok := lo.Find(nc.Spec.Requirements, func(r v1.NodeSelectorRequirementWithMinValues) bool {
+				return r.Key == corev1.LabelInstanceTypeStable
+			})
+			Expect(ok).To(BeTrue())
+			Expect(requirement.Values).To(ConsistOf("arm-instance-type"))
+		}
+	})
+	It("should account for the kubelet configuration when selecting instance types that satisfy minResources", func() {
+		nodePool.Spec.MinResources = corev1.ResourceList{corev1.ResourcePods: resource.MustParse("10")}
+		nodePool.Spec.Template.Spec.Kubelet = &v1.KubeletConfiguration{MaxPods: lo.ToPtr[int32](20)}
+		ExpectApplied(ctx, env.Client, nodePool)
+		ExpectObjectReconciled(ctx, env.Client, controller, nodePool)
+
+		nodeClaims := ExpectNodeClaims(ctx, env.Client)
+		Expect(nodeClaims).To(HaveLen(3))
+		for _, nc := range nodeClaims {
+			requirement, ok := lo.Find(nc.Spec.Requirements, func(r v1.NodeSelectorRequirementWithMinValues) bool {
+				return r.Key == corev1.LabelInstanceTypeStable
+			})
+			Expect(ok).To(BeTrue())
+			Expect(requirement.Values).To(ContainElement("single-pod-instance-type"))
+		}
+	})
+	It("should ignore NodePools without replicas", func() {
+		nodePool.Spec.Replicas = nil
+		ExpectApplied(ctx, env.Client, nodePool)
+		ExpectObjectReconciled(ctx, env.Client, controller, nodePool)
+		Expect(ExpectNodeClaims(ctx, env.Client)).To(HaveLen(0))
+	})
+	It("should ignore NodePools that aren't ready", func() {
+		nodePool.StatusConditions().SetFalse(v1.ConditionTypeNodeClassReady, "NodeClassNotReady", "NodeClass not ready")
+		ExpectApplied(ctx, env.Client, nodePool)
+		ExpectObjectReconciled(ctx, env.Client, controller, nodePool)
+		Expect(ExpectNodeClaims(ctx, env.Client)).To(HaveLen(0))
+	})
+	It("should remove uninitialized NodeClaims first when scaling down", func() {
+		ExpectApplied(ctx, env.Client, nodePool)
+		ExpectObjectReconciled(ctx, env.Client, controller, nodePool)
+		nodeClaims := ExpectNodeClaims(ctx, env.Client)
+		ExpectMakeNodeClaimsInitialized(ctx, env.Client, nodeClaims[0])
+
+		nodePool.Spec.Replicas = lo.ToPtr[int64](1)
+		ExpectApplied(ctx, env.Client, nodePool)
+		ExpectObjectReconciled(ctx, env.Client, controller, nodePool)
+
+		remaining := ExpectNodeClaims(ctx, env.Client)
+		Expect(remaining).To(HaveLen(1))
+		Expect(remaining[0].Name).To(Equal(nodeClaims[0].Name))
+	})
+	It("should launch a replacement before removing a drifted NodeClaim", func() {
+		nodePool.Spec.Replicas = lo.ToPtr[int64](1)
+		ExpectApplied(ctx, env.Client, nodePool)
+		ExpectObjectReconciled(ctx, env.Client, controller, nodePool)
+		nodeClaims := ExpectNodeClaims(ctx, env.Client)
+		Expect(nodeClaims).To(HaveLen(1))
+		drifted := nodeClaims[0]
+		drifted.StatusConditions().SetTrue(v1.ConditionTypeDrifted)
+		ExpectApplied(ctx, env.Client, drifted)
+
+		// The replacement is launched while the drifted NodeClaim is kept around
+		ExpectObjectReconciled(ctx, env.Client, controller, nodePool)
+		nodeClaims = ExpectNodeClaims(ctx, env.Client)
+		Expect(nodeClaims).To(HaveLen(2))
+		replacement, ok := lo.Find(nodeClaims, func(nc *v1.NodeClaim) bool { return nc.Name != drifted.Name })
+		Expect(ok).To(BeTrue())
+
+		ExpectObjectReconciled(ctx, env.Client, controller, nodePool)
+		Expect(ExpectNodeClaims(ctx, env.Client)).To(HaveLen(2))
+
+		// Once the replacement has initialized, the drifted NodeClaim is removed
+		ExpectMakeNodeClaimsInitialized(ctx, env.Client, replacement)
+		ExpectObjectReconciled(ctx, env.Client, controller, nodePool)
+		nodeClaims = ExpectNodeClaims(ctx, env.Client)
+		Expect(nodeClaims).To(HaveLen(1))
+		Expect(nodeClaims[0].Name).To(Equal(replacement.Name))
+	})
+	It("should not count deleting NodeClaims towards the desired replicas", func() {
+		nodeClaim := test.NodeClaim(v1.NodeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels:     map[string]string{v1.NodePoolLabelKey: nodePool.Name},
+				Finalizers: []string{v1.TerminationFinalizer},
+			},
+		})
+		ExpectApplied(ctx, env.Client, nodePool, nodeClaim)
+		Expect(env.Client.Delete(ctx, nodeClaim)).To(Succeed())
+		ExpectObjectReconciled(ctx, env.Client, controller, nodePool)
+		Expect(ExpectNodeClaims(ctx, env.Client)).To(HaveLen(4))
+	})
+})
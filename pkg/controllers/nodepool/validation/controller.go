@@ -18,22 +18,32 @@ package validation
 
 import (
 	"context"
+	"fmt"
+	"time"
 
+	"github.com/samber/lo"
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
 	controllerruntime "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
 	"sigs.k8s.io/karpenter/pkg/cloudprovider"
 	"sigs.k8s.io/karpenter/pkg/operator/injection"
+	"sigs.k8s.io/karpenter/pkg/scheduling"
 	nodepoolutils "sigs.k8s.io/karpenter/pkg/utils/nodepool"
 )
 
+// offeringValidationPeriod is how often the offerings are validated again, as the cloud provider's offerings can
+// change without the NodePool or its NodeClass changing
+const offeringValidationPeriod = 5 * time.Minute
+
 // Controller for the resource
 type Controller struct {
 	kubeClient    client.Client
@@ -50,15 +60,20 @@ func NewController(kubeClient client.Client, cloudProvider cloudprovider.CloudPr
 
 func (c *Controller) Reconcile(ctx context.Context, nodePool *v1.NodePool) (reconcile.Result, error) {
 	ctx = injection.WithControllerName(ctx, "nodepool.validation")
-	if !nodepoolutils.IsManaged(nodePool, c.cloudProvider) {
+	if !nodepoolutils.IsManaged(ctx, nodePool, c.cloudProvider) {
 		return reconcile.Result{}, nil
 	}
 	stored := nodePool.DeepCopy()
-	err := nodePool.RuntimeValidate()
-	if err != nil {
+	result := reconcile.Result{}
+	if err := nodePool.RuntimeValidate(); err != nil {
 		nodePool.StatusConditions().SetFalse(v1.ConditionTypeValidationSucceeded, "NodePoolValidationFailed", err.Error())
 	} else {
-		nodePool.StatusConditions().SetTrue(v1.ConditionTypeValidationSucceeded)
+		result.RequeueAfter = offeringValidationPeriod
+		if err := c.validateOfferings(ctx, nodePool); err != nil {
+			nodePool.StatusConditions().SetFalse(v1.ConditionTypeValidationSucceeded, "NoCompatibleOfferings", err.Error())
+		} else {
+			nodePool.StatusConditions().SetTrue(v1.ConditionTypeValidationSucceeded)
+		}
 	}
 	if !equality.Semantic.DeepEqual(stored, nodePool) {
 		// We use client.MergeFromWithOptimisticLock because patching a list with a JSON merge patch
@@ -71,13 +86,37 @@ func (c *Controller) Reconcile(ctx context.Context, nodePool *v1.NodePool) (reco
 			return reconcile.Result{}, e
 		}
 	}
-	return reconcile.Result{}, nil
+	return result, nil
 }
 
-func (c *Controller) Register(_ context.Context, m manager.Manager) error {
-	return controllerruntime.NewControllerManagedBy(m).
+// validateOfferings returns an error if none of the cloud provider's instance types have an offering that is compatible
+// with the NodePool's requirements, as the NodePool could never launch a node. All offerings are considered, not just the
+// available ones, so that offerings that are temporarily unavailable don't fail the validation. Instance types that
+// can't be resolved, e.g. as the NodeClass isn't ready yet, don't fail the validation either.
+func (c *Controller) validateOfferings(ctx context.Context, nodePool *v1.NodePool) error {
+	instanceTypes, err := c.cloudProvider.GetInstanceTypes(ctx, nodePool.WithActiveNodeClassRef())
+	if err != nil {
+		log.FromContext(ctx).WithValues("NodePool", klog.KRef("", nodePool.Name)).V(1).Info(fmt.Sprintf("skipping offering validation, unable to resolve instance types, %s", err))
+		return nil
+	}
+	requirements := scheduling.NewNodeSelectorRequirementsWithMinValues(nodePool.Spec.Template.Spec.Requirements...)
+	requirements.Add(scheduling.NewLabelRequirements(nodePool.Spec.Template.Labels).Values()...)
+	if lo.ContainsBy(instanceTypes, func(it *cloudprovider.InstanceType) bool {
+		return it.Requirements.IsCompatible(requirements, scheduling.AllowUndefinedWellKnownLabels) && it.Offerings.HasCompatible(requirements)
+	}) {
+		return nil
+	}
+	return fmt.Errorf("no instance type offerings are compatible with the requirements of the nodepool, %d instance types were considered", len(instanceTypes))
+}
+
+func (c *Controller) Register(ctx context.Context, m manager.Manager) error {
+	b := controllerruntime.NewControllerManagedBy(m).
 		Named("nodepool.validation").
-		For(&v1.NodePool{}, builder.WithPredicates(nodepoolutils.IsManagedPredicateFuncs(c.cloudProvider))).
-		WithOptions(controller.Options{MaxConcurrentReconciles: 10}).
-		Complete(reconcile.AsReconciler(m.GetClient(), c))
+		For(&v1.NodePool{}, builder.WithPredicates(nodepoolutils.IsManagedPredicateFuncs(ctx, c.cloudProvider))).
+		WithOptions(controller.Options{MaxConcurrentReconciles: 10})
+	// NodeClasses are watched as the instance types, and so the offerings, that a NodePool resolves depend on its NodeClass
+	for _, nodeClass := range c.cloudProvider.GetSupportedNodeClasses() {
+		b.Watches(nodeClass, nodepoolutils.NodeClassEventHandler(c.kubeClient))
+	}
+	return b.Complete(reconcile.AsReconciler(m.GetClient(), c))
 }
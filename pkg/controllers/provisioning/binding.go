@@ -0,0 +1,173 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provisioning
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/samber/lo"
+	"go.uber.org/multierr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+	controllerruntime "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+	"sigs.k8s.io/karpenter/pkg/cloudprovider"
+	scheduler "sigs.k8s.io/karpenter/pkg/controllers/provisioning/scheduling"
+	"sigs.k8s.io/karpenter/pkg/controllers/state"
+	"sigs.k8s.io/karpenter/pkg/events"
+	"sigs.k8s.io/karpenter/pkg/operator/injection"
+	"sigs.k8s.io/karpenter/pkg/scheduling"
+	nodeclaimutils "sigs.k8s.io/karpenter/pkg/utils/nodeclaim"
+	"sigs.k8s.io/karpenter/pkg/utils/pod"
+	"sigs.k8s.io/karpenter/pkg/utils/resources"
+	"sigs.k8s.io/karpenter/pkg/utils/volume"
+)
+
+// BindingController binds pending pods to the node that was launched for them as soon as the node initializes.
+// Otherwise, the kube-scheduler may bind other pods to the node first, leaving the pods that the node was launched for
+// pending.
+type BindingController struct {
+	kubeClient    client.Client
+	cloudProvider cloudprovider.CloudProvider
+	cluster       *state.Cluster
+	recorder      events.Recorder
+}
+
+// NewBindingController constructs a controller instance
+func NewBindingController(kubeClient client.Client, cloudProvider cloudprovider.CloudProvider, cluster *state.Cluster, recorder events.Recorder) *BindingController {
+	return &BindingController{
+		kubeClient:    kubeClient,
+		cloudProvider: cloudProvider,
+		cluster:       cluster,
+		recorder:      recorder,
+	}
+}
+
+// Reconcile the resource
+func (c *BindingController) Reconcile(ctx context.Context, nodeClaim *v1.NodeClaim) (reconcile.Result, error) {
+	ctx = injection.WithControllerName(ctx, "provisioner.binding")
+
+	podKeys := c.cluster.PreBoundPods(nodeClaim.Name)
+	// Pods are only bound once the node is initialized, so that its startup taints have been removed and its resources
+	// have been registered
+	if len(podKeys) == 0 || !nodeClaim.StatusConditions().Get(v1.ConditionTypeInitialized).IsTrue() {
+		return reconcile.Result{}, nil
+	}
+	node := &corev1.Node{}
+	if err := c.kubeClient.Get(ctx, types.NamespacedName{Name: nodeClaim.Status.NodeName}, node); err != nil {
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+	ctx = log.IntoContext(ctx, log.FromContext(ctx).WithValues("Node", klog.KObj(node)))
+	pods := make([]*corev1.Pod, len(podKeys))
+	errs := make([]error, len(podKeys))
+	workqueue.ParallelizeUntil(ctx, 10, len(podKeys), func(i int) {
+		pods[i], errs[i] = c.bindablePod(ctx, podKeys[i], node)
+	})
+	if err := multierr.Combine(errs...); err != nil {
+		return reconcile.Result{}, err
+	}
+	pods = c.fit(node, lo.Compact(pods))
+	errs = make([]error, len(pods))
+	workqueue.ParallelizeUntil(ctx, 10, len(pods), func(i int) {
+		errs[i] = c.bind(ctx, pods[i], node)
+	})
+	if err := multierr.Combine(errs...); err != nil {
+		return reconcile.Result{}, err
+	}
+	c.cluster.ClearPreBoundPods(nodeClaim.Name)
+	return reconcile.Result{}, nil
+}
+
+// bindablePod returns the pod if it's still pending and can be bound to the node by Karpenter, or nil if it should be
+// left for the kube-scheduler. Pods that don't tolerate the taints of the node would be rejected or evicted once
+// they're bound, and pods with unbound claims that wait for their first consumer need the kube-scheduler to bind them.
+func (c *BindingController) bindablePod(ctx context.Context, podKey types.NamespacedName, node *corev1.Node) (*corev1.Pod, error) {
+	p := &corev1.Pod{}
+	if err := c.kubeClient.Get(ctx, podKey, p); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	if !pod.IsProvisionable(p) {
+		return nil, nil
+	}
+	if err := scheduling.Taints(node.Spec.Taints).Tolerates(p); err != nil {
+		return nil, nil
+	}
+	delayed, err := volume.HasUnboundDelayedClaims(ctx, c.kubeClient, p)
+	if err != nil {
+		return nil, fmt.Errorf("checking volume binding, %w", err)
+	}
+	if delayed {
+		return nil, nil
+	}
+	return p, nil
+}
+
+// fit returns the pods that fit within the resources of the node that aren't requested by the pods that are already
+// bound to it, in order. Pods that don't fit are left for the kube-scheduler.
+func (c *BindingController) fit(node *corev1.Node, pods []*corev1.Pod) []*corev1.Pod {
+	stateNode, ok := lo.Find(c.cluster.Nodes(), func(n *state.StateNode) bool { return n.Node != nil && n.Node.Name == node.Name })
+	if !ok {
+		return nil
+	}
+	available := stateNode.Available()
+	return lo.Filter(pods, func(p *corev1.Pod, _ int) bool {
+		requests := resources.RequestsForPods(p)
+		if !resources.Fits(requests, available) {
+			return false
+		}
+		available = resources.Subtract(available, requests)
+		return true
+	})
+}
+
+// bind binds the pod to the node
+func (c *BindingController) bind(ctx context.Context, p *corev1.Pod, node *corev1.Node) error {
+	binding := &corev1.Binding{
+		ObjectMeta: metav1.ObjectMeta{Name: p.Name, Namespace: p.Namespace, UID: p.UID},
+		Target:     corev1.ObjectReference{Kind: "Node", Name: node.Name},
+	}
+	if err := c.kubeClient.SubResource("binding").Create(ctx, p, binding); err != nil {
+		// the pod was bound or deleted since we read it
+		if apierrors.IsConflict(err) || apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("binding pod, %w", err)
+	}
+	log.FromContext(ctx).WithValues("Pod", klog.KObj(p)).V(1).Info("bound pod")
+	c.recorder.Publish(scheduler.PodPreBoundEvent(p, node))
+	return nil
+}
+
+func (c *BindingController) Register(ctx context.Context, m manager.Manager) error {
+	return controllerruntime.NewControllerManagedBy(m).
+		Named("provisioner.binding").
+		For(&v1.NodeClaim{}, builder.WithPredicates(nodeclaimutils.IsManagedPredicateFuncs(ctx, c.cloudProvider))).
+		WithOptions(controller.Options{MaxConcurrentReconciles: 10}).
+		Complete(reconcile.AsReconciler(m.GetClient(), c))
+}
@@ -22,15 +22,22 @@ import (
 
 	"github.com/samber/lo"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
 	controllerruntime "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+	"sigs.k8s.io/karpenter/pkg/cloudprovider"
 	"sigs.k8s.io/karpenter/pkg/controllers/state"
 	"sigs.k8s.io/karpenter/pkg/operator/injection"
+	nodepoolutils "sigs.k8s.io/karpenter/pkg/utils/nodepool"
 	"sigs.k8s.io/karpenter/pkg/utils/pod"
 )
 
@@ -117,3 +124,65 @@ func (c *NodeController) Register(_ context.Context, m manager.Manager) error {
 		WithOptions(controller.Options{MaxConcurrentReconciles: 10}).
 		Complete(reconcile.AsReconciler(m.GetClient(), c))
 }
+
+// NodePoolController for the resource
+type NodePoolController struct {
+	kubeClient    client.Client
+	cloudProvider cloudprovider.CloudProvider
+	provisioner   *Provisioner
+}
+
+// NewNodePoolController constructs a controller instance
+func NewNodePoolController(kubeClient client.Client, cloudProvider cloudprovider.CloudProvider, provisioner *Provisioner) *NodePoolController {
+	return &NodePoolController{
+		kubeClient:    kubeClient,
+		cloudProvider: cloudProvider,
+		provisioner:   provisioner,
+	}
+}
+
+// Reconcile the resource
+func (c *NodePoolController) Reconcile(ctx context.Context, np *v1.NodePool) (reconcile.Result, error) {
+	ctx = injection.WithControllerName(ctx, "provisioner.trigger.nodepool") //nolint:ineffassign,staticcheck
+
+	if (len(np.Spec.Headroom) == 0 && np.Spec.MinNodes == nil && np.Spec.MinNodesPerZone == nil) || np.IsStatic() || np.IsPaused() || !np.DeletionTimestamp.IsZero() {
+		return reconcile.Result{}, nil
+	}
+	c.provisioner.Trigger(np.UID)
+	return reconcile.Result{}, nil
+}
+
+// Register watches the NodeClaims of the NodePools and the pods that bind to their nodes, as pods binding to the nodes
+// from a NodePool consume its headroom and its NodeClaims can be removed without changing the NodePool, so the headroom
+// and the minimum nodes need to be checked again when either changes
+func (c *NodePoolController) Register(ctx context.Context, m manager.Manager) error {
+	return controllerruntime.NewControllerManagedBy(m).
+		Named("provisioner.trigger.nodepool").
+		For(&v1.NodePool{}, builder.WithPredicates(nodepoolutils.IsManagedPredicateFuncs(ctx, c.cloudProvider))).
+		Watches(&v1.NodeClaim{}, nodepoolutils.NodeClaimEventHandler()).
+		Watches(&corev1.Pod{}, c.podBindingEventHandler(), builder.WithPredicates(predicate.Funcs{
+			CreateFunc: func(e event.CreateEvent) bool { return e.Object.(*corev1.Pod).Spec.NodeName != "" },
+			UpdateFunc: func(e event.UpdateEvent) bool {
+				return e.ObjectOld.(*corev1.Pod).Spec.NodeName != e.ObjectNew.(*corev1.Pod).Spec.NodeName
+			},
+			DeleteFunc:  func(event.DeleteEvent) bool { return false },
+			GenericFunc: func(event.GenericEvent) bool { return false },
+		})).
+		WithOptions(controller.Options{MaxConcurrentReconciles: 10}).
+		Complete(reconcile.AsReconciler(m.GetClient(), c))
+}
+
+// podBindingEventHandler enqueues the NodePool of the node that a pod is bound to
+func (c *NodePoolController) podBindingEventHandler() handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, o client.Object) []reconcile.Request {
+		node := &corev1.Node{}
+		if err := c.kubeClient.Get(ctx, types.NamespacedName{Name: o.(*corev1.Pod).Spec.NodeName}, node); err != nil {
+			return nil
+		}
+		name, ok := node.Labels[v1.NodePoolLabelKey]
+		if !ok {
+			return nil
+		}
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: name}}}
+	})
+}
@@ -0,0 +1,116 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/awslabs/operatorpkg/object"
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+	scheduler "sigs.k8s.io/karpenter/pkg/controllers/provisioning/scheduling"
+	"sigs.k8s.io/karpenter/pkg/operator/injection"
+)
+
+// DryRunPath is the path on the metrics server that the dry-run provisioning endpoint is served on
+const DryRunPath = "/provisioning/dry-run"
+
+// DryRunResponse describes the NodeClaims that the provisioner would launch for the current pending pods
+type DryRunResponse struct {
+	NodeClaims []DryRunNodeClaim `json:"nodeClaims"`
+	// PodErrors are the reasons that pods couldn't be scheduled, keyed by the pod's namespace/name
+	PodErrors map[string]string `json:"podErrors,omitempty"`
+}
+
+type DryRunNodeClaim struct {
+	NodeClaim *v1.NodeClaim `json:"nodeClaim"`
+	// Pods are the namespace/names of the pods that would schedule to the NodeClaim
+	Pods []string `json:"pods"`
+}
+
+// DryRunHandler runs the provisioner for the current pending pods and returns the planned NodeClaims without creating
+// them, e.g. for policy checks in CI or capacity review.
+type DryRunHandler struct {
+	// ctx is the operator's context, which carries the options and logger that the provisioner depends on
+	ctx         context.Context
+	provisioner *Provisioner
+}
+
+func NewDryRunHandler(ctx context.Context, provisioner *Provisioner) *DryRunHandler {
+	return &DryRunHandler{ctx: ctx, provisioner: provisioner}
+}
+
+func (h *DryRunHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	ctx, cancel := context.WithCancel(injection.WithControllerName(h.ctx, "provisioner.dryrun"))
+	defer cancel()
+	defer context.AfterFunc(r.Context(), cancel)()
+
+	if !h.provisioner.cluster.Synced(ctx) {
+		http.Error(w, "waiting on cluster sync", http.StatusServiceUnavailable)
+		return
+	}
+	results, err := h.provisioner.DryRun(ctx)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "failed dry-run provisioning")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(NewDryRunResponse(results)); err != nil {
+		log.FromContext(ctx).Error(err, "failed writing dry-run provisioning response")
+	}
+}
+
+// NewDryRunResponse converts the scheduling results into the NodeClaims that would be created. Headroom pods only
+// exist in memory, so they aren't reported.
+func NewDryRunResponse(results scheduler.Results) DryRunResponse {
+	response := DryRunResponse{NodeClaims: []DryRunNodeClaim{}}
+	for _, n := range results.NewNodeClaims {
+		nodeClaim := n.ToNodeClaim()
+		nodeClaim.SetGroupVersionKind(object.GVK(nodeClaim))
+		response.NodeClaims = append(response.NodeClaims, DryRunNodeClaim{
+			NodeClaim: nodeClaim,
+			Pods:      podNames(n.Pods),
+		})
+	}
+	for p, err := range results.PodErrors {
+		if scheduler.IsHeadroomPod(p) {
+			continue
+		}
+		if response.PodErrors == nil {
+			response.PodErrors = map[string]string{}
+		}
+		response.PodErrors[klog.KObj(p).String()] = err.Error()
+	}
+	return response
+}
+
+func podNames(pods []*corev1.Pod) []string {
+	return lo.FilterMap(pods, func(p *corev1.Pod, _ int) (string, bool) {
+		return klog.KObj(p).String(), !scheduler.IsHeadroomPod(p)
+	})
+}
@@ -0,0 +1,92 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provisioning
+
+import (
+	"sync"
+	"time"
+
+	"github.com/samber/lo"
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+)
+
+// launchRateLimiters tracks a token bucket for each NodePool with a launch rate, which limits the number of NodeClaims
+// that are launched for the NodePool over time
+type launchRateLimiters struct {
+	mu       sync.Mutex
+	limiters map[types.UID]*launchRateLimiter
+}
+
+type launchRateLimiter struct {
+	launchRate v1.LaunchRate
+	*rate.Limiter
+}
+
+func newLaunchRateLimiters() *launchRateLimiters {
+	return &launchRateLimiters{limiters: map[types.UID]*launchRateLimiter{}}
+}
+
+// Reserve takes a token from the NodePool's bucket if a NodeClaim can be launched for the NodePool at the given time,
+// returning a function that refunds the token if the NodeClaim isn't launched after all. The bucket is recreated if the
+// launch rate of the NodePool changes.
+func (l *launchRateLimiters) Reserve(nodePool *v1.NodePool, now time.Time) (refund func(), ok bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if nodePool.Spec.LaunchRate == nil {
+		delete(l.limiters, nodePool.UID)
+		return func() {}, true
+	}
+	limiter, ok := l.limiters[nodePool.UID]
+	if !ok || limiter.launchRate.NodeClaimsPerMinute != nodePool.Spec.LaunchRate.NodeClaimsPerMinute ||
+		lo.FromPtr(limiter.launchRate.Burst) != lo.FromPtr(nodePool.Spec.LaunchRate.Burst) {
+		limiter = &launchRateLimiter{
+			launchRate: *nodePool.Spec.LaunchRate.DeepCopy(),
+			Limiter: rate.NewLimiter(rate.Limit(float64(nodePool.Spec.LaunchRate.NodeClaimsPerMinute)/time.Minute.Seconds()),
+				int(lo.FromPtrOr(nodePool.Spec.LaunchRate.Burst, nodePool.Spec.LaunchRate.NodeClaimsPerMinute))),
+		}
+		l.limiters[nodePool.UID] = limiter
+	}
+	reservation := limiter.ReserveN(now, 1)
+	if !reservation.OK() {
+		return nil, false
+	}
+	// the token is only available later, so hand it back rather than waiting for it
+	if reservation.DelayFrom(now) > 0 {
+		reservation.CancelAt(now)
+		return nil, false
+	}
+	// cancelling at the time of the reservation restores the token to the bucket
+	return func() { reservation.CancelAt(now) }, true
+}
+
+// Prune removes the buckets of NodePools that no longer exist
+func (l *launchRateLimiters) Prune(nodePools []*v1.NodePool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	uids := sets.New(lo.Map(nodePools, func(np *v1.NodePool, _ int) types.UID { return np.UID })...)
+	for uid := range l.limiters {
+		if !uids.Has(uid) {
+			delete(l.limiters, uid)
+		}
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"strings"
 	"time"
 
@@ -30,8 +31,12 @@ import (
 	"go.uber.org/multierr"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/clock"
@@ -48,16 +53,20 @@ import (
 	"sigs.k8s.io/karpenter/pkg/events"
 	"sigs.k8s.io/karpenter/pkg/metrics"
 	"sigs.k8s.io/karpenter/pkg/operator/injection"
+	"sigs.k8s.io/karpenter/pkg/operator/options"
 	"sigs.k8s.io/karpenter/pkg/scheduling"
 	nodeutils "sigs.k8s.io/karpenter/pkg/utils/node"
 	nodepoolutils "sigs.k8s.io/karpenter/pkg/utils/nodepool"
+	podutils "sigs.k8s.io/karpenter/pkg/utils/pod"
 	"sigs.k8s.io/karpenter/pkg/utils/pretty"
+	"sigs.k8s.io/karpenter/pkg/utils/resources"
 )
 
 // LaunchOptions are the set of options that can be used to trigger certain
 // actions and configuration during scheduling
 type LaunchOptions struct {
 	RecordPodNomination bool
+	PreBindPods         bool
 	Reason              string
 }
 
@@ -66,6 +75,11 @@ func RecordPodNomination(o *LaunchOptions) {
 	o.RecordPodNomination = true
 }
 
+// PreBindPods causes the pods to be bound to the node by Karpenter once it initializes, rather than by the kube-scheduler.
+func PreBindPods(o *LaunchOptions) {
+	o.PreBindPods = true
+}
+
 func WithReason(reason string) func(*LaunchOptions) {
 	return func(o *LaunchOptions) { o.Reason = reason }
 }
@@ -80,6 +94,8 @@ type Provisioner struct {
 	recorder       events.Recorder
 	cm             *pretty.ChangeMonitor
 	clock          clock.Clock
+	launchRates    *launchRateLimiters
+	extenderClient *http.Client
 }
 
 func NewProvisioner(kubeClient client.Client, recorder events.Recorder,
@@ -95,6 +111,8 @@ func NewProvisioner(kubeClient client.Client, recorder events.Recorder,
 		recorder:       recorder,
 		cm:             pretty.NewChangeMonitor(),
 		clock:          clock,
+		launchRates:    newLaunchRateLimiters(),
+		extenderClient: &http.Client{Timeout: scheduler.ExtenderTimeout},
 	}
 	return p
 }
@@ -133,7 +151,11 @@ func (p *Provisioner) Reconcile(ctx context.Context) (result reconcile.Result, e
 	if len(results.NewNodeClaims) == 0 {
 		return reconcile.Result{RequeueAfter: singleton.RequeueImmediately}, nil
 	}
-	if _, err = p.CreateNodeClaims(ctx, results.NewNodeClaims, WithReason(metrics.ProvisionedReason), RecordPodNomination); err != nil {
+	launchOpts := []option.Function[LaunchOptions]{WithReason(metrics.ProvisionedReason), RecordPodNomination}
+	if options.FromContext(ctx).FeatureGates.PodPreBinding {
+		launchOpts = append(launchOpts, PreBindPods)
+	}
+	if _, err = p.CreateNodeClaims(ctx, results.NewNodeClaims, launchOpts...); err != nil {
 		return reconcile.Result{}, err
 	}
 	return reconcile.Result{RequeueAfter: singleton.RequeueImmediately}, nil
@@ -159,10 +181,14 @@ func (p *Provisioner) CreateNodeClaims(ctx context.Context, nodeClaims []*schedu
 func (p *Provisioner) GetPendingPods(ctx context.Context) ([]*corev1.Pod, error) {
 	// filter for provisionable pods first, so we don't check for validity/PVCs on pods we won't provision anyway
 	// (e.g. those owned by daemonsets)
-	pods, err := nodeutils.GetProvisionablePods(ctx, p.kubeClient)
+	pods, gatedPods, err := nodeutils.GetProvisionablePods(ctx, p.kubeClient)
 	if err != nil {
 		return nil, fmt.Errorf("listing pods, %w", err)
 	}
+	// pods are sharded between instances of Karpenter by the managed-by label that they require
+	pods = lo.Filter(pods, func(po *corev1.Pod, _ int) bool {
+		return podutils.IsManagedBy(po, options.FromContext(ctx).ManagedBy)
+	})
 	rejectedPods, pods := lo.FilterReject(pods, func(po *corev1.Pod, _ int) bool {
 		if err := p.Validate(ctx, po); err != nil {
 			log.FromContext(ctx).WithValues("Pod", klog.KRef(po.Namespace, po.Name)).V(1).Info(fmt.Sprintf("ignoring pod, %s", err))
@@ -171,10 +197,74 @@ func (p *Provisioner) GetPendingPods(ctx context.Context) ([]*corev1.Pod, error)
 		return false
 	})
 	scheduler.IgnoredPodCount.Set(float64(len(rejectedPods)), nil)
+	// pods with scheduling gates aren't provisionable, but we surface that we've skipped them so that it's clear why
+	// capacity isn't being launched for them
+	for _, po := range gatedPods {
+		p.recorder.Publish(scheduler.PodSchedulingGatedEvent(po))
+	}
+	scheduler.SchedulingGatedPodCount.Set(float64(len(gatedPods)), nil)
 	p.consolidationWarnings(ctx, pods)
 	return pods, nil
 }
 
+// GetHeadroomPods returns the in-memory pods that represent the headroom, the minimum nodes and the minimum nodes per
+// zone of the NodePools that provisioning considers
+func (p *Provisioner) GetHeadroomPods(ctx context.Context) ([]*corev1.Pod, error) {
+	nodePools, err := nodepoolutils.ListManaged(ctx, p.kubeClient, p.cloudProvider)
+	if err != nil {
+		return nil, fmt.Errorf("listing nodepools, %w", err)
+	}
+	var pods []*corev1.Pod
+	for _, np := range nodePools {
+		if np.IsStatic() || np.IsPaused() || !np.DeletionTimestamp.IsZero() {
+			continue
+		}
+		pods = append(pods, scheduler.NewHeadroomPods(np)...)
+		pods = append(pods, scheduler.NewMinNodesPods(np, scheduler.NodeCount(p.cluster.Nodes(), np.Name))...)
+		if np.Spec.MinNodesPerZone != nil {
+			// The minimum is kept in each of the zones that the NodePool's instance types can launch into
+			_, domains := p.resolveNodePool(ctx, np)
+			zones := sets.List(domains[corev1.LabelTopologyZone])
+			pods = append(pods, scheduler.NewMinNodesPerZonePods(np, zones, scheduler.NodesPerZone(p.cluster.Nodes(), np.Name))...)
+		}
+	}
+	return pods, nil
+}
+
+// GetStatefulSetPods returns the in-memory pods for the replicas that StatefulSets with pending pods are expected to
+// create next as they scale out. Only the pending pod with the highest ordinal of each StatefulSet is considered, as the
+// StatefulSet creates its replicas in order after that pod.
+func (p *Provisioner) GetStatefulSetPods(ctx context.Context, pendingPods []*corev1.Pod) ([]*corev1.Pod, error) {
+	latest := map[types.NamespacedName]*corev1.Pod{}
+	for _, pod := range pendingPods {
+		owner := metav1.GetControllerOf(pod)
+		if owner == nil || owner.Kind != "StatefulSet" || owner.APIVersion != appsv1.SchemeGroupVersion.String() {
+			continue
+		}
+		key := types.NamespacedName{Namespace: pod.Namespace, Name: owner.Name}
+		if current, ok := latest[key]; !ok || ordinalOf(owner.Name, pod) > ordinalOf(owner.Name, current) {
+			latest[key] = pod
+		}
+	}
+	var pods []*corev1.Pod
+	for key, pod := range latest {
+		statefulSet := &appsv1.StatefulSet{}
+		if err := p.kubeClient.Get(ctx, key, statefulSet); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("getting statefulset, %w", err)
+		}
+		pods = append(pods, scheduler.NewStatefulSetPods(statefulSet, pod)...)
+	}
+	return pods, nil
+}
+
+func ordinalOf(statefulSetName string, pod *corev1.Pod) int32 {
+	ordinal, _ := scheduler.StatefulSetOrdinal(&appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: statefulSetName}}, pod)
+	return ordinal
+}
+
 // consolidationWarnings potentially writes logs warning about possible unexpected interactions
 // between scheduling constraints and consolidation
 func (p *Provisioner) consolidationWarnings(ctx context.Context, pods []*corev1.Pod) {
@@ -212,16 +302,26 @@ func (p *Provisioner) consolidationWarnings(ctx context.Context, pods []*corev1.
 var ErrNodePoolsNotFound = errors.New("no nodepools found")
 
 //nolint:gocyclo
-func (p *Provisioner) NewScheduler(ctx context.Context, pods []*corev1.Pod, stateNodes []*state.StateNode) (*scheduler.Scheduler, error) {
+func (p *Provisioner) NewScheduler(ctx context.Context, pods []*corev1.Pod, stateNodes []*state.StateNode, opts ...scheduler.Options) (*scheduler.Scheduler, error) {
 	nodePools, err := nodepoolutils.ListManaged(ctx, p.kubeClient, p.cloudProvider)
 	if err != nil {
 		return nil, fmt.Errorf("listing nodepools, %w", err)
 	}
+	// drop the launch rate buckets of NodePools that have been deleted
+	p.launchRates.Prune(nodePools)
 	nodePools = lo.Filter(nodePools, func(np *v1.NodePool, _ int) bool {
 		if !np.StatusConditions().IsTrue(status.ConditionReady) {
 			log.FromContext(ctx).WithValues("NodePool", klog.KRef("", np.Name)).Error(err, "ignoring nodepool, not ready")
 			return false
 		}
+		// Static NodePools maintain a fixed number of nodes and don't provision for pending pods
+		if np.IsStatic() {
+			return false
+		}
+		if np.IsPaused() {
+			log.FromContext(ctx).WithValues("NodePool", klog.KRef("", np.Name)).V(1).Info("ignoring nodepool, paused")
+			return false
+		}
 		return np.DeletionTimestamp.IsZero()
 	})
 	if len(nodePools) == 0 {
@@ -233,52 +333,26 @@ func (p *Provisioner) NewScheduler(ctx context.Context, pods []*corev1.Pod, stat
 	// will always attempt to schedule on the first nodeTemplate
 	nodepoolutils.OrderByWeight(nodePools)
 
+	// Resolve the instance types and topology domains of each NodePool in parallel. Results are stored by index and
+	// merged in NodePool order below so that the outcome doesn't depend on which NodePool finished first.
+	resolvedInstanceTypes := make([][]*cloudprovider.InstanceType, len(nodePools))
+	resolvedDomains := make([]map[string]sets.Set[string], len(nodePools))
+	workqueue.ParallelizeUntil(ctx, len(nodePools), len(nodePools), func(i int) {
+		resolvedInstanceTypes[i], resolvedDomains[i] = p.resolveNodePool(ctx, nodePools[i])
+	})
 	instanceTypes := map[string][]*cloudprovider.InstanceType{}
 	domains := map[string]sets.Set[string]{}
-	for _, np := range nodePools {
-		its, err := p.cloudProvider.GetInstanceTypes(ctx, np)
-		if err != nil {
-			log.FromContext(ctx).WithValues("NodePool", klog.KRef("", np.Name)).Error(err, "skipping, unable to resolve instance types")
-			continue
-		}
-		if len(its) == 0 {
-			log.FromContext(ctx).WithValues("NodePool", klog.KRef("", np.Name)).Info("skipping, no resolved instance types found")
+	for i, np := range nodePools {
+		if resolvedInstanceTypes[i] == nil {
 			continue
 		}
-
-		instanceTypes[np.Name] = its
-
-		// Construct Topology Domains
-		for _, it := range its {
-			// We need to intersect the instance type requirements with the current nodePool requirements.  This
-			// ensures that something like zones from an instance type don't expand the universe of valid domains.
-			requirements := scheduling.NewNodeSelectorRequirementsWithMinValues(np.Spec.Template.Spec.Requirements...)
-			requirements.Add(scheduling.NewLabelRequirements(np.Spec.Template.Labels).Values()...)
-			requirements.Add(it.Requirements.Values()...)
-
-			for key, requirement := range requirements {
-				// This code used to execute a Union between domains[key] and requirement.Values().
-				// The downside of this is that Union is immutable and takes a copy of the set it is executed upon.
-				// This resulted in a lot of memory pressure on the heap and poor performance
-				// https://github.com/aws/karpenter/issues/3565
-				if domains[key] == nil {
-					domains[key] = sets.New(requirement.Values()...)
-				} else {
-					domains[key].Insert(requirement.Values()...)
-				}
-			}
-		}
-
-		requirements := scheduling.NewNodeSelectorRequirementsWithMinValues(np.Spec.Template.Spec.Requirements...)
-		requirements.Add(scheduling.NewLabelRequirements(np.Spec.Template.Labels).Values()...)
-		for key, requirement := range requirements {
-			if requirement.Operator() == corev1.NodeSelectorOpIn {
-				// The following is a performance optimisation, for the explanation see the comment above
-				if domains[key] == nil {
-					domains[key] = sets.New(requirement.Values()...)
-				} else {
-					domains[key].Insert(requirement.Values()...)
-				}
+		instanceTypes[np.Name] = resolvedInstanceTypes[i]
+		for key, values := range resolvedDomains[i] {
+			// The following is a performance optimisation, for the explanation see resolveNodePool
+			if domains[key] == nil {
+				domains[key] = values
+			} else {
+				domains[key].Insert(values.UnsortedList()...)
 			}
 		}
 	}
@@ -287,7 +361,7 @@ func (p *Provisioner) NewScheduler(ctx context.Context, pods []*corev1.Pod, stat
 	pods = p.injectVolumeTopologyRequirements(ctx, pods)
 
 	// Calculate cluster topology
-	topology, err := scheduler.NewTopology(ctx, p.kubeClient, p.cluster, domains, pods)
+	topology, err := scheduler.NewTopology(ctx, p.cluster, domains, pods)
 	if err != nil {
 		return nil, fmt.Errorf("tracking topology counts, %w", err)
 	}
@@ -295,7 +369,101 @@ func (p *Provisioner) NewScheduler(ctx context.Context, pods []*corev1.Pod, stat
 	if err != nil {
 		return nil, fmt.Errorf("getting daemon pods, %w", err)
 	}
-	return scheduler.NewScheduler(ctx, p.kubeClient, nodePools, p.cluster, stateNodes, topology, instanceTypes, daemonSetPods, p.recorder, p.clock), nil
+	limitRanges := &corev1.LimitRangeList{}
+	if err = p.kubeClient.List(ctx, limitRanges); err != nil {
+		return nil, fmt.Errorf("listing limit ranges, %w", err)
+	}
+	opts = append(opts, scheduler.WithLimitRanges(scheduler.NewLimitRanges(limitRanges.Items)), scheduler.WithStaticPods(p.getStaticPods()))
+	// The packing order and NodePool weighting are applied to every scheduler, including the ones used to simulate
+	// disruption, so that simulations pack pods the same way that provisioning does
+	opts = append([]scheduler.Options{scheduler.WithPackingOrder(scheduler.PackingOrder(options.FromContext(ctx).PodPackingOrder))}, opts...)
+	if options.FromContext(ctx).StrictNodePoolWeights {
+		opts = append(opts, scheduler.StrictNodePoolWeights)
+	}
+	if options.FromContext(ctx).StrictStartupTaints {
+		opts = append(opts, scheduler.StrictStartupTaints)
+	}
+	if sla := options.FromContext(ctx).PendingPodSLA; sla > 0 {
+		opts = append(opts, scheduler.WithPendingPodSLA(sla))
+	}
+	// the threshold is validated when the options are parsed
+	if threshold := resource.MustParse(options.FromContext(ctx).FragmentationThreshold); !threshold.IsZero() {
+		opts = append(opts, scheduler.WithFragmentationThreshold(threshold))
+	}
+	// the aliases are validated when the options are parsed
+	if aliases := lo.Must(resources.ParseAliases(options.FromContext(ctx).ResourceAliases)); len(aliases) > 0 {
+		opts = append(opts, scheduler.WithResourceAliases(aliases))
+	}
+	return scheduler.NewScheduler(ctx, p.kubeClient, nodePools, p.cluster, stateNodes, topology, instanceTypes, daemonSetPods, p.recorder, p.clock, opts...), nil
+}
+
+// resolveNodePool returns the instance types that the NodePool can launch and the topology domains that they make
+// available. It returns nil instance types if the NodePool can't be used for scheduling.
+func (p *Provisioner) resolveNodePool(ctx context.Context, np *v1.NodePool) ([]*cloudprovider.InstanceType, map[string]sets.Set[string]) {
+	its, err := p.cloudProvider.GetInstanceTypes(ctx, np.WithActiveNodeClassRef())
+	if err != nil {
+		log.FromContext(ctx).WithValues("NodePool", klog.KRef("", np.Name)).Error(err, "skipping, unable to resolve instance types")
+		return nil, nil
+	}
+	// Account for the kubelet configuration of the NodePool in the allocatable resources of the instance types
+	its = cloudprovider.InstanceTypes(its).WithKubeletConfiguration(np.Spec.Template.Spec.Kubelet)
+	// Adjust the prices, capacity and overhead of the instance types by the node overlays that select them. Overlays
+	// are applied after the kubelet configuration so that the overhead they add isn't replaced by the reserved resources
+	// of the kubelet configuration.
+	overlays := &v1.NodeOverlayList{}
+	if err := p.kubeClient.List(ctx, overlays); err != nil {
+		log.FromContext(ctx).WithValues("NodePool", klog.KRef("", np.Name)).Error(err, "skipping, unable to list node overlays")
+		return nil, nil
+	}
+	its = cloudprovider.InstanceTypes(its).WithOverlays(lo.ToSlicePtr(overlays.Items))
+	its = cloudprovider.InstanceTypes(its).AtLeast(np.Spec.MinResources)
+	if len(its) == 0 {
+		log.FromContext(ctx).WithValues("NodePool", klog.KRef("", np.Name)).Info("skipping, no resolved instance types found")
+		return nil, nil
+	}
+
+	// Construct Topology Domains
+	domains := map[string]sets.Set[string]{}
+	// Zones that the NodePool selects by name constrain the zone ID domains, and the other way around
+	zones := cloudprovider.InstanceTypes(its).ZoneMapping()
+	for _, it := range its {
+		// We need to intersect the instance type requirements with the current nodePool requirements.  This
+		// ensures that something like zones from an instance type don't expand the universe of valid domains.
+		requirements := scheduling.NewNodeSelectorRequirementsWithMinValues(np.Spec.Template.Spec.Requirements...)
+		requirements.Add(scheduling.NewLabelRequirements(np.Spec.Template.Labels).Values()...)
+		requirements = zones.Normalize(requirements)
+		requirements.Add(it.Requirements.Values()...)
+
+		for key, requirement := range requirements {
+			// This code used to execute a Union between domains[key] and requirement.Values().
+			// The downside of this is that Union is immutable and takes a copy of the set it is executed upon.
+			// This resulted in a lot of memory pressure on the heap and poor performance
+			// https://github.com/aws/karpenter/issues/3565
+			if domains[key] == nil {
+				domains[key] = sets.New(requirement.Values()...)
+			} else {
+				domains[key].Insert(requirement.Values()...)
+			}
+		}
+	}
+
+	requirements := scheduling.NewNodeSelectorRequirementsWithMinValues(np.Spec.Template.Spec.Requirements...)
+	requirements.Add(scheduling.NewLabelRequirements(np.Spec.Template.Labels).Values()...)
+	requirements.Add(scheduler.TopologyDomainRequirements(np).Values()...)
+	requirements = zones.Normalize(requirements)
+	// Each NodePool is its own domain for the karpenter.sh/nodepool topology key, so that pods can be spread across NodePools
+	requirements.Add(scheduling.NewRequirement(v1.NodePoolLabelKey, corev1.NodeSelectorOpIn, np.Name))
+	for key, requirement := range requirements {
+		if requirement.Operator() == corev1.NodeSelectorOpIn {
+			// The following is a performance optimisation, for the explanation see the comment above
+			if domains[key] == nil {
+				domains[key] = sets.New(requirement.Values()...)
+			} else {
+				domains[key].Insert(requirement.Values()...)
+			}
+		}
+	}
+	return its, domains
 }
 
 func (p *Provisioner) Schedule(ctx context.Context) (scheduler.Results, error) {
@@ -313,44 +481,116 @@ func (p *Provisioner) Schedule(ctx context.Context) (scheduler.Results, error) {
 	// the pods that are on these nodes so the MarkedForDeletion node capacity can't be considered.
 	nodes := p.cluster.Nodes()
 
-	// Get pods, exit if nothing to do
-	pendingPods, err := p.GetPendingPods(ctx)
+	pendingPods, pods, err := p.getSchedulablePods(ctx, nodes)
 	if err != nil {
 		return scheduler.Results{}, err
 	}
-
-	// Get pods from nodes that are preparing for deletion
-	// We do this after getting the pending pods so that we undershoot if pods are
-	// actively migrating from a node that is being deleted
-	// NOTE: The assumption is that these nodes are cordoned and no additional pods will schedule to them
-	deletingNodePods, err := nodes.Deleting().ReschedulablePods(ctx, p.kubeClient)
-	if err != nil {
-		return scheduler.Results{}, err
-	}
-	pods := append(pendingPods, deletingNodePods...)
 	// nothing to schedule, so just return success
 	if len(pods) == 0 {
 		return scheduler.Results{}, nil
 	}
-	s, err := p.NewScheduler(ctx, pods, nodes.Active())
+	results, err := p.solve(ctx, nodes, pods)
 	if err != nil {
 		if errors.Is(err, ErrNodePoolsNotFound) {
 			log.FromContext(ctx).Info("no nodepools found")
 			return scheduler.Results{}, nil
 		}
-		return scheduler.Results{}, fmt.Errorf("creating scheduler, %w", err)
+		return scheduler.Results{}, err
 	}
-	results := s.Solve(ctx, pods).TruncateInstanceTypes(scheduler.MaxInstanceTypes)
 	scheduler.UnschedulablePodsCount.Set(float64(len(results.PodErrors)), map[string]string{scheduler.ControllerLabel: injection.GetControllerName(ctx)})
 	if len(results.NewNodeClaims) > 0 {
-		log.FromContext(ctx).WithValues("Pods", pretty.Slice(lo.Map(pods, func(p *corev1.Pod, _ int) string { return klog.KRef(p.Namespace, p.Name).String() }), 5), "duration", time.Since(start)).Info("found provisionable pod(s)")
+		log.FromContext(ctx).WithValues("Pods", pretty.Slice(lo.Map(lo.Reject(pods, func(p *corev1.Pod, _ int) bool { return scheduler.IsHeadroomPod(p) }), func(p *corev1.Pod, _ int) string { return klog.KRef(p.Namespace, p.Name).String() }), 5), "duration", time.Since(start)).Info("found provisionable pod(s)")
 	}
 	// Mark in memory when these pods were marked as schedulable or when we made a decision on the pods
 	p.cluster.MarkPodSchedulingDecisions(results.PodErrors, pendingPods...)
 	results.Record(ctx, p.recorder, p.cluster)
+	if options.FromContext(ctx).EnableSchedulingSnapshots {
+		if err := p.recordSchedulingSnapshot(ctx, results); err != nil {
+			log.FromContext(ctx).Error(err, "failed recording scheduling snapshot")
+		}
+	}
 	return results, nil
 }
 
+// DryRun returns the results of scheduling the current pending pods without launching the NodeClaims, nominating
+// nodes for pods or recording the scheduling decisions against cluster state.
+func (p *Provisioner) DryRun(ctx context.Context) (scheduler.Results, error) {
+	nodes := p.cluster.Nodes()
+	_, pods, err := p.getSchedulablePods(ctx, nodes)
+	if err != nil {
+		return scheduler.Results{}, err
+	}
+	if len(pods) == 0 {
+		return scheduler.Results{}, nil
+	}
+	results, err := p.solve(ctx, nodes, pods)
+	if errors.Is(err, ErrNodePoolsNotFound) {
+		return scheduler.Results{}, nil
+	}
+	return results, err
+}
+
+// getSchedulablePods returns the pending pods, and all the pods that need to be scheduled including those on deleting
+// nodes and the NodePools' headroom pods
+func (p *Provisioner) getSchedulablePods(ctx context.Context, nodes state.StateNodes) (pendingPods, pods []*corev1.Pod, err error) {
+	pendingPods, err = p.GetPendingPods(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Get pods from nodes that are preparing for deletion
+	// We do this after getting the pending pods so that we undershoot if pods are
+	// actively migrating from a node that is being deleted
+	// NOTE: The assumption is that these nodes are cordoned and no additional pods will schedule to them
+	deletingNodePods, err := nodes.Deleting().ReschedulablePods(ctx, p.kubeClient)
+	if err != nil {
+		return nil, nil, err
+	}
+	pods = append(pendingPods, deletingNodePods...)
+	headroomPods, err := p.GetHeadroomPods(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	pods = append(pods, headroomPods...)
+	if options.FromContext(ctx).FeatureGates.StatefulSetAwareProvisioning {
+		statefulSetPods, err := p.GetStatefulSetPods(ctx, pendingPods)
+		if err != nil {
+			return nil, nil, err
+		}
+		pods = append(pods, statefulSetPods...)
+	}
+	return pendingPods, pods, nil
+}
+
+func (p *Provisioner) solve(ctx context.Context, nodes state.StateNodes, pods []*corev1.Pod) (scheduler.Results, error) {
+	// pods only wait for initializing nodes when provisioning, the NodePools without an existing capacity window are
+	// unaffected
+	opts := []scheduler.Options{scheduler.PreferExistingCapacity}
+	if options.FromContext(ctx).FeatureGates.PreemptionAwareProvisioning {
+		opts = append(opts, scheduler.PreemptionAware)
+	}
+	s, err := p.NewScheduler(ctx, pods, nodes.Active(), opts...)
+	if err != nil {
+		return scheduler.Results{}, fmt.Errorf("creating scheduler, %w", err)
+	}
+	results, err := p.Extend(ctx, s.Solve(ctx, pods))
+	if err != nil {
+		return scheduler.Results{}, err
+	}
+	return results.TruncateInstanceTypes(scheduler.MaxInstanceTypes), nil
+}
+
+// Extend filters and scores the instance types of the new NodeClaims in the results with the scheduling extender, if
+// one is configured. Disruption only uses it if SchedulingExtenderDisruption is enabled, so that its simulations launch
+// the same instance types as provisioning.
+func (p *Provisioner) Extend(ctx context.Context, results scheduler.Results) (scheduler.Results, error) {
+	url := options.FromContext(ctx).SchedulingExtenderURL
+	if url == "" {
+		return results, nil
+	}
+	return scheduler.NewExtender(p.extenderClient, url).Extend(ctx, results)
+}
+
 func (p *Provisioner) Create(ctx context.Context, n *scheduler.NodeClaim, opts ...option.Function[LaunchOptions]) (string, error) {
 	ctx = log.IntoContext(ctx, log.FromContext(ctx).WithValues("NodePool", klog.KRef("", n.NodePoolName)))
 	options := option.Resolve(opts...)
@@ -361,11 +601,45 @@ func (p *Provisioner) Create(ctx context.Context, n *scheduler.NodeClaim, opts .
 	if err := latest.Spec.Limits.ExceededBy(latest.Status.Resources); err != nil {
 		return "", err
 	}
+	// capacity types whose limits have been exceeded since the NodeClaim was scheduled are excluded from it, failing the
+	// launch if none of its capacity types are left
+	var exceeded []string
+	var errs error
+	for capacityType, limits := range latest.Spec.CapacityTypeLimits {
+		if !n.Requirements.Get(v1.CapacityTypeLabelKey).Has(capacityType) {
+			continue
+		}
+		if err := limits.ExceededBy(latest.Status.CapacityTypeResources[capacityType]); err != nil {
+			exceeded = append(exceeded, capacityType)
+			errs = multierr.Append(errs, fmt.Errorf("%s capacity type limits exceeded, %w", capacityType, err))
+		}
+	}
+	if len(exceeded) > 0 {
+		n.Requirements.Add(scheduling.NewRequirement(v1.CapacityTypeLabelKey, corev1.NodeSelectorOpNotIn, exceeded...))
+		if !lo.ContainsBy(n.InstanceTypeOptions, func(it *cloudprovider.InstanceType) bool {
+			return it.Offerings.Available().HasCompatible(n.Requirements)
+		}) {
+			return "", errs
+		}
+	}
+	refund, ok := p.launchRates.Reserve(latest, p.clock.Now())
+	if !ok {
+		return "", fmt.Errorf("launch rate of %d nodeclaim(s) per minute exceeded for nodepool %q", latest.Spec.LaunchRate.NodeClaimsPerMinute, latest.Name)
+	}
+	rejections := n.InstanceTypeRejections()
 	nodeClaim := n.ToNodeClaim()
 
 	if err := p.kubeClient.Create(ctx, nodeClaim); err != nil {
+		// the NodeClaim wasn't launched, so it shouldn't count against the launch rate
+		refund()
 		return "", err
 	}
+	for reason, count := range rejections {
+		scheduler.InstanceTypesRejectedTotal.Add(float64(count), map[string]string{
+			metrics.NodePoolLabel: n.NodePoolName,
+			metrics.ReasonLabel:   string(reason),
+		})
+	}
 	instanceTypeRequirement, _ := lo.Find(nodeClaim.Spec.Requirements, func(req v1.NodeSelectorRequirementWithMinValues) bool {
 		return req.Key == corev1.LabelInstanceTypeStable
 	})
@@ -383,8 +657,14 @@ func (p *Provisioner) Create(ctx context.Context, n *scheduler.NodeClaim, opts .
 	// to then trigger cluster state updates. Triggering it manually ensures that Karpenter waits for the
 	// internal cache to sync before moving onto another disruption loop.
 	p.cluster.UpdateNodeClaim(nodeClaim)
+	if options.PreBindPods {
+		p.cluster.PreBindPods(nodeClaim.Name, lo.Reject(n.Pods, func(pod *corev1.Pod, _ int) bool { return scheduler.IsHeadroomPod(pod) })...)
+	}
 	if option.Resolve(opts...).RecordPodNomination {
 		for _, pod := range n.Pods {
+			if scheduler.IsHeadroomPod(pod) {
+				continue
+			}
 			p.recorder.Publish(scheduler.NominatePodEvent(pod, nil, nodeClaim))
 		}
 	}
@@ -415,7 +695,7 @@ func (p *Provisioner) getDaemonSetPods(ctx context.Context) ([]*corev1.Pod, erro
 	return lo.Map(daemonSetList.Items, func(d appsv1.DaemonSet, _ int) *corev1.Pod {
 		pod := p.cluster.GetDaemonSetPod(&d)
 		if pod == nil {
-			pod = &corev1.Pod{Spec: d.Spec.Template.Spec}
+			pod = &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: d.Spec.Template.Labels}, Spec: d.Spec.Template.Spec}
 		}
 		// Replacing retrieved pod affinity with daemonset pod template required node affinity since this is overridden
 		// by the daemonset controller during pod creation
@@ -433,9 +713,44 @@ func (p *Provisioner) getDaemonSetPods(ctx context.Context) ([]*corev1.Pod, erro
 	}), nil
 }
 
+// getStaticPods returns a pod for each of the static pods that the nodes of each NodePool run, which new nodes from the
+// NodePool are expected to run as well. Static pods are configured on the node rather than scheduled, so the pods are
+// restricted to their NodePool and tolerate all taints. Nodes that run the same static pod have mirror pods with the
+// same name, suffixed with the node name.
+func (p *Provisioner) getStaticPods() []*corev1.Pod {
+	staticPods := map[string]*corev1.Pod{}
+	p.cluster.ForEachNode(func(node *state.StateNode) bool {
+		nodePoolName, ok := node.Labels()[v1.NodePoolLabelKey]
+		if !ok {
+			return true
+		}
+		for _, mirror := range node.StaticPods() {
+			name := strings.TrimSuffix(mirror.Name, "-"+mirror.Spec.NodeName)
+			key := fmt.Sprintf("%s/%s/%s", nodePoolName, mirror.Namespace, name)
+			if _, ok := staticPods[key]; ok {
+				continue
+			}
+			mirror = mirror.DeepCopy()
+			staticPods[key] = &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: mirror.Namespace, Labels: mirror.Labels},
+				Spec: corev1.PodSpec{
+					InitContainers: mirror.Spec.InitContainers,
+					Containers:     mirror.Spec.Containers,
+					Overhead:       mirror.Spec.Overhead,
+					NodeSelector:   map[string]string{v1.NodePoolLabelKey: nodePoolName},
+					Tolerations:    []corev1.Toleration{{Operator: corev1.TolerationOpExists}},
+				},
+			}
+		}
+		return true
+	})
+	return lo.Values(staticPods)
+}
+
 func (p *Provisioner) Validate(ctx context.Context, pod *corev1.Pod) error {
 	return multierr.Combine(
 		validateKarpenterManagedLabelCanExist(pod),
+		validateNodePoolAnnotation(pod),
 		validateNodeSelector(pod),
 		validateAffinity(pod),
 		p.volumeTopology.ValidatePersistentVolumeClaims(ctx, pod),
@@ -454,6 +769,22 @@ func validateKarpenterManagedLabelCanExist(p *corev1.Pod) error {
 	return nil
 }
 
+// validateNodePoolAnnotation ensures that the NodePool requested through the pod's annotation is a valid NodePool name
+// that doesn't conflict with the pod's other NodePool constraints
+func validateNodePoolAnnotation(p *corev1.Pod) error {
+	nodePool, ok := p.Annotations[v1.NodePoolAnnotationKey]
+	if !ok {
+		return nil
+	}
+	if errs := validation.IsDNS1123Subdomain(nodePool); len(errs) > 0 {
+		return fmt.Errorf("invalid value %q for annotation %s, %s", nodePool, v1.NodePoolAnnotationKey, strings.Join(errs, ", "))
+	}
+	if !scheduling.NewStrictPodRequirements(p).Get(v1.NodePoolLabelKey).Has(nodePool) {
+		return fmt.Errorf("requested nodepool %q from the %s annotation conflicts with the pod's %s requirements", nodePool, v1.NodePoolAnnotationKey, v1.NodePoolLabelKey)
+	}
+	return nil
+}
+
 func (p *Provisioner) injectVolumeTopologyRequirements(ctx context.Context, pods []*corev1.Pod) []*corev1.Pod {
 	var schedulablePods []*corev1.Pod
 	for _, pod := range pods {
@@ -0,0 +1,45 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduling
+
+import (
+	"sort"
+
+	"github.com/samber/lo"
+)
+
+// capacityTypesBySplit returns the capacity types of the split ordered by how far the NodePool would be below the
+// percentage of each capacity type after launching another NodeClaim, given the number of NodeClaims of each capacity
+// type that the NodePool already has. Capacity types with a percentage of zero are never returned.
+func capacityTypesBySplit(split map[string]int32, counts map[string]int) []string {
+	total := lo.Sum(lo.Values(counts)) + 1
+	deficits := map[string]float64{}
+	for capacityType, percentage := range split {
+		if percentage <= 0 {
+			continue
+		}
+		deficits[capacityType] = float64(total)*float64(percentage)/100 - float64(counts[capacityType])
+	}
+	capacityTypes := lo.Keys(deficits)
+	sort.Slice(capacityTypes, func(i, j int) bool {
+		if deficits[capacityTypes[i]] != deficits[capacityTypes[j]] {
+			return deficits[capacityTypes[i]] > deficits[capacityTypes[j]]
+		}
+		return capacityTypes[i] < capacityTypes[j]
+	})
+	return capacityTypes
+}
@@ -0,0 +1,178 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduling
+
+import (
+	"context"
+
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/workqueue"
+
+	"sigs.k8s.io/karpenter/pkg/cloudprovider"
+	"sigs.k8s.io/karpenter/pkg/scheduling"
+	"sigs.k8s.io/karpenter/pkg/utils/resources"
+)
+
+// daemonOverhead is the overhead of the daemonset pods that will schedule to a node launched from a NodeClaimTemplate.
+// DaemonSets can select on labels that are only defined by some instance types (e.g. GPU or architecture labels), so
+// the overhead is tracked separately for each instance type.
+type daemonOverhead struct {
+	requests    map[string]corev1.ResourceList // (instance type name) -> requests of the daemons
+	allocatable map[string]corev1.ResourceList // (instance type name) -> allocatable resources that remain for other pods
+}
+
+// min returns the overhead that a node launched with any of the instance types will have
+func (d *daemonOverhead) min(instanceTypes []*cloudprovider.InstanceType) corev1.ResourceList {
+	if len(instanceTypes) == 0 {
+		return corev1.ResourceList{}
+	}
+	result := d.requests[instanceTypes[0].Name].DeepCopy()
+	for _, it := range instanceTypes[1:] {
+		requests := d.requests[it.Name]
+		for name, quantity := range result {
+			if other, ok := requests[name]; !ok {
+				delete(result, name)
+			} else if other.Cmp(quantity) < 0 {
+				result[name] = other.DeepCopy()
+			}
+		}
+	}
+	return result
+}
+
+// daemon is a daemonset pod along with the requirements that it can schedule with, one for each of its required node
+// affinity terms
+type daemon struct {
+	pod          *corev1.Pod
+	requirements []scheduling.Requirements
+	keys         sets.Set[string]
+}
+
+func newDaemon(pod *corev1.Pod) *daemon {
+	// we relax the pod in place, so we don't want to modify the pod that is shared across NodeClaimTemplates
+	pod = pod.DeepCopy()
+	preferences := &Preferences{}
+	// Add a toleration for PreferNoSchedule since a daemon pod shouldn't respect the preference
+	_ = preferences.toleratePreferNoScheduleTaints(pod)
+	d := &daemon{pod: pod, keys: sets.New[string]()}
+	for {
+		// We don't consider pod preferences for scheduling requirements since we know that pod preferences won't matter with Daemonset scheduling
+		requirements := scheduling.NewStrictPodRequirements(pod)
+		d.requirements = append(d.requirements, requirements)
+		d.keys.Insert(requirements.Keys().UnsortedList()...)
+		// Each required node affinity term is an alternative that the daemon can schedule with. We don't consider other
+		// forms of relaxation here since we don't consider pod affinities/anti-affinities when considering DaemonSet
+		// schedulability
+		if preferences.removeRequiredNodeAffinityTerm(pod) == nil {
+			return d
+		}
+	}
+}
+
+// compatible determines if the daemon pod can schedule to a node with the taints and requirements
+func (d *daemon) compatible(taints []corev1.Taint, requirements scheduling.Requirements) bool {
+	if err := scheduling.Taints(taints).Tolerates(d.pod); err != nil {
+		return false
+	}
+	return lo.ContainsBy(d.requirements, func(r scheduling.Requirements) bool {
+		return requirements.IsCompatible(r, scheduling.AllowUndefinedWellKnownLabels)
+	})
+}
+
+// getDaemonOverhead determines the overhead for each NodeClaimTemplate required for daemons to schedule for any node
+// provisioned by the NodeClaimTemplate
+func getDaemonOverhead(ctx context.Context, nodeClaimTemplates []*NodeClaimTemplate, daemonSetPods []*corev1.Pod) map[*NodeClaimTemplate]*daemonOverhead {
+	daemons := lo.Map(daemonSetPods, func(p *corev1.Pod, _ int) *daemon { return newDaemon(p) })
+	overhead := make([]*daemonOverhead, len(nodeClaimTemplates))
+	workqueue.ParallelizeUntil(ctx, len(nodeClaimTemplates), len(nodeClaimTemplates), func(i int) {
+		overhead[i] = getInstanceTypeDaemonOverhead(nodeClaimTemplates[i], daemons)
+	})
+	result := make(map[*NodeClaimTemplate]*daemonOverhead, len(nodeClaimTemplates))
+	for i, nct := range nodeClaimTemplates {
+		result[nct] = overhead[i]
+	}
+	return result
+}
+
+// getInstanceTypeDaemonOverhead determines the overhead for each instance type of the NodeClaimTemplate. A daemon is
+// compatible with an instance type if it's compatible with the NodeClaimTemplate requirements combined with the
+// instance type requirements. Labels that are defined by some of the instance types, but not by this one, won't exist
+// on the node, so a daemon that requires them isn't counted.
+func getInstanceTypeDaemonOverhead(nodeClaimTemplate *NodeClaimTemplate, daemons []*daemon) *daemonOverhead {
+	// only the daemons that are compatible with the NodeClaimTemplate can be compatible with its instance types, and
+	// daemons that the NodePool excludes are never counted
+	daemons = lo.Filter(daemons, func(d *daemon, _ int) bool {
+		if nodeClaimTemplate.ExcludedDaemonSets != nil && nodeClaimTemplate.ExcludedDaemonSets.Matches(labels.Set(d.pod.Labels)) {
+			return false
+		}
+		return d.compatible(nodeClaimTemplate.Spec.TaintsWithTemplates(), nodeClaimTemplate.Requirements)
+	})
+	instanceTypeKeys := sets.New[string]()
+	for _, it := range nodeClaimTemplate.InstanceTypeOptions {
+		instanceTypeKeys.Insert(it.Requirements.Keys().UnsortedList()...)
+	}
+	// daemons that don't select on any instance type labels are compatible with all of the instance types
+	var common []*corev1.Pod
+	var selective []*daemon
+	for _, d := range daemons {
+		if d.keys.HasAny(instanceTypeKeys.UnsortedList()...) {
+			selective = append(selective, d)
+		} else {
+			common = append(common, d.pod)
+		}
+	}
+	overhead := &daemonOverhead{
+		requests:    make(map[string]corev1.ResourceList, len(nodeClaimTemplate.InstanceTypeOptions)),
+		allocatable: make(map[string]corev1.ResourceList, len(nodeClaimTemplate.InstanceTypeOptions)),
+	}
+	for _, it := range nodeClaimTemplate.InstanceTypeOptions {
+		pods := common
+		if len(selective) > 0 {
+			requirements := scheduling.NewRequirements(nodeClaimTemplate.Requirements.Values()...)
+			requirements.Add(it.Requirements.Values()...)
+			for key := range instanceTypeKeys {
+				if !requirements.Has(key) {
+					requirements.Add(scheduling.NewRequirement(key, corev1.NodeSelectorOpDoesNotExist))
+				}
+			}
+			pods = append(lo.FilterMap(selective, func(d *daemon, _ int) (*corev1.Pod, bool) {
+				return d.pod, d.compatible(nodeClaimTemplate.Spec.TaintsWithTemplates(), requirements)
+			}), common...)
+		}
+		requests := resources.RequestsForPods(pods...)
+		overhead.requests[it.Name] = requests
+		overhead.allocatable[it.Name] = remainingAllocatable(it.Allocatable(), requests)
+	}
+	return overhead
+}
+
+// remainingAllocatable returns the allocatable resources that remain after the requests are subtracted. Requested
+// resources that aren't allocatable at all are negative, so that nothing fits.
+func remainingAllocatable(allocatable, requests corev1.ResourceList) corev1.ResourceList {
+	remaining := resources.Subtract(allocatable, requests)
+	for name, quantity := range requests {
+		if _, ok := remaining[name]; !ok {
+			negated := quantity.DeepCopy()
+			negated.Neg()
+			remaining[name] = negated
+		}
+	}
+	return remaining
+}
@@ -0,0 +1,227 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduling
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+	"sigs.k8s.io/karpenter/pkg/cloudprovider"
+	"sigs.k8s.io/karpenter/pkg/controllers/state"
+	"sigs.k8s.io/karpenter/pkg/scheduling"
+	"sigs.k8s.io/karpenter/pkg/utils/resources"
+)
+
+// domainLimits tracks the resources that each NodePool can still provision in each domain of the topology keys of its
+// domain limits. The remaining resources of the capacity types of a NodePool's capacity type limits are tracked as
+// domains of the capacity type label.
+type domainLimits struct {
+	limits             map[string]map[string]v1.Limits                      // (NodePool name) -> (topology key) -> limits
+	capacityTypeLimits map[string]map[string]v1.Limits                      // (NodePool name) -> (capacity type) -> limits
+	remaining          map[string]map[string]map[string]corev1.ResourceList // (NodePool name) -> (topology key) -> (domain) -> remaining resources
+}
+
+func newDomainLimits(nodePools []*v1.NodePool) *domainLimits {
+	d := &domainLimits{
+		limits:             map[string]map[string]v1.Limits{},
+		capacityTypeLimits: map[string]map[string]v1.Limits{},
+		remaining:          map[string]map[string]map[string]corev1.ResourceList{},
+	}
+	for _, np := range nodePools {
+		if len(np.Spec.DomainLimits) > 0 {
+			d.limits[np.Name] = np.Spec.DomainLimits
+		}
+		if len(np.Spec.CapacityTypeLimits) > 0 {
+			d.capacityTypeLimits[np.Name] = np.Spec.CapacityTypeLimits
+		}
+	}
+	return d
+}
+
+// keys returns the topology keys of the NodePool's domain limits
+func (d *domainLimits) keys(nodePoolName string) []string {
+	return sets.List(sets.KeySet(d.limits[nodePoolName]))
+}
+
+// capacityTypes returns the capacity types of the NodePool's capacity type limits that the requirements allow
+func (d *domainLimits) capacityTypes(nodePoolName string, requirements scheduling.Requirements) []string {
+	return lo.Filter(sets.List(sets.KeySet(d.capacityTypeLimits[nodePoolName])), func(capacityType string, _ int) bool {
+		return requirements.Get(v1.CapacityTypeLabelKey).Has(capacityType)
+	})
+}
+
+// limitsIn returns the limits of the domain of the topology key
+func (d *domainLimits) limitsIn(nodePoolName, key, domain string) corev1.ResourceList {
+	if key == v1.CapacityTypeLabelKey {
+		return corev1.ResourceList(d.capacityTypeLimits[nodePoolName][domain]).DeepCopy()
+	}
+	return corev1.ResourceList(d.limits[nodePoolName][key]).DeepCopy()
+}
+
+// remainingIn returns the resources that the NodePool can still provision in the domain of the topology key
+func (d *domainLimits) remainingIn(nodePoolName, key, domain string) corev1.ResourceList {
+	if d.remaining[nodePoolName] == nil {
+		d.remaining[nodePoolName] = map[string]map[string]corev1.ResourceList{}
+	}
+	if d.remaining[nodePoolName][key] == nil {
+		d.remaining[nodePoolName][key] = map[string]corev1.ResourceList{}
+	}
+	remaining, ok := d.remaining[nodePoolName][key][domain]
+	if !ok {
+		remaining = d.limitsIn(nodePoolName, key, domain)
+		d.remaining[nodePoolName][key][domain] = remaining
+	}
+	return remaining
+}
+
+// subtractNode subtracts the capacity of an existing node from the remaining resources of the domains that it's in
+func (d *domainLimits) subtractNode(node *state.StateNode) {
+	nodePoolName := node.Labels()[v1.NodePoolLabelKey]
+	keys := d.keys(nodePoolName)
+	if len(d.capacityTypeLimits[nodePoolName]) > 0 {
+		keys = append(keys, v1.CapacityTypeLabelKey)
+	}
+	for _, key := range keys {
+		if domain, ok := node.Labels()[key]; ok {
+			d.remaining[nodePoolName][key][domain] = resources.Subtract(d.remainingIn(nodePoolName, key, domain), withNode(node.Capacity()))
+		}
+	}
+}
+
+// restrict restricts the requirements of a new NodeClaim of the NodePool to a single domain of each topology key of its
+// domain limits, returning the instance types that fit in those domains. The domain that has room for the most of the
+// instance types is picked, and the cheapest of those domains on a tie. The NodeClaim is restricted to a single domain,
+// as a NodeClaim that could launch into any of several domains would otherwise count against the limits of all of them.
+func (d *domainLimits) restrict(nodePoolName string, requirements scheduling.Requirements, instanceTypes []*cloudprovider.InstanceType, domains map[string]sets.Set[string]) ([]*cloudprovider.InstanceType, error) {
+	for _, key := range d.keys(nodePoolName) {
+		var best string
+		var bestFit []*cloudprovider.InstanceType
+		bestPrice := math.MaxFloat64
+		for _, domain := range sets.List(domains[key]) {
+			if !requirements.Get(key).Has(domain) {
+				continue
+			}
+			inDomain := scheduling.NewRequirements(requirements.Values()...)
+			inDomain.Add(scheduling.NewRequirement(key, corev1.NodeSelectorOpIn, domain))
+			offered := lo.Filter(instanceTypes, func(it *cloudprovider.InstanceType, _ int) bool {
+				return it.Requirements.IsCompatible(inDomain, scheduling.AllowUndefinedWellKnownLabels) &&
+					it.Offerings.Available().HasCompatible(inDomain)
+			})
+			fit := filterByRemainingResources(offered, d.remainingIn(nodePoolName, key, domain))
+			if len(fit) == 0 {
+				continue
+			}
+			price := lo.Min(lo.Map(fit, func(it *cloudprovider.InstanceType, _ int) float64 {
+				return it.Offerings.Available().Compatible(inDomain).Cheapest().Price
+			}))
+			if len(fit) > len(bestFit) || (len(fit) == len(bestFit) && price < bestPrice) {
+				best, bestFit, bestPrice = domain, fit, price
+			}
+		}
+		if len(bestFit) == 0 {
+			return nil, fmt.Errorf("all available instance types exceed the %s domain limits of nodepool %q", key, nodePoolName)
+		}
+		instanceTypes = bestFit
+		requirements.Add(scheduling.NewRequirement(key, corev1.NodeSelectorOpIn, best))
+	}
+	return d.restrictCapacityTypes(nodePoolName, requirements, instanceTypes)
+}
+
+// restrictCapacityTypes enforces the NodePool's capacity type limits on each offering of the instance types. Unlike the
+// domains of the domain limits, the NodeClaim keeps every capacity type that has room, so the cloud provider can still
+// fall back between them. Capacity types that don't have room for any of the instance types are excluded, and an
+// instance type is kept as long as one of its offerings fits, e.g. a spot offering within the spot limits even though
+// its on-demand offering would exceed the on-demand limits. As the NodeClaim's requirements can't pair capacity types
+// with instance types, the capacity types that the kept instance types would exceed are excluded from the NodeClaim,
+// unless that leaves none of its instance types, in which case the instance types that would exceed them are dropped.
+func (d *domainLimits) restrictCapacityTypes(nodePoolName string, requirements scheduling.Requirements, instanceTypes []*cloudprovider.InstanceType) ([]*cloudprovider.InstanceType, error) {
+	capacityTypes := d.capacityTypes(nodePoolName, requirements)
+	if len(capacityTypes) == 0 {
+		return instanceTypes, nil
+	}
+	full := lo.Filter(capacityTypes, func(capacityType string, _ int) bool {
+		return len(filterByRemainingResources(offeredIn(instanceTypes, requirements, capacityType), d.remainingIn(nodePoolName, v1.CapacityTypeLabelKey, capacityType))) == 0
+	})
+	if len(full) > 0 {
+		requirements.Add(scheduling.NewRequirement(v1.CapacityTypeLabelKey, corev1.NodeSelectorOpNotIn, full...))
+	}
+	capacityTypes, _ = lo.Difference(capacityTypes, full)
+	exceeds := func(it *cloudprovider.InstanceType, capacityType string) bool {
+		return len(offeredIn([]*cloudprovider.InstanceType{it}, requirements, capacityType)) == 1 &&
+			len(filterByRemainingResources([]*cloudprovider.InstanceType{it}, d.remainingIn(nodePoolName, v1.CapacityTypeLabelKey, capacityType))) == 0
+	}
+	// an offering fits if its capacity type isn't limited, or if the instance type fits in the capacity type's limits
+	fits := lo.Filter(instanceTypes, func(it *cloudprovider.InstanceType, _ int) bool {
+		unlimited := scheduling.NewRequirements(requirements.Values()...)
+		if len(capacityTypes) > 0 {
+			unlimited.Add(scheduling.NewRequirement(v1.CapacityTypeLabelKey, corev1.NodeSelectorOpNotIn, capacityTypes...))
+		}
+		return it.Offerings.Available().HasCompatible(unlimited) || lo.ContainsBy(capacityTypes, func(capacityType string) bool {
+			return len(offeredIn([]*cloudprovider.InstanceType{it}, requirements, capacityType)) == 1 && !exceeds(it, capacityType)
+		})
+	})
+	exceeded := lo.Filter(capacityTypes, func(capacityType string, _ int) bool {
+		return lo.ContainsBy(fits, func(it *cloudprovider.InstanceType) bool { return exceeds(it, capacityType) })
+	})
+	withoutExceeded := scheduling.NewRequirements(requirements.Values()...)
+	if len(exceeded) > 0 {
+		withoutExceeded.Add(scheduling.NewRequirement(v1.CapacityTypeLabelKey, corev1.NodeSelectorOpNotIn, exceeded...))
+	}
+	if kept := lo.Filter(fits, func(it *cloudprovider.InstanceType, _ int) bool {
+		return it.Offerings.Available().HasCompatible(withoutExceeded)
+	}); len(kept) > 0 {
+		requirements.Add(withoutExceeded.Values()...)
+		return kept, nil
+	}
+	instanceTypes = lo.Filter(instanceTypes, func(it *cloudprovider.InstanceType, _ int) bool {
+		return !lo.ContainsBy(capacityTypes, func(capacityType string) bool { return exceeds(it, capacityType) }) &&
+			it.Offerings.Available().HasCompatible(requirements)
+	})
+	if len(instanceTypes) == 0 {
+		return nil, fmt.Errorf("all available instance types exceed the capacity type limits of nodepool %q", nodePoolName)
+	}
+	return instanceTypes, nil
+}
+
+// offeredIn returns the instance types with an available offering of the capacity type that's compatible with the
+// requirements
+func offeredIn(instanceTypes []*cloudprovider.InstanceType, requirements scheduling.Requirements, capacityType string) []*cloudprovider.InstanceType {
+	inCapacityType := scheduling.NewRequirements(requirements.Values()...)
+	inCapacityType.Add(scheduling.NewRequirement(v1.CapacityTypeLabelKey, corev1.NodeSelectorOpIn, capacityType))
+	return lo.Filter(instanceTypes, func(it *cloudprovider.InstanceType, _ int) bool {
+		return it.Offerings.Available().HasCompatible(inCapacityType)
+	})
+}
+
+// subtract subtracts the largest of the instance types of a new NodeClaim of the NodePool from the remaining resources
+// of the domains that it was restricted to, like the NodePool's limits
+func (d *domainLimits) subtract(nodePoolName string, requirements scheduling.Requirements, instanceTypes []*cloudprovider.InstanceType) {
+	for _, key := range d.keys(nodePoolName) {
+		domain := requirements.Get(key).Any()
+		d.remaining[nodePoolName][key][domain] = subtractMax(d.remainingIn(nodePoolName, key, domain), instanceTypes)
+	}
+	// the NodeClaim could launch as any of its capacity types, so it counts against the limits of each of them
+	for _, capacityType := range d.capacityTypes(nodePoolName, requirements) {
+		d.remaining[nodePoolName][v1.CapacityTypeLabelKey][capacityType] = subtractMax(d.remainingIn(nodePoolName, v1.CapacityTypeLabelKey, capacityType),
+			offeredIn(instanceTypes, requirements, capacityType))
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/samber/lo"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/util/flowcontrol"
 
@@ -31,6 +32,9 @@ import (
 // PodNominationRateLimiter is a pointer so it rate-limits across events
 var PodNominationRateLimiter = flowcontrol.NewTokenBucketRateLimiter(5, 10)
 
+// PodSchedulingGatedRateLimiter is a pointer so it rate-limits across events
+var PodSchedulingGatedRateLimiter = flowcontrol.NewTokenBucketRateLimiter(5, 10)
+
 func NominatePodEvent(pod *corev1.Pod, node *corev1.Node, nodeClaim *v1.NodeClaim) events.Event {
 	var info []string
 	if nodeClaim != nil {
@@ -60,6 +64,19 @@ func NoCompatibleInstanceTypes(np *v1.NodePool) events.Event {
 	}
 }
 
+func PodSchedulingGatedEvent(pod *corev1.Pod) events.Event {
+	return events.Event{
+		InvolvedObject: pod,
+		Type:           corev1.EventTypeNormal,
+		Reason:         "SchedulingGated",
+		Message: fmt.Sprintf("Pod has scheduling gates %s, capacity won't be provisioned until they are removed",
+			strings.Join(lo.Map(pod.Spec.SchedulingGates, func(g corev1.PodSchedulingGate, _ int) string { return g.Name }), ", ")),
+		DedupeValues:  []string{string(pod.UID)},
+		DedupeTimeout: 5 * time.Minute,
+		RateLimiter:   PodSchedulingGatedRateLimiter,
+	}
+}
+
 func PodFailedToScheduleEvent(pod *corev1.Pod, err error) events.Event {
 	return events.Event{
 		InvolvedObject: pod,
@@ -70,3 +87,80 @@ func PodFailedToScheduleEvent(pod *corev1.Pod, err error) events.Event {
 		DedupeTimeout:  5 * time.Minute,
 	}
 }
+
+// PodIncompatibleNodePoolsEvent explains which constraint eliminated each NodePool when a pod failed to schedule,
+// similar to the kube-scheduler's per-plugin failure messages.
+func PodIncompatibleNodePoolsEvent(pod *corev1.Pod, failures []NodePoolFailure) events.Event {
+	return events.Event{
+		InvolvedObject: pod,
+		Type:           corev1.EventTypeWarning,
+		Reason:         "IncompatibleNodePools",
+		Message: fmt.Sprintf("0/%d nodepools are available: %s", len(failures),
+			strings.Join(lo.Map(failures, func(f NodePoolFailure, _ int) string { return f.String() }), "; ")),
+		DedupeValues:  []string{string(pod.UID)},
+		DedupeTimeout: 5 * time.Minute,
+	}
+}
+
+// PodNamespaceNotAllowedEvent explains that NodePools rejected a pod because they're restricted to other namespaces,
+// so that tenants can tell that a NodePool isn't theirs to use rather than that it lacks capacity
+func PodNamespaceNotAllowedEvent(pod *corev1.Pod, nodePoolNames []string) events.Event {
+	return events.Event{
+		InvolvedObject: pod,
+		Type:           corev1.EventTypeWarning,
+		Reason:         "NamespaceNotAllowed",
+		Message:        fmt.Sprintf("Namespace %q isn't allowed by nodepools %s", pod.Namespace, strings.Join(nodePoolNames, ", ")),
+		DedupeValues:   []string{string(pod.UID)},
+		DedupeTimeout:  5 * time.Minute,
+	}
+}
+
+// PodWaitingForNodeEvent explains that no capacity is launched for a pod because it's waiting for an initializing node
+// that it may fit on
+func PodWaitingForNodeEvent(pod *corev1.Pod, nodeName string) events.Event {
+	return events.Event{
+		InvolvedObject: pod,
+		Type:           corev1.EventTypeNormal,
+		Reason:         "WaitingForInitializingNode",
+		Message:        fmt.Sprintf("Waiting for initializing node %q that the pod may fit on", nodeName),
+		DedupeValues:   []string{string(pod.UID)},
+		DedupeTimeout:  5 * time.Minute,
+	}
+}
+
+// PodPreBoundEvent is published when Karpenter binds a pod to the node that was launched for it
+func PodPreBoundEvent(pod *corev1.Pod, node *corev1.Node) events.Event {
+	return events.Event{
+		InvolvedObject: pod,
+		Type:           corev1.EventTypeNormal,
+		Reason:         "PreBound",
+		Message:        fmt.Sprintf("Bound to node/%s that was launched for the pod", node.Name),
+		DedupeValues:   []string{string(pod.UID)},
+	}
+}
+
+// PodNodePoolFallbackEvent explains why a pod is scheduled to a NodePool when NodePools with a higher weight exist
+func PodNodePoolFallbackEvent(pod *corev1.Pod, nodePoolName string, failures []NodePoolFailure) events.Event {
+	return events.Event{
+		InvolvedObject: pod,
+		Type:           corev1.EventTypeNormal,
+		Reason:         "NodePoolFallback",
+		Message: fmt.Sprintf("Falling back to nodepool %q, higher weight nodepools can't satisfy the pod: %s", nodePoolName,
+			strings.Join(lo.Map(failures, func(f NodePoolFailure, _ int) string { return f.String() }), "; ")),
+		DedupeValues:  []string{string(pod.UID)},
+		DedupeTimeout: 5 * time.Minute,
+	}
+}
+
+// PodPreferencesRelaxedEvent explains which preferences of a pod were removed so that it could schedule, as the pod may
+// be placed differently than the kube-scheduler would ideally place it
+func PodPreferencesRelaxedEvent(pod *corev1.Pod, relaxed []string) events.Event {
+	return events.Event{
+		InvolvedObject: pod,
+		Type:           corev1.EventTypeNormal,
+		Reason:         "PreferencesRelaxed",
+		Message:        fmt.Sprintf("Relaxed preferences to schedule pod: %s", strings.Join(relaxed, "; ")),
+		DedupeValues:   []string{string(pod.UID)},
+		DedupeTimeout:  5 * time.Minute,
+	}
+}
@@ -19,12 +19,19 @@ package scheduling
 import (
 	"context"
 	"fmt"
+	"sort"
+	"time"
 
+	"github.com/samber/lo"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	apisv1 "sigs.k8s.io/karpenter/pkg/apis/v1"
 	"sigs.k8s.io/karpenter/pkg/controllers/state"
 	"sigs.k8s.io/karpenter/pkg/scheduling"
+	podutils "sigs.k8s.io/karpenter/pkg/utils/pod"
 	"sigs.k8s.io/karpenter/pkg/utils/resources"
 )
 
@@ -37,6 +44,17 @@ type ExistingNode struct {
 	topology     *Topology
 	requests     v1.ResourceList
 	requirements scheduling.Requirements
+	preempted    sets.Set[types.NamespacedName] // Pods that we expect the kube-scheduler to preempt from this node
+	// allowedNamespaces are the namespaces that the node's NodePool allows, or nil if it allows any namespace
+	allowedNamespaces sets.Set[string]
+	// existingCapacityWindow is how long after the node's NodeClaim is launched that pending pods wait for it to
+	// initialize, rather than triggering new NodeClaims
+	existingCapacityWindow time.Duration
+	// waitingRequests are the requests of the pods that are waiting for the node to initialize, which are reserved
+	// against the node's remaining allocatable resources
+	waitingRequests v1.ResourceList
+	// simulatedPodRequests stand in for the resources that pods don't request when they're fit on the node
+	simulatedPodRequests v1.ResourceList
 }
 
 func NewExistingNode(n *state.StateNode, topology *Topology, taints []v1.Taint, daemonResources v1.ResourceList) *ExistingNode {
@@ -59,15 +77,112 @@ func NewExistingNode(n *state.StateNode, topology *Topology, taints []v1.Taint,
 		topology:        topology,
 		requests:        remainingDaemonResources,
 		requirements:    scheduling.NewLabelRequirements(n.Labels()),
+		preempted:       sets.New[types.NamespacedName](),
 	}
 	node.requirements.Add(scheduling.NewRequirement(v1.LabelHostname, v1.NodeSelectorOpIn, n.HostName()))
 	topology.Register(v1.LabelHostname, n.HostName())
 	return node
 }
 
-func (n *ExistingNode) Add(ctx context.Context, kubeClient client.Client, pod *v1.Pod, podRequests v1.ResourceList) error {
-	// Check Taints
-	if err := scheduling.Taints(n.cachedTaints).Tolerates(pod); err != nil {
+// PrefersNoSchedule returns true if the node has PreferNoSchedule taints that the pod doesn't tolerate, meaning that the
+// kube-scheduler would only bind the pod to the node if it doesn't fit on a node without these taints
+func (n *ExistingNode) PrefersNoSchedule(pod *v1.Pod) bool {
+	return len(scheduling.Taints(n.cachedTaints).WithEffect(v1.TaintEffectPreferNoSchedule).Untolerated(pod)) > 0
+}
+
+// Waitable returns true if the node is initializing within its NodePool's existing capacity window, and the pod is
+// compatible with the node and its requests fit within the node's remaining allocatable resources along with the other
+// pods that are waiting for the node, regardless of the other pods that are expected to schedule to the node. The pod
+// may fit on the node once it initializes, so it can wait for the node rather than triggering a new NodeClaim. The
+// pod's requests are reserved on the node when it's waitable, so that the node doesn't absorb more waiting pods than
+// it can fit.
+func (n *ExistingNode) Waitable(pod *v1.Pod, podData *PodData, now time.Time) bool {
+	if n.Initialized() || n.existingCapacityWindow == 0 || now.Sub(n.launchTime()) > n.existingCapacityWindow {
+		return false
+	}
+	if !n.allowsNamespace(pod) {
+		return false
+	}
+	if err := scheduling.Taints(n.cachedTaints).WithoutEffect(v1.TaintEffectPreferNoSchedule).Tolerates(pod); err != nil {
+		return false
+	}
+	if err := n.requirements.Compatible(podData.StrictRequirements); err != nil {
+		return false
+	}
+	requests := resources.Merge(n.waitingRequests, simulatedRequests(podData.Requests, n.simulatedPodRequests))
+	if !resources.Fits(requests, n.cachedAvailable) {
+		return false
+	}
+	n.waitingRequests = requests
+	return true
+}
+
+// launchTime returns when the node's NodeClaim was created, falling back to when the node was created for nodes without
+// a NodeClaim
+func (n *ExistingNode) launchTime() time.Time {
+	if n.NodeClaim != nil {
+		return n.NodeClaim.CreationTimestamp.Time
+	}
+	return n.Node.CreationTimestamp.Time
+}
+
+func (n *ExistingNode) Add(ctx context.Context, kubeClient client.Client, pod *v1.Pod, podData *PodData) error {
+	return n.add(ctx, kubeClient, pod, podData, n.cachedAvailable)
+}
+
+// Preempt attempts to add the pod to the node assuming that the kube-scheduler will preempt lower priority pods that
+// are bound to the node to make room for it. Victims are chosen from the lowest priority upwards until the pod fits,
+// and are remembered so that they aren't counted twice across multiple preemptors.
+func (n *ExistingNode) Preempt(ctx context.Context, kubeClient client.Client, pod *v1.Pod, podData *PodData) error {
+	if pod.Spec.PreemptionPolicy != nil && *pod.Spec.PreemptionPolicy == v1.PreemptNever {
+		return fmt.Errorf("pod has preemption policy %q", v1.PreemptNever)
+	}
+	// The kube-scheduler can only preempt pods on nodes that have already registered
+	if n.Node == nil || !n.Initialized() {
+		return fmt.Errorf("node is not initialized")
+	}
+	pods, err := n.StateNode.Pods(ctx, kubeClient)
+	if err != nil {
+		return fmt.Errorf("getting pods from node, %w", err)
+	}
+	priority := lo.FromPtr(pod.Spec.Priority)
+	victims := lo.Filter(pods, func(p *v1.Pod, _ int) bool {
+		return lo.FromPtr(p.Spec.Priority) < priority && podutils.IsActive(p) && !podutils.IsOwnedByDaemonSet(p) &&
+			!podutils.IsOwnedByNode(p) && !n.preempted.Has(client.ObjectKeyFromObject(p))
+	})
+	sort.SliceStable(victims, func(i, j int) bool {
+		return lo.FromPtr(victims[i].Spec.Priority) < lo.FromPtr(victims[j].Spec.Priority)
+	})
+	requests := resources.Merge(n.requests, simulatedRequests(podData.Requests, n.simulatedPodRequests))
+	available := n.cachedAvailable
+	var preempted []*v1.Pod
+	for _, victim := range victims {
+		if resources.Fits(requests, available) {
+			break
+		}
+		available = resources.Merge(available, resources.RequestsForPods(victim))
+		preempted = append(preempted, victim)
+	}
+	if len(preempted) == 0 {
+		return fmt.Errorf("no lower priority pods to preempt")
+	}
+	if err = n.add(ctx, kubeClient, pod, podData, available); err != nil {
+		return err
+	}
+	n.cachedAvailable = available
+	for _, p := range preempted {
+		n.preempted.Insert(client.ObjectKeyFromObject(p))
+	}
+	return nil
+}
+
+func (n *ExistingNode) add(ctx context.Context, kubeClient client.Client, pod *v1.Pod, podData *PodData, available v1.ResourceList) error {
+	if !n.allowsNamespace(pod) {
+		return fmt.Errorf("namespace %q isn't allowed by the node's nodepool", pod.Namespace)
+	}
+	// Check Taints. PreferNoSchedule taints don't prevent the kube-scheduler from binding the pod to the node, they
+	// only make the node less preferred, which the scheduler accounts for by trying these nodes last.
+	if err := scheduling.Taints(n.cachedTaints).WithoutEffect(v1.TaintEffectPreferNoSchedule).Tolerates(pod); err != nil {
 		return err
 	}
 	// determine the volumes that will be mounted if the pod schedules
@@ -85,30 +200,25 @@ func (n *ExistingNode) Add(ctx context.Context, kubeClient client.Client, pod *v
 	}
 
 	// check resource requests first since that's a pretty likely reason the pod won't schedule on an in-flight
-	// node, which at this point can't be increased in size
-	requests := resources.Merge(n.requests, podRequests)
+	// node, which at this point can't be increased in size. The node's NodePool's simulated pod requests stand in for the
+	// resources that the pod doesn't request, the same as when the pod is fit on a new NodeClaim.
+	requests := resources.Merge(n.requests, simulatedRequests(podData.Requests, n.simulatedPodRequests))
 
-	if !resources.Fits(requests, n.cachedAvailable) {
+	if !resources.Fits(requests, available) {
 		return fmt.Errorf("exceeds node resources")
 	}
 
 	nodeRequirements := scheduling.NewRequirements(n.requirements.Values()...)
-	podRequirements := scheduling.NewPodRequirements(pod)
 	// Check NodeClaim Affinity Requirements
-	if err = nodeRequirements.Compatible(podRequirements); err != nil {
+	if err = nodeRequirements.Compatible(podData.Requirements); err != nil {
 		return err
 	}
-	nodeRequirements.Add(podRequirements.Values()...)
+	nodeRequirements.Add(podData.Requirements.Values()...)
 
-	strictPodRequirements := podRequirements
-	if scheduling.HasPreferredNodeAffinity(pod) {
-		// strictPodRequirements is important as it ensures we don't inadvertently restrict the possible pod domains by a
-		// preferred node affinity.  Only required node affinities can actually reduce pod domains.
-		strictPodRequirements = scheduling.NewStrictPodRequirements(pod)
-	}
-
-	// Check Topology Requirements
-	topologyRequirements, err := n.topology.AddRequirements(strictPodRequirements, nodeRequirements, pod)
+	// Check Topology Requirements. The strict pod requirements are important as they ensure we don't inadvertently
+	// restrict the possible pod domains by a preferred node affinity.  Only required node affinities can actually reduce
+	// pod domains.
+	topologyRequirements, err := n.topology.AddRequirements(podData.StrictRequirements, nodeRequirements, pod)
 	if err != nil {
 		return err
 	}
@@ -126,3 +236,9 @@ func (n *ExistingNode) Add(ctx context.Context, kubeClient client.Client, pod *v
 	n.VolumeUsage().Add(pod, volumes)
 	return nil
 }
+
+// allowsNamespace returns true if the node's NodePool allows the pod's namespace. The NodePool's own headroom pods
+// belong to the NodePool rather than a namespace, so they're always allowed.
+func (n *ExistingNode) allowsNamespace(pod *v1.Pod) bool {
+	return n.allowedNamespaces == nil || n.allowedNamespaces.Has(pod.Namespace) || isHeadroomPodOf(pod, n.Labels()[apisv1.NodePoolLabelKey])
+}
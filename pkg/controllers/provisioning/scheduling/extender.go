@@ -0,0 +1,162 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduling
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/karpenter/pkg/cloudprovider"
+	"sigs.k8s.io/karpenter/pkg/utils/resources"
+)
+
+// ExtenderTimeout is the maximum amount of time that a scheduling loop waits for the extender to respond
+const ExtenderTimeout = 10 * time.Second
+
+// ExtenderArgs are sent to the extender for each scheduling loop that launches NodeClaims
+type ExtenderArgs struct {
+	NodeClaims []ExtenderNodeClaim `json:"nodeClaims"`
+}
+
+type ExtenderNodeClaim struct {
+	// NodePool is the name of the NodePool that the NodeClaim would be launched for
+	NodePool string `json:"nodePool"`
+	// Pods are the namespace/names of the pods that were scheduled to the NodeClaim
+	Pods []string `json:"pods"`
+	// InstanceTypes are the names of the instance types that the NodeClaim could be launched as
+	InstanceTypes []string `json:"instanceTypes"`
+}
+
+// ExtenderResult is the extender's response, with a result for each of the NodeClaims of the ExtenderArgs in the same
+// order
+type ExtenderResult struct {
+	NodeClaims []ExtenderNodeClaimResult `json:"nodeClaims"`
+}
+
+type ExtenderNodeClaimResult struct {
+	// InstanceTypes are the instance types that passed the extender's filter. If unset, all of the instance types pass.
+	InstanceTypes []string `json:"instanceTypes,omitempty"`
+	// Scores are the extender's preference for each instance type. Only the instance types with the highest score are
+	// launched, and the cheapest of them is chosen as usual. Instance types without a score have a score of 0.
+	Scores map[string]int64 `json:"scores,omitempty"`
+}
+
+// Extender is an out-of-process extension point, similar to kube-scheduler extenders, that filters and scores the
+// instance types of the NodeClaims that a scheduling loop would launch. It allows placement policies that Karpenter
+// doesn't model to be enforced without forking Karpenter.
+type Extender struct {
+	url    string
+	client *http.Client
+}
+
+// NewExtender returns an extender that calls the url with the client, which should be shared across scheduling loops
+// so that its connections are reused
+func NewExtender(client *http.Client, url string) *Extender {
+	return &Extender{url: url, client: client}
+}
+
+// Extend calls the extender with the new NodeClaims of the results, and removes the instance type options that the
+// extender filtered out or didn't score the highest. Pods of NodeClaims without any remaining instance types fail to
+// schedule. An error is returned if the extender can't be reached, so that placement policies are never bypassed.
+func (e *Extender) Extend(ctx context.Context, r Results) (Results, error) {
+	if len(r.NewNodeClaims) == 0 {
+		return r, nil
+	}
+	args := ExtenderArgs{NodeClaims: lo.Map(r.NewNodeClaims, func(n *NodeClaim, _ int) ExtenderNodeClaim {
+		return ExtenderNodeClaim{
+			NodePool: n.NodePoolName,
+			Pods: lo.FilterMap(n.Pods, func(p *corev1.Pod, _ int) (string, bool) {
+				return klog.KObj(p).String(), !IsHeadroomPod(p)
+			}),
+			InstanceTypes: lo.Map(n.InstanceTypeOptions, func(it *cloudprovider.InstanceType, _ int) string { return it.Name }),
+		}
+	})}
+	result, err := e.call(ctx, args)
+	if err != nil {
+		return Results{}, fmt.Errorf("calling scheduling extender, %w", err)
+	}
+	if len(result.NodeClaims) != len(args.NodeClaims) {
+		return Results{}, fmt.Errorf("calling scheduling extender, expected %d nodeclaim results, got %d", len(args.NodeClaims), len(result.NodeClaims))
+	}
+	var nodeClaims []*NodeClaim
+	for i, n := range r.NewNodeClaims {
+		if n.removeExtenderRejections(result.NodeClaims[i]) {
+			nodeClaims = append(nodeClaims, n)
+			continue
+		}
+		for _, pod := range n.Pods {
+			r.PodErrors[pod] = fmt.Errorf("pod didn’t schedule because the scheduling extender rejected all of the instance types of NodePool %q", n.NodePoolName)
+		}
+	}
+	r.NewNodeClaims = nodeClaims
+	return r, nil
+}
+
+func (e *Extender) call(ctx context.Context, args ExtenderArgs) (ExtenderResult, error) {
+	body, err := json.Marshal(args)
+	if err != nil {
+		return ExtenderResult{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return ExtenderResult{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return ExtenderResult{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return ExtenderResult{}, fmt.Errorf("unexpected status %q", resp.Status)
+	}
+	result := ExtenderResult{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return ExtenderResult{}, fmt.Errorf("decoding response, %w", err)
+	}
+	return result, nil
+}
+
+// removeExtenderRejections removes the instance type options that the extender filtered out or scored lower than the
+// best scored option, returning false if no options remain
+func (n *NodeClaim) removeExtenderRejections(result ExtenderNodeClaimResult) bool {
+	kept := n.InstanceTypeOptions
+	if result.InstanceTypes != nil {
+		passed := sets.New(result.InstanceTypes...)
+		kept = lo.Filter(kept, func(it *cloudprovider.InstanceType, _ int) bool { return passed.Has(it.Name) })
+	}
+	if len(result.Scores) > 0 && len(kept) > 0 {
+		best := lo.Max(lo.Map(kept, func(it *cloudprovider.InstanceType, _ int) int64 { return result.Scores[it.Name] }))
+		kept = lo.Filter(kept, func(it *cloudprovider.InstanceType, _ int) bool { return result.Scores[it.Name] == best })
+	}
+	n.rejectedByExtender.Insert(lo.Map(lo.Without(n.InstanceTypeOptions, kept...), func(it *cloudprovider.InstanceType, _ int) string { return it.Name })...)
+	n.InstanceTypeOptions = kept
+	if len(kept) == 0 {
+		return false
+	}
+	n.Spec.Resources.Requests = resources.Merge(n.daemonOverhead.min(kept), n.requests)
+	return true
+}
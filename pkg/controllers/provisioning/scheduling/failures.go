@@ -0,0 +1,172 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduling
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/samber/lo"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"sigs.k8s.io/karpenter/pkg/cloudprovider"
+	"sigs.k8s.io/karpenter/pkg/scheduling"
+)
+
+// FailureReason is the kind of constraint that prevented a pod from being added to a NodeClaim
+type FailureReason string
+
+const (
+	FailureReasonLimits       FailureReason = "limits"
+	FailureReasonTaint        FailureReason = "taint"
+	FailureReasonHostPort     FailureReason = "host-port"
+	FailureReasonRequirement  FailureReason = "requirement"
+	FailureReasonVolume       FailureReason = "volume"
+	FailureReasonTopology     FailureReason = "topology"
+	FailureReasonResources    FailureReason = "resources"
+	FailureReasonOffering     FailureReason = "offering"
+	FailureReasonInstanceType FailureReason = "instance-type"
+	FailureReasonNamespace    FailureReason = "namespace"
+	// FailureReasonPrice is only used for instance types that satisfied a NodeClaim, but were dropped from its instance
+	// type options in favor of cheaper instance types
+	FailureReasonPrice FailureReason = "price"
+	// FailureReasonFragmentation is only used for instance types that satisfied a NodeClaim, but were dropped from its
+	// instance type options as they would leave a small amount of cpu unused that no other pod could use
+	FailureReasonFragmentation FailureReason = "fragmentation"
+	// FailureReasonExtender is only used for instance types that satisfied a NodeClaim, but were filtered out or scored
+	// lower than the other instance types by the scheduling extender
+	FailureReasonExtender FailureReason = "extender"
+)
+
+// SchedulingError is returned when a pod can't be added to a NodeClaim. It identifies the constraint that prevented it,
+// while preserving the original error message.
+type SchedulingError struct {
+	Reason FailureReason
+	// Details identify the constraint within the reason, e.g. requirement keys, taints or resource names
+	Details []string
+	err     error
+	// rejectedInstanceTypes resolves why each instance type was rejected when the error is from filtering the instance
+	// types. It's resolved lazily as it's only needed for scheduling snapshots.
+	rejectedInstanceTypes func() map[string]FailureReason
+}
+
+func NewSchedulingError(reason FailureReason, details []string, err error) *SchedulingError {
+	return &SchedulingError{Reason: reason, Details: details, err: err}
+}
+
+func (e *SchedulingError) Error() string {
+	return e.err.Error()
+}
+
+func (e *SchedulingError) Unwrap() error {
+	return e.err
+}
+
+// NodePoolFailure explains why a pod couldn't be scheduled to a new NodeClaim for a NodePool
+type NodePoolFailure struct {
+	NodePoolName string
+	Reason       FailureReason
+	Details      []string
+}
+
+func (f NodePoolFailure) String() string {
+	if len(f.Details) == 0 {
+		return fmt.Sprintf("nodepool %q: %s", f.NodePoolName, f.Reason)
+	}
+	return fmt.Sprintf("nodepool %q: %s %s", f.NodePoolName, f.Reason, strings.Join(f.Details, ", "))
+}
+
+// NodePoolsError is returned when a pod couldn't be scheduled to a new NodeClaim for any of the NodePools
+type NodePoolsError struct {
+	Failures []NodePoolFailure
+	// rejectedInstanceTypes resolves why each instance type of a NodePool was rejected, by NodePool name
+	rejectedInstanceTypes map[string]func() map[string]FailureReason
+	err                   error
+}
+
+func (e *NodePoolsError) Error() string {
+	return e.err.Error()
+}
+
+func (e *NodePoolsError) Unwrap() error {
+	return e.err
+}
+
+func newNodePoolFailure(nodePoolName string, err error) NodePoolFailure {
+	var schedulingErr *SchedulingError
+	if errors.As(err, &schedulingErr) {
+		return NodePoolFailure{NodePoolName: nodePoolName, Reason: schedulingErr.Reason, Details: schedulingErr.Details}
+	}
+	return NodePoolFailure{NodePoolName: nodePoolName}
+}
+
+// NodePoolFailures returns the failure for each NodePool that explains why a pod failed to schedule, if known
+func NodePoolFailures(err error) []NodePoolFailure {
+	var nodePoolsErr *NodePoolsError
+	if errors.As(err, &nodePoolsErr) {
+		return nodePoolsErr.Failures
+	}
+	return nil
+}
+
+// RejectedInstanceTypes returns why each instance type of each NodePool couldn't launch a NodeClaim for the pod, by
+// NodePool name and then instance type name, for the NodePools that the pod failed to schedule to because of their
+// instance types
+func RejectedInstanceTypes(err error) map[string]map[string]FailureReason {
+	var nodePoolsErr *NodePoolsError
+	if !errors.As(err, &nodePoolsErr) || len(nodePoolsErr.rejectedInstanceTypes) == 0 {
+		return nil
+	}
+	return lo.MapValues(nodePoolsErr.rejectedInstanceTypes, func(resolve func() map[string]FailureReason, _ string) map[string]FailureReason {
+		return resolve()
+	})
+}
+
+// instanceTypeFailure identifies the constraint that filtered out all instance types. Only the first failing criteria
+// is reported, in the same order of precedence as filterResults.FailureReason.
+func instanceTypeFailure(instanceTypes []*cloudprovider.InstanceType, requirements scheduling.Requirements, filtered filterResults) (FailureReason, []string) {
+	switch {
+	case filtered.minValuesIncompatibleErr != nil:
+		return FailureReasonRequirement, lo.Filter(sets.List(requirements.Keys()), func(key string, _ int) bool {
+			return requirements.Get(key).MinValues != nil
+		})
+	case !filtered.requirementsMet:
+		keys := sets.New[string]()
+		for _, it := range instanceTypes {
+			keys.Insert(scheduling.IncompatibleKeys(it.Requirements.Intersects(requirements))...)
+		}
+		return FailureReasonRequirement, sets.List(keys)
+	case !filtered.fits:
+		// report the resources that don't fit on any instance type on their own, or all of them if it's only their
+		// combination that doesn't fit
+		names := lo.Filter(lo.Keys(filtered.requests), func(name v1.ResourceName, _ int) bool {
+			return !lo.ContainsBy(instanceTypes, func(it *cloudprovider.InstanceType) bool {
+				return fits(it, v1.ResourceList{name: filtered.requests[name]}, filtered.allocatable)
+			})
+		})
+		if len(names) == 0 {
+			names = lo.Keys(filtered.requests)
+		}
+		return FailureReasonResources, sets.List(sets.New(lo.Map(names, func(name v1.ResourceName, _ int) string { return string(name) })...))
+	case !filtered.hasOffering:
+		return FailureReasonOffering, nil
+	default:
+		return FailureReasonInstanceType, nil
+	}
+}
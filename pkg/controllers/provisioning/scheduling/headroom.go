@@ -0,0 +1,234 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduling
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/karpenter/pkg/apis"
+	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+	"sigs.k8s.io/karpenter/pkg/controllers/state"
+	"sigs.k8s.io/karpenter/pkg/scheduling"
+)
+
+// headroomAnnotationKey marks the in-memory pods that represent NodePool headroom, including the minimum number of
+// nodes and the minimum number of nodes per zone, with the name of the NodePool that they belong to. These pods are
+// never persisted to the API server.
+const headroomAnnotationKey = apis.Group + "/headroom"
+
+// headroomNamespace is the namespace of the in-memory pods that represent NodePool headroom. These pods belong to the
+// NodePool rather than to a namespace, but scheduling expects every pod to have one, so they're placed in the namespace
+// of the cluster's system components.
+const headroomNamespace = metav1.NamespaceSystem
+
+// statefulSetReplicaAnnotationKey marks the in-memory pods for the replicas that scaling StatefulSets are expected to
+// create, with the name of the StatefulSet. Unlike headroom, these pods belong to the StatefulSet's namespace.
+const statefulSetReplicaAnnotationKey = apis.Group + "/statefulset-replica"
+
+// minNodesLabelKey selects the in-memory pods that keep the minimum number of nodes of a NodePool
+const minNodesLabelKey = apis.Group + "/min-nodes"
+
+// minNodesPerZoneLabelKey selects the in-memory pods that keep the minimum number of nodes of a NodePool in each zone
+const minNodesPerZoneLabelKey = apis.Group + "/min-nodes-per-zone"
+
+// NewHeadroomPods returns the in-memory pods that represent the headroom of the NodePool. The headroom is split
+// into chunks of at most one CPU so that it can be spread across nodes rather than requiring a single node large
+// enough to hold all of it.
+func NewHeadroomPods(nodePool *v1.NodePool) []*corev1.Pod {
+	if len(nodePool.Spec.Headroom) == 0 {
+		return nil
+	}
+	chunks := int64(1)
+	if cpu, ok := nodePool.Spec.Headroom[corev1.ResourceCPU]; ok && cpu.MilliValue() > 1000 {
+		chunks = int64(math.Ceil(float64(cpu.MilliValue()) / 1000))
+	}
+	requests := corev1.ResourceList{}
+	for name, quantity := range nodePool.Spec.Headroom {
+		requests[name] = *resource.NewMilliQuantity((quantity.MilliValue()+chunks-1)/chunks, quantity.Format)
+	}
+	tolerations := headroomTolerations(nodePool)
+	pods := make([]*corev1.Pod, 0, chunks)
+	for i := range chunks {
+		name := fmt.Sprintf("%s-headroom-%d", nodePool.Name, i)
+		pods = append(pods, &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   headroomNamespace,
+				UID:         types.UID(fmt.Sprintf("%s-headroom-%d", nodePool.UID, i)),
+				Annotations: map[string]string{headroomAnnotationKey: nodePool.Name},
+			},
+			Spec: corev1.PodSpec{
+				NodeSelector: map[string]string{v1.NodePoolLabelKey: nodePool.Name},
+				Tolerations:  tolerations,
+				Containers: []corev1.Container{{
+					Name:      "headroom",
+					Resources: corev1.ResourceRequirements{Requests: requests.DeepCopy()},
+				}},
+			},
+			// Headroom pods are treated as pending pods, so failing to fit the headroom doesn't block disruption
+			Status: corev1.PodStatus{
+				Phase: corev1.PodPending,
+				Conditions: []corev1.PodCondition{{
+					Type:   corev1.PodScheduled,
+					Status: corev1.ConditionFalse,
+					Reason: corev1.PodReasonUnschedulable,
+				}},
+			},
+		})
+	}
+	return pods
+}
+
+// NewMinNodesPerZonePods returns the in-memory pods that keep the minimum number of nodes of the NodePool in each of
+// the zones. Each pod requires its own node in its zone through hostname anti-affinity with the other pods of the
+// NodePool. The pods for nodes that already exist are treated as running pods so that disruption can't take the zone
+// below the minimum, while the pods for missing nodes are treated as pending pods so that a zone that can't reach the
+// minimum doesn't block disruption.
+func NewMinNodesPerZonePods(nodePool *v1.NodePool, zones []string, nodesPerZone map[string]int) []*corev1.Pod {
+	if nodePool.Spec.MinNodesPerZone == nil {
+		return nil
+	}
+	var pods []*corev1.Pod
+	for _, zone := range zones {
+		for i := range int(*nodePool.Spec.MinNodesPerZone) {
+			pods = append(pods, newMinNodesPod(nodePool, fmt.Sprintf("%s-min-nodes-%d", zone, i), minNodesPerZoneLabelKey,
+				map[string]string{v1.NodePoolLabelKey: nodePool.Name, corev1.LabelTopologyZone: zone}, i >= nodesPerZone[zone]))
+		}
+	}
+	return pods
+}
+
+// NewMinNodesPods returns the in-memory pods that keep the minimum number of nodes of the NodePool. Like the pods for
+// the minimum nodes per zone, each pod requires its own node and only the pods for missing nodes are pending.
+func NewMinNodesPods(nodePool *v1.NodePool, nodes int) []*corev1.Pod {
+	if nodePool.Spec.MinNodes == nil {
+		return nil
+	}
+	pods := make([]*corev1.Pod, 0, *nodePool.Spec.MinNodes)
+	for i := range int(*nodePool.Spec.MinNodes) {
+		pods = append(pods, newMinNodesPod(nodePool, fmt.Sprintf("min-nodes-%d", i), minNodesLabelKey,
+			map[string]string{v1.NodePoolLabelKey: nodePool.Name}, i >= nodes))
+	}
+	return pods
+}
+
+// newMinNodesPod returns an in-memory pod that requires a node of its own from the NodePool, through hostname
+// anti-affinity with the other pods that have the same label
+func newMinNodesPod(nodePool *v1.NodePool, suffix string, labelKey string, nodeSelector map[string]string, pending bool) *corev1.Pod {
+	selector := map[string]string{labelKey: nodePool.Name}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        fmt.Sprintf("%s-%s", nodePool.Name, suffix),
+			Namespace:   headroomNamespace,
+			UID:         types.UID(fmt.Sprintf("%s-%s", nodePool.UID, suffix)),
+			Labels:      selector,
+			Annotations: map[string]string{headroomAnnotationKey: nodePool.Name},
+		},
+		Spec: corev1.PodSpec{
+			NodeSelector: nodeSelector,
+			Tolerations:  headroomTolerations(nodePool),
+			Affinity: &corev1.Affinity{PodAntiAffinity: &corev1.PodAntiAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{
+					LabelSelector: &metav1.LabelSelector{MatchLabels: selector},
+					TopologyKey:   corev1.LabelHostname,
+				}},
+			}},
+			Containers: []corev1.Container{{Name: "min-nodes"}},
+		},
+	}
+	if pending {
+		pod.Status = corev1.PodStatus{
+			Phase: corev1.PodPending,
+			Conditions: []corev1.PodCondition{{
+				Type:   corev1.PodScheduled,
+				Status: corev1.ConditionFalse,
+				Reason: corev1.PodReasonUnschedulable,
+			}},
+		}
+	}
+	return pod
+}
+
+// NodeCount returns the number of nodes of the NodePool, ignoring the nodes that are being deleted
+func NodeCount(nodes state.StateNodes, nodePoolName string) int {
+	return lo.CountBy(nodes, func(n *state.StateNode) bool {
+		return !n.MarkedForDeletion() && n.Labels()[v1.NodePoolLabelKey] == nodePoolName
+	})
+}
+
+// NodesPerZone returns the number of nodes of the NodePool in each zone, ignoring the nodes that are being deleted.
+// In-flight NodeClaims that aren't labeled with their zone yet are counted in the zone of their zone requirement, if it
+// requires a single zone.
+func NodesPerZone(nodes state.StateNodes, nodePoolName string) map[string]int {
+	nodesPerZone := map[string]int{}
+	for _, n := range nodes {
+		if n.MarkedForDeletion() || n.Labels()[v1.NodePoolLabelKey] != nodePoolName {
+			continue
+		}
+		if zone, ok := n.Labels()[corev1.LabelTopologyZone]; ok {
+			nodesPerZone[zone]++
+			continue
+		}
+		if n.NodeClaim == nil {
+			continue
+		}
+		if zone := scheduling.NewNodeSelectorRequirementsWithMinValues(n.NodeClaim.Spec.Requirements...).Get(corev1.LabelTopologyZone); zone.Operator() == corev1.NodeSelectorOpIn && zone.Len() == 1 {
+			nodesPerZone[zone.Any()]++
+		}
+	}
+	return nodesPerZone
+}
+
+func headroomTolerations(nodePool *v1.NodePool) []corev1.Toleration {
+	var tolerations []corev1.Toleration
+	for _, taint := range nodePool.Spec.Template.Spec.Taints {
+		tolerations = append(tolerations, corev1.Toleration{
+			Key:      taint.Key,
+			Operator: corev1.TolerationOpEqual,
+			Value:    taint.Value,
+			Effect:   taint.Effect,
+		})
+	}
+	// the values of taint templates aren't known until the headroom's nodes register
+	for _, taint := range nodePool.Spec.Template.Spec.TaintTemplates {
+		tolerations = append(tolerations, corev1.Toleration{
+			Key:      taint.Key,
+			Operator: corev1.TolerationOpExists,
+			Effect:   taint.Effect,
+		})
+	}
+	return tolerations
+}
+
+// IsHeadroomPod returns true if the pod represents headroom or an expected StatefulSet replica rather than a real pod
+func IsHeadroomPod(pod *corev1.Pod) bool {
+	_, headroom := pod.Annotations[headroomAnnotationKey]
+	_, replica := pod.Annotations[statefulSetReplicaAnnotationKey]
+	return headroom || replica
+}
+
+// isHeadroomPodOf returns true if the pod represents the headroom of the NodePool
+func isHeadroomPodOf(pod *corev1.Pod, nodePoolName string) bool {
+	return pod.Annotations[headroomAnnotationKey] == nodePoolName && nodePoolName != ""
+}
@@ -19,6 +19,7 @@ package scheduling_test
 import (
 	"fmt"
 	"math/rand"
+	"time"
 
 	"github.com/mitchellh/hashstructure/v2"
 	. "github.com/onsi/ginkgo/v2"
@@ -27,11 +28,15 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
 
 	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
 	"sigs.k8s.io/karpenter/pkg/cloudprovider"
 	"sigs.k8s.io/karpenter/pkg/cloudprovider/fake"
+	"sigs.k8s.io/karpenter/pkg/controllers/provisioning"
 	"sigs.k8s.io/karpenter/pkg/controllers/provisioning/scheduling"
+	"sigs.k8s.io/karpenter/pkg/events"
+	"sigs.k8s.io/karpenter/pkg/operator/options"
 	scheduler "sigs.k8s.io/karpenter/pkg/scheduling"
 	"sigs.k8s.io/karpenter/pkg/test"
 	. "sigs.k8s.io/karpenter/pkg/test/expectations"
@@ -1563,4 +1568,424 @@ var _ = Describe("Instance Type Selection", func() {
 			Expect(len(supportedInstanceTypes(cloudProvider.CreateCalls[0]))).To(BeNumerically(">=", 2))
 		})
 	})
+	Context("Truncation", func() {
+		BeforeEach(func() {
+			cloudProvider.InstanceTypes = fake.InstanceTypes(10)
+		})
+		It("should launch with at most maxInstanceTypes instance types", func() {
+			nodePool.Spec.InstanceTypeTruncation = &v1.InstanceTypeTruncation{MaxInstanceTypes: lo.ToPtr[int32](3)}
+			ExpectApplied(ctx, env.Client, nodePool)
+			pod := test.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			Expect(cloudProvider.CreateCalls).To(HaveLen(1))
+			Expect(lo.Map(supportedInstanceTypes(cloudProvider.CreateCalls[0]), func(it *cloudprovider.InstanceType, _ int) string { return it.Name })).
+				To(ConsistOf("fake-it-0", "fake-it-1", "fake-it-2"))
+		})
+		It("should launch with the instance types in the cheapest price percentile", func() {
+			nodePool.Spec.InstanceTypeTruncation = &v1.InstanceTypeTruncation{
+				Strategy:        v1.InstanceTypeTruncationStrategyPricePercentile,
+				PricePercentile: lo.ToPtr[int32](20),
+			}
+			ExpectApplied(ctx, env.Client, nodePool)
+			pod := test.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			Expect(cloudProvider.CreateCalls).To(HaveLen(1))
+			Expect(lo.Map(supportedInstanceTypes(cloudProvider.CreateCalls[0]), func(it *cloudprovider.InstanceType, _ int) string { return it.Name })).
+				To(ConsistOf("fake-it-0", "fake-it-1"))
+		})
+		It("should launch with diverse instance types to satisfy minValues", func() {
+			// The cheapest instance types are all amd64, so only selecting the cheapest instance types can't satisfy minValues
+			cloudProvider.InstanceTypes = nil
+			for i := 0; i < 10; i++ {
+				cloudProvider.InstanceTypes = append(cloudProvider.InstanceTypes, fake.NewInstanceType(fake.InstanceTypeOptions{
+					Name:         fmt.Sprintf("instance-type-%d", i),
+					Architecture: lo.Ternary(i < 8, v1.ArchitectureAmd64, v1.ArchitectureArm64),
+					Resources: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse(fmt.Sprintf("%d", i+1)),
+						corev1.ResourceMemory: resource.MustParse(fmt.Sprintf("%dGi", i+1)),
+					},
+				}))
+			}
+			nodePool.Spec.Template.Spec.Requirements = []v1.NodeSelectorRequirementWithMinValues{
+				{
+					NodeSelectorRequirement: corev1.NodeSelectorRequirement{
+						Key:      corev1.LabelArchStable,
+						Operator: corev1.NodeSelectorOpExists,
+					},
+					MinValues: lo.ToPtr(2),
+				},
+			}
+			nodePool.Spec.InstanceTypeTruncation = &v1.InstanceTypeTruncation{
+				MaxInstanceTypes: lo.ToPtr[int32](3),
+				Strategy:         v1.InstanceTypeTruncationStrategyDiverse,
+			}
+			ExpectApplied(ctx, env.Client, nodePool)
+			pod := test.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			Expect(cloudProvider.CreateCalls).To(HaveLen(1))
+			Expect(lo.Map(supportedInstanceTypes(cloudProvider.CreateCalls[0]), func(it *cloudprovider.InstanceType, _ int) string { return it.Name })).
+				To(ConsistOf("instance-type-0", "instance-type-1", "instance-type-8"))
+		})
+		It("should fail to schedule when the cheapest instance types can't satisfy minValues", func() {
+			cloudProvider.InstanceTypes = nil
+			for i := 0; i < 10; i++ {
+				cloudProvider.InstanceTypes = append(cloudProvider.InstanceTypes, fake.NewInstanceType(fake.InstanceTypeOptions{
+					Name:         fmt.Sprintf("instance-type-%d", i),
+					Architecture: lo.Ternary(i < 8, v1.ArchitectureAmd64, v1.ArchitectureArm64),
+					Resources: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse(fmt.Sprintf("%d", i+1)),
+						corev1.ResourceMemory: resource.MustParse(fmt.Sprintf("%dGi", i+1)),
+					},
+				}))
+			}
+			nodePool.Spec.Template.Spec.Requirements = []v1.NodeSelectorRequirementWithMinValues{
+				{
+					NodeSelectorRequirement: corev1.NodeSelectorRequirement{
+						Key:      corev1.LabelArchStable,
+						Operator: corev1.NodeSelectorOpExists,
+					},
+					MinValues: lo.ToPtr(2),
+				},
+			}
+			nodePool.Spec.InstanceTypeTruncation = &v1.InstanceTypeTruncation{MaxInstanceTypes: lo.ToPtr[int32](3)}
+			ExpectApplied(ctx, env.Client, nodePool)
+			pod := test.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectNotScheduled(ctx, env.Client, pod)
+		})
+	})
+	Context("MaxPricePercentile", func() {
+		BeforeEach(func() {
+			cloudProvider.InstanceTypes = fake.InstanceTypes(10)
+		})
+		It("should only consider the instance types in the cheapest price percentile", func() {
+			nodePool.Spec.MaxPricePercentile = lo.ToPtr[int32](30)
+			ExpectApplied(ctx, env.Client, nodePool)
+			pod := test.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			Expect(cloudProvider.CreateCalls).To(HaveLen(1))
+			Expect(lo.Map(supportedInstanceTypes(cloudProvider.CreateCalls[0]), func(it *cloudprovider.InstanceType, _ int) string { return it.Name })).
+				To(ConsistOf("fake-it-0", "fake-it-1", "fake-it-2"))
+		})
+		It("should take the percentile of the instance types that are compatible with the pods", func() {
+			nodePool.Spec.MaxPricePercentile = lo.ToPtr[int32](30)
+			ExpectApplied(ctx, env.Client, nodePool)
+			// Only fake-it-4 through fake-it-9 have enough CPU for the pod
+			pod := test.UnschedulablePod(test.PodOptions{ResourceRequirements: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4.5")},
+			}})
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			Expect(cloudProvider.CreateCalls).To(HaveLen(1))
+			Expect(lo.Map(supportedInstanceTypes(cloudProvider.CreateCalls[0]), func(it *cloudprovider.InstanceType, _ int) string { return it.Name })).
+				To(ConsistOf("fake-it-4", "fake-it-5"))
+		})
+		It("should not compound the percentile as pods are added to a NodeClaim", func() {
+			nodePool.Spec.MaxPricePercentile = lo.ToPtr[int32](50)
+			ExpectApplied(ctx, env.Client, nodePool)
+			pods := lo.Times(2, func(_ int) *corev1.Pod {
+				return test.UnschedulablePod(test.PodOptions{ResourceRequirements: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+				}})
+			})
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pods...)
+			Expect(ExpectScheduled(ctx, env.Client, pods[0]).Name).To(Equal(ExpectScheduled(ctx, env.Client, pods[1]).Name))
+			Expect(cloudProvider.CreateCalls).To(HaveLen(1))
+			Expect(lo.Map(supportedInstanceTypes(cloudProvider.CreateCalls[0]), func(it *cloudprovider.InstanceType, _ int) string { return it.Name })).
+				To(ConsistOf("fake-it-2", "fake-it-3", "fake-it-4", "fake-it-5"))
+		})
+	})
+	Context("Fragmentation Threshold", func() {
+		BeforeEach(func() {
+			cloudProvider.InstanceTypes = fake.InstanceTypes(5)
+			ctx = options.ToContext(ctx, test.Options(test.OptionsFields{FragmentationThreshold: lo.ToPtr("500m")}))
+		})
+		AfterEach(func() {
+			ctx = options.ToContext(ctx, test.Options())
+		})
+		It("should not consider instance types that would strand cpu", func() {
+			ExpectApplied(ctx, env.Client, nodePool)
+			// fake-it-1 has 1.9 cpu allocatable, which would leave 0.4 cpu unused
+			pod := test.UnschedulablePod(test.PodOptions{ResourceRequirements: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1.5")},
+			}})
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			Expect(cloudProvider.CreateCalls).To(HaveLen(1))
+			Expect(lo.Map(supportedInstanceTypes(cloudProvider.CreateCalls[0]), func(it *cloudprovider.InstanceType, _ int) string { return it.Name })).
+				To(ConsistOf("fake-it-2", "fake-it-3", "fake-it-4"))
+		})
+		It("should consider instance types that would strand cpu if no other instance types fit", func() {
+			ExpectApplied(ctx, env.Client, nodePool)
+			// only fake-it-4 fits the pod, and it would leave 0.4 cpu unused
+			pod := test.UnschedulablePod(test.PodOptions{ResourceRequirements: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4.5")},
+			}})
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			Expect(cloudProvider.CreateCalls).To(HaveLen(1))
+			Expect(lo.Map(supportedInstanceTypes(cloudProvider.CreateCalls[0]), func(it *cloudprovider.InstanceType, _ int) string { return it.Name })).
+				To(ConsistOf("fake-it-4"))
+		})
+	})
+	Context("Image Filesystem Overhead", func() {
+		BeforeEach(func() {
+			cloudProvider.InstanceTypes = []*cloudprovider.InstanceType{
+				fake.NewInstanceType(fake.InstanceTypeOptions{
+					Name: "small-disk-with-images",
+					Resources: corev1.ResourceList{
+						corev1.ResourceCPU:              resource.MustParse("2"),
+						corev1.ResourceEphemeralStorage: resource.MustParse("20Gi"),
+					},
+					ImageFilesystemOverhead: corev1.ResourceList{corev1.ResourceEphemeralStorage: resource.MustParse("15Gi")},
+				}),
+				fake.NewInstanceType(fake.InstanceTypeOptions{
+					Name: "large-disk-with-images",
+					Resources: corev1.ResourceList{
+						corev1.ResourceCPU:              resource.MustParse("4"),
+						corev1.ResourceEphemeralStorage: resource.MustParse("40Gi"),
+					},
+					ImageFilesystemOverhead: corev1.ResourceList{corev1.ResourceEphemeralStorage: resource.MustParse("15Gi")},
+				}),
+			}
+		})
+		It("should subtract the image filesystem overhead from the allocatable ephemeral storage", func() {
+			ExpectResources(corev1.ResourceList{corev1.ResourceEphemeralStorage: resource.MustParse("5Gi")}, cloudProvider.InstanceTypes[0].Allocatable())
+		})
+		It("should not launch instance types whose disk is mostly consumed by images", func() {
+			ExpectApplied(ctx, env.Client, nodePool)
+			pod := test.UnschedulablePod(test.PodOptions{ResourceRequirements: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceEphemeralStorage: resource.MustParse("10Gi")},
+			}})
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			node := ExpectScheduled(ctx, env.Client, pod)
+			Expect(node.Labels).To(HaveKeyWithValue(corev1.LabelInstanceTypeStable, "large-disk-with-images"))
+		})
+		It("should keep the image filesystem overhead when the kubelet configuration overrides the overhead", func() {
+			its := cloudprovider.InstanceTypes(cloudProvider.InstanceTypes).WithKubeletConfiguration(&v1.KubeletConfiguration{
+				KubeReserved: map[string]string{string(corev1.ResourceCPU): "200m"},
+			})
+			ExpectResources(corev1.ResourceList{corev1.ResourceEphemeralStorage: resource.MustParse("5Gi")}, its[0].Allocatable())
+		})
+	})
+	Context("Node Overlays", func() {
+		var small, large *cloudprovider.InstanceType
+		BeforeEach(func() {
+			small = fake.NewInstanceType(fake.InstanceTypeOptions{
+				Name:      "small",
+				Resources: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+			})
+			large = fake.NewInstanceType(fake.InstanceTypeOptions{
+				Name:      "large",
+				Resources: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")},
+			})
+			cloudProvider.InstanceTypes = []*cloudprovider.InstanceType{small, large}
+		})
+		It("should only adjust the price of the selected offerings", func() {
+			its := cloudprovider.InstanceTypes(cloudProvider.InstanceTypes).WithOverlays([]*v1.NodeOverlay{test.NodeOverlay(v1.NodeOverlay{
+				Spec: v1.NodeOverlaySpec{
+					Requirements: []corev1.NodeSelectorRequirement{
+						{Key: corev1.LabelInstanceTypeStable, Operator: corev1.NodeSelectorOpIn, Values: []string{"small"}},
+						{Key: v1.CapacityTypeLabelKey, Operator: corev1.NodeSelectorOpIn, Values: []string{v1.CapacityTypeSpot}},
+					},
+					PriceAdjustment: lo.ToPtr("-50%"),
+				},
+			})})
+			for i, o := range its[0].Offerings {
+				if o.Requirements.Get(v1.CapacityTypeLabelKey).Any() == v1.CapacityTypeSpot {
+					Expect(o.Price).To(BeNumerically("~", small.Offerings[i].Price/2))
+				} else {
+					Expect(o.Price).To(Equal(small.Offerings[i].Price))
+				}
+			}
+			Expect(its[1]).To(BeIdenticalTo(large))
+		})
+		It("should take each value from the overlay with the highest weight that sets it", func() {
+			its := cloudprovider.InstanceTypes(cloudProvider.InstanceTypes).WithOverlays([]*v1.NodeOverlay{
+				test.NodeOverlay(v1.NodeOverlay{Spec: v1.NodeOverlaySpec{
+					Price:    lo.ToPtr("1"),
+					Capacity: corev1.ResourceList{fake.ResourceGPUVendorA: resource.MustParse("1"), corev1.ResourcePods: resource.MustParse("10")},
+					Weight:   lo.ToPtr[int32](1),
+				}}),
+				test.NodeOverlay(v1.NodeOverlay{Spec: v1.NodeOverlaySpec{
+					PriceAdjustment: lo.ToPtr("+0.5"),
+					Capacity:        corev1.ResourceList{corev1.ResourcePods: resource.MustParse("20")},
+					Weight:          lo.ToPtr[int32](10),
+				}}),
+			})
+			for _, it := range its {
+				ExpectResources(corev1.ResourceList{fake.ResourceGPUVendorA: resource.MustParse("1"), corev1.ResourcePods: resource.MustParse("20")}, it.Capacity)
+			}
+			Expect(its[0].Offerings[0].Price).To(BeNumerically("~", small.Offerings[0].Price+0.5))
+			Expect(its[1].Offerings[0].Price).To(BeNumerically("~", large.Offerings[0].Price+0.5))
+		})
+		It("should reserve the overhead of the selected instance types", func() {
+			its := cloudprovider.InstanceTypes(cloudProvider.InstanceTypes).WithOverlays([]*v1.NodeOverlay{test.NodeOverlay(v1.NodeOverlay{
+				Spec: v1.NodeOverlaySpec{
+					Requirements: []corev1.NodeSelectorRequirement{{Key: corev1.LabelInstanceTypeStable, Operator: corev1.NodeSelectorOpIn, Values: []string{"large"}}},
+					Overhead:     corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+				},
+			})})
+			cpu := large.Allocatable()[corev1.ResourceCPU]
+			cpu.Sub(resource.MustParse("1"))
+			ExpectResources(corev1.ResourceList{corev1.ResourceCPU: cpu}, its[1].Allocatable())
+		})
+		It("should keep the overhead of overlays when the kubelet configuration reserves the same resource", func() {
+			nodePool.Spec.Template.Spec.Kubelet = &v1.KubeletConfiguration{SystemReserved: map[string]string{string(corev1.ResourceCPU): "1"}}
+			ExpectApplied(ctx, env.Client, nodePool, test.NodeOverlay(v1.NodeOverlay{
+				Spec: v1.NodeOverlaySpec{
+					Requirements: []corev1.NodeSelectorRequirement{{Key: corev1.LabelInstanceTypeStable, Operator: corev1.NodeSelectorOpIn, Values: []string{"small"}}},
+					Overhead:     corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+				},
+			}))
+			// small has 2 CPUs, and 1.5 CPUs are reserved by the kubelet configuration and the overlay
+			pod := test.UnschedulablePod(test.PodOptions{ResourceRequirements: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("900m")},
+			}})
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			node := ExpectScheduled(ctx, env.Client, pod)
+			Expect(node.Labels).To(HaveKeyWithValue(corev1.LabelInstanceTypeStable, "large"))
+		})
+		It("should launch the instance type that is cheapest after the overlays are applied", func() {
+			ExpectApplied(ctx, env.Client, nodePool, test.NodeOverlay(v1.NodeOverlay{
+				Spec: v1.NodeOverlaySpec{
+					Requirements: []corev1.NodeSelectorRequirement{{Key: corev1.LabelInstanceTypeStable, Operator: corev1.NodeSelectorOpIn, Values: []string{"large"}}},
+					Price:        lo.ToPtr("0"),
+				},
+			}))
+			pod := test.UnschedulablePod(test.PodOptions{ResourceRequirements: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+			}})
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			node := ExpectScheduled(ctx, env.Client, pod)
+			Expect(node.Labels).To(HaveKeyWithValue(corev1.LabelInstanceTypeStable, "large"))
+		})
+	})
+	Context("Unavailable Offerings", func() {
+		var unavailableOfferings *cloudprovider.UnavailableOfferings
+		var decorated cloudprovider.CloudProvider
+		var small, large *cloudprovider.InstanceType
+		BeforeEach(func() {
+			small = fake.NewInstanceType(fake.InstanceTypeOptions{
+				Name:      "small",
+				Resources: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+			})
+			large = fake.NewInstanceType(fake.InstanceTypeOptions{
+				Name:      "large",
+				Resources: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")},
+			})
+			cloudProvider.InstanceTypes = []*cloudprovider.InstanceType{small, large}
+			unavailableOfferings = cloudprovider.NewUnavailableOfferings()
+			decorated = unavailableOfferings.Decorate(cloudProvider)
+		})
+		It("should mark the unavailable offerings of the instance types as such", func() {
+			unavailableOfferings.MarkUnavailable(ctx, "test", cloudprovider.UnavailableOffering{InstanceType: "small", Zone: "test-zone-1", CapacityType: v1.CapacityTypeSpot})
+			its, err := decorated.GetInstanceTypes(ctx, nodePool)
+			Expect(err).ToNot(HaveOccurred())
+			for i, o := range its[0].Offerings {
+				unavailable := o.Requirements.Get(corev1.LabelTopologyZone).Any() == "test-zone-1" && o.Requirements.Get(v1.CapacityTypeLabelKey).Any() == v1.CapacityTypeSpot
+				Expect(o.Available).To(Equal(!unavailable))
+				Expect(small.Offerings[i].Available).To(BeTrue())
+			}
+			Expect(its[1]).To(BeIdenticalTo(large))
+		})
+		It("should match every value of the fields that the unavailable offering doesn't set", func() {
+			unavailableOfferings.MarkUnavailable(ctx, "test", cloudprovider.UnavailableOffering{Zone: "test-zone-1"})
+			its, err := decorated.GetInstanceTypes(ctx, nodePool)
+			Expect(err).ToNot(HaveOccurred())
+			for _, it := range its {
+				for _, o := range it.Offerings {
+					Expect(o.Available).To(Equal(o.Requirements.Get(corev1.LabelTopologyZone).Any() != "test-zone-1"))
+				}
+			}
+		})
+		It("should mark the offerings of insufficient capacity errors unavailable", func() {
+			cloudProvider.NextCreateErr = cloudprovider.NewInsufficientCapacityError(fmt.Errorf("no capacity"), cloudprovider.UnavailableOffering{InstanceType: "large", Zone: "test-zone-2", CapacityType: v1.CapacityTypeOnDemand})
+			_, err := decorated.Create(ctx, test.NodeClaim())
+			Expect(cloudprovider.IsInsufficientCapacityError(err)).To(BeTrue())
+			Expect(unavailableOfferings.IsUnavailable("large", "test-zone-2", v1.CapacityTypeOnDemand)).To(BeTrue())
+			Expect(unavailableOfferings.IsUnavailable("large", "test-zone-1", v1.CapacityTypeOnDemand)).To(BeFalse())
+			Expect(unavailableOfferings.IsUnavailable("small", "test-zone-2", v1.CapacityTypeOnDemand)).To(BeFalse())
+		})
+		It("should not launch unavailable offerings", func() {
+			unavailableOfferings.MarkUnavailable(ctx, "test", cloudprovider.UnavailableOffering{Zone: "test-zone-1"})
+			unavailableOfferings.MarkUnavailable(ctx, "test", cloudprovider.UnavailableOffering{Zone: "test-zone-2"})
+			p := provisioning.NewProvisioner(env.Client, events.NewRecorder(&record.FakeRecorder{}), decorated, cluster, fakeClock)
+			ExpectApplied(ctx, env.Client, nodePool)
+			pod := test.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, decorated, p, pod)
+			node := ExpectScheduled(ctx, env.Client, pod)
+			Expect(node.Labels).To(HaveKeyWithValue(corev1.LabelTopologyZone, "test-zone-3"))
+		})
+		It("should make offerings available again when they're flushed", func() {
+			unavailableOfferings.MarkUnavailable(ctx, "test", cloudprovider.UnavailableOffering{InstanceType: "small"})
+			Expect(unavailableOfferings.IsUnavailable("small", "test-zone-1", v1.CapacityTypeSpot)).To(BeTrue())
+			unavailableOfferings.Flush()
+			Expect(unavailableOfferings.IsUnavailable("small", "test-zone-1", v1.CapacityTypeSpot)).To(BeFalse())
+			its, err := decorated.GetInstanceTypes(ctx, nodePool)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(its[0]).To(BeIdenticalTo(small))
+		})
+	})
+	Context("Pricing", func() {
+		var small, large *cloudprovider.InstanceType
+		BeforeEach(func() {
+			small = fake.NewInstanceType(fake.InstanceTypeOptions{
+				Name:      "small",
+				Resources: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+			})
+			large = fake.NewInstanceType(fake.InstanceTypeOptions{
+				Name:      "large",
+				Resources: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")},
+			})
+			cloudProvider.InstanceTypes = []*cloudprovider.InstanceType{small, large}
+		})
+		It("should set the prices of the offerings that the pricing prices", func() {
+			its := cloudprovider.InstanceTypes{small, large}.WithPricing(cloudprovider.Pricing{
+				Prices: map[cloudprovider.PriceKey]float64{
+					{InstanceType: "small", Zone: "test-zone-1", CapacityType: v1.CapacityTypeSpot}: 0.5,
+				},
+			})
+			for i, o := range its[0].Offerings {
+				priced := o.Requirements.Get(corev1.LabelTopologyZone).Any() == "test-zone-1" && o.Requirements.Get(v1.CapacityTypeLabelKey).Any() == v1.CapacityTypeSpot
+				Expect(o.Price).To(Equal(lo.Ternary(priced, 0.5, small.Offerings[i].Price)))
+			}
+			Expect(its[1]).To(BeIdenticalTo(large))
+		})
+		It("should fall back to the price of the instance type and capacity type in every zone", func() {
+			pricing := cloudprovider.Pricing{
+				Prices: map[cloudprovider.PriceKey]float64{
+					{InstanceType: "small", CapacityType: v1.CapacityTypeOnDemand}:                      1,
+					{InstanceType: "small", Zone: "test-zone-2", CapacityType: v1.CapacityTypeOnDemand}: 2,
+				},
+			}
+			Expect(lo.T2(pricing.Price("small", "test-zone-1", v1.CapacityTypeOnDemand))).To(Equal(lo.T2(1.0, true)))
+			Expect(lo.T2(pricing.Price("small", "test-zone-2", v1.CapacityTypeOnDemand))).To(Equal(lo.T2(2.0, true)))
+			Expect(lo.T2(pricing.Price("small", "test-zone-1", v1.CapacityTypeSpot))).To(Equal(lo.T2(0.0, false)))
+		})
+		It("should report the age of the prices", func() {
+			Expect(cloudprovider.Pricing{}.Age(fakeClock)).To(BeZero())
+			Expect(cloudprovider.Pricing{UpdatedAt: fakeClock.Now().Add(-time.Minute)}.Age(fakeClock)).To(Equal(time.Minute))
+		})
+		It("should launch the cheapest instance type by the CloudProvider's pricing", func() {
+			cloudProvider.Prices = cloudprovider.Pricing{
+				Prices: map[cloudprovider.PriceKey]float64{
+					{InstanceType: "large", CapacityType: v1.CapacityTypeSpot}:     0.0001,
+					{InstanceType: "large", CapacityType: v1.CapacityTypeOnDemand}: 0.0001,
+				},
+				Currency: "USD",
+			}
+			decorated := cloudprovider.DecorateWithPricing(cloudProvider)
+			p := provisioning.NewProvisioner(env.Client, events.NewRecorder(&record.FakeRecorder{}), decorated, cluster, fakeClock)
+			ExpectApplied(ctx, env.Client, nodePool)
+			pod := test.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, decorated, p, pod)
+			node := ExpectScheduled(ctx, env.Client, pod)
+			Expect(node.Labels).To(HaveKeyWithValue(corev1.LabelInstanceTypeStable, "large"))
+		})
+	})
 })
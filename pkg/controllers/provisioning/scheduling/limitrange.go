@@ -0,0 +1,94 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduling
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// LimitRanges are the LimitRanges of each namespace. The LimitRanger admission plugin sets the default requests and
+// limits of a namespace's LimitRanges on containers that don't specify them, so pods that we simulate without going
+// through admission, or that were admitted before the LimitRange was created, need the same defaults to be applied for
+// their requests to be accurate.
+type LimitRanges map[string][]corev1.LimitRange
+
+func NewLimitRanges(limitRanges []corev1.LimitRange) LimitRanges {
+	l := LimitRanges{}
+	for _, limitRange := range limitRanges {
+		l[limitRange.Namespace] = append(l[limitRange.Namespace], limitRange)
+	}
+	return l
+}
+
+// ApplyDefaults returns the pod with the default requests and limits of the LimitRanges in its namespace applied to the
+// containers that don't specify them. The pod is copied before being modified, and returned as is if no defaults apply.
+func (l LimitRanges) ApplyDefaults(pod *corev1.Pod) *corev1.Pod {
+	requests, limits := corev1.ResourceList{}, corev1.ResourceList{}
+	for _, limitRange := range l[pod.Namespace] {
+		for _, item := range limitRange.Spec.Limits {
+			if item.Type != corev1.LimitTypeContainer {
+				continue
+			}
+			for name, quantity := range item.DefaultRequest {
+				requests[name] = quantity
+			}
+			for name, quantity := range item.Default {
+				limits[name] = quantity
+			}
+		}
+	}
+	if !needsDefaults(pod.Spec.InitContainers, requests, limits) && !needsDefaults(pod.Spec.Containers, requests, limits) {
+		return pod
+	}
+	pod = pod.DeepCopy()
+	for _, containers := range [][]corev1.Container{pod.Spec.InitContainers, pod.Spec.Containers} {
+		for i := range containers {
+			containers[i].Resources.Requests = withDefaults(containers[i].Resources.Requests, requests)
+			containers[i].Resources.Limits = withDefaults(containers[i].Resources.Limits, limits)
+		}
+	}
+	return pod
+}
+
+func needsDefaults(containers []corev1.Container, requests, limits corev1.ResourceList) bool {
+	for _, c := range containers {
+		for name := range requests {
+			if _, ok := c.Resources.Requests[name]; !ok {
+				return true
+			}
+		}
+		for name := range limits {
+			if _, ok := c.Resources.Limits[name]; !ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func withDefaults(resources, defaults corev1.ResourceList) corev1.ResourceList {
+	for name, quantity := range defaults {
+		if _, ok := resources[name]; ok {
+			continue
+		}
+		if resources == nil {
+			resources = corev1.ResourceList{}
+		}
+		resources[name] = quantity.DeepCopy()
+	}
+	return resources
+}
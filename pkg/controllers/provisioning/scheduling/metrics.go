@@ -17,7 +17,6 @@ limitations under the License.
 package scheduling
 
 import (
-	opmetrics "github.com/awslabs/operatorpkg/metrics"
 	"github.com/prometheus/client_golang/prometheus"
 	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 
@@ -31,7 +30,7 @@ const (
 )
 
 var (
-	DurationSeconds = opmetrics.NewPrometheusHistogram(
+	DurationSeconds = metrics.NewPrometheusHistogram(
 		crmetrics.Registry,
 		prometheus.HistogramOpts{
 			Namespace: metrics.Namespace,
@@ -44,7 +43,7 @@ var (
 			ControllerLabel,
 		},
 	)
-	QueueDepth = opmetrics.NewPrometheusGauge(
+	QueueDepth = metrics.NewPrometheusGauge(
 		crmetrics.Registry,
 		prometheus.GaugeOpts{
 			Namespace: metrics.Namespace,
@@ -57,7 +56,7 @@ var (
 			schedulingIDLabel,
 		},
 	)
-	UnfinishedWorkSeconds = opmetrics.NewPrometheusGauge(
+	UnfinishedWorkSeconds = metrics.NewPrometheusGauge(
 		crmetrics.Registry,
 		prometheus.GaugeOpts{
 			Namespace: metrics.Namespace,
@@ -70,7 +69,7 @@ var (
 			schedulingIDLabel,
 		},
 	)
-	IgnoredPodCount = opmetrics.NewPrometheusGauge(
+	IgnoredPodCount = metrics.NewPrometheusGauge(
 		crmetrics.Registry,
 		prometheus.GaugeOpts{
 			Namespace: metrics.Namespace,
@@ -79,7 +78,29 @@ var (
 		},
 		[]string{},
 	)
-	UnschedulablePodsCount = opmetrics.NewPrometheusGauge(
+	SchedulingGatedPodCount = metrics.NewPrometheusGauge(
+		crmetrics.Registry,
+		prometheus.GaugeOpts{
+			Namespace: metrics.Namespace,
+			Name:      "scheduling_gated_pod_count",
+			Help:      "Number of pending pods skipped during scheduling by Karpenter because they have scheduling gates",
+		},
+		[]string{},
+	)
+	InstanceTypesRejectedTotal = metrics.NewPrometheusCounter(
+		crmetrics.Registry,
+		prometheus.CounterOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: schedulerSubsystem,
+			Name:      "instance_types_rejected_total",
+			Help:      "The number of instance types of a NodePool that were rejected as options for launched NodeClaims, by the reason that they were rejected.",
+		},
+		[]string{
+			metrics.NodePoolLabel,
+			metrics.ReasonLabel,
+		},
+	)
+	UnschedulablePodsCount = metrics.NewPrometheusGauge(
 		crmetrics.Registry,
 		prometheus.GaugeOpts{
 			Namespace: metrics.Namespace,
@@ -18,15 +18,19 @@ package scheduling
 
 import (
 	"fmt"
+	"math"
 	"strings"
 	"sync/atomic"
 
 	"github.com/samber/lo"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 
 	"sigs.k8s.io/karpenter/pkg/cloudprovider"
 	"sigs.k8s.io/karpenter/pkg/scheduling"
+	"sigs.k8s.io/karpenter/pkg/utils/pretty"
 	"sigs.k8s.io/karpenter/pkg/utils/resources"
 )
 
@@ -35,16 +39,33 @@ import (
 type NodeClaim struct {
 	NodeClaimTemplate
 
-	Pods            []*v1.Pod
-	topology        *Topology
-	hostPortUsage   *scheduling.HostPortUsage
-	daemonResources v1.ResourceList
-	hostname        string
+	Pods     []*v1.Pod
+	topology *Topology
+	// domainLimits restrict the NodeClaim to a single domain of each of its NodePool's limited topology keys when its
+	// first pod is added
+	domainLimits   *domainLimits
+	hostPortUsage  *scheduling.HostPortUsage
+	daemonOverhead *daemonOverhead
+	requests       v1.ResourceList // requests of the pods, without the daemon overhead
+	volumes        scheduling.Volumes
+	hostname       string
+	// fallbacks are the reasons that higher weight NodePools couldn't satisfy the pods that were added to this NodeClaim
+	fallbacks map[types.UID][]NodePoolFailure
+	// candidates are the instance types of the NodePool that the NodeClaim's instance type options were selected from
+	candidates cloudprovider.InstanceTypes
+	// truncated are the names of the instance type options that were dropped for their price, either to limit the
+	// number of options or to stay within the NodePool's MaxPricePercentile
+	truncated sets.Set[string]
+	// fragmenting are the names of the instance type options that were dropped as they would strand a small amount of cpu
+	fragmenting sets.Set[string]
+	// rejectedByExtender are the names of the instance type options that the scheduling extender filtered out or scored
+	// lower than the other options
+	rejectedByExtender sets.Set[string]
 }
 
 var nodeID int64
 
-func NewNodeClaim(nodeClaimTemplate *NodeClaimTemplate, topology *Topology, daemonResources v1.ResourceList, instanceTypes []*cloudprovider.InstanceType) *NodeClaim {
+func NewNodeClaim(nodeClaimTemplate *NodeClaimTemplate, topology *Topology, domainLimits *domainLimits, daemonOverhead *daemonOverhead, instanceTypes []*cloudprovider.InstanceType) *NodeClaim {
 	// Copy the template, and add hostname
 	hostname := fmt.Sprintf("hostname-placeholder-%04d", atomic.AddInt64(&nodeID, 1))
 	topology.Register(v1.LabelHostname, hostname)
@@ -53,74 +74,243 @@ func NewNodeClaim(nodeClaimTemplate *NodeClaimTemplate, topology *Topology, daem
 	template.Requirements.Add(nodeClaimTemplate.Requirements.Values()...)
 	template.Requirements.Add(scheduling.NewRequirement(v1.LabelHostname, v1.NodeSelectorOpIn, hostname))
 	template.InstanceTypeOptions = instanceTypes
-	template.Spec.Resources.Requests = daemonResources
+	template.Spec.Resources.Requests = daemonOverhead.min(instanceTypes)
 
 	return &NodeClaim{
-		NodeClaimTemplate: template,
-		hostPortUsage:     scheduling.NewHostPortUsage(),
-		topology:          topology,
-		daemonResources:   daemonResources,
-		hostname:          hostname,
+		NodeClaimTemplate:  template,
+		hostPortUsage:      scheduling.NewHostPortUsage(),
+		topology:           topology,
+		domainLimits:       domainLimits,
+		daemonOverhead:     daemonOverhead,
+		hostname:           hostname,
+		fallbacks:          map[types.UID][]NodePoolFailure{},
+		candidates:         nodeClaimTemplate.InstanceTypeOptions,
+		truncated:          sets.New[string](),
+		fragmenting:        sets.New[string](),
+		rejectedByExtender: sets.New[string](),
 	}
 }
 
-func (n *NodeClaim) Add(pod *v1.Pod, podRequests v1.ResourceList) error {
-	// Check Taints
-	if err := scheduling.Taints(n.Spec.Taints).Tolerates(pod); err != nil {
+func (n *NodeClaim) Add(pod *v1.Pod, podData *PodData) error {
+	// Check that the NodePool allows the pod's namespace
+	if err := n.AllowsNamespace(pod); err != nil {
 		return err
 	}
+	// Check Taints
+	taints := scheduling.Taints(n.Spec.TaintsWithTemplates())
+	if err := taints.Tolerates(pod); err != nil {
+		return NewSchedulingError(FailureReasonTaint, lo.Map(taints.Untolerated(pod), func(t v1.Taint, _ int) string { return pretty.Taint(t) }), err)
+	}
 
 	// exposed host ports on the node
 	hostPorts := scheduling.GetHostPorts(pod)
 	if err := n.hostPortUsage.Conflicts(pod, hostPorts); err != nil {
-		return fmt.Errorf("checking host port usage, %w", err)
+		return NewSchedulingError(FailureReasonHostPort, nil, fmt.Errorf("checking host port usage, %w", err))
 	}
 	nodeClaimRequirements := scheduling.NewRequirements(n.Requirements.Values()...)
-	podRequirements := scheduling.NewPodRequirements(pod)
 
 	// Check NodeClaim Affinity Requirements
-	if err := nodeClaimRequirements.Compatible(podRequirements, scheduling.AllowUndefinedWellKnownLabels); err != nil {
-		return fmt.Errorf("incompatible requirements, %w", err)
+	if err := nodeClaimRequirements.Compatible(podData.Requirements, scheduling.AllowUndefinedWellKnownLabels); err != nil {
+		return NewSchedulingError(FailureReasonRequirement, scheduling.IncompatibleKeys(err), fmt.Errorf("incompatible requirements, %w", err))
 	}
-	nodeClaimRequirements.Add(podRequirements.Values()...)
+	nodeClaimRequirements.Add(podData.Requirements.Values()...)
+	podRequests := n.SimulatedRequests(podData.NodeClaimRequests)
+	requests := resources.Merge(n.requests, podRequests)
 
-	strictPodRequirements := podRequirements
-	if scheduling.HasPreferredNodeAffinity(pod) {
-		// strictPodRequirements is important as it ensures we don't inadvertently restrict the possible pod domains by a
-		// preferred node affinity.  Only required node affinities can actually reduce pod domains.
-		strictPodRequirements = scheduling.NewStrictPodRequirements(pod)
-	}
-	// Check Topology Requirements
-	topologyRequirements, err := n.topology.AddRequirements(strictPodRequirements, nodeClaimRequirements, pod, scheduling.AllowUndefinedWellKnownLabels)
+	// determine the volumes that will be attached if the pod schedules
+	volumes, instanceTypes, err := n.attachVolumes(podData.Volumes)
 	if err != nil {
-		return err
-	}
-	if err = nodeClaimRequirements.Compatible(topologyRequirements, scheduling.AllowUndefinedWellKnownLabels); err != nil {
-		return err
+		return NewSchedulingError(FailureReasonVolume, nil, fmt.Errorf("checking volume usage, %w", err))
 	}
-	nodeClaimRequirements.Add(topologyRequirements.Values()...)
 
-	// Check instance type combinations
-	requests := resources.Merge(n.Spec.Resources.Requests, podRequests)
-
-	filtered := filterInstanceTypesByRequirements(n.InstanceTypeOptions, nodeClaimRequirements, requests)
+	var filtered filterResults
+	for {
+		requirements, err := n.addTopologyRequirements(pod, podData, nodeClaimRequirements)
+		if err != nil {
+			return NewSchedulingError(FailureReasonTopology, nil, err)
+		}
+		// Check instance type combinations
+		filtered = filterInstanceTypesByRequirements(instanceTypes, requirements, requests, n.daemonOverhead.allocatable)
+		if len(filtered.remaining) > 0 {
+			nodeClaimRequirements = requirements
+			break
+		}
+		// If the only thing preventing the pod from scheduling is that the cloud provider has no capacity available in the
+		// domains we selected, we exclude those domains and retry against the domains that are left rather than waiting
+		// for the next scheduling loop.
+		unavailable := unavailableDomains(instanceTypes, nodeClaimRequirements, requirements, filtered)
+		if len(unavailable) == 0 || nodeClaimRequirements.Compatible(unavailable, scheduling.AllowUndefinedWellKnownLabels) != nil {
+			// log the total resources being requested (daemonset + the pod)
+			cumulativeResources := resources.Merge(n.daemonOverhead.min(instanceTypes), podRequests)
+			reason, details := instanceTypeFailure(instanceTypes, requirements, filtered)
+			err := NewSchedulingError(reason, details, fmt.Errorf("no instance type satisfied resources %s and requirements %s (%s)", resources.String(cumulativeResources), requirements, filtered.FailureReason()))
+			err.rejectedInstanceTypes = func() map[string]FailureReason {
+				return lo.SliceToMap(instanceTypes, func(it *cloudprovider.InstanceType) (string, FailureReason) {
+					// instance types that satisfied the NodeClaim on their own were rejected as too few of them satisfied
+					// the minimum values of its requirements
+					reason, _ := instanceTypeRejection(it, requirements, requests, n.daemonOverhead.allocatable)
+					return it.Name, lo.CoalesceOrEmpty(reason, FailureReasonRequirement)
+				})
+			}
+			return err
+		}
+		nodeClaimRequirements.Add(unavailable.Values()...)
+	}
+	remaining := filtered.remaining
+	if n.MaxPricePercentile != nil {
+		if remaining = n.filterByPricePercentile(remaining, nodeClaimRequirements, requests); len(remaining) == 0 {
+			return NewSchedulingError(FailureReasonPrice, nil, fmt.Errorf("no instance type within the cheapest %d%% of instance types satisfied resources %s and requirements %s",
+				*n.MaxPricePercentile, resources.String(requests), nodeClaimRequirements))
+		}
+	}
 
-	if len(filtered.remaining) == 0 {
-		// log the total resources being requested (daemonset + the pod)
-		cumulativeResources := resources.Merge(n.daemonResources, podRequests)
-		return fmt.Errorf("no instance type satisfied resources %s and requirements %s (%s)", resources.String(cumulativeResources), nodeClaimRequirements, filtered.FailureReason())
+	// The domains are picked before the pod is recorded in the topology, so that it's counted against the domains that
+	// the NodeClaim will launch into
+	if n.domainLimits != nil && len(n.Pods) == 0 {
+		if remaining, err = n.domainLimits.restrict(n.NodePoolName, nodeClaimRequirements, remaining, n.topology.domains); err != nil {
+			return NewSchedulingError(FailureReasonLimits, nil, err)
+		}
 	}
 
 	// Update node
 	n.Pods = append(n.Pods, pod)
-	n.InstanceTypeOptions = filtered.remaining
-	n.Spec.Resources.Requests = requests
+	n.InstanceTypeOptions = remaining
+	n.requests = requests
+	n.volumes = volumes
+	n.Spec.Resources.Requests = resources.Merge(n.daemonOverhead.min(remaining), requests)
 	n.Requirements = nodeClaimRequirements
 	n.topology.Record(pod, nodeClaimRequirements, scheduling.AllowUndefinedWellKnownLabels)
 	n.hostPortUsage.Add(pod, hostPorts)
+	if n.domainLimits != nil && len(n.Pods) == 1 {
+		n.domainLimits.subtract(n.NodePoolName, n.Requirements, n.InstanceTypeOptions)
+	}
 	return nil
 }
 
+// InstanceTypeRejections returns the number of the NodePool's instance types that aren't options for the NodeClaim, by
+// the reason that they were rejected. It must be called before the NodeClaim is converted with ToNodeClaim, as that
+// restricts the requirements to the instance type options.
+func (n *NodeClaim) InstanceTypeRejections() map[FailureReason]int {
+	return lo.CountValues(lo.Values(n.RejectedInstanceTypes()))
+}
+
+// RejectedInstanceTypes returns the reason that each of the NodePool's instance types that aren't options for the
+// NodeClaim was rejected, by instance type name. It must be called before the NodeClaim is converted with ToNodeClaim,
+// as that restricts the requirements to the instance type options.
+func (n *NodeClaim) RejectedInstanceTypes() map[string]FailureReason {
+	options := sets.New(lo.Map(n.InstanceTypeOptions, func(it *cloudprovider.InstanceType, _ int) string { return it.Name })...)
+	rejections := map[string]FailureReason{}
+	for _, it := range n.candidates {
+		if options.Has(it.Name) {
+			continue
+		}
+		if reason, ok := instanceTypeRejection(it, n.Requirements, n.requests, n.daemonOverhead.allocatable); ok {
+			rejections[it.Name] = reason
+			continue
+		}
+		switch {
+		case n.truncated.Has(it.Name):
+			rejections[it.Name] = FailureReasonPrice
+		case n.fragmenting.Has(it.Name):
+			rejections[it.Name] = FailureReasonFragmentation
+		case n.rejectedByExtender.Has(it.Name):
+			rejections[it.Name] = FailureReasonExtender
+		default:
+			// the instance type satisfied the NodeClaim, so it was only excluded to stay within the NodePool's limits
+			rejections[it.Name] = FailureReasonLimits
+		}
+	}
+	return rejections
+}
+
+// instanceTypeRejection returns why the instance type can't launch a NodeClaim with the requirements and requests, and
+// false if it can
+func instanceTypeRejection(it *cloudprovider.InstanceType, requirements scheduling.Requirements, requests v1.ResourceList, allocatable map[string]v1.ResourceList) (FailureReason, bool) {
+	switch {
+	case !compatible(it, requirements):
+		return FailureReasonRequirement, true
+	case !it.Offerings.Available().HasCompatible(requirements):
+		return FailureReasonOffering, true
+	case !fits(it, requests, allocatable):
+		return FailureReasonResources, true
+	}
+	return "", false
+}
+
+// filterByPricePercentile returns the instance types that are within the NodePool's MaxPricePercentile of the cheapest
+// of the NodePool's instance types that satisfy the requirements and requests. The percentile is taken over all of the
+// NodePool's instance types rather than the NodeClaim's current options so that adding pods to the NodeClaim doesn't
+// compound the filtering.
+func (n *NodeClaim) filterByPricePercentile(instanceTypes []*cloudprovider.InstanceType, requirements scheduling.Requirements, requests v1.ResourceList) []*cloudprovider.InstanceType {
+	ordered := filterInstanceTypesByRequirements(n.candidates, requirements, requests, n.daemonOverhead.allocatable).remaining.OrderByPrice(requirements)
+	count := lo.Max([]int{1, int(math.Ceil(float64(len(ordered)*int(*n.MaxPricePercentile)) / 100))})
+	cheapest := sets.New(lo.Map(lo.Slice(ordered, 0, count), func(it *cloudprovider.InstanceType, _ int) string { return it.Name })...)
+	kept, dropped := lo.FilterReject(instanceTypes, func(it *cloudprovider.InstanceType, _ int) bool { return cheapest.Has(it.Name) })
+	n.truncated.Insert(lo.Map(dropped, func(it *cloudprovider.InstanceType, _ int) string { return it.Name })...)
+	return kept
+}
+
+// RemoveFragmentingInstanceTypes removes the instance type options that would leave less than the threshold of cpu
+// unused, but not none, once the NodeClaim's pods and daemons are scheduled. Instance types are only removed if the
+// remaining options can still launch the NodeClaim, so the threshold discourages stranded capacity without preventing
+// pods from scheduling.
+func (n *NodeClaim) RemoveFragmentingInstanceTypes(threshold resource.Quantity) {
+	kept, dropped := lo.FilterReject(n.InstanceTypeOptions, func(it *cloudprovider.InstanceType, _ int) bool {
+		allocatable := n.daemonOverhead.allocatable[it.Name]
+		unused := allocatable.Cpu().DeepCopy()
+		unused.Sub(*n.requests.Cpu())
+		return unused.Sign() <= 0 || unused.Cmp(threshold) >= 0
+	})
+	if len(dropped) == 0 || len(kept) == 0 {
+		return
+	}
+	if _, err := cloudprovider.InstanceTypes(kept).SatisfiesMinValues(n.Requirements); err != nil {
+		return
+	}
+	n.InstanceTypeOptions = kept
+	n.Spec.Resources.Requests = resources.Merge(n.daemonOverhead.min(kept), n.requests)
+	n.fragmenting.Insert(lo.Map(dropped, func(it *cloudprovider.InstanceType, _ int) string { return it.Name })...)
+}
+
+// attachVolumes returns the volumes that will be attached to the node if the volumes are added, and the instance types
+// that are able to attach all of them
+func (n *NodeClaim) attachVolumes(volumes scheduling.Volumes) (scheduling.Volumes, []*cloudprovider.InstanceType, error) {
+	if len(volumes) == 0 {
+		return n.volumes, n.InstanceTypeOptions, nil
+	}
+	volumes = n.volumes.Union(volumes)
+	instanceTypes := lo.Filter(n.InstanceTypeOptions, func(it *cloudprovider.InstanceType, _ int) bool {
+		return volumes.ExceedsLimits(it.VolumeLimits) == nil
+	})
+	if len(instanceTypes) == 0 {
+		return nil, nil, fmt.Errorf("no instance type can attach volumes %s", pretty.Concise(lo.MapValues(volumes, func(v sets.Set[string], _ string) int { return len(v) })))
+	}
+	return volumes, instanceTypes, nil
+}
+
+// addTopologyRequirements returns the node claim requirements tightened by the topology requirements of the pod. The
+// strict pod requirements are important as they ensure we don't inadvertently restrict the possible pod domains by a
+// preferred node affinity.  Only required node affinities can actually reduce pod domains.
+func (n *NodeClaim) addTopologyRequirements(pod *v1.Pod, podData *PodData, nodeClaimRequirements scheduling.Requirements) (scheduling.Requirements, error) {
+	topologyRequirements, err := n.topology.AddRequirements(podData.StrictRequirements, nodeClaimRequirements, pod, scheduling.AllowUndefinedWellKnownLabels)
+	if err != nil {
+		return nil, err
+	}
+	if err = nodeClaimRequirements.Compatible(topologyRequirements, scheduling.AllowUndefinedWellKnownLabels); err != nil {
+		return nil, err
+	}
+	requirements := scheduling.NewRequirements(nodeClaimRequirements.Values()...)
+	requirements.Add(topologyRequirements.Values()...)
+	return requirements, nil
+}
+
+// recordFallback records why higher weight NodePools couldn't satisfy a pod that was added to the NodeClaim
+func (n *NodeClaim) recordFallback(pod *v1.Pod, failures []NodePoolFailure) {
+	if len(failures) > 0 {
+		n.fallbacks[pod.UID] = failures
+	}
+}
+
 func (n *NodeClaim) Destroy() {
 	n.topology.Unregister(v1.LabelHostname, n.hostname)
 }
@@ -174,6 +364,7 @@ type filterResults struct {
 	fitsAndOffering          bool
 	minValuesIncompatibleErr error
 	requests                 v1.ResourceList
+	allocatable              map[string]v1.ResourceList
 }
 
 // FailureReason returns a presentable string explaining why all instance types were filtered out
@@ -244,10 +435,15 @@ func (r filterResults) FailureReason() string {
 	return "no instance type met the requirements/resources/offering tuple"
 }
 
+// filterInstanceTypesByRequirements returns the instance types that are compatible with the requirements, fit the
+// requests and have an available offering. The allocatable resources of an instance type can be overridden by its name,
+// e.g. to account for the daemons that will schedule to the node.
+//
 //nolint:gocyclo
-func filterInstanceTypesByRequirements(instanceTypes []*cloudprovider.InstanceType, requirements scheduling.Requirements, requests v1.ResourceList) filterResults {
+func filterInstanceTypesByRequirements(instanceTypes []*cloudprovider.InstanceType, requirements scheduling.Requirements, requests v1.ResourceList, allocatable map[string]v1.ResourceList) filterResults {
 	results := filterResults{
 		requests:        requests,
+		allocatable:     allocatable,
 		requirementsMet: false,
 		fits:            false,
 		hasOffering:     false,
@@ -261,7 +457,7 @@ func filterInstanceTypesByRequirements(instanceTypes []*cloudprovider.InstanceTy
 		// the tradeoff to not short circuiting on the filtering is that we can report much better error messages
 		// about why scheduling failed
 		itCompat := compatible(it, requirements)
-		itFits := fits(it, requests)
+		itFits := fits(it, requests, allocatable)
 		itHasOffering := it.Offerings.Available().HasCompatible(requirements)
 
 		// track if any single instance type met a single criteria
@@ -292,10 +488,50 @@ func filterInstanceTypesByRequirements(instanceTypes []*cloudprovider.InstanceTy
 	return results
 }
 
+// unavailableDomains returns NotIn requirements for the offering domains (e.g. zones or capacity types) that were selected
+// by topology, but have no available offering that is compatible with the requirements and fits the requests, while
+// other domains allowed by the node claim requirements do. Excluding these domains lets the scheduler fall back to the
+// other domains when the cloud provider reports an offering as unavailable. An empty result means that narrowing the
+// domains won't allow the pod to schedule.
+func unavailableDomains(instanceTypes []*cloudprovider.InstanceType, nodeClaimRequirements, requirements scheduling.Requirements, filtered filterResults) scheduling.Requirements {
+	unavailable := scheduling.NewRequirements()
+	// narrowing the domains can't help if no instance type has enough resources
+	if !filtered.fits {
+		return unavailable
+	}
+	candidates := lo.Filter(instanceTypes, func(it *cloudprovider.InstanceType, _ int) bool {
+		return fits(it, filtered.requests, filtered.allocatable)
+	})
+	keys := sets.New[string]()
+	for _, it := range candidates {
+		for _, of := range it.Offerings {
+			keys.Insert(lo.Keys(of.Requirements)...)
+		}
+	}
+	for key := range keys {
+		requirement := requirements.Get(key)
+		// we can only exclude domains that have been restricted to a known set of values
+		if requirement.Operator() != v1.NodeSelectorOpIn {
+			continue
+		}
+		relaxed := scheduling.NewRequirements(requirements.Values()...)
+		relaxed[key] = nodeClaimRequirements.Get(key)
+		if lo.ContainsBy(candidates, func(it *cloudprovider.InstanceType) bool {
+			return compatible(it, relaxed) && it.Offerings.Available().HasCompatible(relaxed)
+		}) {
+			unavailable.Add(scheduling.NewRequirement(key, v1.NodeSelectorOpNotIn, requirement.Values()...))
+		}
+	}
+	return unavailable
+}
+
 func compatible(instanceType *cloudprovider.InstanceType, requirements scheduling.Requirements) bool {
 	return instanceType.Requirements.Intersects(requirements) == nil
 }
 
-func fits(instanceType *cloudprovider.InstanceType, requests v1.ResourceList) bool {
+func fits(instanceType *cloudprovider.InstanceType, requests v1.ResourceList, allocatable map[string]v1.ResourceList) bool {
+	if a, ok := allocatable[instanceType.Name]; ok {
+		return resources.Fits(requests, a)
+	}
 	return resources.Fits(requests, instanceType.Allocatable())
 }
@@ -18,16 +18,20 @@ package scheduling
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/awslabs/operatorpkg/object"
 	"github.com/samber/lo"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 
 	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
 	"sigs.k8s.io/karpenter/pkg/cloudprovider"
 	"sigs.k8s.io/karpenter/pkg/scheduling"
+	"sigs.k8s.io/karpenter/pkg/utils/resources"
 )
 
 // MaxInstanceTypes is a constant that restricts the number of instance types to be sent for launch. Note that this
@@ -40,35 +44,78 @@ var MaxInstanceTypes = 60
 type NodeClaimTemplate struct {
 	v1.NodeClaim
 
-	NodePoolName        string
-	NodePoolUUID        types.UID
-	InstanceTypeOptions cloudprovider.InstanceTypes
-	Requirements        scheduling.Requirements
+	NodePoolName           string
+	NodePoolUUID           types.UID
+	NodePoolWeight         int32
+	InstanceTypeOptions    cloudprovider.InstanceTypes
+	InstanceTypeTruncation *v1.InstanceTypeTruncation
+	CapacityTypeSplit      map[string]int32
+	MaxPricePercentile     *int32
+	// ExcludedDaemonSets selects the daemonset pods that aren't counted in the overhead of the NodeClaims
+	ExcludedDaemonSets labels.Selector
+	// AllowedNamespaces are the namespaces of the pods that can schedule to the NodeClaims, or nil if pods in any
+	// namespace can
+	AllowedNamespaces sets.Set[string]
+	// ExistingCapacityWindow is how long pending pods wait for the NodeClaims to initialize before triggering new ones
+	ExistingCapacityWindow time.Duration
+	// SimulatedPodRequests stand in for the resources that pods don't request when they're fit on the NodeClaims
+	SimulatedPodRequests corev1.ResourceList
+	Requirements         scheduling.Requirements
 }
 
 func NewNodeClaimTemplate(nodePool *v1.NodePool) *NodeClaimTemplate {
 	nct := &NodeClaimTemplate{
-		NodeClaim:    *nodePool.Spec.Template.ToNodeClaim(),
-		NodePoolName: nodePool.Name,
-		NodePoolUUID: nodePool.UID,
-		Requirements: scheduling.NewRequirements(),
+		NodeClaim:              *nodePool.Spec.Template.ToNodeClaim(),
+		NodePoolName:           nodePool.Name,
+		NodePoolUUID:           nodePool.UID,
+		NodePoolWeight:         lo.FromPtr(nodePool.Spec.Weight),
+		InstanceTypeTruncation: nodePool.Spec.InstanceTypeTruncation.DeepCopy(),
+		CapacityTypeSplit:      nodePool.Spec.CapacityTypeSplit,
+		MaxPricePercentile:     nodePool.Spec.MaxPricePercentile,
+		ExistingCapacityWindow: lo.FromPtr(nodePool.Spec.ExistingCapacityWindow).Duration,
+		SimulatedPodRequests:   nodePool.Spec.SimulatedPodRequests,
+		Requirements:           scheduling.NewRequirements(),
 	}
+	if nodePool.Spec.ExcludedDaemonSets != nil {
+		// invalid selectors fail the NodePool's runtime validation, so they never get here
+		nct.ExcludedDaemonSets = lo.Must(metav1.LabelSelectorAsSelector(nodePool.Spec.ExcludedDaemonSets))
+	}
+	if nodePool.Spec.AllowedNamespaces != nil {
+		nct.AllowedNamespaces = sets.New(nodePool.Spec.AllowedNamespaces...)
+	}
+	// NodeClaims are launched with the NodePool's active NodeClass, which may be one of its fallbacks
+	nct.Spec.NodeClassRef = nodePool.ActiveNodeClassRef()
 	nct.Annotations = lo.Assign(nct.Annotations, map[string]string{
 		v1.NodePoolHashAnnotationKey:        nodePool.Hash(),
 		v1.NodePoolHashVersionAnnotationKey: v1.NodePoolHashVersion,
 	})
 	nct.Labels = lo.Assign(nct.Labels, map[string]string{
 		v1.NodePoolLabelKey: nodePool.Name,
-		v1.NodeClassLabelKey(nodePool.Spec.Template.Spec.NodeClassRef.GroupKind()): nodePool.Spec.Template.Spec.NodeClassRef.Name,
+		v1.NodeClassLabelKey(nct.Spec.NodeClassRef.GroupKind()): nct.Spec.NodeClassRef.Name,
 	})
+	// NodeClaims are managed by the same instance of Karpenter as their NodePool
+	if managedBy, ok := nodePool.Labels[v1.ManagedByLabelKey]; ok {
+		nct.Labels = lo.Assign(nct.Labels, map[string]string{v1.ManagedByLabelKey: managedBy})
+	}
 	nct.Requirements.Add(scheduling.NewNodeSelectorRequirementsWithMinValues(nct.Spec.Requirements...).Values()...)
 	nct.Requirements.Add(scheduling.NewLabelRequirements(nct.Labels).Values()...)
+	nct.Requirements.Add(TopologyDomainRequirements(nodePool).Values()...)
 	return nct
 }
 
+// TopologyDomainRequirements returns a requirement for each of the NodePool's user-defined topology domain keys, so
+// that NodeClaims from the NodePool can be constrained to, and labeled with, one of its domains
+func TopologyDomainRequirements(nodePool *v1.NodePool) scheduling.Requirements {
+	requirements := scheduling.NewRequirements()
+	for key, values := range nodePool.Spec.TopologyDomains {
+		requirements.Add(scheduling.NewRequirement(key, corev1.NodeSelectorOpIn, values...))
+	}
+	return requirements
+}
+
 func (i *NodeClaimTemplate) ToNodeClaim() *v1.NodeClaim {
-	// Order the instance types by price and only take the first 100 of them to decrease the instance type size in the requirements
-	instanceTypes := lo.Slice(i.InstanceTypeOptions.OrderByPrice(i.Requirements), 0, MaxInstanceTypes)
+	// Select a subset of the instance types using the NodePool's truncation strategy to decrease the instance type size in the requirements
+	instanceTypes := i.InstanceTypeOptions.SelectByStrategy(i.Requirements, MaxInstanceTypes, i.InstanceTypeTruncation)
 	i.Requirements.Add(scheduling.NewRequirementWithFlexibility(corev1.LabelInstanceTypeStable, corev1.NodeSelectorOpIn, i.Requirements.Get(corev1.LabelInstanceTypeStable).MinValues, lo.Map(instanceTypes, func(i *cloudprovider.InstanceType, _ int) string {
 		return i.Name
 	})...))
@@ -78,6 +125,7 @@ func (i *NodeClaimTemplate) ToNodeClaim() *v1.NodeClaim {
 			GenerateName: fmt.Sprintf("%s-", i.NodePoolName),
 			Annotations:  i.Annotations,
 			Labels:       i.Labels,
+			Finalizers:   i.Finalizers,
 			OwnerReferences: []metav1.OwnerReference{
 				{
 					APIVersion:         object.GVK(&v1.NodePool{}).GroupVersion().String(),
@@ -93,3 +141,33 @@ func (i *NodeClaimTemplate) ToNodeClaim() *v1.NodeClaim {
 	nc.Spec.Requirements = i.Requirements.NodeSelectorRequirements()
 	return nc
 }
+
+// SimulatedRequests returns the requests that a pod is fit on the NodeClaims with, where the NodePool's simulated pod
+// requests stand in for the resources that the pod doesn't request
+func (i *NodeClaimTemplate) SimulatedRequests(requests corev1.ResourceList) corev1.ResourceList {
+	return simulatedRequests(requests, i.SimulatedPodRequests)
+}
+
+// simulatedRequests returns the requests with the simulated pod requests standing in for the resources that aren't
+// requested
+func simulatedRequests(requests, simulatedPodRequests corev1.ResourceList) corev1.ResourceList {
+	if len(simulatedPodRequests) == 0 {
+		return requests
+	}
+	simulated := resources.Merge(requests)
+	for name, quantity := range simulatedPodRequests {
+		if resources.IsZero(simulated[name]) {
+			simulated[name] = quantity
+		}
+	}
+	return simulated
+}
+
+// AllowsNamespace returns an error if the NodePool is restricted to namespaces that don't include the pod's namespace.
+// The NodePool's own headroom pods belong to the NodePool rather than a namespace, so they're always allowed.
+func (i *NodeClaimTemplate) AllowsNamespace(pod *corev1.Pod) error {
+	if i.AllowedNamespaces == nil || i.AllowedNamespaces.Has(pod.Namespace) || isHeadroomPodOf(pod, i.NodePoolName) {
+		return nil
+	}
+	return NewSchedulingError(FailureReasonNamespace, []string{pod.Namespace}, fmt.Errorf("namespace %q isn't allowed by nodepool %q", pod.Namespace, i.NodePoolName))
+}
@@ -0,0 +1,182 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduling
+
+import (
+	"fmt"
+
+	"github.com/mitchellh/hashstructure/v2"
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+	"sigs.k8s.io/karpenter/pkg/utils/resources"
+)
+
+// PodGroups batches the pending pods of an owner that the scheduler would treat identically, e.g. the replicas of a
+// Deployment. The scheduling decisions for the first pod of a group are reused for the rest of the group, so that
+// scheduling a 1000 replica Deployment doesn't check every replica against every node:
+//   - the scheduling data of the group is only computed once
+//   - nodes and NodeClaims that rejected a pod of the group aren't checked again for the other pods of the group, as
+//     nodes only have less room for the group as pods are scheduled
+//   - once a pod of the group fails to schedule, the other pods of the group fail with the same error until another pod
+//     schedules and may have launched a NodeClaim that the group fits on
+type PodGroups struct {
+	keys               map[types.UID]string
+	data               map[string]*PodData
+	rejectedNodes      map[string]sets.Set[*ExistingNode]
+	rejectedNodeClaims map[string]sets.Set[*NodeClaim]
+	errors             map[string]error
+}
+
+func NewPodGroups() *PodGroups {
+	return &PodGroups{
+		keys:               map[types.UID]string{},
+		data:               map[string]*PodData{},
+		rejectedNodes:      map[string]sets.Set[*ExistingNode]{},
+		rejectedNodeClaims: map[string]sets.Set[*NodeClaim]{},
+		errors:             map[string]error{},
+	}
+}
+
+// Add adds the pod to its group, returning the scheduling data of the group if another pod of the group was already
+// added. Pods that can't be batched aren't added to a group.
+func (g *PodGroups) Add(pod *corev1.Pod, exceedsPendingPodSLA bool) (*PodData, bool) {
+	key, ok := PodGroupKey(pod)
+	if !ok {
+		return nil, false
+	}
+	// pods that exceed the pending pod SLA can launch capacity that the other pods of the owner can't
+	key = fmt.Sprintf("%s/%t", key, exceedsPendingPodSLA)
+	g.keys[pod.UID] = key
+	data, ok := g.data[key]
+	return data, ok
+}
+
+// SetData stores the scheduling data of the pod's group
+func (g *PodGroups) SetData(pod *corev1.Pod, data *PodData) {
+	if key, ok := g.keys[pod.UID]; ok {
+		g.data[key] = data
+	}
+}
+
+// Remove removes the pod from its group. This needs to be called whenever the pod is relaxed, as the pod no longer has
+// the same constraints as the rest of its group.
+func (g *PodGroups) Remove(pod *corev1.Pod) {
+	delete(g.keys, pod.UID)
+}
+
+// RejectedNode returns true if the node rejected another pod of the pod's group
+func (g *PodGroups) RejectedNode(pod *corev1.Pod, node *ExistingNode) bool {
+	key, ok := g.keys[pod.UID]
+	return ok && g.rejectedNodes[key].Has(node)
+}
+
+// RejectNode records that the node rejected the pod's group
+func (g *PodGroups) RejectNode(pod *corev1.Pod, node *ExistingNode) {
+	if key, ok := g.keys[pod.UID]; ok {
+		if g.rejectedNodes[key] == nil {
+			g.rejectedNodes[key] = sets.New[*ExistingNode]()
+		}
+		g.rejectedNodes[key].Insert(node)
+	}
+}
+
+// RejectedNodeClaim returns true if the NodeClaim rejected another pod of the pod's group
+func (g *PodGroups) RejectedNodeClaim(pod *corev1.Pod, nodeClaim *NodeClaim) bool {
+	key, ok := g.keys[pod.UID]
+	return ok && g.rejectedNodeClaims[key].Has(nodeClaim)
+}
+
+// RejectNodeClaim records that the NodeClaim rejected the pod's group
+func (g *PodGroups) RejectNodeClaim(pod *corev1.Pod, nodeClaim *NodeClaim) {
+	if key, ok := g.keys[pod.UID]; ok {
+		if g.rejectedNodeClaims[key] == nil {
+			g.rejectedNodeClaims[key] = sets.New[*NodeClaim]()
+		}
+		g.rejectedNodeClaims[key].Insert(nodeClaim)
+	}
+}
+
+// Error returns the error that another pod of the pod's group failed to schedule with
+func (g *PodGroups) Error(pod *corev1.Pod) error {
+	if key, ok := g.keys[pod.UID]; ok {
+		return g.errors[key]
+	}
+	return nil
+}
+
+// Fail records the error that the pod's group failed to schedule with
+func (g *PodGroups) Fail(pod *corev1.Pod, err error) {
+	if key, ok := g.keys[pod.UID]; ok {
+		g.errors[key] = err
+	}
+}
+
+// Scheduled clears the errors of every group, as the pod that scheduled may have launched a NodeClaim that other
+// groups fit on
+func (g *PodGroups) Scheduled() {
+	if len(g.errors) > 0 {
+		g.errors = map[string]error{}
+	}
+}
+
+// PodGroupKey returns a key that is the same for the pods of an owner that the scheduler would treat identically, or
+// false if the pod can't be batched with other pods. Pods with topology spread constraints or pod (anti-)affinities
+// aren't batched, as where they can schedule depends on where the other pods of their group scheduled. Pods with
+// persistent volume claims aren't batched, as each pod's claims may be bound to different zones.
+func PodGroupKey(pod *corev1.Pod) (string, bool) {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil || IsHeadroomPod(pod) || len(pod.Spec.TopologySpreadConstraints) > 0 {
+		return "", false
+	}
+	if pod.Spec.Affinity != nil && (pod.Spec.Affinity.PodAffinity != nil || pod.Spec.Affinity.PodAntiAffinity != nil) {
+		return "", false
+	}
+	if lo.ContainsBy(pod.Spec.Volumes, func(v corev1.Volume) bool { return v.PersistentVolumeClaim != nil || v.Ephemeral != nil }) {
+		return "", false
+	}
+	var nodeAffinity *corev1.NodeAffinity
+	if pod.Spec.Affinity != nil {
+		nodeAffinity = pod.Spec.Affinity.NodeAffinity
+	}
+	// quantities are hashed as strings, as their values aren't exported
+	hash := lo.Must(hashstructure.Hash(struct {
+		Labels       map[string]string
+		NodePool     string
+		NodeSelector map[string]string
+		NodeAffinity *corev1.NodeAffinity
+		Tolerations  []corev1.Toleration
+		Priority     *int32
+		Requests     map[corev1.ResourceName]string
+		Ports        []corev1.ContainerPort
+	}{
+		Labels:       pod.Labels,
+		NodePool:     pod.Annotations[v1.NodePoolAnnotationKey],
+		NodeSelector: pod.Spec.NodeSelector,
+		NodeAffinity: nodeAffinity,
+		Tolerations:  pod.Spec.Tolerations,
+		Priority:     pod.Spec.Priority,
+		Requests:     lo.MapValues(resources.RequestsForPods(pod), func(q resource.Quantity, _ corev1.ResourceName) string { return q.String() }),
+		Ports:        lo.FlatMap(pod.Spec.Containers, func(c corev1.Container, _ int) []corev1.ContainerPort { return c.Ports }),
+	}, hashstructure.FormatV2, nil))
+	return fmt.Sprintf("%s/%d", owner.UID, hash), true
+}
@@ -23,6 +23,7 @@ import (
 
 	"github.com/samber/lo"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
@@ -33,11 +34,32 @@ type Preferences struct {
 	// ToleratePreferNoSchedule controls if preference relaxation adds a toleration for PreferNoSchedule taints.  This only
 	// helps if there is a corresponding taint, so we don't always add it.
 	ToleratePreferNoSchedule bool
+	// relaxed are the preferences that were removed from each pod, in the order that they were removed
+	relaxed map[types.UID][]string
+}
+
+// Relaxed returns the preferences that were removed from the pod so that it could schedule
+func (p *Preferences) Relaxed(pod *v1.Pod) []string {
+	return p.relaxed[pod.UID]
 }
 
 func (p *Preferences) Relax(ctx context.Context, pod *v1.Pod) bool {
+	return p.relax(ctx, pod, "it previously failed to schedule", append([]func(*v1.Pod) *string{p.removeRequiredNodeAffinityTerm}, p.softRelaxations()...))
+}
+
+// RelaxSoftConstraints removes all of the soft constraints of a pod that has been pending for too long at once rather
+// than one at a time, returning true if any were removed. Unlike Relax, the required node affinity terms of the pod are
+// left as they are.
+func (p *Preferences) RelaxSoftConstraints(ctx context.Context, pod *v1.Pod) bool {
+	relaxed := false
+	for p.relax(ctx, pod, "it exceeded the pending pod SLA", p.softRelaxations()) {
+		relaxed = true
+	}
+	return relaxed
+}
+
+func (p *Preferences) softRelaxations() []func(*v1.Pod) *string {
 	relaxations := []func(*v1.Pod) *string{
-		p.removeRequiredNodeAffinityTerm,
 		p.removePreferredPodAffinityTerm,
 		p.removePreferredPodAntiAffinityTerm,
 		p.removePreferredNodeAffinityTerm,
@@ -46,10 +68,17 @@ func (p *Preferences) Relax(ctx context.Context, pod *v1.Pod) bool {
 	if p.ToleratePreferNoSchedule {
 		relaxations = append(relaxations, p.toleratePreferNoScheduleTaints)
 	}
+	return relaxations
+}
 
+func (p *Preferences) relax(ctx context.Context, pod *v1.Pod, cause string, relaxations []func(*v1.Pod) *string) bool {
 	for _, relaxFunc := range relaxations {
 		if reason := relaxFunc(pod); reason != nil {
-			log.FromContext(ctx).WithValues("Pod", klog.KRef(pod.Namespace, pod.Name)).V(1).Info(fmt.Sprintf("relaxing soft constraints for pod since it previously failed to schedule, %s", lo.FromPtr(reason)))
+			log.FromContext(ctx).WithValues("Pod", klog.KRef(pod.Namespace, pod.Name)).V(1).Info(fmt.Sprintf("relaxing soft constraints for pod since %s, %s", cause, lo.FromPtr(reason)))
+			if p.relaxed == nil {
+				p.relaxed = map[types.UID][]string{}
+			}
+			p.relaxed[pod.UID] = append(p.relaxed[pod.UID], lo.FromPtr(reason))
 			return true
 		}
 	}
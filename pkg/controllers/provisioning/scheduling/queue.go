@@ -19,12 +19,27 @@ package scheduling
 import (
 	"sort"
 
+	"github.com/samber/lo"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 
 	"sigs.k8s.io/karpenter/pkg/utils/resources"
 )
 
+// PackingOrder is the order in which pending pods are packed onto nodes
+type PackingOrder string
+
+const (
+	// PackingOrderLargestFirst packs pods with the largest cpu and memory requests first, which generally results in
+	// the fewest nodes for homogeneous workloads
+	PackingOrderLargestFirst PackingOrder = "LargestFirst"
+	// PackingOrderPriorityFirst packs pods with the highest priority first so that capacity goes to the most important
+	// pods when NodePool limits are reached, packing pods of equal priority largest first
+	PackingOrderPriorityFirst PackingOrder = "PriorityFirst"
+	// PackingOrderFIFO packs the oldest pods first
+	PackingOrderFIFO PackingOrder = "FIFO"
+)
+
 // Queue is a queue of pods that is scheduled.  It's used to attempt to schedule pods as long as we are making progress
 // in scheduling. This is sometimes required to maintain zonal topology spreads with constrained pods, and can satisfy
 // pod affinities that occur in a batch of pods if there are enough constraints provided.
@@ -33,9 +48,17 @@ type Queue struct {
 	lastLen map[types.UID]int
 }
 
-// NewQueue constructs a new queue given the input pods, sorting them to optimize for bin-packing into nodes.
-func NewQueue(pods []*v1.Pod, podRequests map[types.UID]v1.ResourceList) *Queue {
-	sort.Slice(pods, byCPUAndMemoryDescending(pods, podRequests))
+// NewQueue constructs a new queue given the input pods, sorting them in the given packing order. Pods are sorted to
+// optimize for bin-packing into nodes if no packing order is given.
+func NewQueue(pods []*v1.Pod, podData map[types.UID]*PodData, order PackingOrder) *Queue {
+	switch order {
+	case PackingOrderPriorityFirst:
+		sort.Slice(pods, byPriorityDescending(pods, podData))
+	case PackingOrderFIFO:
+		sort.Slice(pods, byCreationTimestamp(pods))
+	default:
+		sort.Slice(pods, byCPUAndMemoryDescending(pods, podData))
+	}
 	return &Queue{
 		pods:    pods,
 		lastLen: map[types.UID]int{},
@@ -73,13 +96,37 @@ func (q *Queue) List() []*v1.Pod {
 	return q.pods
 }
 
-func byCPUAndMemoryDescending(pods []*v1.Pod, podRequests map[types.UID]v1.ResourceList) func(i int, j int) bool {
+func byPriorityDescending(pods []*v1.Pod, podData map[types.UID]*PodData) func(i int, j int) bool {
+	bySize := byCPUAndMemoryDescending(pods, podData)
+	return func(i, j int) bool {
+		lhs := lo.FromPtr(pods[i].Spec.Priority)
+		rhs := lo.FromPtr(pods[j].Spec.Priority)
+		if lhs != rhs {
+			return lhs > rhs
+		}
+		return bySize(i, j)
+	}
+}
+
+func byCreationTimestamp(pods []*v1.Pod) func(i int, j int) bool {
+	return func(i, j int) bool {
+		lhsPod := pods[i]
+		rhsPod := pods[j]
+		if lhsPod.CreationTimestamp != rhsPod.CreationTimestamp {
+			return lhsPod.CreationTimestamp.Before(&rhsPod.CreationTimestamp)
+		}
+		// pods created within the same second are ordered by UID to give a consistent ordering
+		return lhsPod.UID < rhsPod.UID
+	}
+}
+
+func byCPUAndMemoryDescending(pods []*v1.Pod, podData map[types.UID]*PodData) func(i int, j int) bool {
 	return func(i, j int) bool {
 		lhsPod := pods[i]
 		rhsPod := pods[j]
 
-		lhs := podRequests[lhsPod.UID]
-		rhs := podRequests[rhsPod.UID]
+		lhs := podData[lhsPod.UID].Requests
+		rhs := podData[rhsPod.UID].Requests
 
 		cpuCmp := resources.Cmp(lhs[v1.ResourceCPU], rhs[v1.ResourceCPU])
 		if cpuCmp < 0 {
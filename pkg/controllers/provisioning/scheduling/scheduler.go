@@ -19,15 +19,22 @@ package scheduling
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
 	"sort"
 	"time"
 
+	"github.com/awslabs/operatorpkg/option"
 	"github.com/samber/lo"
 	"go.uber.org/multierr"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/clock"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -44,10 +51,103 @@ import (
 	"sigs.k8s.io/karpenter/pkg/utils/resources"
 )
 
+type options struct {
+	preemptionAware        bool
+	strictNodePoolWeights  bool
+	strictStartupTaints    bool
+	packingOrder           PackingOrder
+	limitRanges            LimitRanges
+	pendingPodSLA          time.Duration
+	fragmentationThreshold resource.Quantity
+	preferExistingCapacity bool
+	staticPods             []*corev1.Pod
+	resourceAliases        resources.Aliases
+}
+
+type Options = option.Function[options]
+
+// PreemptionAware causes the scheduler to consider whether the kube-scheduler could fit a pending pod onto an existing
+// node by preempting lower priority pods before the scheduler decides to launch new capacity for that pod
+func PreemptionAware(o *options) {
+	o.preemptionAware = true
+}
+
+// PreferExistingCapacity causes the scheduler to hold pending pods for initializing nodes that they could fit on,
+// rather than launching new NodeClaims for them, while the nodes are within their NodePool's existing capacity window.
+// It's only used for provisioning, as disruption can't rely on pods fitting on a node that they don't fit on yet.
+func PreferExistingCapacity(o *options) {
+	o.preferExistingCapacity = true
+}
+
+// StrictNodePoolWeights causes the scheduler to only add pods to NodeClaims for lower weight NodePools when none of the
+// higher weight NodePools can satisfy them, rather than only using weights to order the NodePools for new NodeClaims
+func StrictNodePoolWeights(o *options) {
+	o.strictNodePoolWeights = true
+}
+
+// StrictStartupTaints causes the scheduler to only schedule pods to in-flight nodes if they tolerate the startup taints of
+// the node, rather than assuming that the startup taints will be removed once the node initializes
+func StrictStartupTaints(o *options) {
+	o.strictStartupTaints = true
+}
+
+// WithPackingOrder sets the order in which the scheduler packs pending pods onto nodes. If unset, pods are packed
+// largest first.
+func WithPackingOrder(order PackingOrder) Options {
+	return func(o *options) {
+		o.packingOrder = order
+	}
+}
+
+// WithLimitRanges causes the scheduler to apply the default requests and limits of the LimitRanges in each pod's
+// namespace to the containers that don't specify them before computing the pod's requests
+func WithLimitRanges(limitRanges LimitRanges) Options {
+	return func(o *options) {
+		o.limitRanges = limitRanges
+	}
+}
+
+// WithPendingPodSLA causes the scheduler to escalate scheduling for pods that have been pending for longer than the SLA.
+// Escalated pods have all of their soft constraints relaxed before they're scheduled, and aren't restricted to the
+// capacity types of a NodePool's capacity type split.
+func WithPendingPodSLA(sla time.Duration) Options {
+	return func(o *options) {
+		o.pendingPodSLA = sla
+	}
+}
+
+// WithFragmentationThreshold causes the scheduler to avoid launching NodeClaims with instance types that would leave
+// less than the threshold of cpu unused, but not none, when other instance types can hold the same pods. Such small
+// remainders are rarely enough for any other pod, so they're stranded for the lifetime of the node.
+func WithFragmentationThreshold(cpu resource.Quantity) Options {
+	return func(o *options) {
+		o.fragmentationThreshold = cpu
+	}
+}
+
+// WithStaticPods sets the static pods that the nodes launched for new NodeClaims are expected to run, in addition to
+// daemonset pods. Static pods are run by the kubelet rather than scheduled, so they're counted in the overhead of new
+// NodeClaims, but not of existing nodes, whose static pods are already running.
+func WithStaticPods(pods []*corev1.Pod) Options {
+	return func(o *options) {
+		o.staticPods = pods
+	}
+}
+
+// WithResourceAliases causes the scheduler to convert the aliased resources that pods request to fractions of their
+// target resources when fitting the pods on new NodeClaims, e.g. so that pods requesting MIG partitions and pods
+// requesting full GPUs binpack onto the GPUs that instance types advertise. Existing nodes advertise the aliased
+// resources themselves, so pods are fit on them using the resources that they request.
+func WithResourceAliases(aliases resources.Aliases) Options {
+	return func(o *options) {
+		o.resourceAliases = aliases
+	}
+}
+
 func NewScheduler(ctx context.Context, kubeClient client.Client, nodePools []*v1.NodePool,
 	cluster *state.Cluster, stateNodes []*state.StateNode, topology *Topology,
 	instanceTypes map[string][]*cloudprovider.InstanceType, daemonSetPods []*corev1.Pod,
-	recorder events.Recorder, clock clock.Clock) *Scheduler {
+	recorder events.Recorder, clock clock.Clock, opts ...Options) *Scheduler {
 
 	// if any of the nodePools add a taint with a prefer no schedule effect, we add a toleration for the taint
 	// during preference relaxation
@@ -59,50 +159,101 @@ func NewScheduler(ctx context.Context, kubeClient client.Client, nodePools []*v1
 			}
 		}
 	}
-	// Pre-filter instance types eligible for NodePools to reduce work done during scheduling loops for pods
-	templates := lo.FilterMap(nodePools, func(np *v1.NodePool, _ int) (*NodeClaimTemplate, bool) {
-		nct := NewNodeClaimTemplate(np)
-		nct.InstanceTypeOptions = filterInstanceTypesByRequirements(instanceTypes[np.Name], nct.Requirements, corev1.ResourceList{}).remaining
+	// Requirements on zone IDs and zone names are normalized into each other, so that pods and NodePools can select zones
+	// either way
+	zones := cloudprovider.InstanceTypes(lo.Flatten(lo.Values(instanceTypes))).ZoneMapping()
+	// Pre-filter instance types eligible for NodePools to reduce work done during scheduling loops for pods. NodePools
+	// are filtered in parallel, and the templates are merged back in NodePool order so that they remain ordered by weight.
+	templates := make([]*NodeClaimTemplate, len(nodePools))
+	workqueue.ParallelizeUntil(ctx, len(nodePools), len(nodePools), func(i int) {
+		nct := NewNodeClaimTemplate(nodePools[i])
+		nct.Requirements = zones.Normalize(nct.Requirements)
+		nct.InstanceTypeOptions = filterInstanceTypesByRequirements(instanceTypes[nodePools[i].Name], nct.Requirements, corev1.ResourceList{}, nil).remaining
+		templates[i] = nct
+	})
+	templates = lo.Filter(templates, func(nct *NodeClaimTemplate, i int) bool {
 		if len(nct.InstanceTypeOptions) == 0 {
-			recorder.Publish(NoCompatibleInstanceTypes(np))
-			log.FromContext(ctx).WithValues("NodePool", klog.KRef("", np.Name)).Info("skipping, nodepool requirements filtered out all instance types")
-			return nil, false
+			recorder.Publish(NoCompatibleInstanceTypes(nodePools[i]))
+			log.FromContext(ctx).WithValues("NodePool", klog.KRef("", nodePools[i].Name)).Info("skipping, nodepool requirements filtered out all instance types")
+			return false
 		}
-		return nct, true
+		return true
 	})
+	resolvedOpts := option.Resolve(opts...)
+	daemonSetPods = lo.Map(daemonSetPods, func(p *corev1.Pod, _ int) *corev1.Pod { return resolvedOpts.limitRanges.ApplyDefaults(p) })
 	s := &Scheduler{
 		id:                 uuid.NewUUID(),
 		kubeClient:         kubeClient,
 		nodeClaimTemplates: templates,
 		topology:           topology,
 		cluster:            cluster,
-		daemonOverhead:     getDaemonOverhead(templates, daemonSetPods),
-		cachedPodRequests:  map[types.UID]corev1.ResourceList{}, // cache pod requests to avoid having to continually recompute this total
+		daemonOverhead:     getDaemonOverhead(ctx, templates, slices.Concat(daemonSetPods, resolvedOpts.staticPods)),
+		cachedPodData:      map[types.UID]*PodData{}, // cache pod data to avoid having to continually recompute it
+		podGroups:          NewPodGroups(),
+		requirementsCache:  scheduling.NewPodRequirementsCache(),
+		zones:              zones,
 		recorder:           recorder,
 		preferences:        &Preferences{ToleratePreferNoSchedule: toleratePreferNoSchedule},
 		remainingResources: lo.SliceToMap(nodePools, func(np *v1.NodePool) (string, corev1.ResourceList) {
 			return np.Name, corev1.ResourceList(np.Spec.Limits)
 		}),
-		clock: clock,
+		domainLimits:           newDomainLimits(nodePools),
+		capacityTypeCounts:     map[string]map[string]int{},
+		clock:                  clock,
+		preemptionAware:        resolvedOpts.preemptionAware,
+		strictNodePoolWeights:  resolvedOpts.strictNodePoolWeights,
+		strictStartupTaints:    resolvedOpts.strictStartupTaints,
+		packingOrder:           resolvedOpts.packingOrder,
+		limitRanges:            resolvedOpts.limitRanges,
+		pendingPodSLA:          resolvedOpts.pendingPodSLA,
+		fragmentationThreshold: resolvedOpts.fragmentationThreshold,
+		preferExistingCapacity: resolvedOpts.preferExistingCapacity,
+		resourceAliases:        resolvedOpts.resourceAliases,
 	}
-	s.calculateExistingNodeClaims(stateNodes, daemonSetPods)
+	s.calculateExistingNodeClaims(stateNodes, daemonSetPods, instanceTypes)
 	return s
 }
 
+// PodData is the scheduling data for a pod that is computed once per scheduling attempt instead of once for every node
+// that the pod is checked against
+type PodData struct {
+	Requests           corev1.ResourceList
+	Requirements       scheduling.Requirements
+	StrictRequirements scheduling.Requirements
+	Volumes            scheduling.Volumes
+	// NodeClaimRequests are the requests that are fit on new NodeClaims, with aliased resources converted to fractions
+	// of the resources that instance types advertise
+	NodeClaimRequests corev1.ResourceList
+}
+
 type Scheduler struct {
-	id                 types.UID // Unique UUID attached to this scheduling loop
-	newNodeClaims      []*NodeClaim
-	existingNodes      []*ExistingNode
-	nodeClaimTemplates []*NodeClaimTemplate
-	remainingResources map[string]corev1.ResourceList // (NodePool name) -> remaining resources for that NodePool
-	daemonOverhead     map[*NodeClaimTemplate]corev1.ResourceList
-	cachedPodRequests  map[types.UID]corev1.ResourceList // (Pod Namespace/Name) -> calculated resource requests for the pod
-	preferences        *Preferences
-	topology           *Topology
-	cluster            *state.Cluster
-	recorder           events.Recorder
-	kubeClient         client.Client
-	clock              clock.Clock
+	id                     types.UID // Unique UUID attached to this scheduling loop
+	newNodeClaims          []*NodeClaim
+	existingNodes          []*ExistingNode
+	nodeClaimTemplates     []*NodeClaimTemplate
+	remainingResources     map[string]corev1.ResourceList // (NodePool name) -> remaining resources for that NodePool
+	capacityTypeCounts     map[string]map[string]int      // (NodePool name) -> (capacity type) -> number of NodeClaims
+	daemonOverhead         map[*NodeClaimTemplate]*daemonOverhead
+	cachedPodData          map[types.UID]*PodData // (Pod UID) -> calculated requests and requirements for the pod
+	podGroups              *PodGroups
+	domainLimits           *domainLimits
+	requirementsCache      *scheduling.PodRequirementsCache
+	zones                  *scheduling.ZoneMapping
+	preferences            *Preferences
+	topology               *Topology
+	cluster                *state.Cluster
+	recorder               events.Recorder
+	kubeClient             client.Client
+	clock                  clock.Clock
+	preemptionAware        bool
+	strictNodePoolWeights  bool
+	strictStartupTaints    bool
+	packingOrder           PackingOrder
+	limitRanges            LimitRanges
+	pendingPodSLA          time.Duration
+	fragmentationThreshold resource.Quantity
+	preferExistingCapacity bool
+	resourceAliases        resources.Aliases
 }
 
 // Results contains the results of the scheduling operation
@@ -110,6 +261,13 @@ type Results struct {
 	NewNodeClaims []*NodeClaim
 	ExistingNodes []*ExistingNode
 	PodErrors     map[*corev1.Pod]error
+	// RelaxedPreferences are the preferences that were removed from the pods that scheduled
+	RelaxedPreferences map[*corev1.Pod][]string
+	// TopologyDomains are the domains of each topology key that the pods could be spread across
+	TopologyDomains map[string]sets.Set[string]
+	// WaitingPods are the pods that are waiting for an initializing node that they could fit on, rather than triggering
+	// new NodeClaims
+	WaitingPods map[*corev1.Pod]*ExistingNode
 }
 
 // Record sends eventing and log messages back for the results that were produced from a scheduling run
@@ -118,14 +276,41 @@ type Results struct {
 func (r Results) Record(ctx context.Context, recorder events.Recorder, cluster *state.Cluster) {
 	// Report failures and nominations
 	for p, err := range r.PodErrors {
+		// Headroom pods only exist in memory, so there is nothing to report against
+		if IsHeadroomPod(p) {
+			continue
+		}
 		log.FromContext(ctx).WithValues("Pod", klog.KRef(p.Namespace, p.Name)).Error(err, "could not schedule pod")
 		recorder.Publish(PodFailedToScheduleEvent(p, err))
+		if failures := NodePoolFailures(err); len(failures) > 0 {
+			recorder.Publish(PodIncompatibleNodePoolsEvent(p, failures))
+			if rejected := lo.FilterMap(failures, func(f NodePoolFailure, _ int) (string, bool) {
+				return f.NodePoolName, f.Reason == FailureReasonNamespace
+			}); len(rejected) > 0 {
+				recorder.Publish(PodNamespaceNotAllowedEvent(p, rejected))
+			}
+		}
+	}
+	for p, relaxed := range r.RelaxedPreferences {
+		recorder.Publish(PodPreferencesRelaxedEvent(p, relaxed))
+	}
+	// Nodes are only nominated for real pods. Headroom pods are recreated every loop, so nominating nodes for them would
+	// keep the nodes nominated, and block their disruption, forever.
+	for p, node := range r.WaitingPods {
+		if IsHeadroomPod(p) {
+			continue
+		}
+		cluster.NominateNodeForPod(ctx, node.ProviderID())
+		recorder.Publish(PodWaitingForNodeEvent(p, node.Name()))
 	}
 	for _, existing := range r.ExistingNodes {
-		if len(existing.Pods) > 0 {
+		if lo.ContainsBy(existing.Pods, func(p *corev1.Pod) bool { return !IsHeadroomPod(p) }) {
 			cluster.NominateNodeForPod(ctx, existing.ProviderID())
 		}
 		for _, p := range existing.Pods {
+			if IsHeadroomPod(p) {
+				continue
+			}
 			recorder.Publish(NominatePodEvent(p, existing.Node, existing.NodeClaim))
 		}
 	}
@@ -138,6 +323,13 @@ func (r Results) Record(ctx context.Context, recorder events.Recorder, cluster *
 		return
 	}
 	log.FromContext(ctx).WithValues("nodeclaims", len(r.NewNodeClaims), "pods", newCount).Info("computed new nodeclaim(s) to fit pod(s)")
+	for _, nodeClaim := range r.NewNodeClaims {
+		for _, p := range nodeClaim.Pods {
+			if failures, ok := nodeClaim.fallbacks[p.UID]; ok && !IsHeadroomPod(p) {
+				recorder.Publish(PodNodePoolFallbackEvent(p, nodeClaim.NodePoolName, failures))
+			}
+		}
+	}
 	// Report in flight newNodes, or exit to avoid log spam
 	inflightCount := 0
 	existingCount := 0
@@ -189,8 +381,9 @@ func (r Results) TruncateInstanceTypes(maxInstanceTypes int) Results {
 	var validNewNodeClaims []*NodeClaim
 	for _, newNodeClaim := range r.NewNodeClaims {
 		// The InstanceTypeOptions are truncated due to limitations in sending the number of instances to launch API.
-		var err error
-		newNodeClaim.InstanceTypeOptions, err = newNodeClaim.InstanceTypeOptions.Truncate(newNodeClaim.Requirements, maxInstanceTypes)
+		truncated, err := newNodeClaim.InstanceTypeOptions.Truncate(newNodeClaim.Requirements, maxInstanceTypes, newNodeClaim.InstanceTypeTruncation)
+		newNodeClaim.truncated.Insert(lo.Map(lo.Without(newNodeClaim.InstanceTypeOptions, truncated...), func(it *cloudprovider.InstanceType, _ int) string { return it.Name })...)
+		newNodeClaim.InstanceTypeOptions = truncated
 		if err != nil {
 			// Check if the truncated InstanceTypeOptions in each NewNodeClaim from the results still satisfy the minimum requirements
 			// If number of InstanceTypes in the NodeClaim cannot satisfy the minimum requirements, add its Pods to error map with reason.
@@ -213,13 +406,25 @@ func (s *Scheduler) Solve(ctx context.Context, pods []*corev1.Pod) Results {
 	// had 5xA pods and 5xB pods were they have a zonal topology spread, but A can only go in one zone and B in another.
 	// We need to schedule them alternating, A, B, A, B, .... and this solution also solves that as well.
 	errors := map[*corev1.Pod]error{}
+	waiting := map[*corev1.Pod]*ExistingNode{}
 	// Reset the metric for the controller, so we don't keep old ids around
 	UnschedulablePodsCount.DeletePartialMatch(map[string]string{ControllerLabel: injection.GetControllerName(ctx)})
 	QueueDepth.DeletePartialMatch(map[string]string{ControllerLabel: injection.GetControllerName(ctx)})
 	for _, p := range pods {
-		s.cachedPodRequests[p.UID] = resources.RequestsForPods(p)
+		if s.exceedsPendingPodSLA(p) && s.preferences.RelaxSoftConstraints(ctx, p) {
+			if err := s.topology.Update(ctx, p); err != nil {
+				log.FromContext(ctx).Error(err, "failed updating topology")
+			}
+		}
+		// identical pods of the same owner share their scheduling data
+		if data, ok := s.podGroups.Add(p, s.exceedsPendingPodSLA(p)); ok {
+			s.cachedPodData[p.UID] = data
+			continue
+		}
+		s.updateCachedPodData(ctx, p)
+		s.podGroups.SetData(p, s.cachedPodData[p.UID])
 	}
-	q := NewQueue(pods, s.cachedPodRequests)
+	q := NewQueue(pods, s.cachedPodData, s.packingOrder)
 
 	startTime := s.clock.Now()
 	lastLogTime := s.clock.Now()
@@ -238,16 +443,30 @@ func (s *Scheduler) Solve(ctx context.Context, pods []*corev1.Pod) Results {
 			break
 		}
 
-		// Schedule to existing nodes or create a new node
-		if errors[pod] = s.add(ctx, pod); errors[pod] == nil {
+		// Schedule to existing nodes or create a new node, unless an identical pod of the same owner already failed to
+		if errors[pod] = s.podGroups.Error(pod); errors[pod] == nil {
+			errors[pod] = s.add(ctx, pod)
+		}
+		if errors[pod] == nil {
+			delete(errors, pod)
+			s.podGroups.Scheduled()
+			continue
+		}
+		if err, ok := errors[pod].(*waitingForNodeError); ok {
 			delete(errors, pod)
+			waiting[pod] = err.node
 			continue
 		}
 
 		// If unsuccessful, relax the pod and recompute topology
 		relaxed := s.preferences.Relax(ctx, pod)
 		q.Push(pod, relaxed)
-		if relaxed {
+		if !relaxed {
+			s.podGroups.Fail(pod, errors[pod])
+		} else {
+			// the relaxed pod no longer has the same constraints as the other pods of its owner
+			s.podGroups.Remove(pod)
+			s.updateCachedPodData(ctx, pod)
 			if err := s.topology.Update(ctx, pod); err != nil {
 				log.FromContext(ctx).Error(err, "failed updating topology")
 			}
@@ -255,74 +474,404 @@ func (s *Scheduler) Solve(ctx context.Context, pods []*corev1.Pod) Results {
 	}
 	UnfinishedWorkSeconds.Delete(map[string]string{ControllerLabel: injection.GetControllerName(ctx), schedulingIDLabel: string(s.id)})
 	for _, m := range s.newNodeClaims {
+		s.annotateRelaxedPreferences(m)
+		if !s.fragmentationThreshold.IsZero() {
+			m.RemoveFragmentingInstanceTypes(s.fragmentationThreshold)
+		}
 		m.FinalizeScheduling()
 	}
 
 	return Results{
-		NewNodeClaims: s.newNodeClaims,
-		ExistingNodes: s.existingNodes,
-		PodErrors:     errors,
+		NewNodeClaims:      s.newNodeClaims,
+		ExistingNodes:      s.existingNodes,
+		PodErrors:          errors,
+		RelaxedPreferences: s.relaxedPreferences(),
+		TopologyDomains:    s.topology.domains,
+		WaitingPods:        waiting,
+	}
+}
+
+// relaxedPreferences returns the preferences that were removed from each pod that scheduled. Headroom pods are
+// excluded as there is nothing to report against.
+func (s *Scheduler) relaxedPreferences() map[*corev1.Pod][]string {
+	relaxed := map[*corev1.Pod][]string{}
+	pods := lo.FlatMap(s.newNodeClaims, func(n *NodeClaim, _ int) []*corev1.Pod { return n.Pods })
+	pods = append(pods, lo.FlatMap(s.existingNodes, func(n *ExistingNode, _ int) []*corev1.Pod { return n.Pods })...)
+	for _, p := range pods {
+		if preferences := s.preferences.Relaxed(p); len(preferences) > 0 && !IsHeadroomPod(p) {
+			relaxed[p] = preferences
+		}
+	}
+	return relaxed
+}
+
+// annotateRelaxedPreferences annotates the NodeClaim with the preferences that were removed from each of its pods, so
+// that users can see why the pods were placed differently than the kube-scheduler would ideally place them
+func (s *Scheduler) annotateRelaxedPreferences(n *NodeClaim) {
+	relaxed := map[string][]string{}
+	for _, p := range n.Pods {
+		if preferences := s.preferences.Relaxed(p); len(preferences) > 0 && !IsHeadroomPod(p) {
+			relaxed[client.ObjectKeyFromObject(p).String()] = preferences
+		}
+	}
+	if len(relaxed) == 0 {
+		return
 	}
+	// The template's annotations are shared by every NodeClaim of the NodePool, so they're copied rather than modified
+	n.Annotations = lo.Assign(n.Annotations, map[string]string{v1.RelaxedPreferencesAnnotationKey: string(lo.Must(json.Marshal(relaxed)))})
+}
+
+// exceedsPendingPodSLA returns true if the pod has been pending for longer than the pending pod SLA, in which case its
+// scheduling is escalated. Pods are pending from when the kube-scheduler marked them unschedulable, or from when they
+// were created if it hasn't recorded when. Headroom pods are never pending, so their scheduling is never escalated.
+func (s *Scheduler) exceedsPendingPodSLA(p *corev1.Pod) bool {
+	if s.pendingPodSLA == 0 || IsHeadroomPod(p) {
+		return false
+	}
+	pendingSince := p.CreationTimestamp.Time
+	if condition, ok := lo.Find(p.Status.Conditions, func(c corev1.PodCondition) bool {
+		return c.Type == corev1.PodScheduled && c.Status == corev1.ConditionFalse
+	}); ok && !condition.LastTransitionTime.IsZero() {
+		pendingSince = condition.LastTransitionTime.Time
+	}
+	return s.clock.Since(pendingSince) > s.pendingPodSLA
+}
+
+// updateCachedPodData computes the scheduling data for the pod. This needs to be called again whenever the pod is
+// relaxed, as relaxing a pod's preferences changes its requirements.
+func (s *Scheduler) updateCachedPodData(ctx context.Context, p *corev1.Pod) {
+	requirements := s.requirementsCache.Get(p)
+	podData := &PodData{
+		Requests:           resources.RequestsForPods(s.limitRanges.ApplyDefaults(p)),
+		Requirements:       s.zones.Normalize(requirements.Requirements),
+		StrictRequirements: s.zones.Normalize(requirements.StrictRequirements),
+	}
+	podData.NodeClaimRequests = s.resourceAliases.Apply(podData.Requests)
+	// relaxing a pod doesn't change its volumes, so we only need to resolve them once
+	if cached, ok := s.cachedPodData[p.UID]; ok {
+		podData.Volumes = cached.Volumes
+	} else if volumes, err := scheduling.GetVolumes(ctx, s.kubeClient, p); err != nil {
+		log.FromContext(ctx).WithValues("Pod", klog.KObj(p)).Error(err, "failed resolving volumes")
+	} else {
+		podData.Volumes = volumes
+	}
+	s.cachedPodData[p.UID] = podData
 }
 
 func (s *Scheduler) add(ctx context.Context, pod *corev1.Pod) error {
-	// first try to schedule against an in-flight real node
+	// first try to schedule against an in-flight real node. Like the kube-scheduler, we only consider the nodes with
+	// PreferNoSchedule taints that the pod doesn't tolerate after the pod doesn't fit on any of the other nodes.
+	var preferNoScheduleNodes []*ExistingNode
 	for _, node := range s.existingNodes {
-		if err := node.Add(ctx, s.kubeClient, pod, s.cachedPodRequests[pod.UID]); err == nil {
+		if node.PrefersNoSchedule(pod) {
+			preferNoScheduleNodes = append(preferNoScheduleNodes, node)
+			continue
+		}
+		if s.addToExistingNode(ctx, pod, node) {
+			return nil
+		}
+	}
+	for _, node := range preferNoScheduleNodes {
+		if s.addToExistingNode(ctx, pod, node) {
 			return nil
 		}
 	}
 
+	// then see if the kube-scheduler would be able to make room for the pod on a node by preempting lower priority pods
+	// so that we don't launch capacity for the preemptor and its victims at the same time
+	if s.preemptionAware {
+		for _, node := range s.existingNodes {
+			if err := node.Preempt(ctx, s.kubeClient, pod, s.cachedPodData[pod.UID]); err == nil {
+				log.FromContext(ctx).V(1).WithValues("Pod", klog.KRef(pod.Namespace, pod.Name), "Node", klog.KRef("", node.Name())).Info("pod can schedule by preempting lower priority pods")
+				return nil
+			}
+		}
+	}
+
+	// then see if the pod can wait for an initializing node that it may fit on once the node initializes
+	if s.preferExistingCapacity {
+		if node, ok := lo.Find(s.existingNodes, func(n *ExistingNode) bool {
+			return n.Waitable(pod, s.cachedPodData[pod.UID], s.clock.Now())
+		}); ok {
+			return &waitingForNodeError{node: node}
+		}
+	}
+
+	requested, hasRequested := pod.Annotations[v1.NodePoolAnnotationKey]
+	if hasRequested && !lo.ContainsBy(s.nodeClaimTemplates, func(nct *NodeClaimTemplate) bool { return nct.NodePoolName == requested }) {
+		return fmt.Errorf("requested nodepool %q from the %s annotation doesn't exist or can't be used for provisioning", requested, v1.NodePoolAnnotationKey)
+	}
+	var err error
+	if s.strictNodePoolWeights {
+		err = s.addByWeight(ctx, pod)
+	} else {
+		err = s.addToNewNodeClaims(ctx, pod)
+	}
+	if err != nil && hasRequested {
+		return fmt.Errorf("requested nodepool %q from the %s annotation can't satisfy the pod, %w", requested, v1.NodePoolAnnotationKey, err)
+	}
+	return err
+}
+
+// addToExistingNode adds the pod to the node, skipping nodes that already rejected an identical pod of the same owner
+func (s *Scheduler) addToExistingNode(ctx context.Context, pod *corev1.Pod, node *ExistingNode) bool {
+	if s.podGroups.RejectedNode(pod, node) {
+		return false
+	}
+	if err := node.Add(ctx, s.kubeClient, pod, s.cachedPodData[pod.UID]); err != nil {
+		s.podGroups.RejectNode(pod, node)
+		return false
+	}
+	return true
+}
+
+// addToNodeClaim adds the pod to a NodeClaim that we are about to create, skipping NodeClaims that already rejected an
+// identical pod of the same owner
+func (s *Scheduler) addToNodeClaim(pod *corev1.Pod, nodeClaim *NodeClaim) bool {
+	if s.podGroups.RejectedNodeClaim(pod, nodeClaim) {
+		return false
+	}
+	if err := nodeClaim.Add(pod, s.cachedPodData[pod.UID]); err != nil {
+		s.podGroups.RejectNodeClaim(pod, nodeClaim)
+		return false
+	}
+	return true
+}
+
+// addToNewNodeClaims adds the pod to a NodeClaim that we are about to create, or to a new NodeClaim for the highest
+// weight NodePool that can satisfy it
+func (s *Scheduler) addToNewNodeClaims(ctx context.Context, pod *corev1.Pod) error {
 	// Consider using https://pkg.go.dev/container/heap
 	sort.Slice(s.newNodeClaims, func(a, b int) bool { return len(s.newNodeClaims[a].Pods) < len(s.newNodeClaims[b].Pods) })
 
 	// Pick existing node that we are about to create
 	for _, nodeClaim := range s.newNodeClaims {
-		if err := nodeClaim.Add(pod, s.cachedPodRequests[pod.UID]); err == nil {
+		if s.addToNodeClaim(pod, nodeClaim) {
 			return nil
 		}
 	}
 
 	// Create new node
+	if _, err := s.addToNewNodeClaim(ctx, pod, s.nodeClaimTemplates); err != nil {
+		return err
+	}
+	return nil
+}
+
+// addByWeight adds the pod to a NodeClaim for the highest weight NodePools that can satisfy it. Unlike
+// addToNewNodeClaims, the pod isn't added to a NodeClaim that we are about to create for a lower weight NodePool unless
+// none of the higher weight NodePools can satisfy it. NodePools with equal weights are treated the same way as
+// addToNewNodeClaims treats all NodePools.
+func (s *Scheduler) addByWeight(ctx context.Context, pod *corev1.Pod) error {
 	var errs error
-	for _, nodeClaimTemplate := range s.nodeClaimTemplates {
-		instanceTypes := nodeClaimTemplate.InstanceTypeOptions
-		// if limits have been applied to the nodepool, ensure we filter instance types to avoid violating those limits
-		if remaining, ok := s.remainingResources[nodeClaimTemplate.NodePoolName]; ok {
-			instanceTypes = filterByRemainingResources(instanceTypes, remaining)
-			if len(instanceTypes) == 0 {
-				errs = multierr.Append(errs, fmt.Errorf("all available instance types exceed limits for nodepool: %q", nodeClaimTemplate.NodePoolName))
-				continue
-			} else if len(nodeClaimTemplate.InstanceTypeOptions) != len(instanceTypes) {
-				log.FromContext(ctx).V(1).WithValues("NodePool", klog.KRef("", nodeClaimTemplate.NodePoolName)).Info(fmt.Sprintf("%d out of %d instance types were excluded because they would breach limits",
-					len(nodeClaimTemplate.InstanceTypeOptions)-len(instanceTypes), len(nodeClaimTemplate.InstanceTypeOptions)))
+	var failures []NodePoolFailure
+	rejectedInstanceTypes := map[string]func() map[string]FailureReason{}
+	// nodeClaimTemplates are ordered by weight, so we walk them in groups of equal weight
+	for i := 0; i < len(s.nodeClaimTemplates); {
+		weight := s.nodeClaimTemplates[i].NodePoolWeight
+		j := i
+		for j < len(s.nodeClaimTemplates) && s.nodeClaimTemplates[j].NodePoolWeight == weight {
+			j++
+		}
+		templates := s.nodeClaimTemplates[i:j]
+		i = j
+
+		newNodeClaims := lo.Filter(s.newNodeClaims, func(nc *NodeClaim, _ int) bool { return nc.NodePoolWeight == weight })
+		sort.Slice(newNodeClaims, func(a, b int) bool { return len(newNodeClaims[a].Pods) < len(newNodeClaims[b].Pods) })
+		for _, nodeClaim := range newNodeClaims {
+			if s.addToNodeClaim(pod, nodeClaim) {
+				nodeClaim.recordFallback(pod, failures)
+				return nil
 			}
 		}
-		nodeClaim := NewNodeClaim(nodeClaimTemplate, s.topology, s.daemonOverhead[nodeClaimTemplate], instanceTypes)
-		if err := nodeClaim.Add(pod, s.cachedPodRequests[pod.UID]); err != nil {
-			nodeClaim.Destroy() // Ensure we cleanup any changes that we made while mocking out a NodeClaim
+		nodeClaim, err := s.addToNewNodeClaim(ctx, pod, templates)
+		if err != nil {
+			var nodePoolsErr *NodePoolsError
+			if errors.As(err, &nodePoolsErr) {
+				errs = multierr.Append(errs, nodePoolsErr.err)
+				failures = append(failures, nodePoolsErr.Failures...)
+				rejectedInstanceTypes = lo.Assign(rejectedInstanceTypes, nodePoolsErr.rejectedInstanceTypes)
+			}
+			continue
+		}
+		// pods that request a nodepool have no NodeClaim for the groups that don't contain that nodepool
+		if nodeClaim != nil {
+			nodeClaim.recordFallback(pod, failures)
+			return nil
+		}
+	}
+	if errs != nil {
+		return &NodePoolsError{Failures: failures, rejectedInstanceTypes: rejectedInstanceTypes, err: errs}
+	}
+	return nil
+}
+
+// minTemplatesToFilterInParallel is the number of NodeClaimTemplates from which addToNewNodeClaim filters their instance
+// types for a pod in parallel. Below it, starting the goroutines for every pod costs more than filtering serially.
+const minTemplatesToFilterInParallel = 4
+
+// addToNewNodeClaim adds the pod to a new NodeClaim for the first of the NodeClaimTemplates that can satisfy it,
+// returning the NodeClaim that was created
+func (s *Scheduler) addToNewNodeClaim(ctx context.Context, pod *corev1.Pod, templates []*NodeClaimTemplate) (*NodeClaim, error) {
+	var errs error
+	var failures []NodePoolFailure
+	rejectedInstanceTypes := map[string]func() map[string]FailureReason{}
+	requested, hasRequested := pod.Annotations[v1.NodePoolAnnotationKey]
+	// With many NodeClaimTemplates, the instance types of each are filtered for the pod in parallel. The templates are
+	// then tried one at a time in weight order, so the NodeClaim that is created doesn't depend on which template
+	// finished filtering first. With few templates, each is only filtered once it's tried.
+	withinLimits := make([][]*cloudprovider.InstanceType, len(templates))
+	instanceTypes := make([][]*cloudprovider.InstanceType, len(templates))
+	parallel := len(templates) >= minTemplatesToFilterInParallel
+	if parallel {
+		workqueue.ParallelizeUntil(ctx, len(templates), len(templates), func(i int) {
+			// pods that request a nodepool can only ever be compatible with that nodepool
+			if hasRequested && templates[i].NodePoolName != requested {
+				return
+			}
+			if templates[i].AllowsNamespace(pod) != nil {
+				return
+			}
+			withinLimits[i], instanceTypes[i] = s.filterInstanceTypesForPod(templates[i], s.cachedPodData[pod.UID])
+		})
+	}
+	for i, nodeClaimTemplate := range templates {
+		if hasRequested && nodeClaimTemplate.NodePoolName != requested {
+			continue
+		}
+		if err := nodeClaimTemplate.AllowsNamespace(pod); err != nil {
+			errs = multierr.Append(errs, err)
+			failures = append(failures, newNodePoolFailure(nodeClaimTemplate.NodePoolName, err))
+			continue
+		}
+		if !parallel {
+			withinLimits[i], instanceTypes[i] = s.filterInstanceTypesForPod(nodeClaimTemplate, s.cachedPodData[pod.UID])
+		}
+		// if limits have been applied to the nodepool, we've filtered instance types to avoid violating those limits
+		if len(withinLimits[i]) == 0 {
+			errs = multierr.Append(errs, fmt.Errorf("all available instance types exceed limits for nodepool: %q", nodeClaimTemplate.NodePoolName))
+			failures = append(failures, NodePoolFailure{NodePoolName: nodeClaimTemplate.NodePoolName, Reason: FailureReasonLimits})
+			continue
+		} else if len(nodeClaimTemplate.InstanceTypeOptions) != len(withinLimits[i]) {
+			log.FromContext(ctx).V(1).WithValues("NodePool", klog.KRef("", nodeClaimTemplate.NodePoolName)).Info(fmt.Sprintf("%d out of %d instance types were excluded because they would breach limits",
+				len(nodeClaimTemplate.InstanceTypeOptions)-len(withinLimits[i]), len(nodeClaimTemplate.InstanceTypeOptions)))
+		}
+		nodeClaim, err := s.newNodeClaimForPod(pod, nodeClaimTemplate, instanceTypes[i])
+		if err != nil {
 			errs = multierr.Append(errs, fmt.Errorf("incompatible with nodepool %q, daemonset overhead=%s, %w",
 				nodeClaimTemplate.NodePoolName,
-				resources.String(s.daemonOverhead[nodeClaimTemplate]),
+				resources.String(s.daemonOverhead[nodeClaimTemplate].min(instanceTypes[i])),
 				err))
+			failures = append(failures, newNodePoolFailure(nodeClaimTemplate.NodePoolName, err))
+			var schedulingErr *SchedulingError
+			if errors.As(err, &schedulingErr) && schedulingErr.rejectedInstanceTypes != nil {
+				rejectedInstanceTypes[nodeClaimTemplate.NodePoolName] = schedulingErr.rejectedInstanceTypes
+			}
 			continue
 		}
 		// we will launch this nodeClaim and need to track its maximum possible resource usage against our remaining resources
 		s.newNodeClaims = append(s.newNodeClaims, nodeClaim)
 		s.remainingResources[nodeClaimTemplate.NodePoolName] = subtractMax(s.remainingResources[nodeClaimTemplate.NodePoolName], nodeClaim.InstanceTypeOptions)
-		return nil
+		return nodeClaim, nil
+	}
+	if errs != nil {
+		return nil, &NodePoolsError{Failures: failures, err: errs}
+	}
+	return nil, nil
+}
+
+// newNodeClaimForPod creates a NodeClaim from the NodeClaimTemplate with the pod added to it. If the NodePool has a
+// capacity type split, the NodeClaim is restricted to the capacity type that is furthest below its percentage that can
+// satisfy the pod, unless the pod has exceeded the pending pod SLA.
+func (s *Scheduler) newNodeClaimForPod(pod *corev1.Pod, nodeClaimTemplate *NodeClaimTemplate, instanceTypes []*cloudprovider.InstanceType) (*NodeClaim, error) {
+	if len(nodeClaimTemplate.CapacityTypeSplit) == 0 || s.exceedsPendingPodSLA(pod) {
+		nodeClaim := NewNodeClaim(nodeClaimTemplate, s.topology, s.domainLimits, s.daemonOverhead[nodeClaimTemplate], instanceTypes)
+		if err := nodeClaim.Add(pod, s.cachedPodData[pod.UID]); err != nil {
+			nodeClaim.Destroy() // Ensure we cleanup any changes that we made while mocking out a NodeClaim
+			return nil, err
+		}
+		return nodeClaim, nil
+	}
+	var errs error
+	for _, capacityType := range capacityTypesBySplit(nodeClaimTemplate.CapacityTypeSplit, s.capacityTypeCounts[nodeClaimTemplate.NodePoolName]) {
+		nodeClaim := NewNodeClaim(nodeClaimTemplate, s.topology, s.domainLimits, s.daemonOverhead[nodeClaimTemplate], instanceTypes)
+		nodeClaim.Requirements.Add(scheduling.NewRequirement(v1.CapacityTypeLabelKey, corev1.NodeSelectorOpIn, capacityType))
+		if err := nodeClaim.Add(pod, s.cachedPodData[pod.UID]); err != nil {
+			nodeClaim.Destroy() // Ensure we cleanup any changes that we made while mocking out a NodeClaim
+			errs = multierr.Append(errs, fmt.Errorf("capacity type %q, %w", capacityType, err))
+			continue
+		}
+		if _, ok := s.capacityTypeCounts[nodeClaimTemplate.NodePoolName]; !ok {
+			s.capacityTypeCounts[nodeClaimTemplate.NodePoolName] = map[string]int{}
+		}
+		s.capacityTypeCounts[nodeClaimTemplate.NodePoolName][capacityType]++
+		return nodeClaim, nil
+	}
+	if errs == nil {
+		return nil, fmt.Errorf("capacity type split doesn't allow any capacity types")
+	}
+	return nil, errs
+}
+
+// capacityTypeOf returns the capacity type of the node, falling back to the requirements of its NodeClaim for NodeClaims
+// that haven't launched yet
+func capacityTypeOf(node *state.StateNode) (string, bool) {
+	if capacityType, ok := node.Labels()[v1.CapacityTypeLabelKey]; ok {
+		return capacityType, true
+	}
+	if node.NodeClaim == nil {
+		return "", false
+	}
+	if requirement := scheduling.NewNodeSelectorRequirementsWithMinValues(node.NodeClaim.Spec.Requirements...).Get(v1.CapacityTypeLabelKey); requirement.Operator() == corev1.NodeSelectorOpIn && requirement.Len() == 1 {
+		return requirement.Any(), true
+	}
+	return "", false
+}
+
+// filterInstanceTypesForPod returns the instance types of the NodeClaimTemplate that are within the NodePool's limits,
+// and the subset of those that could fit the pod on a new NodeClaim. It doesn't modify scheduler state, so it's safe to
+// call for many templates in parallel. Topology requirements are only known once a NodeClaim is constructed and can
+// only narrow the instance types further, so if the pod can't fit on any instance type we return all of those within
+// limits and leave NodeClaim.Add to report why.
+func (s *Scheduler) filterInstanceTypesForPod(nodeClaimTemplate *NodeClaimTemplate, podData *PodData) (withinLimits, compatible []*cloudprovider.InstanceType) {
+	withinLimits = nodeClaimTemplate.InstanceTypeOptions
+	if remaining, ok := s.remainingResources[nodeClaimTemplate.NodePoolName]; ok {
+		withinLimits = filterByRemainingResources(withinLimits, remaining)
 	}
-	return errs
+	requirements := scheduling.NewRequirements(nodeClaimTemplate.Requirements.Values()...)
+	if err := requirements.Compatible(podData.Requirements, scheduling.AllowUndefinedWellKnownLabels); err != nil {
+		return withinLimits, withinLimits
+	}
+	requirements.Add(podData.Requirements.Values()...)
+	filtered := filterInstanceTypesByRequirements(withinLimits, requirements, nodeClaimTemplate.SimulatedRequests(podData.NodeClaimRequests), s.daemonOverhead[nodeClaimTemplate].allocatable)
+	if len(filtered.remaining) == 0 {
+		return withinLimits, withinLimits
+	}
+	return withinLimits, filtered.remaining
 }
 
-func (s *Scheduler) calculateExistingNodeClaims(stateNodes []*state.StateNode, daemonSetPods []*corev1.Pod) {
+func (s *Scheduler) calculateExistingNodeClaims(stateNodes []*state.StateNode, daemonSetPods []*corev1.Pod, instanceTypes map[string][]*cloudprovider.InstanceType) {
 	// create our existing nodes
 	for _, node := range stateNodes {
+		// Nodes that haven't registered yet don't report the volume limits of their CSI drivers, so we fall back to the
+		// volume limits of their instance type
+		if node.Node == nil {
+			if it, ok := lo.Find(instanceTypes[node.Labels()[v1.NodePoolLabelKey]], func(it *cloudprovider.InstanceType) bool {
+				return it.Name == node.Labels()[corev1.LabelInstanceTypeStable]
+			}); ok {
+				for driver, limit := range it.VolumeLimits {
+					if !node.VolumeUsage().HasLimit(driver) {
+						node.VolumeUsage().AddLimit(driver, limit)
+					}
+				}
+			}
+		}
 		// Calculate any daemonsets that should schedule to the inflight node
 		taints := node.Taints()
 		var daemons []*corev1.Pod
 		for _, p := range daemonSetPods {
-			if err := scheduling.Taints(taints).Tolerates(p); err != nil {
+			// daemonsets are scheduled regardless of PreferNoSchedule taints
+			if err := scheduling.Taints(taints).WithoutEffect(corev1.TaintEffectPreferNoSchedule).Tolerates(p); err != nil {
 				continue
 			}
 			if err := scheduling.NewLabelRequirements(node.Labels()).Compatible(scheduling.NewPodRequirements(p)); err != nil {
@@ -330,13 +879,33 @@ func (s *Scheduler) calculateExistingNodeClaims(stateNodes []*state.StateNode, d
 			}
 			daemons = append(daemons, p)
 		}
-		s.existingNodes = append(s.existingNodes, NewExistingNode(node, s.topology, taints, resources.RequestsForPods(daemons...)))
+		// Startup taints are removed before daemonsets are expected to schedule, so they're only considered for pods
+		if s.strictStartupTaints {
+			taints = slices.Concat(taints, node.StartupTaints())
+		}
+		existingNode := NewExistingNode(node, s.topology, taints, resources.RequestsForPods(daemons...))
+		// Pods in namespaces that the node's NodePool doesn't allow aren't considered for the node
+		if nct, ok := lo.Find(s.nodeClaimTemplates, func(nct *NodeClaimTemplate) bool { return nct.NodePoolName == node.Labels()[v1.NodePoolLabelKey] }); ok {
+			existingNode.allowedNamespaces = nct.AllowedNamespaces
+			existingNode.existingCapacityWindow = nct.ExistingCapacityWindow
+			existingNode.simulatedPodRequests = nct.SimulatedPodRequests
+		}
+		s.existingNodes = append(s.existingNodes, existingNode)
 
 		// We don't use the status field and instead recompute the remaining resources to ensure we have a consistent view
 		// of the cluster during scheduling.  Depending on how node creation falls out, this will also work for cases where
 		// we don't create NodeClaim resources.
 		if _, ok := s.remainingResources[node.Labels()[v1.NodePoolLabelKey]]; ok {
-			s.remainingResources[node.Labels()[v1.NodePoolLabelKey]] = resources.Subtract(s.remainingResources[node.Labels()[v1.NodePoolLabelKey]], node.Capacity())
+			s.remainingResources[node.Labels()[v1.NodePoolLabelKey]] = resources.Subtract(s.remainingResources[node.Labels()[v1.NodePoolLabelKey]], withNode(node.Capacity()))
+		}
+		s.domainLimits.subtractNode(node)
+		// Track the capacity types of the NodePool's nodes so that new NodeClaims can follow the NodePool's capacity type split
+		if capacityType, ok := capacityTypeOf(node); ok {
+			nodePoolName := node.Labels()[v1.NodePoolLabelKey]
+			if _, ok := s.capacityTypeCounts[nodePoolName]; !ok {
+				s.capacityTypeCounts[nodePoolName] = map[string]int{}
+			}
+			s.capacityTypeCounts[nodePoolName][capacityType]++
 		}
 	}
 	// Order the existing nodes for scheduling with initialized nodes first
@@ -353,35 +922,6 @@ func (s *Scheduler) calculateExistingNodeClaims(stateNodes []*state.StateNode, d
 	})
 }
 
-// getDaemonOverhead determines the overhead for each NodeClaimTemplate required for daemons to schedule for any node provisioned by the NodeClaimTemplate
-func getDaemonOverhead(nodeClaimTemplates []*NodeClaimTemplate, daemonSetPods []*corev1.Pod) map[*NodeClaimTemplate]corev1.ResourceList {
-	return lo.SliceToMap(nodeClaimTemplates, func(nct *NodeClaimTemplate) (*NodeClaimTemplate, corev1.ResourceList) {
-		return nct, resources.RequestsForPods(lo.Filter(daemonSetPods, func(p *corev1.Pod, _ int) bool { return isDaemonPodCompatible(nct, p) })...)
-	})
-}
-
-// isDaemonPodCompatible determines if the daemon pod is compatible with the NodeClaimTemplate for daemon scheduling
-func isDaemonPodCompatible(nodeClaimTemplate *NodeClaimTemplate, pod *corev1.Pod) bool {
-	preferences := &Preferences{}
-	// Add a toleration for PreferNoSchedule since a daemon pod shouldn't respect the preference
-	_ = preferences.toleratePreferNoScheduleTaints(pod)
-	if err := scheduling.Taints(nodeClaimTemplate.Spec.Taints).Tolerates(pod); err != nil {
-		return false
-	}
-	for {
-		// We don't consider pod preferences for scheduling requirements since we know that pod preferences won't matter with Daemonset scheduling
-		if nodeClaimTemplate.Requirements.IsCompatible(scheduling.NewStrictPodRequirements(pod), scheduling.AllowUndefinedWellKnownLabels) {
-			return true
-		}
-		// If relaxing the Node Affinity term didn't succeed, then this DaemonSet can't schedule to this NodePool
-		// We don't consider other forms of relaxation here since we don't consider pod affinities/anti-affinities
-		// when considering DaemonSet schedulability
-		if preferences.removeRequiredNodeAffinityTerm(pod) == nil {
-			return false
-		}
-	}
-}
-
 // subtractMax returns the remaining resources after subtracting the max resource quantity per instance type. To avoid
 // overshooting out, we need to pessimistically assume that if e.g. we request a 2, 4 or 8 CPU instance type
 // that the 8 CPU instance type is all that will be available.  This could cause a batch of pods to take multiple rounds
@@ -396,7 +936,7 @@ func subtractMax(remaining corev1.ResourceList, instanceTypes []*cloudprovider.I
 		allInstanceResources = append(allInstanceResources, it.Capacity)
 	}
 	result := corev1.ResourceList{}
-	itResources := resources.MaxResources(allInstanceResources...)
+	itResources := withNode(resources.MaxResources(allInstanceResources...))
 	for k, v := range remaining {
 		cp := v.DeepCopy()
 		cp.Sub(itResources[k])
@@ -405,8 +945,17 @@ func subtractMax(remaining corev1.ResourceList, instanceTypes []*cloudprovider.I
 	return result
 }
 
+// withNode returns the capacity of a node along with the node itself, so that NodePools can limit their number of nodes
+func withNode(capacity corev1.ResourceList) corev1.ResourceList {
+	return resources.Merge(capacity, corev1.ResourceList{v1.ResourceNodes: resource.MustParse("1")})
+}
+
 // filterByRemainingResources is used to filter out instance types that if launched would exceed the nodepool limits
 func filterByRemainingResources(instanceTypes []*cloudprovider.InstanceType, remaining corev1.ResourceList) []*cloudprovider.InstanceType {
+	// every instance type launches a single node, so none of them are viable once the NodePool's node limit is reached
+	if nodes, ok := remaining[v1.ResourceNodes]; ok && nodes.Cmp(resource.MustParse("1")) < 0 {
+		return nil
+	}
 	var filtered []*cloudprovider.InstanceType
 	for _, it := range instanceTypes {
 		itResources := it.Capacity
@@ -423,3 +972,12 @@ func filterByRemainingResources(instanceTypes []*cloudprovider.InstanceType, rem
 	}
 	return filtered
 }
+
+// waitingForNodeError is returned when a pod is held for an initializing node instead of triggering a new NodeClaim
+type waitingForNodeError struct {
+	node *ExistingNode
+}
+
+func (e *waitingForNodeError) Error() string {
+	return fmt.Sprintf("waiting for initializing node %q", e.node.Name())
+}
@@ -168,7 +168,7 @@ func benchmarkScheduler(b *testing.B, instanceCount, podCount int) {
 	clock := &clock.RealClock{}
 	cluster = state.NewCluster(clock, client, cloudProvider)
 	domains := map[string]sets.Set[string]{}
-	topology, err := scheduling.NewTopology(ctx, client, cluster, domains, pods)
+	topology, err := scheduling.NewTopology(ctx, cluster, domains, pods)
 	if err != nil {
 		b.Fatalf("creating topology, %s", err)
 	}
@@ -0,0 +1,124 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduling
+
+import (
+	"sort"
+	"time"
+
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+	"sigs.k8s.io/karpenter/pkg/cloudprovider"
+)
+
+// The snapshot is a single object, so it's bounded to stay well within the size limits of the API server, no matter the
+// size of the cluster. Only the first of the nodes, NodeClaims and pod errors are recorded, along with the first of the
+// pods and instance types of each.
+const (
+	maxSnapshotItems       = 50
+	maxSnapshotNames       = 20
+	maxSnapshotErrorLength = 1024
+)
+
+// Snapshot describes the results in a SchedulingSnapshot, so that the inputs and decisions of the scheduling loop can
+// be inspected after the fact. Everything is sorted so that snapshots of identical results are identical. Headroom pods
+// only exist in memory, so they aren't included.
+func (r Results) Snapshot(now time.Time) v1.SchedulingSnapshotSpec {
+	spec := v1.SchedulingSnapshotSpec{Time: metav1.NewTime(now)}
+	for _, n := range r.ExistingNodes {
+		spec.ExistingNodes = append(spec.ExistingNodes, v1.SchedulingSnapshotNode{
+			Name:         n.Name(),
+			NodePool:     n.Labels()[v1.NodePoolLabelKey],
+			InstanceType: n.Labels()[corev1.LabelInstanceTypeStable],
+			InFlight:     !n.Initialized(),
+			Pods:         truncate(snapshotPods(n.Pods), maxSnapshotNames, &spec.Truncated),
+		})
+	}
+	sort.Slice(spec.ExistingNodes, func(i, j int) bool { return spec.ExistingNodes[i].Name < spec.ExistingNodes[j].Name })
+	spec.ExistingNodes = truncate(spec.ExistingNodes, maxSnapshotItems, &spec.Truncated)
+	for _, n := range truncate(r.NewNodeClaims, maxSnapshotItems, &spec.Truncated) {
+		spec.NodeClaims = append(spec.NodeClaims, v1.SchedulingSnapshotNodeClaim{
+			NodePool: n.NodePoolName,
+			InstanceTypes: truncate(lo.Map(n.InstanceTypeOptions, func(it *cloudprovider.InstanceType, _ int) string {
+				return it.Name
+			}), maxSnapshotNames, &spec.Truncated),
+			Pods:                  truncate(snapshotPods(n.Pods), maxSnapshotNames, &spec.Truncated),
+			RejectedInstanceTypes: truncate(snapshotRejectedInstanceTypes(n.RejectedInstanceTypes()), maxSnapshotNames, &spec.Truncated),
+		})
+	}
+	if len(r.TopologyDomains) > 0 {
+		spec.TopologyDomains = lo.MapValues(r.TopologyDomains, func(domains sets.Set[string], _ string) []string {
+			return truncate(sets.List(domains), maxSnapshotItems, &spec.Truncated)
+		})
+	}
+	for p, err := range r.PodErrors {
+		if IsHeadroomPod(p) {
+			continue
+		}
+		rejected := RejectedInstanceTypes(err)
+		message := err.Error()
+		if len(message) > maxSnapshotErrorLength {
+			message = message[:maxSnapshotErrorLength] + "..."
+			spec.Truncated = true
+		}
+		spec.PodErrors = append(spec.PodErrors, v1.SchedulingSnapshotPodError{
+			Pod:   klog.KObj(p).String(),
+			Error: message,
+			Failures: lo.Map(NodePoolFailures(err), func(f NodePoolFailure, _ int) v1.SchedulingSnapshotNodePoolFailure {
+				return v1.SchedulingSnapshotNodePoolFailure{
+					NodePool:              f.NodePoolName,
+					Reason:                string(f.Reason),
+					Details:               f.Details,
+					RejectedInstanceTypes: truncate(snapshotRejectedInstanceTypes(rejected[f.NodePoolName]), maxSnapshotNames, &spec.Truncated),
+				}
+			}),
+		})
+	}
+	sort.Slice(spec.PodErrors, func(i, j int) bool { return spec.PodErrors[i].Pod < spec.PodErrors[j].Pod })
+	spec.PodErrors = truncate(spec.PodErrors, maxSnapshotItems, &spec.Truncated)
+	return spec
+}
+
+// truncate returns the first max of the items, recording whether any were left out
+func truncate[T any](items []T, max int, truncated *bool) []T {
+	if len(items) <= max {
+		return items
+	}
+	*truncated = true
+	return items[:max]
+}
+
+func snapshotPods(pods []*corev1.Pod) []string {
+	names := lo.FilterMap(pods, func(p *corev1.Pod, _ int) (string, bool) {
+		return klog.KObj(p).String(), !IsHeadroomPod(p)
+	})
+	sort.Strings(names)
+	return names
+}
+
+func snapshotRejectedInstanceTypes(rejected map[string]FailureReason) []v1.SchedulingSnapshotInstanceTypeRejection {
+	rejections := lo.MapToSlice(rejected, func(name string, reason FailureReason) v1.SchedulingSnapshotInstanceTypeRejection {
+		return v1.SchedulingSnapshotInstanceTypeRejection{InstanceType: name, Reason: string(reason)}
+	})
+	sort.Slice(rejections, func(i, j int) bool { return rejections[i].InstanceType < rejections[j].InstanceType })
+	return rejections
+}
@@ -0,0 +1,91 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduling
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/samber/lo"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// StatefulSetOrdinal returns the ordinal of a pod of a StatefulSet, which is the suffix of the pod's name
+func StatefulSetOrdinal(statefulSet *appsv1.StatefulSet, pod *corev1.Pod) (int32, bool) {
+	suffix, ok := strings.CutPrefix(pod.Name, statefulSet.Name+"-")
+	if !ok {
+		return 0, false
+	}
+	ordinal, err := strconv.ParseInt(suffix, 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	return int32(ordinal), true
+}
+
+// NewStatefulSetPods returns the in-memory pods for the replicas that a StatefulSet is expected to create after the
+// pending pod when it's scaling out. StatefulSets with the OrderedReady pod management policy only create a replica
+// once the previous replica is ready, so provisioning for each replica as it's created launches a series of small
+// nodes. Scheduling the expected replicas along with the pending pod sizes the NodeClaim for all of them instead. The
+// expected replicas are copies of the pending pod without its persistent volume claims, as the claims of the replicas
+// don't exist yet.
+func NewStatefulSetPods(statefulSet *appsv1.StatefulSet, pod *corev1.Pod) []*corev1.Pod {
+	if statefulSet.Spec.PodManagementPolicy == appsv1.ParallelPodManagement || statefulSet.Spec.Replicas == nil ||
+		statefulSet.Status.Replicas >= *statefulSet.Spec.Replicas {
+		return nil
+	}
+	ordinal, ok := StatefulSetOrdinal(statefulSet, pod)
+	if !ok {
+		return nil
+	}
+	start := int32(0)
+	if statefulSet.Spec.Ordinals != nil {
+		start = statefulSet.Spec.Ordinals.Start
+	}
+	var pods []*corev1.Pod
+	for i := ordinal + 1; i < start+*statefulSet.Spec.Replicas; i++ {
+		replica := pod.DeepCopy()
+		replica.ObjectMeta = metav1.ObjectMeta{
+			Name:        fmt.Sprintf("%s-%d", statefulSet.Name, i),
+			Namespace:   pod.Namespace,
+			UID:         types.UID(fmt.Sprintf("%s-%d", statefulSet.UID, i)),
+			Labels:      pod.Labels,
+			Annotations: lo.Assign(pod.Annotations, map[string]string{statefulSetReplicaAnnotationKey: statefulSet.Name}),
+		}
+		replica.Spec.NodeName = ""
+		replica.Spec.Volumes = lo.Reject(replica.Spec.Volumes, func(v corev1.Volume, _ int) bool {
+			return v.PersistentVolumeClaim != nil
+		})
+		for j := range replica.Spec.Containers {
+			replica.Spec.Containers[j].VolumeMounts = nil
+		}
+		replica.Status = corev1.PodStatus{
+			Phase: corev1.PodPending,
+			Conditions: []corev1.PodCondition{{
+				Type:   corev1.PodScheduled,
+				Status: corev1.ConditionFalse,
+				Reason: corev1.PodReasonUnschedulable,
+			}},
+		}
+		pods = append(pods, replica)
+	}
+	return pods
+}
@@ -36,6 +36,7 @@ import (
 	storagev1 "k8s.io/api/storage/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/record"
 	cloudproviderapi "k8s.io/cloud-provider/api"
@@ -70,6 +71,7 @@ var cloudProvider *fake.CloudProvider
 var nodeStateController *informer.NodeController
 var nodeClaimStateController *informer.NodeClaimController
 var podStateController *informer.PodController
+var namespaceStateController *informer.NamespaceController
 var podController *provisioning.PodController
 
 const csiProvider = "fake.csi.provider"
@@ -93,6 +95,7 @@ var _ = BeforeSuite(func() {
 	nodeStateController = informer.NewNodeController(env.Client, cluster)
 	nodeClaimStateController = informer.NewNodeClaimController(env.Client, cloudProvider, cluster)
 	podStateController = informer.NewPodController(env.Client, cluster)
+	namespaceStateController = informer.NewNamespaceController(env.Client, cluster)
 	prov = provisioning.NewProvisioner(env.Client, events.NewRecorder(&record.FakeRecorder{}), cloudProvider, cluster, fakeClock)
 	podController = provisioning.NewPodController(env.Client, prov, cluster)
 })
@@ -268,6 +271,30 @@ var _ = Context("Scheduling", func() {
 				node := ExpectScheduled(ctx, env.Client, pod)
 				Expect(node.Labels).To(HaveKeyWithValue(fake.IntegerInstanceLabelKey, "2"))
 			})
+			It("should schedule compatible requirements with Operator=Gt on the NodePool and Operator=Lt on the pod", func() {
+				nodePool.Spec.Template.Spec.Requirements = []v1.NodeSelectorRequirementWithMinValues{
+					{NodeSelectorRequirement: corev1.NodeSelectorRequirement{Key: fake.IntegerInstanceLabelKey, Operator: corev1.NodeSelectorOpGt, Values: []string{"2"}}}}
+				ExpectApplied(ctx, env.Client, nodePool)
+				pod := test.UnschedulablePod(
+					test.PodOptions{NodeRequirements: []corev1.NodeSelectorRequirement{
+						{Key: fake.IntegerInstanceLabelKey, Operator: corev1.NodeSelectorOpLt, Values: []string{"16"}},
+					}},
+				)
+				ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+				node := ExpectScheduled(ctx, env.Client, pod)
+				Expect(node.Labels).To(HaveKeyWithValue(fake.IntegerInstanceLabelKey, "4"))
+			})
+			It("should not schedule requirements with Operator=Gt and Operator=Lt that no instance type satisfies", func() {
+				ExpectApplied(ctx, env.Client, nodePool)
+				pod := test.UnschedulablePod(
+					test.PodOptions{NodeRequirements: []corev1.NodeSelectorRequirement{
+						{Key: fake.IntegerInstanceLabelKey, Operator: corev1.NodeSelectorOpGt, Values: []string{"4"}},
+						{Key: fake.IntegerInstanceLabelKey, Operator: corev1.NodeSelectorOpLt, Values: []string{"16"}},
+					}},
+				)
+				ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+				ExpectNotScheduled(ctx, env.Client, pod)
+			})
 			It("should not schedule incompatible preferences and requirements with Operator=In", func() {
 				ExpectApplied(ctx, env.Client, nodePool)
 				pod := test.UnschedulablePod(
@@ -1177,6 +1204,44 @@ var _ = Context("Scheduling", func() {
 				node := ExpectScheduled(ctx, env.Client, pod)
 				Expect(node.Labels).To(HaveKeyWithValue(corev1.LabelTopologyZone, "test-zone-2"))
 			})
+			It("should report the preferences that were relaxed", func() {
+				pod := test.UnschedulablePod(test.PodOptions{NodePreferences: []corev1.NodeSelectorRequirement{
+					{Key: corev1.LabelTopologyZone, Operator: corev1.NodeSelectorOpIn, Values: []string{"invalid"}},
+				}})
+				ExpectApplied(ctx, env.Client, nodePool)
+				s, err := prov.NewScheduler(ctx, []*corev1.Pod{pod}, nil)
+				Expect(err).ToNot(HaveOccurred())
+				results := s.Solve(ctx, []*corev1.Pod{pod})
+				Expect(results.PodErrors).To(BeEmpty())
+				Expect(results.RelaxedPreferences).To(HaveLen(1))
+				Expect(results.RelaxedPreferences[pod]).To(ConsistOf(HavePrefix("removing: spec.affinity.nodeAffinity.preferredDuringSchedulingIgnoredDuringExecution[0]=")))
+
+				Expect(results.NewNodeClaims).To(HaveLen(1))
+				annotations := results.NewNodeClaims[0].ToNodeClaim().Annotations
+				Expect(annotations).To(HaveKeyWithValue(v1.RelaxedPreferencesAnnotationKey, ContainSubstring(client.ObjectKeyFromObject(pod).String())))
+				Expect(annotations[v1.RelaxedPreferencesAnnotationKey]).To(ContainSubstring("preferredDuringSchedulingIgnoredDuringExecution"))
+
+				recorder := test.NewEventRecorder()
+				results.Record(ctx, recorder, cluster)
+				Expect(recorder.Calls("PreferencesRelaxed")).To(Equal(1))
+			})
+			It("should not report relaxed preferences for pods that scheduled with all of their preferences", func() {
+				pod := test.UnschedulablePod(test.PodOptions{NodePreferences: []corev1.NodeSelectorRequirement{
+					{Key: corev1.LabelTopologyZone, Operator: corev1.NodeSelectorOpIn, Values: []string{"test-zone-1"}},
+				}})
+				ExpectApplied(ctx, env.Client, nodePool)
+				s, err := prov.NewScheduler(ctx, []*corev1.Pod{pod}, nil)
+				Expect(err).ToNot(HaveOccurred())
+				results := s.Solve(ctx, []*corev1.Pod{pod})
+				Expect(results.PodErrors).To(BeEmpty())
+				Expect(results.RelaxedPreferences).To(BeEmpty())
+				Expect(results.NewNodeClaims).To(HaveLen(1))
+				Expect(results.NewNodeClaims[0].ToNodeClaim().Annotations).ToNot(HaveKey(v1.RelaxedPreferencesAnnotationKey))
+
+				recorder := test.NewEventRecorder()
+				results.Record(ctx, recorder, cluster)
+				Expect(recorder.Calls("PreferencesRelaxed")).To(Equal(0))
+			})
 			It("should schedule even if preference is conflicting with requirement", func() {
 				pod := test.UnschedulablePod()
 				pod.Spec.Affinity = &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{PreferredDuringSchedulingIgnoredDuringExecution: []corev1.PreferredSchedulingTerm{
@@ -1815,6 +1880,78 @@ var _ = Context("Scheduling", func() {
 		})
 	})
 
+	Describe("Host Network", func() {
+		hostNetworkPod := func(port int32) *corev1.Pod {
+			pod := test.UnschedulablePod()
+			pod.Spec.HostNetwork = true
+			pod.Spec.Containers[0].Ports = []corev1.ContainerPort{{ContainerPort: port}}
+			return pod
+		}
+		It("should not pack pods using the host network that listen on the same port onto the same node", func() {
+			ExpectApplied(ctx, env.Client, nodePool)
+			first, second := hostNetworkPod(8080), hostNetworkPod(8080)
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, first, second)
+			Expect(ExpectScheduled(ctx, env.Client, first).Name).ToNot(Equal(ExpectScheduled(ctx, env.Client, second).Name))
+		})
+		It("should pack pods using the host network that listen on different ports onto the same node", func() {
+			ExpectApplied(ctx, env.Client, nodePool)
+			first, second := hostNetworkPod(8080), hostNetworkPod(9090)
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, first, second)
+			Expect(ExpectScheduled(ctx, env.Client, first).Name).To(Equal(ExpectScheduled(ctx, env.Client, second).Name))
+		})
+		It("should not schedule a pod using the host network to an existing node where its port is in use", func() {
+			ExpectApplied(ctx, env.Client, nodePool)
+			first := hostNetworkPod(8080)
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, first)
+			node := ExpectScheduled(ctx, env.Client, first)
+
+			second := hostNetworkPod(8080)
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, second)
+			Expect(ExpectScheduled(ctx, env.Client, second).Name).ToNot(Equal(node.Name))
+		})
+	})
+	Describe("Limit Ranges", func() {
+		BeforeEach(func() {
+			ExpectApplied(ctx, env.Client, &corev1.LimitRange{
+				ObjectMeta: metav1.ObjectMeta{Name: "default-requests", Namespace: "default"},
+				Spec: corev1.LimitRangeSpec{Limits: []corev1.LimitRangeItem{{
+					Type:           corev1.LimitTypeContainer,
+					DefaultRequest: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("3")},
+				}}},
+			})
+		})
+		It("should apply the default requests of a LimitRange to pods that don't specify requests", func() {
+			cloudProvider.InstanceTypes = []*cloudprovider.InstanceType{
+				fake.NewInstanceType(fake.InstanceTypeOptions{Name: "small", Resources: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")}}),
+				fake.NewInstanceType(fake.InstanceTypeOptions{Name: "large", Resources: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")}}),
+			}
+			ExpectApplied(ctx, env.Client, nodePool)
+			first, second := test.UnschedulablePod(), test.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, first, second)
+			firstNode, secondNode := ExpectScheduled(ctx, env.Client, first), ExpectScheduled(ctx, env.Client, second)
+			Expect(firstNode.Name).ToNot(Equal(secondNode.Name))
+			Expect(firstNode.Labels[corev1.LabelInstanceTypeStable]).To(Equal("large"))
+			Expect(secondNode.Labels[corev1.LabelInstanceTypeStable]).To(Equal("large"))
+		})
+		It("should not apply the default requests of a LimitRange to pods that specify requests", func() {
+			ExpectApplied(ctx, env.Client, nodePool)
+			opts := test.PodOptions{ResourceRequirements: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("10m")},
+			}}
+			first, second := test.UnschedulablePod(opts), test.UnschedulablePod(opts)
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, first, second)
+			Expect(ExpectScheduled(ctx, env.Client, first).Name).To(Equal(ExpectScheduled(ctx, env.Client, second).Name))
+		})
+		It("should not apply the default requests of a LimitRange to pods in other namespaces", func() {
+			ExpectApplied(ctx, env.Client, nodePool)
+			ns := test.Namespace()
+			ExpectApplied(ctx, env.Client, ns)
+			first := test.UnschedulablePod(test.PodOptions{ObjectMeta: metav1.ObjectMeta{Namespace: ns.Name}})
+			second := test.UnschedulablePod(test.PodOptions{ObjectMeta: metav1.ObjectMeta{Namespace: ns.Name}})
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, first, second)
+			Expect(ExpectScheduled(ctx, env.Client, first).Name).To(Equal(ExpectScheduled(ctx, env.Client, second).Name))
+		})
+	})
 	Describe("In-Flight Nodes", func() {
 		It("should not launch a second node if there is an in-flight node that can support the pod", func() {
 			opts := test.PodOptions{ResourceRequirements: corev1.ResourceRequirements{
@@ -1901,6 +2038,69 @@ var _ = Context("Scheduling", func() {
 			node2 := ExpectScheduled(ctx, env.Client, secondPod)
 			Expect(node1.Name).ToNot(Equal(node2.Name))
 		})
+		Context("Existing Capacity Window", func() {
+			var opts test.PodOptions
+			var node1 *corev1.Node
+			BeforeEach(func() {
+				fakeClock.SetTime(time.Now())
+				nodePool.Spec.ExistingCapacityWindow = &metav1.Duration{Duration: 5 * time.Minute}
+				ExpectApplied(ctx, env.Client, nodePool)
+				opts = test.PodOptions{ResourceRequirements: corev1.ResourceRequirements{
+					Limits: map[corev1.ResourceName]resource.Quantity{
+						corev1.ResourceCPU: resource.MustParse("1001m"),
+					},
+				}}
+				initialPod := test.UnschedulablePod(opts)
+				ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, initialPod)
+				node1 = ExpectScheduled(ctx, env.Client, initialPod)
+				// the initializing node is left without any pods bound to it, so that all of its allocatable resources
+				// remain
+				ExpectDeleted(ctx, env.Client, initialPod)
+				ExpectReconcileSucceeded(ctx, nodeStateController, client.ObjectKeyFromObject(node1))
+				opts.ResourceRequirements.Limits[corev1.ResourceCPU] = resource.MustParse("1")
+			})
+			It("should not launch a second node for a pod that could fit on the initializing node", func() {
+				// the node will have 2000m CPU, so only one of the pods is expected to schedule to it, but the other would
+				// fit on the node on its own
+				pods := test.UnschedulablePods(opts, 2)
+				ExpectApplied(ctx, env.Client, pods[0], pods[1])
+				results, err := prov.Schedule(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(results.NewNodeClaims).To(BeEmpty())
+				Expect(results.PodErrors).To(BeEmpty())
+				Expect(results.WaitingPods).To(HaveLen(1))
+				Expect(lo.Values(results.WaitingPods)[0].Name()).To(Equal(node1.Name))
+			})
+			It("should reserve the requests of waiting pods against the initializing node's remaining allocatable resources", func() {
+				// one pod is expected to schedule to the node and a second waits for it, which leaves no room on the node
+				// for the third pod to wait
+				pods := test.UnschedulablePods(opts, 3)
+				ExpectApplied(ctx, env.Client, pods[0], pods[1], pods[2])
+				results, err := prov.Schedule(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(results.NewNodeClaims).To(HaveLen(1))
+				Expect(results.PodErrors).To(BeEmpty())
+				Expect(results.WaitingPods).To(HaveLen(1))
+			})
+			It("should launch a second node for a pod that can't fit on the initializing node", func() {
+				secondPod := test.UnschedulablePod(test.PodOptions{
+					ResourceRequirements: opts.ResourceRequirements,
+					NodeSelector:         map[string]string{corev1.LabelArchStable: "arm64"},
+				})
+				ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, secondPod)
+				node2 := ExpectScheduled(ctx, env.Client, secondPod)
+				Expect(node1.Name).ToNot(Equal(node2.Name))
+			})
+			It("should launch a second node once the window has passed", func() {
+				fakeClock.Step(10 * time.Minute)
+				pods := test.UnschedulablePods(opts, 2)
+				ExpectApplied(ctx, env.Client, pods[0], pods[1])
+				results, err := prov.Schedule(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(results.NewNodeClaims).To(HaveLen(1))
+				Expect(results.WaitingPods).To(BeEmpty())
+			})
+		})
 		It("should launch a second node if a pod isn't compatible with the existingNodes node (node selector)", func() {
 			ExpectApplied(ctx, env.Client, nodePool)
 			opts := test.PodOptions{ResourceRequirements: corev1.ResourceRequirements{
@@ -2091,6 +2291,42 @@ var _ = Context("Scheduling", func() {
 				node2 := ExpectScheduled(ctx, env.Client, secondPod)
 				Expect(node1.Name).To(Equal(node2.Name))
 			})
+			Context("Strict Startup Taints", func() {
+				BeforeEach(func() {
+					ctx = options.ToContext(ctx, test.Options(test.OptionsFields{StrictStartupTaints: lo.ToPtr(true)}))
+				})
+				AfterEach(func() {
+					ctx = options.ToContext(ctx, test.Options())
+				})
+				It("should not assume pod will schedule to a node with a custom startup taint", func() {
+					nodePool.Spec.Template.Spec.StartupTaints = []corev1.Taint{{Key: "foo.com/taint", Value: "tainted", Effect: corev1.TaintEffectNoSchedule}}
+					ExpectApplied(ctx, env.Client, nodePool)
+					initialPod := test.UnschedulablePod()
+					ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, initialPod)
+					node1 := ExpectScheduled(ctx, env.Client, initialPod)
+					ExpectReconcileSucceeded(ctx, nodeStateController, client.ObjectKeyFromObject(node1))
+
+					secondPod := test.UnschedulablePod()
+					ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, secondPod)
+					node2 := ExpectScheduled(ctx, env.Client, secondPod)
+					Expect(node1.Name).ToNot(Equal(node2.Name))
+				})
+				It("should assume pod will schedule to a node with a custom startup taint that it tolerates", func() {
+					startupTaint := corev1.Taint{Key: "foo.com/taint", Value: "tainted", Effect: corev1.TaintEffectNoSchedule}
+					nodePool.Spec.Template.Spec.StartupTaints = []corev1.Taint{startupTaint}
+					ExpectApplied(ctx, env.Client, nodePool)
+					opts := test.PodOptions{Tolerations: []corev1.Toleration{{Key: startupTaint.Key, Operator: corev1.TolerationOpExists}}}
+					initialPod := test.UnschedulablePod(opts)
+					ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, initialPod)
+					node1 := ExpectScheduled(ctx, env.Client, initialPod)
+					ExpectReconcileSucceeded(ctx, nodeStateController, client.ObjectKeyFromObject(node1))
+
+					secondPod := test.UnschedulablePod(opts)
+					ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, secondPod)
+					node2 := ExpectScheduled(ctx, env.Client, secondPod)
+					Expect(node1.Name).To(Equal(node2.Name))
+				})
+			})
 			It("should not assume pod will schedule to a node with startup taints after initialization", func() {
 				startupTaint := corev1.Taint{Key: "ignore-me", Value: "nothing-to-see-here", Effect: corev1.TaintEffectNoSchedule}
 				nodePool.Spec.Template.Spec.StartupTaints = []corev1.Taint{startupTaint}
@@ -2480,6 +2716,85 @@ var _ = Context("Scheduling", func() {
 				Expect(node.Name).To(Equal(scheduledNode.Name))
 			}
 		})
+		It("should schedule a pod to an existing node with a PreferNoSchedule taint rather than launching a node", func() {
+			node := test.Node(test.NodeOptions{
+				Taints: []corev1.Taint{{Key: "foo", Value: "bar", Effect: corev1.TaintEffectPreferNoSchedule}},
+				Allocatable: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("10"),
+					corev1.ResourceMemory: resource.MustParse("10Gi"),
+					corev1.ResourcePods:   resource.MustParse("110"),
+				},
+			})
+			ExpectApplied(ctx, env.Client, node)
+			ExpectMakeNodesInitialized(ctx, env.Client, node)
+			ExpectReconcileSucceeded(ctx, nodeStateController, client.ObjectKeyFromObject(node))
+
+			ExpectApplied(ctx, env.Client, nodePool)
+			pod := test.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			scheduledNode := ExpectScheduled(ctx, env.Client, pod)
+			Expect(scheduledNode.Name).To(Equal(node.Name))
+			Expect(cloudProvider.CreateCalls).To(HaveLen(0))
+		})
+		It("should prefer existing nodes without PreferNoSchedule taints", func() {
+			// existing nodes are ordered by name, so the tainted node would be tried first if the taint wasn't scored
+			tainted := test.Node(test.NodeOptions{
+				ObjectMeta: metav1.ObjectMeta{Name: "a-tainted"},
+				Taints:     []corev1.Taint{{Key: "foo", Value: "bar", Effect: corev1.TaintEffectPreferNoSchedule}},
+				Allocatable: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("10"),
+					corev1.ResourceMemory: resource.MustParse("10Gi"),
+					corev1.ResourcePods:   resource.MustParse("110"),
+				},
+			})
+			untainted := test.Node(test.NodeOptions{
+				ObjectMeta: metav1.ObjectMeta{Name: "b-untainted"},
+				Allocatable: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("10"),
+					corev1.ResourceMemory: resource.MustParse("10Gi"),
+					corev1.ResourcePods:   resource.MustParse("110"),
+				},
+			})
+			ExpectApplied(ctx, env.Client, tainted, untainted)
+			ExpectMakeNodesInitialized(ctx, env.Client, tainted, untainted)
+			ExpectReconcileSucceeded(ctx, nodeStateController, client.ObjectKeyFromObject(tainted))
+			ExpectReconcileSucceeded(ctx, nodeStateController, client.ObjectKeyFromObject(untainted))
+
+			ExpectApplied(ctx, env.Client, nodePool)
+			pod := test.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			Expect(ExpectScheduled(ctx, env.Client, pod).Name).To(Equal(untainted.Name))
+		})
+		It("should not deprioritize existing nodes with PreferNoSchedule taints that the pod tolerates", func() {
+			tainted := test.Node(test.NodeOptions{
+				ObjectMeta: metav1.ObjectMeta{Name: "a-tainted"},
+				Taints:     []corev1.Taint{{Key: "foo", Value: "bar", Effect: corev1.TaintEffectPreferNoSchedule}},
+				Allocatable: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("10"),
+					corev1.ResourceMemory: resource.MustParse("10Gi"),
+					corev1.ResourcePods:   resource.MustParse("110"),
+				},
+			})
+			untainted := test.Node(test.NodeOptions{
+				ObjectMeta: metav1.ObjectMeta{Name: "b-untainted"},
+				Allocatable: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("10"),
+					corev1.ResourceMemory: resource.MustParse("10Gi"),
+					corev1.ResourcePods:   resource.MustParse("110"),
+				},
+			})
+			ExpectApplied(ctx, env.Client, tainted, untainted)
+			ExpectMakeNodesInitialized(ctx, env.Client, tainted, untainted)
+			ExpectReconcileSucceeded(ctx, nodeStateController, client.ObjectKeyFromObject(tainted))
+			ExpectReconcileSucceeded(ctx, nodeStateController, client.ObjectKeyFromObject(untainted))
+
+			ExpectApplied(ctx, env.Client, nodePool)
+			pod := test.UnschedulablePod(test.PodOptions{
+				Tolerations: []corev1.Toleration{{Key: "foo", Operator: corev1.TolerationOpEqual, Value: "bar", Effect: corev1.TaintEffectPreferNoSchedule}},
+			})
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			Expect(ExpectScheduled(ctx, env.Client, pod).Name).To(Equal(tainted.Name))
+		})
 		It("should order initialized nodes for scheduling uninitialized nodes", func() {
 			ExpectApplied(ctx, env.Client, nodePool)
 
@@ -2605,6 +2920,244 @@ var _ = Context("Scheduling", func() {
 		})
 	})
 
+	Describe("Preemption", func() {
+		var node *corev1.Node
+		var victim *corev1.Pod
+		BeforeEach(func() {
+			ctx = options.ToContext(ctx, test.Options(test.OptionsFields{FeatureGates: test.FeatureGates{PreemptionAwareProvisioning: lo.ToPtr(true)}}))
+			node = test.Node(test.NodeOptions{
+				Allocatable: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("2"),
+					corev1.ResourceMemory: resource.MustParse("2Gi"),
+					corev1.ResourcePods:   resource.MustParse("110"),
+				},
+			})
+			ExpectApplied(ctx, env.Client, node, nodePool)
+			ExpectMakeNodesInitialized(ctx, env.Client, node)
+			ExpectReconcileSucceeded(ctx, nodeStateController, client.ObjectKeyFromObject(node))
+
+			victim = test.Pod(test.PodOptions{ResourceRequirements: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1500m")},
+			}})
+			victim.Spec.Priority = lo.ToPtr[int32](0)
+			ExpectApplied(ctx, env.Client, victim)
+			ExpectManualBinding(ctx, env.Client, victim, node)
+			ExpectReconcileSucceeded(ctx, podStateController, client.ObjectKeyFromObject(victim))
+		})
+		AfterEach(func() {
+			ctx = options.ToContext(ctx, test.Options())
+		})
+		It("should not launch capacity for a pod that can preempt lower priority pods on an existing node", func() {
+			pod := test.UnschedulablePod(test.PodOptions{ResourceRequirements: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+			}})
+			pod.Spec.Priority = lo.ToPtr[int32](1000)
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			Expect(ExpectScheduled(ctx, env.Client, pod).Name).To(Equal(node.Name))
+			Expect(cloudProvider.CreateCalls).To(HaveLen(0))
+		})
+		It("should launch capacity for a pod that has a lower priority than the pods on the node", func() {
+			pod := test.UnschedulablePod(test.PodOptions{ResourceRequirements: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+			}})
+			pod.Spec.Priority = lo.ToPtr[int32](-10)
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			Expect(ExpectScheduled(ctx, env.Client, pod).Name).ToNot(Equal(node.Name))
+			Expect(cloudProvider.CreateCalls).To(HaveLen(1))
+		})
+		It("should launch capacity for a pod with a preemption policy of Never", func() {
+			pod := test.UnschedulablePod(test.PodOptions{ResourceRequirements: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+			}})
+			pod.Spec.Priority = lo.ToPtr[int32](1000)
+			pod.Spec.PreemptionPolicy = lo.ToPtr(corev1.PreemptNever)
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			Expect(ExpectScheduled(ctx, env.Client, pod).Name).ToNot(Equal(node.Name))
+			Expect(cloudProvider.CreateCalls).To(HaveLen(1))
+		})
+		It("should only count a victim once across multiple preemptors", func() {
+			pods := lo.Times(2, func(_ int) *corev1.Pod {
+				p := test.UnschedulablePod(test.PodOptions{ResourceRequirements: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+				}})
+				p.Spec.Priority = lo.ToPtr[int32](1000)
+				return p
+			})
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pods...)
+			nodeNames := lo.Map(pods, func(p *corev1.Pod, _ int) string { return ExpectScheduled(ctx, env.Client, p).Name })
+			Expect(lo.Count(nodeNames, node.Name)).To(Equal(1))
+			Expect(cloudProvider.CreateCalls).To(HaveLen(1))
+		})
+		It("should launch capacity when the feature gate is disabled", func() {
+			ctx = options.ToContext(ctx, test.Options())
+			pod := test.UnschedulablePod(test.PodOptions{ResourceRequirements: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+			}})
+			pod.Spec.Priority = lo.ToPtr[int32](1000)
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			Expect(ExpectScheduled(ctx, env.Client, pod).Name).ToNot(Equal(node.Name))
+			Expect(cloudProvider.CreateCalls).To(HaveLen(1))
+		})
+	})
+
+	Describe("Packing Order", func() {
+		var small, large, highPriority *corev1.Pod
+		var podData map[types.UID]*scheduling.PodData
+		BeforeEach(func() {
+			small = test.UnschedulablePod(test.PodOptions{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(time.Unix(0, 0))}})
+			small.Spec.Priority = lo.ToPtr[int32](0)
+			large = test.UnschedulablePod(test.PodOptions{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(time.Unix(10, 0))}})
+			large.Spec.Priority = lo.ToPtr[int32](0)
+			highPriority = test.UnschedulablePod(test.PodOptions{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(time.Unix(20, 0))}})
+			highPriority.Spec.Priority = lo.ToPtr[int32](1000)
+			podData = map[types.UID]*scheduling.PodData{
+				small.UID:        {Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")}},
+				large.UID:        {Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")}},
+				highPriority.UID: {Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")}},
+			}
+		})
+		It("should pack the largest pods first by default", func() {
+			q := scheduling.NewQueue([]*corev1.Pod{small, highPriority, large}, podData, "")
+			Expect(q.List()).To(Equal([]*corev1.Pod{large, highPriority, small}))
+		})
+		It("should pack the highest priority pods first", func() {
+			q := scheduling.NewQueue([]*corev1.Pod{small, large, highPriority}, podData, scheduling.PackingOrderPriorityFirst)
+			Expect(q.List()).To(Equal([]*corev1.Pod{highPriority, large, small}))
+		})
+		It("should pack the oldest pods first", func() {
+			q := scheduling.NewQueue([]*corev1.Pod{highPriority, large, small}, podData, scheduling.PackingOrderFIFO)
+			Expect(q.List()).To(Equal([]*corev1.Pod{small, large, highPriority}))
+		})
+	})
+
+	Describe("Pod Groups", func() {
+		var owner metav1.ObjectMeta
+		BeforeEach(func() {
+			owner = metav1.ObjectMeta{OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "apps/v1",
+				Kind:       "ReplicaSet",
+				Name:       "replicaset",
+				UID:        "replicaset-uid",
+				Controller: lo.ToPtr(true),
+			}}, Labels: map[string]string{"app": "test"}}
+		})
+		It("should group identical pods of the same owner", func() {
+			pods := test.UnschedulablePods(test.PodOptions{ObjectMeta: owner, ResourceRequirements: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+			}}, 2)
+			lhs, ok := scheduling.PodGroupKey(pods[0])
+			Expect(ok).To(BeTrue())
+			rhs, ok := scheduling.PodGroupKey(pods[1])
+			Expect(ok).To(BeTrue())
+			Expect(lhs).To(Equal(rhs))
+		})
+		It("should not group pods with different requests", func() {
+			lhs, _ := scheduling.PodGroupKey(test.UnschedulablePod(test.PodOptions{ObjectMeta: owner, ResourceRequirements: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+			}}))
+			rhs, _ := scheduling.PodGroupKey(test.UnschedulablePod(test.PodOptions{ObjectMeta: owner, ResourceRequirements: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+			}}))
+			Expect(lhs).ToNot(Equal(rhs))
+		})
+		It("should not group pods without an owner", func() {
+			_, ok := scheduling.PodGroupKey(test.UnschedulablePod())
+			Expect(ok).To(BeFalse())
+		})
+		It("should not group pods with topology spread constraints", func() {
+			_, ok := scheduling.PodGroupKey(test.UnschedulablePod(test.PodOptions{ObjectMeta: owner, TopologySpreadConstraints: []corev1.TopologySpreadConstraint{{
+				TopologyKey:       corev1.LabelTopologyZone,
+				WhenUnsatisfiable: corev1.DoNotSchedule,
+				LabelSelector:     &metav1.LabelSelector{MatchLabels: owner.Labels},
+				MaxSkew:           1,
+			}}}))
+			Expect(ok).To(BeFalse())
+		})
+		It("should not group pods with persistent volume claims", func() {
+			_, ok := scheduling.PodGroupKey(test.UnschedulablePod(test.PodOptions{ObjectMeta: owner, PersistentVolumeClaims: []string{"claim"}}))
+			Expect(ok).To(BeFalse())
+		})
+		It("should binpack the pods of a group", func() {
+			ExpectApplied(ctx, env.Client, nodePool)
+			pods := test.UnschedulablePods(test.PodOptions{ObjectMeta: owner, ResourceRequirements: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+			}}, 10)
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pods...)
+			nodeNames := sets.New[string]()
+			for _, pod := range pods {
+				nodeNames.Insert(ExpectScheduled(ctx, env.Client, pod).Name)
+			}
+			Expect(nodeNames).To(HaveLen(1))
+		})
+		It("should fail every pod of a group that can't schedule", func() {
+			ExpectApplied(ctx, env.Client, nodePool)
+			pods := test.UnschedulablePods(test.PodOptions{ObjectMeta: owner, NodeSelector: map[string]string{corev1.LabelTopologyZone: "unknown"}}, 5)
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pods...)
+			for _, pod := range pods {
+				ExpectNotScheduled(ctx, env.Client, pod)
+			}
+		})
+		It("should not group pods once they're relaxed", func() {
+			ExpectApplied(ctx, env.Client, nodePool)
+			pods := test.UnschedulablePods(test.PodOptions{ObjectMeta: owner, NodePreferences: []corev1.NodeSelectorRequirement{
+				{Key: corev1.LabelTopologyZone, Operator: corev1.NodeSelectorOpIn, Values: []string{"unknown"}},
+			}}, 5)
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pods...)
+			for _, pod := range pods {
+				ExpectScheduled(ctx, env.Client, pod)
+			}
+		})
+	})
+
+	Describe("Resource Aliases", func() {
+		const resourceMIGVendorA corev1.ResourceName = "fake.com/vendor-a-mig"
+		BeforeEach(func() {
+			ctx = options.ToContext(ctx, test.Options(test.OptionsFields{ResourceAliases: lo.ToPtr(fmt.Sprintf("%s=%s/7", resourceMIGVendorA, fake.ResourceGPUVendorA))}))
+		})
+		AfterEach(func() {
+			ctx = options.ToContext(ctx, test.Options())
+		})
+		It("should binpack pods requesting aliased resources and their target onto the same node", func() {
+			ExpectApplied(ctx, env.Client, nodePool)
+			pods := append(test.UnschedulablePods(test.PodOptions{ResourceRequirements: corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{resourceMIGVendorA: resource.MustParse("1")},
+			}}, 7), test.UnschedulablePod(test.PodOptions{ResourceRequirements: corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{fake.ResourceGPUVendorA: resource.MustParse("1")},
+			}}))
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pods...)
+			nodeNames := sets.New[string]()
+			for _, pod := range pods {
+				node := ExpectScheduled(ctx, env.Client, pod)
+				Expect(node.Labels).To(HaveKeyWithValue(corev1.LabelInstanceTypeStable, "gpu-vendor-instance-type"))
+				nodeNames.Insert(node.Name)
+			}
+			// seven partitions make up one of the two gpus of the instance type
+			Expect(nodeNames).To(HaveLen(1))
+		})
+		It("should launch another node once the aliased resources exceed the target", func() {
+			ExpectApplied(ctx, env.Client, nodePool)
+			pods := append(test.UnschedulablePods(test.PodOptions{ResourceRequirements: corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{resourceMIGVendorA: resource.MustParse("1")},
+			}}, 8), test.UnschedulablePods(test.PodOptions{ResourceRequirements: corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{fake.ResourceGPUVendorA: resource.MustParse("1")},
+			}}, 1)...)
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pods...)
+			nodeNames := sets.New[string]()
+			for _, pod := range pods {
+				nodeNames.Insert(ExpectScheduled(ctx, env.Client, pod).Name)
+			}
+			Expect(nodeNames).To(HaveLen(2))
+		})
+		It("should not schedule pods requesting aliased resources without the alias", func() {
+			ctx = options.ToContext(ctx, test.Options())
+			ExpectApplied(ctx, env.Client, nodePool)
+			pod := test.UnschedulablePod(test.PodOptions{ResourceRequirements: corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{resourceMIGVendorA: resource.MustParse("1")},
+			}})
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectNotScheduled(ctx, env.Client, pod)
+		})
+	})
 	Describe("No Pre-Binding", func() {
 		It("should not bind pods to nodes", func() {
 			opts := test.PodOptions{ResourceRequirements: corev1.ResourceRequirements{
@@ -2866,6 +3419,66 @@ var _ = Context("Scheduling", func() {
 			// 5 of the same PVC should all be schedulable on the same node
 			Expect(nodeList.Items).To(HaveLen(1))
 		})
+		It("should launch multiple nodes if required due to instance type volume limits", func() {
+			cloudProvider.InstanceTypes[0].VolumeLimits = map[string]int{csiProvider: 4}
+			sc := test.StorageClass(test.StorageClassOptions{
+				ObjectMeta:  metav1.ObjectMeta{Name: "my-storage-class"},
+				Provisioner: lo.ToPtr(csiProvider),
+				Zones:       []string{"test-zone-1"}})
+			ExpectApplied(ctx, env.Client, nodePool, sc)
+
+			var pods []*corev1.Pod
+			for i := 0; i < 3; i++ {
+				pvcA := test.PersistentVolumeClaim(test.PersistentVolumeClaimOptions{
+					StorageClassName: lo.ToPtr("my-storage-class"),
+					ObjectMeta:       metav1.ObjectMeta{Name: fmt.Sprintf("my-claim-a-%d", i)},
+				})
+				pvcB := test.PersistentVolumeClaim(test.PersistentVolumeClaimOptions{
+					StorageClassName: lo.ToPtr("my-storage-class"),
+					ObjectMeta:       metav1.ObjectMeta{Name: fmt.Sprintf("my-claim-b-%d", i)},
+				})
+				ExpectApplied(ctx, env.Client, pvcA, pvcB)
+				pods = append(pods, test.UnschedulablePod(test.PodOptions{
+					PersistentVolumeClaims: []string{pvcA.Name, pvcB.Name},
+				}))
+			}
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pods...)
+			var nodeList corev1.NodeList
+			Expect(env.Client.List(ctx, &nodeList)).To(Succeed())
+			// each node can only attach the volumes of 2 pods due to the instance type volume limit
+			Expect(nodeList.Items).To(HaveLen(2))
+		})
+		It("should choose an instance type that can attach all of the volumes", func() {
+			cloudProvider.InstanceTypes[0].VolumeLimits = map[string]int{csiProvider: 1}
+			cloudProvider.InstanceTypes = append(cloudProvider.InstanceTypes, fake.NewInstanceType(
+				fake.InstanceTypeOptions{
+					Name: "large-volume-instance-type",
+					Resources: map[corev1.ResourceName]resource.Quantity{
+						corev1.ResourceCPU:  resource.MustParse("2048"),
+						corev1.ResourcePods: resource.MustParse("1024"),
+					},
+					VolumeLimits: map[string]int{csiProvider: 10},
+				}))
+			sc := test.StorageClass(test.StorageClassOptions{
+				ObjectMeta:  metav1.ObjectMeta{Name: "my-storage-class"},
+				Provisioner: lo.ToPtr(csiProvider),
+				Zones:       []string{"test-zone-1"}})
+			pvcA := test.PersistentVolumeClaim(test.PersistentVolumeClaimOptions{
+				StorageClassName: lo.ToPtr("my-storage-class"),
+				ObjectMeta:       metav1.ObjectMeta{Name: "my-claim-a"},
+			})
+			pvcB := test.PersistentVolumeClaim(test.PersistentVolumeClaimOptions{
+				StorageClassName: lo.ToPtr("my-storage-class"),
+				ObjectMeta:       metav1.ObjectMeta{Name: "my-claim-b"},
+			})
+			ExpectApplied(ctx, env.Client, nodePool, sc, pvcA, pvcB)
+			pod := test.UnschedulablePod(test.PodOptions{
+				PersistentVolumeClaims: []string{pvcA.Name, pvcB.Name},
+			})
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			node := ExpectScheduled(ctx, env.Client, pod)
+			Expect(node.Labels[corev1.LabelInstanceTypeStable]).To(Equal("large-volume-instance-type"))
+		})
 		It("should launch nodes for pods with ephemeral volume using the specified storage class name", func() {
 			// Launch an initial pod onto a node and register the CSI Node with a volume count limit of 1
 			sc := test.StorageClass(test.StorageClassOptions{
@@ -3439,6 +4052,412 @@ var _ = Context("Scheduling", func() {
 		})
 	})
 
+	Describe("Multiple NodePools", func() {
+		It("should consistently schedule to the highest weight compatible NodePool", func() {
+			var nodePools []*v1.NodePool
+			for i := range 20 {
+				np := test.NodePool(v1.NodePool{Spec: v1.NodePoolSpec{Weight: lo.ToPtr(int32(i + 1))}})
+				// only the even NodePools can launch arm capacity
+				if i%2 == 0 {
+					np.Spec.Template.Spec.Requirements = []v1.NodeSelectorRequirementWithMinValues{{NodeSelectorRequirement: corev1.NodeSelectorRequirement{
+						Key: corev1.LabelArchStable, Operator: corev1.NodeSelectorOpIn, Values: []string{v1.ArchitectureArm64},
+					}}}
+				}
+				nodePools = append(nodePools, np)
+			}
+			ExpectApplied(ctx, env.Client, lo.Map(nodePools, func(np *v1.NodePool, _ int) client.Object { return np })...)
+			pods := lo.Times(10, func(_ int) *corev1.Pod {
+				return test.UnschedulablePod(test.PodOptions{NodeSelector: map[string]string{corev1.LabelArchStable: v1.ArchitectureArm64}})
+			})
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pods...)
+			for _, pod := range pods {
+				node := ExpectScheduled(ctx, env.Client, pod)
+				Expect(node.Labels[v1.NodePoolLabelKey]).To(Equal(nodePools[18].Name))
+			}
+		})
+		It("should fall back to lower weight NodePools when higher weight NodePools are at their limits", func() {
+			limited := test.NodePool(v1.NodePool{Spec: v1.NodePoolSpec{
+				Weight: lo.ToPtr(int32(100)),
+				Limits: v1.Limits(corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("0")}),
+			}})
+			fallback := test.NodePool()
+			ExpectApplied(ctx, env.Client, limited, fallback)
+			pod := test.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			node := ExpectScheduled(ctx, env.Client, pod)
+			Expect(node.Labels[v1.NodePoolLabelKey]).To(Equal(fallback.Name))
+		})
+		Context("Strict Weights", func() {
+			var amd, arm *v1.NodePool
+			var armPod, pod *corev1.Pod
+			BeforeEach(func() {
+				amd = test.NodePool(v1.NodePool{Spec: v1.NodePoolSpec{
+					Weight: lo.ToPtr(int32(100)),
+					Template: v1.NodeClaimTemplate{Spec: v1.NodeClaimTemplateSpec{
+						Requirements: []v1.NodeSelectorRequirementWithMinValues{{NodeSelectorRequirement: corev1.NodeSelectorRequirement{
+							Key: corev1.LabelArchStable, Operator: corev1.NodeSelectorOpIn, Values: []string{v1.ArchitectureAmd64},
+						}}},
+					}},
+				}})
+				arm = test.NodePool()
+				ExpectApplied(ctx, env.Client, amd, arm)
+				// the arm pod is larger, so it's packed first and can only launch capacity for the lower weight NodePool
+				armPod = test.UnschedulablePod(test.PodOptions{
+					NodeSelector:         map[string]string{corev1.LabelArchStable: v1.ArchitectureArm64},
+					ResourceRequirements: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")}},
+				})
+				pod = test.UnschedulablePod(test.PodOptions{
+					ResourceRequirements: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")}},
+				})
+			})
+			AfterEach(func() {
+				ctx = options.ToContext(ctx, test.Options())
+			})
+			It("should pack pods onto capacity for lower weight NodePools by default", func() {
+				ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, armPod, pod)
+				Expect(ExpectScheduled(ctx, env.Client, pod).Labels[v1.NodePoolLabelKey]).To(Equal(arm.Name))
+				Expect(ExpectScheduled(ctx, env.Client, armPod).Labels[v1.NodePoolLabelKey]).To(Equal(arm.Name))
+			})
+			It("should only schedule pods to lower weight NodePools when higher weight NodePools can't satisfy them", func() {
+				ctx = options.ToContext(ctx, test.Options(test.OptionsFields{StrictNodePoolWeights: lo.ToPtr(true)}))
+				ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, armPod, pod)
+				Expect(ExpectScheduled(ctx, env.Client, pod).Labels[v1.NodePoolLabelKey]).To(Equal(amd.Name))
+				Expect(ExpectScheduled(ctx, env.Client, armPod).Labels[v1.NodePoolLabelKey]).To(Equal(arm.Name))
+			})
+			It("should record why pods fell back to lower weight NodePools", func() {
+				ctx = options.ToContext(ctx, test.Options(test.OptionsFields{StrictNodePoolWeights: lo.ToPtr(true)}))
+				s, err := prov.NewScheduler(ctx, []*corev1.Pod{armPod, pod}, nil)
+				Expect(err).ToNot(HaveOccurred())
+				results := s.Solve(ctx, []*corev1.Pod{armPod, pod})
+				Expect(results.PodErrors).To(BeEmpty())
+
+				recorder := test.NewEventRecorder()
+				results.Record(ctx, recorder, cluster)
+				Expect(recorder.DetectedEvent(fmt.Sprintf(`Falling back to nodepool %q, higher weight nodepools can't satisfy the pod: nodepool %q: requirement kubernetes.io/arch`,
+					arm.Name, amd.Name))).To(BeTrue())
+				Expect(recorder.Calls("NodePoolFallback")).To(Equal(1))
+			})
+		})
+	})
+
+	Describe("Unavailable Offerings", func() {
+		offering := func(zone string, available bool) cloudprovider.Offering {
+			return cloudprovider.Offering{
+				Requirements: pscheduling.NewLabelRequirements(map[string]string{
+					v1.CapacityTypeLabelKey:  v1.CapacityTypeOnDemand,
+					corev1.LabelTopologyZone: zone,
+				}),
+				Price:     1.00,
+				Available: available,
+			}
+		}
+		BeforeEach(func() {
+			cloudProvider.InstanceTypes = []*cloudprovider.InstanceType{
+				fake.NewInstanceType(fake.InstanceTypeOptions{
+					Name: "small",
+					Resources: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("1"),
+						corev1.ResourceMemory: resource.MustParse("1Gi"),
+					},
+					Offerings: []cloudprovider.Offering{offering("test-zone-1", true), offering("test-zone-2", true), offering("test-zone-3", true)},
+				}),
+				// the large instance type has no capacity available in test-zone-1
+				fake.NewInstanceType(fake.InstanceTypeOptions{
+					Name: "large",
+					Resources: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("4"),
+						corev1.ResourceMemory: resource.MustParse("4Gi"),
+					},
+					Offerings: []cloudprovider.Offering{offering("test-zone-1", false), offering("test-zone-2", true), offering("test-zone-3", true)},
+				}),
+			}
+		})
+		It("should fall back to other zones when the zone selected by topology has no available offerings", func() {
+			labels := map[string]string{"foo": "bar"}
+			pods := test.UnschedulablePods(test.PodOptions{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				ResourceRequirements: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("2"),
+				}},
+				TopologySpreadConstraints: []corev1.TopologySpreadConstraint{{
+					TopologyKey:       corev1.LabelTopologyZone,
+					WhenUnsatisfiable: corev1.DoNotSchedule,
+					LabelSelector:     &metav1.LabelSelector{MatchLabels: labels},
+					MaxSkew:           1,
+				}},
+			}, 2)
+			ExpectApplied(ctx, env.Client, nodePool)
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pods...)
+			zones := sets.New[string]()
+			for _, pod := range pods {
+				node := ExpectScheduled(ctx, env.Client, pod)
+				Expect(node.Labels[corev1.LabelInstanceTypeStable]).To(Equal("large"))
+				zones.Insert(node.Labels[corev1.LabelTopologyZone])
+			}
+			Expect(zones.UnsortedList()).To(ConsistOf("test-zone-2", "test-zone-3"))
+		})
+		It("should not fall back to other zones when the pod requires a zone with no available offerings", func() {
+			pod := test.UnschedulablePod(test.PodOptions{
+				NodeSelector: map[string]string{corev1.LabelTopologyZone: "test-zone-1"},
+				ResourceRequirements: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("2"),
+				}},
+			})
+			ExpectApplied(ctx, env.Client, nodePool)
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectNotScheduled(ctx, env.Client, pod)
+		})
+	})
+
+	Describe("Capacity Type Split", func() {
+		It("should launch nodeclaims across capacity types in the configured proportion", func() {
+			nodePool.Spec.CapacityTypeSplit = map[string]int32{v1.CapacityTypeSpot: 75, v1.CapacityTypeOnDemand: 25}
+			ExpectApplied(ctx, env.Client, nodePool)
+			// the pods use the same host port, so each of them needs its own node
+			pods := lo.Times(4, func(_ int) *corev1.Pod { return test.UnschedulablePod(test.PodOptions{HostPorts: []int32{80}}) })
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pods...)
+			capacityTypes := lo.Map(pods, func(p *corev1.Pod, _ int) string {
+				return ExpectScheduled(ctx, env.Client, p).Labels[v1.CapacityTypeLabelKey]
+			})
+			Expect(lo.Count(capacityTypes, v1.CapacityTypeSpot)).To(Equal(3))
+			Expect(lo.Count(capacityTypes, v1.CapacityTypeOnDemand)).To(Equal(1))
+		})
+		It("should launch the capacity type that a pod requires", func() {
+			nodePool.Spec.CapacityTypeSplit = map[string]int32{v1.CapacityTypeSpot: 50, v1.CapacityTypeOnDemand: 50}
+			ExpectApplied(ctx, env.Client, nodePool)
+			pods := lo.Times(2, func(_ int) *corev1.Pod {
+				return test.UnschedulablePod(test.PodOptions{
+					HostPorts:    []int32{80},
+					NodeSelector: map[string]string{v1.CapacityTypeLabelKey: v1.CapacityTypeOnDemand},
+				})
+			})
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pods...)
+			for _, pod := range pods {
+				Expect(ExpectScheduled(ctx, env.Client, pod).Labels[v1.CapacityTypeLabelKey]).To(Equal(v1.CapacityTypeOnDemand))
+			}
+		})
+		It("should not launch capacity types that aren't in the split", func() {
+			nodePool.Spec.CapacityTypeSplit = map[string]int32{v1.CapacityTypeSpot: 100}
+			ExpectApplied(ctx, env.Client, nodePool)
+			pod := test.UnschedulablePod(test.PodOptions{NodeSelector: map[string]string{v1.CapacityTypeLabelKey: v1.CapacityTypeOnDemand}})
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectNotScheduled(ctx, env.Client, pod)
+		})
+		It("should account for the capacity types of existing nodes", func() {
+			nodePool.Spec.CapacityTypeSplit = map[string]int32{v1.CapacityTypeSpot: 50, v1.CapacityTypeOnDemand: 50}
+			ExpectApplied(ctx, env.Client, nodePool)
+			first := test.UnschedulablePod(test.PodOptions{HostPorts: []int32{80}})
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, first)
+			firstCapacityType := ExpectScheduled(ctx, env.Client, first).Labels[v1.CapacityTypeLabelKey]
+
+			second := test.UnschedulablePod(test.PodOptions{HostPorts: []int32{80}})
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, second)
+			Expect(ExpectScheduled(ctx, env.Client, second).Labels[v1.CapacityTypeLabelKey]).ToNot(Equal(firstCapacityType))
+		})
+	})
+	Describe("Pending Pod SLA", func() {
+		BeforeEach(func() {
+			ctx = options.ToContext(ctx, test.Options(test.OptionsFields{PendingPodSLA: lo.ToPtr(5 * time.Minute)}))
+		})
+		AfterEach(func() {
+			ctx = options.ToContext(ctx, test.Options())
+		})
+		pendingPod := func(pending time.Duration, opts test.PodOptions) *corev1.Pod {
+			pod := test.UnschedulablePod(opts)
+			pod.CreationTimestamp = metav1.NewTime(fakeClock.Now().Add(-24 * time.Hour))
+			pod.Status.Conditions[0].LastTransitionTime = metav1.NewTime(fakeClock.Now().Add(-pending))
+			return pod
+		}
+		solve := func(pod *corev1.Pod) scheduling.Results {
+			s, err := prov.NewScheduler(ctx, []*corev1.Pod{pod}, nil)
+			Expect(err).ToNot(HaveOccurred())
+			return s.Solve(ctx, []*corev1.Pod{pod})
+		}
+		preferSpot := test.PodOptions{NodePreferences: []corev1.NodeSelectorRequirement{{
+			Key: v1.CapacityTypeLabelKey, Operator: corev1.NodeSelectorOpIn, Values: []string{v1.CapacityTypeSpot},
+		}}}
+		It("should keep the soft constraints of pods that haven't exceeded the SLA", func() {
+			ExpectApplied(ctx, env.Client, nodePool)
+			results := solve(pendingPod(time.Minute, preferSpot))
+			Expect(results.PodErrors).To(BeEmpty())
+			Expect(results.NewNodeClaims).To(HaveLen(1))
+			Expect(results.NewNodeClaims[0].Requirements.Get(v1.CapacityTypeLabelKey).Has(v1.CapacityTypeOnDemand)).To(BeFalse())
+		})
+		It("should relax the soft constraints of pods that exceeded the SLA", func() {
+			ExpectApplied(ctx, env.Client, nodePool)
+			results := solve(pendingPod(time.Hour, preferSpot))
+			Expect(results.PodErrors).To(BeEmpty())
+			Expect(results.NewNodeClaims).To(HaveLen(1))
+			Expect(results.NewNodeClaims[0].Requirements.Get(v1.CapacityTypeLabelKey).Has(v1.CapacityTypeOnDemand)).To(BeTrue())
+		})
+		It("should launch capacity types that aren't in the split for pods that exceeded the SLA", func() {
+			nodePool.Spec.CapacityTypeSplit = map[string]int32{v1.CapacityTypeSpot: 100}
+			ExpectApplied(ctx, env.Client, nodePool)
+			opts := test.PodOptions{NodeSelector: map[string]string{v1.CapacityTypeLabelKey: v1.CapacityTypeOnDemand}}
+			Expect(solve(pendingPod(time.Minute, opts)).PodErrors).To(HaveLen(1))
+
+			results := solve(pendingPod(time.Hour, opts))
+			Expect(results.PodErrors).To(BeEmpty())
+			Expect(results.NewNodeClaims).To(HaveLen(1))
+			Expect(results.NewNodeClaims[0].Requirements.Get(v1.CapacityTypeLabelKey).Values()).To(ConsistOf(v1.CapacityTypeOnDemand))
+		})
+		It("should measure how long pods are pending from their creation without an unschedulable transition time", func() {
+			ExpectApplied(ctx, env.Client, nodePool)
+			pod := test.UnschedulablePod(preferSpot)
+			pod.CreationTimestamp = metav1.NewTime(fakeClock.Now().Add(-time.Hour))
+			results := solve(pod)
+			Expect(results.PodErrors).To(BeEmpty())
+			Expect(results.NewNodeClaims).To(HaveLen(1))
+			Expect(results.NewNodeClaims[0].Requirements.Get(v1.CapacityTypeLabelKey).Has(v1.CapacityTypeOnDemand)).To(BeTrue())
+		})
+		It("should not escalate the scheduling of headroom pods", func() {
+			nodePool.Spec.CapacityTypeSplit = map[string]int32{v1.CapacityTypeOnDemand: 100}
+			nodePool.Spec.MinNodes = lo.ToPtr[int32](1)
+			ExpectApplied(ctx, env.Client, nodePool)
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov)
+			nodeClaims := ExpectNodeClaims(ctx, env.Client)
+			Expect(nodeClaims).To(HaveLen(1))
+			Expect(nodeClaims[0].Labels).To(HaveKeyWithValue(v1.CapacityTypeLabelKey, v1.CapacityTypeOnDemand))
+		})
+	})
+	Describe("Failure Explanations", func() {
+		It("should explain which constraint eliminated each NodePool", func() {
+			zonal := test.NodePool(v1.NodePool{Spec: v1.NodePoolSpec{
+				Weight: lo.ToPtr(int32(2)),
+				Template: v1.NodeClaimTemplate{Spec: v1.NodeClaimTemplateSpec{
+					Requirements: []v1.NodeSelectorRequirementWithMinValues{{NodeSelectorRequirement: corev1.NodeSelectorRequirement{
+						Key: corev1.LabelTopologyZone, Operator: corev1.NodeSelectorOpIn, Values: []string{"test-zone-1"},
+					}}},
+				}},
+			}})
+			tainted := test.NodePool(v1.NodePool{Spec: v1.NodePoolSpec{
+				Weight: lo.ToPtr(int32(1)),
+				Template: v1.NodeClaimTemplate{Spec: v1.NodeClaimTemplateSpec{
+					Taints: []corev1.Taint{{Key: "test-key", Value: "test-value", Effect: corev1.TaintEffectNoSchedule}},
+				}},
+			}})
+			ExpectApplied(ctx, env.Client, zonal, tainted)
+			pod := test.UnschedulablePod(test.PodOptions{NodeSelector: map[string]string{corev1.LabelTopologyZone: "test-zone-2"}})
+			s, err := prov.NewScheduler(ctx, []*corev1.Pod{pod}, nil)
+			Expect(err).ToNot(HaveOccurred())
+			results := s.Solve(ctx, []*corev1.Pod{pod})
+			Expect(results.PodErrors).To(HaveKey(pod))
+			Expect(scheduling.NodePoolFailures(results.PodErrors[pod])).To(ConsistOf(
+				scheduling.NodePoolFailure{NodePoolName: zonal.Name, Reason: scheduling.FailureReasonRequirement, Details: []string{corev1.LabelTopologyZone}},
+				scheduling.NodePoolFailure{NodePoolName: tainted.Name, Reason: scheduling.FailureReasonTaint, Details: []string{"test-key=test-value:NoSchedule"}},
+			))
+
+			recorder := test.NewEventRecorder()
+			results.Record(ctx, recorder, cluster)
+			Expect(recorder.DetectedEvent(fmt.Sprintf(`0/2 nodepools are available: nodepool %q: requirement topology.kubernetes.io/zone; nodepool %q: taint test-key=test-value:NoSchedule`,
+				zonal.Name, tainted.Name))).To(BeTrue())
+		})
+		It("should explain which resources no instance type has enough of", func() {
+			ExpectApplied(ctx, env.Client, nodePool)
+			pod := test.UnschedulablePod(test.PodOptions{ResourceRequirements: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1000"), corev1.ResourceMemory: resource.MustParse("1Gi")},
+			}})
+			s, err := prov.NewScheduler(ctx, []*corev1.Pod{pod}, nil)
+			Expect(err).ToNot(HaveOccurred())
+			results := s.Solve(ctx, []*corev1.Pod{pod})
+			Expect(scheduling.NodePoolFailures(results.PodErrors[pod])).To(ConsistOf(
+				scheduling.NodePoolFailure{NodePoolName: nodePool.Name, Reason: scheduling.FailureReasonResources, Details: []string{string(corev1.ResourceCPU)}},
+			))
+		})
+		It("should explain which NodePools are at their limits", func() {
+			nodePool.Spec.Limits = v1.Limits(corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("0")})
+			ExpectApplied(ctx, env.Client, nodePool)
+			pod := test.UnschedulablePod()
+			s, err := prov.NewScheduler(ctx, []*corev1.Pod{pod}, nil)
+			Expect(err).ToNot(HaveOccurred())
+			results := s.Solve(ctx, []*corev1.Pod{pod})
+			Expect(scheduling.NodePoolFailures(results.PodErrors[pod])).To(ConsistOf(
+				scheduling.NodePoolFailure{NodePoolName: nodePool.Name, Reason: scheduling.FailureReasonLimits},
+			))
+		})
+	})
+	Describe("Allowed Namespaces", func() {
+		var tenant *corev1.Namespace
+		BeforeEach(func() {
+			tenant = &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tenant-a"}}
+			ExpectApplied(ctx, env.Client, tenant)
+		})
+		It("should launch nodes for pods in the allowed namespaces", func() {
+			nodePool.Spec.AllowedNamespaces = []string{tenant.Name}
+			ExpectApplied(ctx, env.Client, nodePool)
+			pod := test.UnschedulablePod(test.PodOptions{ObjectMeta: metav1.ObjectMeta{Namespace: tenant.Name}})
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			node := ExpectScheduled(ctx, env.Client, pod)
+			Expect(node.Labels).To(HaveKeyWithValue(v1.NodePoolLabelKey, nodePool.Name))
+		})
+		It("should not launch nodes for pods in other namespaces", func() {
+			nodePool.Spec.AllowedNamespaces = []string{tenant.Name}
+			ExpectApplied(ctx, env.Client, nodePool)
+			pod := test.UnschedulablePod()
+			s, err := prov.NewScheduler(ctx, []*corev1.Pod{pod}, nil)
+			Expect(err).ToNot(HaveOccurred())
+			results := s.Solve(ctx, []*corev1.Pod{pod})
+			Expect(results.NewNodeClaims).To(BeEmpty())
+			Expect(scheduling.NodePoolFailures(results.PodErrors[pod])).To(ConsistOf(
+				scheduling.NodePoolFailure{NodePoolName: nodePool.Name, Reason: scheduling.FailureReasonNamespace, Details: []string{pod.Namespace}},
+			))
+
+			recorder := test.NewEventRecorder()
+			results.Record(ctx, recorder, cluster)
+			Expect(recorder.Calls("NamespaceNotAllowed")).To(Equal(1))
+			Expect(recorder.DetectedEvent(fmt.Sprintf(`Namespace %q isn't allowed by nodepools %s`, pod.Namespace, nodePool.Name))).To(BeTrue())
+		})
+		It("should launch nodes from an unrestricted NodePool for pods in other namespaces", func() {
+			nodePool.Spec.AllowedNamespaces = []string{tenant.Name}
+			nodePool.Spec.Weight = lo.ToPtr(int32(100))
+			shared := test.NodePool()
+			ExpectApplied(ctx, env.Client, nodePool, shared)
+			pod := test.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			node := ExpectScheduled(ctx, env.Client, pod)
+			Expect(node.Labels).To(HaveKeyWithValue(v1.NodePoolLabelKey, shared.Name))
+		})
+		It("should not schedule pods in other namespaces to the existing nodes of a restricted NodePool", func() {
+			nodePool.Spec.AllowedNamespaces = []string{tenant.Name}
+			shared := test.NodePool()
+			ExpectApplied(ctx, env.Client, nodePool, shared)
+			tenantPod := test.UnschedulablePod(test.PodOptions{ObjectMeta: metav1.ObjectMeta{Namespace: tenant.Name}})
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, tenantPod)
+			tenantNode := ExpectScheduled(ctx, env.Client, tenantPod)
+			Expect(tenantNode.Labels).To(HaveKeyWithValue(v1.NodePoolLabelKey, nodePool.Name))
+
+			pod := test.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			node := ExpectScheduled(ctx, env.Client, pod)
+			Expect(node.Name).ToNot(Equal(tenantNode.Name))
+			Expect(node.Labels).To(HaveKeyWithValue(v1.NodePoolLabelKey, shared.Name))
+		})
+		It("should not launch nodes from a restricted NodePool for the expected replicas of a StatefulSet in other namespaces", func() {
+			nodePool.Spec.AllowedNamespaces = []string{tenant.Name}
+			ExpectApplied(ctx, env.Client, nodePool)
+			statefulSet := test.StatefulSet()
+			statefulSet.Spec.Replicas = lo.ToPtr[int32](3)
+			pod := test.UnschedulablePod(test.PodOptions{ObjectMeta: metav1.ObjectMeta{Name: statefulSet.Name + "-0", Namespace: statefulSet.Namespace}})
+			replicas := scheduling.NewStatefulSetPods(statefulSet, pod)
+			Expect(replicas).To(HaveLen(2))
+			s, err := prov.NewScheduler(ctx, replicas, nil)
+			Expect(err).ToNot(HaveOccurred())
+			results := s.Solve(ctx, replicas)
+			Expect(results.NewNodeClaims).To(BeEmpty())
+			for _, replica := range replicas {
+				Expect(scheduling.NodePoolFailures(results.PodErrors[replica])).To(ConsistOf(
+					scheduling.NodePoolFailure{NodePoolName: nodePool.Name, Reason: scheduling.FailureReasonNamespace, Details: []string{replica.Namespace}},
+				))
+			}
+		})
+		It("should launch nodes to reach the minimum of a restricted NodePool", func() {
+			nodePool.Spec.AllowedNamespaces = []string{tenant.Name}
+			nodePool.Spec.MinNodes = lo.ToPtr[int32](2)
+			ExpectApplied(ctx, env.Client, nodePool)
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov)
+			Expect(ExpectNodeClaims(ctx, env.Client)).To(HaveLen(2))
+		})
+	})
 	Describe("Deleting Nodes", func() {
 		It("should re-schedule pods from a deleting node when pods are active", func() {
 			ExpectApplied(ctx, env.Client, nodePool)
@@ -3744,6 +4763,26 @@ var _ = Context("Scheduling", func() {
 			_, ok = lo.Find(m.Histogram.Bucket, func(b *io_prometheus_client.Bucket) bool { return lo.FromPtr(b.CumulativeCount) > 0 })
 			Expect(ok).To(BeTrue())
 		})
+		It("should surface the instance types that were rejected for a launched NodeClaim by reason", func() {
+			nodePool = test.NodePool()
+			ExpectApplied(ctx, env.Client, nodePool)
+			m, _ := FindMetricWithLabelValues("karpenter_scheduler_instance_types_rejected_total", map[string]string{"nodepool": nodePool.Name, "reason": "requirement"})
+			Expect(m).To(BeNil())
+
+			pod := test.UnschedulablePod(test.PodOptions{
+				NodeSelector:         map[string]string{corev1.LabelArchStable: v1.ArchitectureAmd64},
+				ResourceRequirements: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("3")}},
+			})
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+
+			m, ok := FindMetricWithLabelValues("karpenter_scheduler_instance_types_rejected_total", map[string]string{"nodepool": nodePool.Name, "reason": "requirement"})
+			Expect(ok).To(BeTrue())
+			Expect(lo.FromPtr(m.Counter.Value)).To(BeNumerically(">", 0))
+			m, ok = FindMetricWithLabelValues("karpenter_scheduler_instance_types_rejected_total", map[string]string{"nodepool": nodePool.Name, "reason": "resources"})
+			Expect(ok).To(BeTrue())
+			Expect(lo.FromPtr(m.Counter.Value)).To(BeNumerically(">", 0))
+		})
 		It("should set the PodSchedulerDecisionSeconds metric after a scheduling loop", func() {
 			// Find the starting point since the metric is shared across test suites
 			m, _ := FindMetricWithLabelValues("karpenter_pods_scheduling_decision_duration_seconds", nil)
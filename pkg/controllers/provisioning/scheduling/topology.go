@@ -24,7 +24,6 @@ import (
 	"github.com/awslabs/operatorpkg/option"
 	"go.uber.org/multierr"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/selection"
@@ -39,16 +38,29 @@ import (
 )
 
 type Topology struct {
-	kubeClient client.Client
 	// Both the topologies and inverseTopologies are maps of the hash from TopologyGroup.Hash() to the topology group
 	// itself. This is used to allow us to store one topology group that tracks the topology of many pods instead of
 	// having a 1<->1 mapping between topology groups and pods owned/selected by that group.
 	topologies map[uint64]*TopologyGroup
+	// topologyIndex indexes the topologies by the labels that their selectors require, so that recording a pod only
+	// evaluates the selectors of the topologies that could select it
+	topologyIndex *TopologyIndex
+	// topologyOwners are the hashes of the topologies that each pod owns, so that the topologies that control the
+	// scheduling of a pod are found without scanning every topology
+	topologyOwners map[types.UID]sets.Set[uint64]
 	// Anti-affinity works both ways (if a zone has a pod foo with anti-affinity to a pod bar, we can't schedule bar to
 	// that zone, even though bar has no anti affinity terms on it. For this to work, we need to separately track the
 	// topologies of pods with anti-affinity terms, so we can prevent scheduling the pods they have anti-affinity to
 	// in some cases.
 	inverseTopologies map[uint64]*TopologyGroup
+	// inverseTopologyIndex indexes the inverse topologies by the labels that their selectors require, so that we only
+	// evaluate the selectors of the inverse topologies that could select a pod
+	inverseTopologyIndex *TopologyIndex
+	// inverseTopologyOwners are the hashes of the inverse topologies that each pod owns
+	inverseTopologyOwners map[types.UID]sets.Set[uint64]
+	// topologiesByKey are the topologies and inverse topologies by their topology key, so that registering a domain only
+	// visits the topologies of that key
+	topologiesByKey map[string][]*TopologyGroup
 	// The universe of domains by topology key
 	domains map[string]sets.Set[string]
 	// excludedPods are the pod UIDs of pods that are excluded from counting.  This is used so we can simulate
@@ -57,14 +69,18 @@ type Topology struct {
 	cluster      *state.Cluster
 }
 
-func NewTopology(ctx context.Context, kubeClient client.Client, cluster *state.Cluster, domains map[string]sets.Set[string], pods []*corev1.Pod) (*Topology, error) {
+func NewTopology(ctx context.Context, cluster *state.Cluster, domains map[string]sets.Set[string], pods []*corev1.Pod) (*Topology, error) {
 	t := &Topology{
-		kubeClient:        kubeClient,
-		cluster:           cluster,
-		domains:           domains,
-		topologies:        map[uint64]*TopologyGroup{},
-		inverseTopologies: map[uint64]*TopologyGroup{},
-		excludedPods:      sets.New[string](),
+		cluster:               cluster,
+		domains:               domains,
+		topologies:            map[uint64]*TopologyGroup{},
+		topologyIndex:         NewTopologyIndex(),
+		topologyOwners:        map[types.UID]sets.Set[uint64]{},
+		inverseTopologies:     map[uint64]*TopologyGroup{},
+		inverseTopologyIndex:  NewTopologyIndex(),
+		inverseTopologyOwners: map[types.UID]sets.Set[uint64]{},
+		topologiesByKey:       map[string][]*TopologyGroup{},
+		excludedPods:          sets.New[string](),
 	}
 
 	// these are the pods that we intend to schedule, so if they are currently in the cluster we shouldn't count them for
@@ -73,7 +89,7 @@ func NewTopology(ctx context.Context, kubeClient client.Client, cluster *state.C
 		t.excludedPods.Insert(string(p.UID))
 	}
 
-	errs := t.updateInverseAffinities(ctx)
+	errs := t.updateInverseAffinities()
 	for i := range pods {
 		errs = multierr.Append(errs, t.Update(ctx, pods[i]))
 	}
@@ -101,18 +117,19 @@ func (t topologyError) Error() string {
 // relaxation of a preference to properly break the topology <-> owner relationship so that the preferred topology will
 // no longer influence scheduling.
 func (t *Topology) Update(ctx context.Context, p *corev1.Pod) error {
-	for _, topology := range t.topologies {
-		topology.RemoveOwner(p.UID)
+	for hash := range t.topologyOwners[p.UID] {
+		t.topologies[hash].RemoveOwner(p.UID)
 	}
+	delete(t.topologyOwners, p.UID)
 
 	if pod.HasPodAntiAffinity(p) {
-		if err := t.updateInverseAntiAffinity(ctx, p, nil); err != nil {
+		if err := t.updateInverseAntiAffinity(p, nil); err != nil {
 			return fmt.Errorf("updating inverse anti-affinities, %w", err)
 		}
 	}
 
 	topologies := t.newForTopologies(p)
-	affinities, err := t.newForAffinities(ctx, p)
+	affinities, err := t.newForAffinities(p)
 	if err != nil {
 		return fmt.Errorf("updating affinities, %w", err)
 	}
@@ -121,23 +138,31 @@ func (t *Topology) Update(ctx context.Context, p *corev1.Pod) error {
 		hash := tg.Hash()
 		// Avoid recomputing topology counts if we've already seen this group
 		if existing, ok := t.topologies[hash]; !ok {
-			if err := t.countDomains(ctx, tg); err != nil {
-				return err
-			}
+			t.countDomains(tg)
 			t.topologies[hash] = tg
+			t.topologyIndex.Add(hash, tg.rawSelector)
+			t.topologiesByKey[tg.Key] = append(t.topologiesByKey[tg.Key], tg)
 		} else {
 			tg = existing
 		}
 		tg.AddOwner(p.UID)
+		addOwnedHash(t.topologyOwners, p.UID, hash)
 	}
 	return nil
 }
 
+func addOwnedHash(owners map[types.UID]sets.Set[uint64], uid types.UID, hash uint64) {
+	if _, ok := owners[uid]; !ok {
+		owners[uid] = sets.New[uint64]()
+	}
+	owners[uid].Insert(hash)
+}
+
 // Record records the topology changes given that pod p schedule on a node with the given requirements
 func (t *Topology) Record(p *corev1.Pod, requirements scheduling.Requirements, compatabilityOptions ...option.Function[scheduling.CompatibilityOptions]) {
 	// once we've committed to a domain, we record the usage in every topology that cares about it
-	for _, tc := range t.topologies {
-		if tc.Counts(p, requirements, compatabilityOptions...) {
+	for _, hash := range t.topologyIndex.Candidates(p) {
+		if tc := t.topologies[hash]; tc.Counts(p, requirements, compatabilityOptions...) {
 			domains := requirements.Get(tc.Key)
 			if tc.Type == TopologyTypePodAntiAffinity {
 				// for anti-affinity topologies we need to block out all possible domains that the pod could land in
@@ -152,10 +177,9 @@ func (t *Topology) Record(p *corev1.Pod, requirements scheduling.Requirements, c
 	}
 	// for anti-affinities, we record where the pods could be, even if
 	// requirements haven't collapsed to a single value.
-	for _, tc := range t.inverseTopologies {
-		if tc.IsOwnedBy(p.UID) {
-			tc.Record(requirements.Get(tc.Key).Values()...)
-		}
+	for hash := range t.inverseTopologyOwners[p.UID] {
+		tc := t.inverseTopologies[hash]
+		tc.Record(requirements.Get(tc.Key).Values()...)
 	}
 }
 
@@ -189,42 +213,28 @@ func (t *Topology) AddRequirements(podRequirements, nodeRequirements scheduling.
 
 // Register is used to register a domain as available across topologies for the given topology key.
 func (t *Topology) Register(topologyKey string, domain string) {
-	for _, topology := range t.topologies {
-		if topology.Key == topologyKey {
-			topology.Register(domain)
-		}
-	}
-	for _, topology := range t.inverseTopologies {
-		if topology.Key == topologyKey {
-			topology.Register(domain)
-		}
+	for _, topology := range t.topologiesByKey[topologyKey] {
+		topology.Register(domain)
 	}
 }
 
 // Unregister is used to unregister a domain as available across topologies for the given topology key.
 func (t *Topology) Unregister(topologyKey string, domain string) {
-	for _, topology := range t.topologies {
-		if topology.Key == topologyKey {
-			topology.Unregister(domain)
-		}
-	}
-	for _, topology := range t.inverseTopologies {
-		if topology.Key == topologyKey {
-			topology.Unregister(domain)
-		}
+	for _, topology := range t.topologiesByKey[topologyKey] {
+		topology.Unregister(domain)
 	}
 }
 
 // updateInverseAffinities is used to identify pods with anti-affinity terms so we can track those topologies.  We
 // have to look at every pod in the cluster as there is no way to query for a pod with anti-affinity terms.
-func (t *Topology) updateInverseAffinities(ctx context.Context) error {
+func (t *Topology) updateInverseAffinities() error {
 	var errs error
 	t.cluster.ForPodsWithAntiAffinity(func(pod *corev1.Pod, node *corev1.Node) bool {
 		// don't count the pod we are excluding
 		if t.excludedPods.Has(string(pod.UID)) {
 			return true
 		}
-		if err := t.updateInverseAntiAffinity(ctx, pod, node.Labels); err != nil {
+		if err := t.updateInverseAntiAffinity(pod, node.Labels); err != nil {
 			errs = multierr.Append(errs, fmt.Errorf("tracking existing pod anti-affinity, %w", err))
 		}
 		return true
@@ -234,22 +244,24 @@ func (t *Topology) updateInverseAffinities(ctx context.Context) error {
 
 // updateInverseAntiAffinity is used to track topologies of inverse anti-affinities. Here the domains & counts track the
 // pods with the anti-affinity.
-func (t *Topology) updateInverseAntiAffinity(ctx context.Context, pod *corev1.Pod, domains map[string]string) error {
+func (t *Topology) updateInverseAntiAffinity(pod *corev1.Pod, domains map[string]string) error {
 	// We intentionally don't track inverse anti-affinity preferences. We're not
 	// required to enforce them so it just adds complexity for very little
 	// value.  The problem with them comes from the relaxation process, the pod
 	// we are relaxing is not the pod with the anti-affinity term.
 	for _, term := range pod.Spec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution {
-		namespaces, err := t.buildNamespaceList(ctx, pod.Namespace, term.Namespaces, term.NamespaceSelector)
+		namespaces, err := t.buildNamespaceList(pod.Namespace, term.Namespaces, term.NamespaceSelector)
 		if err != nil {
 			return err
 		}
 
-		tg := NewTopologyGroup(TopologyTypePodAntiAffinity, term.TopologyKey, pod, namespaces, term.LabelSelector, math.MaxInt32, nil, t.domains[term.TopologyKey])
+		tg := NewTopologyGroup(TopologyTypePodAntiAffinity, term.TopologyKey, pod, namespaces, term.LabelSelector, math.MaxInt32, nil, nil, t.domains[term.TopologyKey])
 
 		hash := tg.Hash()
 		if existing, ok := t.inverseTopologies[hash]; !ok {
 			t.inverseTopologies[hash] = tg
+			t.inverseTopologyIndex.Add(hash, tg.rawSelector)
+			t.topologiesByKey[tg.Key] = append(t.topologiesByKey[tg.Key], tg)
 		} else {
 			tg = existing
 		}
@@ -257,79 +269,59 @@ func (t *Topology) updateInverseAntiAffinity(ctx context.Context, pod *corev1.Po
 			tg.Record(domain)
 		}
 		tg.AddOwner(pod.UID)
+		addOwnedHash(t.inverseTopologyOwners, pod.UID, hash)
 	}
 	return nil
 }
 
 // countDomains initializes the topology group by registereding any well known domains and performing pod counts
-// against the cluster for any existing pods.
-//
-//nolint:gocyclo
-func (t *Topology) countDomains(ctx context.Context, tg *TopologyGroup) error {
-	podList := &corev1.PodList{}
-
-	// collect the pods from all the specified namespaces (don't see a way to query multiple namespaces
-	// simultaneously)
-	var pods []corev1.Pod
+// against the cluster for any existing pods. Bound pods are read from the cluster state's incrementally maintained
+// index rather than listed from the API server so that the cost doesn't grow with the number of topology groups.
+func (t *Topology) countDomains(tg *TopologyGroup) {
+	selector := TopologyListOptions("", tg.rawSelector).LabelSelector
+	// collect the pods from all the specified namespaces
 	for _, ns := range tg.namespaces.UnsortedList() {
-		if err := t.kubeClient.List(ctx, podList, TopologyListOptions(ns, tg.rawSelector)); err != nil {
-			return fmt.Errorf("listing pods, %w", err)
-		}
-		pods = append(pods, podList.Items...)
-	}
-
-	for i, p := range pods {
-		if IgnoredForTopology(&pods[i]) {
-			continue
-		}
-		// pod is excluded for counting purposes
-		if t.excludedPods.Has(string(p.UID)) {
-			continue
-		}
-		node := &corev1.Node{}
-		if err := t.kubeClient.Get(ctx, types.NamespacedName{Name: p.Spec.NodeName}, node); err != nil {
-			// Pods that cannot be evicted can be leaked in the API Server after
-			// a Node is removed. Since pod bindings are immutable, these pods
-			// cannot be recovered, and will be deleted by the pod lifecycle
-			// garbage collector. These pods are not running, and should not
-			// impact future topology calculations.
-			if errors.IsNotFound(err) {
-				continue
+		t.cluster.ForPodsInNamespace(ns, func(p *corev1.Pod, node *corev1.Node) bool {
+			if IgnoredForTopology(p) || !selector.Matches(labels.Set(p.Labels)) {
+				return true
 			}
-			return fmt.Errorf("getting node %s, %w", p.Spec.NodeName, err)
-		}
-		domain, ok := node.Labels[tg.Key]
-		// Kubelet sets the hostname label, but the node may not be ready yet so there is no label.  We fall back and just
-		// treat the node name as the label.  It probably is in most cases, but even if not we at least count the existence
-		// of the pods in some domain, even if not in the correct one.  This is needed to handle the case of pods with
-		// self-affinity only fulfilling that affinity if all domains are empty.
-		if !ok && tg.Key == corev1.LabelHostname {
-			domain = node.Name
-			ok = true
-		}
-		if !ok {
-			continue // Don't include pods if node doesn't contain domain https://kubernetes.io/docs/concepts/workloads/pods/pod-topology-spread-constraints/#conventions
-		}
-		// nodes may or may not be considered for counting purposes for topology spread constraints depending on if they
-		// are selected by the pod's node selectors and required node affinities.  If these are unset, the node always counts.
-		if !tg.nodeFilter.Matches(node) {
-			continue
-		}
-		tg.Record(domain)
+			// pod is excluded for counting purposes
+			if t.excludedPods.Has(string(p.UID)) {
+				return true
+			}
+			domain, ok := node.Labels[tg.Key]
+			// Kubelet sets the hostname label, but the node may not be ready yet so there is no label.  We fall back and just
+			// treat the node name as the label.  It probably is in most cases, but even if not we at least count the existence
+			// of the pods in some domain, even if not in the correct one.  This is needed to handle the case of pods with
+			// self-affinity only fulfilling that affinity if all domains are empty.
+			if !ok && tg.Key == corev1.LabelHostname {
+				domain = node.Name
+				ok = true
+			}
+			if !ok {
+				return true // Don't include pods if node doesn't contain domain https://kubernetes.io/docs/concepts/workloads/pods/pod-topology-spread-constraints/#conventions
+			}
+			// nodes may or may not be considered for counting purposes for topology spread constraints depending on if they
+			// are selected by the pod's node selectors and required node affinities.  If these are unset, the node always counts.
+			if !tg.nodeFilter.Matches(node) {
+				return true
+			}
+			tg.Record(domain)
+			return true
+		})
 	}
-	return nil
 }
 
 func (t *Topology) newForTopologies(p *corev1.Pod) []*TopologyGroup {
 	var topologyGroups []*TopologyGroup
 	for _, cs := range p.Spec.TopologySpreadConstraints {
-		topologyGroups = append(topologyGroups, NewTopologyGroup(TopologyTypeSpread, cs.TopologyKey, p, sets.New(p.Namespace), cs.LabelSelector, cs.MaxSkew, cs.MinDomains, t.domains[cs.TopologyKey]))
+		topologyGroups = append(topologyGroups, NewTopologyGroup(TopologyTypeSpread, cs.TopologyKey, p, sets.New(p.Namespace), cs.LabelSelector, cs.MaxSkew, cs.MinDomains, cs.NodeAffinityPolicy, t.domains[cs.TopologyKey]))
 	}
 	return topologyGroups
 }
 
 // newForAffinities returns a list of topology groups that have been constructed based on the input pod and required/preferred affinity terms
-func (t *Topology) newForAffinities(ctx context.Context, p *corev1.Pod) ([]*TopologyGroup, error) {
+func (t *Topology) newForAffinities(p *corev1.Pod) ([]*TopologyGroup, error) {
 	var topologyGroups []*TopologyGroup
 	// No affinity defined
 	if p.Spec.Affinity == nil {
@@ -356,52 +348,43 @@ func (t *Topology) newForAffinities(ctx context.Context, p *corev1.Pod) ([]*Topo
 	// build topologies
 	for topologyType, terms := range affinityTerms {
 		for _, term := range terms {
-			namespaces, err := t.buildNamespaceList(ctx, p.Namespace, term.Namespaces, term.NamespaceSelector)
+			namespaces, err := t.buildNamespaceList(p.Namespace, term.Namespaces, term.NamespaceSelector)
 			if err != nil {
 				return nil, err
 			}
-			topologyGroups = append(topologyGroups, NewTopologyGroup(topologyType, term.TopologyKey, p, namespaces, term.LabelSelector, math.MaxInt32, nil, t.domains[term.TopologyKey]))
+			topologyGroups = append(topologyGroups, NewTopologyGroup(topologyType, term.TopologyKey, p, namespaces, term.LabelSelector, math.MaxInt32, nil, nil, t.domains[term.TopologyKey]))
 		}
 	}
 	return topologyGroups, nil
 }
 
 // buildNamespaceList constructs a unique list of namespaces consisting of the pod's namespace and the optional list of
-// namespaces and those selected by the namespace selector
-func (t *Topology) buildNamespaceList(ctx context.Context, namespace string, namespaces []string, selector *metav1.LabelSelector) (sets.Set[string], error) {
+// namespaces and those selected by the namespace selector. The namespace selector is resolved against the namespaces
+// tracked by cluster state, and as in the kube-scheduler, the term applies to the union of the selected namespaces and
+// the explicit list of namespaces rather than to the namespaces that are both listed and selected.
+func (t *Topology) buildNamespaceList(namespace string, namespaces []string, selector *metav1.LabelSelector) (sets.Set[string], error) {
 	if len(namespaces) == 0 && selector == nil {
 		return sets.New(namespace), nil
 	}
 	if selector == nil {
 		return sets.New(namespaces...), nil
 	}
-	var namespaceList corev1.NamespaceList
 	labelSelector, err := metav1.LabelSelectorAsSelector(selector)
 	if err != nil {
 		return nil, fmt.Errorf("parsing selector, %w", err)
 	}
-	if err := t.kubeClient.List(ctx, &namespaceList, &client.ListOptions{LabelSelector: labelSelector}); err != nil {
-		return nil, fmt.Errorf("listing namespaces, %w", err)
-	}
-	selected := sets.New[string]()
-	for _, namespace := range namespaceList.Items {
-		selected.Insert(namespace.Name)
-	}
-	selected.Insert(namespaces...)
-	return selected, nil
+	return t.cluster.NamespacesMatching(labelSelector).Insert(namespaces...), nil
 }
 
 // getMatchingTopologies returns a sorted list of topologies that either control the scheduling of pod p, or for which
 // the topology selects pod p and the scheduling of p affects the count per topology domain
 func (t *Topology) getMatchingTopologies(p *corev1.Pod, requirements scheduling.Requirements, compatabilityOptions ...option.Function[scheduling.CompatibilityOptions]) []*TopologyGroup {
 	var matchingTopologies []*TopologyGroup
-	for _, tc := range t.topologies {
-		if tc.IsOwnedBy(p.UID) {
-			matchingTopologies = append(matchingTopologies, tc)
-		}
+	for hash := range t.topologyOwners[p.UID] {
+		matchingTopologies = append(matchingTopologies, t.topologies[hash])
 	}
-	for _, tc := range t.inverseTopologies {
-		if tc.Counts(p, requirements, compatabilityOptions...) {
+	for _, hash := range t.inverseTopologyIndex.Candidates(p) {
+		if tc := t.inverseTopologies[hash]; tc.Counts(p, requirements, compatabilityOptions...) {
 			matchingTopologies = append(matchingTopologies, tc)
 		}
 	}
@@ -17,6 +17,7 @@ limitations under the License.
 package scheduling_test
 
 import (
+	"math"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -29,7 +30,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+	"sigs.k8s.io/karpenter/pkg/cloudprovider"
 	"sigs.k8s.io/karpenter/pkg/cloudprovider/fake"
+	"sigs.k8s.io/karpenter/pkg/controllers/provisioning/scheduling"
+	pscheduling "sigs.k8s.io/karpenter/pkg/scheduling"
 	"sigs.k8s.io/karpenter/pkg/test"
 	. "sigs.k8s.io/karpenter/pkg/test/expectations"
 )
@@ -410,7 +414,7 @@ var _ = Describe("Topology", func() {
 			ExpectReconcileSucceeded(ctx, nodeStateController, client.ObjectKeyFromObject(firstNode))
 			ExpectReconcileSucceeded(ctx, nodeStateController, client.ObjectKeyFromObject(secondNode))
 			ExpectReconcileSucceeded(ctx, nodeStateController, client.ObjectKeyFromObject(thirdNode))
-			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov,
+			ExpectProvisionedWithBoundPods(ctx, env.Client, cluster, cloudProvider, prov,
 				test.Pod(test.PodOptions{NodeName: firstNode.Name}),                                                                                               // ignored, missing labels
 				test.Pod(test.PodOptions{ObjectMeta: metav1.ObjectMeta{Labels: labels}}),                                                                          // ignored, pending
 				test.Pod(test.PodOptions{ObjectMeta: metav1.ObjectMeta{Labels: labels}, NodeName: thirdNode.Name}),                                                // ignored, no domain on node
@@ -745,7 +749,7 @@ var _ = Describe("Topology", func() {
 			ExpectReconcileSucceeded(ctx, nodeStateController, client.ObjectKeyFromObject(firstNode))
 			ExpectReconcileSucceeded(ctx, nodeStateController, client.ObjectKeyFromObject(secondNode))
 			ExpectReconcileSucceeded(ctx, nodeStateController, client.ObjectKeyFromObject(thirdNode))
-			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov,
+			ExpectProvisionedWithBoundPods(ctx, env.Client, cluster, cloudProvider, prov,
 				test.Pod(test.PodOptions{NodeName: firstNode.Name}),                                                                                               // ignored, missing labels
 				test.Pod(test.PodOptions{ObjectMeta: metav1.ObjectMeta{Labels: labels}}),                                                                          // ignored, pending
 				test.Pod(test.PodOptions{ObjectMeta: metav1.ObjectMeta{Labels: labels}, NodeName: thirdNode.Name}),                                                // ignored, no domain on node
@@ -923,6 +927,182 @@ var _ = Describe("Topology", func() {
 		})
 	})
 
+	Context("NodePool", func() {
+		It("should balance pods across nodepools", func() {
+			topology := []corev1.TopologySpreadConstraint{{
+				TopologyKey:       v1.NodePoolLabelKey,
+				WhenUnsatisfiable: corev1.DoNotSchedule,
+				LabelSelector:     &metav1.LabelSelector{MatchLabels: labels},
+				MaxSkew:           1,
+			}}
+			ExpectApplied(ctx, env.Client, nodePool, test.NodePool())
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov,
+				test.UnschedulablePods(test.PodOptions{ObjectMeta: metav1.ObjectMeta{Labels: labels}, TopologySpreadConstraints: topology}, 4)...,
+			)
+			ExpectSkew(ctx, env.Client, "default", &topology[0]).To(ConsistOf(2, 2))
+		})
+		It("should balance pods across nodepools with differing weights", func() {
+			topology := []corev1.TopologySpreadConstraint{{
+				TopologyKey:       v1.NodePoolLabelKey,
+				WhenUnsatisfiable: corev1.DoNotSchedule,
+				LabelSelector:     &metav1.LabelSelector{MatchLabels: labels},
+				MaxSkew:           1,
+			}}
+			nodePool.Spec.Weight = lo.ToPtr[int32](100)
+			ExpectApplied(ctx, env.Client, nodePool, test.NodePool(), test.NodePool())
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov,
+				test.UnschedulablePods(test.PodOptions{ObjectMeta: metav1.ObjectMeta{Labels: labels}, TopologySpreadConstraints: topology}, 6)...,
+			)
+			ExpectSkew(ctx, env.Client, "default", &topology[0]).To(ConsistOf(2, 2, 2))
+		})
+		It("should only spread across the nodepools selected by the pod", func() {
+			topology := []corev1.TopologySpreadConstraint{{
+				TopologyKey:       v1.NodePoolLabelKey,
+				WhenUnsatisfiable: corev1.DoNotSchedule,
+				LabelSelector:     &metav1.LabelSelector{MatchLabels: labels},
+				MaxSkew:           1,
+			}}
+			ExpectApplied(ctx, env.Client, nodePool, test.NodePool())
+			pods := test.UnschedulablePods(test.PodOptions{
+				ObjectMeta:                metav1.ObjectMeta{Labels: labels},
+				TopologySpreadConstraints: topology,
+				NodeSelector:              map[string]string{v1.NodePoolLabelKey: nodePool.Name},
+			}, 3)
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pods...)
+			for _, p := range pods {
+				node := ExpectScheduled(ctx, env.Client, p)
+				Expect(node.Labels[v1.NodePoolLabelKey]).To(Equal(nodePool.Name))
+			}
+			ExpectSkew(ctx, env.Client, "default", &topology[0]).To(ConsistOf(3))
+		})
+		It("should balance pods across nodepools with existing pods", func() {
+			topology := []corev1.TopologySpreadConstraint{{
+				TopologyKey:       v1.NodePoolLabelKey,
+				WhenUnsatisfiable: corev1.DoNotSchedule,
+				LabelSelector:     &metav1.LabelSelector{MatchLabels: labels},
+				MaxSkew:           1,
+			}}
+			other := test.NodePool()
+			ExpectApplied(ctx, env.Client, nodePool, other)
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov,
+				test.UnschedulablePods(test.PodOptions{
+					ObjectMeta:                metav1.ObjectMeta{Labels: labels},
+					TopologySpreadConstraints: topology,
+					NodeSelector:              map[string]string{v1.NodePoolLabelKey: nodePool.Name},
+				}, 2)...,
+			)
+			ExpectSkew(ctx, env.Client, "default", &topology[0]).To(ConsistOf(2))
+			pods := test.UnschedulablePods(test.PodOptions{ObjectMeta: metav1.ObjectMeta{Labels: labels}, TopologySpreadConstraints: topology}, 2)
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pods...)
+			for _, p := range pods {
+				node := ExpectScheduled(ctx, env.Client, p)
+				Expect(node.Labels[v1.NodePoolLabelKey]).To(Equal(other.Name))
+			}
+			ExpectSkew(ctx, env.Client, "default", &topology[0]).To(ConsistOf(2, 2))
+		})
+	})
+
+	Context("PlacementGroup", func() {
+		BeforeEach(func() {
+			cloudProvider.InstanceTypes = []*cloudprovider.InstanceType{
+				fake.NewInstanceType(fake.InstanceTypeOptions{
+					Name: "placement-group-instance-type",
+					Offerings: lo.Map([]string{"pg-1", "pg-2", "pg-3"}, func(pg string, _ int) cloudprovider.Offering {
+						return cloudprovider.Offering{
+							Requirements: pscheduling.NewLabelRequirements(map[string]string{
+								v1.CapacityTypeLabelKey:   v1.CapacityTypeOnDemand,
+								corev1.LabelTopologyZone:  "test-zone-1",
+								v1.PlacementGroupLabelKey: pg,
+							}),
+							Price:     1,
+							Available: true,
+						}
+					}),
+				}),
+			}
+		})
+		It("should balance pods across placement groups", func() {
+			topology := []corev1.TopologySpreadConstraint{{
+				TopologyKey:       v1.PlacementGroupLabelKey,
+				WhenUnsatisfiable: corev1.DoNotSchedule,
+				LabelSelector:     &metav1.LabelSelector{MatchLabels: labels},
+				MaxSkew:           1,
+			}}
+			ExpectApplied(ctx, env.Client, nodePool)
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov,
+				test.UnschedulablePods(test.PodOptions{ObjectMeta: metav1.ObjectMeta{Labels: labels}, TopologySpreadConstraints: topology}, 6)...,
+			)
+			ExpectSkew(ctx, env.Client, "default", &topology[0]).To(ConsistOf(2, 2, 2))
+		})
+		It("should only spread across the placement groups allowed by the nodepool", func() {
+			topology := []corev1.TopologySpreadConstraint{{
+				TopologyKey:       v1.PlacementGroupLabelKey,
+				WhenUnsatisfiable: corev1.DoNotSchedule,
+				LabelSelector:     &metav1.LabelSelector{MatchLabels: labels},
+				MaxSkew:           1,
+			}}
+			nodePool.Spec.Template.Spec.Requirements = append(nodePool.Spec.Template.Spec.Requirements, v1.NodeSelectorRequirementWithMinValues{
+				NodeSelectorRequirement: corev1.NodeSelectorRequirement{Key: v1.PlacementGroupLabelKey, Operator: corev1.NodeSelectorOpIn, Values: []string{"pg-1", "pg-2"}},
+			})
+			ExpectApplied(ctx, env.Client, nodePool)
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov,
+				test.UnschedulablePods(test.PodOptions{ObjectMeta: metav1.ObjectMeta{Labels: labels}, TopologySpreadConstraints: topology}, 4)...,
+			)
+			ExpectSkew(ctx, env.Client, "default", &topology[0]).To(ConsistOf(2, 2))
+		})
+		It("should pack pods into the placement group of their affinity target", func() {
+			affLabels := map[string]string{"security": "s2"}
+			affPod := test.UnschedulablePod(test.PodOptions{
+				ObjectMeta:   metav1.ObjectMeta{Labels: affLabels},
+				NodeSelector: map[string]string{v1.PlacementGroupLabelKey: "pg-2"},
+			})
+			pods := test.UnschedulablePods(test.PodOptions{
+				PodRequirements: []corev1.PodAffinityTerm{{
+					LabelSelector: &metav1.LabelSelector{MatchLabels: affLabels},
+					TopologyKey:   v1.PlacementGroupLabelKey,
+				}},
+			}, 3)
+			ExpectApplied(ctx, env.Client, nodePool)
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, append(pods, affPod)...)
+			Expect(ExpectScheduled(ctx, env.Client, affPod).Labels).To(HaveKeyWithValue(v1.PlacementGroupLabelKey, "pg-2"))
+			for _, p := range pods {
+				Expect(ExpectScheduled(ctx, env.Client, p).Labels).To(HaveKeyWithValue(v1.PlacementGroupLabelKey, "pg-2"))
+			}
+		})
+	})
+
+	Context("User-Defined Topology Domains", func() {
+		const rackLabelKey = "example.com/rack"
+		It("should balance pods across the domains declared by the nodepool", func() {
+			topology := []corev1.TopologySpreadConstraint{{
+				TopologyKey:       rackLabelKey,
+				WhenUnsatisfiable: corev1.DoNotSchedule,
+				LabelSelector:     &metav1.LabelSelector{MatchLabels: labels},
+				MaxSkew:           1,
+			}}
+			nodePool.Spec.TopologyDomains = map[string][]string{rackLabelKey: {"rack-a", "rack-b", "rack-c"}}
+			ExpectApplied(ctx, env.Client, nodePool)
+			pods := test.UnschedulablePods(test.PodOptions{ObjectMeta: metav1.ObjectMeta{Labels: labels}, TopologySpreadConstraints: topology}, 6)
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pods...)
+			for _, p := range pods {
+				node := ExpectScheduled(ctx, env.Client, p)
+				Expect(node.Labels).To(HaveKeyWithValue(rackLabelKey, BeElementOf("rack-a", "rack-b", "rack-c")))
+			}
+			ExpectSkew(ctx, env.Client, "default", &topology[0]).To(ConsistOf(2, 2, 2))
+		})
+		It("should not schedule pods that spread across domains that no nodepool declares", func() {
+			topology := []corev1.TopologySpreadConstraint{{
+				TopologyKey:       rackLabelKey,
+				WhenUnsatisfiable: corev1.DoNotSchedule,
+				LabelSelector:     &metav1.LabelSelector{MatchLabels: labels},
+				MaxSkew:           1,
+			}}
+			ExpectApplied(ctx, env.Client, nodePool)
+			pod := test.UnschedulablePod(test.PodOptions{ObjectMeta: metav1.ObjectMeta{Labels: labels}, TopologySpreadConstraints: topology})
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectNotScheduled(ctx, env.Client, pod)
+		})
+	})
 	Context("Combined Hostname and Zonal Topology", func() {
 		It("should spread pods while respecting both constraints (hostname and zonal)", func() {
 			topology := []corev1.TopologySpreadConstraint{{
@@ -1963,6 +2143,34 @@ var _ = Describe("Topology", func() {
 			// pod with anti-affinity rules that prevent it from scheduling
 			ExpectNotScheduled(ctx, env.Client, affPod)
 		})
+		It("should not violate pod anti-affinity on zone (inverse w/match expressions)", func() {
+			anti := []corev1.PodAffinityTerm{{
+				LabelSelector: &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "security", Operator: metav1.LabelSelectorOpIn, Values: []string{"s1", "s2"}}},
+				},
+				TopologyKey: corev1.LabelTopologyZone,
+			}}
+			rr := corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+			}
+			zonePods := lo.Map([]string{"test-zone-1", "test-zone-2", "test-zone-3"}, func(zone string, _ int) *corev1.Pod {
+				return test.UnschedulablePod(test.PodOptions{
+					ResourceRequirements: rr,
+					PodAntiRequirements:  anti,
+					NodeSelector:         map[string]string{corev1.LabelTopologyZone: zone}})
+			})
+			affPod := test.UnschedulablePod(test.PodOptions{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"security": "s2"}}})
+			otherPod := test.UnschedulablePod(test.PodOptions{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"security": "s3"}}})
+
+			ExpectApplied(ctx, env.Client, nodePool)
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, append(zonePods, affPod, otherPod)...)
+			for _, p := range zonePods {
+				ExpectScheduled(ctx, env.Client, p)
+			}
+			// the pod selected by the anti-affinity terms can't schedule to any zone, but the pod that isn't selected can
+			ExpectNotScheduled(ctx, env.Client, affPod)
+			ExpectScheduled(ctx, env.Client, otherPod)
+		})
 		It("should not violate pod anti-affinity on zone (Schrödinger)", func() {
 			affLabels := map[string]string{"security": "s2"}
 			anti := []corev1.PodAffinityTerm{{
@@ -2392,7 +2600,9 @@ var _ = Describe("Topology", func() {
 				MaxSkew:           1,
 			}}
 
-			ExpectApplied(ctx, env.Client, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "empty-ns-selector", Labels: map[string]string{"foo": "bar"}}})
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "empty-ns-selector", Labels: map[string]string{"foo": "bar"}}}
+			ExpectApplied(ctx, env.Client, ns)
+			ExpectReconcileSucceeded(ctx, namespaceStateController, client.ObjectKeyFromObject(ns))
 			affLabels := map[string]string{"security": "s2"}
 
 			affPod1 := test.UnschedulablePod(test.PodOptions{ObjectMeta: metav1.ObjectMeta{Labels: affLabels, Namespace: "empty-ns-selector"}})
@@ -2424,6 +2634,112 @@ var _ = Describe("Topology", func() {
 			// should be scheduled on the same node due to the empty namespace selector
 			Expect(n1.Name).To(Equal(n2.Name))
 		})
+		It("should filter pod affinity topologies by namespace, matching namespace selector", func() {
+			topology := []corev1.TopologySpreadConstraint{{
+				TopologyKey:       corev1.LabelHostname,
+				WhenUnsatisfiable: corev1.DoNotSchedule,
+				LabelSelector:     &metav1.LabelSelector{MatchLabels: labels},
+				MaxSkew:           1,
+			}}
+
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "matching-ns-selector", Labels: map[string]string{"team": "security"}}}
+			ExpectApplied(ctx, env.Client, ns)
+			ExpectReconcileSucceeded(ctx, namespaceStateController, client.ObjectKeyFromObject(ns))
+			affLabels := map[string]string{"security": "s2"}
+
+			affPod1 := test.UnschedulablePod(test.PodOptions{ObjectMeta: metav1.ObjectMeta{Labels: affLabels, Namespace: "matching-ns-selector"}})
+			// affPod2 will try to get scheduled with affPod1
+			affPod2 := test.UnschedulablePod(test.PodOptions{PodRequirements: []corev1.PodAffinityTerm{{
+				LabelSelector: &metav1.LabelSelector{
+					MatchLabels: affLabels,
+				},
+				NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "security"}},
+				TopologyKey:       corev1.LabelHostname,
+			}}})
+
+			var pods []*corev1.Pod
+			// create 10 nodes
+			pods = append(pods, test.UnschedulablePods(test.PodOptions{
+				ObjectMeta:                metav1.ObjectMeta{Labels: labels},
+				TopologySpreadConstraints: topology,
+			}, 10)...)
+			pods = append(pods, affPod1)
+			pods = append(pods, affPod2)
+
+			ExpectApplied(ctx, env.Client, nodePool)
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pods...)
+			n1 := ExpectScheduled(ctx, env.Client, affPod1)
+			n2 := ExpectScheduled(ctx, env.Client, affPod2)
+			// should be scheduled on the same node since the namespace labels match the selector
+			Expect(n1.Name).To(Equal(n2.Name))
+		})
+		It("should filter pod affinity topologies by namespace, namespace selector combined with namespace list", func() {
+			topology := []corev1.TopologySpreadConstraint{{
+				TopologyKey:       corev1.LabelHostname,
+				WhenUnsatisfiable: corev1.DoNotSchedule,
+				LabelSelector:     &metav1.LabelSelector{MatchLabels: labels},
+				MaxSkew:           1,
+			}}
+
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "listed-ns", Labels: map[string]string{"team": "other"}}}
+			ExpectApplied(ctx, env.Client, ns)
+			ExpectReconcileSucceeded(ctx, namespaceStateController, client.ObjectKeyFromObject(ns))
+			affLabels := map[string]string{"security": "s2"}
+
+			affPod1 := test.UnschedulablePod(test.PodOptions{ObjectMeta: metav1.ObjectMeta{Labels: affLabels, Namespace: "listed-ns"}})
+			// affPod2 will try to get scheduled with affPod1
+			affPod2 := test.UnschedulablePod(test.PodOptions{PodRequirements: []corev1.PodAffinityTerm{{
+				LabelSelector: &metav1.LabelSelector{
+					MatchLabels: affLabels,
+				},
+				// the selector doesn't match, but the namespace is listed explicitly
+				Namespaces:        []string{"listed-ns"},
+				NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "security"}},
+				TopologyKey:       corev1.LabelHostname,
+			}}})
+
+			var pods []*corev1.Pod
+			// create 10 nodes
+			pods = append(pods, test.UnschedulablePods(test.PodOptions{
+				ObjectMeta:                metav1.ObjectMeta{Labels: labels},
+				TopologySpreadConstraints: topology,
+			}, 10)...)
+			pods = append(pods, affPod1)
+			pods = append(pods, affPod2)
+
+			ExpectApplied(ctx, env.Client, nodePool)
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pods...)
+			n1 := ExpectScheduled(ctx, env.Client, affPod1)
+			n2 := ExpectScheduled(ctx, env.Client, affPod2)
+			Expect(n1.Name).To(Equal(n2.Name))
+		})
+		It("should filter pod anti-affinity topologies by the union of the namespace list and the namespace selector", func() {
+			listed := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "union-listed-ns", Labels: map[string]string{"team": "other"}}}
+			selected := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "union-selected-ns", Labels: map[string]string{"team": "security"}}}
+			ExpectApplied(ctx, env.Client, listed, selected)
+			ExpectReconcileSucceeded(ctx, namespaceStateController, client.ObjectKeyFromObject(listed))
+			ExpectReconcileSucceeded(ctx, namespaceStateController, client.ObjectKeyFromObject(selected))
+			affLabels := map[string]string{"security": "s2"}
+
+			listedPod := test.UnschedulablePod(test.PodOptions{ObjectMeta: metav1.ObjectMeta{Labels: affLabels, Namespace: listed.Name}})
+			selectedPod := test.UnschedulablePod(test.PodOptions{ObjectMeta: metav1.ObjectMeta{Labels: affLabels, Namespace: selected.Name}})
+			ExpectApplied(ctx, env.Client, nodePool)
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, listedPod, selectedPod)
+			listedNode := ExpectScheduled(ctx, env.Client, listedPod)
+			selectedNode := ExpectScheduled(ctx, env.Client, selectedPod)
+
+			// the term applies to pods in either namespace, so the pod can't schedule to either node
+			antiAffPod := test.UnschedulablePod(test.PodOptions{PodAntiRequirements: []corev1.PodAffinityTerm{{
+				LabelSelector:     &metav1.LabelSelector{MatchLabels: affLabels},
+				Namespaces:        []string{listed.Name},
+				NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "security"}},
+				TopologyKey:       corev1.LabelHostname,
+			}}})
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, antiAffPod)
+			node := ExpectScheduled(ctx, env.Client, antiAffPod)
+			Expect(node.Name).ToNot(Equal(listedNode.Name))
+			Expect(node.Name).ToNot(Equal(selectedNode.Name))
+		})
 	})
 })
 
@@ -2500,3 +2816,153 @@ var _ = Describe("Taints", func() {
 		Expect(node.Spec.Taints).To(HaveLen(1)) // Expect no taints generated beyond the default
 	})
 })
+
+var _ = Describe("TopologyIndex", func() {
+	var index *scheduling.TopologyIndex
+	BeforeEach(func() {
+		index = scheduling.NewTopologyIndex()
+	})
+	It("should return groups whose match labels select the pod", func() {
+		index.Add(1, &metav1.LabelSelector{MatchLabels: map[string]string{"app": "foo", "tier": "web"}})
+		index.Add(2, &metav1.LabelSelector{MatchLabels: map[string]string{"app": "bar"}})
+		Expect(index.Candidates(test.Pod(test.PodOptions{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "foo", "tier": "web"}}}))).To(ConsistOf(uint64(1)))
+		Expect(index.Candidates(test.Pod(test.PodOptions{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "bar"}}}))).To(ConsistOf(uint64(2)))
+		Expect(index.Candidates(test.Pod(test.PodOptions{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "baz"}}}))).To(BeEmpty())
+	})
+	It("should return groups whose In expressions select the pod", func() {
+		index.Add(1, &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "app", Operator: metav1.LabelSelectorOpIn, Values: []string{"foo", "bar"}}}})
+		Expect(index.Candidates(test.Pod(test.PodOptions{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "foo"}}}))).To(ConsistOf(uint64(1)))
+		Expect(index.Candidates(test.Pod(test.PodOptions{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "bar"}}}))).To(ConsistOf(uint64(1)))
+		Expect(index.Candidates(test.Pod(test.PodOptions{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "baz"}}}))).To(BeEmpty())
+	})
+	It("should always return groups whose selectors don't require a label value", func() {
+		index.Add(1, &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "app", Operator: metav1.LabelSelectorOpExists}}})
+		index.Add(2, &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "app", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"foo"}}}})
+		index.Add(3, nil)
+		Expect(index.Candidates(test.Pod())).To(ConsistOf(uint64(1), uint64(2), uint64(3)))
+	})
+})
+
+var _ = Describe("TopologyGroup", func() {
+	hash := func(selector *metav1.LabelSelector) uint64 {
+		return scheduling.NewTopologyGroup(scheduling.TopologyTypePodAntiAffinity, corev1.LabelHostname, test.Pod(), sets.New("default"), selector, math.MaxInt32, nil, nil, nil).Hash()
+	}
+	It("should share groups across selectors that select the same pods", func() {
+		Expect(hash(&metav1.LabelSelector{MatchLabels: map[string]string{"app": "foo"}})).
+			To(Equal(hash(&metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "app", Operator: metav1.LabelSelectorOpIn, Values: []string{"foo"}}}})))
+		Expect(hash(&metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+			{Key: "app", Operator: metav1.LabelSelectorOpIn, Values: []string{"foo", "bar"}},
+			{Key: "tier", Operator: metav1.LabelSelectorOpExists},
+		}})).To(Equal(hash(&metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+			{Key: "tier", Operator: metav1.LabelSelectorOpExists},
+			{Key: "app", Operator: metav1.LabelSelectorOpIn, Values: []string{"bar", "foo"}},
+		}})))
+	})
+	It("should not share groups across selectors that select different pods", func() {
+		Expect(hash(&metav1.LabelSelector{MatchLabels: map[string]string{"app": "foo"}})).
+			ToNot(Equal(hash(&metav1.LabelSelector{MatchLabels: map[string]string{"app": "bar"}})))
+		Expect(hash(&metav1.LabelSelector{MatchLabels: map[string]string{"app": "foo"}})).
+			ToNot(Equal(hash(&metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "app", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"foo"}}}})))
+		// a nil selector selects nothing, while an empty selector selects everything
+		Expect(hash(nil)).ToNot(Equal(hash(&metav1.LabelSelector{})))
+	})
+})
+
+// minDomainsFixture describes a topology spread constraint over zones and the zones that the kube-scheduler allows a
+// pod to schedule to, given the number of matching pods in each zone
+type minDomainsFixture struct {
+	minDomains         *int32
+	nodeAffinityPolicy *corev1.NodeInclusionPolicy
+	// podZones are the zones that the pod's node affinity allows
+	podZones []string
+	counts   map[string]int32
+	// selfSelecting is true if the pod matches the constraint's selector
+	selfSelecting bool
+	// allowed are the zones that the kube-scheduler's PodTopologySpread filter allows
+	allowed []string
+}
+
+var _ = Describe("MinDomains Conformance", func() {
+	DescribeTable("should allow the same domains as the kube-scheduler",
+		func(fixture minDomainsFixture) {
+			selector := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "foo"}}
+			pod := test.Pod()
+			if fixture.selfSelecting {
+				pod.Labels = selector.MatchLabels
+			}
+			tg := scheduling.NewTopologyGroup(scheduling.TopologyTypeSpread, corev1.LabelTopologyZone, pod, sets.New(pod.Namespace), selector, 1,
+				fixture.minDomains, fixture.nodeAffinityPolicy, sets.KeySet(fixture.counts))
+			for zone, count := range fixture.counts {
+				for range count {
+					tg.Record(zone)
+				}
+			}
+			podDomains := pscheduling.NewRequirement(corev1.LabelTopologyZone, corev1.NodeSelectorOpIn, fixture.podZones...)
+			var allowed []string
+			for _, zone := range fixture.podZones {
+				if tg.Get(pod, podDomains, pscheduling.NewRequirement(corev1.LabelTopologyZone, corev1.NodeSelectorOpIn, zone)).Has(zone) {
+					allowed = append(allowed, zone)
+				}
+			}
+			Expect(allowed).To(ConsistOf(lo.ToAnySlice(fixture.allowed)...))
+		},
+		Entry("no matching pods", minDomainsFixture{
+			podZones: []string{"a", "b", "c"}, counts: map[string]int32{"a": 0, "b": 0, "c": 0}, selfSelecting: true,
+			allowed: []string{"a", "b", "c"},
+		}),
+		Entry("skewed domains without minDomains", minDomainsFixture{
+			podZones: []string{"a", "b"}, counts: map[string]int32{"a": 1, "b": 0}, selfSelecting: true,
+			allowed: []string{"b"},
+		}),
+		Entry("fewer domains than minDomains", minDomainsFixture{
+			minDomains: lo.ToPtr(int32(3)),
+			podZones:   []string{"a", "b"}, counts: map[string]int32{"a": 3, "b": 3}, selfSelecting: true,
+			allowed: nil,
+		}),
+		Entry("as many domains as minDomains", minDomainsFixture{
+			minDomains: lo.ToPtr(int32(3)),
+			podZones:   []string{"a", "b", "c"}, counts: map[string]int32{"a": 3, "b": 3, "c": 3}, selfSelecting: true,
+			allowed: []string{"a", "b", "c"},
+		}),
+		Entry("skewed domains with minDomains satisfied", minDomainsFixture{
+			minDomains: lo.ToPtr(int32(2)),
+			podZones:   []string{"a", "b"}, counts: map[string]int32{"a": 2, "b": 1}, selfSelecting: true,
+			allowed: []string{"b"},
+		}),
+		Entry("pod that doesn't match its own selector", minDomainsFixture{
+			minDomains: lo.ToPtr(int32(3)),
+			podZones:   []string{"a", "b"}, counts: map[string]int32{"a": 1, "b": 0}, selfSelecting: false,
+			allowed: []string{"a", "b"},
+		}),
+		Entry("domains excluded by node affinity don't count towards the global minimum", minDomainsFixture{
+			nodeAffinityPolicy: lo.ToPtr(corev1.NodeInclusionPolicyHonor),
+			podZones:           []string{"a", "b"}, counts: map[string]int32{"a": 2, "b": 2, "c": 0}, selfSelecting: true,
+			allowed: []string{"a", "b"},
+		}),
+		Entry("domains excluded by node affinity count towards the global minimum when ignoring node affinity", minDomainsFixture{
+			nodeAffinityPolicy: lo.ToPtr(corev1.NodeInclusionPolicyIgnore),
+			podZones:           []string{"a", "b"}, counts: map[string]int32{"a": 2, "b": 2, "c": 0}, selfSelecting: true,
+			allowed: nil,
+		}),
+		Entry("domains excluded by node affinity don't count towards minDomains", minDomainsFixture{
+			minDomains: lo.ToPtr(int32(3)),
+			podZones:   []string{"a", "b"}, counts: map[string]int32{"a": 1, "b": 1, "c": 5}, selfSelecting: true,
+			allowed: nil,
+		}),
+		Entry("domains excluded by node affinity count towards minDomains when ignoring node affinity", minDomainsFixture{
+			minDomains:         lo.ToPtr(int32(3)),
+			nodeAffinityPolicy: lo.ToPtr(corev1.NodeInclusionPolicyIgnore),
+			podZones:           []string{"a", "b"}, counts: map[string]int32{"a": 1, "b": 1, "c": 1}, selfSelecting: true,
+			allowed: []string{"a", "b"},
+		}),
+	)
+	It("should not share groups across constraints with different minDomains or node affinity policies", func() {
+		hash := func(minDomains *int32, nodeAffinityPolicy *corev1.NodeInclusionPolicy) uint64 {
+			return scheduling.NewTopologyGroup(scheduling.TopologyTypeSpread, corev1.LabelTopologyZone, test.Pod(), sets.New("default"), &metav1.LabelSelector{},
+				1, minDomains, nodeAffinityPolicy, nil).Hash()
+		}
+		Expect(hash(nil, nil)).ToNot(Equal(hash(lo.ToPtr(int32(2)), nil)))
+		Expect(hash(nil, nil)).ToNot(Equal(hash(nil, lo.ToPtr(corev1.NodeInclusionPolicyIgnore))))
+		Expect(hash(nil, nil)).To(Equal(hash(nil, lo.ToPtr(corev1.NodeInclusionPolicyHonor))))
+	})
+})
@@ -19,6 +19,8 @@ package scheduling
 import (
 	"fmt"
 	"math"
+	"sort"
+	"strings"
 
 	"github.com/awslabs/operatorpkg/option"
 	"github.com/mitchellh/hashstructure/v2"
@@ -26,6 +28,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 
@@ -63,20 +66,25 @@ type TopologyGroup struct {
 	selector    labels.Selector
 	rawSelector *metav1.LabelSelector
 	nodeFilter  TopologyNodeFilter
+	// ignoreNodeAffinity is true for topology spread constraints with a node affinity policy of Ignore, whose domains
+	// and pod counts aren't filtered by the pod's node selector and required node affinity
+	ignoreNodeAffinity bool
 	// Index
 	owners       map[types.UID]struct{} // Pods that have this topology as a scheduling rule
 	domains      map[string]int32       // TODO(ellistarn) explore replacing with a minheap
 	emptyDomains sets.Set[string]       // domains for which we know that no pod exists
 }
 
-func NewTopologyGroup(topologyType TopologyType, topologyKey string, pod *v1.Pod, namespaces sets.Set[string], labelSelector *metav1.LabelSelector, maxSkew int32, minDomains *int32, domains sets.Set[string]) *TopologyGroup {
+func NewTopologyGroup(topologyType TopologyType, topologyKey string, pod *v1.Pod, namespaces sets.Set[string], labelSelector *metav1.LabelSelector, maxSkew int32, minDomains *int32, nodeAffinityPolicy *v1.NodeInclusionPolicy, domains sets.Set[string]) *TopologyGroup {
 	domainCounts := map[string]int32{}
 	for domain := range domains {
 		domainCounts[domain] = 0
 	}
-	// the nil *TopologyNodeFilter always passes which is what we need for affinity/anti-affinity
+	// the nil *TopologyNodeFilter always passes which is what we need for affinity/anti-affinity, and for topology spread
+	// constraints that ignore the pod's node affinity
 	var nodeSelector TopologyNodeFilter
-	if topologyType == TopologyTypeSpread {
+	ignoreNodeAffinity := topologyType == TopologyTypeSpread && lo.FromPtr(nodeAffinityPolicy) == v1.NodeInclusionPolicyIgnore
+	if topologyType == TopologyTypeSpread && !ignoreNodeAffinity {
 		nodeSelector = MakeTopologyNodeFilter(pod)
 	}
 	selector, err := metav1.LabelSelectorAsSelector(labelSelector)
@@ -84,17 +92,18 @@ func NewTopologyGroup(topologyType TopologyType, topologyKey string, pod *v1.Pod
 		selector = labels.Nothing()
 	}
 	return &TopologyGroup{
-		Type:         topologyType,
-		Key:          topologyKey,
-		namespaces:   namespaces,
-		selector:     selector,
-		rawSelector:  labelSelector,
-		nodeFilter:   nodeSelector,
-		maxSkew:      maxSkew,
-		domains:      domainCounts,
-		emptyDomains: domains.Clone(),
-		owners:       map[types.UID]struct{}{},
-		minDomains:   minDomains,
+		Type:               topologyType,
+		Key:                topologyKey,
+		namespaces:         namespaces,
+		selector:           selector,
+		rawSelector:        labelSelector,
+		nodeFilter:         nodeSelector,
+		ignoreNodeAffinity: ignoreNodeAffinity,
+		maxSkew:            maxSkew,
+		domains:            domainCounts,
+		emptyDomains:       domains.Clone(),
+		owners:             map[types.UID]struct{}{},
+		minDomains:         minDomains,
 	}
 }
 
@@ -155,25 +164,55 @@ func (t *TopologyGroup) IsOwnedBy(key types.UID) bool {
 }
 
 // Hash is used so we can track single topologies that affect multiple groups of pods.  If a deployment has 100x pods
-// with self anti-affinity, we track that as a single topology with 100 owners instead of 100x topologies.
+// with self anti-affinity, we track that as a single topology with 100 owners instead of 100x topologies. The selector
+// is hashed in a canonical form, so that workloads whose selectors are written differently, but select the same pods,
+// also share a topology.
 func (t *TopologyGroup) Hash() uint64 {
 	return lo.Must(hashstructure.Hash(struct {
-		TopologyKey string
-		Type        TopologyType
-		Namespaces  sets.Set[string]
-		RawSelector *metav1.LabelSelector
-		MaxSkew     int32
-		NodeFilter  TopologyNodeFilter
+		TopologyKey        string
+		Type               TopologyType
+		Namespaces         sets.Set[string]
+		Selector           string
+		MaxSkew            int32
+		MinDomains         *int32
+		NodeFilter         TopologyNodeFilter
+		IgnoreNodeAffinity bool
 	}{
-		TopologyKey: t.Key,
-		Type:        t.Type,
-		Namespaces:  t.namespaces,
-		RawSelector: t.rawSelector,
-		MaxSkew:     t.maxSkew,
-		NodeFilter:  t.nodeFilter,
+		TopologyKey:        t.Key,
+		Type:               t.Type,
+		Namespaces:         t.namespaces,
+		Selector:           canonicalSelector(t.selector),
+		MaxSkew:            t.maxSkew,
+		MinDomains:         t.minDomains,
+		NodeFilter:         t.nodeFilter,
+		IgnoreNodeAffinity: t.ignoreNodeAffinity,
 	}, hashstructure.FormatV2, &hashstructure.HashOptions{SlicesAsSets: true}))
 }
 
+// canonicalSelector returns a string that is the same for selectors that select the same pods, regardless of the order
+// of their expressions and values, or whether a label value is matched with match labels or a single valued In
+// expression. Selectors that select nothing, e.g. because they're invalid, are distinguished from empty selectors that
+// select everything.
+func canonicalSelector(selector labels.Selector) string {
+	requirements, selectable := selector.Requirements()
+	if !selectable {
+		return "<none>"
+	}
+	terms := make([]string, 0, len(requirements))
+	for _, requirement := range requirements {
+		operator := requirement.Operator()
+		switch operator {
+		case selection.Equals, selection.DoubleEquals:
+			operator = selection.In
+		case selection.NotEquals:
+			operator = selection.NotIn
+		}
+		terms = append(terms, fmt.Sprintf("%s %s %v", requirement.Key(), operator, requirement.Values().List()))
+	}
+	sort.Strings(terms)
+	return strings.Join(terms, ",")
+}
+
 // nextDomainTopologySpread returns a scheduling.Requirement that includes a node domain that a pod should be scheduled to.
 // If there are multiple eligible domains, we return any random domain that satisfies the `maxSkew` configuration.
 // If there are no eligible domains, we return a `DoesNotExist` requirement, implying that we could not satisfy the topologySpread requirement.
@@ -226,6 +265,10 @@ func (t *TopologyGroup) nextDomainTopologySpread(pod *v1.Pod, podDomains, nodeDo
 	return scheduling.NewRequirement(podDomains.Key, v1.NodeSelectorOpIn, minDomain)
 }
 
+// domainMinCount returns the global minimum of the skew calculation, in the same way as the kube-scheduler. The minimum
+// is taken over the eligible domains, which are the domains that the pod's node affinity allows unless the constraint's
+// node affinity policy is Ignore. If there are fewer eligible domains than the constraint's minDomains, the minimum is
+// zero, as the domains that don't exist yet would be empty.
 func (t *TopologyGroup) domainMinCount(domains *scheduling.Requirement) int32 {
 	// hostname based topologies always have a min pod count of zero since we can create one
 	if t.Key == v1.LabelHostname {
@@ -236,7 +279,7 @@ func (t *TopologyGroup) domainMinCount(domains *scheduling.Requirement) int32 {
 	var numPodSupportedDomains int32
 	// determine our current min count
 	for domain, count := range t.domains {
-		if domains.Has(domain) {
+		if t.ignoreNodeAffinity || domains.Has(domain) {
 			numPodSupportedDomains++
 			if count < min {
 				min = count
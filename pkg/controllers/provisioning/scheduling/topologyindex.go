@@ -0,0 +1,99 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduling
+
+import (
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// TopologyIndex is an inverted index from the labels required by topology group selectors to the hashes of those
+// topology groups. It allows finding the topology groups that could select a pod by looking up the pod's labels
+// instead of evaluating the selector of every topology group. This matters for workloads with many distinct
+// anti-affinity terms, as every pod needs to be checked against every inverse anti-affinity for each node that it's
+// considered for.
+type TopologyIndex struct {
+	// label key -> label value -> topology group hashes whose selector requires that label value
+	byLabel map[string]map[string]sets.Set[uint64]
+	// unindexed are the topology group hashes whose selectors don't require any particular label value, e.g. selectors
+	// that only use Exists or NotIn expressions. These groups are candidates for every pod.
+	unindexed sets.Set[uint64]
+}
+
+func NewTopologyIndex() *TopologyIndex {
+	return &TopologyIndex{
+		byLabel:   map[string]map[string]sets.Set[uint64]{},
+		unindexed: sets.New[uint64](),
+	}
+}
+
+// Add indexes the topology group hash by a single label key that its selector requires. Each group is only indexed
+// under one label key, so a pod matches at most one entry per group and no de-duplication is needed on lookup.
+func (i *TopologyIndex) Add(hash uint64, selector *metav1.LabelSelector) {
+	key, values, ok := indexableRequirement(selector)
+	if !ok {
+		i.unindexed.Insert(hash)
+		return
+	}
+	if _, ok := i.byLabel[key]; !ok {
+		i.byLabel[key] = map[string]sets.Set[uint64]{}
+	}
+	for _, value := range values {
+		if _, ok := i.byLabel[key][value]; !ok {
+			i.byLabel[key][value] = sets.New[uint64]()
+		}
+		i.byLabel[key][value].Insert(hash)
+	}
+}
+
+// Candidates returns the hashes of the topology groups that could select the pod. The selectors of the returned groups
+// still need to be evaluated as only a single requirement of each selector is indexed.
+func (i *TopologyIndex) Candidates(pod *v1.Pod) []uint64 {
+	candidates := i.unindexed.UnsortedList()
+	for key, value := range pod.Labels {
+		if hashes, ok := i.byLabel[key][value]; ok {
+			candidates = append(candidates, hashes.UnsortedList()...)
+		}
+	}
+	return candidates
+}
+
+// indexableRequirement returns a label key and the values of that key that a pod must have to be selected by the
+// selector. The match labels are preferred over In expressions as they only have a single value. Keys are sorted so
+// that the same selector is always indexed the same way.
+func indexableRequirement(selector *metav1.LabelSelector) (string, []string, bool) {
+	if selector == nil {
+		return "", nil, false
+	}
+	if len(selector.MatchLabels) > 0 {
+		keys := make([]string, 0, len(selector.MatchLabels))
+		for key := range selector.MatchLabels {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		return keys[0], []string{selector.MatchLabels[keys[0]]}, true
+	}
+	for _, expression := range selector.MatchExpressions {
+		if expression.Operator == metav1.LabelSelectorOpIn && len(expression.Values) > 0 {
+			return expression.Key, expression.Values, true
+		}
+	}
+	return "", nil, false
+}
@@ -19,11 +19,13 @@ package scheduling
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/samber/lo"
 	v1 "k8s.io/api/core/v1"
 	storagev1 "k8s.io/api/storage/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -143,10 +145,51 @@ func (v *VolumeTopology) getPersistentVolumeRequirements(ctx context.Context, po
 				return n.Key == v1.LabelHostname
 			})
 		}
+		// Bound zonal volumes can only be attached in their zone, so we translate the zone constraint into a hard
+		// requirement on the well known zone label. Otherwise, replacement capacity for a pod without its own zone
+		// selector could be launched in a zone the volume can't follow it to.
+		if zoneRequirement, ok := getZoneRequirement(pv.Spec.NodeAffinity.Required.NodeSelectorTerms); ok {
+			requirements = append(lo.Reject(requirements, func(n v1.NodeSelectorRequirement, _ int) bool {
+				return isZoneKey(n.Key) && n.Operator == v1.NodeSelectorOpIn
+			}), zoneRequirement)
+		}
 	}
 	return requirements, nil
 }
 
+// getZoneRequirement returns the union of the zones allowed by each of the volume's node selector terms. If any term
+// doesn't constrain the zone, the volume isn't zonal and no requirement is returned.
+func getZoneRequirement(terms []v1.NodeSelectorTerm) (v1.NodeSelectorRequirement, bool) {
+	zones := sets.New[string]()
+	for _, term := range terms {
+		var termZones sets.Set[string]
+		for _, expr := range term.MatchExpressions {
+			if !isZoneKey(expr.Key) || expr.Operator != v1.NodeSelectorOpIn {
+				continue
+			}
+			if termZones == nil {
+				termZones = sets.New(expr.Values...)
+			} else {
+				termZones = termZones.Intersection(sets.New(expr.Values...))
+			}
+		}
+		if termZones == nil {
+			return v1.NodeSelectorRequirement{}, false
+		}
+		zones = zones.Union(termZones)
+	}
+	return v1.NodeSelectorRequirement{Key: v1.LabelTopologyZone, Operator: v1.NodeSelectorOpIn, Values: sets.List(zones)}, true
+}
+
+// isZoneKey returns true if the key identifies a zone, either via the well known (or deprecated beta) zone labels or
+// via a CSI driver specific topology key (e.g. topology.ebs.csi.aws.com/zone).
+func isZoneKey(key string) bool {
+	if key == v1.LabelTopologyZone || key == v1.LabelFailureDomainBetaZone {
+		return true
+	}
+	return strings.HasPrefix(key, "topology.") && strings.HasSuffix(key, "/zone")
+}
+
 // ValidatePersistentVolumeClaims returns an error if the pod doesn't appear to be valid with respect to
 // PVCs (e.g. the PVC is not found or references an unknown storage class).
 func (v *VolumeTopology) ValidatePersistentVolumeClaims(ctx context.Context, pod *v1.Pod) error {
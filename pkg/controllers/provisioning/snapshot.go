@@ -0,0 +1,54 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provisioning
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+	scheduler "sigs.k8s.io/karpenter/pkg/controllers/provisioning/scheduling"
+)
+
+// SchedulingSnapshotName is the name of the SchedulingSnapshot that the results of the latest provisioning loop are
+// recorded in when scheduling snapshots are enabled
+const SchedulingSnapshotName = "provisioner"
+
+// recordSchedulingSnapshot replaces the SchedulingSnapshot with the results of the scheduling loop
+func (p *Provisioner) recordSchedulingSnapshot(ctx context.Context, results scheduler.Results) error {
+	spec := results.Snapshot(p.clock.Now())
+	snapshot := &v1.SchedulingSnapshot{}
+	if err := p.kubeClient.Get(ctx, types.NamespacedName{Name: SchedulingSnapshotName}, snapshot); err != nil {
+		if !errors.IsNotFound(err) {
+			return fmt.Errorf("getting scheduling snapshot, %w", err)
+		}
+		snapshot = &v1.SchedulingSnapshot{ObjectMeta: metav1.ObjectMeta{Name: SchedulingSnapshotName}, Spec: spec}
+		if err = p.kubeClient.Create(ctx, snapshot); err != nil {
+			return fmt.Errorf("creating scheduling snapshot, %w", err)
+		}
+		return nil
+	}
+	snapshot.Spec = spec
+	if err := p.kubeClient.Update(ctx, snapshot); err != nil {
+		return fmt.Errorf("updating scheduling snapshot, %w", err)
+	}
+	return nil
+}
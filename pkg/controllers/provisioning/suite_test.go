@@ -18,7 +18,10 @@ package provisioning_test
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"sync"
 	"testing"
 	"time"
@@ -28,8 +31,10 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/samber/lo"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -224,6 +229,22 @@ var _ = Describe("Provisioning", func() {
 		Expect(len(nodes.Items)).To(Equal(0))
 		ExpectNotScheduled(ctx, env.Client, pod)
 	})
+	It("should ignore static NodePools", func() {
+		nodePool := test.NodePool(v1.NodePool{Spec: v1.NodePoolSpec{Replicas: lo.ToPtr[int64](1)}})
+		ExpectApplied(ctx, env.Client, nodePool)
+		pod := test.UnschedulablePod()
+		ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+		Expect(ExpectNodeClaims(ctx, env.Client)).To(HaveLen(0))
+		ExpectNotScheduled(ctx, env.Client, pod)
+	})
+	It("should ignore paused NodePools", func() {
+		nodePool := test.NodePool(v1.NodePool{Spec: v1.NodePoolSpec{Paused: true}})
+		ExpectApplied(ctx, env.Client, nodePool)
+		pod := test.UnschedulablePod()
+		ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+		Expect(ExpectNodeClaims(ctx, env.Client)).To(HaveLen(0))
+		ExpectNotScheduled(ctx, env.Client, pod)
+	})
 	It("should provision nodes for pods with supported node selectors", func() {
 		nodePool := test.NodePool()
 		schedulable := []*corev1.Pod{
@@ -673,6 +694,199 @@ var _ = Describe("Provisioning", func() {
 			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
 			ExpectNotScheduled(ctx, env.Client, pod)
 		})
+		It("should not launch more nodes than the node limit", func() {
+			ExpectApplied(ctx, env.Client, test.NodePool(v1.NodePool{
+				Spec: v1.NodePoolSpec{
+					Limits: v1.Limits(corev1.ResourceList{v1.ResourceNodes: resource.MustParse("2")}),
+				},
+			}))
+			// prevent these pods from scheduling on the same node
+			opts := test.PodOptions{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "foo"}},
+				PodAntiRequirements: []corev1.PodAffinityTerm{{
+					TopologyKey:   corev1.LabelHostname,
+					LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "foo"}},
+				}},
+			}
+			pods := test.UnschedulablePods(opts, 3)
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pods...)
+			Expect(ExpectNodeClaims(ctx, env.Client)).To(HaveLen(2))
+			Expect(lo.CountBy(pods, func(p *corev1.Pod) bool {
+				return ExpectPodExists(ctx, env.Client, p.Name, p.Namespace).Spec.NodeName == ""
+			})).To(Equal(1))
+		})
+		It("should launch nodes into other zones once a zone reaches its domain limit", func() {
+			ExpectApplied(ctx, env.Client, test.NodePool(v1.NodePool{
+				Spec: v1.NodePoolSpec{
+					DomainLimits: map[string]v1.Limits{
+						corev1.LabelTopologyZone: v1.Limits(corev1.ResourceList{v1.ResourceNodes: resource.MustParse("1")}),
+					},
+				},
+			}))
+			// prevent these pods from scheduling on the same node
+			opts := test.PodOptions{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "foo"}},
+				PodAntiRequirements: []corev1.PodAffinityTerm{{
+					TopologyKey:   corev1.LabelHostname,
+					LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "foo"}},
+				}},
+			}
+			pods := test.UnschedulablePods(opts, 4)
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pods...)
+			nodeClaims := ExpectNodeClaims(ctx, env.Client)
+			Expect(nodeClaims).To(HaveLen(3))
+			zones := sets.New[string]()
+			for _, nodeClaim := range nodeClaims {
+				zones.Insert(nodeClaim.Labels[corev1.LabelTopologyZone])
+			}
+			Expect(sets.List(zones)).To(ConsistOf("test-zone-1", "test-zone-2", "test-zone-3"))
+			Expect(lo.CountBy(pods, func(p *corev1.Pod) bool {
+				return ExpectPodExists(ctx, env.Client, p.Name, p.Namespace).Spec.NodeName == ""
+			})).To(Equal(1))
+		})
+		It("should spread pods across the zones that their domain limits pick", func() {
+			ExpectApplied(ctx, env.Client, test.NodePool(v1.NodePool{
+				Spec: v1.NodePoolSpec{
+					DomainLimits: map[string]v1.Limits{
+						corev1.LabelTopologyZone: v1.Limits(corev1.ResourceList{v1.ResourceNodes: resource.MustParse("1")}),
+					},
+				},
+			}))
+			pods := test.UnschedulablePods(test.PodOptions{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "foo"}},
+				TopologySpreadConstraints: []corev1.TopologySpreadConstraint{{
+					TopologyKey:       corev1.LabelTopologyZone,
+					MaxSkew:           1,
+					WhenUnsatisfiable: corev1.DoNotSchedule,
+					LabelSelector:     &metav1.LabelSelector{MatchLabels: map[string]string{"app": "foo"}},
+				}},
+			}, 3)
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pods...)
+			zones := sets.New[string]()
+			for _, pod := range pods {
+				zones.Insert(ExpectScheduled(ctx, env.Client, pod).Labels[corev1.LabelTopologyZone])
+			}
+			Expect(sets.List(zones)).To(ConsistOf("test-zone-1", "test-zone-2", "test-zone-3"))
+		})
+		It("should count the existing nodes of a zone against its domain limit", func() {
+			nodePool := test.NodePool(v1.NodePool{
+				Spec: v1.NodePoolSpec{
+					DomainLimits: map[string]v1.Limits{
+						corev1.LabelTopologyZone: v1.Limits(corev1.ResourceList{v1.ResourceNodes: resource.MustParse("1")}),
+					},
+				},
+			})
+			node := test.Node(test.NodeOptions{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+				v1.NodePoolLabelKey:      nodePool.Name,
+				corev1.LabelTopologyZone: "test-zone-1",
+			}}, Taints: []corev1.Taint{{Key: "test", Value: "test", Effect: corev1.TaintEffectNoSchedule}}})
+			ExpectApplied(ctx, env.Client, nodePool, node)
+			ExpectMakeNodesInitialized(ctx, env.Client, node)
+			ExpectReconcileSucceeded(ctx, nodeController, client.ObjectKeyFromObject(node))
+			pod := test.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			Expect(ExpectScheduled(ctx, env.Client, pod).Labels[corev1.LabelTopologyZone]).ToNot(Equal("test-zone-1"))
+		})
+		It("should launch nodes of other capacity types once a capacity type reaches its limits", func() {
+			ExpectApplied(ctx, env.Client, test.NodePool(v1.NodePool{
+				Spec: v1.NodePoolSpec{
+					CapacityTypeLimits: map[string]v1.Limits{
+						v1.CapacityTypeOnDemand: v1.Limits(corev1.ResourceList{v1.ResourceNodes: resource.MustParse("1")}),
+					},
+				},
+			}))
+			// prevent these pods from scheduling on the same node
+			opts := test.PodOptions{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "foo"}},
+				PodAntiRequirements: []corev1.PodAffinityTerm{{
+					TopologyKey:   corev1.LabelHostname,
+					LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "foo"}},
+				}},
+			}
+			pods := test.UnschedulablePods(opts, 3)
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pods...)
+			nodeClaims := ExpectNodeClaims(ctx, env.Client)
+			Expect(nodeClaims).To(HaveLen(3))
+			Expect(lo.CountBy(nodeClaims, func(nc *v1.NodeClaim) bool {
+				return nc.Labels[v1.CapacityTypeLabelKey] == v1.CapacityTypeOnDemand
+			})).To(BeNumerically("<=", 1))
+			for _, pod := range pods {
+				ExpectScheduled(ctx, env.Client, pod)
+			}
+		})
+		It("should keep every capacity type with room in the requirements of nodeclaims", func() {
+			ExpectApplied(ctx, env.Client, test.NodePool(v1.NodePool{
+				Spec: v1.NodePoolSpec{
+					CapacityTypeLimits: map[string]v1.Limits{
+						v1.CapacityTypeOnDemand: v1.Limits(corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1000")}),
+					},
+				},
+			}))
+			pod := test.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			nodeClaims := ExpectNodeClaims(ctx, env.Client)
+			Expect(nodeClaims).To(HaveLen(1))
+			capacityTypes := scheduling.NewNodeSelectorRequirementsWithMinValues(nodeClaims[0].Spec.Requirements...).Get(v1.CapacityTypeLabelKey)
+			Expect(capacityTypes.Has(v1.CapacityTypeOnDemand)).To(BeTrue())
+			Expect(capacityTypes.Has(v1.CapacityTypeSpot)).To(BeTrue())
+		})
+		It("should not launch instance types whose offerings of a limited capacity type exceed its limits", func() {
+			ExpectApplied(ctx, env.Client, test.NodePool(v1.NodePool{
+				Spec: v1.NodePoolSpec{
+					CapacityTypeLimits: map[string]v1.Limits{
+						v1.CapacityTypeOnDemand: v1.Limits(corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")}),
+					},
+				},
+			}))
+			pod := test.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			nodeClaims := ExpectNodeClaims(ctx, env.Client)
+			Expect(nodeClaims).To(HaveLen(1))
+			requirements := scheduling.NewNodeSelectorRequirementsWithMinValues(nodeClaims[0].Spec.Requirements...)
+			onDemand := scheduling.NewLabelRequirements(map[string]string{v1.CapacityTypeLabelKey: v1.CapacityTypeOnDemand})
+			instanceTypes, err := cloudProvider.GetInstanceTypes(ctx, nil)
+			Expect(err).ToNot(HaveOccurred())
+			for _, it := range instanceTypes {
+				if requirements.Get(corev1.LabelInstanceTypeStable).Has(it.Name) && requirements.Get(v1.CapacityTypeLabelKey).Has(v1.CapacityTypeOnDemand) &&
+					it.Offerings.Available().HasCompatible(onDemand) {
+					Expect(it.Capacity.Cpu().Cmp(resource.MustParse("4"))).To(BeNumerically("<=", 0))
+				}
+			}
+		})
+		It("should keep instance types whose spot offerings fit even if their on-demand offerings exceed the on-demand limits", func() {
+			ExpectApplied(ctx, env.Client, test.NodePool(v1.NodePool{
+				Spec: v1.NodePoolSpec{
+					CapacityTypeLimits: map[string]v1.Limits{
+						v1.CapacityTypeOnDemand: v1.Limits(corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")}),
+					},
+				},
+			}))
+			pod := test.UnschedulablePod(test.PodOptions{
+				ResourceRequirements: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("10")}},
+			})
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			nodeClaims := ExpectNodeClaims(ctx, env.Client)
+			Expect(nodeClaims).To(HaveLen(1))
+			capacityTypes := scheduling.NewNodeSelectorRequirementsWithMinValues(nodeClaims[0].Spec.Requirements...).Get(v1.CapacityTypeLabelKey)
+			Expect(capacityTypes.Has(v1.CapacityTypeSpot)).To(BeTrue())
+			Expect(capacityTypes.Has(v1.CapacityTypeOnDemand)).To(BeFalse())
+		})
+		It("should not schedule if the limits of every capacity type would be exceeded", func() {
+			ExpectApplied(ctx, env.Client, test.NodePool(v1.NodePool{
+				Spec: v1.NodePoolSpec{
+					CapacityTypeLimits: map[string]v1.Limits{
+						v1.CapacityTypeOnDemand: v1.Limits(corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("0")}),
+						v1.CapacityTypeSpot:     v1.Limits(corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("0")}),
+					},
+				},
+			}))
+			pod := test.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectNotScheduled(ctx, env.Client, pod)
+		})
 		It("should not schedule if limits would be exceeded (GPU)", func() {
 			ExpectApplied(ctx, env.Client, test.NodePool(v1.NodePool{
 				Spec: v1.NodePoolSpec{
@@ -740,6 +954,51 @@ var _ = Describe("Provisioning", func() {
 			Expect(*allocatable.Cpu()).To(Equal(resource.MustParse("4")))
 			Expect(*allocatable.Memory()).To(Equal(resource.MustParse("4Gi")))
 		})
+		It("should account for static pods of existing nodes of the nodepool", func() {
+			nodePool := test.NodePool()
+			ExpectApplied(ctx, env.Client, nodePool)
+			// the existing node is tainted so that the pending pod has to launch a new node
+			node := test.Node(test.NodeOptions{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+					v1.NodePoolLabelKey:            nodePool.Name,
+					corev1.LabelInstanceTypeStable: "small-instance-type",
+				}},
+				Taints:     []corev1.Taint{{Key: "foo.com/taint", Effect: corev1.TaintEffectNoSchedule}},
+				ProviderID: test.RandomProviderID(),
+			})
+			ExpectApplied(ctx, env.Client, node)
+			ExpectReconcileSucceeded(ctx, nodeController, client.ObjectKeyFromObject(node))
+			staticPod := test.UnschedulablePod(test.PodOptions{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "kube-proxy-" + node.Name,
+					OwnerReferences: []metav1.OwnerReference{{
+						APIVersion: "v1",
+						Kind:       "Node",
+						Name:       node.Name,
+						UID:        node.UID,
+						Controller: lo.ToPtr(true),
+					}},
+				},
+				ResourceRequirements: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2"), corev1.ResourceMemory: resource.MustParse("2Gi")}},
+			})
+			ExpectApplied(ctx, env.Client, staticPod)
+			ExpectManualBinding(ctx, env.Client, staticPod, node)
+			Expect(cluster.UpdatePod(ctx, ExpectExists(ctx, env.Client, staticPod))).To(Succeed())
+
+			pod := test.UnschedulablePod(
+				test.PodOptions{
+					ResourceRequirements: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1"), corev1.ResourceMemory: resource.MustParse("1Gi")}},
+				},
+			)
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			newNode := ExpectScheduled(ctx, env.Client, pod)
+			Expect(newNode.Name).ToNot(Equal(node.Name))
+
+			// the static pod runs on every node of the nodepool, so the new node has to fit it alongside the pod
+			allocatable := instanceTypeMap[newNode.Labels[corev1.LabelInstanceTypeStable]].Capacity
+			Expect(*allocatable.Cpu()).To(Equal(resource.MustParse("4")))
+			Expect(*allocatable.Memory()).To(Equal(resource.MustParse("4Gi")))
+		})
 		It("should account for daemonsets (with startup taint)", func() {
 			nodePool := test.NodePool(v1.NodePool{
 				Spec: v1.NodePoolSpec{
@@ -780,6 +1039,55 @@ var _ = Describe("Provisioning", func() {
 			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
 			ExpectNotScheduled(ctx, env.Client, pod)
 		})
+		It("should not account for daemonsets that the nodepool excludes", func() {
+			nodePool := test.NodePool(v1.NodePool{
+				Spec: v1.NodePoolSpec{
+					ExcludedDaemonSets: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "excluded"}},
+				},
+			})
+			ExpectApplied(ctx, env.Client, nodePool, test.DaemonSet(
+				test.DaemonSetOptions{PodOptions: test.PodOptions{
+					ObjectMeta:           metav1.ObjectMeta{Labels: map[string]string{"app": "excluded"}},
+					ResourceRequirements: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("10000"), corev1.ResourceMemory: resource.MustParse("10000Gi")}},
+				}},
+			))
+			pod := test.UnschedulablePod(
+				test.PodOptions{
+					ResourceRequirements: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1"), corev1.ResourceMemory: resource.MustParse("1Gi")}},
+				},
+			)
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			node := ExpectScheduled(ctx, env.Client, pod)
+
+			// the excluded daemonset doesn't fit on any instance type, so the pod only schedules if it isn't counted
+			allocatable := instanceTypeMap[node.Labels[corev1.LabelInstanceTypeStable]].Capacity
+			Expect(*allocatable.Cpu()).To(Equal(resource.MustParse("2")))
+			Expect(*allocatable.Memory()).To(Equal(resource.MustParse("2Gi")))
+		})
+		It("should account for daemonsets that the nodepool doesn't exclude", func() {
+			nodePool := test.NodePool(v1.NodePool{
+				Spec: v1.NodePoolSpec{
+					ExcludedDaemonSets: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "excluded"}},
+				},
+			})
+			ExpectApplied(ctx, env.Client, nodePool, test.DaemonSet(
+				test.DaemonSetOptions{PodOptions: test.PodOptions{
+					ObjectMeta:           metav1.ObjectMeta{Labels: map[string]string{"app": "included"}},
+					ResourceRequirements: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2"), corev1.ResourceMemory: resource.MustParse("2Gi")}},
+				}},
+			))
+			pod := test.UnschedulablePod(
+				test.PodOptions{
+					ResourceRequirements: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1"), corev1.ResourceMemory: resource.MustParse("1Gi")}},
+				},
+			)
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			node := ExpectScheduled(ctx, env.Client, pod)
+
+			allocatable := instanceTypeMap[node.Labels[corev1.LabelInstanceTypeStable]].Capacity
+			Expect(*allocatable.Cpu()).To(Equal(resource.MustParse("4")))
+			Expect(*allocatable.Memory()).To(Equal(resource.MustParse("4Gi")))
+		})
 		It("should account for overhead using daemonset pod spec instead of daemonset spec", func() {
 			nodePool := test.NodePool()
 			// Create a daemonset with large resource requests
@@ -1183,6 +1491,40 @@ var _ = Describe("Provisioning", func() {
 			Expect(*allocatable.Cpu()).To(Equal(resource.MustParse("4")))
 			Expect(*allocatable.Memory()).To(Equal(resource.MustParse("4Gi")))
 		})
+		It("should not account for daemonsets on instance types with labels that the daemonset can't schedule to", func() {
+			ExpectApplied(ctx, env.Client, test.NodePool(), test.DaemonSet(
+				test.DaemonSetOptions{PodOptions: test.PodOptions{
+					NodeRequirements:     []corev1.NodeSelectorRequirement{{Key: corev1.LabelArchStable, Operator: corev1.NodeSelectorOpIn, Values: []string{v1.ArchitectureArm64}}},
+					ResourceRequirements: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("10"), corev1.ResourceMemory: resource.MustParse("10Gi")}},
+				}},
+			))
+			pod := test.UnschedulablePod(
+				test.PodOptions{
+					NodeSelector:         map[string]string{corev1.LabelArchStable: v1.ArchitectureAmd64},
+					ResourceRequirements: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1"), corev1.ResourceMemory: resource.MustParse("1Gi")}},
+				},
+			)
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			node := ExpectScheduled(ctx, env.Client, pod)
+			Expect(node.Labels[corev1.LabelArchStable]).To(Equal(v1.ArchitectureAmd64))
+		})
+		It("should account for daemonsets on instance types with labels that the daemonset can schedule to", func() {
+			ExpectApplied(ctx, env.Client, test.NodePool(), test.DaemonSet(
+				test.DaemonSetOptions{PodOptions: test.PodOptions{
+					NodeRequirements:     []corev1.NodeSelectorRequirement{{Key: corev1.LabelArchStable, Operator: corev1.NodeSelectorOpIn, Values: []string{v1.ArchitectureArm64}}},
+					ResourceRequirements: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("10"), corev1.ResourceMemory: resource.MustParse("10Gi")}},
+				}},
+			))
+			// the arm instance type has 16 CPUs, so this pod only fits if the daemonset isn't accounted for
+			pod := test.UnschedulablePod(
+				test.PodOptions{
+					NodeSelector:         map[string]string{corev1.LabelArchStable: v1.ArchitectureArm64},
+					ResourceRequirements: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("7")}},
+				},
+			)
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectNotScheduled(ctx, env.Client, pod)
+		})
 	})
 	Context("Annotations", func() {
 		It("should annotate nodes", func() {
@@ -1353,6 +1695,21 @@ var _ = Describe("Provisioning", func() {
 			)
 			ExpectScheduled(ctx, env.Client, pod)
 		})
+		It("should create a nodeclaim with the active fallback nodeclass of the nodepool", func() {
+			nodePool := test.NodePool()
+			fallback := *nodePool.Spec.Template.Spec.NodeClassRef
+			fallback.Name = "fallback"
+			nodePool.Spec.Template.Spec.FallbackNodeClassRefs = []v1.NodeClassReference{fallback}
+			nodePool.Status.NodeClassRef = &fallback
+			ExpectApplied(ctx, env.Client, nodePool)
+			pod := test.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+
+			Expect(cloudProvider.CreateCalls).To(HaveLen(1))
+			Expect(cloudProvider.CreateCalls[0].Spec.NodeClassRef.Name).To(Equal("fallback"))
+			Expect(cloudProvider.CreateCalls[0].Labels).To(HaveKeyWithValue(v1.NodeClassLabelKey(fallback.GroupKind()), "fallback"))
+			ExpectScheduled(ctx, env.Client, pod)
+		})
 		It("should create a nodeclaim request with additional expected requirements", func() {
 			nodePool := test.NodePool(v1.NodePool{
 				Spec: v1.NodePoolSpec{
@@ -1797,6 +2154,40 @@ var _ = Describe("Provisioning", func() {
 			node := ExpectScheduled(ctx, env.Client, pod)
 			Expect(node.Labels).To(HaveKeyWithValue(corev1.LabelTopologyZone, "test-zone-3"))
 		})
+		It("should schedule to volume zones if volume already bound with a driver specific zone key", func() {
+			persistentVolume := test.PersistentVolume()
+			persistentVolume.Spec.NodeAffinity = &corev1.VolumeNodeAffinity{Required: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchExpressions: []corev1.NodeSelectorRequirement{
+				{Key: "topology.test.driver/zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"test-zone-3"}},
+			}}}}}
+			persistentVolumeClaim := test.PersistentVolumeClaim(test.PersistentVolumeClaimOptions{VolumeName: persistentVolume.Name, StorageClassName: &storageClass.Name})
+			ExpectApplied(ctx, env.Client, test.NodePool(), storageClass, persistentVolumeClaim, persistentVolume)
+			pod := test.UnschedulablePod(test.PodOptions{
+				PersistentVolumeClaims: []string{persistentVolumeClaim.Name},
+			})
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			node := ExpectScheduled(ctx, env.Client, pod)
+			Expect(node.Labels).To(HaveKeyWithValue(corev1.LabelTopologyZone, "test-zone-3"))
+		})
+		It("should schedule to volume zones from any of the volume's node selector terms", func() {
+			persistentVolume := test.PersistentVolume()
+			persistentVolume.Spec.NodeAffinity = &corev1.VolumeNodeAffinity{Required: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{
+				{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: corev1.LabelTopologyZone, Operator: corev1.NodeSelectorOpIn, Values: []string{"test-zone-1"}}}},
+				{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: corev1.LabelTopologyZone, Operator: corev1.NodeSelectorOpIn, Values: []string{"test-zone-3"}}}},
+			}}}
+			persistentVolumeClaim := test.PersistentVolumeClaim(test.PersistentVolumeClaimOptions{VolumeName: persistentVolume.Name, StorageClassName: &storageClass.Name})
+			nodePool := test.NodePool(v1.NodePool{Spec: v1.NodePoolSpec{Template: v1.NodeClaimTemplate{Spec: v1.NodeClaimTemplateSpec{
+				Requirements: []v1.NodeSelectorRequirementWithMinValues{
+					{NodeSelectorRequirement: corev1.NodeSelectorRequirement{Key: corev1.LabelTopologyZone, Operator: corev1.NodeSelectorOpIn, Values: []string{"test-zone-2", "test-zone-3"}}},
+				},
+			}}}})
+			ExpectApplied(ctx, env.Client, nodePool, storageClass, persistentVolumeClaim, persistentVolume)
+			pod := test.UnschedulablePod(test.PodOptions{
+				PersistentVolumeClaims: []string{persistentVolumeClaim.Name},
+			})
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			node := ExpectScheduled(ctx, env.Client, pod)
+			Expect(node.Labels).To(HaveKeyWithValue(corev1.LabelTopologyZone, "test-zone-3"))
+		})
 		It("should schedule to volume zones if volume already bound (ephemeral volume)", func() {
 			pod := test.UnschedulablePod(test.PodOptions{
 				EphemeralVolumeTemplates: []test.EphemeralVolumeTemplateOptions{
@@ -2130,6 +2521,690 @@ var _ = Describe("Provisioning", func() {
 			})
 		})
 	})
+	Context("Min Resources", func() {
+		It("should not select instance types with less capacity than minResources", func() {
+			ExpectApplied(ctx, env.Client, test.NodePool(v1.NodePool{Spec: v1.NodePoolSpec{
+				MinResources: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("8")},
+			}}))
+			pod := test.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			node := ExpectScheduled(ctx, env.Client, pod)
+			Expect(node.Labels[corev1.LabelInstanceTypeStable]).To(Equal("arm-instance-type"))
+		})
+		It("should not provision when no instance types satisfy minResources", func() {
+			ExpectApplied(ctx, env.Client, test.NodePool(v1.NodePool{Spec: v1.NodePoolSpec{
+				MinResources: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Ti")},
+			}}))
+			pod := test.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectNotScheduled(ctx, env.Client, pod)
+		})
+	})
+	Context("Simulated Pod Requests", func() {
+		It("should size nodes for pods without requests using the simulated pod requests", func() {
+			ExpectApplied(ctx, env.Client, test.NodePool(v1.NodePool{Spec: v1.NodePoolSpec{
+				SimulatedPodRequests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("8")},
+			}}))
+			pod := test.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			node := ExpectScheduled(ctx, env.Client, pod)
+			Expect(node.Labels[corev1.LabelInstanceTypeStable]).To(Equal("arm-instance-type"))
+			Expect(ExpectExists(ctx, env.Client, pod).Spec.Containers[0].Resources.Requests).To(BeEmpty())
+		})
+		It("should size nodes for pods with requests using their requests", func() {
+			ExpectApplied(ctx, env.Client, test.NodePool(v1.NodePool{Spec: v1.NodePoolSpec{
+				SimulatedPodRequests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("8")},
+			}}))
+			pod := test.UnschedulablePod(test.PodOptions{
+				ResourceRequirements: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")}},
+			})
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			node := ExpectScheduled(ctx, env.Client, pod)
+			Expect(node.Labels[corev1.LabelInstanceTypeStable]).ToNot(Equal("arm-instance-type"))
+		})
+	})
+	Context("Launch Rate", func() {
+		It("should not launch more nodeclaims than the launch rate allows", func() {
+			ExpectApplied(ctx, env.Client, test.NodePool(v1.NodePool{Spec: v1.NodePoolSpec{
+				Template:   v1.NodeClaimTemplate{Spec: v1.NodeClaimTemplateSpec{Kubelet: &v1.KubeletConfiguration{MaxPods: lo.ToPtr[int32](1)}}},
+				LaunchRate: &v1.LaunchRate{NodeClaimsPerMinute: 1, Burst: lo.ToPtr[int32](2)},
+			}}))
+			pods := test.UnschedulablePods(test.PodOptions{}, 3)
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pods...)
+			Expect(ExpectNodeClaims(ctx, env.Client)).To(HaveLen(2))
+			pending := lo.Filter(pods, func(p *corev1.Pod, _ int) bool { return ExpectExists(ctx, env.Client, p).Spec.NodeName == "" })
+			Expect(pending).To(HaveLen(1))
+
+			// a token is added to the bucket once a minute has passed
+			fakeClock.Step(time.Minute)
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pending...)
+			ExpectScheduled(ctx, env.Client, pending[0])
+			Expect(ExpectNodeClaims(ctx, env.Client)).To(HaveLen(3))
+		})
+		It("should default the burst to the launch rate", func() {
+			ExpectApplied(ctx, env.Client, test.NodePool(v1.NodePool{Spec: v1.NodePoolSpec{
+				Template:   v1.NodeClaimTemplate{Spec: v1.NodeClaimTemplateSpec{Kubelet: &v1.KubeletConfiguration{MaxPods: lo.ToPtr[int32](1)}}},
+				LaunchRate: &v1.LaunchRate{NodeClaimsPerMinute: 3},
+			}}))
+			pods := test.UnschedulablePods(test.PodOptions{}, 4)
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pods...)
+			Expect(ExpectNodeClaims(ctx, env.Client)).To(HaveLen(3))
+		})
+	})
+	Context("Scheduling Gates", func() {
+		It("should not provision for pods with scheduling gates", func() {
+			ExpectApplied(ctx, env.Client, test.NodePool())
+			pod := test.UnschedulablePod()
+			pod.Spec.SchedulingGates = []corev1.PodSchedulingGate{{Name: "example.com/gate"}}
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectNotScheduled(ctx, env.Client, pod)
+			ExpectMetricGaugeValue(pscheduling.SchedulingGatedPodCount, 1, nil)
+			Expect(ExpectNodeClaims(ctx, env.Client)).To(HaveLen(0))
+		})
+		It("should provision for pods once their scheduling gates are removed", func() {
+			ExpectApplied(ctx, env.Client, test.NodePool())
+			pod := test.UnschedulablePod()
+			pod.Spec.SchedulingGates = []corev1.PodSchedulingGate{{Name: "example.com/gate"}}
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectNotScheduled(ctx, env.Client, pod)
+
+			pod.Spec.SchedulingGates = nil
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			ExpectMetricGaugeValue(pscheduling.SchedulingGatedPodCount, 0, nil)
+		})
+	})
+	Context("Kubelet Configuration", func() {
+		It("should respect maxPods when packing pods onto nodes", func() {
+			ExpectApplied(ctx, env.Client, test.NodePool(v1.NodePool{Spec: v1.NodePoolSpec{
+				Template: v1.NodeClaimTemplate{Spec: v1.NodeClaimTemplateSpec{Kubelet: &v1.KubeletConfiguration{MaxPods: lo.ToPtr[int32](1)}}},
+			}}))
+			pods := test.UnschedulablePods(test.PodOptions{}, 3)
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pods...)
+			nodeNames := sets.New[string]()
+			for _, pod := range pods {
+				nodeNames.Insert(ExpectScheduled(ctx, env.Client, pod).Name)
+			}
+			Expect(nodeNames).To(HaveLen(3))
+		})
+		It("should account for kubeReserved and systemReserved when selecting instance types", func() {
+			ExpectApplied(ctx, env.Client, test.NodePool(v1.NodePool{Spec: v1.NodePoolSpec{
+				Template: v1.NodeClaimTemplate{Spec: v1.NodeClaimTemplateSpec{Kubelet: &v1.KubeletConfiguration{
+					KubeReserved:   map[string]string{string(corev1.ResourceCPU): "1"},
+					SystemReserved: map[string]string{string(corev1.ResourceCPU): "1"},
+				}}},
+			}}))
+			pod := test.UnschedulablePod(test.PodOptions{ResourceRequirements: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("3")},
+			}})
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			node := ExpectScheduled(ctx, env.Client, pod)
+			Expect(node.Labels[corev1.LabelInstanceTypeStable]).To(Equal("arm-instance-type"))
+		})
+		It("should account for percentage based evictionHard thresholds when selecting instance types", func() {
+			ExpectApplied(ctx, env.Client, test.NodePool(v1.NodePool{Spec: v1.NodePoolSpec{
+				Template: v1.NodeClaimTemplate{Spec: v1.NodeClaimTemplateSpec{Kubelet: &v1.KubeletConfiguration{
+					EvictionHard: map[string]string{"memory.available": "90%"},
+				}}},
+			}}))
+			pod := test.UnschedulablePod(test.PodOptions{ResourceRequirements: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")},
+			}})
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			node := ExpectScheduled(ctx, env.Client, pod)
+			Expect(node.Labels[corev1.LabelInstanceTypeStable]).To(Equal("arm-instance-type"))
+		})
+	})
+	Context("Headroom", func() {
+		It("should launch a nodeclaim for headroom without pending pods", func() {
+			nodePool := test.NodePool(v1.NodePool{Spec: v1.NodePoolSpec{
+				Headroom: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2"), corev1.ResourceMemory: resource.MustParse("2Gi")},
+			}})
+			ExpectApplied(ctx, env.Client, nodePool)
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov)
+			nodeClaims := ExpectNodeClaims(ctx, env.Client)
+			Expect(nodeClaims).To(HaveLen(1))
+			Expect(nodeClaims[0].Labels[v1.NodePoolLabelKey]).To(Equal(nodePool.Name))
+			Expect(nodeClaims[0].Spec.Resources.Requests.Cpu().Cmp(resource.MustParse("2"))).To(BeNumerically(">=", 0))
+		})
+		It("should not launch a nodeclaim when existing capacity holds the headroom", func() {
+			nodePool := test.NodePool(v1.NodePool{Spec: v1.NodePoolSpec{
+				Headroom: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+			}})
+			ExpectApplied(ctx, env.Client, nodePool)
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov)
+			Expect(ExpectNodeClaims(ctx, env.Client)).To(HaveLen(1))
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov)
+			Expect(ExpectNodeClaims(ctx, env.Client)).To(HaveLen(1))
+		})
+		It("should not nominate nodes that only hold headroom", func() {
+			nodePool := test.NodePool(v1.NodePool{Spec: v1.NodePoolSpec{
+				Headroom: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+			}})
+			ExpectApplied(ctx, env.Client, nodePool)
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov)
+			nodeClaims := ExpectNodeClaims(ctx, env.Client)
+			Expect(nodeClaims).To(HaveLen(1))
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov)
+			Expect(ExpectNodeClaims(ctx, env.Client)).To(HaveLen(1))
+			Expect(cluster.IsNodeNominated(nodeClaims[0].Status.ProviderID)).To(BeFalse())
+		})
+		It("should keep headroom in addition to pending pods", func() {
+			nodePool := test.NodePool(v1.NodePool{Spec: v1.NodePoolSpec{
+				Headroom: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+			}})
+			ExpectApplied(ctx, env.Client, nodePool)
+			pod := test.UnschedulablePod(test.PodOptions{ResourceRequirements: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+			}})
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			nodeClaims := ExpectNodeClaims(ctx, env.Client)
+			Expect(nodeClaims).To(HaveLen(1))
+			Expect(nodeClaims[0].Spec.Resources.Requests.Cpu().Cmp(resource.MustParse("4"))).To(BeNumerically(">=", 0))
+		})
+		It("should place the headroom pods in the system namespace", func() {
+			nodePool := test.NodePool(v1.NodePool{Spec: v1.NodePoolSpec{
+				Headroom:        corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+				MinNodesPerZone: lo.ToPtr[int32](1),
+			}})
+			pods := append(pscheduling.NewHeadroomPods(nodePool), pscheduling.NewMinNodesPerZonePods(nodePool, []string{"test-zone-1"}, nil)...)
+			Expect(pods).To(HaveLen(3))
+			for _, pod := range pods {
+				Expect(pod.Namespace).To(Equal(metav1.NamespaceSystem))
+			}
+		})
+		It("should tolerate the NodePool taints", func() {
+			nodePool := test.NodePool(v1.NodePool{Spec: v1.NodePoolSpec{
+				Headroom: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+				Template: v1.NodeClaimTemplate{
+					Spec: v1.NodeClaimTemplateSpec{
+						Taints: []corev1.Taint{{Key: "foo", Value: "bar", Effect: corev1.TaintEffectNoSchedule}},
+					},
+				},
+			}})
+			ExpectApplied(ctx, env.Client, nodePool)
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov)
+			Expect(ExpectNodeClaims(ctx, env.Client)).To(HaveLen(1))
+		})
+		It("should not keep headroom for a NodePool that is deleting", func() {
+			nodePool := test.NodePool(v1.NodePool{Spec: v1.NodePoolSpec{
+				Headroom: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+			}})
+			nodePool.Finalizers = []string{"test/finalizer"}
+			ExpectApplied(ctx, env.Client, nodePool)
+			Expect(env.Client.Delete(ctx, nodePool)).To(Succeed())
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov)
+			Expect(ExpectNodeClaims(ctx, env.Client)).To(HaveLen(0))
+			ExpectFinalizersRemoved(ctx, env.Client, nodePool)
+		})
+	})
+	Context("StatefulSet Aware Provisioning", func() {
+		var statefulSet *appsv1.StatefulSet
+		var pod *corev1.Pod
+		BeforeEach(func() {
+			ctx = options.ToContext(ctx, test.Options(test.OptionsFields{FeatureGates: test.FeatureGates{StatefulSetAwareProvisioning: lo.ToPtr(true)}}))
+			statefulSet = test.StatefulSet(test.StatefulSetOptions{Replicas: 4})
+			statefulSet.Status.Replicas = 1
+			ExpectApplied(ctx, env.Client, test.NodePool(), statefulSet)
+			pod = test.UnschedulablePod(test.PodOptions{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   statefulSet.Name + "-0",
+					Labels: statefulSet.Spec.Template.Labels,
+					OwnerReferences: []metav1.OwnerReference{{
+						APIVersion:         appsv1.SchemeGroupVersion.String(),
+						Kind:               "StatefulSet",
+						Name:               statefulSet.Name,
+						UID:                statefulSet.UID,
+						Controller:         lo.ToPtr(true),
+						BlockOwnerDeletion: lo.ToPtr(true),
+					}},
+				},
+				ResourceRequirements: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")}},
+			})
+		})
+		It("should size the nodeclaim for the replicas that the statefulset is expected to create", func() {
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			nodeClaims := ExpectNodeClaims(ctx, env.Client)
+			Expect(nodeClaims).To(HaveLen(1))
+			Expect(nodeClaims[0].Spec.Resources.Requests.Cpu().Cmp(resource.MustParse("4"))).To(BeNumerically(">=", 0))
+		})
+		It("should only provision for the pending pod if the statefulset creates its replicas in parallel", func() {
+			statefulSet.Spec.PodManagementPolicy = appsv1.ParallelPodManagement
+			ExpectApplied(ctx, env.Client, statefulSet)
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			nodeClaims := ExpectNodeClaims(ctx, env.Client)
+			Expect(nodeClaims).To(HaveLen(1))
+			Expect(nodeClaims[0].Spec.Resources.Requests.Cpu().Cmp(resource.MustParse("2"))).To(BeNumerically("<", 0))
+		})
+		It("should only provision for the pending pod if the statefulset isn't scaling out", func() {
+			statefulSet.Status.Replicas = 4
+			ExpectApplied(ctx, env.Client, statefulSet)
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			nodeClaims := ExpectNodeClaims(ctx, env.Client)
+			Expect(nodeClaims).To(HaveLen(1))
+			Expect(nodeClaims[0].Spec.Resources.Requests.Cpu().Cmp(resource.MustParse("2"))).To(BeNumerically("<", 0))
+		})
+		It("should only provision for the pending pod if the feature gate is disabled", func() {
+			ctx = options.ToContext(ctx, test.Options())
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			nodeClaims := ExpectNodeClaims(ctx, env.Client)
+			Expect(nodeClaims).To(HaveLen(1))
+			Expect(nodeClaims[0].Spec.Resources.Requests.Cpu().Cmp(resource.MustParse("2"))).To(BeNumerically("<", 0))
+		})
+	})
+	Context("Min Nodes", func() {
+		It("should launch nodeclaims without pending pods until the minimum is reached", func() {
+			nodePool := test.NodePool(v1.NodePool{Spec: v1.NodePoolSpec{MinNodes: lo.ToPtr[int32](2)}})
+			ExpectApplied(ctx, env.Client, nodePool)
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov)
+			Expect(ExpectNodeClaims(ctx, env.Client)).To(HaveLen(2))
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov)
+			Expect(ExpectNodeClaims(ctx, env.Client)).To(HaveLen(2))
+		})
+		It("should not launch more nodeclaims than the node limit to reach the minimum", func() {
+			nodePool := test.NodePool(v1.NodePool{Spec: v1.NodePoolSpec{
+				MinNodes: lo.ToPtr[int32](3),
+				Limits:   v1.Limits(corev1.ResourceList{v1.ResourceNodes: resource.MustParse("2")}),
+			}})
+			ExpectApplied(ctx, env.Client, nodePool)
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov)
+			Expect(ExpectNodeClaims(ctx, env.Client)).To(HaveLen(2))
+		})
+	})
+	Context("Min Nodes Per Zone", func() {
+		var nodePool *v1.NodePool
+		BeforeEach(func() {
+			nodePool = test.NodePool(v1.NodePool{Spec: v1.NodePoolSpec{
+				MinNodesPerZone: lo.ToPtr[int32](1),
+				Template: v1.NodeClaimTemplate{
+					Spec: v1.NodeClaimTemplateSpec{
+						Requirements: []v1.NodeSelectorRequirementWithMinValues{{
+							NodeSelectorRequirement: corev1.NodeSelectorRequirement{
+								Key:      corev1.LabelTopologyZone,
+								Operator: corev1.NodeSelectorOpIn,
+								Values:   []string{"test-zone-1"},
+							},
+						}},
+					},
+				},
+			}})
+		})
+		It("should launch a nodeclaim in each zone without pending pods", func() {
+			nodePool.Spec.Template.Spec.Requirements[0].Values = []string{"test-zone-1", "test-zone-2"}
+			ExpectApplied(ctx, env.Client, nodePool)
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov)
+			nodeClaims := ExpectNodeClaims(ctx, env.Client)
+			Expect(nodeClaims).To(HaveLen(2))
+			Expect(lo.Map(nodeClaims, func(nc *v1.NodeClaim, _ int) string {
+				return scheduling.NewNodeSelectorRequirementsWithMinValues(nc.Spec.Requirements...).Get(corev1.LabelTopologyZone).Any()
+			})).To(ConsistOf("test-zone-1", "test-zone-2"))
+		})
+		It("should launch a separate nodeclaim for each node of the minimum", func() {
+			nodePool.Spec.MinNodesPerZone = lo.ToPtr[int32](2)
+			ExpectApplied(ctx, env.Client, nodePool)
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov)
+			Expect(ExpectNodeClaims(ctx, env.Client)).To(HaveLen(2))
+		})
+		It("should not launch nodeclaims when the existing nodes meet the minimum", func() {
+			ExpectApplied(ctx, env.Client, nodePool)
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov)
+			Expect(ExpectNodeClaims(ctx, env.Client)).To(HaveLen(1))
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov)
+			Expect(ExpectNodeClaims(ctx, env.Client)).To(HaveLen(1))
+		})
+		It("should count in-flight nodeclaims towards the minimum of the zone that they require", func() {
+			nodePool.Spec.Template.Spec.Requirements[0].Values = []string{"test-zone-1", "test-zone-2"}
+			// the nodeclaim has launched, but isn't labeled with its zone until its node registers
+			inflight := test.NodeClaim(v1.NodeClaim{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{v1.NodePoolLabelKey: nodePool.Name}},
+				Spec: v1.NodeClaimSpec{
+					Requirements: []v1.NodeSelectorRequirementWithMinValues{{
+						NodeSelectorRequirement: corev1.NodeSelectorRequirement{
+							Key:      corev1.LabelTopologyZone,
+							Operator: corev1.NodeSelectorOpIn,
+							Values:   []string{"test-zone-1"},
+						},
+					}},
+				},
+				Status: v1.NodeClaimStatus{ProviderID: test.RandomProviderID()},
+			})
+			ExpectApplied(ctx, env.Client, nodePool, inflight)
+			cluster.UpdateNodeClaim(inflight)
+
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov)
+			nodeClaims := lo.Reject(ExpectNodeClaims(ctx, env.Client), func(nc *v1.NodeClaim, _ int) bool { return nc.Name == inflight.Name })
+			Expect(nodeClaims).To(HaveLen(1))
+			Expect(scheduling.NewNodeSelectorRequirementsWithMinValues(nodeClaims[0].Spec.Requirements...).Get(corev1.LabelTopologyZone).Any()).To(Equal("test-zone-2"))
+		})
+		It("should schedule pending pods to the nodes kept for the minimum", func() {
+			ExpectApplied(ctx, env.Client, nodePool)
+			pod := test.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectScheduled(ctx, env.Client, pod)
+			Expect(ExpectNodeClaims(ctx, env.Client)).To(HaveLen(1))
+		})
+	})
+	Context("Dry Run", func() {
+		It("should return the planned NodeClaims without creating them", func() {
+			nodePool := test.NodePool()
+			ExpectApplied(ctx, env.Client, nodePool)
+			pod := test.UnschedulablePod()
+			incompatible := test.UnschedulablePod(test.PodOptions{NodeSelector: map[string]string{corev1.LabelTopologyZone: "unknown"}})
+			ExpectApplied(ctx, env.Client, pod, incompatible)
+
+			recorder := httptest.NewRecorder()
+			provisioning.NewDryRunHandler(ctx, prov).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, provisioning.DryRunPath, nil))
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			response := provisioning.DryRunResponse{}
+			Expect(json.Unmarshal(recorder.Body.Bytes(), &response)).To(Succeed())
+
+			Expect(response.NodeClaims).To(HaveLen(1))
+			Expect(response.NodeClaims[0].NodeClaim.Kind).To(Equal("NodeClaim"))
+			Expect(response.NodeClaims[0].NodeClaim.Labels).To(HaveKeyWithValue(v1.NodePoolLabelKey, nodePool.Name))
+			Expect(response.NodeClaims[0].Pods).To(ConsistOf(client.ObjectKeyFromObject(pod).String()))
+			Expect(response.PodErrors).To(HaveKey(client.ObjectKeyFromObject(incompatible).String()))
+			Expect(ExpectNodeClaims(ctx, env.Client)).To(HaveLen(0))
+			ExpectNotScheduled(ctx, env.Client, pod)
+		})
+		It("should only allow GET requests", func() {
+			recorder := httptest.NewRecorder()
+			provisioning.NewDryRunHandler(ctx, prov).ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, provisioning.DryRunPath, nil))
+			Expect(recorder.Code).To(Equal(http.StatusMethodNotAllowed))
+		})
+	})
+	Context("Scheduling Snapshots", func() {
+		It("should record the results of the scheduling loop", func() {
+			ctx = options.ToContext(ctx, test.Options(test.OptionsFields{EnableSchedulingSnapshots: lo.ToPtr(true)}))
+			nodePool := test.NodePool()
+			ExpectApplied(ctx, env.Client, nodePool)
+			pod := test.UnschedulablePod()
+			incompatible := test.UnschedulablePod(test.PodOptions{NodeSelector: map[string]string{corev1.LabelTopologyZone: "unknown"}})
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod, incompatible)
+
+			snapshot := &v1.SchedulingSnapshot{}
+			Expect(env.Client.Get(ctx, client.ObjectKey{Name: provisioning.SchedulingSnapshotName}, snapshot)).To(Succeed())
+			Expect(snapshot.Spec.NodeClaims).To(HaveLen(1))
+			Expect(snapshot.Spec.NodeClaims[0].NodePool).To(Equal(nodePool.Name))
+			Expect(snapshot.Spec.NodeClaims[0].InstanceTypes).ToNot(BeEmpty())
+			Expect(snapshot.Spec.NodeClaims[0].Pods).To(ConsistOf(client.ObjectKeyFromObject(pod).String()))
+			Expect(snapshot.Spec.PodErrors).To(HaveLen(1))
+			Expect(snapshot.Spec.PodErrors[0].Pod).To(Equal(client.ObjectKeyFromObject(incompatible).String()))
+			Expect(snapshot.Spec.PodErrors[0].Failures).To(HaveLen(1))
+			instanceTypes, err := cloudProvider.GetInstanceTypes(ctx, nodePool)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(snapshot.Spec.PodErrors[0].Failures[0].RejectedInstanceTypes).To(HaveLen(len(instanceTypes)))
+			for _, rejection := range snapshot.Spec.PodErrors[0].Failures[0].RejectedInstanceTypes {
+				Expect(rejection.Reason).To(Equal("requirement"))
+			}
+		})
+		It("should record the instance types that were rejected for the pods of each NodeClaim", func() {
+			ctx = options.ToContext(ctx, test.Options(test.OptionsFields{EnableSchedulingSnapshots: lo.ToPtr(true)}))
+			nodePool := test.NodePool()
+			ExpectApplied(ctx, env.Client, nodePool)
+			pod := test.UnschedulablePod(test.PodOptions{NodeSelector: map[string]string{corev1.LabelInstanceTypeStable: "default-instance-type"}})
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+
+			snapshot := &v1.SchedulingSnapshot{}
+			Expect(env.Client.Get(ctx, client.ObjectKey{Name: provisioning.SchedulingSnapshotName}, snapshot)).To(Succeed())
+			Expect(snapshot.Spec.NodeClaims).To(HaveLen(1))
+			Expect(snapshot.Spec.NodeClaims[0].InstanceTypes).To(ConsistOf("default-instance-type"))
+			instanceTypes, err := cloudProvider.GetInstanceTypes(ctx, nodePool)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(snapshot.Spec.NodeClaims[0].RejectedInstanceTypes).To(HaveLen(len(instanceTypes) - 1))
+			for _, rejection := range snapshot.Spec.NodeClaims[0].RejectedInstanceTypes {
+				Expect(rejection.InstanceType).ToNot(Equal("default-instance-type"))
+				Expect(rejection.Reason).To(Equal("requirement"))
+			}
+		})
+		It("should replace the snapshot of the previous scheduling loop", func() {
+			ctx = options.ToContext(ctx, test.Options(test.OptionsFields{EnableSchedulingSnapshots: lo.ToPtr(true)}))
+			ExpectApplied(ctx, env.Client, test.NodePool())
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, test.UnschedulablePod())
+			incompatible := test.UnschedulablePod(test.PodOptions{NodeSelector: map[string]string{corev1.LabelTopologyZone: "unknown"}})
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, incompatible)
+
+			snapshot := &v1.SchedulingSnapshot{}
+			Expect(env.Client.Get(ctx, client.ObjectKey{Name: provisioning.SchedulingSnapshotName}, snapshot)).To(Succeed())
+			Expect(snapshot.Spec.NodeClaims).To(BeEmpty())
+			Expect(snapshot.Spec.PodErrors).To(HaveLen(1))
+		})
+		It("should truncate the snapshot when there are too many pod errors", func() {
+			ctx = options.ToContext(ctx, test.Options(test.OptionsFields{EnableSchedulingSnapshots: lo.ToPtr(true)}))
+			ExpectApplied(ctx, env.Client, test.NodePool())
+			pods := test.UnschedulablePods(test.PodOptions{NodeSelector: map[string]string{corev1.LabelTopologyZone: "unknown"}}, 60)
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pods...)
+
+			snapshot := &v1.SchedulingSnapshot{}
+			Expect(env.Client.Get(ctx, client.ObjectKey{Name: provisioning.SchedulingSnapshotName}, snapshot)).To(Succeed())
+			Expect(snapshot.Spec.PodErrors).To(HaveLen(50))
+			Expect(snapshot.Spec.Truncated).To(BeTrue())
+		})
+		It("should not record a snapshot when scheduling snapshots are disabled", func() {
+			ExpectApplied(ctx, env.Client, test.NodePool())
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, test.UnschedulablePod())
+
+			err := env.Client.Get(ctx, client.ObjectKey{Name: provisioning.SchedulingSnapshotName}, &v1.SchedulingSnapshot{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+	})
+	Context("Pod Pre-Binding", func() {
+		var bindingController *provisioning.BindingController
+		BeforeEach(func() {
+			bindingController = provisioning.NewBindingController(env.Client, cloudProvider, cluster, events.NewRecorder(&record.FakeRecorder{}))
+		})
+		It("should record the pods to bind to the nodes launched for them", func() {
+			ExpectApplied(ctx, env.Client, test.NodePool())
+			pod := test.UnschedulablePod()
+			ExpectApplied(ctx, env.Client, pod)
+			results, err := prov.Schedule(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(results.NewNodeClaims).To(HaveLen(1))
+			names, err := prov.CreateNodeClaims(ctx, results.NewNodeClaims, provisioning.PreBindPods)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cluster.PreBoundPods(names[0])).To(ConsistOf(client.ObjectKeyFromObject(pod)))
+		})
+		It("should not record the pods to bind without the launch option", func() {
+			ExpectApplied(ctx, env.Client, test.NodePool())
+			ExpectApplied(ctx, env.Client, test.UnschedulablePod())
+			results, err := prov.Schedule(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			names, err := prov.CreateNodeClaims(ctx, results.NewNodeClaims)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cluster.PreBoundPods(names[0])).To(BeEmpty())
+		})
+		It("should bind pending pods once the node initializes", func() {
+			ExpectApplied(ctx, env.Client, test.NodePool())
+			pod := test.UnschedulablePod()
+			bindings := ExpectProvisionedNoBinding(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			nodeClaim := bindings.Get(pod).NodeClaim
+			ExpectMakeNodeClaimsInitialized(ctx, env.Client, nodeClaim)
+			cluster.PreBindPods(nodeClaim.Name, pod)
+
+			ExpectObjectReconciled(ctx, env.Client, bindingController, nodeClaim)
+			Expect(ExpectScheduled(ctx, env.Client, pod).Name).To(Equal(bindings.Get(pod).Node.Name))
+			Expect(cluster.PreBoundPods(nodeClaim.Name)).To(BeEmpty())
+		})
+		It("should wait for the node to initialize before binding pods", func() {
+			ExpectApplied(ctx, env.Client, test.NodePool())
+			pod := test.UnschedulablePod()
+			bindings := ExpectProvisionedNoBinding(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			nodeClaim := bindings.Get(pod).NodeClaim
+			Expect(nodeClaim.StatusConditions().Get(v1.ConditionTypeRegistered).IsTrue()).To(BeTrue())
+			cluster.PreBindPods(nodeClaim.Name, pod)
+
+			ExpectObjectReconciled(ctx, env.Client, bindingController, nodeClaim)
+			ExpectNotScheduled(ctx, env.Client, pod)
+			Expect(cluster.PreBoundPods(nodeClaim.Name)).To(ConsistOf(client.ObjectKeyFromObject(pod)))
+		})
+		It("should not rebind pods that the kube-scheduler already bound", func() {
+			ExpectApplied(ctx, env.Client, test.NodePool())
+			pod := test.UnschedulablePod()
+			bindings := ExpectProvisionedNoBinding(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			nodeClaim := bindings.Get(pod).NodeClaim
+			ExpectMakeNodeClaimsInitialized(ctx, env.Client, nodeClaim)
+			cluster.PreBindPods(nodeClaim.Name, pod)
+			other := test.Node(test.NodeOptions{ProviderID: test.RandomProviderID()})
+			ExpectApplied(ctx, env.Client, other)
+			ExpectManualBinding(ctx, env.Client, pod, other)
+
+			ExpectObjectReconciled(ctx, env.Client, bindingController, nodeClaim)
+			Expect(ExpectScheduled(ctx, env.Client, pod).Name).To(Equal(other.Name))
+			Expect(cluster.PreBoundPods(nodeClaim.Name)).To(BeEmpty())
+		})
+		DescribeTable("should not bind pods that don't tolerate the taints of the node",
+			func(effect corev1.TaintEffect) {
+				ExpectApplied(ctx, env.Client, test.NodePool())
+				pod := test.UnschedulablePod()
+				bindings := ExpectProvisionedNoBinding(ctx, env.Client, cluster, cloudProvider, prov, pod)
+				nodeClaim, node := bindings.Get(pod).NodeClaim, bindings.Get(pod).Node
+				ExpectMakeNodeClaimsInitialized(ctx, env.Client, nodeClaim)
+				node.Spec.Taints = append(node.Spec.Taints, corev1.Taint{Key: "foo.com/taint", Effect: effect})
+				ExpectApplied(ctx, env.Client, node)
+				cluster.PreBindPods(nodeClaim.Name, pod)
+
+				ExpectObjectReconciled(ctx, env.Client, bindingController, nodeClaim)
+				ExpectNotScheduled(ctx, env.Client, pod)
+			},
+			Entry("NoExecute", corev1.TaintEffectNoExecute),
+			Entry("NoSchedule", corev1.TaintEffectNoSchedule),
+		)
+		It("should not bind pods with unbound claims that wait for their first consumer", func() {
+			storageClass := test.StorageClass(test.StorageClassOptions{VolumeBindingMode: lo.ToPtr(storagev1.VolumeBindingWaitForFirstConsumer)})
+			pvc := test.PersistentVolumeClaim(test.PersistentVolumeClaimOptions{StorageClassName: lo.ToPtr(storageClass.Name)})
+			ExpectApplied(ctx, env.Client, test.NodePool(), storageClass, pvc)
+			pod := test.UnschedulablePod(test.PodOptions{PersistentVolumeClaims: []string{pvc.Name}})
+			bindings := ExpectProvisionedNoBinding(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			nodeClaim := bindings.Get(pod).NodeClaim
+			ExpectMakeNodeClaimsInitialized(ctx, env.Client, nodeClaim)
+			cluster.PreBindPods(nodeClaim.Name, pod)
+
+			ExpectObjectReconciled(ctx, env.Client, bindingController, nodeClaim)
+			ExpectNotScheduled(ctx, env.Client, pod)
+		})
+		It("should only bind the pods that fit within the available resources of the node", func() {
+			ExpectApplied(ctx, env.Client, test.NodePool())
+			pod := test.UnschedulablePod()
+			bindings := ExpectProvisionedNoBinding(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			nodeClaim, node := bindings.Get(pod).NodeClaim, bindings.Get(pod).Node
+			ExpectMakeNodeClaimsInitialized(ctx, env.Client, nodeClaim)
+			cpu := node.Status.Allocatable.Cpu().DeepCopy()
+			cpu.Add(resource.MustParse("1"))
+			large := test.UnschedulablePod(test.PodOptions{ResourceRequirements: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: cpu},
+			}})
+			ExpectApplied(ctx, env.Client, large)
+			cluster.PreBindPods(nodeClaim.Name, pod, large)
+
+			ExpectObjectReconciled(ctx, env.Client, bindingController, nodeClaim)
+			Expect(ExpectScheduled(ctx, env.Client, pod).Name).To(Equal(node.Name))
+			ExpectNotScheduled(ctx, env.Client, large)
+		})
+	})
+	Context("Scheduling Extender", func() {
+		var server *httptest.Server
+		var args []pscheduling.ExtenderArgs
+		extender := func(respond func(pscheduling.ExtenderArgs) pscheduling.ExtenderResult) {
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				defer GinkgoRecover()
+				a := pscheduling.ExtenderArgs{}
+				Expect(json.NewDecoder(r.Body).Decode(&a)).To(Succeed())
+				args = append(args, a)
+				Expect(json.NewEncoder(w).Encode(respond(a))).To(Succeed())
+			}))
+			ctx = options.ToContext(ctx, test.Options(test.OptionsFields{SchedulingExtenderURL: lo.ToPtr(server.URL)}))
+		}
+		BeforeEach(func() {
+			args = nil
+		})
+		AfterEach(func() {
+			ctx = options.ToContext(ctx, test.Options())
+			if server != nil {
+				server.Close()
+				server = nil
+			}
+		})
+		It("should only launch the instance types that pass the extender's filter", func() {
+			extender(func(a pscheduling.ExtenderArgs) pscheduling.ExtenderResult {
+				return pscheduling.ExtenderResult{NodeClaims: lo.Map(a.NodeClaims, func(_ pscheduling.ExtenderNodeClaim, _ int) pscheduling.ExtenderNodeClaimResult {
+					return pscheduling.ExtenderNodeClaimResult{InstanceTypes: []string{"small-instance-type"}}
+				})}
+			})
+			nodePool := test.NodePool()
+			ExpectApplied(ctx, env.Client, nodePool)
+			pod := test.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			node := ExpectScheduled(ctx, env.Client, pod)
+			Expect(node.Labels).To(HaveKeyWithValue(corev1.LabelInstanceTypeStable, "small-instance-type"))
+
+			Expect(args).To(HaveLen(1))
+			Expect(args[0].NodeClaims).To(HaveLen(1))
+			Expect(args[0].NodeClaims[0].NodePool).To(Equal(nodePool.Name))
+			Expect(args[0].NodeClaims[0].Pods).To(ConsistOf(client.ObjectKeyFromObject(pod).String()))
+			Expect(args[0].NodeClaims[0].InstanceTypes).To(ContainElement("small-instance-type"))
+		})
+		It("should only launch the instance types with the highest score", func() {
+			extender(func(a pscheduling.ExtenderArgs) pscheduling.ExtenderResult {
+				return pscheduling.ExtenderResult{NodeClaims: lo.Map(a.NodeClaims, func(_ pscheduling.ExtenderNodeClaim, _ int) pscheduling.ExtenderNodeClaimResult {
+					return pscheduling.ExtenderNodeClaimResult{Scores: map[string]int64{"default-instance-type": 10, "small-instance-type": 5}}
+				})}
+			})
+			ExpectApplied(ctx, env.Client, test.NodePool())
+			pod := test.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			node := ExpectScheduled(ctx, env.Client, pod)
+			Expect(node.Labels).To(HaveKeyWithValue(corev1.LabelInstanceTypeStable, "default-instance-type"))
+		})
+		It("should not schedule pods when the extender rejects all of the instance types", func() {
+			extender(func(a pscheduling.ExtenderArgs) pscheduling.ExtenderResult {
+				return pscheduling.ExtenderResult{NodeClaims: lo.Map(a.NodeClaims, func(_ pscheduling.ExtenderNodeClaim, _ int) pscheduling.ExtenderNodeClaimResult {
+					return pscheduling.ExtenderNodeClaimResult{InstanceTypes: []string{}}
+				})}
+			})
+			ExpectApplied(ctx, env.Client, test.NodePool())
+			pod := test.UnschedulablePod()
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectNotScheduled(ctx, env.Client, pod)
+			Expect(ExpectNodeClaims(ctx, env.Client)).To(HaveLen(0))
+		})
+		It("should not launch NodeClaims when the extender fails", func() {
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			}))
+			ctx = options.ToContext(ctx, test.Options(test.OptionsFields{SchedulingExtenderURL: lo.ToPtr(server.URL)}))
+			ExpectApplied(ctx, env.Client, test.NodePool())
+			pod := test.UnschedulablePod()
+			ExpectApplied(ctx, env.Client, pod)
+			_, err := prov.Schedule(ctx)
+			Expect(err).To(HaveOccurred())
+			Expect(ExpectNodeClaims(ctx, env.Client)).To(HaveLen(0))
+		})
+	})
+	Context("Managed By", func() {
+		BeforeEach(func() {
+			ctx = options.ToContext(ctx, test.Options(test.OptionsFields{ManagedBy: lo.ToPtr("shard-a")}))
+		})
+		AfterEach(func() {
+			ctx = options.ToContext(ctx, test.Options())
+		})
+		It("should only provision for pods that require the managed-by value of the instance", func() {
+			ExpectApplied(ctx, env.Client, test.NodePool(v1.NodePool{ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{v1.ManagedByLabelKey: "shard-a"},
+			}}))
+			pods := []*corev1.Pod{
+				test.UnschedulablePod(test.PodOptions{NodeSelector: map[string]string{v1.ManagedByLabelKey: "shard-a"}}),
+				test.UnschedulablePod(test.PodOptions{NodeSelector: map[string]string{v1.ManagedByLabelKey: "shard-b"}}),
+				test.UnschedulablePod(),
+			}
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pods...)
+			node := ExpectScheduled(ctx, env.Client, pods[0])
+			Expect(node.Labels).To(HaveKeyWithValue(v1.ManagedByLabelKey, "shard-a"))
+			ExpectNotScheduled(ctx, env.Client, pods[1])
+			ExpectNotScheduled(ctx, env.Client, pods[2])
+			Expect(ExpectNodeClaims(ctx, env.Client)).To(HaveLen(1))
+		})
+	})
 	Context("Multiple NodePools", func() {
 		It("should schedule to an explicitly selected NodePool", func() {
 			nodePool := test.NodePool()
@@ -2171,6 +3246,45 @@ var _ = Describe("Provisioning", func() {
 			node := ExpectScheduled(ctx, env.Client, pod)
 			Expect(node.Labels[v1.NodePoolLabelKey]).ToNot(Equal(nodePool.Name))
 		})
+		It("should schedule to the NodePool requested by the pod's annotation", func() {
+			nodePool := test.NodePool()
+			ExpectApplied(ctx, env.Client, nodePool, test.NodePool(v1.NodePool{Spec: v1.NodePoolSpec{Weight: lo.ToPtr(int32(100))}}))
+			pod := test.UnschedulablePod(test.PodOptions{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{v1.NodePoolAnnotationKey: nodePool.Name}}})
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			node := ExpectScheduled(ctx, env.Client, pod)
+			Expect(node.Labels[v1.NodePoolLabelKey]).To(Equal(nodePool.Name))
+		})
+		It("should not schedule when the NodePool requested by the pod's annotation doesn't exist", func() {
+			ExpectApplied(ctx, env.Client, test.NodePool())
+			pod := test.UnschedulablePod(test.PodOptions{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{v1.NodePoolAnnotationKey: "unknown"}}})
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectNotScheduled(ctx, env.Client, pod)
+		})
+		It("should not schedule when the NodePool requested by the pod's annotation can't satisfy the pod", func() {
+			nodePool := test.NodePool(v1.NodePool{Spec: v1.NodePoolSpec{Template: v1.NodeClaimTemplate{Spec: v1.NodeClaimTemplateSpec{
+				Requirements: []v1.NodeSelectorRequirementWithMinValues{
+					{NodeSelectorRequirement: corev1.NodeSelectorRequirement{Key: corev1.LabelArchStable, Operator: corev1.NodeSelectorOpIn, Values: []string{v1.ArchitectureAmd64}}},
+				},
+			}}}})
+			ExpectApplied(ctx, env.Client, nodePool, test.NodePool())
+			pod := test.UnschedulablePod(test.PodOptions{
+				ObjectMeta:   metav1.ObjectMeta{Annotations: map[string]string{v1.NodePoolAnnotationKey: nodePool.Name}},
+				NodeSelector: map[string]string{corev1.LabelArchStable: v1.ArchitectureArm64},
+			})
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectNotScheduled(ctx, env.Client, pod)
+		})
+		It("should ignore pods whose annotation conflicts with their NodePool selector", func() {
+			nodePool := test.NodePool()
+			ExpectApplied(ctx, env.Client, nodePool, test.NodePool())
+			pod := test.UnschedulablePod(test.PodOptions{
+				ObjectMeta:   metav1.ObjectMeta{Annotations: map[string]string{v1.NodePoolAnnotationKey: nodePool.Name}},
+				NodeSelector: map[string]string{v1.NodePoolLabelKey: "other"},
+			})
+			ExpectProvisioned(ctx, env.Client, cluster, cloudProvider, prov, pod)
+			ExpectNotScheduled(ctx, env.Client, pod)
+			Expect(ExpectNodeClaims(ctx, env.Client)).To(HaveLen(0))
+		})
 		Context("Weighted NodePools", func() {
 			It("should schedule to the nodepool with the highest priority always", func() {
 				nodePools := []client.Object{
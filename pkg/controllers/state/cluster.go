@@ -30,6 +30,7 @@ import (
 	storagev1 "k8s.io/api/storage/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -40,6 +41,7 @@ import (
 	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
 	"sigs.k8s.io/karpenter/pkg/cloudprovider"
 	"sigs.k8s.io/karpenter/pkg/scheduling"
+	nodeutils "sigs.k8s.io/karpenter/pkg/utils/node"
 	nodeclaimutils "sigs.k8s.io/karpenter/pkg/utils/nodeclaim"
 	podutils "sigs.k8s.io/karpenter/pkg/utils/pod"
 )
@@ -50,15 +52,22 @@ type Cluster struct {
 	cloudProvider             cloudprovider.CloudProvider
 	clock                     clock.Clock
 	mu                        sync.RWMutex
-	nodes                     map[string]*StateNode           // provider id -> cached node
-	bindings                  map[types.NamespacedName]string // pod namespaced named -> node name
-	nodeNameToProviderID      map[string]string               // node name -> provider id
-	nodeClaimNameToProviderID map[string]string               // node claim name -> provider id
-	daemonSetPods             sync.Map                        // daemonSet -> existing pod
+	nodes                     map[string]*StateNode                           // provider id -> cached node
+	bindings                  map[types.NamespacedName]string                 // pod namespaced named -> node name
+	boundPods                 map[string]map[types.NamespacedName]*corev1.Pod // namespace -> pod namespaced name -> bound pod
+	nodeNameToProviderID      map[string]string                               // node name -> provider id
+	nodeClaimNameToProviderID map[string]string                               // node claim name -> provider id
+	daemonSetPods             sync.Map                                        // daemonSet -> existing pod
+	namespaceLabels           sync.Map                                        // namespace name -> labels of the namespace
+	nodeHeartbeats            sync.Map                                        // node name -> time when the node's lease was last renewed
 
 	podAcks                 sync.Map // pod namespaced name -> time when Karpenter first saw the pod as pending
 	podsSchedulingAttempted sync.Map // pod namespaced name -> time when Karpenter tried to schedule a pod
 	podsSchedulableTimes    sync.Map // pod namespaced name -> time when it was first marked as able to fit to a node
+	preBoundPods            sync.Map // node claim name -> namespaced names of the pods to bind once the node initializes
+
+	launchFailuresMu sync.Mutex
+	launchFailures   map[string][]LaunchFailure // nodepool name -> launch failures within the LaunchFailureWindow
 
 	clusterStateMu sync.RWMutex // Separate mutex as this is called in some places that mu is held
 	// A monotonically increasing timestamp representing the time state of the
@@ -78,19 +87,23 @@ func NewCluster(clk clock.Clock, client client.Client, cloudProvider cloudprovid
 		cloudProvider:             cloudProvider,
 		nodes:                     map[string]*StateNode{},
 		bindings:                  map[types.NamespacedName]string{},
+		boundPods:                 map[string]map[types.NamespacedName]*corev1.Pod{},
 		daemonSetPods:             sync.Map{},
 		nodeNameToProviderID:      map[string]string{},
 		nodeClaimNameToProviderID: map[string]string{},
 		podAcks:                   sync.Map{},
 		podsSchedulableTimes:      sync.Map{},
 		podsSchedulingAttempted:   sync.Map{},
+		launchFailures:            map[string][]LaunchFailure{},
 	}
 }
 
 // Synced validates that the NodeClaims and the Nodes that are stored in the apiserver
 // have the same representation in the cluster state. This is to ensure that our view
 // of the cluster is as close to correct as it can be when we begin to perform operations
-// utilizing the cluster state as our source of truth
+// utilizing the cluster state as our source of truth. Namespaces that the cluster state
+// isn't tracking yet are added from the list, so that the namespaceSelectors of pod
+// affinity terms resolve against every namespace that exists.
 //
 //nolint:gocyclo
 func (c *Cluster) Synced(ctx context.Context) (synced bool) {
@@ -120,6 +133,14 @@ func (c *Cluster) Synced(ctx context.Context) (synced bool) {
 		log.FromContext(ctx).Error(err, "failed checking cluster state sync")
 		return false
 	}
+	namespaceList := &corev1.NamespaceList{}
+	if err := c.kubeClient.List(ctx, namespaceList); err != nil {
+		log.FromContext(ctx).Error(err, "failed checking cluster state sync")
+		return false
+	}
+	for i := range namespaceList.Items {
+		c.namespaceLabels.LoadOrStore(namespaceList.Items[i].Name, labels.Set(namespaceList.Items[i].Labels))
+	}
 	c.mu.RLock()
 	stateNodeClaimNames := sets.New[string]()
 	for name, providerID := range c.nodeClaimNameToProviderID {
@@ -170,6 +191,24 @@ func (c *Cluster) ForPodsWithAntiAffinity(fn func(p *corev1.Pod, n *corev1.Node)
 	})
 }
 
+// ForPodsInNamespace calls the supplied function once for each pod in the namespace that is currently bound to a
+// node. The index backing this is maintained incrementally from pod and node events so that callers that need to
+// inspect bound pods (e.g. topology counting) don't need to list pods from the API server on every call.
+func (c *Cluster) ForPodsInNamespace(namespace string, fn func(p *corev1.Pod, n *corev1.Node) bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, pod := range c.boundPods[namespace] {
+		node, ok := c.nodes[c.nodeNameToProviderID[pod.Spec.NodeName]]
+		if !ok || node.Node == nil {
+			// if we receive the node deletion event before the pod deletion event, this can happen
+			continue
+		}
+		if !fn(pod, node.Node) {
+			return
+		}
+	}
+}
+
 // ForEachNode calls the supplied function once per node object that is being tracked. It is not safe to store the
 // state.StateNode object, it should be only accessed from within the function provided to this method.
 func (c *Cluster) ForEachNode(f func(n *StateNode) bool) {
@@ -183,6 +222,27 @@ func (c *Cluster) ForEachNode(f func(n *StateNode) bool) {
 	}
 }
 
+// DisruptionCounts returns, by NodePool, how many nodes count towards the NodePool's disruption budgets and how many
+// of them are already disrupting. Only managed, initialized nodes count towards the total: if a node is launched or
+// registered but not initialized, pods aren't scheduled to it, and counting it could allow more disruptions to active
+// nodes than desired with percentage budgets. Nodes whose instance is terminating aren't counted at all. Nodes that
+// are NotReady or marked for deletion are disrupting, and are subtracted from what the budgets allow.
+func (c *Cluster) DisruptionCounts() (numNodes, disrupting map[string]int) {
+	numNodes, disrupting = map[string]int{}, map[string]int{}
+	c.ForEachNode(func(n *StateNode) bool {
+		if !n.Managed() || !n.Initialized() || n.NodeClaim.StatusConditions().Get(v1.ConditionTypeInstanceTerminating).IsTrue() {
+			return true
+		}
+		nodePool := n.Labels()[v1.NodePoolLabelKey]
+		numNodes[nodePool]++
+		if cond := nodeutils.GetCondition(n.Node, corev1.NodeReady); cond.Status != corev1.ConditionTrue || n.MarkedForDeletion() {
+			disrupting[nodePool]++
+		}
+		return true
+	})
+	return numNodes, disrupting
+}
+
 // Nodes creates a DeepCopy of all state nodes.
 // NOTE: This is very inefficient so this should only be used when DeepCopying is absolutely necessary
 func (c *Cluster) Nodes() StateNodes {
@@ -266,6 +326,7 @@ func (c *Cluster) DeleteNodeClaim(name string) {
 	defer c.mu.Unlock()
 
 	c.cleanupNodeClaim(name)
+	c.preBoundPods.Delete(name)
 	ClusterStateNodesCount.Set(float64(len(c.nodes)), nil)
 }
 
@@ -304,6 +365,25 @@ func (c *Cluster) DeleteNode(name string) {
 	ClusterStateNodesCount.Set(float64(len(c.nodes)), nil)
 }
 
+// UpdateNodeHeartbeat records when the node's lease was last renewed
+func (c *Cluster) UpdateNodeHeartbeat(name string, renewTime time.Time) {
+	c.nodeHeartbeats.Store(name, renewTime)
+}
+
+// NodesWithStaleHeartbeats returns the names of the nodes in cluster state whose lease was last renewed before the
+// cutoff. Nodes whose lease has never been seen aren't returned.
+func (c *Cluster) NodesWithStaleHeartbeats(cutoff time.Time) []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var names []string
+	for name := range c.nodeNameToProviderID {
+		if renewTime, ok := c.nodeHeartbeats.Load(name); ok && renewTime.(time.Time).Before(cutoff) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
 func (c *Cluster) UpdatePod(ctx context.Context, pod *corev1.Pod) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -375,6 +455,63 @@ func (c *Cluster) PodSchedulingSuccessTime(podKey types.NamespacedName) time.Tim
 	return time.Time{}
 }
 
+// PreBindPods records the pods that Karpenter binds to the node of the NodeClaim once it initializes, rather than leaving
+// them for the kube-scheduler, which may bind other pods to the node first
+func (c *Cluster) PreBindPods(nodeClaimName string, pods ...*corev1.Pod) {
+	c.preBoundPods.Store(nodeClaimName, lo.Map(pods, func(p *corev1.Pod, _ int) types.NamespacedName { return client.ObjectKeyFromObject(p) }))
+}
+
+// PreBoundPods returns the pods that Karpenter binds to the node of the NodeClaim once it initializes
+func (c *Cluster) PreBoundPods(nodeClaimName string) []types.NamespacedName {
+	if pods, ok := c.preBoundPods.Load(nodeClaimName); ok {
+		return pods.([]types.NamespacedName)
+	}
+	return nil
+}
+
+// ClearPreBoundPods forgets the pods that Karpenter binds to the node of the NodeClaim, once they have been bound
+func (c *Cluster) ClearPreBoundPods(nodeClaimName string) {
+	c.preBoundPods.Delete(nodeClaimName)
+}
+
+// LaunchFailureWindow is how long the launch failures of a NodePool's NodeClaims are remembered for
+const LaunchFailureWindow = 10 * time.Minute
+
+// LaunchFailure is a failed launch of one of a NodePool's NodeClaims
+type LaunchFailure struct {
+	Reason string
+	Time   time.Time
+}
+
+// RecordLaunchFailure records that launching one of the NodePool's NodeClaims failed. NodeClaims that fail to launch are
+// often deleted right away, so their failures are tracked here rather than read back from the NodeClaims.
+func (c *Cluster) RecordLaunchFailure(nodePoolName string, reason string) {
+	c.launchFailuresMu.Lock()
+	defer c.launchFailuresMu.Unlock()
+	now := c.clock.Now()
+	c.launchFailures[nodePoolName] = append(c.pruneLaunchFailures(nodePoolName, now), LaunchFailure{Reason: reason, Time: now})
+}
+
+// LaunchFailures returns the launch failures of the NodePool's NodeClaims within the LaunchFailureWindow
+func (c *Cluster) LaunchFailures(nodePoolName string) []LaunchFailure {
+	c.launchFailuresMu.Lock()
+	defer c.launchFailuresMu.Unlock()
+	return append([]LaunchFailure(nil), c.pruneLaunchFailures(nodePoolName, c.clock.Now())...)
+}
+
+// pruneLaunchFailures forgets the launch failures of the NodePool that are older than the LaunchFailureWindow
+func (c *Cluster) pruneLaunchFailures(nodePoolName string, now time.Time) []LaunchFailure {
+	failures := lo.Filter(c.launchFailures[nodePoolName], func(f LaunchFailure, _ int) bool {
+		return now.Sub(f.Time) < LaunchFailureWindow
+	})
+	if len(failures) == 0 {
+		delete(c.launchFailures, nodePoolName)
+		return nil
+	}
+	c.launchFailures[nodePoolName] = failures
+	return failures
+}
+
 func (c *Cluster) DeletePod(podKey types.NamespacedName) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -430,8 +567,15 @@ func (c *Cluster) Reset() {
 	c.nodeNameToProviderID = map[string]string{}
 	c.nodeClaimNameToProviderID = map[string]string{}
 	c.bindings = map[types.NamespacedName]string{}
+	c.boundPods = map[string]map[types.NamespacedName]*corev1.Pod{}
 	c.antiAffinityPods = sync.Map{}
 	c.daemonSetPods = sync.Map{}
+	c.namespaceLabels = sync.Map{}
+	c.nodeHeartbeats = sync.Map{}
+	c.preBoundPods = sync.Map{}
+	c.launchFailuresMu.Lock()
+	c.launchFailures = map[string][]LaunchFailure{}
+	c.launchFailuresMu.Unlock()
 }
 
 func (c *Cluster) GetDaemonSetPod(daemonset *appsv1.DaemonSet) *corev1.Pod {
@@ -467,6 +611,27 @@ func (c *Cluster) DeleteDaemonSet(key types.NamespacedName) {
 	c.daemonSetPods.Delete(key)
 }
 
+func (c *Cluster) UpdateNamespace(namespace *corev1.Namespace) {
+	c.namespaceLabels.Store(namespace.Name, labels.Set(namespace.Labels))
+}
+
+func (c *Cluster) DeleteNamespace(name string) {
+	c.namespaceLabels.Delete(name)
+}
+
+// NamespacesMatching returns the names of the namespaces whose labels match the selector. This is used to resolve the
+// namespaceSelector of pod affinity terms without listing namespaces from the API server.
+func (c *Cluster) NamespacesMatching(selector labels.Selector) sets.Set[string] {
+	namespaces := sets.New[string]()
+	c.namespaceLabels.Range(func(key, value any) bool {
+		if selector.Matches(value.(labels.Set)) {
+			namespaces.Insert(key.(string))
+		}
+		return true
+	})
+	return namespaces
+}
+
 // WARNING
 // Everything under this section of code assumes that you have already held a lock when you are calling into these functions
 // and explicitly modifying the cluster state. If you do not hold the cluster state lock before calling any of these helpers
@@ -481,6 +646,7 @@ func (c *Cluster) newStateFromNodeClaim(nodeClaim *v1.NodeClaim, oldNode *StateN
 		NodeClaim:         nodeClaim,
 		daemonSetRequests: oldNode.daemonSetRequests,
 		daemonSetLimits:   oldNode.daemonSetLimits,
+		staticPods:        oldNode.staticPods,
 		podRequests:       oldNode.podRequests,
 		podLimits:         oldNode.podLimits,
 		hostPortUsage:     oldNode.hostPortUsage,
@@ -522,6 +688,7 @@ func (c *Cluster) newStateFromNode(ctx context.Context, node *corev1.Node, oldNo
 		NodeClaim:         oldNode.NodeClaim,
 		daemonSetRequests: map[types.NamespacedName]corev1.ResourceList{},
 		daemonSetLimits:   map[types.NamespacedName]corev1.ResourceList{},
+		staticPods:        map[types.NamespacedName]*corev1.Pod{},
 		podRequests:       map[types.NamespacedName]corev1.ResourceList{},
 		podLimits:         map[types.NamespacedName]corev1.ResourceList{},
 		hostPortUsage:     scheduling.NewHostPortUsage(),
@@ -555,6 +722,7 @@ func (c *Cluster) cleanupNode(name string) {
 		delete(c.nodeNameToProviderID, name)
 		c.MarkUnconsolidated()
 	}
+	c.nodeHeartbeats.Delete(name)
 }
 
 func (c *Cluster) populateVolumeLimits(ctx context.Context, n *StateNode) error {
@@ -586,6 +754,7 @@ func (c *Cluster) populateResourceRequests(ctx context.Context, n *StateNode) er
 		}
 		c.cleanupOldBindings(pod)
 		c.bindings[client.ObjectKeyFromObject(pod)] = pod.Spec.NodeName
+		c.updateBoundPod(pod)
 	}
 	return nil
 }
@@ -608,6 +777,7 @@ func (c *Cluster) updateNodeUsageFromPod(ctx context.Context, pod *corev1.Pod) e
 	}
 	c.cleanupOldBindings(pod)
 	c.bindings[client.ObjectKeyFromObject(pod)] = pod.Spec.NodeName
+	c.updateBoundPod(pod)
 	return nil
 }
 
@@ -619,6 +789,7 @@ func (c *Cluster) updateNodeUsageFromPodCompletion(podKey types.NamespacedName)
 	}
 
 	delete(c.bindings, podKey)
+	c.deleteBoundPod(podKey)
 	n, ok := c.nodes[c.nodeNameToProviderID[nodeName]]
 	if !ok {
 		// we weren't tracking the node yet, so nothing to do
@@ -645,6 +816,20 @@ func (c *Cluster) cleanupOldBindings(pod *corev1.Pod) {
 	c.MarkUnconsolidated()
 }
 
+func (c *Cluster) updateBoundPod(pod *corev1.Pod) {
+	if _, ok := c.boundPods[pod.Namespace]; !ok {
+		c.boundPods[pod.Namespace] = map[types.NamespacedName]*corev1.Pod{}
+	}
+	c.boundPods[pod.Namespace][client.ObjectKeyFromObject(pod)] = pod
+}
+
+func (c *Cluster) deleteBoundPod(podKey types.NamespacedName) {
+	delete(c.boundPods[podKey.Namespace], podKey)
+	if len(c.boundPods[podKey.Namespace]) == 0 {
+		delete(c.boundPods, podKey.Namespace)
+	}
+}
+
 func (c *Cluster) updatePodAntiAffinities(pod *corev1.Pod) {
 	// We intentionally don't track inverse anti-affinity preferences. We're not
 	// required to enforce them so it just adds complexity for very little
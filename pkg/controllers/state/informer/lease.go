@@ -0,0 +1,71 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package informer
+
+import (
+	"context"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	controllerruntime "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"sigs.k8s.io/karpenter/pkg/controllers/state"
+	"sigs.k8s.io/karpenter/pkg/operator/injection"
+)
+
+// LeaseController reconciles node leases for the purpose of tracking when each node last sent a heartbeat. A deleted
+// lease isn't removed from cluster state, so that the heartbeat of a node that was deleted keeps aging.
+type LeaseController struct {
+	kubeClient client.Client
+	cluster    *state.Cluster
+}
+
+func NewLeaseController(kubeClient client.Client, cluster *state.Cluster) *LeaseController {
+	return &LeaseController{
+		kubeClient: kubeClient,
+		cluster:    cluster,
+	}
+}
+
+func (c *LeaseController) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	ctx = injection.WithControllerName(ctx, "state.lease")
+
+	lease := &coordinationv1.Lease{}
+	if err := c.kubeClient.Get(ctx, req.NamespacedName, lease); err != nil {
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+	if lease.Spec.RenewTime != nil {
+		c.cluster.UpdateNodeHeartbeat(lease.Name, lease.Spec.RenewTime.Time)
+	}
+	return reconcile.Result{}, nil
+}
+
+func (c *LeaseController) Register(_ context.Context, m manager.Manager) error {
+	return controllerruntime.NewControllerManagedBy(m).
+		Named("state.lease").
+		For(&coordinationv1.Lease{}, builder.WithPredicates(predicate.NewPredicateFuncs(func(o client.Object) bool {
+			return o.GetNamespace() == corev1.NamespaceNodeLease
+		}))).
+		WithOptions(controller.Options{MaxConcurrentReconciles: 10}).
+		Complete(c)
+}
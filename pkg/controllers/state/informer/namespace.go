@@ -0,0 +1,69 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package informer
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	controllerruntime "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"sigs.k8s.io/karpenter/pkg/controllers/state"
+	"sigs.k8s.io/karpenter/pkg/operator/injection"
+)
+
+// NamespaceController reconciles namespaces for the purpose of maintaining state of the namespace labels, which are
+// used to resolve the namespaceSelector of pod affinity terms.
+type NamespaceController struct {
+	kubeClient client.Client
+	cluster    *state.Cluster
+}
+
+func NewNamespaceController(kubeClient client.Client, cluster *state.Cluster) *NamespaceController {
+	return &NamespaceController{
+		kubeClient: kubeClient,
+		cluster:    cluster,
+	}
+}
+
+func (c *NamespaceController) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	ctx = injection.WithControllerName(ctx, "state.namespace")
+
+	namespace := &corev1.Namespace{}
+	if err := c.kubeClient.Get(ctx, req.NamespacedName, namespace); err != nil {
+		if errors.IsNotFound(err) {
+			// notify cluster state of the namespace deletion
+			c.cluster.DeleteNamespace(req.Name)
+		}
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+	c.cluster.UpdateNamespace(namespace)
+	return reconcile.Result{}, nil
+}
+
+func (c *NamespaceController) Register(_ context.Context, m manager.Manager) error {
+	return controllerruntime.NewControllerManagedBy(m).
+		Named("state.namespace").
+		For(&corev1.Namespace{}).
+		WithOptions(controller.Options{MaxConcurrentReconciles: 10}).
+		Complete(c)
+}
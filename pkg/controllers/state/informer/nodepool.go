@@ -52,7 +52,7 @@ func NewNodePoolController(kubeClient client.Client, cloudProvider cloudprovider
 
 func (c *NodePoolController) Reconcile(ctx context.Context, np *v1.NodePool) (reconcile.Result, error) {
 	ctx = injection.WithControllerName(ctx, "state.nodepool") //nolint:ineffassign,staticcheck
-	if !nodepoolutils.IsManaged(np, c.cloudProvider) {
+	if !nodepoolutils.IsManaged(ctx, np, c.cloudProvider) {
 		return reconcile.Result{}, nil
 	}
 
@@ -61,10 +61,10 @@ func (c *NodePoolController) Reconcile(ctx context.Context, np *v1.NodePool) (re
 	return reconcile.Result{}, nil
 }
 
-func (c *NodePoolController) Register(_ context.Context, m manager.Manager) error {
+func (c *NodePoolController) Register(ctx context.Context, m manager.Manager) error {
 	return controllerruntime.NewControllerManagedBy(m).
 		Named("state.nodepool").
-		For(&v1.NodePool{}, builder.WithPredicates(nodepoolutils.IsManagedPredicateFuncs(c.cloudProvider))).
+		For(&v1.NodePool{}, builder.WithPredicates(nodepoolutils.IsManagedPredicateFuncs(ctx, c.cloudProvider))).
 		WithOptions(controller.Options{MaxConcurrentReconciles: 10}).
 		WithEventFilter(predicate.GenerationChangedPredicate{}).
 		WithEventFilter(predicate.Funcs{DeleteFunc: func(event event.DeleteEvent) bool { return false }}).
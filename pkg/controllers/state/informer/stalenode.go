@@ -0,0 +1,83 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package informer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/awslabs/operatorpkg/singleton"
+	"go.uber.org/multierr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/utils/clock"
+	controllerruntime "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"sigs.k8s.io/karpenter/pkg/controllers/state"
+	"sigs.k8s.io/karpenter/pkg/operator/injection"
+)
+
+// staleHeartbeatThreshold is how long a node can go without renewing its lease before it's checked for deletion. It's
+// well beyond the node monitor grace period, so that only nodes that are gone or unreachable are checked.
+const staleHeartbeatThreshold = 5 * time.Minute
+
+// StaleNodeController purges nodes from cluster state that were deleted without cluster state being notified, e.g.
+// when a deletion event is lost during a control plane disruption. Such nodes would otherwise be counted as capacity
+// that doesn't exist and suppress provisioning. Only nodes whose heartbeat is stale are checked against the apiserver,
+// which is read directly so that a stale cache can't keep them around.
+type StaleNodeController struct {
+	clock     clock.Clock
+	apiReader client.Reader
+	cluster   *state.Cluster
+}
+
+func NewStaleNodeController(clk clock.Clock, apiReader client.Reader, cluster *state.Cluster) *StaleNodeController {
+	return &StaleNodeController{
+		clock:     clk,
+		apiReader: apiReader,
+		cluster:   cluster,
+	}
+}
+
+func (c *StaleNodeController) Reconcile(ctx context.Context) (reconcile.Result, error) {
+	ctx = injection.WithControllerName(ctx, "state.stalenode")
+
+	var errs error
+	for _, name := range c.cluster.NodesWithStaleHeartbeats(c.clock.Now().Add(-staleHeartbeatThreshold)) {
+		if err := c.apiReader.Get(ctx, client.ObjectKey{Name: name}, &corev1.Node{}); err != nil {
+			if errors.IsNotFound(err) {
+				c.cluster.DeleteNode(name)
+				log.FromContext(ctx).WithValues("Node", name).Info("purged deleted node from cluster state")
+				continue
+			}
+			errs = multierr.Append(errs, fmt.Errorf("getting node, %w", err))
+		}
+	}
+	return reconcile.Result{RequeueAfter: stateRetryPeriod}, errs
+}
+
+func (c *StaleNodeController) Register(_ context.Context, m manager.Manager) error {
+	return controllerruntime.NewControllerManagedBy(m).
+		Named("state.stalenode").
+		WatchesRawSource(singleton.Source()).
+		Complete(singleton.AsReconciler(c))
+}
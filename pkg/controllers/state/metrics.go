@@ -17,7 +17,6 @@ limitations under the License.
 package state
 
 import (
-	opmetrics "github.com/awslabs/operatorpkg/metrics"
 	"github.com/prometheus/client_golang/prometheus"
 	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 
@@ -29,7 +28,7 @@ const (
 )
 
 var (
-	ClusterStateNodesCount = opmetrics.NewPrometheusGauge(
+	ClusterStateNodesCount = metrics.NewPrometheusGauge(
 		crmetrics.Registry,
 		prometheus.GaugeOpts{
 			Namespace: metrics.Namespace,
@@ -39,7 +38,7 @@ var (
 		},
 		[]string{},
 	)
-	ClusterStateSynced = opmetrics.NewPrometheusGauge(
+	ClusterStateSynced = metrics.NewPrometheusGauge(
 		crmetrics.Registry,
 		prometheus.GaugeOpts{
 			Namespace: metrics.Namespace,
@@ -49,7 +48,7 @@ var (
 		},
 		[]string{},
 	)
-	ClusterStateUnsyncedTimeSeconds = opmetrics.NewPrometheusGauge(
+	ClusterStateUnsyncedTimeSeconds = metrics.NewPrometheusGauge(
 		crmetrics.Registry,
 		prometheus.GaugeOpts{
 			Namespace: metrics.Namespace,
@@ -59,7 +58,7 @@ var (
 		},
 		[]string{},
 	)
-	PodSchedulingDecisionSeconds = opmetrics.NewPrometheusHistogram(
+	PodSchedulingDecisionSeconds = metrics.NewPrometheusHistogram(
 		crmetrics.Registry,
 		prometheus.HistogramOpts{
 			Namespace: metrics.Namespace,
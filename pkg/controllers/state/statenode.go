@@ -120,6 +120,9 @@ type StateNode struct {
 	// of the Node to identify the remaining resources that we expect future daemonsets to consume.
 	daemonSetRequests map[types.NamespacedName]corev1.ResourceList
 	daemonSetLimits   map[types.NamespacedName]corev1.ResourceList
+	// staticPods are the mirror pods of the static pods that the kubelet runs on the node. They can't be evicted or
+	// rescheduled, and nodes launched from the same NodePool are expected to run the same static pods.
+	staticPods map[types.NamespacedName]*corev1.Pod
 
 	podRequests map[types.NamespacedName]corev1.ResourceList
 	podLimits   map[types.NamespacedName]corev1.ResourceList
@@ -137,6 +140,7 @@ func NewNode() *StateNode {
 	return &StateNode{
 		daemonSetRequests: map[types.NamespacedName]corev1.ResourceList{},
 		daemonSetLimits:   map[types.NamespacedName]corev1.ResourceList{},
+		staticPods:        map[types.NamespacedName]*corev1.Pod{},
 		podRequests:       map[types.NamespacedName]corev1.ResourceList{},
 		podLimits:         map[types.NamespacedName]corev1.ResourceList{},
 		hostPortUsage:     scheduling.NewHostPortUsage(),
@@ -282,7 +286,7 @@ func (in *StateNode) Taints() []corev1.Taint {
 	// providerID in our state, we should also just use the NodeClaim since this is all that we have
 	var taints []corev1.Taint
 	if (!in.Registered() && in.Managed()) || in.Node == nil {
-		taints = in.NodeClaim.Spec.Taints
+		taints = in.NodeClaim.Spec.TaintsWithTemplates()
 	} else {
 		taints = in.Node.Spec.Taints
 	}
@@ -308,6 +312,24 @@ func (in *StateNode) Taints() []corev1.Taint {
 	return taints
 }
 
+// StartupTaints returns the startup taints of a managed node that hasn't initialized yet. These taints aren't returned
+// by Taints as they're expected to be removed once the node initializes. Nodes that haven't registered yet will have all
+// of the startup taints of their NodeClaim applied to them on registration.
+func (in *StateNode) StartupTaints() []corev1.Taint {
+	if in.Initialized() || !in.Managed() {
+		return nil
+	}
+	if !in.Registered() {
+		return in.NodeClaim.Spec.StartupTaints
+	}
+	return lo.Filter(in.Node.Spec.Taints, func(taint corev1.Taint, _ int) bool {
+		_, found := lo.Find(in.NodeClaim.Spec.StartupTaints, func(t corev1.Taint) bool {
+			return t.MatchTaint(&taint)
+		})
+		return found
+	})
+}
+
 func (in *StateNode) Registered() bool {
 	// Node is managed by Karpenter, so we can check for the Registered label
 	if in.Managed() {
@@ -373,6 +395,11 @@ func (in *StateNode) DaemonSetLimits() corev1.ResourceList {
 	return resources.Merge(lo.Values(in.daemonSetLimits)...)
 }
 
+// StaticPods returns the mirror pods of the static pods that are running on the node
+func (in *StateNode) StaticPods() []*corev1.Pod {
+	return lo.Values(in.staticPods)
+}
+
 func (in *StateNode) HostPortUsage() *scheduling.HostPortUsage {
 	return in.hostPortUsage
 }
@@ -432,6 +459,10 @@ func (in *StateNode) updateForPod(ctx context.Context, kubeClient client.Client,
 		in.daemonSetRequests[podKey] = resources.RequestsForPods(pod)
 		in.daemonSetLimits[podKey] = resources.LimitsForPods(pod)
 	}
+	// static pods are tracked separately as they're expected to run on the NodePool's new nodes as well
+	if podutils.IsOwnedByNode(pod) {
+		in.staticPods[podKey] = pod
+	}
 	in.hostPortUsage.Add(pod, hostPorts)
 	in.volumeUsage.Add(pod, volumes)
 	return nil
@@ -444,9 +475,13 @@ func (in *StateNode) cleanupForPod(podKey types.NamespacedName) {
 	delete(in.podLimits, podKey)
 	delete(in.daemonSetRequests, podKey)
 	delete(in.daemonSetLimits, podKey)
+	delete(in.staticPods, podKey)
 }
 
 func nominationWindow(ctx context.Context) time.Duration {
+	if ttl := options.FromContext(ctx).NominationTTL; ttl > 0 {
+		return ttl
+	}
 	nominationPeriod := 2 * options.FromContext(ctx).BatchMaxDuration
 	if nominationPeriod < 10*time.Second {
 		nominationPeriod = 10 * time.Second
@@ -27,10 +27,12 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/samber/lo"
+	coordinationv1 "k8s.io/api/coordination/v1"
 	corev1 "k8s.io/api/core/v1"
 	storagev1 "k8s.io/api/storage/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/sets"
 	cloudproviderapi "k8s.io/cloud-provider/api"
 	clock "k8s.io/utils/clock/testing"
@@ -58,6 +60,9 @@ var nodeController *informer.NodeController
 var podController *informer.PodController
 var nodePoolController *informer.NodePoolController
 var daemonsetController *informer.DaemonSetController
+var namespaceController *informer.NamespaceController
+var leaseController *informer.LeaseController
+var staleNodeController *informer.StaleNodeController
 var cloudProvider *fake.CloudProvider
 var nodePool *v1.NodePool
 
@@ -80,6 +85,9 @@ var _ = BeforeSuite(func() {
 	podController = informer.NewPodController(env.Client, cluster)
 	nodePoolController = informer.NewNodePoolController(env.Client, cloudProvider, cluster)
 	daemonsetController = informer.NewDaemonSetController(env.Client, cluster)
+	namespaceController = informer.NewNamespaceController(env.Client, cluster)
+	leaseController = informer.NewLeaseController(env.Client, cluster)
+	staleNodeController = informer.NewStaleNodeController(fakeClock, env.Client, cluster)
 })
 
 var _ = AfterSuite(func() {
@@ -362,6 +370,55 @@ var _ = Describe("Node Deletion", func() {
 	})
 })
 
+var _ = Describe("Stale Nodes", func() {
+	var node *corev1.Node
+	BeforeEach(func() {
+		_, node = test.NodeClaimAndNode(v1.NodeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					v1.NodePoolLabelKey:            nodePool.Name,
+					corev1.LabelInstanceTypeStable: cloudProvider.InstanceTypes[0].Name,
+				},
+			},
+		})
+		ExpectApplied(ctx, env.Client, node)
+		ExpectReconcileSucceeded(ctx, nodeController, client.ObjectKeyFromObject(node))
+		ExpectStateNodeCount("==", 1)
+	})
+	It("should track the heartbeat of a node from its lease", func() {
+		lease := &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{Name: node.Name, Namespace: corev1.NamespaceNodeLease},
+			Spec:       coordinationv1.LeaseSpec{RenewTime: &metav1.MicroTime{Time: fakeClock.Now()}},
+		}
+		ExpectApplied(ctx, env.Client, lease)
+		ExpectReconcileSucceeded(ctx, leaseController, client.ObjectKeyFromObject(lease))
+
+		Expect(cluster.NodesWithStaleHeartbeats(fakeClock.Now())).To(BeEmpty())
+		Expect(cluster.NodesWithStaleHeartbeats(fakeClock.Now().Add(time.Second))).To(ConsistOf(node.Name))
+		ExpectDeleted(ctx, env.Client, lease)
+	})
+	It("should purge a deleted node with a stale heartbeat from cluster state", func() {
+		cluster.UpdateNodeHeartbeat(node.Name, fakeClock.Now())
+		// delete the node without notifying cluster state
+		ExpectDeleted(ctx, env.Client, node)
+		fakeClock.Step(10 * time.Minute)
+		ExpectSingletonReconciled(ctx, staleNodeController)
+		ExpectStateNodeCount("==", 0)
+	})
+	It("should not purge a node with a stale heartbeat that still exists", func() {
+		cluster.UpdateNodeHeartbeat(node.Name, fakeClock.Now())
+		fakeClock.Step(10 * time.Minute)
+		ExpectSingletonReconciled(ctx, staleNodeController)
+		ExpectStateNodeCount("==", 1)
+	})
+	It("should not purge a deleted node whose heartbeat is recent", func() {
+		cluster.UpdateNodeHeartbeat(node.Name, fakeClock.Now())
+		ExpectDeleted(ctx, env.Client, node)
+		ExpectSingletonReconciled(ctx, staleNodeController)
+		ExpectStateNodeCount("==", 1)
+	})
+})
+
 var _ = Describe("Node Resource Level", func() {
 	It("should not count pods not bound to nodes", func() {
 		pod1 := test.UnschedulablePod(test.PodOptions{
@@ -815,6 +872,51 @@ var _ = Describe("Node Resource Level", func() {
 			corev1.ResourceMemory: resource.MustParse("2Gi"),
 		}, ExpectStateNodeExists(cluster, node).PodRequests())
 	})
+	It("should track static pods separately", func() {
+		node := test.Node(test.NodeOptions{
+			ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+				v1.NodePoolLabelKey:            nodePool.Name,
+				corev1.LabelInstanceTypeStable: cloudProvider.InstanceTypes[0].Name,
+			}},
+			Allocatable: map[corev1.ResourceName]resource.Quantity{
+				corev1.ResourceCPU:    resource.MustParse("4"),
+				corev1.ResourceMemory: resource.MustParse("8Gi"),
+			},
+			ProviderID: test.RandomProviderID(),
+		})
+		ExpectApplied(ctx, env.Client, node)
+		ExpectReconcileSucceeded(ctx, nodeController, client.ObjectKeyFromObject(node))
+
+		staticPod := test.UnschedulablePod(test.PodOptions{
+			ObjectMeta: metav1.ObjectMeta{OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "v1",
+				Kind:       "Node",
+				Name:       node.Name,
+				UID:        node.UID,
+				Controller: lo.ToPtr(true),
+			}}},
+			ResourceRequirements: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse("500m"),
+			}},
+		})
+		pod := test.UnschedulablePod(test.PodOptions{ResourceRequirements: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+			corev1.ResourceCPU: resource.MustParse("1"),
+		}}})
+		ExpectApplied(ctx, env.Client, staticPod, pod)
+		ExpectManualBinding(ctx, env.Client, staticPod, node)
+		ExpectManualBinding(ctx, env.Client, pod, node)
+		ExpectReconcileSucceeded(ctx, podController, client.ObjectKeyFromObject(staticPod))
+		ExpectReconcileSucceeded(ctx, podController, client.ObjectKeyFromObject(pod))
+
+		stateNode := ExpectStateNodeExists(cluster, node)
+		Expect(lo.Map(stateNode.StaticPods(), func(p *corev1.Pod, _ int) string { return p.Name })).To(ConsistOf(staticPod.Name))
+		// static pods still count towards the node's usage
+		ExpectResources(corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1.5")}, stateNode.PodRequests())
+
+		ExpectDeleted(ctx, env.Client, staticPod)
+		ExpectReconcileSucceeded(ctx, podController, client.ObjectKeyFromObject(staticPod))
+		Expect(ExpectStateNodeExists(cluster, node).StaticPods()).To(BeEmpty())
+	})
 	It("should mark node for deletion when node is deleted", func() {
 		node := test.Node(test.NodeOptions{
 			ObjectMeta: metav1.ObjectMeta{
@@ -925,6 +1027,25 @@ var _ = Describe("Node Resource Level", func() {
 		time.Sleep(time.Second * 11) // past 20s, node should no longer be nominated
 		Expect(ExpectStateNodeExists(cluster, node).Nominated()).To(BeFalse())
 	})
+	It("should nominate the node until the configured nomination ttl passes", func() {
+		node := test.Node(test.NodeOptions{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					v1.NodePoolLabelKey:            nodePool.Name,
+					corev1.LabelInstanceTypeStable: cloudProvider.InstanceTypes[0].Name,
+				},
+				Finalizers: []string{v1.TerminationFinalizer},
+			},
+			ProviderID: test.RandomProviderID(),
+		})
+		ExpectApplied(ctx, env.Client, node)
+		ExpectReconcileSucceeded(ctx, nodeController, client.ObjectKeyFromObject(node))
+
+		cluster.NominateNodeForPod(options.ToContext(ctx, test.Options(test.OptionsFields{NominationTTL: lo.ToPtr(2 * time.Second)})), node.Spec.ProviderID)
+		Expect(ExpectStateNodeExists(cluster, node).Nominated()).To(BeTrue())
+		time.Sleep(time.Second * 3) // past the 2s nomination ttl, node should no longer be nominated
+		Expect(ExpectStateNodeExists(cluster, node).Nominated()).To(BeFalse())
+	})
 	It("should handle a node changing from no providerID to registering a providerID", func() {
 		node := test.Node()
 		ExpectApplied(ctx, env.Client, node)
@@ -1127,7 +1248,78 @@ var _ = Describe("Pod Anti-Affinity", func() {
 	})
 })
 
+var _ = Describe("Bound Pods", func() {
+	var node *corev1.Node
+	BeforeEach(func() {
+		node = test.Node(test.NodeOptions{
+			ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+				v1.NodePoolLabelKey:            nodePool.Name,
+				corev1.LabelInstanceTypeStable: cloudProvider.InstanceTypes[0].Name,
+			}},
+			ProviderID: test.RandomProviderID(),
+		})
+	})
+	It("should track bound pods by namespace", func() {
+		namespace := test.RandomName()
+		pod1 := test.Pod()
+		pod2 := test.Pod(test.PodOptions{ObjectMeta: metav1.ObjectMeta{Namespace: namespace}})
+		ExpectApplied(ctx, env.Client, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}, pod1, pod2, node)
+		ExpectManualBinding(ctx, env.Client, pod1, node)
+		ExpectManualBinding(ctx, env.Client, pod2, node)
+		ExpectReconcileSucceeded(ctx, nodeController, client.ObjectKeyFromObject(node))
+		ExpectReconcileSucceeded(ctx, podController, client.ObjectKeyFromObject(pod1))
+		ExpectReconcileSucceeded(ctx, podController, client.ObjectKeyFromObject(pod2))
+
+		var found []string
+		cluster.ForPodsInNamespace(pod1.Namespace, func(p *corev1.Pod, n *corev1.Node) bool {
+			Expect(n.Name).To(Equal(node.Name))
+			found = append(found, p.Name)
+			return true
+		})
+		Expect(found).To(ConsistOf(pod1.Name))
+	})
+	It("should stop tracking pods once they are deleted", func() {
+		pod := test.Pod()
+		ExpectApplied(ctx, env.Client, pod, node)
+		ExpectManualBinding(ctx, env.Client, pod, node)
+		ExpectReconcileSucceeded(ctx, nodeController, client.ObjectKeyFromObject(node))
+		ExpectReconcileSucceeded(ctx, podController, client.ObjectKeyFromObject(pod))
+		ExpectDeleted(ctx, env.Client, pod)
+		ExpectReconcileSucceeded(ctx, podController, client.ObjectKeyFromObject(pod))
+
+		count := 0
+		cluster.ForPodsInNamespace(pod.Namespace, func(_ *corev1.Pod, _ *corev1.Node) bool {
+			count++
+			return true
+		})
+		Expect(count).To(Equal(0))
+	})
+	It("should not return pods bound to nodes that are no longer tracked", func() {
+		pod := test.Pod()
+		ExpectApplied(ctx, env.Client, pod, node)
+		ExpectManualBinding(ctx, env.Client, pod, node)
+		ExpectReconcileSucceeded(ctx, nodeController, client.ObjectKeyFromObject(node))
+		ExpectReconcileSucceeded(ctx, podController, client.ObjectKeyFromObject(pod))
+		cluster.DeleteNode(node.Name)
+
+		count := 0
+		cluster.ForPodsInNamespace(pod.Namespace, func(_ *corev1.Pod, _ *corev1.Node) bool {
+			count++
+			return true
+		})
+		Expect(count).To(Equal(0))
+	})
+})
+
 var _ = Describe("Cluster State Sync", func() {
+	It("should track the namespaces that exist in the apiserver once synced", func() {
+		ns := test.Namespace(test.NamespaceOptions{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"team": "a"}}})
+		ExpectApplied(ctx, env.Client, ns)
+		Expect(cluster.NamespacesMatching(labels.SelectorFromSet(map[string]string{"team": "a"})).Has(ns.Name)).To(BeFalse())
+
+		Expect(cluster.Synced(ctx)).To(BeTrue())
+		Expect(cluster.NamespacesMatching(labels.SelectorFromSet(map[string]string{"team": "a"})).Has(ns.Name)).To(BeTrue())
+	})
 	It("should consider the cluster state synced when all nodes are tracked", func() {
 		// Deploy 1000 nodes and sync them all with the cluster
 		for i := 0; i < 1000; i++ {
@@ -1463,6 +1655,39 @@ var _ = Describe("DaemonSet Controller", func() {
 	})
 })
 
+var _ = Describe("Namespace Controller", func() {
+	It("should track namespace labels", func() {
+		ns := test.Namespace(test.NamespaceOptions{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"team": "a"}}})
+		ExpectApplied(ctx, env.Client, ns)
+		ExpectReconcileSucceeded(ctx, namespaceController, client.ObjectKeyFromObject(ns))
+
+		Expect(cluster.NamespacesMatching(labels.SelectorFromSet(map[string]string{"team": "a"})).Has(ns.Name)).To(BeTrue())
+		Expect(cluster.NamespacesMatching(labels.SelectorFromSet(map[string]string{"team": "b"})).Has(ns.Name)).To(BeFalse())
+		Expect(cluster.NamespacesMatching(labels.Everything()).Has(ns.Name)).To(BeTrue())
+	})
+	It("should update namespace labels when they change", func() {
+		ns := test.Namespace(test.NamespaceOptions{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"team": "a"}}})
+		ExpectApplied(ctx, env.Client, ns)
+		ExpectReconcileSucceeded(ctx, namespaceController, client.ObjectKeyFromObject(ns))
+
+		ns.Labels["team"] = "b"
+		ExpectApplied(ctx, env.Client, ns)
+		ExpectReconcileSucceeded(ctx, namespaceController, client.ObjectKeyFromObject(ns))
+
+		Expect(cluster.NamespacesMatching(labels.SelectorFromSet(map[string]string{"team": "a"})).Has(ns.Name)).To(BeFalse())
+		Expect(cluster.NamespacesMatching(labels.SelectorFromSet(map[string]string{"team": "b"})).Has(ns.Name)).To(BeTrue())
+	})
+	It("should stop tracking a namespace when it is deleted", func() {
+		ns := test.Namespace(test.NamespaceOptions{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"team": "a"}}})
+		ExpectApplied(ctx, env.Client, ns)
+		ExpectReconcileSucceeded(ctx, namespaceController, client.ObjectKeyFromObject(ns))
+		Expect(cluster.NamespacesMatching(labels.Everything()).Has(ns.Name)).To(BeTrue())
+
+		cluster.DeleteNamespace(ns.Name)
+		Expect(cluster.NamespacesMatching(labels.Everything()).Has(ns.Name)).To(BeFalse())
+	})
+})
+
 var _ = Describe("Consolidated State", func() {
 	It("should update the consolidated value when setting consolidation", func() {
 		state := cluster.ConsolidationState()
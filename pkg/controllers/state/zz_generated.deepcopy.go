@@ -77,6 +77,22 @@ func (in *StateNode) DeepCopyInto(out *StateNode) {
 			(*out)[key] = outVal
 		}
 	}
+	if in.staticPods != nil {
+		in, out := &in.staticPods, &out.staticPods
+		*out = make(map[types.NamespacedName]*v1.Pod, len(*in))
+		for key, val := range *in {
+			var outVal *v1.Pod
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = new(v1.Pod)
+				(*in).DeepCopyInto(*out)
+			}
+			(*out)[key] = outVal
+		}
+	}
 	if in.podRequests != nil {
 		in, out := &in.podRequests, &out.podRequests
 		*out = make(map[types.NamespacedName]v1.ResourceList, len(*in))
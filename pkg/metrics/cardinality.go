@@ -0,0 +1,368 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package metrics
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	opmetrics "github.com/awslabs/operatorpkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// LabelAction is how the value of a high cardinality metric label is reduced before it's recorded
+type LabelAction string
+
+const (
+	// LabelActionDrop records the label with an empty value, so that all of its values are aggregated into one series
+	LabelActionDrop LabelAction = "drop"
+	// LabelActionHash records the label as one of LabelHashBuckets hashes of its value, so that its values are aggregated
+	// into a bounded number of series that still split them up
+	LabelActionHash LabelAction = "hash"
+
+	// AllMetricFamilies matches the labels of every metric family in a label policy
+	AllMetricFamilies = "*"
+
+	// LabelHashBuckets is the number of values that a hashed label is recorded with
+	LabelHashBuckets = 32
+)
+
+// LabelPolicies maps metric families to the actions that are taken on their labels. The policies of the
+// AllMetricFamilies key apply to every metric family, unless the family has its own policy for the same label.
+type LabelPolicies map[string]map[string]LabelAction
+
+var labelPolicies atomic.Pointer[LabelPolicies]
+
+// SetLabelPolicies sets the label policies that every metric created through this package applies when it's recorded
+func SetLabelPolicies(policies LabelPolicies) {
+	labelPolicies.Store(&policies)
+}
+
+// ParseLabelPolicies parses a comma separated list of label policies in the form <metric family>:<label>=<action>, e.g.
+// "karpenter_nodeclaims_created_total:nodepool=drop,*:instance_type=hash". The metric family * matches every metric family.
+func ParseLabelPolicies(str string) (LabelPolicies, error) {
+	policies := LabelPolicies{}
+	for _, entry := range strings.Split(str, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		family, rest, ok := strings.Cut(entry, ":")
+		label, action, ok2 := strings.Cut(rest, "=")
+		if !ok || !ok2 || family == "" || label == "" {
+			return nil, fmt.Errorf("invalid metric label policy %q, expected <metric family>:<label>=<action>", entry)
+		}
+		if a := LabelAction(action); a != LabelActionDrop && a != LabelActionHash {
+			return nil, fmt.Errorf("invalid metric label policy %q, action must be one of %q or %q", entry, LabelActionDrop, LabelActionHash)
+		}
+		if _, ok := policies[family]; !ok {
+			policies[family] = map[string]LabelAction{}
+		}
+		policies[family][label] = LabelAction(action)
+	}
+	return policies, nil
+}
+
+// apply returns the labels with the policies of the metric family applied, and whether any policy applied to them. The
+// labels are returned as is if no policy applies to them, so they mustn't be modified by the caller.
+func (p LabelPolicies) apply(family string, labels map[string]string) (map[string]string, bool) {
+	if len(p) == 0 {
+		return labels, false
+	}
+	var result map[string]string
+	for label, value := range labels {
+		action, ok := p[family][label]
+		if !ok {
+			if action, ok = p[AllMetricFamilies][label]; !ok {
+				continue
+			}
+		}
+		if result == nil {
+			result = make(map[string]string, len(labels))
+			for k, v := range labels {
+				result[k] = v
+			}
+		}
+		if action == LabelActionDrop {
+			result[label] = ""
+		} else {
+			result[label] = hashLabelValue(value)
+		}
+	}
+	if result == nil {
+		return labels, false
+	}
+	return result, true
+}
+
+// hashLabelValue returns the bucket that the label value hashes into, out of LabelHashBuckets. Empty values stay empty
+// so that unset labels are still recognizable.
+func hashLabelValue(value string) string {
+	if value == "" {
+		return ""
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(value))
+	return strconv.FormatUint(uint64(h.Sum32()%LabelHashBuckets), 10)
+}
+
+func applyLabelPolicies(family string, labels map[string]string) map[string]string {
+	labels, _ = applyLabelPoliciesOK(family, labels)
+	return labels
+}
+
+func applyLabelPoliciesOK(family string, labels map[string]string) (map[string]string, bool) {
+	if policies := labelPolicies.Load(); policies != nil {
+		return policies.apply(family, labels)
+	}
+	return labels, false
+}
+
+// Aggregation combines the values of the gauge series that label policies record as a single series
+type Aggregation func(values []float64) float64
+
+// AggregateSum records the sum of the aggregated series, e.g. for counts of objects
+func AggregateSum(values []float64) float64 {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum
+}
+
+// AggregateMin records the smallest of the aggregated series, e.g. for prices
+func AggregateMin(values []float64) float64 {
+	result := math.Inf(1)
+	for _, v := range values {
+		result = math.Min(result, v)
+	}
+	return result
+}
+
+// NewPrometheusCounter creates a counter that applies the label policies of its metric family when it's recorded
+func NewPrometheusCounter(registry prometheus.Registerer, opts prometheus.CounterOpts, labelNames []string) opmetrics.CounterMetric {
+	m := opmetrics.NewPrometheusCounter(registry, opts, labelNames)
+	return &counter{
+		CounterMetric: m,
+		Collector:     m.(prometheus.Collector),
+		family:        prometheus.BuildFQName(opts.Namespace, opts.Subsystem, opts.Name),
+	}
+}
+
+// NewPrometheusGauge creates a gauge that applies the label policies of its metric family when it's recorded. The
+// series that the label policies record as a single series are summed.
+func NewPrometheusGauge(registry prometheus.Registerer, opts prometheus.GaugeOpts, labelNames []string) opmetrics.GaugeMetric {
+	return NewPrometheusGaugeWithAggregation(registry, opts, labelNames, AggregateSum)
+}
+
+// NewPrometheusGaugeWithAggregation creates a gauge that applies the label policies of its metric family when it's
+// recorded, and combines the series that the label policies record as a single series with the aggregation. Unlike
+// counters, the series of gauges can't simply be recorded onto the same series, as they'd overwrite each other.
+func NewPrometheusGaugeWithAggregation(registry prometheus.Registerer, opts prometheus.GaugeOpts, labelNames []string, aggregate Aggregation) opmetrics.GaugeMetric {
+	m := opmetrics.NewPrometheusGauge(registry, opts, labelNames)
+	return &gauge{
+		GaugeMetric: m,
+		Collector:   m.(prometheus.Collector),
+		family:      prometheus.BuildFQName(opts.Namespace, opts.Subsystem, opts.Name),
+		aggregate:   aggregate,
+		series:      map[string]*aggregatedSeries{},
+	}
+}
+
+// NewPrometheusHistogram creates a histogram that applies the label policies of its metric family when it's recorded
+func NewPrometheusHistogram(registry prometheus.Registerer, opts prometheus.HistogramOpts, labelNames []string) opmetrics.ObservationMetric {
+	m := opmetrics.NewPrometheusHistogram(registry, opts, labelNames)
+	return &observation{
+		ObservationMetric: m,
+		Collector:         m.(prometheus.Collector),
+		family:            prometheus.BuildFQName(opts.Namespace, opts.Subsystem, opts.Name),
+	}
+}
+
+// NewPrometheusSummary creates a summary that applies the label policies of its metric family when it's recorded
+func NewPrometheusSummary(registry prometheus.Registerer, opts prometheus.SummaryOpts, labelNames []string) opmetrics.ObservationMetric {
+	m := opmetrics.NewPrometheusSummary(registry, opts, labelNames)
+	return &observation{
+		ObservationMetric: m,
+		Collector:         m.(prometheus.Collector),
+		family:            prometheus.BuildFQName(opts.Namespace, opts.Subsystem, opts.Name),
+	}
+}
+
+type counter struct {
+	opmetrics.CounterMetric
+	prometheus.Collector
+	family string
+}
+
+func (c *counter) Inc(labels map[string]string) {
+	c.CounterMetric.Inc(applyLabelPolicies(c.family, labels))
+}
+
+func (c *counter) Add(v float64, labels map[string]string) {
+	c.CounterMetric.Add(v, applyLabelPolicies(c.family, labels))
+}
+
+func (c *counter) Delete(labels map[string]string) {
+	c.CounterMetric.Delete(applyLabelPolicies(c.family, labels))
+}
+
+func (c *counter) DeletePartialMatch(labels map[string]string) {
+	c.CounterMetric.DeletePartialMatch(applyLabelPolicies(c.family, labels))
+}
+
+type gauge struct {
+	opmetrics.GaugeMetric
+	prometheus.Collector
+	family    string
+	aggregate Aggregation
+
+	mu sync.Mutex
+	// series are the series that label policies applied to, keyed by the labels that they're recorded with
+	series map[string]*aggregatedSeries
+}
+
+// aggregatedSeries is a series that's recorded as the aggregation of the values of every series that the label
+// policies recorded as it, keyed by their original labels
+type aggregatedSeries struct {
+	labels map[string]string
+	values map[string]recordedValue
+}
+
+type recordedValue struct {
+	labels map[string]string
+	value  float64
+}
+
+func (g *gauge) Set(v float64, labels map[string]string) {
+	applied, ok := applyLabelPoliciesOK(g.family, labels)
+	if !ok {
+		g.GaugeMetric.Set(v, labels)
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	key := labelsKey(applied)
+	s, ok := g.series[key]
+	if !ok {
+		s = &aggregatedSeries{labels: applied, values: map[string]recordedValue{}}
+		g.series[key] = s
+	}
+	s.values[labelsKey(labels)] = recordedValue{labels: labels, value: v}
+	g.record(key, s)
+}
+
+func (g *gauge) Delete(labels map[string]string) {
+	applied, ok := applyLabelPoliciesOK(g.family, labels)
+	if !ok {
+		g.GaugeMetric.Delete(labels)
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	key := labelsKey(applied)
+	if s, ok := g.series[key]; ok {
+		delete(s.values, labelsKey(labels))
+		g.record(key, s)
+	}
+}
+
+// DeletePartialMatch deletes the original series that match the labels from the series that they're aggregated into.
+// If no label policy applies to the labels, every series that matches them only aggregates series that match them as
+// well, so they're deleted as is.
+func (g *gauge) DeletePartialMatch(labels map[string]string) {
+	_, applies := applyLabelPoliciesOK(g.family, labels)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for key, s := range g.series {
+		deleted := false
+		for k, v := range s.values {
+			if matchesLabels(v.labels, labels) {
+				delete(s.values, k)
+				deleted = true
+			}
+		}
+		if deleted {
+			g.record(key, s)
+		}
+	}
+	if !applies {
+		g.GaugeMetric.DeletePartialMatch(labels)
+	}
+}
+
+func (g *gauge) Reset() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.series = map[string]*aggregatedSeries{}
+	g.GaugeMetric.Reset()
+}
+
+// record sets the series to the aggregation of its values, or deletes it once it has none
+func (g *gauge) record(key string, s *aggregatedSeries) {
+	if len(s.values) == 0 {
+		delete(g.series, key)
+		g.GaugeMetric.Delete(s.labels)
+		return
+	}
+	values := make([]float64, 0, len(s.values))
+	for _, v := range s.values {
+		values = append(values, v.value)
+	}
+	g.GaugeMetric.Set(g.aggregate(values), s.labels)
+}
+
+func labelsKey(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, strconv.Quote(k)+"="+strconv.Quote(v))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func matchesLabels(labels map[string]string, partial map[string]string) bool {
+	for k, v := range partial {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+type observation struct {
+	opmetrics.ObservationMetric
+	prometheus.Collector
+	family string
+}
+
+func (o *observation) Observe(v float64, labels map[string]string) {
+	o.ObservationMetric.Observe(v, applyLabelPolicies(o.family, labels))
+}
+
+func (o *observation) Delete(labels map[string]string) {
+	o.ObservationMetric.Delete(applyLabelPolicies(o.family, labels))
+}
+
+func (o *observation) DeletePartialMatch(labels map[string]string) {
+	o.ObservationMetric.DeletePartialMatch(applyLabelPolicies(o.family, labels))
+}
@@ -29,10 +29,13 @@ const (
 	NodePoolLabel     = "nodepool"
 	ReasonLabel       = "reason"
 	CapacityTypeLabel = "capacity_type"
+	PhaseLabel        = "phase"
 
 	// Reasons for CREATE/DELETE shared metrics
 	ProvisionedReason = "provisioned"
 	ExpiredReason     = "expired"
+	StaticReason      = "static"
+	MaxLifetimeReason = "max_lifetime"
 )
 
 // DurationBuckets returns a []float64 of default threshold values for duration histograms.
@@ -17,7 +17,6 @@ limitations under the License.
 package metrics
 
 import (
-	opmetrics "github.com/awslabs/operatorpkg/metrics"
 	"github.com/prometheus/client_golang/prometheus"
 	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 )
@@ -30,7 +29,7 @@ const (
 )
 
 var (
-	NodeClaimsCreatedTotal = opmetrics.NewPrometheusCounter(
+	NodeClaimsCreatedTotal = NewPrometheusCounter(
 		crmetrics.Registry,
 		prometheus.CounterOpts{
 			Namespace: Namespace,
@@ -44,7 +43,7 @@ var (
 			CapacityTypeLabel,
 		},
 	)
-	NodeClaimsTerminatedTotal = opmetrics.NewPrometheusCounter(
+	NodeClaimsTerminatedTotal = NewPrometheusCounter(
 		crmetrics.Registry,
 		prometheus.CounterOpts{
 			Namespace: Namespace,
@@ -57,7 +56,7 @@ var (
 			CapacityTypeLabel,
 		},
 	)
-	NodeClaimsDisruptedTotal = opmetrics.NewPrometheusCounter(
+	NodeClaimsDisruptedTotal = NewPrometheusCounter(
 		crmetrics.Registry,
 		prometheus.CounterOpts{
 			Namespace: Namespace,
@@ -71,7 +70,21 @@ var (
 			CapacityTypeLabel,
 		},
 	)
-	NodesCreatedTotal = opmetrics.NewPrometheusCounter(
+	NodeClaimsPhaseDurationSeconds = NewPrometheusHistogram(
+		crmetrics.Registry,
+		prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Subsystem: NodeClaimSubsystem,
+			Name:      "phase_duration_seconds",
+			Help:      "Duration of each lifecycle phase of nodeclaims in seconds. Launched is measured from creation, Registered from launch, Initialized from registration, Drained from when the node started draining and Terminated from when the node drained, or from the nodeclaim's deletion if it wasn't drained. Labeled by the phase and the owning nodepool.",
+			Buckets:   prometheus.ExponentialBuckets(1, 2, 12),
+		},
+		[]string{
+			PhaseLabel,
+			NodePoolLabel,
+		},
+	)
+	NodesCreatedTotal = NewPrometheusCounter(
 		crmetrics.Registry,
 		prometheus.CounterOpts{
 			Namespace: Namespace,
@@ -83,7 +96,7 @@ var (
 			NodePoolLabel,
 		},
 	)
-	NodesTerminatedTotal = opmetrics.NewPrometheusCounter(
+	NodesTerminatedTotal = NewPrometheusCounter(
 		crmetrics.Registry,
 		prometheus.CounterOpts{
 			Namespace: Namespace,
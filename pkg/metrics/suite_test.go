@@ -17,12 +17,14 @@ limitations under the License.
 package metrics_test
 
 import (
+	"fmt"
 	"testing"
 
 	opmetrics "github.com/awslabs/operatorpkg/metrics"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/prometheus/client_golang/prometheus"
+	prometheusmodel "github.com/prometheus/client_model/go"
 	"github.com/samber/lo"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
@@ -32,6 +34,9 @@ import (
 )
 
 var testGauge1, testGauge2 opmetrics.GaugeMetric
+var testCounter opmetrics.CounterMetric
+var testPolicyGauge opmetrics.GaugeMetric
+var testMinGauge opmetrics.GaugeMetric
 
 func TestAPIs(t *testing.T) {
 	RegisterFailHandler(Fail)
@@ -41,6 +46,9 @@ func TestAPIs(t *testing.T) {
 var _ = BeforeSuite(func() {
 	testGauge1 = opmetrics.NewPrometheusGauge(crmetrics.Registry, prometheus.GaugeOpts{Name: "test_gauge_1"}, []string{"label_1", "label_2"})
 	testGauge2 = opmetrics.NewPrometheusGauge(crmetrics.Registry, prometheus.GaugeOpts{Name: "test_gauge_2"}, []string{"label_1", "label_2"})
+	testCounter = metrics.NewPrometheusCounter(crmetrics.Registry, prometheus.CounterOpts{Namespace: "test", Name: "counter"}, []string{"nodepool", "instance_type"})
+	testPolicyGauge = metrics.NewPrometheusGauge(crmetrics.Registry, prometheus.GaugeOpts{Namespace: "test", Name: "gauge"}, []string{"nodepool", "instance_type"})
+	testMinGauge = metrics.NewPrometheusGaugeWithAggregation(crmetrics.Registry, prometheus.GaugeOpts{Namespace: "test", Name: "min_gauge"}, []string{"nodepool", "instance_type"}, metrics.AggregateMin)
 })
 
 var _ = Describe("Store", func() {
@@ -365,3 +373,107 @@ var _ = Describe("Store", func() {
 		})
 	})
 })
+
+var _ = Describe("LabelPolicies", func() {
+	AfterEach(func() {
+		metrics.SetLabelPolicies(nil)
+		testCounter.Reset()
+		testPolicyGauge.Reset()
+		testMinGauge.Reset()
+	})
+	Context("ParseLabelPolicies", func() {
+		It("should parse label policies", func() {
+			policies, err := metrics.ParseLabelPolicies("test_counter:nodepool=drop, *:instance_type=hash,")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(policies).To(Equal(metrics.LabelPolicies{
+				"test_counter": {"nodepool": metrics.LabelActionDrop},
+				"*":            {"instance_type": metrics.LabelActionHash},
+			}))
+		})
+		It("should parse an empty string", func() {
+			policies, err := metrics.ParseLabelPolicies("")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(policies).To(BeEmpty())
+		})
+		DescribeTable("should error with an invalid label policy",
+			func(str string) {
+				_, err := metrics.ParseLabelPolicies(str)
+				Expect(err).To(HaveOccurred())
+			},
+			Entry("missing metric family", "nodepool=drop"),
+			Entry("empty metric family", ":nodepool=drop"),
+			Entry("missing action", "*:nodepool"),
+			Entry("empty label", "*:=drop"),
+			Entry("unknown action", "*:nodepool=truncate"),
+		)
+	})
+	It("should record labels as is without a policy", func() {
+		testCounter.Inc(map[string]string{"nodepool": "default", "instance_type": "m5.large"})
+		ExpectMetricCounterValue(testCounter, 1, map[string]string{"nodepool": "default", "instance_type": "m5.large"})
+	})
+	It("should drop labels", func() {
+		metrics.SetLabelPolicies(lo.Must(metrics.ParseLabelPolicies("test_counter:nodepool=drop")))
+		testCounter.Inc(map[string]string{"nodepool": "default", "instance_type": "m5.large"})
+		testCounter.Inc(map[string]string{"nodepool": "other", "instance_type": "m5.large"})
+		ExpectMetricCounterValue(testCounter, 2, map[string]string{"nodepool": "", "instance_type": "m5.large"})
+	})
+	It("should hash labels", func() {
+		metrics.SetLabelPolicies(lo.Must(metrics.ParseLabelPolicies("*:instance_type=hash")))
+		testCounter.Inc(map[string]string{"nodepool": "default", "instance_type": "m5.large"})
+		ExpectMetricCounterValue(testCounter, 1, map[string]string{"nodepool": "default", "instance_type": "22"})
+	})
+	It("should prefer the policy of the metric family over the policy of every metric family", func() {
+		metrics.SetLabelPolicies(lo.Must(metrics.ParseLabelPolicies("*:nodepool=hash,test_counter:nodepool=drop")))
+		testCounter.Inc(map[string]string{"nodepool": "default", "instance_type": "m5.large"})
+		testPolicyGauge.Set(1, map[string]string{"nodepool": "default", "instance_type": "m5.large"})
+		ExpectMetricCounterValue(testCounter, 1, map[string]string{"nodepool": "", "instance_type": "m5.large"})
+		ExpectMetricGaugeValue(testPolicyGauge, 1, map[string]string{"nodepool": "30", "instance_type": "m5.large"})
+	})
+	It("should hash labels into a bounded number of values", func() {
+		metrics.SetLabelPolicies(lo.Must(metrics.ParseLabelPolicies("*:instance_type=hash")))
+		for i := 0; i < 1000; i++ {
+			testCounter.Inc(map[string]string{"nodepool": "default", "instance_type": fmt.Sprintf("instance-type-%d", i)})
+		}
+		families, err := crmetrics.Registry.Gather()
+		Expect(err).ToNot(HaveOccurred())
+		family, ok := lo.Find(families, func(f *prometheusmodel.MetricFamily) bool { return f.GetName() == "test_counter" })
+		Expect(ok).To(BeTrue())
+		Expect(family.GetMetric()).To(HaveLen(metrics.LabelHashBuckets))
+	})
+	It("should aggregate the series of gauges that have their labels dropped", func() {
+		metrics.SetLabelPolicies(lo.Must(metrics.ParseLabelPolicies("*:nodepool=drop")))
+		testPolicyGauge.Set(3, map[string]string{"nodepool": "default", "instance_type": "m5.large"})
+		testPolicyGauge.Set(2, map[string]string{"nodepool": "other", "instance_type": "m5.large"})
+		ExpectMetricGaugeValue(testPolicyGauge, 5, map[string]string{"nodepool": "", "instance_type": "m5.large"})
+
+		testPolicyGauge.Set(4, map[string]string{"nodepool": "default", "instance_type": "m5.large"})
+		ExpectMetricGaugeValue(testPolicyGauge, 6, map[string]string{"nodepool": "", "instance_type": "m5.large"})
+
+		testPolicyGauge.Delete(map[string]string{"nodepool": "default", "instance_type": "m5.large"})
+		ExpectMetricGaugeValue(testPolicyGauge, 2, map[string]string{"nodepool": "", "instance_type": "m5.large"})
+
+		testPolicyGauge.DeletePartialMatch(map[string]string{"instance_type": "m5.large"})
+		_, ok := FindMetricWithLabelValues("test_gauge", map[string]string{"instance_type": "m5.large"})
+		Expect(ok).To(BeFalse())
+	})
+	It("should only delete the series of gauges that match the dropped labels", func() {
+		metrics.SetLabelPolicies(lo.Must(metrics.ParseLabelPolicies("*:nodepool=drop")))
+		testPolicyGauge.Set(3, map[string]string{"nodepool": "default", "instance_type": "m5.large"})
+		testPolicyGauge.Set(2, map[string]string{"nodepool": "other", "instance_type": "m5.large"})
+		testPolicyGauge.DeletePartialMatch(map[string]string{"nodepool": "default"})
+		ExpectMetricGaugeValue(testPolicyGauge, 2, map[string]string{"nodepool": "", "instance_type": "m5.large"})
+	})
+	It("should aggregate the series of gauges with their aggregation", func() {
+		metrics.SetLabelPolicies(lo.Must(metrics.ParseLabelPolicies("*:nodepool=drop")))
+		testMinGauge.Set(3, map[string]string{"nodepool": "default", "instance_type": "m5.large"})
+		testMinGauge.Set(2, map[string]string{"nodepool": "other", "instance_type": "m5.large"})
+		ExpectMetricGaugeValue(testMinGauge, 2, map[string]string{"nodepool": "", "instance_type": "m5.large"})
+	})
+	It("should apply label policies when deleting metrics", func() {
+		metrics.SetLabelPolicies(lo.Must(metrics.ParseLabelPolicies("*:nodepool=hash")))
+		testPolicyGauge.Set(3, map[string]string{"nodepool": "default", "instance_type": "m5.large"})
+		testPolicyGauge.DeletePartialMatch(map[string]string{"nodepool": "default"})
+		_, ok := FindMetricWithLabelValues("test_gauge", map[string]string{"nodepool": "30"})
+		Expect(ok).To(BeFalse())
+	})
+})
@@ -67,7 +67,7 @@ const (
 )
 
 var (
-	BuildInfo = opmetrics.NewPrometheusGauge(
+	BuildInfo = metrics.NewPrometheusGauge(
 		crmetrics.Registry,
 		prometheus.GaugeOpts{
 			Namespace: metrics.Namespace,
@@ -116,6 +116,9 @@ func NewOperator() (context.Context, *Operator) {
 		debug.SetMemoryLimit(newLimit)
 	}
 
+	// Metrics
+	metrics.SetLabelPolicies(lo.Must(metrics.ParseLabelPolicies(options.FromContext(ctx).MetricLabelPolicies)))
+
 	// Logging
 	logger := zapr.NewLogger(logging.NewLogger(ctx, component))
 	log.SetLogger(logger)
@@ -262,7 +265,8 @@ func setupIndexers(ctx context.Context, mgr manager.Manager) {
 	handleCRDIndexerError(mgr.GetFieldIndexer().IndexField(ctx, &v1.NodePool{}, "spec.template.spec.nodeClassRef.kind", func(o client.Object) []string {
 		return []string{o.(*v1.NodePool).Spec.Template.Spec.NodeClassRef.Kind}
 	}), "failed to setup nodepool nodeclassref kind indexer")
+	// NodePools are also indexed by the names of their fallback NodeClasses, as all of their NodeClasses share a group and kind
 	handleCRDIndexerError(mgr.GetFieldIndexer().IndexField(ctx, &v1.NodePool{}, "spec.template.spec.nodeClassRef.name", func(o client.Object) []string {
-		return []string{o.(*v1.NodePool).Spec.Template.Spec.NodeClassRef.Name}
+		return lo.Map(o.(*v1.NodePool).Spec.Template.Spec.NodeClassRefs(), func(ref *v1.NodeClassReference, _ int) string { return ref.Name })
 	}), "failed to setup nodepool nodeclassref name indexer")
 }
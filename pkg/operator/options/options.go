@@ -21,17 +21,24 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"net/url"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/samber/lo"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation"
 	cliflag "k8s.io/component-base/cli/flag"
 
+	"sigs.k8s.io/karpenter/pkg/metrics"
 	"sigs.k8s.io/karpenter/pkg/utils/env"
+	"sigs.k8s.io/karpenter/pkg/utils/resources"
 )
 
 var (
-	validLogLevels = []string{"", "debug", "info", "error"}
+	validLogLevels        = []string{"", "debug", "info", "error"}
+	validPodPackingOrders = []string{"LargestFirst", "PriorityFirst", "FIFO"}
 
 	Injectables = []Injectable{&Options{}}
 )
@@ -41,28 +48,48 @@ type optionsKey struct{}
 type FeatureGates struct {
 	inputStr string
 
-	SpotToSpotConsolidation bool
-	NodeRepair              bool
+	SpotToSpotConsolidation      bool
+	NodeRepair                   bool
+	PreemptionAwareProvisioning  bool
+	StatefulSetAwareProvisioning bool
+	PodPreBinding                bool
+	NodePoolDeletionProtection   bool
 }
 
 // Options contains all CLI flags / env vars for karpenter-core. It adheres to the options.Injectable interface.
 type Options struct {
-	ServiceName             string
-	MetricsPort             int
-	HealthProbePort         int
-	KubeClientQPS           int
-	KubeClientBurst         int
-	EnableProfiling         bool
-	DisableLeaderElection   bool
-	LeaderElectionName      string
-	LeaderElectionNamespace string
-	MemoryLimit             int64
-	LogLevel                string
-	LogOutputPaths          string
-	LogErrorOutputPaths     string
-	BatchMaxDuration        time.Duration
-	BatchIdleDuration       time.Duration
-	FeatureGates            FeatureGates
+	ServiceName                  string
+	MetricsPort                  int
+	HealthProbePort              int
+	KubeClientQPS                int
+	KubeClientBurst              int
+	EnableProfiling              bool
+	EnableDryRunProvisioning     bool
+	EnableSchedulingSnapshots    bool
+	StrictNodePoolWeights        bool
+	StrictStartupTaints          bool
+	DisableLeaderElection        bool
+	LeaderElectionName           string
+	LeaderElectionNamespace      string
+	MemoryLimit                  int64
+	LogLevel                     string
+	LogOutputPaths               string
+	LogErrorOutputPaths          string
+	BatchMaxDuration             time.Duration
+	BatchIdleDuration            time.Duration
+	NominationTTL                time.Duration
+	PendingPodSLA                time.Duration
+	FragmentationThreshold       string
+	SchedulingExtenderURL        string
+	SchedulingExtenderDisruption bool
+	PodPackingOrder              string
+	ResourceAliases              string
+	ManagedBy                    string
+	GCInterval                   time.Duration
+	GCDryRun                     bool
+	NodeAnnotationAllowlist      string
+	MetricLabelPolicies          string
+	FeatureGates                 FeatureGates
 }
 
 type FlagSet struct {
@@ -89,6 +116,10 @@ func (o *Options) AddFlags(fs *FlagSet) {
 	fs.IntVar(&o.KubeClientQPS, "kube-client-qps", env.WithDefaultInt("KUBE_CLIENT_QPS", 200), "The smoothed rate of qps to kube-apiserver")
 	fs.IntVar(&o.KubeClientBurst, "kube-client-burst", env.WithDefaultInt("KUBE_CLIENT_BURST", 300), "The maximum allowed burst of queries to the kube-apiserver")
 	fs.BoolVarWithEnv(&o.EnableProfiling, "enable-profiling", "ENABLE_PROFILING", false, "Enable the profiling on the metric endpoint")
+	fs.BoolVarWithEnv(&o.EnableDryRunProvisioning, "enable-dry-run-provisioning", "ENABLE_DRY_RUN_PROVISIONING", false, "Enable the dry-run provisioning endpoint on the metrics server, which returns the NodeClaims that would be launched for the current pending pods without creating them")
+	fs.BoolVarWithEnv(&o.EnableSchedulingSnapshots, "enable-scheduling-snapshots", "ENABLE_SCHEDULING_SNAPSHOTS", false, "Record the existing nodes, launched NodeClaims, topology domains and pod scheduling failures of every provisioning loop in the SchedulingSnapshot named \"provisioner\", to debug why pods were scheduled the way that they were")
+	fs.BoolVarWithEnv(&o.StrictNodePoolWeights, "strict-nodepool-weights", "STRICT_NODEPOOL_WEIGHTS", false, "Only schedule pods to lower weight NodePools when none of the higher weight NodePools can satisfy them, e.g. because their limits are reached or their offerings are unavailable. By default, pods may be packed onto capacity that's already being launched for a lower weight NodePool.")
+	fs.BoolVarWithEnv(&o.StrictStartupTaints, "strict-startup-taints", "STRICT_STARTUP_TAINTS", false, "Only schedule pods to in-flight nodes if they tolerate the startup taints of the node. By default, startup taints are expected to be removed once the node initializes, so pods that don't tolerate them are still expected to schedule to in-flight nodes.")
 	fs.BoolVarWithEnv(&o.DisableLeaderElection, "disable-leader-election", "DISABLE_LEADER_ELECTION", false, "Disable the leader election client before executing the main loop. Disable when running replicated components for high availability is not desired.")
 	fs.StringVar(&o.LeaderElectionName, "leader-election-name", env.WithDefaultString("LEADER_ELECTION_NAME", "karpenter-leader-election"), "Leader election name to create and monitor the lease if running outside the cluster")
 	fs.StringVar(&o.LeaderElectionNamespace, "leader-election-namespace", env.WithDefaultString("LEADER_ELECTION_NAMESPACE", ""), "Leader election namespace to create and monitor the lease if running outside the cluster")
@@ -98,7 +129,19 @@ func (o *Options) AddFlags(fs *FlagSet) {
 	fs.StringVar(&o.LogErrorOutputPaths, "log-error-output-paths", env.WithDefaultString("LOG_ERROR_OUTPUT_PATHS", "stderr"), "Optional comma separated paths for logging error output")
 	fs.DurationVar(&o.BatchMaxDuration, "batch-max-duration", env.WithDefaultDuration("BATCH_MAX_DURATION", 10*time.Second), "The maximum length of a batch window. The longer this is, the more pods we can consider for provisioning at one time which usually results in fewer but larger nodes.")
 	fs.DurationVar(&o.BatchIdleDuration, "batch-idle-duration", env.WithDefaultDuration("BATCH_IDLE_DURATION", time.Second), "The maximum amount of time with no new pending pods that if exceeded ends the current batching window. If pods arrive faster than this time, the batching window will be extended up to the maxDuration. If they arrive slower, the pods will be batched separately.")
-	fs.StringVar(&o.FeatureGates.inputStr, "feature-gates", env.WithDefaultString("FEATURE_GATES", "NodeRepair=false,SpotToSpotConsolidation=false"), "Optional features can be enabled / disabled using feature gates. Current options are: SpotToSpotConsolidation")
+	fs.DurationVar(&o.NominationTTL, "nomination-ttl", env.WithDefaultDuration("NOMINATION_TTL", 0), "The amount of time that a node stays nominated after a provisioning pass expects a pending pod to bind to it. Nominated nodes aren't disrupted while the kube-scheduler binds the pod. If unset, this is twice the batch max duration with a minimum of 10 seconds. Increase this if the kube-scheduler is slow to bind pods in your cluster.")
+	fs.DurationVar(&o.PendingPodSLA, "pending-pod-sla", env.WithDefaultDuration("PENDING_POD_SLA", 0), "The amount of time that a pod can be pending before scheduling is escalated for it. Escalated pods have all of their soft scheduling constraints relaxed up front, and aren't restricted by the capacity type split of NodePools, so they can fall back to any capacity type that the NodePool allows. If unset, scheduling is never escalated.")
+	fs.StringVar(&o.FragmentationThreshold, "fragmentation-threshold", env.WithDefaultString("FRAGMENTATION_THRESHOLD", "0"), "The amount of cpu below which the cpu left unused on a new node is considered stranded. Instance types that would leave a non-zero amount of cpu below this threshold unused are avoided when other instance types can hold the same pods, reducing capacity that no pod can use. If 0, instance types aren't avoided for their unused cpu.")
+	fs.StringVar(&o.SchedulingExtenderURL, "scheduling-extender-url", env.WithDefaultString("SCHEDULING_EXTENDER_URL", ""), "The URL of an out-of-process scheduling extender that filters and scores the instance types of the NodeClaims that each provisioning loop launches. The extender is sent a JSON POST request for each loop, and provisioning fails if it can't be reached. If unset, no extender is called.")
+	fs.BoolVarWithEnv(&o.SchedulingExtenderDisruption, "scheduling-extender-disruption", "SCHEDULING_EXTENDER_DISRUPTION", false, "Also call the scheduling extender for the replacement NodeClaims of disruption's scheduling simulations, so that nodes are only consolidated onto instance types that the extender allows. As every candidate that disruption evaluates calls the extender, it's disabled by default.")
+	fs.StringVar(&o.PodPackingOrder, "pod-packing-order", env.WithDefaultString("POD_PACKING_ORDER", "LargestFirst"), "The order in which pending pods are packed onto nodes during scheduling. Can be one of 'LargestFirst', 'PriorityFirst', or 'FIFO'. LargestFirst packs pods with the largest cpu and memory requests first, PriorityFirst packs pods with the highest priority first, and FIFO packs the oldest pods first.")
+	fs.StringVar(&o.ResourceAliases, "resource-aliases", env.WithDefaultString("RESOURCE_ALIASES", ""), "Optional comma separated extended resources that are fractions of other resources, in the form <resource>=<target>/<count>. For example, nvidia.com/mig-1g.5gb=nvidia.com/gpu/7 treats each nvidia.com/mig-1g.5gb that a pod requests as a seventh of a nvidia.com/gpu when fitting the pod on new nodes, so that pods requesting MIG partitions and full GPUs binpack onto the GPUs that instance types advertise.")
+	fs.StringVar(&o.ManagedBy, "managed-by", env.WithDefaultString("MANAGED_BY", ""), "The value of the karpenter.sh/managed-by label of the NodePools that this instance of Karpenter manages, so that multiple instances can run in the same cluster. Each instance only provisions, disrupts and terminates the NodeClaims and Nodes of its own NodePools, which are labeled with the same value, and only provisions for the pods that require its value with a karpenter.sh/managed-by node selector or affinity. If unset, the instance manages NodePools without the label and the pods that don't require it.")
+	fs.DurationVar(&o.GCInterval, "gc-interval", env.WithDefaultDuration("GC_INTERVAL", 2*time.Minute), "The interval at which garbage collection deletes NodeClaims whose instances no longer exist, and finds cloud provider instances that have no NodeClaim.")
+	fs.BoolVarWithEnv(&o.GCDryRun, "gc-dry-run", "GC_DRY_RUN", true, "Only report the cloud provider instances that have no NodeClaim, through events, logs and the karpenter_nodeclaims_orphaned_instances metric, instead of deleting them. Disable to have garbage collection delete these instances.")
+	fs.StringVar(&o.NodeAnnotationAllowlist, "node-annotation-allowlist", env.WithDefaultString("NODE_ANNOTATION_ALLOWLIST", ""), "Optional comma separated annotation keys that are propagated from NodePools and NodeClaims onto their Nodes, and kept in sync as they change. Keys that end with * match every annotation key with that prefix, e.g. example.com/*. The annotations of a NodeClaim take precedence over those of its NodePool. If unset, no annotations are propagated other than those that NodeClaims have when their Nodes register.")
+	fs.StringVar(&o.MetricLabelPolicies, "metric-label-policies", env.WithDefaultString("METRIC_LABEL_POLICIES", ""), "Optional comma separated policies that reduce the cardinality of metric labels, in the form <metric family>:<label>=<action>, where the metric family is * to match every metric family. The drop action records the label with an empty value, aggregating its series into one, and the hash action records one of 32 hashes of the label value, aggregating its series into at most 32. Gauges record the sum of the series they aggregate, other than karpenter_cloudprovider_offering_price_estimate, which records the cheapest price. For example, *:instance_type=hash,karpenter_nodeclaims_created_total:nodepool=drop hashes the instance_type label of every metric and drops the nodepool label of karpenter_nodeclaims_created_total.")
+	fs.StringVar(&o.FeatureGates.inputStr, "feature-gates", env.WithDefaultString("FEATURE_GATES", "NodeRepair=false,SpotToSpotConsolidation=false,PreemptionAwareProvisioning=false,StatefulSetAwareProvisioning=false,PodPreBinding=false,NodePoolDeletionProtection=false"), "Optional features can be enabled / disabled using feature gates. Current options are: SpotToSpotConsolidation, NodeRepair, PreemptionAwareProvisioning, StatefulSetAwareProvisioning, PodPreBinding, NodePoolDeletionProtection")
 }
 
 func (o *Options) Parse(fs *FlagSet, args ...string) error {
@@ -112,6 +155,40 @@ func (o *Options) Parse(fs *FlagSet, args ...string) error {
 	if !lo.Contains(validLogLevels, o.LogLevel) {
 		return fmt.Errorf("validating cli flags / env vars, invalid LOG_LEVEL %q", o.LogLevel)
 	}
+	if !lo.Contains(validPodPackingOrders, o.PodPackingOrder) {
+		return fmt.Errorf("validating cli flags / env vars, invalid POD_PACKING_ORDER %q", o.PodPackingOrder)
+	}
+	if o.NominationTTL < 0 {
+		return fmt.Errorf("validating cli flags / env vars, NOMINATION_TTL %q must not be negative", o.NominationTTL)
+	}
+	if o.PendingPodSLA < 0 {
+		return fmt.Errorf("validating cli flags / env vars, PENDING_POD_SLA %q must not be negative", o.PendingPodSLA)
+	}
+	if threshold, err := resource.ParseQuantity(o.FragmentationThreshold); err != nil || threshold.Sign() < 0 {
+		return fmt.Errorf("validating cli flags / env vars, invalid FRAGMENTATION_THRESHOLD %q", o.FragmentationThreshold)
+	}
+	if o.SchedulingExtenderURL != "" {
+		if _, err := url.ParseRequestURI(o.SchedulingExtenderURL); err != nil {
+			return fmt.Errorf("validating cli flags / env vars, invalid SCHEDULING_EXTENDER_URL %q", o.SchedulingExtenderURL)
+		}
+	}
+	if _, err := resources.ParseAliases(o.ResourceAliases); err != nil {
+		return fmt.Errorf("validating cli flags / env vars, invalid RESOURCE_ALIASES %q, %w", o.ResourceAliases, err)
+	}
+	for _, key := range lo.Compact(strings.Split(o.NodeAnnotationAllowlist, ",")) {
+		if strings.TrimSuffix(strings.TrimSpace(key), "*") == "" {
+			return fmt.Errorf("validating cli flags / env vars, invalid NODE_ANNOTATION_ALLOWLIST %q", o.NodeAnnotationAllowlist)
+		}
+	}
+	if _, err := metrics.ParseLabelPolicies(o.MetricLabelPolicies); err != nil {
+		return fmt.Errorf("validating cli flags / env vars, invalid METRIC_LABEL_POLICIES %q, %w", o.MetricLabelPolicies, err)
+	}
+	if o.GCInterval <= 0 {
+		return fmt.Errorf("validating cli flags / env vars, GC_INTERVAL %q must be positive", o.GCInterval)
+	}
+	if errs := validation.IsValidLabelValue(o.ManagedBy); len(errs) != 0 {
+		return fmt.Errorf("validating cli flags / env vars, invalid MANAGED_BY %q, %s", o.ManagedBy, strings.Join(errs, ", "))
+	}
 	gates, err := ParseFeatureGates(o.FeatureGates.inputStr)
 	if err != nil {
 		return fmt.Errorf("parsing feature gates, %w", err)
@@ -139,6 +216,18 @@ func ParseFeatureGates(gateStr string) (FeatureGates, error) {
 	if val, ok := gateMap["SpotToSpotConsolidation"]; ok {
 		gates.SpotToSpotConsolidation = val
 	}
+	if val, ok := gateMap["PreemptionAwareProvisioning"]; ok {
+		gates.PreemptionAwareProvisioning = val
+	}
+	if val, ok := gateMap["StatefulSetAwareProvisioning"]; ok {
+		gates.StatefulSetAwareProvisioning = val
+	}
+	if val, ok := gateMap["PodPreBinding"]; ok {
+		gates.PodPreBinding = val
+	}
+	if val, ok := gateMap["NodePoolDeletionProtection"]; ok {
+		gates.NodePoolDeletionProtection = val
+	}
 
 	return gates, nil
 }
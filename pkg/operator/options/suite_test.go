@@ -50,6 +50,10 @@ var _ = Describe("Options", func() {
 		"KUBE_CLIENT_QPS",
 		"KUBE_CLIENT_BURST",
 		"ENABLE_PROFILING",
+		"ENABLE_DRY_RUN_PROVISIONING",
+		"ENABLE_SCHEDULING_SNAPSHOTS",
+		"STRICT_NODEPOOL_WEIGHTS",
+		"STRICT_STARTUP_TAINTS",
 		"DISABLE_LEADER_ELECTION",
 		"LEADER_ELECTION_NAMESPACE",
 		"MEMORY_LIMIT",
@@ -58,6 +62,17 @@ var _ = Describe("Options", func() {
 		"LOG_ERROR_OUTPUT_PATHS",
 		"BATCH_MAX_DURATION",
 		"BATCH_IDLE_DURATION",
+		"NOMINATION_TTL",
+		"PENDING_POD_SLA",
+		"FRAGMENTATION_THRESHOLD",
+		"SCHEDULING_EXTENDER_URL",
+		"SCHEDULING_EXTENDER_DISRUPTION",
+		"POD_PACKING_ORDER",
+		"MANAGED_BY",
+		"GC_INTERVAL",
+		"GC_DRY_RUN",
+		"NODE_ANNOTATION_ALLOWLIST",
+		"METRIC_LABEL_POLICIES",
 		"FEATURE_GATES",
 	}
 
@@ -95,24 +110,44 @@ var _ = Describe("Options", func() {
 			err := opts.Parse(fs)
 			Expect(err).To(BeNil())
 			expectOptionsMatch(opts, test.Options(test.OptionsFields{
-				ServiceName:             lo.ToPtr(""),
-				MetricsPort:             lo.ToPtr(8080),
-				HealthProbePort:         lo.ToPtr(8081),
-				KubeClientQPS:           lo.ToPtr(200),
-				KubeClientBurst:         lo.ToPtr(300),
-				EnableProfiling:         lo.ToPtr(false),
-				DisableLeaderElection:   lo.ToPtr(false),
-				LeaderElectionName:      lo.ToPtr("karpenter-leader-election"),
-				LeaderElectionNamespace: lo.ToPtr(""),
-				MemoryLimit:             lo.ToPtr[int64](-1),
-				LogLevel:                lo.ToPtr("info"),
-				LogOutputPaths:          lo.ToPtr("stdout"),
-				LogErrorOutputPaths:     lo.ToPtr("stderr"),
-				BatchMaxDuration:        lo.ToPtr(10 * time.Second),
-				BatchIdleDuration:       lo.ToPtr(time.Second),
+				ServiceName:                  lo.ToPtr(""),
+				MetricsPort:                  lo.ToPtr(8080),
+				HealthProbePort:              lo.ToPtr(8081),
+				KubeClientQPS:                lo.ToPtr(200),
+				KubeClientBurst:              lo.ToPtr(300),
+				EnableProfiling:              lo.ToPtr(false),
+				EnableDryRunProvisioning:     lo.ToPtr(false),
+				EnableSchedulingSnapshots:    lo.ToPtr(false),
+				StrictNodePoolWeights:        lo.ToPtr(false),
+				StrictStartupTaints:          lo.ToPtr(false),
+				DisableLeaderElection:        lo.ToPtr(false),
+				LeaderElectionName:           lo.ToPtr("karpenter-leader-election"),
+				LeaderElectionNamespace:      lo.ToPtr(""),
+				MemoryLimit:                  lo.ToPtr[int64](-1),
+				LogLevel:                     lo.ToPtr("info"),
+				LogOutputPaths:               lo.ToPtr("stdout"),
+				LogErrorOutputPaths:          lo.ToPtr("stderr"),
+				BatchMaxDuration:             lo.ToPtr(10 * time.Second),
+				BatchIdleDuration:            lo.ToPtr(time.Second),
+				NominationTTL:                lo.ToPtr(time.Duration(0)),
+				PendingPodSLA:                lo.ToPtr(time.Duration(0)),
+				FragmentationThreshold:       lo.ToPtr("0"),
+				SchedulingExtenderURL:        lo.ToPtr(""),
+				SchedulingExtenderDisruption: lo.ToPtr(false),
+				PodPackingOrder:              lo.ToPtr("LargestFirst"),
+				ResourceAliases:              lo.ToPtr(""),
+				ManagedBy:                    lo.ToPtr(""),
+				GCInterval:                   lo.ToPtr(2 * time.Minute),
+				GCDryRun:                     lo.ToPtr(true),
+				NodeAnnotationAllowlist:      lo.ToPtr(""),
+				MetricLabelPolicies:          lo.ToPtr(""),
 				FeatureGates: test.FeatureGates{
-					NodeRepair:              lo.ToPtr(false),
-					SpotToSpotConsolidation: lo.ToPtr(false),
+					NodeRepair:                   lo.ToPtr(false),
+					SpotToSpotConsolidation:      lo.ToPtr(false),
+					PreemptionAwareProvisioning:  lo.ToPtr(false),
+					StatefulSetAwareProvisioning: lo.ToPtr(false),
+					PodPreBinding:                lo.ToPtr(false),
+					NodePoolDeletionProtection:   lo.ToPtr(false),
 				},
 			}))
 		})
@@ -128,6 +163,10 @@ var _ = Describe("Options", func() {
 				"--kube-client-qps", "0",
 				"--kube-client-burst", "0",
 				"--enable-profiling",
+				"--enable-dry-run-provisioning",
+				"--enable-scheduling-snapshots",
+				"--strict-nodepool-weights",
+				"--strict-startup-taints",
 				"--disable-leader-election=true",
 				"--leader-election-name=karpenter-controller",
 				"--leader-election-namespace=karpenter",
@@ -137,28 +176,60 @@ var _ = Describe("Options", func() {
 				"--log-error-output-paths", "/etc/k8s/testerror",
 				"--batch-max-duration", "5s",
 				"--batch-idle-duration", "5s",
-				"--feature-gates", "SpotToSpotConsolidation=true,NodeRepair=true",
+				"--nomination-ttl", "30s",
+				"--pending-pod-sla", "5m",
+				"--fragmentation-threshold", "500m",
+				"--scheduling-extender-url", "http://extender.example.com/filter",
+				"--scheduling-extender-disruption",
+				"--pod-packing-order", "PriorityFirst",
+				"--resource-aliases", "nvidia.com/mig-1g.5gb=nvidia.com/gpu/7",
+				"--managed-by", "shard-a",
+				"--gc-interval", "5m",
+				"--gc-dry-run=false",
+				"--node-annotation-allowlist", "example.com/team",
+				"--metric-label-policies", "*:nodepool=hash",
+				"--feature-gates", "SpotToSpotConsolidation=true,NodeRepair=true,PreemptionAwareProvisioning=true,StatefulSetAwareProvisioning=true,PodPreBinding=true,NodePoolDeletionProtection=true",
 			)
 			Expect(err).To(BeNil())
 			expectOptionsMatch(opts, test.Options(test.OptionsFields{
-				ServiceName:             lo.ToPtr("cli"),
-				MetricsPort:             lo.ToPtr(0),
-				HealthProbePort:         lo.ToPtr(0),
-				KubeClientQPS:           lo.ToPtr(0),
-				KubeClientBurst:         lo.ToPtr(0),
-				EnableProfiling:         lo.ToPtr(true),
-				DisableLeaderElection:   lo.ToPtr(true),
-				LeaderElectionName:      lo.ToPtr("karpenter-controller"),
-				LeaderElectionNamespace: lo.ToPtr("karpenter"),
-				MemoryLimit:             lo.ToPtr[int64](0),
-				LogLevel:                lo.ToPtr("debug"),
-				LogOutputPaths:          lo.ToPtr("/etc/k8s/test"),
-				LogErrorOutputPaths:     lo.ToPtr("/etc/k8s/testerror"),
-				BatchMaxDuration:        lo.ToPtr(5 * time.Second),
-				BatchIdleDuration:       lo.ToPtr(5 * time.Second),
+				ServiceName:                  lo.ToPtr("cli"),
+				MetricsPort:                  lo.ToPtr(0),
+				HealthProbePort:              lo.ToPtr(0),
+				KubeClientQPS:                lo.ToPtr(0),
+				KubeClientBurst:              lo.ToPtr(0),
+				EnableProfiling:              lo.ToPtr(true),
+				EnableDryRunProvisioning:     lo.ToPtr(true),
+				EnableSchedulingSnapshots:    lo.ToPtr(true),
+				StrictNodePoolWeights:        lo.ToPtr(true),
+				StrictStartupTaints:          lo.ToPtr(true),
+				DisableLeaderElection:        lo.ToPtr(true),
+				LeaderElectionName:           lo.ToPtr("karpenter-controller"),
+				LeaderElectionNamespace:      lo.ToPtr("karpenter"),
+				MemoryLimit:                  lo.ToPtr[int64](0),
+				LogLevel:                     lo.ToPtr("debug"),
+				LogOutputPaths:               lo.ToPtr("/etc/k8s/test"),
+				LogErrorOutputPaths:          lo.ToPtr("/etc/k8s/testerror"),
+				BatchMaxDuration:             lo.ToPtr(5 * time.Second),
+				BatchIdleDuration:            lo.ToPtr(5 * time.Second),
+				NominationTTL:                lo.ToPtr(30 * time.Second),
+				PendingPodSLA:                lo.ToPtr(5 * time.Minute),
+				FragmentationThreshold:       lo.ToPtr("500m"),
+				SchedulingExtenderURL:        lo.ToPtr("http://extender.example.com/filter"),
+				SchedulingExtenderDisruption: lo.ToPtr(true),
+				PodPackingOrder:              lo.ToPtr("PriorityFirst"),
+				ResourceAliases:              lo.ToPtr("nvidia.com/mig-1g.5gb=nvidia.com/gpu/7"),
+				ManagedBy:                    lo.ToPtr("shard-a"),
+				GCInterval:                   lo.ToPtr(5 * time.Minute),
+				GCDryRun:                     lo.ToPtr(false),
+				NodeAnnotationAllowlist:      lo.ToPtr("example.com/team"),
+				MetricLabelPolicies:          lo.ToPtr("*:nodepool=hash"),
 				FeatureGates: test.FeatureGates{
-					NodeRepair:              lo.ToPtr(true),
-					SpotToSpotConsolidation: lo.ToPtr(true),
+					NodeRepair:                   lo.ToPtr(true),
+					SpotToSpotConsolidation:      lo.ToPtr(true),
+					PreemptionAwareProvisioning:  lo.ToPtr(true),
+					StatefulSetAwareProvisioning: lo.ToPtr(true),
+					PodPreBinding:                lo.ToPtr(true),
+					NodePoolDeletionProtection:   lo.ToPtr(true),
 				},
 			}))
 		})
@@ -170,6 +241,10 @@ var _ = Describe("Options", func() {
 			os.Setenv("KUBE_CLIENT_QPS", "0")
 			os.Setenv("KUBE_CLIENT_BURST", "0")
 			os.Setenv("ENABLE_PROFILING", "true")
+			os.Setenv("ENABLE_DRY_RUN_PROVISIONING", "true")
+			os.Setenv("ENABLE_SCHEDULING_SNAPSHOTS", "true")
+			os.Setenv("STRICT_NODEPOOL_WEIGHTS", "true")
+			os.Setenv("STRICT_STARTUP_TAINTS", "true")
 			os.Setenv("DISABLE_LEADER_ELECTION", "true")
 			os.Setenv("LEADER_ELECTION_NAME", "karpenter-controller")
 			os.Setenv("LEADER_ELECTION_NAMESPACE", "karpenter")
@@ -179,6 +254,18 @@ var _ = Describe("Options", func() {
 			os.Setenv("LOG_ERROR_OUTPUT_PATHS", "/etc/k8s/testerror")
 			os.Setenv("BATCH_MAX_DURATION", "5s")
 			os.Setenv("BATCH_IDLE_DURATION", "5s")
+			os.Setenv("NOMINATION_TTL", "30s")
+			os.Setenv("PENDING_POD_SLA", "5m")
+			os.Setenv("FRAGMENTATION_THRESHOLD", "500m")
+			os.Setenv("SCHEDULING_EXTENDER_URL", "http://extender.example.com/filter")
+			os.Setenv("SCHEDULING_EXTENDER_DISRUPTION", "true")
+			os.Setenv("POD_PACKING_ORDER", "FIFO")
+			os.Setenv("RESOURCE_ALIASES", "nvidia.com/mig-3g.20gb=nvidia.com/gpu/2")
+			os.Setenv("MANAGED_BY", "shard-b")
+			os.Setenv("GC_INTERVAL", "10m")
+			os.Setenv("GC_DRY_RUN", "false")
+			os.Setenv("NODE_ANNOTATION_ALLOWLIST", "example.com/*")
+			os.Setenv("METRIC_LABEL_POLICIES", "karpenter_nodeclaims_created_total:nodepool=drop")
 			os.Setenv("FEATURE_GATES", "SpotToSpotConsolidation=true,NodeRepair=true")
 			fs = &options.FlagSet{
 				FlagSet: flag.NewFlagSet("karpenter", flag.ContinueOnError),
@@ -187,21 +274,37 @@ var _ = Describe("Options", func() {
 			err := opts.Parse(fs)
 			Expect(err).To(BeNil())
 			expectOptionsMatch(opts, test.Options(test.OptionsFields{
-				ServiceName:             lo.ToPtr("env"),
-				MetricsPort:             lo.ToPtr(0),
-				HealthProbePort:         lo.ToPtr(0),
-				KubeClientQPS:           lo.ToPtr(0),
-				KubeClientBurst:         lo.ToPtr(0),
-				EnableProfiling:         lo.ToPtr(true),
-				DisableLeaderElection:   lo.ToPtr(true),
-				LeaderElectionName:      lo.ToPtr("karpenter-controller"),
-				LeaderElectionNamespace: lo.ToPtr("karpenter"),
-				MemoryLimit:             lo.ToPtr[int64](0),
-				LogLevel:                lo.ToPtr("debug"),
-				LogOutputPaths:          lo.ToPtr("/etc/k8s/test"),
-				LogErrorOutputPaths:     lo.ToPtr("/etc/k8s/testerror"),
-				BatchMaxDuration:        lo.ToPtr(5 * time.Second),
-				BatchIdleDuration:       lo.ToPtr(5 * time.Second),
+				ServiceName:                  lo.ToPtr("env"),
+				MetricsPort:                  lo.ToPtr(0),
+				HealthProbePort:              lo.ToPtr(0),
+				KubeClientQPS:                lo.ToPtr(0),
+				KubeClientBurst:              lo.ToPtr(0),
+				EnableProfiling:              lo.ToPtr(true),
+				EnableDryRunProvisioning:     lo.ToPtr(true),
+				EnableSchedulingSnapshots:    lo.ToPtr(true),
+				StrictNodePoolWeights:        lo.ToPtr(true),
+				StrictStartupTaints:          lo.ToPtr(true),
+				DisableLeaderElection:        lo.ToPtr(true),
+				LeaderElectionName:           lo.ToPtr("karpenter-controller"),
+				LeaderElectionNamespace:      lo.ToPtr("karpenter"),
+				MemoryLimit:                  lo.ToPtr[int64](0),
+				LogLevel:                     lo.ToPtr("debug"),
+				LogOutputPaths:               lo.ToPtr("/etc/k8s/test"),
+				LogErrorOutputPaths:          lo.ToPtr("/etc/k8s/testerror"),
+				BatchMaxDuration:             lo.ToPtr(5 * time.Second),
+				BatchIdleDuration:            lo.ToPtr(5 * time.Second),
+				NominationTTL:                lo.ToPtr(30 * time.Second),
+				PendingPodSLA:                lo.ToPtr(5 * time.Minute),
+				FragmentationThreshold:       lo.ToPtr("500m"),
+				SchedulingExtenderURL:        lo.ToPtr("http://extender.example.com/filter"),
+				SchedulingExtenderDisruption: lo.ToPtr(true),
+				PodPackingOrder:              lo.ToPtr("FIFO"),
+				ResourceAliases:              lo.ToPtr("nvidia.com/mig-3g.20gb=nvidia.com/gpu/2"),
+				ManagedBy:                    lo.ToPtr("shard-b"),
+				GCInterval:                   lo.ToPtr(10 * time.Minute),
+				GCDryRun:                     lo.ToPtr(false),
+				NodeAnnotationAllowlist:      lo.ToPtr("example.com/*"),
+				MetricLabelPolicies:          lo.ToPtr("karpenter_nodeclaims_created_total:nodepool=drop"),
 				FeatureGates: test.FeatureGates{
 					NodeRepair:              lo.ToPtr(true),
 					SpotToSpotConsolidation: lo.ToPtr(true),
@@ -215,11 +318,27 @@ var _ = Describe("Options", func() {
 			os.Setenv("KUBE_CLIENT_QPS", "0")
 			os.Setenv("KUBE_CLIENT_BURST", "0")
 			os.Setenv("ENABLE_PROFILING", "true")
+			os.Setenv("ENABLE_DRY_RUN_PROVISIONING", "true")
+			os.Setenv("ENABLE_SCHEDULING_SNAPSHOTS", "true")
+			os.Setenv("STRICT_NODEPOOL_WEIGHTS", "true")
+			os.Setenv("STRICT_STARTUP_TAINTS", "true")
 			os.Setenv("DISABLE_LEADER_ELECTION", "true")
 			os.Setenv("MEMORY_LIMIT", "0")
 			os.Setenv("LOG_LEVEL", "debug")
 			os.Setenv("BATCH_MAX_DURATION", "5s")
 			os.Setenv("BATCH_IDLE_DURATION", "5s")
+			os.Setenv("NOMINATION_TTL", "30s")
+			os.Setenv("PENDING_POD_SLA", "5m")
+			os.Setenv("FRAGMENTATION_THRESHOLD", "500m")
+			os.Setenv("SCHEDULING_EXTENDER_URL", "http://extender.example.com/filter")
+			os.Setenv("SCHEDULING_EXTENDER_DISRUPTION", "true")
+			os.Setenv("POD_PACKING_ORDER", "FIFO")
+			os.Setenv("RESOURCE_ALIASES", "nvidia.com/mig-3g.20gb=nvidia.com/gpu/2")
+			os.Setenv("MANAGED_BY", "shard-b")
+			os.Setenv("GC_INTERVAL", "10m")
+			os.Setenv("GC_DRY_RUN", "false")
+			os.Setenv("NODE_ANNOTATION_ALLOWLIST", "example.com/*")
+			os.Setenv("METRIC_LABEL_POLICIES", "karpenter_nodeclaims_created_total:nodepool=drop")
 			os.Setenv("FEATURE_GATES", "SpotToSpotConsolidation=true,NodeRepair=true")
 			fs = &options.FlagSet{
 				FlagSet: flag.NewFlagSet("karpenter", flag.ContinueOnError),
@@ -233,21 +352,37 @@ var _ = Describe("Options", func() {
 			)
 			Expect(err).To(BeNil())
 			expectOptionsMatch(opts, test.Options(test.OptionsFields{
-				ServiceName:             lo.ToPtr("cli"),
-				MetricsPort:             lo.ToPtr(0),
-				HealthProbePort:         lo.ToPtr(0),
-				KubeClientQPS:           lo.ToPtr(0),
-				KubeClientBurst:         lo.ToPtr(0),
-				EnableProfiling:         lo.ToPtr(true),
-				DisableLeaderElection:   lo.ToPtr(true),
-				LeaderElectionName:      lo.ToPtr("karpenter-leader-election"),
-				LeaderElectionNamespace: lo.ToPtr(""),
-				MemoryLimit:             lo.ToPtr[int64](0),
-				LogLevel:                lo.ToPtr("debug"),
-				LogOutputPaths:          lo.ToPtr("/etc/k8s/test"),
-				LogErrorOutputPaths:     lo.ToPtr("/etc/k8s/testerror"),
-				BatchMaxDuration:        lo.ToPtr(5 * time.Second),
-				BatchIdleDuration:       lo.ToPtr(5 * time.Second),
+				ServiceName:                  lo.ToPtr("cli"),
+				MetricsPort:                  lo.ToPtr(0),
+				HealthProbePort:              lo.ToPtr(0),
+				KubeClientQPS:                lo.ToPtr(0),
+				KubeClientBurst:              lo.ToPtr(0),
+				EnableProfiling:              lo.ToPtr(true),
+				EnableDryRunProvisioning:     lo.ToPtr(true),
+				EnableSchedulingSnapshots:    lo.ToPtr(true),
+				StrictNodePoolWeights:        lo.ToPtr(true),
+				StrictStartupTaints:          lo.ToPtr(true),
+				DisableLeaderElection:        lo.ToPtr(true),
+				LeaderElectionName:           lo.ToPtr("karpenter-leader-election"),
+				LeaderElectionNamespace:      lo.ToPtr(""),
+				MemoryLimit:                  lo.ToPtr[int64](0),
+				LogLevel:                     lo.ToPtr("debug"),
+				LogOutputPaths:               lo.ToPtr("/etc/k8s/test"),
+				LogErrorOutputPaths:          lo.ToPtr("/etc/k8s/testerror"),
+				BatchMaxDuration:             lo.ToPtr(5 * time.Second),
+				BatchIdleDuration:            lo.ToPtr(5 * time.Second),
+				NominationTTL:                lo.ToPtr(30 * time.Second),
+				PendingPodSLA:                lo.ToPtr(5 * time.Minute),
+				FragmentationThreshold:       lo.ToPtr("500m"),
+				SchedulingExtenderURL:        lo.ToPtr("http://extender.example.com/filter"),
+				SchedulingExtenderDisruption: lo.ToPtr(true),
+				PodPackingOrder:              lo.ToPtr("FIFO"),
+				ResourceAliases:              lo.ToPtr("nvidia.com/mig-3g.20gb=nvidia.com/gpu/2"),
+				ManagedBy:                    lo.ToPtr("shard-b"),
+				GCInterval:                   lo.ToPtr(10 * time.Minute),
+				GCDryRun:                     lo.ToPtr(false),
+				NodeAnnotationAllowlist:      lo.ToPtr("example.com/*"),
+				MetricLabelPolicies:          lo.ToPtr("karpenter_nodeclaims_created_total:nodepool=drop"),
 				FeatureGates: test.FeatureGates{
 					NodeRepair:              lo.ToPtr(true),
 					SpotToSpotConsolidation: lo.ToPtr(true),
@@ -281,6 +416,50 @@ var _ = Describe("Options", func() {
 			err := opts.Parse(fs, "--log-level", "hello")
 			Expect(err).ToNot(BeNil())
 		})
+		It("should error with a negative nomination ttl", func() {
+			err := opts.Parse(fs, "--nomination-ttl", "-1s")
+			Expect(err).ToNot(BeNil())
+		})
+		It("should error with a negative pending pod sla", func() {
+			err := opts.Parse(fs, "--pending-pod-sla", "-1s")
+			Expect(err).ToNot(BeNil())
+		})
+		It("should error with an invalid fragmentation threshold", func() {
+			err := opts.Parse(fs, "--fragmentation-threshold", "half")
+			Expect(err).ToNot(BeNil())
+		})
+		It("should error with a negative fragmentation threshold", func() {
+			err := opts.Parse(fs, "--fragmentation-threshold", "-500m")
+			Expect(err).ToNot(BeNil())
+		})
+		It("should error with an invalid scheduling extender url", func() {
+			err := opts.Parse(fs, "--scheduling-extender-url", "extender")
+			Expect(err).ToNot(BeNil())
+		})
+		It("should error with an invalid pod packing order", func() {
+			err := opts.Parse(fs, "--pod-packing-order", "SmallestFirst")
+			Expect(err).ToNot(BeNil())
+		})
+		It("should error with an invalid resource alias", func() {
+			err := opts.Parse(fs, "--resource-aliases", "nvidia.com/mig-1g.5gb=nvidia.com/gpu")
+			Expect(err).ToNot(BeNil())
+		})
+		It("should error with an invalid node annotation allowlist", func() {
+			err := opts.Parse(fs, "--node-annotation-allowlist", "example.com/team,*")
+			Expect(err).ToNot(BeNil())
+		})
+		It("should error with an invalid metric label policy", func() {
+			err := opts.Parse(fs, "--metric-label-policies", "*:nodepool=truncate")
+			Expect(err).ToNot(BeNil())
+		})
+		It("should error with a non-positive gc interval", func() {
+			err := opts.Parse(fs, "--gc-interval", "0s")
+			Expect(err).ToNot(BeNil())
+		})
+		It("should error with an invalid managed-by label value", func() {
+			err := opts.Parse(fs, "--managed-by", "shard a")
+			Expect(err).ToNot(BeNil())
+		})
 	})
 })
 
@@ -297,6 +476,10 @@ func expectOptionsMatch(optsA, optsB *options.Options) {
 	Expect(optsA.KubeClientQPS).To(Equal(optsB.KubeClientQPS))
 	Expect(optsA.KubeClientBurst).To(Equal(optsB.KubeClientBurst))
 	Expect(optsA.EnableProfiling).To(Equal(optsB.EnableProfiling))
+	Expect(optsA.EnableDryRunProvisioning).To(Equal(optsB.EnableDryRunProvisioning))
+	Expect(optsA.EnableSchedulingSnapshots).To(Equal(optsB.EnableSchedulingSnapshots))
+	Expect(optsA.StrictNodePoolWeights).To(Equal(optsB.StrictNodePoolWeights))
+	Expect(optsA.StrictStartupTaints).To(Equal(optsB.StrictStartupTaints))
 	Expect(optsA.DisableLeaderElection).To(Equal(optsB.DisableLeaderElection))
 	Expect(optsA.MemoryLimit).To(Equal(optsB.MemoryLimit))
 	Expect(optsA.LogLevel).To(Equal(optsB.LogLevel))
@@ -304,5 +487,22 @@ func expectOptionsMatch(optsA, optsB *options.Options) {
 	Expect(optsA.LogErrorOutputPaths).To(Equal(optsB.LogErrorOutputPaths))
 	Expect(optsA.BatchMaxDuration).To(Equal(optsB.BatchMaxDuration))
 	Expect(optsA.BatchIdleDuration).To(Equal(optsB.BatchIdleDuration))
+	Expect(optsA.NominationTTL).To(Equal(optsB.NominationTTL))
+	Expect(optsA.PendingPodSLA).To(Equal(optsB.PendingPodSLA))
+	Expect(optsA.FragmentationThreshold).To(Equal(optsB.FragmentationThreshold))
+	Expect(optsA.SchedulingExtenderURL).To(Equal(optsB.SchedulingExtenderURL))
+	Expect(optsA.SchedulingExtenderDisruption).To(Equal(optsB.SchedulingExtenderDisruption))
+	Expect(optsA.PodPackingOrder).To(Equal(optsB.PodPackingOrder))
+	Expect(optsA.ResourceAliases).To(Equal(optsB.ResourceAliases))
+	Expect(optsA.ManagedBy).To(Equal(optsB.ManagedBy))
+	Expect(optsA.GCInterval).To(Equal(optsB.GCInterval))
+	Expect(optsA.GCDryRun).To(Equal(optsB.GCDryRun))
+	Expect(optsA.NodeAnnotationAllowlist).To(Equal(optsB.NodeAnnotationAllowlist))
+	Expect(optsA.MetricLabelPolicies).To(Equal(optsB.MetricLabelPolicies))
 	Expect(optsA.FeatureGates.SpotToSpotConsolidation).To(Equal(optsB.FeatureGates.SpotToSpotConsolidation))
+	Expect(optsA.FeatureGates.NodeRepair).To(Equal(optsB.FeatureGates.NodeRepair))
+	Expect(optsA.FeatureGates.PreemptionAwareProvisioning).To(Equal(optsB.FeatureGates.PreemptionAwareProvisioning))
+	Expect(optsA.FeatureGates.StatefulSetAwareProvisioning).To(Equal(optsB.FeatureGates.StatefulSetAwareProvisioning))
+	Expect(optsA.FeatureGates.PodPreBinding).To(Equal(optsB.FeatureGates.PodPreBinding))
+	Expect(optsA.FeatureGates.NodePoolDeletionProtection).To(Equal(optsB.FeatureGates.NodePoolDeletionProtection))
 }
@@ -89,11 +89,19 @@ func (u *HostPortUsage) DeletePod(key types.NamespacedName) {
 	delete(u.reserved, key)
 }
 
+// GetHostPorts returns the ports on the node that the pod listens on. Pods using the host network listen on the node
+// with their container ports, so their container ports are treated as host ports.
 func GetHostPorts(pod *v1.Pod) []HostPort {
 	var usage []HostPort
 	for _, c := range pod.Spec.Containers {
 		for _, p := range c.Ports {
-			if p.HostPort == 0 {
+			// The API server defaults the host port to the container port for pods using the host network, but pods that
+			// we simulate may not have been defaulted
+			hostPort := p.HostPort
+			if hostPort == 0 && pod.Spec.HostNetwork {
+				hostPort = p.ContainerPort
+			}
+			if hostPort == 0 {
 				continue
 			}
 			// Per the K8s docs, "If you don't specify the hostIP and Protocol explicitly, Kubernetes will use 0.0.0.0
@@ -103,10 +111,14 @@ func GetHostPorts(pod *v1.Pod) []HostPort {
 			if hostIP == "" {
 				hostIP = "0.0.0.0"
 			}
+			protocol := p.Protocol
+			if protocol == "" {
+				protocol = v1.ProtocolTCP
+			}
 			usage = append(usage, HostPort{
 				IP:       net.ParseIP(hostIP),
-				Port:     p.HostPort,
-				Protocol: p.Protocol,
+				Port:     hostPort,
+				Protocol: protocol,
 			})
 		}
 	}
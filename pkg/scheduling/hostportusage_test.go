@@ -100,4 +100,34 @@ var _ = Describe("HostPortUsage", func() {
 			Expect(e2.Matches(e1)).To(BeFalse())
 		})
 	})
+	Context("GetHostPorts", func() {
+		It("should return the host ports of the pod", func() {
+			pod := &v1.Pod{Spec: v1.PodSpec{Containers: []v1.Container{{
+				Ports: []v1.ContainerPort{{ContainerPort: 8080, HostPort: 80}, {ContainerPort: 9090}},
+			}}}}
+			Expect(GetHostPorts(pod)).To(ConsistOf(HostPort{IP: net.IPv4zero, Port: 80, Protocol: v1.ProtocolTCP}))
+		})
+		It("should return the container ports of a pod using the host network", func() {
+			pod := &v1.Pod{Spec: v1.PodSpec{HostNetwork: true, Containers: []v1.Container{{
+				Ports: []v1.ContainerPort{{ContainerPort: 8080}, {ContainerPort: 53, Protocol: v1.ProtocolUDP}},
+			}}}}
+			Expect(GetHostPorts(pod)).To(ConsistOf(
+				HostPort{IP: net.IPv4zero, Port: 8080, Protocol: v1.ProtocolTCP},
+				HostPort{IP: net.IPv4zero, Port: 53, Protocol: v1.ProtocolUDP},
+			))
+		})
+		It("should conflict with a host port of another pod using the host network", func() {
+			hostNetwork := &v1.Pod{Spec: v1.PodSpec{HostNetwork: true, Containers: []v1.Container{{
+				Ports: []v1.ContainerPort{{ContainerPort: 8080}},
+			}}}}
+			hostNetwork.Name = "host-network"
+			hostPort := &v1.Pod{Spec: v1.PodSpec{Containers: []v1.Container{{
+				Ports: []v1.ContainerPort{{ContainerPort: 80, HostPort: 8080, Protocol: v1.ProtocolTCP}},
+			}}}}
+			hostPort.Name = "host-port"
+			usage := NewHostPortUsage()
+			usage.Add(hostNetwork, GetHostPorts(hostNetwork))
+			Expect(usage.Conflicts(hostPort, GetHostPorts(hostPort))).ToNot(Succeed())
+		})
+	})
 })
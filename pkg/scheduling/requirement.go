@@ -24,7 +24,6 @@ import (
 
 	"github.com/samber/lo"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/util/sets"
 
 	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
 )
@@ -33,7 +32,7 @@ import (
 type Requirement struct {
 	Key         string
 	complement  bool
-	values      sets.Set[string]
+	values      valueSet
 	greaterThan *int
 	lessThan    *int
 	MinValues   *int
@@ -48,13 +47,9 @@ func NewRequirementWithFlexibility(key string, operator corev1.NodeSelectorOpera
 
 	// This is a super-common case, so optimize for it an inline everything.
 	if operator == corev1.NodeSelectorOpIn {
-		s := make(sets.Set[string], len(values))
-		for _, value := range values {
-			s[value] = sets.Empty{}
-		}
 		return &Requirement{
 			Key:        key,
-			values:     s,
+			values:     newValueSet(values...),
 			complement: false,
 			MinValues:  minValues,
 		}
@@ -62,7 +57,6 @@ func NewRequirementWithFlexibility(key string, operator corev1.NodeSelectorOpera
 
 	r := &Requirement{
 		Key:        key,
-		values:     sets.New[string](),
 		complement: true,
 		MinValues:  minValues,
 	}
@@ -70,7 +64,7 @@ func NewRequirementWithFlexibility(key string, operator corev1.NodeSelectorOpera
 		r.complement = false
 	}
 	if operator == corev1.NodeSelectorOpIn || operator == corev1.NodeSelectorOpNotIn {
-		r.values.Insert(values...)
+		r.values = newValueSet(values...)
 	}
 	if operator == corev1.NodeSelectorOpGt {
 		value, _ := strconv.Atoi(values[0]) // prevalidated
@@ -109,12 +103,12 @@ func (r *Requirement) NodeSelectorRequirement() v1.NodeSelectorRequirementWithMi
 		}
 	case r.complement:
 		switch {
-		case len(r.values) > 0:
+		case r.values.Len() > 0:
 			return v1.NodeSelectorRequirementWithMinValues{
 				NodeSelectorRequirement: corev1.NodeSelectorRequirement{
 					Key:      r.Key,
 					Operator: corev1.NodeSelectorOpNotIn,
-					Values:   sets.List(r.values),
+					Values:   r.values.List(),
 				},
 				MinValues: r.MinValues,
 			}
@@ -129,12 +123,12 @@ func (r *Requirement) NodeSelectorRequirement() v1.NodeSelectorRequirementWithMi
 		}
 	default:
 		switch {
-		case len(r.values) > 0:
+		case r.values.Len() > 0:
 			return v1.NodeSelectorRequirementWithMinValues{
 				NodeSelectorRequirement: corev1.NodeSelectorRequirement{
 					Key:      r.Key,
 					Operator: corev1.NodeSelectorOpIn,
-					Values:   sets.List(r.values),
+					Values:   r.values.List(),
 				},
 				MinValues: r.MinValues,
 			}
@@ -150,6 +144,47 @@ func (r *Requirement) NodeSelectorRequirement() v1.NodeSelectorRequirementWithMi
 	}
 }
 
+// NodeSelectorRequirements returns the NodeSelectorRequirements that are needed to fully represent the requirement.
+// Unlike NodeSelectorRequirement, a requirement with both Gt and Lt bounds or with excluded values and bounds is
+// represented without losing any of its constraints.
+func (r *Requirement) NodeSelectorRequirements() []v1.NodeSelectorRequirementWithMinValues {
+	if r.greaterThan == nil && r.lessThan == nil {
+		return []v1.NodeSelectorRequirementWithMinValues{r.NodeSelectorRequirement()}
+	}
+	var requirements []v1.NodeSelectorRequirementWithMinValues
+	if r.greaterThan != nil {
+		requirements = append(requirements, v1.NodeSelectorRequirementWithMinValues{
+			NodeSelectorRequirement: corev1.NodeSelectorRequirement{
+				Key:      r.Key,
+				Operator: corev1.NodeSelectorOpGt,
+				Values:   []string{strconv.FormatInt(int64(lo.FromPtr(r.greaterThan)), 10)},
+			},
+			MinValues: r.MinValues,
+		})
+	}
+	if r.lessThan != nil {
+		requirements = append(requirements, v1.NodeSelectorRequirementWithMinValues{
+			NodeSelectorRequirement: corev1.NodeSelectorRequirement{
+				Key:      r.Key,
+				Operator: corev1.NodeSelectorOpLt,
+				Values:   []string{strconv.FormatInt(int64(lo.FromPtr(r.lessThan)), 10)},
+			},
+			MinValues: r.MinValues,
+		})
+	}
+	if r.complement && !r.values.empty() {
+		requirements = append(requirements, v1.NodeSelectorRequirementWithMinValues{
+			NodeSelectorRequirement: corev1.NodeSelectorRequirement{
+				Key:      r.Key,
+				Operator: corev1.NodeSelectorOpNotIn,
+				Values:   r.values.List(),
+			},
+			MinValues: r.MinValues,
+		})
+	}
+	return requirements
+}
+
 // Intersection constraints the Requirement from the incoming requirements
 // nolint:gocyclo
 func (r *Requirement) Intersection(requirement *Requirement) *Requirement {
@@ -160,12 +195,13 @@ func (r *Requirement) Intersection(requirement *Requirement) *Requirement {
 	greaterThan := maxIntPtr(r.greaterThan, requirement.greaterThan)
 	lessThan := minIntPtr(r.lessThan, requirement.lessThan)
 	minValues := maxIntPtr(r.MinValues, requirement.MinValues)
-	if greaterThan != nil && lessThan != nil && *greaterThan >= *lessThan {
+	// Bounds are exclusive, so there must be at least one integer between them
+	if greaterThan != nil && lessThan != nil && *greaterThan+1 >= *lessThan {
 		return NewRequirementWithFlexibility(r.Key, corev1.NodeSelectorOpDoesNotExist, minValues)
 	}
 
 	// Values
-	var values sets.Set[string]
+	var values valueSet
 	if r.complement && requirement.complement {
 		values = r.values.Union(requirement.values)
 	} else if r.complement && !requirement.complement {
@@ -175,10 +211,8 @@ func (r *Requirement) Intersection(requirement *Requirement) *Requirement {
 	} else {
 		values = r.values.Intersection(requirement.values)
 	}
-	for value := range values {
-		if !withinIntPtrs(value, greaterThan, lessThan) {
-			values.Delete(value)
-		}
+	if greaterThan != nil || lessThan != nil {
+		values = values.Filter(func(value string) bool { return withinIntPtrs(value, greaterThan, lessThan) })
 	}
 	// Remove boundaries for concrete sets
 	if !complement {
@@ -187,6 +221,11 @@ func (r *Requirement) Intersection(requirement *Requirement) *Requirement {
 	return &Requirement{Key: r.Key, values: values, complement: complement, greaterThan: greaterThan, lessThan: lessThan, MinValues: minValues}
 }
 
+// isConcrete returns true if the requirement only allows a known set of values, i.e. it has the In or DoesNotExist operator
+func (r *Requirement) isConcrete() bool {
+	return !r.complement && r.greaterThan == nil && r.lessThan == nil
+}
+
 func (r *Requirement) Any() string {
 	switch r.Operator() {
 	case corev1.NodeSelectorOpIn:
@@ -200,7 +239,15 @@ func (r *Requirement) Any() string {
 		if r.lessThan != nil {
 			max = *r.lessThan
 		}
-		return fmt.Sprint(rand.Intn(max-min) + min) //nolint:gosec
+		value := rand.Intn(max-min) + min //nolint:gosec
+		// Values excluded by NotIn are skipped, searching upwards and wrapping around to the lower bound
+		for i := 0; r.values.Has(fmt.Sprint(value)) && i < r.values.Len(); i++ {
+			value++
+			if value >= max {
+				value = min
+			}
+		}
+		return fmt.Sprint(value)
 	}
 	return ""
 }
@@ -218,12 +265,12 @@ func (r *Requirement) Values() []string {
 }
 
 func (r *Requirement) Insert(items ...string) {
-	r.values.Insert(items...)
+	r.values = r.values.Union(newValueSet(items...))
 }
 
 func (r *Requirement) Operator() corev1.NodeSelectorOperator {
 	if r.complement {
-		if r.Len() < math.MaxInt64 {
+		if !r.values.empty() {
 			return corev1.NodeSelectorOpNotIn
 		}
 		return corev1.NodeSelectorOpExists // corev1.NodeSelectorOpGt and corev1.NodeSelectorOpLt are treated as "Exists" with bounds
@@ -236,6 +283,12 @@ func (r *Requirement) Operator() corev1.NodeSelectorOperator {
 
 func (r *Requirement) Len() int {
 	if r.complement {
+		// A requirement bounded on both sides can only be satisfied by the integers between its bounds
+		if r.greaterThan != nil && r.lessThan != nil {
+			return *r.lessThan - *r.greaterThan - 1 - r.values.Filter(func(value string) bool {
+				return withinIntPtrs(value, r.greaterThan, r.lessThan)
+			}).Len()
+		}
 		return math.MaxInt64 - r.values.Len()
 	}
 	return r.values.Len()
@@ -247,7 +300,7 @@ func (r *Requirement) String() string {
 	case corev1.NodeSelectorOpExists, corev1.NodeSelectorOpDoesNotExist:
 		s = fmt.Sprintf("%s %s", r.Key, r.Operator())
 	default:
-		values := sets.List(r.values)
+		values := r.values.List()
 		if length := len(values); length > 5 {
 			values = append(values[:5], fmt.Sprintf("and %d others", length-5))
 		}
@@ -23,6 +23,7 @@ import (
 	"strings"
 
 	"github.com/awslabs/operatorpkg/option"
+	"github.com/mitchellh/hashstructure/v2"
 	"github.com/samber/lo"
 	"go.uber.org/multierr"
 	corev1 "k8s.io/api/core/v1"
@@ -89,6 +90,10 @@ const (
 
 func newPodRequirements(pod *corev1.Pod, typ podRequirementType) Requirements {
 	requirements := NewLabelRequirements(pod.Spec.NodeSelector)
+	// A NodePool requested through the pod's annotation is a hard requirement, like a nodeSelector
+	if nodePool, ok := pod.Annotations[v1.NodePoolAnnotationKey]; ok {
+		requirements.Add(NewRequirement(v1.NodePoolLabelKey, corev1.NodeSelectorOpIn, nodePool))
+	}
 	if pod.Spec.Affinity == nil || pod.Spec.Affinity.NodeAffinity == nil {
 		return requirements
 	}
@@ -109,6 +114,59 @@ func newPodRequirements(pod *corev1.Pod, typ podRequirementType) Requirements {
 	return requirements
 }
 
+// PodRequirements are the requirements of a pod, both with its heaviest preferred node affinity treated as required and
+// without any preferences.
+type PodRequirements struct {
+	Requirements       Requirements
+	StrictRequirements Requirements
+}
+
+// PodRequirementsCache caches pod requirements by a hash of the scheduling relevant fields of the pod spec. Pods that
+// share a template (e.g. the pods of a ReplicaSet) have identical requirements, so large scale-ups only need to compute
+// them once. The cached requirements are shared between pods and must not be mutated. It isn't safe for concurrent use.
+type PodRequirementsCache struct {
+	entries map[uint64]*PodRequirements
+}
+
+func NewPodRequirementsCache() *PodRequirementsCache {
+	return &PodRequirementsCache{entries: map[uint64]*PodRequirements{}}
+}
+
+// Get returns the requirements for the pod, computing them if no pod with the same scheduling relevant fields has been
+// seen before.
+func (c *PodRequirementsCache) Get(pod *corev1.Pod) *PodRequirements {
+	hash := podRequirementsHash(pod)
+	if entry, ok := c.entries[hash]; ok {
+		return entry
+	}
+	entry := &PodRequirements{Requirements: NewPodRequirements(pod)}
+	entry.StrictRequirements = entry.Requirements
+	if HasPreferredNodeAffinity(pod) {
+		entry.StrictRequirements = NewStrictPodRequirements(pod)
+	}
+	c.entries[hash] = entry
+	return entry
+}
+
+// podRequirementsHash hashes the fields of the pod that newPodRequirements reads. Ordering is significant as only the
+// first node selector term is considered.
+func podRequirementsHash(pod *corev1.Pod) uint64 {
+	var nodeAffinity *corev1.NodeAffinity
+	if pod.Spec.Affinity != nil {
+		nodeAffinity = pod.Spec.Affinity.NodeAffinity
+	}
+	nodePool, ok := pod.Annotations[v1.NodePoolAnnotationKey]
+	return lo.Must(hashstructure.Hash(struct {
+		NodeSelector map[string]string
+		NodeAffinity *corev1.NodeAffinity
+		NodePool     *string
+	}{
+		NodeSelector: pod.Spec.NodeSelector,
+		NodeAffinity: nodeAffinity,
+		NodePool:     lo.Ternary(ok, &nodePool, nil),
+	}, hashstructure.FormatV2, nil))
+}
+
 // HasPreferredNodeAffinity returns true if the pod has a preferred node affinity term
 func HasPreferredNodeAffinity(p *corev1.Pod) bool {
 	if p == nil {
@@ -118,8 +176,8 @@ func HasPreferredNodeAffinity(p *corev1.Pod) bool {
 }
 
 func (r Requirements) NodeSelectorRequirements() []v1.NodeSelectorRequirementWithMinValues {
-	return lo.Map(lo.Values(r), func(req *Requirement, _ int) v1.NodeSelectorRequirementWithMinValues {
-		return req.NodeSelectorRequirement()
+	return lo.FlatMap(lo.Values(r), func(req *Requirement, _ int) []v1.NodeSelectorRequirementWithMinValues {
+		return req.NodeSelectorRequirements()
 	})
 }
 
@@ -180,7 +238,7 @@ func (r Requirements) Compatible(requirements Requirements, options ...option.Fu
 		if operator := requirements.Get(key).Operator(); r.Has(key) || operator == corev1.NodeSelectorOpNotIn || operator == corev1.NodeSelectorOpDoesNotExist {
 			continue
 		}
-		errs = multierr.Append(errs, fmt.Errorf("label %q does not have known values%s", key, labelHint(r, key, opts.AllowUndefined)))
+		errs = multierr.Append(errs, undefinedKeyError{key: key, hint: labelHint(r, key, opts.AllowUndefined)})
 	}
 	// Well Known Labels must intersect, but if not defined, are allowed.
 	return multierr.Append(errs, r.Intersects(requirements))
@@ -262,6 +320,30 @@ func (b badKeyError) Error() string {
 	return fmt.Sprintf("key %s, %s not in %s", b.key, b.incoming, b.existing)
 }
 
+// undefinedKeyError is returned when a custom label is required, but doesn't have any known values
+type undefinedKeyError struct {
+	key  string
+	hint string
+}
+
+func (u undefinedKeyError) Error() string {
+	return fmt.Sprintf("label %q does not have known values%s", u.key, u.hint)
+}
+
+// IncompatibleKeys returns the sorted keys of the requirements that caused an error returned by Compatible or Intersects
+func IncompatibleKeys(err error) []string {
+	keys := sets.New[string]()
+	for _, e := range multierr.Errors(err) {
+		switch e := e.(type) {
+		case badKeyError:
+			keys.Insert(e.key)
+		case undefinedKeyError:
+			keys.Insert(e.key)
+		}
+	}
+	return sets.List(keys)
+}
+
 // intersectKeys is much faster and allocates less han getting the two key sets separately and intersecting them
 func (r Requirements) intersectKeys(rhs Requirements) sets.Set[string] {
 	smallest := r
@@ -284,6 +366,11 @@ func (r Requirements) Intersects(requirements Requirements) (errs error) {
 	for key := range r.intersectKeys(requirements) {
 		existing := r.Get(key)
 		incoming := requirements.Get(key)
+		// Most requirements are concrete sets of values, e.g. instance types and zones, which intersect if they have any
+		// value in common
+		if existing.isConcrete() && incoming.isConcrete() && existing.values.Intersects(incoming.values) {
+			continue
+		}
 		// There must be some value, except
 		if existing.Intersection(incoming).Len() == 0 {
 			// where the incoming requirement has operator { NotIn, DoesNotExist }
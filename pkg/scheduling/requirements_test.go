@@ -25,12 +25,22 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/samber/lo"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 
 	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
 )
 
 var _ = Describe("Requirements", func() {
 	Context("Compatibility", func() {
+		It("should allow undefined labels that a cloud provider registers as well known labels", func() {
+			nodePool := NewRequirements(NewRequirement(corev1.LabelTopologyZone, corev1.NodeSelectorOpIn, "test"))
+			pod := NewRequirements(NewRequirement("example.com/registered-label", corev1.NodeSelectorOpIn, "value"))
+			Expect(nodePool.Compatible(pod, AllowUndefinedWellKnownLabels)).ToNot(Succeed())
+			DeferCleanup(func(labels sets.Set[string]) { v1.WellKnownLabels = labels }, v1.WellKnownLabels.Clone())
+			v1.RegisterWellKnownLabels("example.com/registered-label")
+			Expect(nodePool.Compatible(pod, AllowUndefinedWellKnownLabels)).To(Succeed())
+		})
 		It("should normalize aliased labels", func() {
 			requirements := NewRequirements(NewRequirement(corev1.LabelFailureDomainBetaZone, corev1.NodeSelectorOpIn, "test"))
 			Expect(requirements.Has(corev1.LabelFailureDomainBetaZone)).To(BeFalse())
@@ -540,6 +550,73 @@ var _ = Describe("Requirements", func() {
 			Expect(lessThan9.Compatible(lessThan1)).To(Succeed())
 			Expect(lessThan9.Compatible(lessThan9)).To(Succeed())
 		})
+		It("should bound numeric instance type labels with Gt and Lt", func() {
+			bounded := NewRequirements(
+				NewRequirement("instance-cpu", corev1.NodeSelectorOpGt, "2"),
+				NewRequirement("instance-cpu", corev1.NodeSelectorOpLt, "16"),
+			)
+			Expect(bounded.Compatible(NewRequirements(NewRequirement("instance-cpu", corev1.NodeSelectorOpIn, "4")))).To(Succeed())
+			Expect(bounded.Compatible(NewRequirements(NewRequirement("instance-cpu", corev1.NodeSelectorOpIn, "2")))).ToNot(Succeed())
+			Expect(bounded.Compatible(NewRequirements(NewRequirement("instance-cpu", corev1.NodeSelectorOpIn, "16")))).ToNot(Succeed())
+			Expect(bounded.Compatible(NewRequirements(NewRequirement("instance-cpu", corev1.NodeSelectorOpIn, "large")))).ToNot(Succeed())
+			Expect(bounded.Compatible(NewRequirements(NewRequirement("instance-cpu", corev1.NodeSelectorOpGt, "14")))).To(Succeed())
+			Expect(bounded.Compatible(NewRequirements(NewRequirement("instance-cpu", corev1.NodeSelectorOpGt, "15")))).ToNot(Succeed())
+		})
+	})
+	Context("Pod Requirements", func() {
+		It("should require the nodepool from the pod's annotation", func() {
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{v1.NodePoolAnnotationKey: "default"}}}
+			for _, requirements := range []Requirements{NewPodRequirements(pod), NewStrictPodRequirements(pod)} {
+				Expect(requirements.Get(v1.NodePoolLabelKey).Operator()).To(Equal(corev1.NodeSelectorOpIn))
+				Expect(requirements.Get(v1.NodePoolLabelKey).Values()).To(ConsistOf("default"))
+			}
+		})
+		It("should intersect the nodepool from the pod's annotation with the pod's nodeSelector", func() {
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{v1.NodePoolAnnotationKey: "default"}},
+				Spec:       corev1.PodSpec{NodeSelector: map[string]string{v1.NodePoolLabelKey: "other"}},
+			}
+			Expect(NewPodRequirements(pod).Get(v1.NodePoolLabelKey).Len()).To(Equal(0))
+		})
+	})
+	Context("Pod Requirements Cache", func() {
+		It("should share requirements between pods with identical scheduling fields", func() {
+			cache := NewPodRequirementsCache()
+			pod1 := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod-1"}, Spec: corev1.PodSpec{NodeSelector: map[string]string{corev1.LabelTopologyZone: "test-zone-1"}}}
+			pod2 := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod-2"}, Spec: corev1.PodSpec{NodeSelector: map[string]string{corev1.LabelTopologyZone: "test-zone-1"}}}
+			Expect(cache.Get(pod1)).To(BeIdenticalTo(cache.Get(pod2)))
+			Expect(cache.Get(pod1).Requirements.Get(corev1.LabelTopologyZone).Values()).To(ConsistOf("test-zone-1"))
+		})
+		It("should compute requirements for pods with different scheduling fields", func() {
+			cache := NewPodRequirementsCache()
+			pod1 := &corev1.Pod{Spec: corev1.PodSpec{NodeSelector: map[string]string{corev1.LabelTopologyZone: "test-zone-1"}}}
+			pod2 := &corev1.Pod{Spec: corev1.PodSpec{NodeSelector: map[string]string{corev1.LabelTopologyZone: "test-zone-2"}}}
+			pod3 := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{v1.NodePoolAnnotationKey: "default"}}, Spec: corev1.PodSpec{NodeSelector: map[string]string{corev1.LabelTopologyZone: "test-zone-1"}}}
+			Expect(cache.Get(pod1)).ToNot(BeIdenticalTo(cache.Get(pod2)))
+			Expect(cache.Get(pod1)).ToNot(BeIdenticalTo(cache.Get(pod3)))
+			Expect(cache.Get(pod2).Requirements.Get(corev1.LabelTopologyZone).Values()).To(ConsistOf("test-zone-2"))
+		})
+		It("should exclude preferences from the strict requirements", func() {
+			cache := NewPodRequirementsCache()
+			pod := &corev1.Pod{Spec: corev1.PodSpec{Affinity: &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+				PreferredDuringSchedulingIgnoredDuringExecution: []corev1.PreferredSchedulingTerm{{Weight: 1, Preference: corev1.NodeSelectorTerm{
+					MatchExpressions: []corev1.NodeSelectorRequirement{{Key: corev1.LabelTopologyZone, Operator: corev1.NodeSelectorOpIn, Values: []string{"test-zone-1"}}},
+				}}},
+			}}}}
+			Expect(cache.Get(pod).Requirements.Has(corev1.LabelTopologyZone)).To(BeTrue())
+			Expect(cache.Get(pod).StrictRequirements.Has(corev1.LabelTopologyZone)).To(BeFalse())
+		})
+		It("should recompute requirements once a pod's preferences are relaxed", func() {
+			cache := NewPodRequirementsCache()
+			pod := &corev1.Pod{Spec: corev1.PodSpec{Affinity: &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+				PreferredDuringSchedulingIgnoredDuringExecution: []corev1.PreferredSchedulingTerm{{Weight: 1, Preference: corev1.NodeSelectorTerm{
+					MatchExpressions: []corev1.NodeSelectorRequirement{{Key: corev1.LabelTopologyZone, Operator: corev1.NodeSelectorOpIn, Values: []string{"test-zone-1"}}},
+				}}},
+			}}}}
+			Expect(cache.Get(pod).Requirements.Has(corev1.LabelTopologyZone)).To(BeTrue())
+			pod.Spec.Affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution = nil
+			Expect(cache.Get(pod).Requirements.Has(corev1.LabelTopologyZone)).To(BeFalse())
+		})
 	})
 	Context("Error Messages", func() {
 		DescribeTable("should detect well known label truncations", func(badLabel, expectedError string) {
@@ -572,6 +649,19 @@ var _ = Describe("Requirements", func() {
 			req := NewRequirements(NewRequirement("deployment", corev1.NodeSelectorOpExists))
 			Expect(unconstrained.Compatible(req).Error()).To(Equal(`label "deployment" does not have known values`))
 		})
+		It("should report the keys that are incompatible", func() {
+			existing := NewRequirements(
+				NewRequirement(corev1.LabelTopologyZone, corev1.NodeSelectorOpIn, "test-zone-1"),
+				NewRequirement(corev1.LabelArchStable, corev1.NodeSelectorOpIn, "amd64"),
+			)
+			incoming := NewRequirements(
+				NewRequirement(corev1.LabelTopologyZone, corev1.NodeSelectorOpIn, "test-zone-2"),
+				NewRequirement(corev1.LabelArchStable, corev1.NodeSelectorOpIn, "amd64"),
+				NewRequirement("deployment", corev1.NodeSelectorOpExists),
+			)
+			Expect(IncompatibleKeys(existing.Compatible(incoming, AllowUndefinedWellKnownLabels))).To(Equal([]string{"deployment", corev1.LabelTopologyZone}))
+			Expect(IncompatibleKeys(existing.Compatible(existing, AllowUndefinedWellKnownLabels))).To(BeEmpty())
+		})
 	})
 	Context("NodeSelectorRequirements Conversion", func() {
 		It("should convert combinations of labels to expected NodeSelectorRequirements", func() {
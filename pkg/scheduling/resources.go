@@ -0,0 +1,28 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduling
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// KnownDevicePluginResources are extended resources that are registered by device plugins after a node has started.
+// Cloud providers that advertise these resources in an InstanceType's Capacity should add them here so that
+// Karpenter-managed nodes aren't considered initialized until the device plugin has registered them. This ensures that
+// pods which were scheduled against the advertised capacity of an in-flight node can still bind once it initializes.
+var KnownDevicePluginResources = sets.New[corev1.ResourceName]()
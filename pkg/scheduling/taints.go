@@ -44,6 +44,15 @@ type Taints []corev1.Taint
 
 // Tolerates returns true if the pod tolerates all taints.
 func (ts Taints) Tolerates(pod *corev1.Pod) (errs error) {
+	for _, taint := range ts.Untolerated(pod) {
+		errs = multierr.Append(errs, fmt.Errorf("did not tolerate %s", pretty.Taint(taint)))
+	}
+	return errs
+}
+
+// Untolerated returns the taints that the pod doesn't tolerate.
+func (ts Taints) Untolerated(pod *corev1.Pod) Taints {
+	var untolerated Taints
 	for i := range ts {
 		taint := ts[i]
 		tolerates := false
@@ -51,10 +60,21 @@ func (ts Taints) Tolerates(pod *corev1.Pod) (errs error) {
 			tolerates = tolerates || t.ToleratesTaint(&taint)
 		}
 		if !tolerates {
-			errs = multierr.Append(errs, fmt.Errorf("did not tolerate %s", pretty.Taint(taint)))
+			untolerated = append(untolerated, taint)
 		}
 	}
-	return errs
+	return untolerated
+}
+
+// WithEffect returns the taints with the given effect.
+func (ts Taints) WithEffect(effect corev1.TaintEffect) Taints {
+	return lo.Filter(ts, func(t corev1.Taint, _ int) bool { return t.Effect == effect })
+}
+
+// WithoutEffect returns the taints without the given effect. This is used to drop PreferNoSchedule taints, which the
+// kube-scheduler uses to score nodes rather than to filter them.
+func (ts Taints) WithoutEffect(effect corev1.TaintEffect) Taints {
+	return lo.Reject(ts, func(t corev1.Taint, _ int) bool { return t.Effect == effect })
 }
 
 // Merge merges in taints with the passed in taints.
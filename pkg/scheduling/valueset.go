@@ -0,0 +1,312 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduling
+
+import (
+	"math/bits"
+	"sort"
+	"sync"
+)
+
+// maxInternedLabelValues bounds the number of label values that are interned. Label values like hostnames change as
+// nodes come and go, so values that are seen once the table is full are kept as strings instead of being interned.
+const maxInternedLabelValues = 1 << 14
+
+// labelValues interns the label values of requirements to small integers, so that sets of label values can be
+// represented as bitsets. Intersecting and comparing requirements is done for every instance type that a pod is
+// considered for, so set operations on bitsets are much cheaper than hashing every value of a requirement. Values are
+// never removed as they're shared by every requirement that has been constructed, so the table is bounded instead.
+var labelValues = newInternTable(maxInternedLabelValues)
+
+// internTable is safe for concurrent use. Lookups don't take a lock, since values are only ever added to the table and
+// the value of an id is written before the id is published.
+type internTable struct {
+	mu     sync.Mutex // serializes interning new values
+	ids    sync.Map   // map[string]uint32
+	values []string
+	size   uint32
+}
+
+func newInternTable(capacity int) *internTable {
+	return &internTable{values: make([]string, capacity)}
+}
+
+// id returns the interned id of the value, interning it if it hasn't been seen before. It returns false if the value
+// isn't interned and the table is full.
+func (t *internTable) id(value string) (uint32, bool) {
+	if id, ok := t.lookup(value); ok {
+		return id, true
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if id, ok := t.lookup(value); ok {
+		return id, true
+	}
+	if int(t.size) == len(t.values) {
+		return 0, false
+	}
+	id := t.size
+	t.values[id] = value
+	t.size++
+	t.ids.Store(value, id)
+	return id, true
+}
+
+// lookup returns the interned id of the value without interning it
+func (t *internTable) lookup(value string) (uint32, bool) {
+	id, ok := t.ids.Load(value)
+	if !ok {
+		return 0, false
+	}
+	return id.(uint32), true
+}
+
+// valueOf returns the value with the interned id
+func (t *internTable) valueOf(id uint32) string {
+	return t.values[id]
+}
+
+// valueBlock holds the members of a valueSet with the interned ids index*64 to index*64+63
+type valueBlock struct {
+	index uint32
+	bits  uint64
+}
+
+// valueSet is a sparse bitset of interned label values. Blocks are ordered by index and blocks without any members are
+// never stored, so that equal sets are always deeply equal and an empty set is the zero value. Label values like
+// hostnames are interned with ever increasing ids as nodes come and go, so only storing the blocks that have members
+// keeps sets of a few values small regardless of how many values have been interned. Values that couldn't be interned
+// because the table is full are kept in sorted order in overflow. A value is either always or never interned once the
+// table is full, so the same value is never held both ways.
+type valueSet struct {
+	blocks   []valueBlock
+	overflow []string
+}
+
+func newValueSet(values ...string) valueSet {
+	if len(values) == 0 {
+		return valueSet{}
+	}
+	ids := make([]uint32, 0, len(values))
+	var overflow []string
+	for _, value := range values {
+		if id, ok := labelValues.id(value); ok {
+			ids = append(ids, id)
+		} else {
+			overflow = append(overflow, value)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	var s valueSet
+	for _, id := range ids {
+		if len(s.blocks) == 0 || s.blocks[len(s.blocks)-1].index != id/64 {
+			s.blocks = append(s.blocks, valueBlock{index: id / 64})
+		}
+		s.blocks[len(s.blocks)-1].bits |= 1 << (id % 64)
+	}
+	if len(overflow) > 0 {
+		sort.Strings(overflow)
+		s.overflow = unionStrings(overflow[:1], overflow[1:])
+	}
+	return s
+}
+
+func (s valueSet) Has(value string) bool {
+	id, ok := labelValues.lookup(value)
+	if !ok {
+		return containsString(s.overflow, value)
+	}
+	i := sort.Search(len(s.blocks), func(i int) bool { return s.blocks[i].index >= id/64 })
+	return i < len(s.blocks) && s.blocks[i].index == id/64 && s.blocks[i].bits&(1<<(id%64)) != 0
+}
+
+// empty returns true if the set has no values, without counting them
+func (s valueSet) empty() bool {
+	return len(s.blocks) == 0 && len(s.overflow) == 0
+}
+
+func (s valueSet) Len() int {
+	n := len(s.overflow)
+	for _, block := range s.blocks {
+		n += bits.OnesCount64(block.bits)
+	}
+	return n
+}
+
+// UnsortedList returns the interned values of the set in the order that they were interned, followed by the values
+// that couldn't be interned
+func (s valueSet) UnsortedList() []string {
+	values := make([]string, 0, s.Len())
+	s.each(func(id uint32) { values = append(values, labelValues.valueOf(id)) })
+	return append(values, s.overflow...)
+}
+
+// List returns the values of the set in sorted order
+func (s valueSet) List() []string {
+	values := s.UnsortedList()
+	sort.Strings(values)
+	return values
+}
+
+func (s valueSet) Union(o valueSet) valueSet {
+	result := make([]valueBlock, 0, len(s.blocks)+len(o.blocks))
+	i, j := 0, 0
+	for i < len(s.blocks) && j < len(o.blocks) {
+		switch {
+		case s.blocks[i].index < o.blocks[j].index:
+			result = append(result, s.blocks[i])
+			i++
+		case s.blocks[i].index > o.blocks[j].index:
+			result = append(result, o.blocks[j])
+			j++
+		default:
+			result = append(result, valueBlock{index: s.blocks[i].index, bits: s.blocks[i].bits | o.blocks[j].bits})
+			i, j = i+1, j+1
+		}
+	}
+	result = append(append(result, s.blocks[i:]...), o.blocks[j:]...)
+	if len(result) == 0 {
+		result = nil
+	}
+	return valueSet{blocks: result, overflow: unionStrings(s.overflow, o.overflow)}
+}
+
+func (s valueSet) Intersection(o valueSet) valueSet {
+	var result []valueBlock
+	for i, j := 0, 0; i < len(s.blocks) && j < len(o.blocks); {
+		switch {
+		case s.blocks[i].index < o.blocks[j].index:
+			i++
+		case s.blocks[i].index > o.blocks[j].index:
+			j++
+		default:
+			if b := s.blocks[i].bits & o.blocks[j].bits; b != 0 {
+				result = append(result, valueBlock{index: s.blocks[i].index, bits: b})
+			}
+			i, j = i+1, j+1
+		}
+	}
+	return valueSet{blocks: result, overflow: filterStrings(s.overflow, func(value string) bool { return containsString(o.overflow, value) })}
+}
+
+// Difference returns the values of the set that aren't in the other set
+func (s valueSet) Difference(o valueSet) valueSet {
+	var result []valueBlock
+	j := 0
+	for _, block := range s.blocks {
+		for j < len(o.blocks) && o.blocks[j].index < block.index {
+			j++
+		}
+		if j < len(o.blocks) && o.blocks[j].index == block.index {
+			block.bits &^= o.blocks[j].bits
+		}
+		if block.bits != 0 {
+			result = append(result, block)
+		}
+	}
+	return valueSet{blocks: result, overflow: filterStrings(s.overflow, func(value string) bool { return !containsString(o.overflow, value) })}
+}
+
+// Intersects returns true if the sets have any values in common, without allocating their intersection
+func (s valueSet) Intersects(o valueSet) bool {
+	for i, j := 0, 0; i < len(s.blocks) && j < len(o.blocks); {
+		switch {
+		case s.blocks[i].index < o.blocks[j].index:
+			i++
+		case s.blocks[i].index > o.blocks[j].index:
+			j++
+		default:
+			if s.blocks[i].bits&o.blocks[j].bits != 0 {
+				return true
+			}
+			i, j = i+1, j+1
+		}
+	}
+	for _, value := range s.overflow {
+		if containsString(o.overflow, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// Filter returns the values of the set that satisfy the predicate
+func (s valueSet) Filter(predicate func(string) bool) valueSet {
+	var result []valueBlock
+	for _, block := range s.blocks {
+		kept := block.bits
+		for b := block.bits; b != 0; b &= b - 1 {
+			id := block.index*64 + uint32(bits.TrailingZeros64(b))
+			if !predicate(labelValues.valueOf(id)) {
+				kept &^= 1 << (id % 64)
+			}
+		}
+		if kept != 0 {
+			result = append(result, valueBlock{index: block.index, bits: kept})
+		}
+	}
+	return valueSet{blocks: result, overflow: filterStrings(s.overflow, predicate)}
+}
+
+// each calls f with the interned id of every interned value in the set, in increasing order
+func (s valueSet) each(f func(uint32)) {
+	for _, block := range s.blocks {
+		for b := block.bits; b != 0; b &= b - 1 {
+			f(block.index*64 + uint32(bits.TrailingZeros64(b)))
+		}
+	}
+}
+
+// unionStrings merges two sorted slices of strings without duplicates, returning nil if both are empty
+func unionStrings(a, b []string) []string {
+	if len(a) == 0 && len(b) == 0 {
+		return nil
+	}
+	result := make([]string, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		var next string
+		switch {
+		case j == len(b) || (i < len(a) && a[i] < b[j]):
+			next, i = a[i], i+1
+		case i == len(a) || b[j] < a[i]:
+			next, j = b[j], j+1
+		default:
+			next, i, j = a[i], i+1, j+1
+		}
+		if len(result) == 0 || result[len(result)-1] != next {
+			result = append(result, next)
+		}
+	}
+	return result
+}
+
+// filterStrings returns the strings that satisfy the predicate, returning nil if none do
+func filterStrings(values []string, predicate func(string) bool) []string {
+	var result []string
+	for _, value := range values {
+		if predicate(value) {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+func containsString(sorted []string, value string) bool {
+	i := sort.SearchStrings(sorted, value)
+	return i < len(sorted) && sorted[i] == value
+}
@@ -0,0 +1,89 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduling
+
+import (
+	"fmt"
+	"math/rand"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/samber/lo"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+var _ = Describe("ValueSet", func() {
+	It("should contain the values that it was constructed with", func() {
+		s := newValueSet("a", "b", "c", "a")
+		Expect(s.Len()).To(Equal(3))
+		Expect(s.Has("a")).To(BeTrue())
+		Expect(s.Has("d")).To(BeFalse())
+		Expect(s.Has("never-interned-value")).To(BeFalse())
+		Expect(s.List()).To(Equal([]string{"a", "b", "c"}))
+	})
+	It("should represent empty sets as the zero value", func() {
+		a, b := newValueSet("a"), newValueSet("b")
+		Expect(newValueSet()).To(BeZero())
+		Expect(a.Intersection(b)).To(BeZero())
+		Expect(a.Difference(a)).To(BeZero())
+		Expect(a.Filter(func(string) bool { return false })).To(BeZero())
+		Expect(valueSet{}.Union(valueSet{})).To(BeZero())
+	})
+	It("should be deeply equal to sets with the same values", func() {
+		Expect(newValueSet("a", "b")).To(Equal(newValueSet("b", "a")))
+		Expect(newValueSet("a", "b", "c").Difference(newValueSet("c"))).To(Equal(newValueSet("a", "b")))
+		Expect(newValueSet("a").Union(newValueSet("b"))).To(Equal(newValueSet("a", "b")))
+	})
+	DescribeTable("should match the set operations of string sets",
+		func(capacity int) {
+			if capacity > 0 {
+				stored := labelValues
+				labelValues = newInternTable(capacity)
+				DeferCleanup(func() { labelValues = stored })
+			}
+			universe := lo.Times(500, func(i int) string { return fmt.Sprintf("value-%d", i) })
+			random := func() []string {
+				return lo.Filter(universe, func(string, int) bool { return rand.Intn(10) == 0 }) //nolint:gosec
+			}
+			for range 100 {
+				a, b := random(), random()
+				setA, setB := sets.New(a...), sets.New(b...)
+				valuesA, valuesB := newValueSet(a...), newValueSet(b...)
+				Expect(valuesA.Len()).To(Equal(setA.Len()))
+				Expect(valuesA.Union(valuesB).List()).To(Equal(sets.List(setA.Union(setB))))
+				Expect(valuesA.Intersection(valuesB).List()).To(Equal(sets.List(setA.Intersection(setB))))
+				Expect(valuesA.Difference(valuesB).List()).To(Equal(sets.List(setA.Difference(setB))))
+				Expect(valuesA.Intersects(valuesB)).To(Equal(setA.HasAny(b...)))
+				Expect(lo.EveryBy(a, valuesA.Has)).To(BeTrue())
+			}
+		},
+		Entry("with every value interned", 0),
+		Entry("with values that overflow the intern table", 100),
+	)
+	It("should keep values that don't fit in the intern table", func() {
+		stored := labelValues
+		labelValues = newInternTable(1)
+		DeferCleanup(func() { labelValues = stored })
+
+		s := newValueSet("a", "c", "b", "c")
+		Expect(s.overflow).To(Equal([]string{"b", "c"}))
+		Expect(s.Len()).To(Equal(3))
+		Expect(s.Has("b")).To(BeTrue())
+		Expect(s.List()).To(Equal([]string{"a", "b", "c"}))
+		Expect(s.Difference(newValueSet("a", "b"))).To(Equal(newValueSet("c")))
+	})
+})
@@ -79,6 +79,16 @@ func (u Volumes) Insert(volumes Volumes) {
 	}
 }
 
+// ExceedsLimits returns an error if the volumes exceed the limits
+func (u Volumes) ExceedsLimits(limits map[string]int) error {
+	for k, volumes := range u {
+		if limit, hasLimit := limits[k]; hasLimit && len(volumes) > limit {
+			return fmt.Errorf("would exceed volume limit for %s, %d > %d", k, len(volumes), limit)
+		}
+	}
+	return nil
+}
+
 //nolint:gocyclo
 func GetVolumes(ctx context.Context, kubeClient client.Client, pod *v1.Pod) (Volumes, error) {
 	podPVCs := Volumes{}
@@ -199,18 +209,18 @@ func NewVolumeUsage() *VolumeUsage {
 }
 
 func (v *VolumeUsage) ExceedsLimits(vols Volumes) error {
-	for k, volumes := range v.volumes.Union(vols) {
-		if limit, hasLimit := v.limits[k]; hasLimit && len(volumes) > limit {
-			return fmt.Errorf("would exceed volume limit for %s, %d > %d", k, len(volumes), limit)
-		}
-	}
-	return nil
+	return v.volumes.Union(vols).ExceedsLimits(v.limits)
 }
 
 func (v *VolumeUsage) AddLimit(storageDriver string, value int) {
 	v.limits[storageDriver] = value
 }
 
+func (v *VolumeUsage) HasLimit(storageDriver string) bool {
+	_, ok := v.limits[storageDriver]
+	return ok
+}
+
 func (v *VolumeUsage) Add(pod *v1.Pod, volumes Volumes) {
 	v.podVolumes[client.ObjectKeyFromObject(pod)] = volumes
 	v.volumes = v.volumes.Union(volumes)
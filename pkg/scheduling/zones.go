@@ -0,0 +1,104 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduling
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+)
+
+// ZoneMapping maps the IDs of zones to their names and back, for cloud providers that label nodes with both
+type ZoneMapping struct {
+	names map[string]string // zone ID -> zone name
+	ids   map[string]string // zone name -> zone ID
+}
+
+func NewZoneMapping() *ZoneMapping {
+	return &ZoneMapping{names: map[string]string{}, ids: map[string]string{}}
+}
+
+// Insert records the zone ID and name of requirements that have a single value for both, e.g. those of an offering
+func (m *ZoneMapping) Insert(requirements Requirements) {
+	if v1.ZoneIDLabelKey == "" || !requirements.Has(v1.ZoneIDLabelKey) || !requirements.Has(corev1.LabelTopologyZone) {
+		return
+	}
+	id, name := requirements.Get(v1.ZoneIDLabelKey), requirements.Get(corev1.LabelTopologyZone)
+	if id.Operator() != corev1.NodeSelectorOpIn || id.Len() != 1 || name.Operator() != corev1.NodeSelectorOpIn || name.Len() != 1 {
+		return
+	}
+	m.names[id.Any()] = name.Any()
+	m.ids[name.Any()] = id.Any()
+}
+
+// Normalize returns the requirements with their zone ID requirement translated into a zone name requirement, and their
+// zone name requirement translated into a zone ID requirement. The requirements are copied rather than modified if
+// anything is translated. Zones that aren't in the mapping are dropped from the translated requirements. Zone names are
+// translated into the zone IDs that they exclude, so that nodes without the zone ID label, e.g. existing nodes that the
+// cloud provider didn't label, still match.
+func (m *ZoneMapping) Normalize(requirements Requirements) Requirements {
+	if m == nil || v1.ZoneIDLabelKey == "" || len(m.names) == 0 {
+		return requirements
+	}
+	var translated []*Requirement
+	if requirement, ok := m.translate(requirements, v1.ZoneIDLabelKey, corev1.LabelTopologyZone, m.names); ok {
+		translated = append(translated, requirement)
+	}
+	if requirement, ok := m.translate(requirements, corev1.LabelTopologyZone, v1.ZoneIDLabelKey, m.ids); ok {
+		translated = append(translated, m.excludedIDs(requirement))
+	}
+	if len(translated) == 0 {
+		return requirements
+	}
+	normalized := NewRequirements(requirements.Values()...)
+	normalized.Add(translated...)
+	return normalized
+}
+
+// translate returns the requirement on the key that is equivalent to the requirement on the other key, if the
+// requirement constrains the values of the other key
+func (m *ZoneMapping) translate(requirements Requirements, from, to string, mapping map[string]string) (*Requirement, bool) {
+	if !requirements.Has(from) {
+		return nil, false
+	}
+	requirement := requirements.Get(from)
+	if operator := requirement.Operator(); operator != corev1.NodeSelectorOpIn && operator != corev1.NodeSelectorOpNotIn {
+		return nil, false
+	}
+	var values []string
+	for _, value := range requirement.Values() {
+		if mapped, ok := mapping[value]; ok {
+			values = append(values, mapped)
+		}
+	}
+	return NewRequirement(to, requirement.Operator(), values...), true
+}
+
+// excludedIDs returns the zone ID requirement as a NotIn requirement on the zone IDs that it doesn't allow, as NotIn
+// requirements allow the label to be undefined
+func (m *ZoneMapping) excludedIDs(requirement *Requirement) *Requirement {
+	if requirement.Operator() != corev1.NodeSelectorOpIn {
+		return requirement
+	}
+	var excluded []string
+	for id := range m.names {
+		if !requirement.Has(id) {
+			excluded = append(excluded, id)
+		}
+	}
+	return NewRequirement(requirement.Key, corev1.NodeSelectorOpNotIn, excluded...)
+}
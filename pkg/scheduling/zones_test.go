@@ -0,0 +1,84 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduling
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+)
+
+var _ = Describe("ZoneMapping", func() {
+	const zoneIDLabelKey = "example.com/zone-id"
+	var zones *ZoneMapping
+	BeforeEach(func() {
+		DeferCleanup(func(key string) { v1.ZoneIDLabelKey = key }, v1.ZoneIDLabelKey)
+		DeferCleanup(func(labels sets.Set[string]) { v1.WellKnownLabels = labels }, v1.WellKnownLabels.Clone())
+		v1.RegisterZoneIDLabel(zoneIDLabelKey)
+		zones = NewZoneMapping()
+		for id, name := range map[string]string{"id-1": "zone-1", "id-2": "zone-2", "id-3": "zone-3"} {
+			zones.Insert(NewRequirements(
+				NewRequirement(zoneIDLabelKey, corev1.NodeSelectorOpIn, id),
+				NewRequirement(corev1.LabelTopologyZone, corev1.NodeSelectorOpIn, name),
+			))
+		}
+	})
+	It("should translate zone IDs into zone names", func() {
+		requirements := zones.Normalize(NewRequirements(NewRequirement(zoneIDLabelKey, corev1.NodeSelectorOpIn, "id-1", "id-2")))
+		Expect(requirements.Get(corev1.LabelTopologyZone).Values()).To(ConsistOf("zone-1", "zone-2"))
+	})
+	It("should translate zone names into zone IDs", func() {
+		requirements := zones.Normalize(NewRequirements(NewRequirement(corev1.LabelTopologyZone, corev1.NodeSelectorOpNotIn, "zone-3")))
+		Expect(requirements.Get(zoneIDLabelKey).Operator()).To(Equal(corev1.NodeSelectorOpNotIn))
+		Expect(requirements.Get(zoneIDLabelKey).Values()).To(ConsistOf("id-3"))
+	})
+	It("should intersect translated requirements with existing ones", func() {
+		requirements := zones.Normalize(NewRequirements(
+			NewRequirement(zoneIDLabelKey, corev1.NodeSelectorOpIn, "id-1", "id-2"),
+			NewRequirement(corev1.LabelTopologyZone, corev1.NodeSelectorOpIn, "zone-2", "zone-3"),
+		))
+		Expect(requirements.Get(corev1.LabelTopologyZone).Values()).To(ConsistOf("zone-2"))
+		Expect(requirements.Get(zoneIDLabelKey).Values()).To(ConsistOf("id-2"))
+	})
+	It("should make pods selecting zone IDs compatible with NodePools selecting zone names", func() {
+		nodePool := zones.Normalize(NewRequirements(NewRequirement(corev1.LabelTopologyZone, corev1.NodeSelectorOpIn, "zone-1")))
+		Expect(nodePool.Compatible(zones.Normalize(NewRequirements(NewRequirement(zoneIDLabelKey, corev1.NodeSelectorOpIn, "id-1"))))).To(Succeed())
+		Expect(nodePool.Compatible(zones.Normalize(NewRequirements(NewRequirement(zoneIDLabelKey, corev1.NodeSelectorOpIn, "id-2"))))).ToNot(Succeed())
+	})
+	It("should allow nodes without the zone ID label when translating zone names", func() {
+		pod := zones.Normalize(NewRequirements(NewRequirement(corev1.LabelTopologyZone, corev1.NodeSelectorOpIn, "zone-1")))
+		Expect(pod.Get(zoneIDLabelKey).Operator()).To(Equal(corev1.NodeSelectorOpNotIn))
+		Expect(pod.Get(zoneIDLabelKey).Values()).To(ConsistOf("id-2", "id-3"))
+		Expect(NewLabelRequirements(map[string]string{corev1.LabelTopologyZone: "zone-1"}).Compatible(pod)).To(Succeed())
+		Expect(NewLabelRequirements(map[string]string{corev1.LabelTopologyZone: "zone-1", zoneIDLabelKey: "id-1"}).Compatible(pod)).To(Succeed())
+		Expect(NewLabelRequirements(map[string]string{corev1.LabelTopologyZone: "zone-2"}).Compatible(pod)).ToNot(Succeed())
+	})
+	It("should not copy requirements that don't select zones", func() {
+		requirements := NewRequirements(NewRequirement(corev1.LabelArchStable, corev1.NodeSelectorOpIn, v1.ArchitectureAmd64))
+		normalized := zones.Normalize(requirements)
+		normalized.Add(NewRequirement(corev1.LabelOSStable, corev1.NodeSelectorOpIn, "linux"))
+		Expect(requirements.Has(corev1.LabelOSStable)).To(BeTrue())
+	})
+	It("should not translate requirements if no zone ID label is registered", func() {
+		v1.ZoneIDLabelKey = ""
+		requirements := zones.Normalize(NewRequirements(NewRequirement(corev1.LabelTopologyZone, corev1.NodeSelectorOpIn, "zone-1")))
+		Expect(requirements.Has(zoneIDLabelKey)).To(BeFalse())
+	})
+})
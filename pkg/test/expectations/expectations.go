@@ -245,14 +245,18 @@ func ExpectCleanedUp(ctx context.Context, c client.Client) {
 		&corev1.Pod{},
 		&corev1.Node{},
 		&appsv1.DaemonSet{},
+		&appsv1.StatefulSet{},
 		&nodev1.RuntimeClass{},
 		&policyv1.PodDisruptionBudget{},
+		&corev1.LimitRange{},
 		&corev1.PersistentVolumeClaim{},
 		&corev1.PersistentVolume{},
 		&storagev1.StorageClass{},
 		&v1.NodePool{},
 		&v1alpha1.TestNodeClass{},
 		&v1.NodeClaim{},
+		&v1.SchedulingSnapshot{},
+		&v1.NodeOverlay{},
 	} {
 		for _, namespace := range namespaces.Items {
 			wg.Add(1)
@@ -306,6 +310,20 @@ func ExpectProvisioned(ctx context.Context, c client.Client, cluster *state.Clus
 	return bindings
 }
 
+// ExpectProvisionedWithBoundPods is ExpectProvisioned for pods that include pods which are already bound to nodes. The
+// bound pods are tracked by the cluster state, as the pod informer would, before the pods are provisioned.
+func ExpectProvisionedWithBoundPods(ctx context.Context, c client.Client, cluster *state.Cluster, cloudProvider cloudprovider.CloudProvider, provisioner *provisioning.Provisioner, pods ...*corev1.Pod) Bindings {
+	GinkgoHelper()
+	for _, pod := range pods {
+		if pod.Spec.NodeName == "" {
+			continue
+		}
+		ExpectApplied(ctx, c, pod)
+		Expect(client.IgnoreNotFound(cluster.UpdatePod(ctx, pod))).To(Succeed())
+	}
+	return ExpectProvisioned(ctx, c, cluster, cloudProvider, provisioner, pods...)
+}
+
 //nolint:gocyclo
 func ExpectProvisionedNoBinding(ctx context.Context, c client.Client, cluster *state.Cluster, cloudProvider cloudprovider.CloudProvider, provisioner *provisioning.Provisioner, pods ...*corev1.Pod) Bindings {
 	GinkgoHelper()
@@ -566,7 +584,7 @@ func FindMetricWithLabelValues(name string, labelValues map[string]string) (*pro
 
 func ExpectMetricGaugeValue(collector opmetrics.GaugeMetric, expectedValue float64, labels map[string]string) {
 	GinkgoHelper()
-	metricName := ExpectMetricName(collector.(*opmetrics.PrometheusGauge))
+	metricName := ExpectMetricName(collector.(prometheus.Collector))
 	metric, ok := FindMetricWithLabelValues(metricName, labels)
 	Expect(ok).To(BeTrue(), "Metric "+metricName+" should be available")
 	Expect(lo.FromPtr(metric.Gauge.Value)).To(Equal(expectedValue), "Metric "+metricName+" should have the expected value")
@@ -574,7 +592,7 @@ func ExpectMetricGaugeValue(collector opmetrics.GaugeMetric, expectedValue float
 
 func ExpectMetricCounterValue(collector opmetrics.CounterMetric, expectedValue float64, labels map[string]string) {
 	GinkgoHelper()
-	metricName := ExpectMetricName(collector.(*opmetrics.PrometheusCounter))
+	metricName := ExpectMetricName(collector.(prometheus.Collector))
 	metric, ok := FindMetricWithLabelValues(metricName, labels)
 	Expect(ok).To(BeTrue(), "Metric "+metricName+" should be available")
 	Expect(lo.FromPtr(metric.Counter.Value)).To(Equal(expectedValue), "Metric "+metricName+" should have the expected value")
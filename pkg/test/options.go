@@ -28,27 +28,47 @@ import (
 
 type OptionsFields struct {
 	// Vendor Neutral
-	ServiceName             *string
-	MetricsPort             *int
-	HealthProbePort         *int
-	KubeClientQPS           *int
-	KubeClientBurst         *int
-	EnableProfiling         *bool
-	DisableLeaderElection   *bool
-	LeaderElectionName      *string
-	LeaderElectionNamespace *string
-	MemoryLimit             *int64
-	LogLevel                *string
-	LogOutputPaths          *string
-	LogErrorOutputPaths     *string
-	BatchMaxDuration        *time.Duration
-	BatchIdleDuration       *time.Duration
-	FeatureGates            FeatureGates
+	ServiceName                  *string
+	MetricsPort                  *int
+	HealthProbePort              *int
+	KubeClientQPS                *int
+	KubeClientBurst              *int
+	EnableProfiling              *bool
+	EnableDryRunProvisioning     *bool
+	EnableSchedulingSnapshots    *bool
+	StrictNodePoolWeights        *bool
+	StrictStartupTaints          *bool
+	DisableLeaderElection        *bool
+	LeaderElectionName           *string
+	LeaderElectionNamespace      *string
+	MemoryLimit                  *int64
+	LogLevel                     *string
+	LogOutputPaths               *string
+	LogErrorOutputPaths          *string
+	BatchMaxDuration             *time.Duration
+	BatchIdleDuration            *time.Duration
+	NominationTTL                *time.Duration
+	PendingPodSLA                *time.Duration
+	FragmentationThreshold       *string
+	SchedulingExtenderURL        *string
+	SchedulingExtenderDisruption *bool
+	PodPackingOrder              *string
+	ResourceAliases              *string
+	ManagedBy                    *string
+	GCInterval                   *time.Duration
+	GCDryRun                     *bool
+	NodeAnnotationAllowlist      *string
+	MetricLabelPolicies          *string
+	FeatureGates                 FeatureGates
 }
 
 type FeatureGates struct {
-	NodeRepair              *bool
-	SpotToSpotConsolidation *bool
+	NodeRepair                   *bool
+	SpotToSpotConsolidation      *bool
+	PreemptionAwareProvisioning  *bool
+	StatefulSetAwareProvisioning *bool
+	PodPreBinding                *bool
+	NodePoolDeletionProtection   *bool
 }
 
 func Options(overrides ...OptionsFields) *options.Options {
@@ -60,22 +80,42 @@ func Options(overrides ...OptionsFields) *options.Options {
 	}
 
 	return &options.Options{
-		ServiceName:           lo.FromPtrOr(opts.ServiceName, ""),
-		MetricsPort:           lo.FromPtrOr(opts.MetricsPort, 8080),
-		HealthProbePort:       lo.FromPtrOr(opts.HealthProbePort, 8081),
-		KubeClientQPS:         lo.FromPtrOr(opts.KubeClientQPS, 200),
-		KubeClientBurst:       lo.FromPtrOr(opts.KubeClientBurst, 300),
-		EnableProfiling:       lo.FromPtrOr(opts.EnableProfiling, false),
-		DisableLeaderElection: lo.FromPtrOr(opts.DisableLeaderElection, false),
-		MemoryLimit:           lo.FromPtrOr(opts.MemoryLimit, -1),
-		LogLevel:              lo.FromPtrOr(opts.LogLevel, ""),
-		LogOutputPaths:        lo.FromPtrOr(opts.LogOutputPaths, "stdout"),
-		LogErrorOutputPaths:   lo.FromPtrOr(opts.LogErrorOutputPaths, "stderr"),
-		BatchMaxDuration:      lo.FromPtrOr(opts.BatchMaxDuration, 10*time.Second),
-		BatchIdleDuration:     lo.FromPtrOr(opts.BatchIdleDuration, time.Second),
+		ServiceName:                  lo.FromPtrOr(opts.ServiceName, ""),
+		MetricsPort:                  lo.FromPtrOr(opts.MetricsPort, 8080),
+		HealthProbePort:              lo.FromPtrOr(opts.HealthProbePort, 8081),
+		KubeClientQPS:                lo.FromPtrOr(opts.KubeClientQPS, 200),
+		KubeClientBurst:              lo.FromPtrOr(opts.KubeClientBurst, 300),
+		EnableProfiling:              lo.FromPtrOr(opts.EnableProfiling, false),
+		EnableDryRunProvisioning:     lo.FromPtrOr(opts.EnableDryRunProvisioning, false),
+		EnableSchedulingSnapshots:    lo.FromPtrOr(opts.EnableSchedulingSnapshots, false),
+		StrictNodePoolWeights:        lo.FromPtrOr(opts.StrictNodePoolWeights, false),
+		StrictStartupTaints:          lo.FromPtrOr(opts.StrictStartupTaints, false),
+		DisableLeaderElection:        lo.FromPtrOr(opts.DisableLeaderElection, false),
+		MemoryLimit:                  lo.FromPtrOr(opts.MemoryLimit, -1),
+		LogLevel:                     lo.FromPtrOr(opts.LogLevel, ""),
+		LogOutputPaths:               lo.FromPtrOr(opts.LogOutputPaths, "stdout"),
+		LogErrorOutputPaths:          lo.FromPtrOr(opts.LogErrorOutputPaths, "stderr"),
+		BatchMaxDuration:             lo.FromPtrOr(opts.BatchMaxDuration, 10*time.Second),
+		BatchIdleDuration:            lo.FromPtrOr(opts.BatchIdleDuration, time.Second),
+		NominationTTL:                lo.FromPtrOr(opts.NominationTTL, 0),
+		PendingPodSLA:                lo.FromPtrOr(opts.PendingPodSLA, 0),
+		FragmentationThreshold:       lo.FromPtrOr(opts.FragmentationThreshold, "0"),
+		SchedulingExtenderURL:        lo.FromPtrOr(opts.SchedulingExtenderURL, ""),
+		SchedulingExtenderDisruption: lo.FromPtrOr(opts.SchedulingExtenderDisruption, false),
+		PodPackingOrder:              lo.FromPtrOr(opts.PodPackingOrder, "LargestFirst"),
+		ResourceAliases:              lo.FromPtrOr(opts.ResourceAliases, ""),
+		ManagedBy:                    lo.FromPtrOr(opts.ManagedBy, ""),
+		GCInterval:                   lo.FromPtrOr(opts.GCInterval, 2*time.Minute),
+		GCDryRun:                     lo.FromPtrOr(opts.GCDryRun, true),
+		NodeAnnotationAllowlist:      lo.FromPtrOr(opts.NodeAnnotationAllowlist, ""),
+		MetricLabelPolicies:          lo.FromPtrOr(opts.MetricLabelPolicies, ""),
 		FeatureGates: options.FeatureGates{
-			NodeRepair:              lo.FromPtrOr(opts.FeatureGates.NodeRepair, false),
-			SpotToSpotConsolidation: lo.FromPtrOr(opts.FeatureGates.SpotToSpotConsolidation, false),
+			NodeRepair:                   lo.FromPtrOr(opts.FeatureGates.NodeRepair, false),
+			SpotToSpotConsolidation:      lo.FromPtrOr(opts.FeatureGates.SpotToSpotConsolidation, false),
+			PreemptionAwareProvisioning:  lo.FromPtrOr(opts.FeatureGates.PreemptionAwareProvisioning, false),
+			StatefulSetAwareProvisioning: lo.FromPtrOr(opts.FeatureGates.StatefulSetAwareProvisioning, false),
+			PodPreBinding:                lo.FromPtrOr(opts.FeatureGates.PodPreBinding, false),
+			NodePoolDeletionProtection:   lo.FromPtrOr(opts.FeatureGates.NodePoolDeletionProtection, false),
 		},
 	}
 }
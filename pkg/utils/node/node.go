@@ -33,6 +33,7 @@ import (
 
 	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
 	"sigs.k8s.io/karpenter/pkg/cloudprovider"
+	"sigs.k8s.io/karpenter/pkg/operator/options"
 	nodeclaimutils "sigs.k8s.io/karpenter/pkg/utils/nodeclaim"
 	"sigs.k8s.io/karpenter/pkg/utils/pod"
 )
@@ -138,15 +139,23 @@ func GetReschedulablePods(ctx context.Context, kubeClient client.Client, nodes .
 	}), nil
 }
 
-// GetProvisionablePods grabs all the pods from the passed nodes that satisfy the IsProvisionable criteria
-func GetProvisionablePods(ctx context.Context, kubeClient client.Client) ([]*corev1.Pod, error) {
+// GetProvisionablePods grabs all the pods that haven't been bound to a node, and splits them into the pods that satisfy
+// the IsProvisionable criteria and the pods that are waiting on scheduling gates
+func GetProvisionablePods(ctx context.Context, kubeClient client.Client) (provisionable []*corev1.Pod, gated []*corev1.Pod, err error) {
 	var podList corev1.PodList
-	if err := kubeClient.List(ctx, &podList, client.MatchingFields{"spec.nodeName": ""}); err != nil {
-		return nil, fmt.Errorf("listing pods, %w", err)
+	if err = kubeClient.List(ctx, &podList, client.MatchingFields{"spec.nodeName": ""}); err != nil {
+		return nil, nil, fmt.Errorf("listing pods, %w", err)
 	}
-	return lo.FilterMap(podList.Items, func(p corev1.Pod, _ int) (*corev1.Pod, bool) {
-		return &p, pod.IsProvisionable(&p)
-	}), nil
+	for i := range podList.Items {
+		p := &podList.Items[i]
+		switch {
+		case pod.IsProvisionable(p):
+			provisionable = append(provisionable, p)
+		case pod.IsSchedulingGated(p) && !pod.IsTerminal(p) && !pod.IsTerminating(p):
+			gated = append(gated, p)
+		}
+	}
+	return provisionable, gated, nil
 }
 
 // GetVolumeAttachments grabs all volumeAttachments associated with the passed node
@@ -167,17 +176,19 @@ func GetCondition(n *corev1.Node, match corev1.NodeConditionType) corev1.NodeCon
 	return corev1.NodeCondition{}
 }
 
-func IsManaged(node *corev1.Node, cp cloudprovider.CloudProvider) bool {
-	return lo.ContainsBy(cp.GetSupportedNodeClasses(), func(nodeClass status.Object) bool {
+// IsManaged returns true if the Node is labeled with one of the cloudprovider's NodeClasses and with the managed-by
+// value of this instance of Karpenter. Nodes inherit the label from their NodeClaim when they register.
+func IsManaged(ctx context.Context, node *corev1.Node, cp cloudprovider.CloudProvider) bool {
+	return node.Labels[v1.ManagedByLabelKey] == options.FromContext(ctx).ManagedBy && lo.ContainsBy(cp.GetSupportedNodeClasses(), func(nodeClass status.Object) bool {
 		_, ok := node.Labels[v1.NodeClassLabelKey(object.GVK(nodeClass).GroupKind())]
 		return ok
 	})
 }
 
 // IsManagedPredicateFuncs is used to filter controller-runtime NodeClaim watches to NodeClaims managed by the given cloudprovider.
-func IsManagedPredicateFuncs(cp cloudprovider.CloudProvider) predicate.Funcs {
+func IsManagedPredicateFuncs(ctx context.Context, cp cloudprovider.CloudProvider) predicate.Funcs {
 	return predicate.NewPredicateFuncs(func(o client.Object) bool {
-		return IsManaged(o.(*corev1.Node), cp)
+		return IsManaged(ctx, o.(*corev1.Node), cp)
 	})
 }
 
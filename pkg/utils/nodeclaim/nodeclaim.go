@@ -20,6 +20,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"hash/fnv"
+	"time"
 
 	"github.com/awslabs/operatorpkg/object"
 	"github.com/awslabs/operatorpkg/status"
@@ -34,18 +36,73 @@ import (
 
 	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
 	"sigs.k8s.io/karpenter/pkg/cloudprovider"
+	"sigs.k8s.io/karpenter/pkg/operator/options"
 )
 
-func IsManaged(nodeClaim *v1.NodeClaim, cp cloudprovider.CloudProvider) bool {
-	return lo.ContainsBy(cp.GetSupportedNodeClasses(), func(nodeClass status.Object) bool {
+// IsManaged returns true if the NodeClaim uses one of the cloudprovider's NodeClasses and is labeled with the managed-by
+// value of this instance of Karpenter. NodeClaims inherit the label from their NodePool when they're launched, so their
+// owner is resolved without looking up the NodePool on every event.
+func IsManaged(ctx context.Context, nodeClaim *v1.NodeClaim, cp cloudprovider.CloudProvider) bool {
+	return nodeClaim.Labels[v1.ManagedByLabelKey] == options.FromContext(ctx).ManagedBy && lo.ContainsBy(cp.GetSupportedNodeClasses(), func(nodeClass status.Object) bool {
 		return object.GVK(nodeClass).GroupKind() == nodeClaim.Spec.NodeClassRef.GroupKind()
 	})
 }
 
+// IsStandalone returns true if the NodeClaim was created directly rather than by a NodePool. Standalone NodeClaims go
+// through the same lifecycle as the NodeClaims of a NodePool, but they have no NodePool to drift from or to replace
+// them, so they're never drifted or consolidated. They have no owner to be garbage collected with either, so they're
+// only removed when they're deleted, expire, fail to register, or their instance is gone. Without a NodePool to default
+// their requirements, they're launched with the default requirements of NodePools.
+func IsStandalone(nodeClaim *v1.NodeClaim) bool {
+	_, ok := nodeClaim.Labels[v1.NodePoolLabelKey]
+	return !ok
+}
+
+// defaultRequirements are applied to the NodePools and standalone NodeClaims that don't constrain their keys through
+// either their requirements or their labels
+var defaultRequirements = []v1.NodeSelectorRequirementWithMinValues{
+	{NodeSelectorRequirement: corev1.NodeSelectorRequirement{Key: corev1.LabelArchStable, Operator: corev1.NodeSelectorOpIn, Values: []string{v1.ArchitectureAmd64}}},
+	{NodeSelectorRequirement: corev1.NodeSelectorRequirement{Key: corev1.LabelOSStable, Operator: corev1.NodeSelectorOpIn, Values: []string{string(corev1.Linux)}}},
+	{NodeSelectorRequirement: corev1.NodeSelectorRequirement{Key: v1.CapacityTypeLabelKey, Operator: corev1.NodeSelectorOpIn, Values: []string{v1.CapacityTypeOnDemand}}},
+}
+
+// WithDefaultRequirements appends the default requirements whose keys aren't constrained by the requirements or the
+// labels
+func WithDefaultRequirements(requirements []v1.NodeSelectorRequirementWithMinValues, labels map[string]string) []v1.NodeSelectorRequirementWithMinValues {
+	for _, requirement := range defaultRequirements {
+		if _, ok := labels[requirement.Key]; ok {
+			continue
+		}
+		if lo.ContainsBy(requirements, func(r v1.NodeSelectorRequirementWithMinValues) bool {
+			return lo.CoalesceOrEmpty(v1.NormalizedLabels[r.Key], r.Key) == requirement.Key
+		}) {
+			continue
+		}
+		requirements = append(requirements, requirement)
+	}
+	return requirements
+}
+
+// ExpireAfter returns how long after its creation the NodeClaim expires, or nil if it never expires. NodeClaims with an
+// expireAfterJitter expire up to that much later, by an amount that is derived from their UID so that it's the same
+// on every reconcile.
+func ExpireAfter(nodeClaim *v1.NodeClaim) *time.Duration {
+	if nodeClaim.Spec.ExpireAfter.Duration == nil {
+		return nil
+	}
+	expireAfter := *nodeClaim.Spec.ExpireAfter.Duration
+	if jitter := lo.FromPtr(nodeClaim.Spec.ExpireAfterJitter).Duration; jitter > 0 {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(nodeClaim.UID))
+		expireAfter += time.Duration(h.Sum64() % uint64(jitter))
+	}
+	return &expireAfter
+}
+
 // IsManagedPredicateFuncs is used to filter controller-runtime NodeClaim watches to NodeClaims managed by the given cloudprovider.
-func IsManagedPredicateFuncs(cp cloudprovider.CloudProvider) predicate.Funcs {
+func IsManagedPredicateFuncs(ctx context.Context, cp cloudprovider.CloudProvider) predicate.Funcs {
 	return predicate.NewPredicateFuncs(func(o client.Object) bool {
-		return IsManaged(o.(*v1.NodeClaim), cp)
+		return IsManaged(ctx, o.(*v1.NodeClaim), cp)
 	})
 }
 
@@ -65,13 +122,13 @@ func ForNodeClass(nodeClass status.Object) client.ListOption {
 	}
 }
 
-func ListManaged(ctx context.Context, c client.Client, cloudProvider cloudprovider.CloudProvider, opts ...client.ListOption) ([]*v1.NodeClaim, error) {
+func ListManaged(ctx context.Context, c client.Reader, cloudProvider cloudprovider.CloudProvider, opts ...client.ListOption) ([]*v1.NodeClaim, error) {
 	nodeClaimList := &v1.NodeClaimList{}
 	if err := c.List(ctx, nodeClaimList, opts...); err != nil {
 		return nil, err
 	}
 	return lo.FilterMap(nodeClaimList.Items, func(nc v1.NodeClaim, _ int) (*v1.NodeClaim, bool) {
-		return &nc, IsManaged(&nc, cloudProvider)
+		return &nc, IsManaged(ctx, &nc, cloudProvider)
 	}), nil
 }
 
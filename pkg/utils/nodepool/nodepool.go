@@ -31,18 +31,21 @@ import (
 
 	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
 	"sigs.k8s.io/karpenter/pkg/cloudprovider"
+	"sigs.k8s.io/karpenter/pkg/operator/options"
 )
 
-func IsManaged(nodePool *v1.NodePool, cp cloudprovider.CloudProvider) bool {
-	return lo.ContainsBy(cp.GetSupportedNodeClasses(), func(nodeClass status.Object) bool {
+// IsManaged returns true if the NodePool uses one of the cloudprovider's NodeClasses and is labeled with the managed-by
+// value of this instance of Karpenter
+func IsManaged(ctx context.Context, nodePool *v1.NodePool, cp cloudprovider.CloudProvider) bool {
+	return nodePool.Labels[v1.ManagedByLabelKey] == options.FromContext(ctx).ManagedBy && lo.ContainsBy(cp.GetSupportedNodeClasses(), func(nodeClass status.Object) bool {
 		return object.GVK(nodeClass).GroupKind() == nodePool.Spec.Template.Spec.NodeClassRef.GroupKind()
 	})
 }
 
 // IsManagedPredicateFuncs is used to filter controller-runtime NodeClaim watches to NodeClaims managed by the given cloudprovider.
-func IsManagedPredicateFuncs(cp cloudprovider.CloudProvider) predicate.Funcs {
+func IsManagedPredicateFuncs(ctx context.Context, cp cloudprovider.CloudProvider) predicate.Funcs {
 	return predicate.NewPredicateFuncs(func(o client.Object) bool {
-		return IsManaged(o.(*v1.NodePool), cp)
+		return IsManaged(ctx, o.(*v1.NodePool), cp)
 	})
 }
 
@@ -60,7 +63,7 @@ func ListManaged(ctx context.Context, c client.Client, cloudProvider cloudprovid
 		return nil, err
 	}
 	return lo.FilterMap(nodePoolList.Items, func(np v1.NodePool, _ int) (*v1.NodePool, bool) {
-		return &np, IsManaged(&np, cloudProvider)
+		return &np, IsManaged(ctx, &np, cloudProvider)
 	}), nil
 }
 
@@ -104,10 +107,17 @@ func NodeClassEventHandler(c client.Client) handler.EventHandler {
 
 // OrderByWeight orders the NodePools in the provided slice by their priority weight in-place. This priority evaluates
 // the following things in precedence order:
-//  1. NodePools that have a larger weight are ordered first
-//  2. If two NodePools have the same weight, then the NodePool with the name later in the alphabet will come first
+//  1. NodePools that aren't Degraded are ordered before those that are
+//  2. NodePools that have a larger weight are ordered first
+//  3. If two NodePools have the same weight, then the NodePool with the name later in the alphabet will come first
 func OrderByWeight(nps []*v1.NodePool) {
 	sort.Slice(nps, func(a, b int) bool {
+		// Degraded NodePools are deprioritized, rather than excluded, so pods that only they can satisfy still schedule
+		degradedA := nps[a].StatusConditions().IsTrue(v1.ConditionTypeDegraded)
+		degradedB := nps[b].StatusConditions().IsTrue(v1.ConditionTypeDegraded)
+		if degradedA != degradedB {
+			return degradedB
+		}
 		weightA := lo.FromPtr(nps[a].Spec.Weight)
 		weightB := lo.FromPtr(nps[b].Spec.Weight)
 		if weightA == weightB {
@@ -24,9 +24,12 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/samber/lo"
 	"golang.org/x/exp/rand"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"sigs.k8s.io/karpenter/pkg/apis"
 	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
+	"sigs.k8s.io/karpenter/pkg/cloudprovider/fake"
+	"sigs.k8s.io/karpenter/pkg/operator/options"
 	"sigs.k8s.io/karpenter/pkg/test"
 	. "sigs.k8s.io/karpenter/pkg/test/expectations"
 	"sigs.k8s.io/karpenter/pkg/test/v1alpha1"
@@ -58,6 +61,29 @@ var _ = AfterEach(func() {
 })
 
 var _ = Describe("NodePoolUtils", func() {
+	Context("IsManaged", func() {
+		var cloudProvider *fake.CloudProvider
+		BeforeEach(func() {
+			cloudProvider = fake.NewCloudProvider()
+		})
+		It("should only manage NodePools without the managed-by label when managed-by isn't set", func() {
+			ctx := options.ToContext(ctx, test.Options())
+			Expect(nodepoolutils.IsManaged(ctx, test.NodePool(), cloudProvider)).To(BeTrue())
+			Expect(nodepoolutils.IsManaged(ctx, test.NodePool(v1.NodePool{ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{v1.ManagedByLabelKey: "shard-a"},
+			}}), cloudProvider)).To(BeFalse())
+		})
+		It("should only manage NodePools with a matching managed-by label when managed-by is set", func() {
+			ctx := options.ToContext(ctx, test.Options(test.OptionsFields{ManagedBy: lo.ToPtr("shard-a")}))
+			Expect(nodepoolutils.IsManaged(ctx, test.NodePool(), cloudProvider)).To(BeFalse())
+			Expect(nodepoolutils.IsManaged(ctx, test.NodePool(v1.NodePool{ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{v1.ManagedByLabelKey: "shard-a"},
+			}}), cloudProvider)).To(BeTrue())
+			Expect(nodepoolutils.IsManaged(ctx, test.NodePool(v1.NodePool{ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{v1.ManagedByLabelKey: "shard-b"},
+			}}), cloudProvider)).To(BeFalse())
+		})
+	})
 	Context("OrderByWeight", func() {
 		It("should order the NodePools by weight", func() {
 			// Generate 10 NodePools that have random weights, some might have the same weights
@@ -93,5 +119,13 @@ var _ = Describe("NodePoolUtils", func() {
 				lastName = np.Name
 			}
 		})
+		It("should order Degraded NodePools after the NodePools that aren't Degraded", func() {
+			degraded := test.NodePool(v1.NodePool{Spec: v1.NodePoolSpec{Weight: lo.ToPtr[int32](100)}})
+			degraded.StatusConditions().SetTrueWithReason(v1.ConditionTypeDegraded, v1.LaunchFailureReasonQuotaExceeded, "")
+			healthy := test.NodePool(v1.NodePool{Spec: v1.NodePoolSpec{Weight: lo.ToPtr[int32](10)}})
+			nps := []*v1.NodePool{degraded, healthy}
+			nodepoolutils.OrderByWeight(nps)
+			Expect(nps).To(Equal([]*v1.NodePool{healthy, degraded}))
+		})
 	})
 })
@@ -88,14 +88,27 @@ func IsDrainable(pod *corev1.Pod, clk clock.Clock) bool {
 // - Isn't currently preempting other pods on the cluster and about to schedule
 // - Isn't owned by a DaemonSet
 // - Isn't a mirror pod (https://kubernetes.io/docs/tasks/configure-pod-container/static-pod/)
+// - Isn't waiting on scheduling gates to be removed
 func IsProvisionable(pod *corev1.Pod) bool {
 	return FailedToSchedule(pod) &&
+		!IsSchedulingGated(pod) &&
 		!IsScheduled(pod) &&
 		!IsPreempting(pod) &&
 		!IsOwnedByDaemonSet(pod) &&
 		!IsOwnedByNode(pod)
 }
 
+// IsManagedBy returns true if the instance of Karpenter with the given managed-by value provisions capacity for the pod.
+// Pods that require a karpenter.sh/managed-by node label belong to the instances whose value it allows, while pods
+// that don't belong to the instance without a managed-by value, so that no two instances launch capacity for a pod.
+func IsManagedBy(pod *corev1.Pod, managedBy string) bool {
+	requirements := scheduling.NewStrictPodRequirements(pod)
+	if !requirements.Has(v1.ManagedByLabelKey) {
+		return managedBy == ""
+	}
+	return requirements.Get(v1.ManagedByLabelKey).Has(managedBy)
+}
+
 // IsDisruptable checks if a pod can be disrupted based on validating the `karpenter.sh/do-not-disrupt` annotation on the pod.
 // It checks whether the following is true for the pod:
 // - Has the `karpenter.sh/do-not-disrupt` annotation
@@ -119,6 +132,12 @@ func FailedToSchedule(pod *corev1.Pod) bool {
 	return false
 }
 
+// IsSchedulingGated returns true if the pod has scheduling gates. The kube-scheduler won't consider the pod until all of
+// its gates are removed (https://kubernetes.io/docs/concepts/scheduling-eviction/pod-scheduling-readiness/)
+func IsSchedulingGated(pod *corev1.Pod) bool {
+	return len(pod.Spec.SchedulingGates) != 0
+}
+
 func IsScheduled(pod *corev1.Pod) bool {
 	return pod.Spec.NodeName != ""
 }
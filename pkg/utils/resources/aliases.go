@@ -0,0 +1,98 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Alias describes an extended resource that is a fraction of another resource, e.g. a MIG partition of a GPU
+type Alias struct {
+	// Target is the resource that the aliased resource is a fraction of
+	Target v1.ResourceName
+	// Count is how many of the aliased resource make up one of the target resource
+	Count int64
+}
+
+// Aliases maps aliased resources to the resources that they're a fraction of
+type Aliases map[v1.ResourceName]Alias
+
+// ParseAliases parses a comma separated list of aliases in the form <resource>=<target>/<count>, e.g.
+// "nvidia.com/mig-1g.5gb=nvidia.com/gpu/7" treats each nvidia.com/mig-1g.5gb as a seventh of a nvidia.com/gpu
+func ParseAliases(str string) (Aliases, error) {
+	aliases := Aliases{}
+	for _, entry := range strings.Split(str, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(entry, "=")
+		i := strings.LastIndex(value, "/")
+		if !ok || name == "" || i <= 0 {
+			return nil, fmt.Errorf("invalid resource alias %q, expected <resource>=<target>/<count>", entry)
+		}
+		count, err := strconv.ParseInt(value[i+1:], 10, 64)
+		if err != nil || count <= 0 {
+			return nil, fmt.Errorf("invalid resource alias %q, count must be a positive integer", entry)
+		}
+		target := v1.ResourceName(value[:i])
+		if target == v1.ResourceName(name) {
+			return nil, fmt.Errorf("invalid resource alias %q, resource can't alias itself", entry)
+		}
+		aliases[v1.ResourceName(name)] = Alias{Target: target, Count: count}
+	}
+	for name, alias := range aliases {
+		if _, ok := aliases[alias.Target]; ok {
+			return nil, fmt.Errorf("invalid resource alias %q, target %q is itself aliased", name, alias.Target)
+		}
+	}
+	return aliases, nil
+}
+
+// Apply returns the resources with the aliased resources converted to fractions of their target resources and summed
+// with any of the target resources that are already in the list. The list is returned as is if it doesn't have any
+// aliased resources, so it mustn't be modified by the caller.
+func (a Aliases) Apply(list v1.ResourceList) v1.ResourceList {
+	if !a.appliesTo(list) {
+		return list
+	}
+	result := make(v1.ResourceList, len(list))
+	for name, quantity := range list {
+		alias, ok := a[name]
+		if !ok {
+			MergeInto(result, v1.ResourceList{name: quantity})
+			continue
+		}
+		// The fraction is rounded down so that Count of the aliased resource always fit within one of the target resource
+		MergeInto(result, v1.ResourceList{alias.Target: *resource.NewScaledQuantity(quantity.ScaledValue(resource.Nano)/alias.Count, resource.Nano)})
+	}
+	return result
+}
+
+func (a Aliases) appliesTo(list v1.ResourceList) bool {
+	for name := range list {
+		if _, ok := a[name]; ok {
+			return true
+		}
+	}
+	return false
+}
@@ -599,4 +599,55 @@ var _ = Describe("Resources", func() {
 			})
 		})
 	})
+	Context("Resource Aliases", func() {
+		It("should parse aliases", func() {
+			aliases, err := resources.ParseAliases("nvidia.com/mig-1g.5gb=nvidia.com/gpu/7, nvidia.com/mig-3g.20gb=nvidia.com/gpu/2")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(aliases).To(Equal(resources.Aliases{
+				"nvidia.com/mig-1g.5gb":  {Target: "nvidia.com/gpu", Count: 7},
+				"nvidia.com/mig-3g.20gb": {Target: "nvidia.com/gpu", Count: 2},
+			}))
+			aliases, err = resources.ParseAliases("")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(aliases).To(BeEmpty())
+		})
+		DescribeTable("should fail to parse invalid aliases",
+			func(str string) {
+				_, err := resources.ParseAliases(str)
+				Expect(err).To(HaveOccurred())
+			},
+			Entry("missing target", "nvidia.com/mig-1g.5gb"),
+			Entry("missing count", "nvidia.com/mig-1g.5gb=nvidia.com/gpu"),
+			Entry("zero count", "nvidia.com/mig-1g.5gb=nvidia.com/gpu/0"),
+			Entry("self alias", "nvidia.com/gpu=nvidia.com/gpu/2"),
+			Entry("chained alias", "nvidia.com/mig-1g.5gb=nvidia.com/mig-3g.20gb/3,nvidia.com/mig-3g.20gb=nvidia.com/gpu/2"),
+		)
+		It("should convert aliased resources to fractions of their target", func() {
+			aliases := resources.Aliases{
+				"nvidia.com/mig-1g.5gb":  {Target: "nvidia.com/gpu", Count: 7},
+				"nvidia.com/mig-3g.20gb": {Target: "nvidia.com/gpu", Count: 2},
+			}
+			converted := aliases.Apply(v1.ResourceList{
+				v1.ResourceCPU:           resource.MustParse("1"),
+				"nvidia.com/gpu":         resource.MustParse("1"),
+				"nvidia.com/mig-3g.20gb": resource.MustParse("1"),
+			})
+			ExpectResources(converted, v1.ResourceList{
+				v1.ResourceCPU:   resource.MustParse("1"),
+				"nvidia.com/gpu": resource.MustParse("1.5"),
+			})
+			Expect(converted).ToNot(HaveKey(v1.ResourceName("nvidia.com/mig-3g.20gb")))
+		})
+		It("should fit as many aliased resources in their target as the alias count", func() {
+			aliases := resources.Aliases{"nvidia.com/mig-1g.5gb": {Target: "nvidia.com/gpu", Count: 7}}
+			capacity := v1.ResourceList{"nvidia.com/gpu": resource.MustParse("1")}
+			Expect(resources.Fits(aliases.Apply(v1.ResourceList{"nvidia.com/mig-1g.5gb": resource.MustParse("7")}), capacity)).To(BeTrue())
+			Expect(resources.Fits(aliases.Apply(v1.ResourceList{"nvidia.com/mig-1g.5gb": resource.MustParse("8")}), capacity)).To(BeFalse())
+		})
+		It("should return resources without aliased resources as is", func() {
+			requests := v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")}
+			Expect(resources.Aliases{"nvidia.com/mig-1g.5gb": {Target: "nvidia.com/gpu", Count: 7}}.Apply(requests)).To(Equal(requests))
+			Expect(resources.Aliases(nil).Apply(requests)).To(Equal(requests))
+		})
+	})
 })
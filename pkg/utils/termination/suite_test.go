@@ -20,9 +20,12 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/samber/lo"
+	clock "k8s.io/utils/clock/testing"
 
 	"sigs.k8s.io/karpenter/pkg/apis"
 	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
@@ -39,6 +42,7 @@ var (
 	ctx           context.Context
 	env           *test.Environment
 	cloudProvider *fake.CloudProvider
+	fakeClock     *clock.FakeClock
 )
 
 func TestAPIs(t *testing.T) {
@@ -50,6 +54,7 @@ func TestAPIs(t *testing.T) {
 var _ = BeforeSuite(func() {
 	env = test.NewEnvironment(test.WithCRDs(apis.CRDs...), test.WithCRDs(v1alpha1.CRDs...))
 	cloudProvider = fake.NewCloudProvider()
+	fakeClock = clock.NewFakeClock(time.Now())
 })
 
 var _ = AfterSuite(func() {
@@ -70,7 +75,7 @@ var _ = Describe("TerminationUtils", func() {
 	It("should not call cloudProvider Delete if the status condition is already Terminating", func() {
 		nodeClaim.StatusConditions().SetTrue(v1.ConditionTypeInstanceTerminating)
 		ExpectApplied(ctx, env.Client, nodeClaim)
-		instanceTerminated, err := termination.EnsureTerminated(ctx, env.Client, nodeClaim, cloudProvider)
+		instanceTerminated, err := termination.EnsureTerminated(ctx, env.Client, nodeClaim, cloudProvider, fakeClock)
 		Expect(len(cloudProvider.DeleteCalls)).To(BeEquivalentTo(0))
 		Expect(len(cloudProvider.GetCalls)).To(BeEquivalentTo(1))
 		Expect(instanceTerminated).To(BeFalse())
@@ -79,14 +84,14 @@ var _ = Describe("TerminationUtils", func() {
 	It("should call cloudProvider Delete followed by Get and return true when the cloudProvider instance is terminated", func() {
 		ExpectApplied(ctx, env.Client, nodeClaim)
 		// This will call cloudProvider.Delete()
-		instanceTerminated, err := termination.EnsureTerminated(ctx, env.Client, nodeClaim, cloudProvider)
+		instanceTerminated, err := termination.EnsureTerminated(ctx, env.Client, nodeClaim, cloudProvider, fakeClock)
 		Expect(len(cloudProvider.DeleteCalls)).To(BeEquivalentTo(1))
 		Expect(instanceTerminated).To(BeFalse())
 		Expect(err).NotTo(HaveOccurred())
 		Expect(nodeClaim.StatusConditions().Get(v1.ConditionTypeInstanceTerminating).IsTrue()).To(BeTrue())
 
 		//This will call cloudProvider.Get(). Instance is terminated at this point
-		instanceTerminated, err = termination.EnsureTerminated(ctx, env.Client, nodeClaim, cloudProvider)
+		instanceTerminated, err = termination.EnsureTerminated(ctx, env.Client, nodeClaim, cloudProvider, fakeClock)
 		Expect(len(cloudProvider.GetCalls)).To(BeEquivalentTo(1))
 
 		Expect(instanceTerminated).To(BeTrue())
@@ -95,7 +100,7 @@ var _ = Describe("TerminationUtils", func() {
 	It("should call cloudProvider Delete followed by Get and return false when the cloudProvider instance is not terminated", func() {
 		ExpectApplied(ctx, env.Client, nodeClaim)
 		// This will call cloudProvider.Delete()
-		instanceTerminated, err := termination.EnsureTerminated(ctx, env.Client, nodeClaim, cloudProvider)
+		instanceTerminated, err := termination.EnsureTerminated(ctx, env.Client, nodeClaim, cloudProvider, fakeClock)
 		Expect(len(cloudProvider.DeleteCalls)).To(BeEquivalentTo(1))
 		Expect(instanceTerminated).To(BeFalse())
 		Expect(err).NotTo(HaveOccurred())
@@ -105,17 +110,26 @@ var _ = Describe("TerminationUtils", func() {
 		// To model the behavior of having cloudProvider instance not terminated, we add it back here.
 		cloudProvider.CreatedNodeClaims[nodeClaim.Status.ProviderID] = nodeClaim
 		//This will call cloudProvider.Get(). Instance is not terminated at this point
-		instanceTerminated, err = termination.EnsureTerminated(ctx, env.Client, nodeClaim, cloudProvider)
+		instanceTerminated, err = termination.EnsureTerminated(ctx, env.Client, nodeClaim, cloudProvider, fakeClock)
 		Expect(len(cloudProvider.GetCalls)).To(BeEquivalentTo(1))
 
 		Expect(instanceTerminated).To(BeFalse())
 		Expect(err).NotTo(HaveOccurred())
 	})
+	It("should not call cloudProvider Delete and return true if the NodeClaim retains its instance", func() {
+		nodeClaim.Spec.InstanceRetentionPolicy = v1.InstanceRetentionPolicyRetain
+		ExpectApplied(ctx, env.Client, nodeClaim)
+		instanceTerminated, err := termination.EnsureTerminated(ctx, env.Client, nodeClaim, cloudProvider, fakeClock)
+		Expect(len(cloudProvider.DeleteCalls)).To(BeEquivalentTo(0))
+		Expect(instanceTerminated).To(BeTrue())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cloudProvider.CreatedNodeClaims).To(HaveKey(nodeClaim.Status.ProviderID))
+	})
 	It("should call cloudProvider Delete and return true if cloudProvider instance is not found", func() {
 		ExpectApplied(ctx, env.Client, nodeClaim)
 
 		cloudProvider.NextDeleteErr = cloudprovider.NewNodeClaimNotFoundError(fmt.Errorf("no nodeclaim exists"))
-		instanceTerminated, err := termination.EnsureTerminated(ctx, env.Client, nodeClaim, cloudProvider)
+		instanceTerminated, err := termination.EnsureTerminated(ctx, env.Client, nodeClaim, cloudProvider, fakeClock)
 		Expect(len(cloudProvider.GetCalls)).To(BeEquivalentTo(0))
 
 		Expect(instanceTerminated).To(BeTrue())
@@ -133,10 +147,41 @@ var _ = Describe("TerminationUtils", func() {
 		nodeClaim = ExpectExists(ctx, env.Client, nodeClaim)
 		Expect(nodeClaim.StatusConditions().Root().IsTrue())
 		ExpectApplied(ctx, env.Client, nodeClaim)
-		instanceTerminated, err := termination.EnsureTerminated(ctx, env.Client, nodeClaim, cloudProvider)
+		instanceTerminated, err := termination.EnsureTerminated(ctx, env.Client, nodeClaim, cloudProvider, fakeClock)
 		Expect(instanceTerminated).To(BeFalse())
 		Expect(err).NotTo(HaveOccurred())
 		Expect(nodeClaim.StatusConditions().Get(v1.ConditionTypeInstanceTerminating).IsTrue()).To(BeTrue())
 		Expect(nodeClaim.StatusConditions().Root().IsTrue())
 	})
+	It("should not call cloudProvider Delete while the NodeClaim has termination finalizers", func() {
+		nodeClaim.Annotations = lo.Assign(nodeClaim.Annotations, map[string]string{v1.TerminationFinalizersAnnotationKey: "test.sh/hook"})
+		nodeClaim.Finalizers = append(nodeClaim.Finalizers, "test.sh/hook")
+		ExpectApplied(ctx, env.Client, nodeClaim)
+		instanceTerminated, err := termination.EnsureTerminated(ctx, env.Client, nodeClaim, cloudProvider, fakeClock)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(instanceTerminated).To(BeFalse())
+		Expect(cloudProvider.DeleteCalls).To(HaveLen(0))
+	})
+	It("should call cloudProvider Delete despite termination finalizers once the termination timestamp has passed", func() {
+		nodeClaim.Annotations = lo.Assign(nodeClaim.Annotations, map[string]string{
+			v1.TerminationFinalizersAnnotationKey:         "test.sh/hook",
+			v1.NodeClaimTerminationTimestampAnnotationKey: fakeClock.Now().Add(-time.Minute).Format(time.RFC3339),
+		})
+		nodeClaim.Finalizers = append(nodeClaim.Finalizers, "test.sh/hook")
+		ExpectApplied(ctx, env.Client, nodeClaim)
+		_, err := termination.EnsureTerminated(ctx, env.Client, nodeClaim, cloudProvider, fakeClock)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cloudProvider.DeleteCalls).To(HaveLen(1))
+	})
+	It("should call cloudProvider Delete despite termination finalizers if the NodeClaim is force deleted", func() {
+		nodeClaim.Annotations = lo.Assign(nodeClaim.Annotations, map[string]string{
+			v1.TerminationFinalizersAnnotationKey: "test.sh/hook",
+			v1.ForceDeleteAnnotationKey:           "true",
+		})
+		nodeClaim.Finalizers = append(nodeClaim.Finalizers, "test.sh/hook")
+		ExpectApplied(ctx, env.Client, nodeClaim)
+		_, err := termination.EnsureTerminated(ctx, env.Client, nodeClaim, cloudProvider, fakeClock)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cloudProvider.DeleteCalls).To(HaveLen(1))
+	})
 })
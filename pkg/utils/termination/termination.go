@@ -19,8 +19,13 @@ package termination
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/samber/lo"
+	"k8s.io/utils/clock"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	v1 "sigs.k8s.io/karpenter/pkg/apis/v1"
 	"sigs.k8s.io/karpenter/pkg/cloudprovider"
@@ -29,10 +34,19 @@ import (
 // EnsureTerminated is a helper function that takes a v1.NodeClaim and calls cloudProvider.Delete() if status condition
 // on nodeClaim is not terminating. If it is terminating then it will call cloudProvider.Get() to check if the instance
 // is terminated or not. It will return an error and a boolean that indicates if the instance is terminated or not. We simply return
-// conflict or a NotFound error if we encounter it while updating the status on nodeClaim.
-func EnsureTerminated(ctx context.Context, c client.Client, nodeClaim *v1.NodeClaim, cloudProvider cloudprovider.CloudProvider) (terminated bool, err error) {
+// conflict or a NotFound error if we encounter it while updating the status on nodeClaim. The instances of NodeClaims
+// with the Retain instance retention policy are left running and reported as terminated.
+func EnsureTerminated(ctx context.Context, c client.Client, nodeClaim *v1.NodeClaim, cloudProvider cloudprovider.CloudProvider, clk clock.PassiveClock) (terminated bool, err error) {
 	// Check if the status condition on nodeClaim is Terminating
 	if !nodeClaim.StatusConditions().Get(v1.ConditionTypeInstanceTerminating).IsTrue() {
+		// Wait for external controllers to remove the finalizers that the NodeClaim was created with before terminating the instance
+		if waitsForTerminationFinalizers(nodeClaim, clk) {
+			return false, nil
+		}
+		// The instances of NodeClaims that retain them are left running, so they're treated as already terminated
+		if nodeClaim.Spec.InstanceRetentionPolicy == v1.InstanceRetentionPolicyRetain {
+			return true, nil
+		}
 		// If not then call Delete on cloudProvider to trigger termination and always requeue reconciliation
 		if err = cloudProvider.Delete(ctx, nodeClaim); err != nil {
 			if cloudprovider.IsNodeClaimNotFoundError(err) {
@@ -70,6 +84,33 @@ func EnsureTerminated(ctx context.Context, c client.Client, nodeClaim *v1.NodeCl
 	return false, nil
 }
 
+// HasTerminationFinalizers returns true if the NodeClaim still has any of the finalizers that were recorded in its
+// termination finalizers annotation when it was created from its NodePool's template
+func HasTerminationFinalizers(nodeClaim *v1.NodeClaim) bool {
+	value, ok := nodeClaim.Annotations[v1.TerminationFinalizersAnnotationKey]
+	if !ok || value == "" {
+		return false
+	}
+	return lo.SomeBy(strings.Split(value, ","), func(finalizer string) bool {
+		return controllerutil.ContainsFinalizer(nodeClaim, finalizer)
+	})
+}
+
+// waitsForTerminationFinalizers returns true if the termination of the NodeClaim's instance should wait for its
+// termination finalizers to be removed. Force deleted NodeClaims and NodeClaims that are past their termination
+// timestamp don't wait, so that the finalizers can't block termination indefinitely.
+func waitsForTerminationFinalizers(nodeClaim *v1.NodeClaim, clk clock.PassiveClock) bool {
+	if !HasTerminationFinalizers(nodeClaim) || nodeClaim.Annotations[v1.ForceDeleteAnnotationKey] == "true" {
+		return false
+	}
+	if value, ok := nodeClaim.Annotations[v1.NodeClaimTerminationTimestampAnnotationKey]; ok {
+		if terminationTime, err := time.Parse(time.RFC3339, value); err == nil && !clk.Now().Before(terminationTime) {
+			return false
+		}
+	}
+	return true
+}
+
 func updateStatusConditionsForDeleting(nc *v1.NodeClaim) {
 	// perform a no-op for whatever the status condition is currently set to
 	// so that we bump the observed generation to the latest and prevent the nodeclaim
@@ -20,7 +20,10 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/samber/lo"
 	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -43,3 +46,33 @@ func GetPersistentVolumeClaim(ctx context.Context, kubeClient client.Client, pod
 	}
 	return pvc, nil
 }
+
+// HasUnboundDelayedClaims returns true if any of the pod's persistent volume claims are unbound and use a storage class
+// that delays binding until a pod that uses them is scheduled. The kube-scheduler binds these claims as it schedules
+// the pod, so pods with these claims must be left for it to schedule.
+func HasUnboundDelayedClaims(ctx context.Context, kubeClient client.Client, pod *v1.Pod) (bool, error) {
+	for _, volume := range pod.Spec.Volumes {
+		pvc, err := GetPersistentVolumeClaim(ctx, kubeClient, pod, volume)
+		if err != nil {
+			// the claims of ephemeral volumes are created after the pod, so they may not exist yet
+			if errors.IsNotFound(err) {
+				return true, nil
+			}
+			return false, err
+		}
+		if pvc == nil || pvc.Spec.VolumeName != "" || lo.FromPtr(pvc.Spec.StorageClassName) == "" {
+			continue
+		}
+		storageClass := &storagev1.StorageClass{}
+		if err = kubeClient.Get(ctx, types.NamespacedName{Name: *pvc.Spec.StorageClassName}, storageClass); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return false, fmt.Errorf("getting storage class %q, %w", *pvc.Spec.StorageClassName, err)
+		}
+		if lo.FromPtr(storageClass.VolumeBindingMode) == storagev1.VolumeBindingWaitForFirstConsumer {
+			return true, nil
+		}
+	}
+	return false, nil
+}